@@ -0,0 +1,44 @@
+//go:build cgo
+
+// Command osv-scanner-cshared exposes the scanning of lockfiles to C when built
+// as a shared library, so that it can be embedded by other languages without
+// running the osv-scanner binary:
+//
+//	go build -buildmode=c-shared -o libosvscanner.so ./cmd/osv-scanner-cshared
+//
+// This also generates a libosvscanner.h header declaring the functions below.
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import "unsafe"
+
+// OsvScanLockfile checks the packages of a lockfile against the OSV database,
+// given its path, the parser to use (or an empty string to find it from the
+// path) and its content, which does not need to be null terminated.
+//
+// It returns the results as JSON, in the same format as the --json output of
+// osv-scanner, or an object with an "error" if the scan failed. The string
+// must be freed with OsvFreeString.
+//
+//export OsvScanLockfile
+func OsvScanLockfile(path *C.char, parseAs *C.char, content *C.char, length C.int) *C.char {
+	return C.CString(scanJSON(
+		C.GoString(path),
+		C.GoString(parseAs),
+		C.GoBytes(unsafe.Pointer(content), length),
+	))
+}
+
+// OsvFreeString frees a string that was returned by OsvScanLockfile
+//
+//export OsvFreeString
+func OsvFreeString(str *C.char) {
+	C.free(unsafe.Pointer(str))
+}
+
+// main is required to build a shared library, but is never run
+func main() {}
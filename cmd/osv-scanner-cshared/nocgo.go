@@ -0,0 +1,13 @@
+//go:build !cgo
+
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	fmt.Fprintln(os.Stderr, "osv-scanner-cshared must be built with cgo enabled, using -buildmode=c-shared")
+	os.Exit(1)
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/google/osv-scanner/pkg/contentscan"
+)
+
+// scanError is returned in place of the results when a scan fails
+type scanError struct {
+	Error string `json:"error"`
+}
+
+// scanJSON scans the content of a lockfile, returning the results or the
+// error that the scan failed with as JSON
+func scanJSON(path string, parseAs string, content []byte) string {
+	results, err := contentscan.Scan([]contentscan.File{{
+		Path:    path,
+		ParseAs: parseAs,
+		Content: content,
+	}})
+
+	var out []byte
+	if err == nil {
+		out, err = json.Marshal(results)
+	}
+	if err != nil {
+		// marshalling a struct of a string cannot fail
+		out, _ = json.Marshal(scanError{Error: err.Error()})
+	}
+
+	return string(out)
+}
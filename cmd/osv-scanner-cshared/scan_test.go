@@ -0,0 +1,38 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func Test_scanJSON_ParseFailure(t *testing.T) {
+	t.Parallel()
+
+	out := scanJSON("package-lock.json", "", []byte("this is not json"))
+
+	var results models.VulnerabilityResults
+	if err := json.Unmarshal([]byte(out), &results); err != nil {
+		t.Fatalf("expected the results to be JSON, but got %q: %v", out, err)
+	}
+
+	if len(results.ParseFailures) != 1 || results.ParseFailures[0].Path != "package-lock.json" {
+		t.Errorf("expected package-lock.json to fail to parse, but got %s", out)
+	}
+}
+
+func Test_scanJSON_UnknownParser(t *testing.T) {
+	t.Parallel()
+
+	out := scanJSON("deps.txt", "not-a-parser", []byte{})
+
+	var results models.VulnerabilityResults
+	if err := json.Unmarshal([]byte(out), &results); err != nil {
+		t.Fatalf("expected the results to be JSON, but got %q: %v", out, err)
+	}
+
+	if len(results.ParseFailures) != 1 || results.ParseFailures[0].Parser != "not-a-parser" {
+		t.Errorf("expected deps.txt to fail to parse, but got %s", out)
+	}
+}
@@ -0,0 +1,68 @@
+//go:build js && wasm
+
+// Command osv-scanner-wasm exposes the scanning of lockfiles to JavaScript when
+// compiled to WebAssembly, for use by pages where lockfiles are pasted in.
+//
+// It defines a global osvScanLockfile(path, content[, parseAs]) function that
+// returns a promise of the results as JSON, in the same format as the
+// --json output of osv-scanner.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"syscall/js"
+
+	"github.com/google/osv-scanner/pkg/contentscan"
+)
+
+func scanLockfile(args []js.Value) (string, error) {
+	if len(args) < 2 {
+		return "", errors.New("expected the path and content of a lockfile")
+	}
+
+	file := contentscan.File{
+		Path:    args[0].String(),
+		Content: []byte(args[1].String()),
+	}
+	if len(args) > 2 && args[2].Type() == js.TypeString {
+		file.ParseAs = args[2].String()
+	}
+
+	results, err := contentscan.Scan([]contentscan.File{file})
+	if err != nil {
+		return "", err
+	}
+
+	out, err := json.Marshal(results)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+func main() {
+	js.Global().Set("osvScanLockfile", js.FuncOf(func(this js.Value, args []js.Value) any {
+		// requests block until they are fetched, which can only happen once
+		// control has been returned to the event loop
+		return js.Global().Get("Promise").New(js.FuncOf(func(this js.Value, handlers []js.Value) any {
+			resolve, reject := handlers[0], handlers[1]
+
+			go func() {
+				out, err := scanLockfile(args)
+				if err != nil {
+					reject.Invoke(js.Global().Get("Error").New(err.Error()))
+
+					return
+				}
+				resolve.Invoke(out)
+			}()
+
+			return nil
+		}))
+	}))
+
+	// keep running so that the function can still be called
+	select {}
+}
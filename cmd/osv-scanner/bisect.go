@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/githistory"
+	"github.com/google/osv-scanner/pkg/output"
+	"github.com/urfave/cli/v2"
+)
+
+// bisectAction reports the commit that introduced a version of a package to a lockfile
+func bisectAction(context *cli.Context, r *output.WriterReporter) error {
+	lockfileElem := context.String("lockfile")
+	name := context.String("package")
+	if lockfileElem == "" || name == "" {
+		return errors.New("--lockfile and --package are required")
+	}
+
+	parseAs, lockfilePath := "", lockfileElem
+	if before, after, found := strings.Cut(lockfileElem, ":"); found {
+		parseAs, lockfilePath = before, after
+	}
+
+	introduction, err := githistory.FindIntroduction(lockfilePath, parseAs, name, context.String("version"))
+	if err != nil {
+		//nolint:wrapcheck
+		return err
+	}
+
+	if context.String("format") == "json" {
+		encoder := json.NewEncoder(context.App.Writer)
+		encoder.SetIndent("", "  ")
+
+		//nolint:wrapcheck
+		return encoder.Encode(introduction)
+	}
+
+	commit := introduction.Commit
+	message, _, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+
+	r.PrintText(fmt.Sprintf(
+		"%s@%s was introduced to %s in %s by %s <%s> on %s: %s\n",
+		introduction.Package,
+		introduction.Version,
+		introduction.Lockfile,
+		commit.Hash,
+		commit.Author,
+		commit.Email,
+		commit.Time.Format("2006-01-02"),
+		message,
+	))
+
+	return nil
+}
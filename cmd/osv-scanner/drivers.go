@@ -0,0 +1,8 @@
+package main
+
+// The database/sql drivers for the SQL scan history stores, which are both
+// pure Go so that the binary does not need cgo
+import (
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
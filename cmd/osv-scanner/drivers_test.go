@@ -0,0 +1,62 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/store"
+)
+
+func TestSQLiteStore(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "history.db")
+
+	for _, location := range []string{path, "sqlite://" + path} {
+		s, err := store.Open(location)
+		if err != nil {
+			t.Fatalf("could not open %s: %v", location, err)
+		}
+		if sqlStore, ok := s.(*store.SQLStore); !ok || sqlStore.Dialect != store.DialectSQLite {
+			t.Fatalf("expected a SQLite store for %s, got %#v", location, s)
+		}
+	}
+
+	scan := store.Scan{
+		Repository: "my-repo",
+		Time:       time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		Findings:   []store.Finding{{Fingerprint: "a", Package: "minimist", IDs: []string{"GHSA-vh95-rmgr-6w4m"}}},
+	}
+
+	s, err := store.Open(path)
+	if err != nil {
+		t.Fatalf("could not open store: %v", err)
+	}
+	if err := s.Save(scan); err != nil {
+		t.Fatalf("could not save scan: %v", err)
+	}
+
+	// reopen the store to read the scan back from the database file
+	s, err = store.Open(path)
+	if err != nil {
+		t.Fatalf("could not reopen store: %v", err)
+	}
+
+	scans, err := s.Scans("my-repo")
+	if err != nil {
+		t.Fatalf("could not read scans: %v", err)
+	}
+	if diff := cmp.Diff([]store.Scan{scan}, scans); diff != "" {
+		t.Errorf("Scans() mismatch (-want +got):\n%s", diff)
+	}
+
+	repositories, err := s.Repositories()
+	if err != nil {
+		t.Fatalf("could not read repositories: %v", err)
+	}
+	if diff := cmp.Diff([]string{"my-repo"}, repositories); diff != "" {
+		t.Errorf("Repositories() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/output"
+	"github.com/google/osv-scanner/pkg/store"
+	"github.com/urfave/cli/v2"
+)
+
+// storeRepository returns the name to record scans under, defaulting to the
+// name of the current directory
+func storeRepository(context *cli.Context) string {
+	if repository := context.String("store-repository"); repository != "" {
+		return repository
+	}
+
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return "default"
+	}
+
+	return filepath.Base(workingDir)
+}
+
+// recordScan saves the results of the scan to the store given with --store, if any
+func recordScan(context *cli.Context, r *output.WriterReporter, results models.VulnerabilityResults) error {
+	path := context.String("store")
+	if path == "" {
+		return nil
+	}
+
+	scan := store.Scan{
+		Repository: storeRepository(context),
+		Time:       time.Now().UTC(),
+		Findings:   store.FindingsFromResults(results),
+	}
+
+	s, err := store.Open(path)
+	if err != nil {
+		//nolint:wrapcheck
+		return err
+	}
+
+	if err := s.Save(scan); err != nil {
+		return fmt.Errorf("failed to record scan: %w", err)
+	}
+
+	r.PrintText(fmt.Sprintf("Recorded scan of %s in %s\n", scan.Repository, path))
+
+	return nil
+}
+
+// trendsAction reports how the findings of a repository have changed over the
+// scans recorded in the store
+func trendsAction(context *cli.Context, r *output.WriterReporter) error {
+	path := context.String("store")
+	if path == "" {
+		return errors.New("--store is required")
+	}
+
+	repository := storeRepository(context)
+
+	s, err := store.Open(path)
+	if err != nil {
+		//nolint:wrapcheck
+		return err
+	}
+
+	scans, err := s.Scans(repository)
+	if err != nil {
+		//nolint:wrapcheck
+		return err
+	}
+
+	trends := store.Trends(scans)
+
+	if context.String("format") == "json" {
+		encoder := json.NewEncoder(context.App.Writer)
+		encoder.SetIndent("", "  ")
+
+		//nolint:wrapcheck
+		return encoder.Encode(trends)
+	}
+
+	if len(trends) == 0 {
+		r.PrintText(fmt.Sprintf("No scans of %s have been recorded\n", repository))
+
+		return nil
+	}
+
+	r.PrintText(fmt.Sprintf("Trends for %s:\n", repository))
+	output.PrintTrendsTable(trends, context.App.Writer)
+
+	return nil
+}
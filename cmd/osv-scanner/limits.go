@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// byteSizeUnits are the suffixes accepted by parseByteSize, longest first
+var byteSizeUnits = []struct {
+	suffix     string
+	multiplier int64
+}{
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"B", 1},
+}
+
+// parseByteSize parses a size such as "512KB", "100MB" or "2GB" into a number
+// of bytes, with a plain number being taken as bytes
+func parseByteSize(input string) (int64, error) {
+	size := strings.ToUpper(strings.TrimSpace(input))
+	multiplier := int64(1)
+
+	for _, unit := range byteSizeUnits {
+		if strings.HasSuffix(size, unit.suffix) {
+			size = strings.TrimSpace(strings.TrimSuffix(size, unit.suffix))
+			multiplier = unit.multiplier
+
+			break
+		}
+	}
+
+	value, err := strconv.ParseInt(size, 10, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("%q is not a valid size", input)
+	}
+
+	return value * multiplier, nil
+}
+
+// byteSizeFlag returns the value of the given size flag in bytes, or 0 if it is not set
+func byteSizeFlag(context *cli.Context, name string) (int64, error) {
+	value := context.String(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	size, err := parseByteSize(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid --%s: %w", name, err)
+	}
+
+	return size, nil
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/google/osv-scanner/pkg/lsp"
+	"github.com/google/osv-scanner/pkg/output"
+	"github.com/urfave/cli/v2"
+)
+
+// lspAction runs a language server over stdin and stdout, until the client
+// asks it to exit
+func lspAction(context *cli.Context, r *output.WriterReporter) error {
+	server := &lsp.Server{
+		Version:  context.App.Version,
+		Reporter: r,
+	}
+
+	//nolint:wrapcheck
+	return server.Serve(context.App.Reader, context.App.Writer)
+}
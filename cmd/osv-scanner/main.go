@@ -5,11 +5,15 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/google/osv-scanner/pkg/osv"
 	"github.com/google/osv-scanner/pkg/osvscanner"
 	"github.com/google/osv-scanner/pkg/output"
 
 	"github.com/urfave/cli/v2"
+	"golang.org/x/exp/slices"
 )
 
 var (
@@ -19,7 +23,7 @@ var (
 )
 
 func run(args []string, stdout, stderr io.Writer) int {
-	var r *output.Reporter
+	var r *output.WriterReporter
 
 	cli.VersionPrinter = func(ctx *cli.Context) {
 		r = output.NewReporter(ctx.App.Writer, ctx.App.ErrWriter, "")
@@ -36,29 +40,227 @@ func run(args []string, stdout, stderr io.Writer) int {
 		Flags: []cli.Flag{
 			&cli.StringSliceFlag{
 				Name:      "docker",
+				EnvVars:   []string{"OSV_SCANNER_DOCKER"},
 				Aliases:   []string{"D"},
 				Usage:     "scan docker image with this name",
 				TakesFile: false,
 			},
 			&cli.StringSliceFlag{
 				Name:      "lockfile",
+				EnvVars:   []string{"OSV_SCANNER_LOCKFILE"},
 				Aliases:   []string{"L"},
 				Usage:     "scan package lockfile on this path",
 				TakesFile: true,
 			},
 			&cli.StringSliceFlag{
 				Name:      "sbom",
+				EnvVars:   []string{"OSV_SCANNER_SBOM"},
 				Aliases:   []string{"S"},
 				Usage:     "scan sbom file on this path",
 				TakesFile: true,
 			},
+			&cli.BoolFlag{
+				Name:    "audit-host",
+				EnvVars: []string{"OSV_SCANNER_AUDIT_HOST"},
+				Usage:   "audit the operating system, kernel and OS packages of the host being run on",
+			},
+			&cli.StringFlag{
+				Name:      "host-root",
+				EnvVars:   []string{"OSV_SCANNER_HOST_ROOT"},
+				Usage:     "audit the filesystem mounted at this path instead of the running host when using --audit-host",
+				Value:     "/",
+				TakesFile: true,
+			},
+			&cli.StringSliceFlag{
+				Name:      "artifact",
+				EnvVars:   []string{"OSV_SCANNER_ARTIFACT"},
+				Usage:     "scan the build artifact (such as a jar, wheel or binary) on this path, identifying the package it is a release of by its hash",
+				TakesFile: true,
+			},
 			&cli.StringFlag{
 				Name:      "config",
+				EnvVars:   []string{"OSV_SCANNER_CONFIG"},
 				Usage:     "set/override config file",
 				TakesFile: true,
 			},
+			&cli.StringFlag{
+				Name:      "targets",
+				EnvVars:   []string{"OSV_SCANNER_TARGETS"},
+				Usage:     "scan each of the targets listed in the given YAML file",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:    "github-org",
+				EnvVars: []string{"OSV_SCANNER_GITHUB_ORG"},
+				Usage:   "clone and scan every repository in the given GitHub `organization`, authenticating with GITHUB_TOKEN",
+			},
+			&cli.StringFlag{
+				Name:    "max-file-size",
+				EnvVars: []string{"OSV_SCANNER_MAX_FILE_SIZE"},
+				Usage:   "skip lockfiles and SBOMs larger than this `size`, e.g. 100MB",
+			},
+			&cli.IntFlag{
+				Name:    "max-packages-per-source",
+				EnvVars: []string{"OSV_SCANNER_MAX_PACKAGES_PER_SOURCE"},
+				Usage:   "skip lockfiles and SBOMs with more than this many packages",
+			},
+			&cli.StringFlag{
+				Name:    "memory-budget",
+				EnvVars: []string{"OSV_SCANNER_MEMORY_BUDGET"},
+				Usage:   "the `size` of memory to stay within, skipping lockfiles and SBOMs that go beyond it, e.g. 2GB",
+			},
+			&cli.IntFlag{
+				Name:    "request-workers",
+				EnvVars: []string{"OSV_SCANNER_REQUEST_WORKERS"},
+				Usage:   "how many requests to send to the OSV API at the same time when querying for vulnerabilities",
+				Value:   osv.DefaultRequestWorkers,
+			},
+			&cli.IntFlag{
+				Name:        "parse-workers",
+				EnvVars:     []string{"OSV_SCANNER_PARSE_WORKERS"},
+				Usage:       "how many files to parse at the same time when scanning directories",
+				DefaultText: "the number of CPUs",
+			},
+			&cli.BoolFlag{
+				Name:    "quiet",
+				EnvVars: []string{"OSV_SCANNER_QUIET"},
+				Aliases: []string{"q"},
+				Usage:   "only output the results and any errors, without progress or informational messages",
+			},
+			&cli.StringFlag{
+				Name:    "locale",
+				EnvVars: []string{"OSV_SCANNER_LOCALE"},
+				Usage:   "the language to write table headers and summaries in, e.g. de, or \"auto\" to use the LANG environment variable",
+				Value:   string(output.DefaultLocale),
+			},
+			&cli.StringFlag{
+				Name:    "color",
+				EnvVars: []string{"OSV_SCANNER_COLOR"},
+				Usage:   "when to color table output: auto, always or never",
+				Value:   string(output.ColorAuto),
+			},
+			&cli.StringFlag{
+				Name:    "theme",
+				EnvVars: []string{"OSV_SCANNER_THEME"},
+				Usage:   "the colors to use in table output: default, light or high-contrast",
+				Value:   output.DefaultThemeName,
+			},
+			&cli.BoolFlag{
+				Name:    "reproducible",
+				EnvVars: []string{"OSV_SCANNER_REPRODUCIBLE"},
+				Usage:   "sort results and make paths relative to the working directory, so scans of identical inputs produce identical reports",
+			},
+			&cli.BoolFlag{
+				Name:    "profile",
+				EnvVars: []string{"OSV_SCANNER_PROFILE"},
+				Usage:   "report how long was spent walking, parsing, querying, hydrating and formatting",
+			},
+			&cli.StringFlag{
+				Name:      "cpu-profile",
+				EnvVars:   []string{"OSV_SCANNER_CPU_PROFILE"},
+				Usage:     "write a pprof CPU profile of the scan to the given file",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:      "mem-profile",
+				EnvVars:   []string{"OSV_SCANNER_MEM_PROFILE"},
+				Usage:     "write a pprof memory profile of the scan to the given file",
+				TakesFile: true,
+			},
+			&cli.BoolFlag{
+				Name:    "blame",
+				EnvVars: []string{"OSV_SCANNER_BLAME"},
+				Usage:   "annotate vulnerable packages with the git commit that last changed the lockfile line pinning them",
+			},
+			&cli.BoolFlag{
+				Name:    "history",
+				EnvVars: []string{"OSV_SCANNER_HISTORY"},
+				Usage:   "scan lockfiles at each tag of their git history to report when each vulnerability first affected them",
+			},
+			&cli.IntFlag{
+				Name:    "history-every",
+				EnvVars: []string{"OSV_SCANNER_HISTORY_EVERY"},
+				Usage:   "scan the git history of lockfiles at every `N`th commit rather than at each tag, implying --history",
+			},
+			&cli.BoolFlag{
+				Name:    "verify-registry",
+				EnvVars: []string{"OSV_SCANNER_VERIFY_REGISTRY"},
+				Usage:   "check that each package in a lockfile exists on its public registry, with the hash that the lockfile records",
+			},
+			&cli.BoolFlag{
+				Name:    "check-outdated",
+				EnvVars: []string{"OSV_SCANNER_CHECK_OUTDATED"},
+				Usage:   "report packages that are behind the latest version on their public registry",
+			},
+			&cli.BoolFlag{
+				Name:    "check-deprecated",
+				EnvVars: []string{"OSV_SCANNER_CHECK_DEPRECATED"},
+				Usage:   "report packages that have been deprecated, yanked or are unmaintained according to their public registry",
+			},
+			&cli.BoolFlag{
+				Name:    "scorecard",
+				EnvVars: []string{"OSV_SCANNER_SCORECARD"},
+				Usage:   "include the OpenSSF Scorecards of direct dependencies from deps.dev in the results",
+			},
+			&cli.BoolFlag{
+				Name:    "analyze-upgrades",
+				EnvVars: []string{"OSV_SCANNER_ANALYZE_UPGRADES"},
+				Usage:   "report what upgrading vulnerable packages to their fixed versions involves, including the changes to their dependencies from deps.dev",
+			},
+			&cli.BoolFlag{
+				Name:    "check-licenses",
+				EnvVars: []string{"OSV_SCANNER_CHECK_LICENSES"},
+				Usage:   "look up the licenses of packages with deps.dev, and fail the scan if any are not allowed by the license policy of their config",
+			},
+			&cli.BoolFlag{
+				Name:    "call-analysis",
+				EnvVars: []string{"OSV_SCANNER_CALL_ANALYSIS"},
+				Usage:   "report whether the code of scanned Go modules calls the vulnerable symbols of their vulnerabilities, or only imports the affected packages",
+			},
+			&cli.BoolFlag{
+				Name:    "find-symbol-usages",
+				EnvVars: []string{"OSV_SCANNER_FIND_SYMBOL_USAGES"},
+				Usage:   "search the code around scanned lockfiles for calls of the functions that their vulnerabilities affect, and report where they are used",
+			},
+			&cli.BoolFlag{
+				Name:    "resolve-manifests",
+				EnvVars: []string{"OSV_SCANNER_RESOLVE_MANIFESTS"},
+				Usage:   "scan package.json and Cargo.toml files without lockfiles by resolving their dependencies with deps.dev",
+			},
+			&cli.BoolFlag{
+				Name:    "detect-typosquats",
+				EnvVars: []string{"OSV_SCANNER_DETECT_TYPOSQUATS"},
+				Usage:   "report packages in lockfiles whose names are likely typosquats of popular packages",
+			},
+			&cli.StringFlag{
+				Name:    "platform",
+				EnvVars: []string{"OSV_SCANNER_PLATFORM"},
+				Usage:   "only scan the builds of packages for this platform (such as x86_64-linux) where lockfiles record builds for several platforms",
+			},
+			&cli.StringSliceFlag{
+				Name:    "exclude-group",
+				EnvVars: []string{"OSV_SCANNER_EXCLUDE_GROUP"},
+				Usage:   "skip packages that are only depended on in this group of the manifest (such as development or test)",
+			},
+			&cli.StringFlag{
+				Name:    "php-version",
+				EnvVars: []string{"OSV_SCANNER_PHP_VERSION"},
+				Usage:   "report Composer packages that require a different version of PHP than this one",
+			},
+			&cli.StringSliceFlag{
+				Name:    "php-extension",
+				EnvVars: []string{"OSV_SCANNER_PHP_EXTENSION"},
+				Usage:   "report Composer packages that require PHP extensions other than the ones given with this flag",
+			},
+			&cli.StringFlag{
+				Name:      "git-credentials",
+				EnvVars:   []string{"OSV_SCANNER_GIT_CREDENTIALS"},
+				Usage:     "authenticate with git hosts as described in the given YAML file when cloning remote repositories",
+				TakesFile: true,
+			},
 			&cli.StringFlag{
 				Name:    "format",
+				EnvVars: []string{"OSV_SCANNER_FORMAT"},
 				Aliases: []string{"f"},
 				Usage:   "sets the output format",
 				Value:   "table",
@@ -67,32 +269,363 @@ func run(args []string, stdout, stderr io.Writer) int {
 					case
 						"table",
 						"json",
-						"markdown":
+						"markdown",
+						"projects",
+						"azure-devops",
+						"github-annotations",
+						"diagnostics",
+						"sarif":
 						return nil
 					}
 
-					return fmt.Errorf("unsupported output format \"%s\" - must be one of: \"table\", \"json\", \"markdown\"", s)
+					return fmt.Errorf("unsupported output format \"%s\" - must be one of: \"table\", \"json\", \"markdown\", \"projects\", \"azure-devops\", \"github-annotations\", \"diagnostics\", \"sarif\"", s)
 				},
 			},
 			&cli.BoolFlag{
-				Name:  "json",
-				Usage: "sets output to json (deprecated, use --format json instead)",
+				Name:    "json",
+				EnvVars: []string{"OSV_SCANNER_JSON"},
+				Usage:   "sets output to json (deprecated, use --format json instead)",
+			},
+			&cli.BoolFlag{
+				Name:    "inventory-only",
+				EnvVars: []string{"OSV_SCANNER_INVENTORY_ONLY"},
+				Usage:   "list the packages that are found, with their package URLs and sources, without querying them for vulnerabilities",
+			},
+			&cli.BoolFlag{
+				Name:    "dry-run",
+				EnvVars: []string{"OSV_SCANNER_DRY_RUN"},
+				Usage:   "list the files and repositories that would be scanned, with the parser for each lockfile, and those that would be skipped, without scanning them",
+			},
+			&cli.StringFlag{
+				Name:      "sbom-output",
+				EnvVars:   []string{"OSV_SCANNER_SBOM_OUTPUT"},
+				Usage:     "also write an SBOM of every package that was found to the given `file`",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:    "sbom-format",
+				EnvVars: []string{"OSV_SCANNER_SBOM_FORMAT"},
+				Usage:   "sets the format of the SBOM written by --sbom-output, either cyclonedx or spdx",
+				Value:   "cyclonedx",
+				Action: func(context *cli.Context, s string) error {
+					if slices.Contains(output.SBOMFormats, s) {
+						return nil
+					}
+
+					return fmt.Errorf("unsupported SBOM format \"%s\" - must be one of: \"%s\"", s, strings.Join(output.SBOMFormats, "\", \""))
+				},
 			},
 			&cli.BoolFlag{
-				Name:  "skip-git",
-				Usage: "skip scanning git repositories",
-				Value: false,
+				Name:    "skip-git",
+				EnvVars: []string{"OSV_SCANNER_SKIP_GIT"},
+				Usage:   "skip scanning git repositories",
+				Value:   false,
+			},
+			&cli.StringFlag{
+				Name:    "nested-repos",
+				EnvVars: []string{"OSV_SCANNER_NESTED_REPOS"},
+				Usage:   "what to do with git repositories nested within scanned directories: scan, skip, or boundary to only scan their commit and not their files",
+				Value:   osvscanner.NestedReposScan,
+				Action: func(context *cli.Context, s string) error {
+					if slices.Contains(osvscanner.NestedRepoPolicies, s) {
+						return nil
+					}
+
+					return fmt.Errorf("unsupported nested repos policy \"%s\" - must be one of: \"%s\"", s, strings.Join(osvscanner.NestedRepoPolicies, "\", \""))
+				},
+			},
+			&cli.StringSliceFlag{
+				Name:    "parse-as",
+				EnvVars: []string{"OSV_SCANNER_PARSE_AS"},
+				Usage:   "parse files found while scanning directories that match a path as a lockfile, given as `PATH=PARSER` such as renamed-requirements.txt=requirements.txt",
 			},
 			&cli.BoolFlag{
 				Name:    "recursive",
+				EnvVars: []string{"OSV_SCANNER_RECURSIVE"},
 				Aliases: []string{"r"},
 				Usage:   "check subdirectories",
 				Value:   false,
 			},
 			&cli.BoolFlag{
-				Name:  "no-ignore",
-				Usage: "also scan files that would be ignored by .gitignore",
-				Value: false,
+				Name:    "no-ignore",
+				EnvVars: []string{"OSV_SCANNER_NO_IGNORE"},
+				Usage:   "also scan files that would be ignored by .gitignore",
+				Value:   false,
+			},
+			&cli.BoolFlag{
+				Name:    "strict",
+				EnvVars: []string{"OSV_SCANNER_STRICT"},
+				Usage:   "fail the scan if any input could not be parsed or fully scanned",
+				Value:   false,
+			},
+			&cli.Float64Flag{
+				Name:    "fail-on-score",
+				EnvVars: []string{"OSV_SCANNER_FAIL_ON_SCORE"},
+				Usage:   "only fail the scan for vulnerabilities whose effective `score` out of 10 is at least this, or is not known",
+			},
+			&cli.StringFlag{
+				Name:    "fail-on-severity",
+				EnvVars: []string{"OSV_SCANNER_FAIL_ON_SEVERITY"},
+				Usage:   "only fail the scan for vulnerabilities whose effective `severity` is at least this (low, medium, high or critical), or is not known",
+			},
+			&cli.BoolFlag{
+				Name:    "fail-only-if-fix-available",
+				EnvVars: []string{"OSV_SCANNER_FAIL_ONLY_IF_FIX_AVAILABLE"},
+				Usage:   "only fail the scan for vulnerabilities that have been fixed in a later version of their package",
+			},
+			&cli.StringSliceFlag{
+				Name:    "grace-period",
+				EnvVars: []string{"OSV_SCANNER_GRACE_PERIOD"},
+				Usage:   "only fail the scan for vulnerabilities published at least this many days ago, given as `SEVERITY=DAYS` or as DAYS for all other severities",
+			},
+			&cli.StringFlag{
+				Name:      "offline-db",
+				EnvVars:   []string{"OSV_SCANNER_OFFLINE_DB"},
+				Usage:     "match packages against the exports of the OSV database in the given `directory` instead of querying the API",
+				TakesFile: true,
+			},
+			&cli.BoolFlag{
+				Name:    "download-offline-db",
+				EnvVars: []string{"OSV_SCANNER_DOWNLOAD_OFFLINE_DB"},
+				Usage:   "download the exports of the ecosystems that are missing from --offline-db",
+			},
+			&cli.StringFlag{
+				Name:      "commit-cache",
+				EnvVars:   []string{"OSV_SCANNER_COMMIT_CACHE"},
+				Usage:     "cache the vulnerabilities of the git commits that are queried in the given `file`, to reuse in later scans",
+				TakesFile: true,
+			},
+			&cli.DurationFlag{
+				Name:    "commit-cache-ttl",
+				EnvVars: []string{"OSV_SCANNER_COMMIT_CACHE_TTL"},
+				Usage:   "how long the vulnerabilities of commits are cached for before they are queried again, or 0 to cache them forever",
+				Value:   24 * time.Hour,
+			},
+			&cli.StringFlag{
+				Name:      "query-cache",
+				EnvVars:   []string{"OSV_SCANNER_QUERY_CACHE"},
+				Usage:     "cache the vulnerabilities of the package versions that are queried in the given `directory`, to reuse in later scans",
+				TakesFile: true,
+			},
+			&cli.DurationFlag{
+				Name:    "query-cache-ttl",
+				EnvVars: []string{"OSV_SCANNER_QUERY_CACHE_TTL"},
+				Usage:   "how long the vulnerabilities of package versions are cached for before they are queried again, or 0 to cache them forever",
+				Value:   24 * time.Hour,
+			},
+			&cli.StringFlag{
+				Name:      "advisory-bundle",
+				EnvVars:   []string{"OSV_SCANNER_ADVISORY_BUNDLE"},
+				Usage:     "keep the details of the vulnerabilities that are found in the given `file`, such as .osv-scan-cache, to reuse in later scans",
+				TakesFile: true,
+			},
+			&cli.DurationFlag{
+				Name:    "advisory-bundle-ttl",
+				EnvVars: []string{"OSV_SCANNER_ADVISORY_BUNDLE_TTL"},
+				Usage:   "how long the details of vulnerabilities are kept for before they are fetched again when it is not known if they have been modified, or 0 to keep them forever",
+				Value:   24 * time.Hour,
+			},
+			&cli.BoolFlag{
+				Name:    "allow-partial-results",
+				EnvVars: []string{"OSV_SCANNER_ALLOW_PARTIAL_RESULTS"},
+				Usage:   "report the results obtained so far instead of failing if the OSV API becomes unavailable",
+				Value:   false,
+			},
+			&cli.StringFlag{
+				Name:    "github-dismissals",
+				EnvVars: []string{"OSV_SCANNER_GITHUB_DISMISSALS"},
+				Usage:   "ignore vulnerabilities whose alerts have been dismissed in the given GitHub `owner/repo`, authenticating with GITHUB_TOKEN",
+			},
+			&cli.StringFlag{
+				Name:    "github-repository",
+				EnvVars: []string{"OSV_SCANNER_GITHUB_REPOSITORY", "GITHUB_REPOSITORY"},
+				Usage:   "the GitHub `owner/repo` to publish results to",
+			},
+			&cli.IntFlag{
+				Name:    "github-pr-comment",
+				EnvVars: []string{"OSV_SCANNER_GITHUB_PR_COMMENT"},
+				Usage:   "post a summary of the results as a comment on the given pull request `number`, authenticating with GITHUB_TOKEN",
+			},
+			&cli.StringFlag{
+				Name:    "gitlab-project",
+				EnvVars: []string{"OSV_SCANNER_GITLAB_PROJECT", "CI_PROJECT_ID"},
+				Usage:   "the GitLab project `id or path` to publish results to",
+			},
+			&cli.IntFlag{
+				Name:    "gitlab-mr-comment",
+				EnvVars: []string{"OSV_SCANNER_GITLAB_MR_COMMENT"},
+				Usage:   "post a summary of the results as a note on the given merge request `iid`, authenticating with GITLAB_TOKEN or CI_JOB_TOKEN",
+			},
+			&cli.BoolFlag{
+				Name:    "bitbucket-report",
+				EnvVars: []string{"OSV_SCANNER_BITBUCKET_REPORT"},
+				Usage:   "publish the results as a Bitbucket Code Insights report, authenticating with BITBUCKET_TOKEN",
+			},
+			&cli.StringFlag{
+				Name:    "bitbucket-repository",
+				EnvVars: []string{"OSV_SCANNER_BITBUCKET_REPOSITORY", "BITBUCKET_REPO_FULL_NAME"},
+				Usage:   "the Bitbucket `workspace/repo` (or PROJECT/repo for Bitbucket Server) to publish the report to",
+			},
+			&cli.StringFlag{
+				Name:    "bitbucket-commit",
+				EnvVars: []string{"OSV_SCANNER_BITBUCKET_COMMIT", "BITBUCKET_COMMIT"},
+				Usage:   "the `commit` to attach the Bitbucket report to",
+			},
+			&cli.StringFlag{
+				Name:    "bitbucket-server-url",
+				EnvVars: []string{"OSV_SCANNER_BITBUCKET_SERVER_URL"},
+				Usage:   "the `url` of the Bitbucket Server instance to publish the report to, instead of Bitbucket Cloud",
+			},
+			&cli.StringFlag{
+				Name:    "azure-devops-work-items",
+				EnvVars: []string{"OSV_SCANNER_AZURE_DEVOPS_WORK_ITEMS"},
+				Usage:   "create an Azure DevOps work item of the given `type` (e.g. Bug) for each new vulnerability, authenticating with SYSTEM_ACCESSTOKEN",
+			},
+			&cli.StringFlag{
+				Name:      "store",
+				EnvVars:   []string{"OSV_SCANNER_STORE"},
+				Usage:     "record the findings of the scan in the scan history store at this path or database url",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:    "store-repository",
+				EnvVars: []string{"OSV_SCANNER_STORE_REPOSITORY"},
+				Usage:   "the `name` to record the scan under in the store, defaulting to the name of the current directory",
+			},
+			&cli.StringFlag{
+				Name:      "baseline",
+				EnvVars:   []string{"OSV_SCANNER_BASELINE"},
+				Usage:     "JSON results of a previous scan, whose vulnerabilities are not reported and do not fail the scan",
+				TakesFile: true,
+			},
+			&cli.BoolFlag{
+				Name:    "include-withdrawn",
+				EnvVars: []string{"OSV_SCANNER_INCLUDE_WITHDRAWN"},
+				Usage:   "report advisories that have been withdrawn, marked as such, rather than leaving them out",
+			},
+			&cli.StringFlag{
+				Name:      "base-results",
+				EnvVars:   []string{"OSV_SCANNER_BASE_RESULTS"},
+				Usage:     "JSON results from scanning the base branch, used to highlight new vulnerabilities when publishing results",
+				TakesFile: true,
+			},
+		},
+		Commands: []*cli.Command{
+			{
+				Name:  "bisect",
+				Usage: "finds the commit that introduced a version of a package to a lockfile, by walking the lockfile's git history",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:      "lockfile",
+						Aliases:   []string{"L"},
+						EnvVars:   []string{"OSV_SCANNER_BISECT_LOCKFILE"},
+						Usage:     "the lockfile to walk the history of, optionally prefixed with the parser to use",
+						TakesFile: true,
+					},
+					&cli.StringFlag{
+						Name:    "package",
+						EnvVars: []string{"OSV_SCANNER_BISECT_PACKAGE"},
+						Usage:   "the `name` of the package",
+					},
+					&cli.StringFlag{
+						Name:    "version",
+						EnvVars: []string{"OSV_SCANNER_BISECT_VERSION"},
+						Usage:   "the version of the package, defaulting to the version in the latest commit",
+					},
+					&cli.StringFlag{
+						Name:    "format",
+						EnvVars: []string{"OSV_SCANNER_BISECT_FORMAT"},
+						Aliases: []string{"f"},
+						Usage:   "sets the output format, either text or json",
+						Value:   "text",
+					},
+				},
+				Action: func(context *cli.Context) error {
+					r = output.NewReporter(stdout, stderr, context.String("format"))
+
+					return bisectAction(context, r)
+				},
+			},
+			{
+				Name:  "trends",
+				Usage: "reports how the findings of a repository have changed over the scans recorded in a store",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:      "store",
+						EnvVars:   []string{"OSV_SCANNER_STORE"},
+						Usage:     "the scan history store to report on",
+						TakesFile: true,
+					},
+					&cli.StringFlag{
+						Name:    "store-repository",
+						EnvVars: []string{"OSV_SCANNER_STORE_REPOSITORY"},
+						Usage:   "the `name` of the repository to report on, defaulting to the name of the current directory",
+					},
+					&cli.StringFlag{
+						Name:    "format",
+						EnvVars: []string{"OSV_SCANNER_TRENDS_FORMAT"},
+						Aliases: []string{"f"},
+						Usage:   "sets the output format, either table or json",
+						Value:   "table",
+					},
+				},
+				Action: func(context *cli.Context) error {
+					r = output.NewReporter(stdout, stderr, context.String("format"))
+
+					return trendsAction(context, r)
+				},
+			},
+			{
+				Name:  "serve",
+				Usage: "runs osv-scanner as a service, with a web dashboard over the scans recorded in a store",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:      "store",
+						EnvVars:   []string{"OSV_SCANNER_STORE"},
+						Usage:     "the scan history store to serve",
+						TakesFile: true,
+					},
+					&cli.StringFlag{
+						Name:    "listen",
+						EnvVars: []string{"OSV_SCANNER_LISTEN"},
+						Usage:   "the `address` to serve the dashboard on",
+						Value:   "localhost:8080",
+					},
+					&cli.StringFlag{
+						Name:      "projects",
+						EnvVars:   []string{"OSV_SCANNER_PROJECTS"},
+						Usage:     "YAML file listing projects to rescan on a schedule, recording the results in the store",
+						TakesFile: true,
+					},
+					&cli.DurationFlag{
+						Name:    "rescan-interval",
+						EnvVars: []string{"OSV_SCANNER_RESCAN_INTERVAL"},
+						Usage:   "how often to rescan projects that do not have their own interval",
+						Value:   24 * time.Hour,
+					},
+					&cli.StringFlag{
+						Name:      "git-credentials",
+						EnvVars:   []string{"OSV_SCANNER_GIT_CREDENTIALS"},
+						Usage:     "authenticate with git hosts as described in the given YAML file when cloning remote repositories",
+						TakesFile: true,
+					},
+				},
+				Action: func(context *cli.Context) error {
+					r = output.NewReporter(stdout, stderr, "")
+
+					return serveAction(context, r)
+				},
+			},
+			{
+				Name:  "lsp",
+				Usage: "runs a language server over stdio, publishing diagnostics for vulnerable packages in open lockfiles",
+				Action: func(context *cli.Context) error {
+					// stdout is used to talk to the client, so everything else
+					// has to go to stderr
+					r = output.NewReporter(stderr, stderr, "")
+
+					return lspAction(context, r)
+				},
 			},
 		},
 		ArgsUsage: "[directory1 directory2...]",
@@ -105,20 +638,165 @@ func run(args []string, stdout, stderr io.Writer) int {
 
 			r = output.NewReporter(stdout, stderr, format)
 
+			locale, err := localeFlag(context)
+			if err != nil {
+				return err
+			}
+			r.SetLocale(locale)
+
+			colorMode, theme, err := colorFlags(context)
+			if err != nil {
+				return err
+			}
+			r.SetColors(colorMode, theme)
+			r.SetQuiet(context.Bool("quiet"))
+
+			maxFileSize, err := byteSizeFlag(context, "max-file-size")
+			if err != nil {
+				return err
+			}
+			memoryBudget, err := byteSizeFlag(context, "memory-budget")
+			if err != nil {
+				return err
+			}
+			gracePeriods, err := osvscanner.ParseGracePeriods(context.StringSlice("grace-period"))
+			if err != nil {
+				return fmt.Errorf("invalid --grace-period: %w", err)
+			}
+			parserOverrides, err := osvscanner.ParseParserOverrides(context.StringSlice("parse-as"))
+			if err != nil {
+				return fmt.Errorf("invalid --parse-as: %w", err)
+			}
+			failOnSeverity, err := osvscanner.ParseSeverity(context.String("fail-on-severity"))
+			if err != nil {
+				return fmt.Errorf("invalid --fail-on-severity: %w", err)
+			}
+			inventoryOnly := context.Bool("inventory-only")
+			if inventoryOnly && !slices.Contains(output.InventoryFormats, format) {
+				return fmt.Errorf("--inventory-only does not support the %s format - must be one of: \"%s\"", format, strings.Join(output.InventoryFormats, "\", \""))
+			}
+			dryRun := context.Bool("dry-run")
+			if dryRun && inventoryOnly {
+				return fmt.Errorf("--dry-run cannot be used with --inventory-only")
+			}
+			if dryRun && !slices.Contains(output.PlanFormats, format) {
+				return fmt.Errorf("--dry-run does not support the %s format - must be one of: \"%s\"", format, strings.Join(output.PlanFormats, "\", \""))
+			}
+
+			var profile *osvscanner.Profile
+			if context.Bool("profile") {
+				profile = osvscanner.NewProfile()
+			}
+
+			stopCPUProfile, err := startCPUProfile(context.String("cpu-profile"))
+			if err != nil {
+				return err
+			}
+
 			vulnResult, err := osvscanner.DoScan(osvscanner.ScannerActions{
-				LockfilePaths:        context.StringSlice("lockfile"),
-				SBOMPaths:            context.StringSlice("sbom"),
-				DockerContainerNames: context.StringSlice("docker"),
-				Recursive:            context.Bool("recursive"),
-				SkipGit:              context.Bool("skip-git"),
-				NoIgnore:             context.Bool("no-ignore"),
-				ConfigOverridePath:   context.String("config"),
-				DirectoryPaths:       context.Args().Slice(),
+				LockfilePaths:              context.StringSlice("lockfile"),
+				SBOMPaths:                  context.StringSlice("sbom"),
+				ArtifactPaths:              context.StringSlice("artifact"),
+				DockerContainerNames:       context.StringSlice("docker"),
+				HostRoot:                   hostRoot(context),
+				Recursive:                  context.Bool("recursive"),
+				SkipGit:                    context.Bool("skip-git"),
+				NestedRepos:                context.String("nested-repos"),
+				ParserOverrides:            parserOverrides,
+				NoIgnore:                   context.Bool("no-ignore"),
+				AllowPartialResults:        context.Bool("allow-partial-results"),
+				Strict:                     context.Bool("strict"),
+				FailOnScore:                context.Float64("fail-on-score"),
+				FailOnSeverity:             failOnSeverity,
+				FailOnlyIfFixAvailable:     context.Bool("fail-only-if-fix-available"),
+				GracePeriods:               gracePeriods,
+				CollectInventory:           context.String("sbom-output") != "",
+				InventoryOnly:              inventoryOnly,
+				DryRun:                     dryRun,
+				CommitCachePath:            context.String("commit-cache"),
+				CommitCacheTTL:             context.Duration("commit-cache-ttl"),
+				QueryCachePath:             context.String("query-cache"),
+				QueryCacheTTL:              context.Duration("query-cache-ttl"),
+				AdvisoryBundlePath:         context.String("advisory-bundle"),
+				AdvisoryBundleTTL:          context.Duration("advisory-bundle-ttl"),
+				BaselinePath:               context.String("baseline"),
+				IncludeWithdrawn:           context.Bool("include-withdrawn"),
+				OfflineDatabasePath:        context.String("offline-db"),
+				DownloadOfflineDatabases:   context.Bool("download-offline-db"),
+				GitHubDismissalsRepository: context.String("github-dismissals"),
+				ConfigOverridePath:         context.String("config"),
+				TargetsPath:                context.String("targets"),
+				GitHubOrganization:         context.String("github-org"),
+				GitCredentialsPath:         context.String("git-credentials"),
+				Blame:                      context.Bool("blame"),
+				History:                    context.Bool("history") || context.Int("history-every") > 0,
+				HistoryEvery:               context.Int("history-every"),
+				VerifyRegistry:             context.Bool("verify-registry"),
+				CheckOutdated:              context.Bool("check-outdated"),
+				CheckDeprecated:            context.Bool("check-deprecated"),
+				FetchScorecards:            context.Bool("scorecard"),
+				AnalyzeUpgrades:            context.Bool("analyze-upgrades"),
+				CheckLicenses:              context.Bool("check-licenses"),
+				CallAnalysis:               context.Bool("call-analysis"),
+				FindSymbolUsages:           context.Bool("find-symbol-usages"),
+				ResolveManifests:           context.Bool("resolve-manifests"),
+				DetectTyposquats:           context.Bool("detect-typosquats"),
+				Platform:                   context.String("platform"),
+				ExcludedGroups:             context.StringSlice("exclude-group"),
+				PHPVersion:                 context.String("php-version"),
+				PHPExtensions:              context.StringSlice("php-extension"),
+				MaxFileSize:                maxFileSize,
+				MaxPackagesPerSource:       context.Int("max-packages-per-source"),
+				MemoryBudget:               memoryBudget,
+				RequestWorkers:             context.Int("request-workers"),
+				ParseWorkers:               context.Int("parse-workers"),
+				Profile:                    profile,
+				Reproducible:               context.Bool("reproducible"),
+				DirectoryPaths:             directoryPaths(context),
 			}, r)
 
-			if errPrint := r.PrintResult(&vulnResult); errPrint != nil {
+			done := profile.Track("formatting")
+			var errPrint error
+			switch {
+			case dryRun:
+				errPrint = r.PrintPlan(&vulnResult)
+			case inventoryOnly:
+				errPrint = r.PrintInventory(&vulnResult)
+			default:
+				errPrint = r.PrintResult(&vulnResult)
+			}
+			done()
+			stopCPUProfile()
+			if errPrint != nil {
 				return fmt.Errorf("failed to write output: %w", errPrint)
 			}
+
+			if profile != nil {
+				r.PrintText(profile.String())
+			}
+
+			if errProfile := writeHeapProfile(context.String("mem-profile")); errProfile != nil {
+				return errProfile
+			}
+
+			// nothing was scanned, so there is nothing to write or share
+			if !dryRun && (err == nil || errors.Is(err, osvscanner.VulnerabilitiesFoundErr) || errors.Is(err, osvscanner.ErrRegistryMismatch) || errors.Is(err, osvscanner.ErrLicenseViolation)) {
+				if errSBOM := writeSBOM(context, r, vulnResult); errSBOM != nil {
+					return errSBOM
+				}
+
+				// nothing was queried, so there are no results to share
+				if !inventoryOnly {
+					if errPublish := publishResults(context, r, vulnResult); errPublish != nil {
+						return errPublish
+					}
+
+					if errRecord := recordScan(context, r, vulnResult); errRecord != nil {
+						return errRecord
+					}
+				}
+			}
+
 			//nolint:wrapcheck
 			return err
 		},
@@ -128,12 +806,12 @@ func run(args []string, stdout, stderr io.Writer) int {
 		if r == nil {
 			r = output.NewReporter(stdout, stderr, "")
 		}
-		if errors.Is(err, osvscanner.VulnerabilitiesFoundErr) {
+		if errors.Is(err, osvscanner.VulnerabilitiesFoundErr) || errors.Is(err, osvscanner.ErrRegistryMismatch) || errors.Is(err, osvscanner.ErrLicenseViolation) {
 			return 1
 		}
 
 		if errors.Is(err, osvscanner.NoPackagesFoundErr) {
-			r.PrintError("No package sources found, --help for usage information.\n")
+			r.PrintError(r.Localize("No package sources found, --help for usage information.") + "\n")
 			return 128
 		}
 
@@ -149,6 +827,32 @@ func run(args []string, stdout, stderr io.Writer) int {
 	return 0
 }
 
+// directoryPaths returns the directories to scan, falling back to the
+// comma-separated OSV_SCANNER_DIRECTORIES environment variable if none were
+// passed as arguments
+func directoryPaths(context *cli.Context) []string {
+	if context.Args().Present() {
+		return context.Args().Slice()
+	}
+
+	env := os.Getenv("OSV_SCANNER_DIRECTORIES")
+	if env == "" {
+		return []string{}
+	}
+
+	return strings.Split(env, ",")
+}
+
+// hostRoot returns the root of the filesystem to audit, which is "" if the
+// host is not being audited
+func hostRoot(context *cli.Context) string {
+	if !context.Bool("audit-host") {
+		return ""
+	}
+
+	return context.String("host-root")
+}
+
 func main() {
 	os.Exit(run(os.Args, os.Stdout, os.Stderr))
 }
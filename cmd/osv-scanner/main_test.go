@@ -171,6 +171,19 @@ func TestRun(t *testing.T) {
 				Attempted to scan lockfile but failed: %%/fixtures/locks-many-with-invalid/composer.lock
 			`,
 		},
+		// files that fail to parse stop the scan in strict mode
+		{
+			name:         "",
+			args:         []string{"", "--strict", "./fixtures/locks-many-with-invalid"},
+			wantExitCode: 127,
+			wantStdout: `
+				Scanning dir ./fixtures/locks-many-with-invalid
+				Scanned %%/fixtures/locks-many-with-invalid/Gemfile.lock file and found 1 packages
+			`,
+			wantStderr: `
+				could not parse %%/fixtures/locks-many-with-invalid/composer.lock: invalid character ',' looking for beginning of object key string
+			`,
+		},
 		// only the files in the given directories are checked by default (no recursion)
 		{
 			name:         "",
@@ -231,6 +244,7 @@ func TestRun(t *testing.T) {
 			wantExitCode: 0,
 			wantStdout: `
 				{
+					"schemaVersion": 1,
 					"results": []
 				}
 			`,
@@ -245,6 +259,7 @@ func TestRun(t *testing.T) {
 			wantExitCode: 0,
 			wantStdout: `
 				{
+					"schemaVersion": 1,
 					"results": []
 				}
 			`,
@@ -275,6 +290,54 @@ func TestRun(t *testing.T) {
 	}
 }
 
+//nolint:paralleltest // t.Setenv cannot be used with t.Parallel
+func TestRun_EnvironmentVariables(t *testing.T) {
+	t.Setenv("OSV_SCANNER_LOCKFILE", "my-file:my-file")
+	t.Setenv("OSV_SCANNER_DIRECTORIES", "./fixtures/locks-many")
+
+	testCli(t, cliTestCase{
+		name:         "",
+		args:         []string{""},
+		wantExitCode: 127,
+		wantStdout:   "",
+		wantStderr: `
+			could not determine parser, requested my-file
+		`,
+	})
+}
+
+//nolint:paralleltest // t.Setenv cannot be used with t.Parallel
+func TestRun_SubcommandEnvironmentVariables(t *testing.T) {
+	// the format of a scan does not change the format of the subcommands
+	t.Setenv("OSV_SCANNER_FORMAT", "json")
+	t.Setenv("OSV_SCANNER_STORE", "./fixtures/history/history.json")
+	t.Setenv("OSV_SCANNER_STORE_REPOSITORY", "another-repo")
+
+	testCli(t, cliTestCase{
+		name:         "",
+		args:         []string{"", "trends"},
+		wantExitCode: 0,
+		wantStdout: `
+			No scans of another-repo have been recorded
+		`,
+		wantStderr: "",
+	})
+
+	// nor is the lockfile of a scan the one that is bisected
+	t.Setenv("OSV_SCANNER_LOCKFILE", "./fixtures/locks-many/package-lock.json")
+	t.Setenv("OSV_SCANNER_BISECT_PACKAGE", "lodash")
+
+	testCli(t, cliTestCase{
+		name:         "",
+		args:         []string{"", "bisect"},
+		wantExitCode: 127,
+		wantStdout:   "",
+		wantStderr: `
+			--lockfile and --package are required
+		`,
+	})
+}
+
 func TestRun_LockfileWithExplicitParseAs(t *testing.T) {
 	t.Parallel()
 
@@ -406,3 +469,441 @@ func TestRun_LockfileWithExplicitParseAs(t *testing.T) {
 		})
 	}
 }
+
+func TestRun_Trends(t *testing.T) {
+	t.Parallel()
+
+	tests := []cliTestCase{
+		{
+			name:         "",
+			args:         []string{"", "trends", "--store", "./fixtures/history/history.json", "--store-repository", "my-repo"},
+			wantExitCode: 0,
+			wantStdout: `
+				Trends for my-repo:
+				+----------------------+-----+-------+---------+-------+
+				| SCANNED AT           | NEW | FIXED | ONGOING | TOTAL |
+				+----------------------+-----+-------+---------+-------+
+				| 2023-01-01T00:00:00Z |   2 |     0 |       0 |     2 |
+				| 2023-02-01T00:00:00Z |   0 |     1 |       1 |     1 |
+				+----------------------+-----+-------+---------+-------+
+			`,
+			wantStderr: "",
+		},
+		{
+			name:         "",
+			args:         []string{"", "trends", "--store", "./fixtures/history/history.json", "--store-repository", "another-repo"},
+			wantExitCode: 0,
+			wantStdout: `
+				No scans of another-repo have been recorded
+			`,
+			wantStderr: "",
+		},
+		{
+			name:         "",
+			args:         []string{"", "trends"},
+			wantExitCode: 127,
+			wantStdout:   "",
+			wantStderr: `
+				--store is required
+			`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			testCli(t, tt)
+		})
+	}
+}
+
+func TestRun_Targets(t *testing.T) {
+	t.Parallel()
+
+	tests := []cliTestCase{
+		{
+			name:         "",
+			args:         []string{"", "--targets", "./fixtures/targets/targets.yaml"},
+			wantExitCode: 128,
+			wantStdout: `
+				Scanning target empty
+				Scanning dir fixtures/locks-empty
+				Scanned %%/fixtures/locks-empty/Gemfile.lock file and found 0 packages
+				Scanned %%/fixtures/locks-empty/composer.lock file and found 0 packages
+				Scanned %%/fixtures/locks-empty/yarn.lock file and found 0 packages
+				Scanning target missing
+				Target empty has 0 vulnerabilities
+				Target missing could not be scanned: (parsing as requirements.txt) could not open %%/fixtures/targets/does-not-exist.txt: %%
+				Scanned 2 targets: 0 with vulnerabilities, 1 could not be scanned
+			`,
+			wantStderr: `
+				No package sources found, --help for usage information.
+			`,
+		},
+		{
+			name:         "",
+			args:         []string{"", "--targets", "./fixtures/targets/invalid.yaml"},
+			wantExitCode: 127,
+			wantStdout:   "",
+			wantStderr: `
+				Failed to read targets file: invalid targets file: target 1 does not have a name
+				invalid targets file: target 1 does not have a name
+			`,
+		},
+		{
+			name:         "",
+			args:         []string{"", "--targets", "./fixtures/targets/targets.yaml", "./fixtures/locks-one-with-nested"},
+			wantExitCode: 127,
+			wantStdout:   "",
+			wantStderr: `
+				invalid targets file: cannot be combined with other inputs
+			`,
+		},
+		{
+			name:         "",
+			args:         []string{"", "--github-org", "my-org", "./fixtures/locks-one-with-nested"},
+			wantExitCode: 127,
+			wantStdout:   "",
+			wantStderr: `
+				scanning a GitHub organization cannot be combined with other inputs
+			`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			testCli(t, tt)
+		})
+	}
+}
+
+func TestRun_Baseline(t *testing.T) {
+	t.Parallel()
+
+	tests := []cliTestCase{
+		{
+			name:         "",
+			args:         []string{"", "--baseline", "./fixtures/does-not-exist.json", "./fixtures/locks-many/not-a-lockfile.toml"},
+			wantExitCode: 127,
+			wantStdout:   "",
+			wantStderr: `
+				could not read baseline: open ./fixtures/does-not-exist.json: no such file or directory
+			`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			testCli(t, tt)
+		})
+	}
+}
+
+func TestRun_Profile(t *testing.T) {
+	t.Parallel()
+
+	tests := []cliTestCase{
+		{
+			name:         "",
+			args:         []string{"", "--profile", "./fixtures/locks-many/not-a-lockfile.toml"},
+			wantExitCode: 128,
+			wantStdout: `
+				Scanning dir ./fixtures/locks-many/not-a-lockfile.toml
+				Scan profile:
+				  walking             %%
+				  parsing (sbom)      %%
+				  parsing (go-binary) %%
+				  formatting          %%
+				  total               %%
+			`,
+			wantStderr: `
+				No package sources found, --help for usage information.
+			`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			testCli(t, tt)
+		})
+	}
+}
+
+func TestRun_Locale(t *testing.T) {
+	t.Parallel()
+
+	tests := []cliTestCase{
+		{
+			name:         "",
+			args:         []string{"", "--locale", "de_DE.UTF-8", "./fixtures/locks-many/not-a-lockfile.toml"},
+			wantExitCode: 128,
+			wantStdout: `
+				Scanning dir ./fixtures/locks-many/not-a-lockfile.toml
+			`,
+			wantStderr: `
+				Keine Paketquellen gefunden, --help für Hinweise zur Verwendung.
+			`,
+		},
+		{
+			name:         "",
+			args:         []string{"", "--locale", "xx", "./fixtures/locks-many"},
+			wantExitCode: 127,
+			wantStdout:   "",
+			wantStderr: `
+				invalid --locale: unsupported locale "xx", expected one of de, en, es, fr
+			`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			testCli(t, tt)
+		})
+	}
+}
+
+func TestRun_Quiet(t *testing.T) {
+	t.Parallel()
+
+	tests := []cliTestCase{
+		{
+			name:         "",
+			args:         []string{"", "--quiet", "./fixtures/locks-many/not-a-lockfile.toml"},
+			wantExitCode: 128,
+			wantStdout:   "",
+			wantStderr: `
+				No package sources found, --help for usage information.
+			`,
+		},
+		{
+			name:         "",
+			args:         []string{"", "-q", "--format", "json", "--max-file-size", "10B", "-L", "./fixtures/locks-many/composer.lock"},
+			wantExitCode: 128,
+			wantStdout: `
+				{
+				  "schemaVersion": 1,
+				  "results": null,
+				  "skipped": [
+				    {
+				      "source": {
+				        "path": "%%/fixtures/locks-many/composer.lock",
+				        "type": "lockfile"
+				      },
+				      "reason": "limit exceeded: file is %% bytes, which is more than the maximum of 10"
+				    }
+				  ]
+				}
+			`,
+			wantStderr: `
+				No package sources found, --help for usage information.
+			`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			testCli(t, tt)
+		})
+	}
+}
+
+func TestRun_Limits(t *testing.T) {
+	t.Parallel()
+
+	tests := []cliTestCase{
+		{
+			name:         "",
+			args:         []string{"", "--max-file-size", "10B", "-L", "./fixtures/locks-many/composer.lock"},
+			wantExitCode: 128,
+			wantStdout: `
+				Skipping %%/fixtures/locks-many/composer.lock: limit exceeded: file is %% bytes, which is more than the maximum of 10
+			`,
+			wantStderr: `
+				No package sources found, --help for usage information.
+			`,
+		},
+		{
+			name:         "",
+			args:         []string{"", "--memory-budget", "lots", "./fixtures/locks-many"},
+			wantExitCode: 127,
+			wantStdout:   "",
+			wantStderr: `
+				invalid --memory-budget: "lots" is not a valid size
+			`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			testCli(t, tt)
+		})
+	}
+}
+
+func TestRun_GracePeriod(t *testing.T) {
+	t.Parallel()
+
+	tests := []cliTestCase{
+		{
+			name:         "",
+			args:         []string{"", "--grace-period", "SEVERE=7", "./fixtures/locks-many"},
+			wantExitCode: 127,
+			wantStdout:   "",
+			wantStderr: `
+				invalid --grace-period: "SEVERE" is not a severity, expected one of LOW, MEDIUM, HIGH, CRITICAL or UNKNOWN
+			`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			testCli(t, tt)
+		})
+	}
+}
+
+func TestRun_SBOM(t *testing.T) {
+	t.Parallel()
+
+	tests := []cliTestCase{
+		{
+			name:         "",
+			args:         []string{"", "--sbom-output", "bom.json", "--sbom-format", "swid", "./fixtures/locks-many"},
+			wantExitCode: 127,
+			wantStdout:   "",
+			wantStderr: `
+				unsupported SBOM format "swid" - must be one of: "cyclonedx", "spdx"
+			`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			testCli(t, tt)
+		})
+	}
+}
+
+func TestRun_InventoryOnly(t *testing.T) {
+	t.Parallel()
+
+	tests := []cliTestCase{
+		{
+			name:         "",
+			args:         []string{"", "--inventory-only", "./fixtures/locks-many/composer.lock"},
+			wantExitCode: 0,
+			wantStdout: `
+				Scanning dir ./fixtures/locks-many/composer.lock
+				Scanned %%/fixtures/locks-many/composer.lock file and found 1 packages
+				Found 1 packages
+				+-----------+------------+---------+-------------------------------+-----------------------------------+
+				| ECOSYSTEM | PACKAGE    | VERSION | PACKAGE URL                   | SOURCE                            |
+				+-----------+------------+---------+-------------------------------+-----------------------------------+
+				| Packagist | sentry/sdk | 2.0.4   | pkg:composer/sentry/sdk@2.0.4 | fixtures/locks-many/composer.lock |
+				+-----------+------------+---------+-------------------------------+-----------------------------------+
+			`,
+			wantStderr: "",
+		},
+		{
+			name:         "",
+			args:         []string{"", "--inventory-only", "--format", "sarif", "./fixtures/locks-many"},
+			wantExitCode: 127,
+			wantStdout:   "",
+			wantStderr: `
+				--inventory-only does not support the sarif format - must be one of: "table", "markdown", "json"
+			`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			testCli(t, tt)
+		})
+	}
+}
+
+func TestRun_DryRun(t *testing.T) {
+	t.Parallel()
+
+	tests := []cliTestCase{
+		{
+			name:         "",
+			args:         []string{"", "--dry-run", "--format", "markdown", "./fixtures/locks-many"},
+			wantExitCode: 0,
+			wantStdout: `
+				Planning the scan of dir ./fixtures/locks-many
+				Would scan 3 inputs and skip 0
+				| Source | Type | Parser | Skipped |
+				| --- | --- | --- | --- |
+				| fixtures/locks-many/Gemfile.lock | lockfile | Gemfile.lock |  |
+				| fixtures/locks-many/composer.lock | lockfile | composer.lock |  |
+				| fixtures/locks-many/yarn.lock | lockfile | yarn.lock |  |
+			`,
+			wantStderr: "",
+		},
+		{
+			name:         "",
+			args:         []string{"", "--dry-run", "--inventory-only", "./fixtures/locks-many"},
+			wantExitCode: 127,
+			wantStdout:   "",
+			wantStderr: `
+				--dry-run cannot be used with --inventory-only
+			`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			testCli(t, tt)
+		})
+	}
+}
+
+func TestRun_ParseAs(t *testing.T) {
+	t.Parallel()
+
+	tests := []cliTestCase{
+		{
+			name:         "",
+			args:         []string{"", "--dry-run", "--format", "markdown", "--parse-as", "not-a-lockfile.toml=Cargo.lock", "./fixtures/locks-many"},
+			wantExitCode: 0,
+			wantStdout: `
+				Planning the scan of dir ./fixtures/locks-many
+				Would scan 4 inputs and skip 0
+				| Source | Type | Parser | Skipped |
+				| --- | --- | --- | --- |
+				| fixtures/locks-many/Gemfile.lock | lockfile | Gemfile.lock |  |
+				| fixtures/locks-many/composer.lock | lockfile | composer.lock |  |
+				| fixtures/locks-many/not-a-lockfile.toml | lockfile | Cargo.lock |  |
+				| fixtures/locks-many/yarn.lock | lockfile | yarn.lock |  |
+			`,
+			wantStderr: "",
+		},
+		{
+			name:         "",
+			args:         []string{"", "--parse-as", "not-a-lockfile.toml", "./fixtures/locks-many"},
+			wantExitCode: 127,
+			wantStdout:   "",
+			wantStderr: `
+				invalid --parse-as: "not-a-lockfile.toml" is not a path and parser, expected PATH=PARSER
+			`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			testCli(t, tt)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/google/osv-scanner/pkg/output"
+	"github.com/urfave/cli/v2"
+)
+
+// localeFlag returns the locale selected by the --locale flag, detecting it
+// from the environment if it is "auto"
+func localeFlag(context *cli.Context) (output.Locale, error) {
+	value := context.String("locale")
+	if value == "auto" {
+		return output.LocaleFromEnvironment(), nil
+	}
+
+	locale, err := output.ParseLocale(value)
+	if err != nil {
+		return output.DefaultLocale, fmt.Errorf("invalid --locale: %w", err)
+	}
+
+	return locale, nil
+}
+
+// colorFlags returns the color mode and theme selected by the --color and
+// --theme flags
+func colorFlags(context *cli.Context) (output.ColorMode, output.Theme, error) {
+	mode, err := output.ParseColorMode(context.String("color"))
+	if err != nil {
+		return mode, output.Theme{}, fmt.Errorf("invalid --color: %w", err)
+	}
+
+	theme, err := output.ParseTheme(context.String("theme"))
+	if err != nil {
+		return mode, theme, fmt.Errorf("invalid --theme: %w", err)
+	}
+
+	return mode, theme, nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/osv-scanner/pkg/azuredevops"
+	"github.com/google/osv-scanner/pkg/bitbucket"
+	"github.com/google/osv-scanner/pkg/github"
+	"github.com/google/osv-scanner/pkg/gitlab"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/output"
+	"github.com/google/osv-scanner/pkg/publisher"
+	"github.com/urfave/cli/v2"
+)
+
+// publishers returns the publishers that have been enabled with flags
+func publishers(context *cli.Context) ([]publisher.Publisher, error) {
+	var pubs []publisher.Publisher
+
+	if number := context.Int("github-pr-comment"); number != 0 {
+		owner, repo, err := github.SplitRepository(context.String("github-repository"))
+		if err != nil {
+			return nil, fmt.Errorf("--github-pr-comment requires --github-repository: %w", err)
+		}
+
+		pubs = append(pubs, publisher.GitHubPullRequest{
+			Client: github.NewClient(),
+			Owner:  owner,
+			Repo:   repo,
+			Number: number,
+		})
+	}
+
+	if iid := context.Int("gitlab-mr-comment"); iid != 0 {
+		project := context.String("gitlab-project")
+		if project == "" {
+			return nil, errors.New("--gitlab-mr-comment requires --gitlab-project")
+		}
+
+		pubs = append(pubs, publisher.GitLabMergeRequest{
+			Client:  gitlab.NewClient(),
+			Project: project,
+			IID:     iid,
+		})
+	}
+
+	if context.Bool("bitbucket-report") {
+		owner, repo, err := bitbucket.SplitRepository(context.String("bitbucket-repository"))
+		if err != nil {
+			return nil, fmt.Errorf("--bitbucket-report requires --bitbucket-repository: %w", err)
+		}
+
+		commit := context.String("bitbucket-commit")
+		if commit == "" {
+			return nil, errors.New("--bitbucket-report requires --bitbucket-commit")
+		}
+
+		pubs = append(pubs, publisher.BitbucketCodeInsights{
+			Client: bitbucket.NewClient(context.String("bitbucket-server-url")),
+			Owner:  owner,
+			Repo:   repo,
+			Commit: commit,
+		})
+	}
+
+	if workItemType := context.String("azure-devops-work-items"); workItemType != "" {
+		client := azuredevops.NewClient()
+		if client.CollectionURL == "" || client.Project == "" {
+			return nil, errors.New("--azure-devops-work-items must be used within an Azure Pipelines run")
+		}
+
+		pubs = append(pubs, publisher.AzureDevOpsWorkItems{
+			Client:       client,
+			WorkItemType: workItemType,
+		})
+	}
+
+	return pubs, nil
+}
+
+// publishResults publishes the results of the scan with every enabled
+// publisher, comparing them to the results given with --base-results if any
+func publishResults(context *cli.Context, r *output.WriterReporter, results models.VulnerabilityResults) error {
+	pubs, err := publishers(context)
+	if err != nil || len(pubs) == 0 {
+		return err
+	}
+
+	var base *models.VulnerabilityResults
+	if path := context.String("base-results"); path != "" {
+		baseResults, err := publisher.LoadResults(path)
+		if err != nil {
+			return err
+		}
+		base = &baseResults
+	}
+
+	for _, pub := range pubs {
+		if err := pub.Publish(context.Context, results, base); err != nil {
+			return fmt.Errorf("failed to publish results: %w", err)
+		}
+	}
+
+	r.PrintText(fmt.Sprintf("Published results to %d destination(s)\n", len(pubs)))
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/output"
+	"github.com/urfave/cli/v2"
+)
+
+// writeSBOM writes the inventory of the scan to the file given by the
+// --sbom-output flag, if there is one
+func writeSBOM(context *cli.Context, r *output.WriterReporter, results models.VulnerabilityResults) error {
+	path := context.String("sbom-output")
+	if path == "" {
+		return nil
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to write SBOM: %w", err)
+	}
+
+	err = output.PrintSBOM(&results, context.String("sbom-format"), f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write SBOM: %w", err)
+	}
+
+	r.PrintText(fmt.Sprintf("Wrote an SBOM of the packages that were found to %s\n", path))
+
+	return nil
+}
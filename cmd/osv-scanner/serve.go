@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/osv-scanner/pkg/gitauth"
+	"github.com/google/osv-scanner/pkg/output"
+	"github.com/google/osv-scanner/pkg/server"
+	"github.com/google/osv-scanner/pkg/store"
+	"github.com/urfave/cli/v2"
+)
+
+// serveAction runs the web dashboard over the store, and rescans any projects
+// given with --projects, until the process is stopped
+func serveAction(context *cli.Context, r *output.WriterReporter) error {
+	path := context.String("store")
+	if path == "" {
+		return errors.New("--store is required")
+	}
+
+	s, err := store.Open(path)
+	if err != nil {
+		//nolint:wrapcheck
+		return err
+	}
+
+	if projectsPath := context.String("projects"); projectsPath != "" {
+		projects, err := server.LoadProjects(projectsPath)
+		if err != nil {
+			//nolint:wrapcheck
+			return err
+		}
+
+		var credentials gitauth.Credentials
+		if credentialsPath := context.String("git-credentials"); credentialsPath != "" {
+			credentials, err = gitauth.Load(credentialsPath)
+			if err != nil {
+				return fmt.Errorf("failed to read git credentials: %w", err)
+			}
+		}
+
+		scheduler := &server.Scheduler{
+			Store:           s,
+			Projects:        projects,
+			DefaultInterval: context.Duration("rescan-interval"),
+			Scan:            server.ProjectScanner(credentials),
+			Reporter:        r,
+		}
+
+		r.PrintText(fmt.Sprintf("Scheduled %d projects to be rescanned\n", len(projects)))
+
+		// the scheduler runs for as long as the server does
+		go scheduler.Run(make(chan struct{}))
+	}
+
+	httpServer := &http.Server{
+		Addr:              context.String("listen"),
+		Handler:           server.NewDashboard(s),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	r.PrintText(fmt.Sprintf("Serving dashboard on %s\n", httpServer.Addr))
+
+	//nolint:wrapcheck
+	return httpServer.ListenAndServe()
+}
@@ -0,0 +1,5 @@
+module example.com/svc
+
+go 1.19
+
+require github.com/gogo/protobuf v1.3.1
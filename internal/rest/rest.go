@@ -0,0 +1,119 @@
+// Package rest sends the JSON requests of the minimal clients of the REST APIs
+// of code hosts, such as GitHub and GitLab
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/osv-scanner/pkg/osv"
+)
+
+// HTTPClient is used by the clients unless they are given another, bounding
+// each request by a timeout so that an unresponsive host cannot hang a scan
+var HTTPClient = osv.NewHTTPClient(osv.ClientOptions{
+	Timeout:             30 * time.Second,
+	MaxIdleConnsPerHost: 2,
+	IdleConnTimeout:     90 * time.Second,
+	TLSHandshakeTimeout: 10 * time.Second,
+})
+
+// Request is a request to a REST API
+type Request struct {
+	Method string
+	URL    string
+	// Path is the path within the API that URL is of, which failed requests
+	// are reported with
+	Path   string
+	Header http.Header
+	// Body is encoded as JSON if it is not nil, being sent as
+	// "application/json" unless Header has another content type
+	Body any
+}
+
+// Do sends the request to the API named `api` with httpClient, or HTTPClient
+// if it is nil, decoding the JSON response into `out` if it is not nil. The
+// request is abandoned once ctx is done.
+func Do(ctx context.Context, httpClient *http.Client, api string, request Request, out any) error {
+	var reqBody io.Reader
+	if request.Body != nil {
+		buf, err := json.Marshal(request.Body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(buf)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, request.Method, request.URL, reqBody)
+	if err != nil {
+		return err
+	}
+
+	for name, values := range request.Header {
+		req.Header[name] = values
+	}
+	if request.Body != nil && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if httpClient == nil {
+		httpClient = HTTPClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBuf, _ := io.ReadAll(resp.Body)
+
+		return fmt.Errorf("%s %s %s failed with %s: %s", api, request.Method, request.Path, resp.Status, string(respBuf))
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// ListAll fetches every page of a listing endpoint that is paginated with the
+// "page" and "per_page" parameters, getting each page with the query and
+// calling `collect` with the items decoded from it
+func ListAll[T any](perPage int, query url.Values, get func(query url.Values, items *[]T) error, collect func([]T)) error {
+	for page := 1; ; page++ {
+		query.Set("per_page", fmt.Sprint(perPage))
+		query.Set("page", fmt.Sprint(page))
+
+		var items []T
+		if err := get(query, &items); err != nil {
+			return err
+		}
+
+		collect(items)
+
+		if len(items) < perPage {
+			return nil
+		}
+	}
+}
+
+// SplitRepository splits a repository such as "owner/repo" into its two parts,
+// with `expected` describing the form it should take when it is invalid
+func SplitRepository(repository string, expected string) (string, string, error) {
+	owner, name, found := strings.Cut(repository, "/")
+	if !found || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("invalid repository %q, expected %s", repository, expected)
+	}
+
+	return owner, name, nil
+}
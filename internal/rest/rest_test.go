@@ -0,0 +1,148 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/internal/rest"
+)
+
+func TestHTTPClient_HasTimeout(t *testing.T) {
+	t.Parallel()
+
+	if rest.HTTPClient.Timeout <= 0 {
+		t.Errorf("expected the shared client to have a timeout")
+	}
+}
+
+func TestDo(t *testing.T) {
+	t.Parallel()
+
+	var gotContentType, gotToken string
+	var gotBody map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotContentType = r.Header.Get("Content-Type")
+		gotToken = r.Header.Get("PRIVATE-TOKEN")
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		_, _ = w.Write([]byte(`{"id": 7}`))
+	}))
+	defer server.Close()
+
+	header := http.Header{}
+	header.Set("PRIVATE-TOKEN", "secret")
+
+	var out struct {
+		ID int `json:"id"`
+	}
+	err := rest.Do(context.Background(), server.Client(), "gitlab", rest.Request{
+		Method: http.MethodPost,
+		URL:    server.URL + "/notes",
+		Path:   "/notes",
+		Header: header,
+		Body:   map[string]string{"body": "hello"},
+	}, &out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if out.ID != 7 {
+		t.Errorf("expected the response to be decoded, got %d", out.ID)
+	}
+	if gotContentType != "application/json" {
+		t.Errorf("expected a JSON content type, got %q", gotContentType)
+	}
+	if gotToken != "secret" {
+		t.Errorf("expected the header to be sent, got %q", gotToken)
+	}
+	if diff := cmp.Diff(map[string]string{"body": "hello"}, gotBody); diff != "" {
+		t.Errorf("body mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDo_Failure(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not allowed", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	err := rest.Do(context.Background(), server.Client(), "github", rest.Request{
+		Method: http.MethodGet,
+		URL:    server.URL + "/repos/google/osv-scanner",
+		Path:   "/repos/google/osv-scanner",
+	}, nil)
+
+	if err == nil || !strings.HasPrefix(err.Error(), "github GET /repos/google/osv-scanner failed with 403 Forbidden") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDo_Timeout(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	httpClient := server.Client()
+	httpClient.Timeout = 50 * time.Millisecond
+
+	err := rest.Do(context.Background(), httpClient, "bitbucket", rest.Request{Method: http.MethodGet, URL: server.URL, Path: "/"}, nil)
+	if err == nil {
+		t.Errorf("expected the request to time out")
+	}
+}
+
+func TestListAll(t *testing.T) {
+	t.Parallel()
+
+	var pages []string
+	var got []int
+	err := rest.ListAll(2, url.Values{"state": {"dismissed"}}, func(query url.Values, items *[]int) error {
+		pages = append(pages, query.Encode())
+		if query.Get("page") == "1" {
+			*items = []int{1, 2}
+		} else {
+			*items = []int{3}
+		}
+
+		return nil
+	}, func(items []int) {
+		got = append(got, items...)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff([]int{1, 2, 3}, got); diff != "" {
+		t.Errorf("items mismatch (-want +got):\n%s", diff)
+	}
+	wantPages := []string{"page=1&per_page=2&state=dismissed", "page=2&per_page=2&state=dismissed"}
+	if diff := cmp.Diff(wantPages, pages); diff != "" {
+		t.Errorf("pages mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSplitRepository(t *testing.T) {
+	t.Parallel()
+
+	owner, repo, err := rest.SplitRepository("google/osv-scanner", "owner/repo")
+	if err != nil || owner != "google" || repo != "osv-scanner" {
+		t.Errorf("unexpected split: %q %q %v", owner, repo, err)
+	}
+
+	for _, invalid := range []string{"", "google", "/osv-scanner", "google/", "google/osv/scanner"} {
+		if _, _, err := rest.SplitRepository(invalid, "owner/repo"); err == nil {
+			t.Errorf("expected %q to be invalid", invalid)
+		}
+	}
+}
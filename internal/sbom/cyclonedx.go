@@ -1,55 +1,113 @@
 package sbom
 
 import (
+	"bufio"
 	"fmt"
 	"io"
 	"strings"
+	"unicode"
 
 	"github.com/CycloneDX/cyclonedx-go"
 )
 
 type CycloneDX struct{}
 
-var (
-	cycloneDXTypes = []cyclonedx.BOMFileFormat{
-		cyclonedx.BOMFileFormatJSON,
-		cyclonedx.BOMFileFormatXML,
-	}
-)
+// cycloneDXNamespace prefixes the XML namespaces of every version of the
+// CycloneDX schema, such as "http://cyclonedx.org/schema/bom/1.4"
+const cycloneDXNamespace = "http://cyclonedx.org/schema/bom"
 
 func (c *CycloneDX) Name() string {
 	return "CycloneDX"
 }
 
-func (c *CycloneDX) enumeratePackages(bom *cyclonedx.BOM, callback func(Identifier) error) error {
-	for _, component := range *bom.Components {
+// enumerateComponents calls the callback for each of the components that has
+// a Package URL, including those nested within other components such as the
+// libraries bundled into a jar, returning the index of the next component
+func (c *CycloneDX) enumerateComponents(components *[]cyclonedx.Component, index int, callback func(Identifier) error) (int, error) {
+	if components == nil {
+		return index, nil
+	}
+
+	for _, component := range *components {
 		if component.PackageURL != "" {
 			err := callback(Identifier{
-				PURL: component.PackageURL,
+				PURL:   component.PackageURL,
+				BOMRef: component.BOMRef,
+				Index:  index,
 			})
 			if err != nil {
-				return err
+				return index, err
 			}
 		}
+		index++
+
+		var err error
+		index, err = c.enumerateComponents(component.Components, index, callback)
+		if err != nil {
+			return index, err
+		}
 	}
 
-	return nil
+	return index, nil
+}
+
+func (c *CycloneDX) enumeratePackages(bom *cyclonedx.BOM, callback func(Identifier) error) error {
+	_, err := c.enumerateComponents(bom.Components, 0, callback)
+
+	return err
+}
+
+// detectFormat returns whether the SBOM is in XML or JSON by its first
+// non-whitespace character, as CycloneDX SBOMs come in either
+func (c *CycloneDX) detectFormat(r io.Reader) (cyclonedx.BOMFileFormat, error) {
+	reader := bufio.NewReader(r)
+	for {
+		char, _, err := reader.ReadRune()
+		if err != nil {
+			return 0, ErrInvalidFormat
+		}
+
+		switch {
+		case char == '\uFEFF' || unicode.IsSpace(char):
+			continue
+		case char == '<':
+			return cyclonedx.BOMFileFormatXML, nil
+		case char == '{':
+			return cyclonedx.BOMFileFormatJSON, nil
+		default:
+			return 0, ErrInvalidFormat
+		}
+	}
 }
 
 func (c *CycloneDX) GetPackages(r io.ReadSeeker, callback func(Identifier) error) error {
 	var bom cyclonedx.BOM
 
-	for _, formatType := range cycloneDXTypes {
-		_, err := r.Seek(0, io.SeekStart)
-		if err != nil {
-			return fmt.Errorf("failed to seek to start of file: %w", err)
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek to start of file: %w", err)
+	}
+	formatType, err := c.detectFormat(r)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek to start of file: %w", err)
+	}
+	if err := cyclonedx.NewBOMDecoder(r, formatType).Decode(&bom); err != nil {
+		return ErrInvalidFormat
+	}
+
+	switch formatType {
+	case cyclonedx.BOMFileFormatXML:
+		if !strings.HasPrefix(bom.XMLNS, cycloneDXNamespace) {
+			return ErrInvalidFormat
 		}
-		decoder := cyclonedx.NewBOMDecoder(r, formatType)
-		err = decoder.Decode(&bom)
-		if err == nil && (bom.BOMFormat == "CycloneDX" || strings.HasPrefix(bom.XMLNS, "http://cyclonedx.org/schema/bom")) {
-			return c.enumeratePackages(&bom, callback)
+	case cyclonedx.BOMFileFormatJSON:
+		if bom.BOMFormat != "CycloneDX" {
+			return ErrInvalidFormat
 		}
 	}
 
-	return ErrInvalidFormat
+	return c.enumeratePackages(&bom, callback)
 }
@@ -8,6 +8,13 @@ import (
 // Identifier is the identifier extracted from the SBOM.
 type Identifier struct {
 	PURL string
+	// BOMRef identifies the component within the SBOM, being the bom-ref of a
+	// CycloneDX component or the SPDXID of an SPDX package
+	BOMRef string
+	// Index is the position of the component amongst the components (or
+	// packages) of the SBOM, starting from 0, with nested CycloneDX components
+	// counted in the order they appear in the document
+	Index int
 }
 
 // SBOMReader is an interface for all SBOM providers.
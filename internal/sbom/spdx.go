@@ -7,6 +7,7 @@ import (
 
 	"github.com/spdx/tools-golang/json"
 	"github.com/spdx/tools-golang/rdfloader"
+	"github.com/spdx/tools-golang/spdx/common"
 	"github.com/spdx/tools-golang/spdx/v2_3"
 	"github.com/spdx/tools-golang/tvloader"
 )
@@ -27,11 +28,13 @@ func (s *SPDX) Name() string {
 }
 
 func (s *SPDX) enumeratePackages(doc *v2_3.Document, callback func(Identifier) error) error {
-	for _, p := range doc.Packages {
+	for i, p := range doc.Packages {
 		for _, r := range p.PackageExternalReferences {
 			if r.RefType == "purl" {
 				err := callback(Identifier{
-					PURL: r.Locator,
+					PURL:   r.Locator,
+					BOMRef: common.RenderElementID(p.PackageSPDXIdentifier),
+					Index:  i,
 				})
 				if err != nil {
 					return err
@@ -0,0 +1,64 @@
+package azuredevops
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/rest"
+)
+
+// apiVersion is the version of the Azure DevOps REST API that is used
+const apiVersion = "7.0"
+
+// Client is a minimal client for the parts of the Azure DevOps REST API used by osv-scanner
+type Client struct {
+	// CollectionURL is the URL of the organization or collection,
+	// e.g. https://dev.azure.com/my-org/
+	CollectionURL string
+	Project       string
+	Token         string
+	HTTPClient    *http.Client
+}
+
+// NewClient creates a client for the collection and project the current
+// pipeline is running in, authenticated with the pipeline's access token
+// (which must be mapped to the SYSTEM_ACCESSTOKEN environment variable)
+func NewClient() *Client {
+	return &Client{
+		CollectionURL: os.Getenv("SYSTEM_COLLECTIONURI"),
+		Project:       os.Getenv("SYSTEM_TEAMPROJECT"),
+		Token:         os.Getenv("SYSTEM_ACCESSTOKEN"),
+		HTTPClient:    rest.HTTPClient,
+	}
+}
+
+// do sends a request to the given API path within the project, encoding
+// `body` as JSON with the given content type and decoding the response into
+// `out` if it is not nil
+func (c *Client) do(ctx context.Context, method string, path string, contentType string, body any, out any) error {
+	endpoint := fmt.Sprintf(
+		"%s/%s/_apis/%s?api-version=%s",
+		strings.TrimSuffix(c.CollectionURL, "/"),
+		url.PathEscape(c.Project),
+		path,
+		apiVersion,
+	)
+
+	header := http.Header{}
+	header.Set("Content-Type", contentType)
+	if c.Token != "" {
+		header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	return rest.Do(ctx, c.HTTPClient, "azure devops", rest.Request{
+		Method: method,
+		URL:    endpoint,
+		Path:   path,
+		Header: header,
+		Body:   body,
+	}, out)
+}
@@ -0,0 +1,65 @@
+package azuredevops
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// WorkItem is the subset of fields osv-scanner sets when creating work items
+type WorkItem struct {
+	Type        string
+	Title       string
+	Description string
+	Tags        []string
+}
+
+type patchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value string `json:"value"`
+}
+
+type wiqlResponse struct {
+	WorkItems []struct {
+		ID int `json:"id"`
+	} `json:"workItems"`
+}
+
+// HasOpenWorkItem reports if there is a work item in the project with the
+// given title that has not been closed or removed
+func (c *Client) HasOpenWorkItem(ctx context.Context, title string) (bool, error) {
+	query := fmt.Sprintf(
+		"SELECT [System.Id] FROM WorkItems WHERE [System.TeamProject] = @project AND [System.Title] = '%s' AND [System.State] NOT IN ('Closed', 'Done', 'Removed')",
+		strings.ReplaceAll(title, "'", "''"),
+	)
+
+	var resp wiqlResponse
+	if err := c.do(ctx, http.MethodPost, "wit/wiql", "application/json", map[string]string{"query": query}, &resp); err != nil {
+		return false, fmt.Errorf("failed to query work items: %w", err)
+	}
+
+	return len(resp.WorkItems) > 0, nil
+}
+
+// CreateWorkItem creates a new work item in the project
+func (c *Client) CreateWorkItem(ctx context.Context, item WorkItem) error {
+	operations := []patchOperation{
+		{Op: "add", Path: "/fields/System.Title", Value: item.Title},
+		{Op: "add", Path: "/fields/System.Description", Value: item.Description},
+	}
+
+	if len(item.Tags) > 0 {
+		operations = append(operations, patchOperation{Op: "add", Path: "/fields/System.Tags", Value: strings.Join(item.Tags, "; ")})
+	}
+
+	path := "wit/workitems/$" + url.PathEscape(item.Type)
+
+	if err := c.do(ctx, http.MethodPost, path, "application/json-patch+json", operations, nil); err != nil {
+		return fmt.Errorf("failed to create work item: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package azuredevops_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/azuredevops"
+)
+
+func TestClient_HasOpenWorkItem(t *testing.T) {
+	t.Parallel()
+
+	var gotQuery string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/my-org/my project/_apis/wit/wiql" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		gotQuery = body["query"]
+
+		_, _ = w.Write([]byte(`{"workItems": [{"id": 42}]}`))
+	}))
+	defer server.Close()
+
+	client := &azuredevops.Client{CollectionURL: server.URL + "/my-org/", Project: "my project"}
+
+	found, err := client.HasOpenWorkItem(context.Background(), "it's vulnerable")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !found {
+		t.Errorf("expected an open work item to be found")
+	}
+
+	want := "SELECT [System.Id] FROM WorkItems WHERE [System.TeamProject] = @project AND [System.Title] = 'it''s vulnerable' AND [System.State] NOT IN ('Closed', 'Done', 'Removed')"
+	if gotQuery != want {
+		t.Errorf("unexpected query: %s", cmp.Diff(want, gotQuery))
+	}
+}
+
+func TestClient_CreateWorkItem(t *testing.T) {
+	t.Parallel()
+
+	var gotPath, gotContentType, gotAuth string
+	var gotOperations []map[string]string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotContentType = r.Header.Get("Content-Type")
+		gotAuth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&gotOperations)
+	}))
+	defer server.Close()
+
+	client := &azuredevops.Client{CollectionURL: server.URL, Project: "project", Token: "token"}
+
+	err := client.CreateWorkItem(context.Background(), azuredevops.WorkItem{
+		Type:        "Bug",
+		Title:       "GHSA-vh95-rmgr-6w4m in minimist (npm)",
+		Description: "Prototype Pollution",
+		Tags:        []string{"osv-scanner", "security"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotPath != "/project/_apis/wit/workitems/$Bug" {
+		t.Errorf("unexpected path %s", gotPath)
+	}
+
+	if gotContentType != "application/json-patch+json" {
+		t.Errorf("unexpected content type %s", gotContentType)
+	}
+
+	if gotAuth != "Bearer token" {
+		t.Errorf("unexpected authorization %s", gotAuth)
+	}
+
+	want := []map[string]string{
+		{"op": "add", "path": "/fields/System.Title", "value": "GHSA-vh95-rmgr-6w4m in minimist (npm)"},
+		{"op": "add", "path": "/fields/System.Description", "value": "Prototype Pollution"},
+		{"op": "add", "path": "/fields/System.Tags", "value": "osv-scanner; security"},
+	}
+
+	if diff := cmp.Diff(want, gotOperations); diff != "" {
+		t.Errorf("unexpected operations (-want +got):\n%s", diff)
+	}
+}
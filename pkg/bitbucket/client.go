@@ -0,0 +1,64 @@
+package bitbucket
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/rest"
+)
+
+// DefaultBaseURL is the URL of the Bitbucket Cloud REST API.
+const DefaultBaseURL = "https://api.bitbucket.org"
+
+// Client is a minimal client for the Code Insights API of Bitbucket Cloud and
+// Bitbucket Server (Data Center)
+type Client struct {
+	BaseURL string
+	// Server is true when BaseURL points at a Bitbucket Server instance,
+	// which has a different API to Bitbucket Cloud
+	Server     bool
+	Token      string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a client for the given Bitbucket Server instance, or for
+// Bitbucket Cloud if serverURL is empty, authenticated with the token in the
+// BITBUCKET_TOKEN environment variable (if any)
+func NewClient(serverURL string) *Client {
+	client := &Client{
+		BaseURL:    DefaultBaseURL,
+		Token:      os.Getenv("BITBUCKET_TOKEN"),
+		HTTPClient: rest.HTTPClient,
+	}
+
+	if serverURL != "" {
+		client.BaseURL = serverURL
+		client.Server = true
+	}
+
+	return client
+}
+
+// SplitRepository splits a "workspace/repo" (or "PROJECT/repo" for Bitbucket
+// Server) string into its two parts
+func SplitRepository(repository string) (string, string, error) {
+	return rest.SplitRepository(repository, "workspace/repo")
+}
+
+// do sends a request to the given API path, encoding `body` as JSON
+func (c *Client) do(ctx context.Context, method string, path string, body any) error {
+	header := http.Header{}
+	if c.Token != "" {
+		header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	return rest.Do(ctx, c.HTTPClient, "bitbucket", rest.Request{
+		Method: method,
+		URL:    strings.TrimSuffix(c.BaseURL, "/") + path,
+		Path:   path,
+		Header: header,
+		Body:   body,
+	}, nil)
+}
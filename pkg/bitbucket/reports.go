@@ -0,0 +1,135 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// maxAnnotationsPerRequest is the most annotations that Bitbucket accepts in
+// a single request
+const maxAnnotationsPerRequest = 100
+
+// Report is a Code Insights report attached to a commit
+type Report struct {
+	Title   string
+	Details string
+	Passed  bool
+}
+
+// Annotation marks a finding on a specific file (and optionally line) of a report
+type Annotation struct {
+	ExternalID string
+	Path       string
+	// Line is 1-based, with 0 meaning the annotation applies to the whole file
+	Line    int
+	Summary string
+	// Severity is one of LOW, MEDIUM, HIGH or CRITICAL
+	Severity string
+	Link     string
+}
+
+// PublishReport creates or replaces the report with the given id on a commit,
+// along with its annotations
+func (c *Client) PublishReport(ctx context.Context, owner string, repo string, commit string, reportID string, report Report, annotations []Annotation) error {
+	reportPath := c.reportPath(owner, repo, commit, reportID)
+
+	if err := c.do(ctx, http.MethodPut, reportPath, c.reportPayload(report)); err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+
+	for start := 0; start < len(annotations); start += maxAnnotationsPerRequest {
+		end := start + maxAnnotationsPerRequest
+		if end > len(annotations) {
+			end = len(annotations)
+		}
+
+		if err := c.do(ctx, http.MethodPost, reportPath+"/annotations", c.annotationsPayload(annotations[start:end])); err != nil {
+			return fmt.Errorf("failed to add annotations: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) reportPath(owner string, repo string, commit string, reportID string) string {
+	if c.Server {
+		return fmt.Sprintf(
+			"/rest/insights/1.0/projects/%s/repos/%s/commits/%s/reports/%s",
+			url.PathEscape(owner), url.PathEscape(repo), url.PathEscape(commit), url.PathEscape(reportID),
+		)
+	}
+
+	return fmt.Sprintf(
+		"/2.0/repositories/%s/%s/commit/%s/reports/%s",
+		url.PathEscape(owner), url.PathEscape(repo), url.PathEscape(commit), url.PathEscape(reportID),
+	)
+}
+
+func (c *Client) reportPayload(report Report) map[string]any {
+	if c.Server {
+		result := "FAIL"
+		if report.Passed {
+			result = "PASS"
+		}
+
+		return map[string]any{
+			"title":    report.Title,
+			"details":  report.Details,
+			"reporter": "OSV-Scanner",
+			"result":   result,
+		}
+	}
+
+	result := "FAILED"
+	if report.Passed {
+		result = "PASSED"
+	}
+
+	return map[string]any{
+		"title":       report.Title,
+		"details":     report.Details,
+		"reporter":    "OSV-Scanner",
+		"report_type": "SECURITY",
+		"result":      result,
+	}
+}
+
+func (c *Client) annotationsPayload(annotations []Annotation) any {
+	payload := make([]map[string]any, 0, len(annotations))
+
+	for _, annotation := range annotations {
+		item := map[string]any{
+			"path":     annotation.Path,
+			"severity": annotation.Severity,
+			"link":     annotation.Link,
+		}
+
+		if annotation.Line > 0 {
+			item["line"] = annotation.Line
+		}
+
+		if c.Server {
+			// Bitbucket Server does not support critical annotations
+			if annotation.Severity == "CRITICAL" {
+				item["severity"] = "HIGH"
+			}
+			item["externalId"] = annotation.ExternalID
+			item["message"] = annotation.Summary
+			item["type"] = "VULNERABILITY"
+		} else {
+			item["external_id"] = annotation.ExternalID
+			item["summary"] = annotation.Summary
+			item["annotation_type"] = "VULNERABILITY"
+		}
+
+		payload = append(payload, item)
+	}
+
+	if c.Server {
+		return map[string]any{"annotations": payload}
+	}
+
+	return payload
+}
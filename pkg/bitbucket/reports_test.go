@@ -0,0 +1,116 @@
+package bitbucket_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/bitbucket"
+)
+
+type request struct {
+	Method string
+	Path   string
+	Body   any
+}
+
+func TestClient_PublishReport(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		server         bool
+		expectedPath   string
+		expectedResult string
+	}{
+		{
+			name:           "cloud",
+			server:         false,
+			expectedPath:   "/2.0/repositories/workspace/repo/commit/abc123/reports/osv-scanner",
+			expectedResult: "FAILED",
+		},
+		{
+			name:           "server",
+			server:         true,
+			expectedPath:   "/rest/insights/1.0/projects/PROJ/repos/repo/commits/abc123/reports/osv-scanner",
+			expectedResult: "FAIL",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var mu sync.Mutex
+			var requests []request
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body any
+				_ = json.NewDecoder(r.Body).Decode(&body)
+
+				mu.Lock()
+				requests = append(requests, request{Method: r.Method, Path: r.URL.Path, Body: body})
+				mu.Unlock()
+			}))
+			defer server.Close()
+
+			client := &bitbucket.Client{BaseURL: server.URL, Server: tt.server}
+			owner := "workspace"
+			if tt.server {
+				owner = "PROJ"
+			}
+
+			annotations := make([]bitbucket.Annotation, 150)
+			for i := range annotations {
+				annotations[i] = bitbucket.Annotation{ExternalID: "id", Path: "package-lock.json", Severity: "CRITICAL"}
+			}
+
+			err := client.PublishReport(context.Background(), owner, "repo", "abc123", "osv-scanner", bitbucket.Report{Title: "OSV-Scanner"}, annotations)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// the report, followed by two batches of annotations
+			if len(requests) != 3 {
+				t.Fatalf("expected 3 requests, got %d", len(requests))
+			}
+
+			if requests[0].Method != http.MethodPut || requests[0].Path != tt.expectedPath {
+				t.Errorf("expected report to be PUT to %s, got %s %s", tt.expectedPath, requests[0].Method, requests[0].Path)
+			}
+
+			if result := requests[0].Body.(map[string]any)["result"]; result != tt.expectedResult {
+				t.Errorf("expected result to be %s, got %v", tt.expectedResult, result)
+			}
+
+			for _, req := range requests[1:] {
+				if req.Method != http.MethodPost || req.Path != tt.expectedPath+"/annotations" {
+					t.Errorf("expected annotations to be POSTed to %s/annotations, got %s %s", tt.expectedPath, req.Method, req.Path)
+				}
+			}
+
+			var batch []any
+			if tt.server {
+				batch = requests[2].Body.(map[string]any)["annotations"].([]any)
+			} else {
+				batch = requests[2].Body.([]any)
+			}
+
+			if len(batch) != 50 {
+				t.Errorf("expected the last batch to have 50 annotations, got %d", len(batch))
+			}
+
+			severity := batch[0].(map[string]any)["severity"]
+			if tt.server && severity != "HIGH" {
+				t.Errorf("expected critical annotations to be downgraded to HIGH, got %v", severity)
+			}
+			if !tt.server && severity != "CRITICAL" {
+				t.Errorf("expected critical annotations to stay CRITICAL, got %v", severity)
+			}
+		})
+	}
+}
@@ -1,18 +1,33 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/google/osv-scanner/pkg/output"
 
-	"github.com/BurntSushi/toml"
 	"golang.org/x/exp/slices"
 )
 
-const osvScannerConfigName = "osv-scanner.toml"
+// osvScannerConfigNames are the config file names that are looked for
+// alongside manifests, in order of precedence
+var osvScannerConfigNames = []string{
+	"osv-scanner.toml",
+	"osv-scanner.yaml",
+	"osv-scanner.yml",
+	"osv-scanner.json",
+}
+
+var errNoConfigFound = errors.New("no config file found on this path")
+
+// IsConfigFileName reports if files with the given name are read as config files
+func IsConfigFileName(name string) bool {
+	return slices.Contains(osvScannerConfigNames, name)
+}
 
 type ConfigManager struct {
 	// Override to replace all other configs
@@ -24,17 +39,43 @@ type ConfigManager struct {
 }
 
 type Config struct {
-	IgnoredVulns []IgnoreEntry `toml:"IgnoredVulns"`
-	LoadPath     string        `toml:"LoadPath"`
+	IgnoredVulns []IgnoreEntry `toml:"IgnoredVulns" yaml:"IgnoredVulns" json:"IgnoredVulns"`
+	// InternalPackages are the prefixes of the names of packages that are only
+	// published to private registries, such as "@acme" for an npm scope
+	InternalPackages []string `toml:"InternalPackages" yaml:"InternalPackages" json:"InternalPackages"`
+	// IgnoredPaths suppress the vulnerabilities of packages that are only
+	// declared in files under certain paths
+	IgnoredPaths []IgnorePathEntry `toml:"IgnoredPaths" yaml:"IgnoredPaths" json:"IgnoredPaths"`
+	// SeverityOverrides replace the severities of specific vulnerabilities
+	SeverityOverrides []SeverityOverride `toml:"SeverityOverrides" yaml:"SeverityOverrides" json:"SeverityOverrides"`
+	// Scoring adjusts the scores of vulnerabilities by how they affect the
+	// project, such as by being in a development dependency
+	Scoring Scoring `toml:"Scoring" yaml:"Scoring" json:"Scoring"`
+	// FailOnSeverity only fails the scan for the vulnerabilities of the
+	// project that are at least this severe, in place of --fail-on-severity
+	FailOnSeverity string `toml:"FailOnSeverity" yaml:"FailOnSeverity" json:"FailOnSeverity"`
+	// Licenses restricts which licenses packages may be under, when licenses
+	// are checked
+	Licenses LicensePolicy `toml:"Licenses" yaml:"Licenses" json:"Licenses"`
+	// ParseAs parses files that are not named like any lockfile as one when
+	// they are found while scanning a directory
+	ParseAs  []ParseAsEntry `toml:"ParseAs" yaml:"ParseAs" json:"ParseAs"`
+	LoadPath string         `toml:"LoadPath" yaml:"-" json:"-"`
 }
 
 type IgnoreEntry struct {
-	ID          string    `toml:"id"`
-	IgnoreUntil time.Time `toml:"ignoreUntil"`
-	Reason      string    `toml:"reason"`
+	ID          string    `toml:"id" yaml:"id" json:"id"`
+	IgnoreUntil time.Time `toml:"ignoreUntil" yaml:"ignoreUntil" json:"ignoreUntil"`
+	Reason      string    `toml:"reason" yaml:"reason" json:"reason"`
 }
 
 func (c *Config) ShouldIgnore(vulnID string) (bool, IgnoreEntry) {
+	return c.ShouldIgnoreAt(vulnID, time.Now())
+}
+
+// ShouldIgnoreAt is like ShouldIgnore, but checks if the ignore has expired as
+// of the given time rather than the current time
+func (c *Config) ShouldIgnoreAt(vulnID string, now time.Time) (bool, IgnoreEntry) {
 	index := slices.IndexFunc(c.IgnoredVulns, func(elem IgnoreEntry) bool { return elem.ID == vulnID })
 	if index == -1 {
 		return false, IgnoreEntry{}
@@ -46,17 +87,43 @@ func (c *Config) ShouldIgnore(vulnID string) (bool, IgnoreEntry) {
 	}
 	// Should ignore if IgnoreUntil is still after current time
 	// Takes timezone offsets into account if it is specified. otherwise it's using local time
-	return ignoredLine.IgnoreUntil.After(time.Now()), ignoredLine
+	return ignoredLine.IgnoreUntil.After(now), ignoredLine
+}
+
+// IsInternalPackage reports if the named package is one of the internal
+// packages, by starting with one of their prefixes regardless of case. An npm
+// scope such as "@acme" only matches the packages within that scope.
+func (c *Config) IsInternalPackage(name string) bool {
+	name = strings.ToLower(name)
+
+	for _, prefix := range c.InternalPackages {
+		prefix = strings.ToLower(prefix)
+		if strings.HasPrefix(prefix, "@") && !strings.Contains(prefix, "/") {
+			prefix += "/"
+		}
+
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
 }
 
 // Sets the override config by reading the config file at configPath.
-// Will return an error if loading the config file fails
-func (c *ConfigManager) UseOverride(configPath string) error {
+// Will return an error if loading the config file fails, and reports any
+// problems that only affect part of it as warnings
+func (c *ConfigManager) UseOverride(r output.Reporter, configPath string) error {
 	config := Config{}
-	_, err := toml.DecodeFile(configPath, &config)
+	content, err := os.ReadFile(configPath)
 	if err != nil {
 		return err
 	}
+	warnings, err := decodeConfig(configPath, content, &config)
+	if err != nil {
+		return err
+	}
+	printWarnings(r, warnings)
 	config.LoadPath = configPath
 	c.OverrideConfig = &config
 
@@ -64,7 +131,7 @@ func (c *ConfigManager) UseOverride(configPath string) error {
 }
 
 // Attempts to get the config
-func (c *ConfigManager) Get(r *output.Reporter, targetPath string) Config {
+func (c *ConfigManager) Get(r output.Reporter, targetPath string) Config {
 	if c.OverrideConfig != nil {
 		return *c.OverrideConfig
 	}
@@ -82,11 +149,15 @@ func (c *ConfigManager) Get(r *output.Reporter, targetPath string) Config {
 		return config
 	}
 
-	config, configErr := tryLoadConfig(configPath)
+	config, warnings, configErr := tryLoadConfig(configPath)
 	if configErr == nil {
 		r.PrintText(fmt.Sprintf("Loaded filter from: %s\n", config.LoadPath))
+		printWarnings(r, warnings)
 	} else {
-		// If config doesn't exist, use the default config
+		if !errors.Is(configErr, errNoConfigFound) {
+			r.PrintError(fmt.Sprintf("Ignoring invalid config, %v\n", configErr))
+		}
+		// If config doesn't exist or is invalid, use the default config
 		config = c.DefaultConfig
 	}
 	c.ConfigMap[configPath] = config
@@ -94,7 +165,15 @@ func (c *ConfigManager) Get(r *output.Reporter, targetPath string) Config {
 	return config
 }
 
-// Finds the containing folder of `target`, then appends osvScannerConfigName
+// printWarnings reports the problems found in a config that was still loaded
+func printWarnings(r output.Reporter, warnings ValidationErrors) {
+	for _, warning := range warnings {
+		r.PrintError(fmt.Sprintf("Warning: %v\n", warning))
+	}
+}
+
+// Finds the containing folder of `target`, then appends the name of the first
+// config file that exists in it, defaulting to osv-scanner.toml
 func normalizeConfigLoadPath(target string) (string, error) {
 	stat, err := os.Stat(target)
 	if err != nil {
@@ -107,27 +186,85 @@ func normalizeConfigLoadPath(target string) (string, error) {
 	} else {
 		containingFolder = target
 	}
-	configPath := filepath.Join(containingFolder, osvScannerConfigName)
+	for _, name := range osvScannerConfigNames {
+		configPath := filepath.Join(containingFolder, name)
+		if _, err := os.Stat(configPath); err == nil {
+			return configPath, nil
+		}
+	}
+
+	return filepath.Join(containingFolder, osvScannerConfigNames[0]), nil
+}
+
+// configFormat returns the format that the config file at `configPath` is
+// decoded as based on its extension, being TOML for anything that is not
+// YAML or JSON
+func configFormat(configPath string) string {
+	switch filepath.Ext(configPath) {
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".json":
+		return "json"
+	default:
+		return "toml"
+	}
+}
+
+// decodeConfig decodes the content of the config file at `configPath` based
+// on its format.
+//
+// Unknown keys and vulnerabilities that are ignored more than once are returned
+// as warnings, keeping the rest of the config, while syntax errors, values of
+// the wrong type and invalid entries are returned as ValidationErrors
+func decodeConfig(configPath string, content []byte, config *Config) (ValidationErrors, error) {
+	var warnings, errs ValidationErrors
+
+	format := configFormat(configPath)
+	isTOML := format == "toml"
+
+	switch format {
+	case "yaml":
+		warnings, errs = validateYAML(configPath, content, config)
+	case "json":
+		warnings, errs = validateJSON(configPath, content, config)
+	default:
+		warnings, errs = validateTOML(configPath, content, config)
+	}
 
-	return configPath, nil
+	if len(errs) == 0 {
+		var duplicates ValidationErrors
+		duplicates, errs = validateIgnoredVulns(configPath, content, config, isTOML)
+		warnings = append(warnings, duplicates...)
+		errs = append(errs, validateInternalPackages(configPath, content, config)...)
+		errs = append(errs, validateIgnoredPaths(configPath, content, config, isTOML)...)
+		errs = append(errs, validateSeverityOverrides(configPath, content, config, isTOML)...)
+		errs = append(errs, validateScoring(configPath, content, config)...)
+		errs = append(errs, validateFailOnSeverity(configPath, content, config)...)
+		errs = append(errs, validateLicenses(configPath, content, config)...)
+		errs = append(errs, validateParseAs(configPath, content, config, isTOML)...)
+	}
+
+	if len(errs) > 0 {
+		return warnings, errs
+	}
+
+	return warnings, nil
 }
 
 // tryLoadConfig tries to load config in `target` (or it's containing directory)
 // `target` will be the key for the entry in configMap
-func tryLoadConfig(configPath string) (Config, error) {
-	file, err := os.Open(configPath)
+func tryLoadConfig(configPath string) (Config, ValidationErrors, error) {
+	content, err := os.ReadFile(configPath)
 	var config Config
 	if err == nil { // File exists, and we have permission to read
-		defer file.Close()
-
-		_, err := toml.NewDecoder(file).Decode(&config)
+		warnings, err := decodeConfig(configPath, content, &config)
 		if err != nil {
-			return Config{}, fmt.Errorf("failed to parse config file: %w", err)
+			return Config{}, warnings, fmt.Errorf("failed to parse config file: %w", err)
 		}
 		config.LoadPath = configPath
 
-		return config, nil
+		return config, warnings, nil
 	}
 
-	return Config{}, fmt.Errorf("no config file found on this path: %s", configPath)
+	return Config{}, nil, fmt.Errorf("%w: %s", errNoConfigFound, configPath)
 }
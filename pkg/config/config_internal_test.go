@@ -3,8 +3,10 @@ package config
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/output"
 )
 
 type testStruct struct {
@@ -63,7 +65,7 @@ func TestTryLoadConfig(t *testing.T) {
 		if err != nil {
 			t.Errorf("%s", err)
 		}
-		config, configErr := tryLoadConfig(configPath)
+		config, _, configErr := tryLoadConfig(configPath)
 		if !cmp.Equal(config.IgnoredVulns, testData.config.IgnoredVulns) {
 			t.Errorf("Configs not equal: %+v != %+v", config, testData.config)
 		}
@@ -74,3 +76,104 @@ func TestTryLoadConfig(t *testing.T) {
 		}
 	}
 }
+
+func TestTryLoadConfig_OtherFormats(t *testing.T) {
+	t.Parallel()
+
+	expectedConfig := Config{
+		IgnoredVulns: []IgnoreEntry{
+			{
+				ID:          "GO-2022-0968",
+				IgnoreUntil: time.Date(2022, 11, 9, 0, 0, 0, 0, time.UTC),
+				Reason:      "No ssh servers are connected to or hosted in Go lang",
+			},
+			{
+				ID: "GO-2022-1059",
+			},
+		},
+	}
+
+	for _, targetPath := range []string{
+		"../../fixtures/testdatainner-yaml/",
+		"../../fixtures/testdatainner-json/",
+	} {
+		absPath, err := filepath.Abs(targetPath)
+		if err != nil {
+			t.Errorf("%s", err)
+		}
+		configPath, err := normalizeConfigLoadPath(absPath)
+		if err != nil {
+			t.Errorf("%s", err)
+		}
+		config, _, configErr := tryLoadConfig(configPath)
+		if configErr != nil {
+			t.Errorf("Unexpected config error for %s: %s", targetPath, configErr)
+		}
+		if !cmp.Equal(config.IgnoredVulns, expectedConfig.IgnoredVulns) {
+			t.Errorf("Configs not equal: %+v != %+v", config, expectedConfig)
+		}
+	}
+}
+
+func TestUseOverride_YAML(t *testing.T) {
+	t.Parallel()
+
+	configManager := ConfigManager{ConfigMap: make(map[string]Config)}
+
+	err := configManager.UseOverride(output.NewVoidReporter(), "../../fixtures/testdatainner-yaml/osv-scanner.yaml")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if len(configManager.OverrideConfig.IgnoredVulns) != 2 {
+		t.Errorf("Expected 2 ignored vulns, got %d", len(configManager.OverrideConfig.IgnoredVulns))
+	}
+}
+
+func TestShouldIgnoreAt(t *testing.T) {
+	t.Parallel()
+
+	until := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	config := Config{IgnoredVulns: []IgnoreEntry{
+		{ID: "GO-2022-0968", IgnoreUntil: until, Reason: "No ssh servers"},
+		{ID: "GO-2022-1059"},
+	}}
+
+	if ignore, _ := config.ShouldIgnoreAt("GO-2022-0968", until.Add(-time.Hour)); !ignore {
+		t.Errorf("expected GO-2022-0968 to be ignored before it expires")
+	}
+	if ignore, _ := config.ShouldIgnoreAt("GO-2022-0968", until.Add(time.Hour)); ignore {
+		t.Errorf("expected GO-2022-0968 to not be ignored after it expires")
+	}
+	if ignore, _ := config.ShouldIgnoreAt("GO-2022-1059", until.Add(time.Hour)); !ignore {
+		t.Errorf("expected GO-2022-1059 to be ignored as it does not expire")
+	}
+	if ignore, _ := config.ShouldIgnoreAt("GHSA-xxxx-xxxx-xxxx", until); ignore {
+		t.Errorf("expected GHSA-xxxx-xxxx-xxxx to not be ignored")
+	}
+}
+
+func TestIsInternalPackage(t *testing.T) {
+	t.Parallel()
+
+	config := Config{InternalPackages: []string{"@acme", "acme-", "com.acme:"}}
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "@acme/utils", want: true},
+		{name: "@Acme/Utils", want: true},
+		{name: "@acme-corp/utils", want: false},
+		{name: "acme-utils", want: true},
+		{name: "com.acme:billing", want: true},
+		{name: "com.acmecorp:billing", want: false},
+		{name: "lodash", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := config.IsInternalPackage(tt.name); got != tt.want {
+			t.Errorf("IsInternalPackage(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// inlineIgnoreRegexp matches suppression comments such as
+//
+//	# osv-scanner:ignore GHSA-xxxx-xxxx-xxxx reason=not exploitable
+//	// osv-scanner:ignore GO-2022-0968 reason="no ssh servers"
+var inlineIgnoreRegexp = regexp.MustCompile(`(?:#|//)\s*osv-scanner:ignore\s+(\S+)(?:\s+reason=(.*))?`)
+
+// commentOnlyRegexp matches lines that are nothing but a comment
+var commentOnlyRegexp = regexp.MustCompile(`^\s*(?:#|//)`)
+
+// InlineIgnore is an ignore declared by an `osv-scanner:ignore` comment
+type InlineIgnore struct {
+	IgnoreEntry
+	// Line is where the dependency that the comment applies to is declared,
+	// being the line the comment is on, or the next line that is not blank or
+	// a comment for a comment on a line of its own
+	Line int
+	// Declaration is the text of that line before any comment
+	Declaration string
+}
+
+// InlineIgnores are the inline ignores declared within a file
+type InlineIgnores []InlineIgnore
+
+// ParseInlineIgnores returns the ignores declared by `osv-scanner:ignore`
+// comments within the given manifest content, along with the declarations
+// that they apply to
+func ParseInlineIgnores(content []byte) InlineIgnores {
+	var ignores InlineIgnores
+	var pending []IgnoreEntry
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		matches := inlineIgnoreRegexp.FindStringSubmatchIndex(text)
+
+		if matches != nil {
+			reason := ""
+			if matches[4] != -1 {
+				reason = strings.Trim(strings.TrimSpace(text[matches[4]:matches[5]]), `"'`)
+			}
+			pending = append(pending, IgnoreEntry{ID: text[matches[2]:matches[3]], Reason: reason})
+			text = text[:matches[0]]
+		}
+
+		if strings.TrimSpace(text) == "" || commentOnlyRegexp.MatchString(text) {
+			continue
+		}
+
+		for _, e := range pending {
+			ignores = append(ignores, InlineIgnore{IgnoreEntry: e, Line: line, Declaration: text})
+		}
+		pending = nil
+	}
+
+	return ignores
+}
+
+// LoadInlineIgnores reads the manifest at the given path and returns the
+// ignores declared in its `osv-scanner:ignore` comments
+func LoadInlineIgnores(path string) (InlineIgnores, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseInlineIgnores(content), nil
+}
+
+// ForLine returns a config of the ignores that apply to the dependency that
+// is declared on the given line of the file at path
+func (ignores InlineIgnores) ForLine(path string, line int) Config {
+	config := Config{LoadPath: path}
+
+	for _, ignore := range ignores {
+		if ignore.Line == line {
+			config.IgnoredVulns = append(config.IgnoredVulns, ignore.IgnoreEntry)
+		}
+	}
+
+	return config
+}
+
+// ForPackage returns a config of the ignores that apply to a declaration of
+// the named package in the file at path
+func (ignores InlineIgnores) ForPackage(path string, name string) Config {
+	config := Config{LoadPath: path}
+
+	for _, ignore := range ignores {
+		if declaresPackage(ignore.Declaration, name) {
+			config.IgnoredVulns = append(config.IgnoredVulns, ignore.IgnoreEntry)
+		}
+	}
+
+	return config
+}
+
+// isPackageNameChar reports if the character can be part of a package name,
+// so that "flask" is not found within "flask-login"
+func isPackageNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || strings.IndexByte("._-/@", c) != -1
+}
+
+// declaresPackage reports if the named package is mentioned in the
+// declaration, regardless of case, as a whole name
+func declaresPackage(declaration string, name string) bool {
+	if name == "" {
+		return false
+	}
+
+	declaration = strings.ToLower(declaration)
+	name = strings.ToLower(name)
+
+	for offset := 0; ; {
+		i := strings.Index(declaration[offset:], name)
+		if i == -1 {
+			return false
+		}
+		start := offset + i
+		end := start + len(name)
+
+		if (start == 0 || !isPackageNameChar(declaration[start-1])) && (end == len(declaration) || !isPackageNameChar(declaration[end])) {
+			return true
+		}
+		offset = start + 1
+	}
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseInlineIgnores(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		content  string
+		expected InlineIgnores
+	}{
+		{
+			name:     "no comments",
+			content:  "flask==1.0\njinja2==2.4.1\n",
+			expected: nil,
+		},
+		{
+			name:    "hash comments",
+			content: "flask==1.0 # osv-scanner:ignore PYSEC-2019-179 reason=we do not use the json module\n# osv-scanner:ignore GHSA-m2qf-hxjv-5gpq\n",
+			expected: InlineIgnores{
+				{IgnoreEntry: IgnoreEntry{ID: "PYSEC-2019-179", Reason: "we do not use the json module"}, Line: 1, Declaration: "flask==1.0 "},
+			},
+		},
+		{
+			name:    "comments on lines of their own",
+			content: "# osv-scanner:ignore GHSA-m2qf-hxjv-5gpq\n\n# pinned for python 2\n# osv-scanner:ignore PYSEC-2014-8 reason=\"not exposed\"\njinja2==2.4.1\nflask==1.0\n",
+			expected: InlineIgnores{
+				{IgnoreEntry: IgnoreEntry{ID: "GHSA-m2qf-hxjv-5gpq"}, Line: 5, Declaration: "jinja2==2.4.1"},
+				{IgnoreEntry: IgnoreEntry{ID: "PYSEC-2014-8", Reason: "not exposed"}, Line: 5, Declaration: "jinja2==2.4.1"},
+			},
+		},
+		{
+			name:    "slash comments",
+			content: "require golang.org/x/crypto v0.0.0 // osv-scanner:ignore GO-2022-0968 reason=\"No ssh servers\"\n",
+			expected: InlineIgnores{
+				{IgnoreEntry: IgnoreEntry{ID: "GO-2022-0968", Reason: "No ssh servers"}, Line: 1, Declaration: "require golang.org/x/crypto v0.0.0 "},
+			},
+		},
+		{
+			name:     "not a suppression",
+			content:  "# osv-scanner is great\n# ignore GO-2022-0968\n",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := ParseInlineIgnores([]byte(tt.content))
+			if diff := cmp.Diff(tt.expected, got); diff != "" {
+				t.Errorf("ParseInlineIgnores() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInlineIgnores_For(t *testing.T) {
+	t.Parallel()
+
+	ignores := ParseInlineIgnores([]byte(`source "https://rubygems.org"
+
+gem "rails", "~> 7.0" # osv-scanner:ignore GHSA-1 reason=not exposed
+# osv-scanner:ignore GHSA-2
+gem "rails-html-sanitizer"
+gem "nokogiri"
+`))
+
+	tests := []struct {
+		name     string
+		got      Config
+		expected []IgnoreEntry
+	}{
+		{
+			name:     "line of the comment",
+			got:      ignores.ForLine("Gemfile", 3),
+			expected: []IgnoreEntry{{ID: "GHSA-1", Reason: "not exposed"}},
+		},
+		{
+			name:     "line after the comment",
+			got:      ignores.ForLine("Gemfile", 5),
+			expected: []IgnoreEntry{{ID: "GHSA-2"}},
+		},
+		{
+			name:     "line without a comment",
+			got:      ignores.ForLine("Gemfile", 6),
+			expected: nil,
+		},
+		{
+			name:     "package named on the line",
+			got:      ignores.ForPackage("Gemfile", "rails"),
+			expected: []IgnoreEntry{{ID: "GHSA-1", Reason: "not exposed"}},
+		},
+		{
+			name:     "package named on the next line",
+			got:      ignores.ForPackage("Gemfile", "Rails-HTML-Sanitizer"),
+			expected: []IgnoreEntry{{ID: "GHSA-2"}},
+		},
+		{
+			name:     "package not named",
+			got:      ignores.ForPackage("Gemfile", "nokogiri"),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if tt.got.LoadPath != "Gemfile" {
+				t.Errorf("expected the config to be loaded from the Gemfile, got %q", tt.got.LoadPath)
+			}
+			if diff := cmp.Diff(tt.expected, tt.got.IgnoredVulns); diff != "" {
+				t.Errorf("IgnoredVulns mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
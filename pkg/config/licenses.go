@@ -0,0 +1,28 @@
+package config
+
+import (
+	"github.com/google/osv-scanner/pkg/licenses"
+)
+
+// LicensePolicy decides which licenses the packages of the project may be
+// under, listing SPDX identifiers such as "MIT", or a license with a specific
+// exception such as "GPL-2.0-only WITH Classpath-exception-2.0"
+type LicensePolicy struct {
+	// Allow are the only licenses that packages may be under, if any are
+	// listed
+	Allow []string `toml:"allow" yaml:"allow" json:"allow"`
+	// Deny are the licenses that packages may not be under, which take
+	// precedence over those that are allowed
+	Deny []string `toml:"deny" yaml:"deny" json:"deny"`
+}
+
+// HasLicensePolicy reports if the config restricts the licenses of packages
+func (c *Config) HasLicensePolicy() bool {
+	return len(c.Licenses.Allow) > 0 || len(c.Licenses.Deny) > 0
+}
+
+// LicensePolicy returns the policy that the licenses of packages are checked
+// against, which allows every license if the config has no policy
+func (c *Config) LicensePolicy() (licenses.Policy, error) {
+	return licenses.NewPolicy(c.Licenses.Allow, c.Licenses.Deny)
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"golang.org/x/exp/slices"
+)
+
+// ParseAsEntry parses the files under a path as a lockfile that they would
+// not be recognised as by their name, such as a requirements.txt file that
+// has been renamed
+type ParseAsEntry struct {
+	// Path is a pattern with the same syntax as a .gitignore file, relative
+	// to the directory of the config file, such as "requirements-*.txt"
+	Path string `toml:"path" yaml:"path" json:"path"`
+	// Parser is the name of the lockfile to parse the files as, being one of
+	// lockfile.ListParsers
+	Parser string `toml:"parser" yaml:"parser" json:"parser"`
+}
+
+// ParsersFor returns the parsers that the file at the given path could be
+// parsed with, being those of every ParseAs entry that matches it, so that
+// the one that best matches the file can be chosen
+func (c *Config) ParsersFor(file string) []string {
+	if len(c.ParseAs) == 0 || file == "" {
+		return nil
+	}
+
+	return MatchParseAs(c.ParseAs, c.dir(), file)
+}
+
+// MatchParseAs returns the parsers of the entries whose paths, relative to
+// dir, match the file at the given path, in the order of the entries without
+// repeating any parser
+func MatchParseAs(entries []ParseAsEntry, dir string, file string) []string {
+	parts, ok := relativeParts(dir, file)
+	if !ok {
+		return nil
+	}
+
+	var parsers []string
+	for _, entry := range entries {
+		if gitignore.ParsePattern(entry.Path, nil).Match(parts, false) == gitignore.Exclude && !slices.Contains(parsers, entry.Parser) {
+			parsers = append(parsers, entry.Parser)
+		}
+	}
+
+	return parsers
+}
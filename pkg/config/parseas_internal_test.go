@@ -0,0 +1,48 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParsersFor(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	config := Config{
+		LoadPath: filepath.Join(root, "osv-scanner.toml"),
+		ParseAs: []ParseAsEntry{
+			{Path: "renamed-requirements.txt", Parser: "requirements.txt"},
+			{Path: "/locks/*.lock", Parser: "Cargo.lock"},
+			{Path: "*.lock", Parser: "yarn.lock"},
+			{Path: "locks/", Parser: "Cargo.lock"},
+		},
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{path: "renamed-requirements.txt", want: []string{"requirements.txt"}},
+		{path: "api/renamed-requirements.txt", want: []string{"requirements.txt"}},
+		{path: "locks/app.lock", want: []string{"Cargo.lock", "yarn.lock"}},
+		{path: "web/locks/app.lock", want: []string{"yarn.lock", "Cargo.lock"}},
+		{path: "requirements.txt", want: nil},
+		{path: "../renamed-requirements.txt", want: nil},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			got := config.ParsersFor(filepath.Join(root, filepath.FromSlash(tt.path)))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParsersFor() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
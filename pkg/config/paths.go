@@ -0,0 +1,85 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// IgnorePathEntry suppresses the vulnerabilities of packages that are declared
+// in files under a path, such as examples and test fixtures that are never
+// deployed
+type IgnorePathEntry struct {
+	// Path is a pattern with the same syntax as a .gitignore file, relative
+	// to the directory of the config file, such as "examples/" or "**/testdata/"
+	Path        string    `toml:"path" yaml:"path" json:"path"`
+	IgnoreUntil time.Time `toml:"ignoreUntil" yaml:"ignoreUntil" json:"ignoreUntil"`
+	Reason      string    `toml:"reason" yaml:"reason" json:"reason"`
+}
+
+// ShouldIgnorePathAt reports if the vulnerabilities of packages declared in
+// the file at the given path should be ignored as of the given time, by the
+// path being matched by one of the ignored paths that has not expired
+func (c *Config) ShouldIgnorePathAt(declaringFile string, now time.Time) (bool, IgnorePathEntry) {
+	for _, entry := range c.IgnoredPathsMatching(declaringFile) {
+		if entry.IgnoreUntil.IsZero() || entry.IgnoreUntil.After(now) {
+			return true, entry
+		}
+	}
+
+	return false, IgnorePathEntry{}
+}
+
+// IgnoredPathsMatching returns the ignored paths that match the file at the
+// given path, including those that have expired
+func (c *Config) IgnoredPathsMatching(declaringFile string) []IgnorePathEntry {
+	if len(c.IgnoredPaths) == 0 || declaringFile == "" {
+		return nil
+	}
+
+	parts, ok := relativeParts(c.dir(), declaringFile)
+	if !ok {
+		// paths are only ignored within the directory of the config
+		return nil
+	}
+
+	var matching []IgnorePathEntry
+	for _, entry := range c.IgnoredPaths {
+		if gitignore.ParsePattern(entry.Path, nil).Match(parts, false) == gitignore.Exclude {
+			matching = append(matching, entry)
+		}
+	}
+
+	return matching
+}
+
+// dir returns the directory that the paths of the config are relative to
+func (c *Config) dir() string {
+	if c.LoadPath == "" {
+		return "."
+	}
+
+	return filepath.Dir(c.LoadPath)
+}
+
+// relativeParts splits the path of the file relative to dir into the parts
+// that patterns are matched against, reporting false if it is not within dir
+func relativeParts(dir string, file string) ([]string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, false
+	}
+	file, err = filepath.Abs(file)
+	if err != nil {
+		return nil, false
+	}
+
+	rel, err := filepath.Rel(dir, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, false
+	}
+
+	return strings.Split(filepath.ToSlash(rel), "/"), true
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestShouldIgnorePathAt(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	now := time.Now()
+
+	config := Config{
+		LoadPath: filepath.Join(root, "osv-scanner.toml"),
+		IgnoredPaths: []IgnorePathEntry{
+			{Path: "examples/", Reason: "examples are not deployed"},
+			{Path: "**/testdata/", Reason: "test fixtures"},
+			{Path: "/docs/*.lock", Reason: "docs"},
+			{Path: "legacy/", Reason: "expired", IgnoreUntil: now.Add(-time.Hour)},
+			{Path: "vendor/", Reason: "not yet expired", IgnoreUntil: now.Add(time.Hour)},
+		},
+	}
+
+	tests := []struct {
+		path   string
+		reason string
+	}{
+		{path: "examples/app/package-lock.json", reason: "examples are not deployed"},
+		{path: "web/examples/package-lock.json", reason: "examples are not deployed"},
+		{path: "pkg/parser/testdata/go.mod", reason: "test fixtures"},
+		{path: "docs/yarn.lock", reason: "docs"},
+		{path: "web/docs/yarn.lock", reason: ""},
+		{path: "legacy/package-lock.json", reason: ""},
+		{path: "vendor/package-lock.json", reason: "not yet expired"},
+		{path: "package-lock.json", reason: ""},
+		{path: "../examples/package-lock.json", reason: ""},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			ignore, entry := config.ShouldIgnorePathAt(filepath.Join(root, filepath.FromSlash(tt.path)), now)
+
+			if ignore != (tt.reason != "") || entry.Reason != tt.reason {
+				t.Errorf("ShouldIgnorePathAt() = %v, %q, want %q", ignore, entry.Reason, tt.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"math"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+
+	"golang.org/x/exp/slices"
+)
+
+// SeverityOverride replaces the severity of a vulnerability, such as when it
+// is known to be more or less severe for the project than its advisory says
+type SeverityOverride struct {
+	ID string `toml:"id" yaml:"id" json:"id"`
+	// Severity is one of "LOW", "MEDIUM", "HIGH" or "CRITICAL", and is
+	// otherwise worked out from Score
+	Severity string `toml:"severity" yaml:"severity" json:"severity"`
+	// Score is out of 10, and otherwise represents Severity
+	Score  float64 `toml:"score" yaml:"score" json:"score"`
+	Reason string  `toml:"reason" yaml:"reason" json:"reason"`
+}
+
+// Scoring multiplies the scores of vulnerabilities by how they affect the
+// project, with the adjusted scores being used for thresholds and sorting
+type Scoring struct {
+	// Multipliers are keyed by the condition that a vulnerability or its
+	// package has to meet for its score to be multiplied by them, being one
+	// of ScoringConditions
+	Multipliers map[string]float64 `toml:"multipliers" yaml:"multipliers" json:"multipliers"`
+	// KnownExploited are the IDs of vulnerabilities that are known to be
+	// exploited, such as those in CISA's KEV catalog
+	KnownExploited []string `toml:"knownExploited" yaml:"knownExploited" json:"knownExploited"`
+}
+
+// ScoringConditions are the conditions that scores can be multiplied by
+var ScoringConditions = []string{
+	// the scope of the package
+	string(lockfile.ScopeProduction), string(lockfile.ScopeDevelopment), string(lockfile.ScopeOptional),
+	// the relation of the package
+	string(lockfile.RelationDirect), string(lockfile.RelationTransitive),
+	// the vulnerability is one of KnownExploited
+	"known-exploited",
+	// the advisories of the vulnerability link to a public exploit or proof
+	// of concept of it
+	"public-exploit",
+}
+
+// HasCustomScoring reports if the config changes the scores of any
+// vulnerabilities
+func (c *Config) HasCustomScoring() bool {
+	return len(c.SeverityOverrides) > 0 || len(c.Scoring.Multipliers) > 0
+}
+
+// SeverityOverrideFor returns the override for the first of the given IDs of
+// a vulnerability that has one
+func (c *Config) SeverityOverrideFor(ids []string) (SeverityOverride, bool) {
+	for _, override := range c.SeverityOverrides {
+		if slices.Contains(ids, override.ID) {
+			return override, true
+		}
+	}
+
+	return SeverityOverride{}, false
+}
+
+// Score returns the effective score and severity of a vulnerability with the
+// given IDs and base score, in a package with the given metadata, and whether
+// a public exploit of it is known
+func (c *Config) Score(ids []string, base float64, metadata *models.PackageMetadata, publicExploit bool) (float64, string) {
+	if override, ok := c.SeverityOverrideFor(ids); ok {
+		score := override.Score
+		if score == 0 {
+			score = models.ScoreOfSeverity(strings.ToUpper(override.Severity))
+		}
+		severity := strings.ToUpper(override.Severity)
+		if severity == "" {
+			severity = models.SeverityOfScore(score)
+		}
+
+		return score, severity
+	}
+
+	score := base
+	for _, condition := range c.conditionsMet(ids, metadata, publicExploit) {
+		if multiplier, ok := c.Scoring.Multipliers[condition]; ok {
+			score *= multiplier
+		}
+	}
+
+	// scores are out of 10 to one decimal place, the same as CVSS
+	score = math.Min(math.Round(score*10)/10, 10)
+
+	return score, models.SeverityOfScore(score)
+}
+
+// conditionsMet returns the scoring conditions that a vulnerability with the
+// given IDs in a package with the given metadata meets
+func (c *Config) conditionsMet(ids []string, metadata *models.PackageMetadata, publicExploit bool) []string {
+	var conditions []string
+
+	if metadata != nil {
+		if metadata.Scope != "" {
+			conditions = append(conditions, metadata.Scope)
+		}
+		if metadata.Relation != "" {
+			conditions = append(conditions, metadata.Relation)
+		}
+	}
+
+	for _, id := range ids {
+		if slices.Contains(c.Scoring.KnownExploited, id) {
+			conditions = append(conditions, "known-exploited")
+
+			break
+		}
+	}
+
+	if publicExploit {
+		conditions = append(conditions, "public-exploit")
+	}
+
+	return conditions
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestConfig_Score(t *testing.T) {
+	t.Parallel()
+
+	config := Config{
+		SeverityOverrides: []SeverityOverride{
+			{ID: "GHSA-low", Severity: "low"},
+			{ID: "GHSA-scored", Score: 7.5},
+		},
+		Scoring: Scoring{
+			Multipliers: map[string]float64{
+				"dev":             0.5,
+				"transitive":      0.8,
+				"known-exploited": 2,
+				"public-exploit":  1.2,
+			},
+			KnownExploited: []string{"CVE-2021-44228"},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		ids           []string
+		base          float64
+		metadata      *models.PackageMetadata
+		publicExploit bool
+		wantScore     float64
+		wantSeverity  string
+	}{
+		{name: "no conditions", ids: []string{"GHSA-other"}, base: 7.5, wantScore: 7.5, wantSeverity: "HIGH"},
+		{
+			name:         "dev and transitive",
+			ids:          []string{"GHSA-other"},
+			base:         9.8,
+			metadata:     &models.PackageMetadata{Scope: "dev", Relation: "transitive"},
+			wantScore:    3.9,
+			wantSeverity: "LOW",
+		},
+		{
+			name:         "known exploited is capped",
+			ids:          []string{"GHSA-jfh8-c2jp-5v3q", "CVE-2021-44228"},
+			base:         9.8,
+			wantScore:    10,
+			wantSeverity: "CRITICAL",
+		},
+		{
+			name:          "public exploit",
+			ids:           []string{"GHSA-other"},
+			base:          5.5,
+			publicExploit: true,
+			wantScore:     6.6,
+			wantSeverity:  "MEDIUM",
+		},
+		{
+			name:         "overridden severity ignores multipliers",
+			ids:          []string{"GHSA-low"},
+			base:         9.8,
+			metadata:     &models.PackageMetadata{Scope: "dev"},
+			wantScore:    2.0,
+			wantSeverity: "LOW",
+		},
+		{name: "overridden score", ids: []string{"GHSA-scored"}, base: 2, wantScore: 7.5, wantSeverity: "HIGH"},
+		{name: "unknown base", ids: []string{"GHSA-other"}, base: 0, wantScore: 0, wantSeverity: ""},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			score, severity := config.Score(tt.ids, tt.base, tt.metadata, tt.publicExploit)
+			if score != tt.wantScore || severity != tt.wantSeverity {
+				t.Errorf("Score() = %v, %q, want %v, %q", score, severity, tt.wantScore, tt.wantSeverity)
+			}
+		})
+	}
+}
@@ -0,0 +1,412 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/google/osv-scanner/pkg/licenses"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v2"
+)
+
+// ValidationError describes a single problem found in a config file
+type ValidationError struct {
+	Path string
+	// Line the problem was found on, or 0 if it could not be determined
+	Line    int
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", e.Path, e.Line, e.Message)
+	}
+
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// ValidationErrors is returned when a config file does not match the schema
+type ValidationErrors []ValidationError
+
+func (errs ValidationErrors) Error() string {
+	lines := make([]string, 0, len(errs))
+
+	for _, err := range errs {
+		lines = append(lines, err.Error())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+var lineNumberRegexp = regexp.MustCompile(`line (\d+)`)
+
+// lineFromMessage extracts the first "line N" from an error message
+func lineFromMessage(msg string) int {
+	matches := lineNumberRegexp.FindStringSubmatch(msg)
+	if matches == nil {
+		return 0
+	}
+	line, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0
+	}
+
+	return line
+}
+
+// lineOfOffset converts a byte offset in `content` into a line number
+func lineOfOffset(content []byte, offset int64) int {
+	if offset > int64(len(content)) {
+		offset = int64(len(content))
+	}
+
+	return bytes.Count(content[:offset], []byte("\n")) + 1
+}
+
+// findLine returns the line of the nth (starting from 0) occurrence of
+// `needle` in `content`, or 0 if there is no such occurrence
+func findLine(content []byte, needle string, nth int) int {
+	for i, line := range strings.Split(string(content), "\n") {
+		if strings.Contains(line, needle) {
+			if nth == 0 {
+				return i + 1
+			}
+			nth--
+		}
+	}
+
+	return 0
+}
+
+// validateTOML decodes the TOML config, returning unknown keys as warnings
+func validateTOML(configPath string, content []byte, config *Config) (ValidationErrors, ValidationErrors) {
+	md, err := toml.Decode(string(content), config)
+	if err != nil {
+		var parseErr toml.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, ValidationErrors{{Path: configPath, Line: parseErr.Position.Line, Message: parseErr.Error()}}
+		}
+
+		return nil, ValidationErrors{{Path: configPath, Line: lineFromMessage(err.Error()), Message: err.Error()}}
+	}
+
+	var warnings ValidationErrors
+	var reported []string
+
+	for _, key := range md.Undecoded() {
+		// only report the outermost unknown key, as everything nested under it
+		// will also be undecoded
+		isNested := false
+		for _, parent := range reported {
+			if strings.HasPrefix(key.String(), parent+".") {
+				isNested = true
+				break
+			}
+		}
+		if isNested {
+			continue
+		}
+		reported = append(reported, key.String())
+
+		warnings = append(warnings, ValidationError{
+			Path:    configPath,
+			Line:    findLine(content, key[len(key)-1], 0),
+			Message: fmt.Sprintf("unknown key %q", key.String()),
+		})
+	}
+
+	return warnings, nil
+}
+
+// yamlErrors converts the errors of decoding the YAML config
+func yamlErrors(configPath string, err error) ValidationErrors {
+	if err == nil {
+		return nil
+	}
+
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		return ValidationErrors{{Path: configPath, Line: lineFromMessage(err.Error()), Message: err.Error()}}
+	}
+
+	errs := make(ValidationErrors, 0, len(typeErr.Errors))
+
+	for _, msg := range typeErr.Errors {
+		line := lineFromMessage(msg)
+		msg = strings.TrimPrefix(msg, fmt.Sprintf("line %d: ", line))
+		errs = append(errs, ValidationError{Path: configPath, Line: line, Message: msg})
+	}
+
+	return errs
+}
+
+// validateYAML decodes the YAML config, returning unknown and repeated keys,
+// which are only rejected when decoding strictly, as warnings
+func validateYAML(configPath string, content []byte, config *Config) (ValidationErrors, ValidationErrors) {
+	if errs := yamlErrors(configPath, yaml.Unmarshal(content, config)); len(errs) > 0 {
+		return nil, errs
+	}
+
+	return yamlErrors(configPath, yaml.UnmarshalStrict(content, &Config{})), nil
+}
+
+// jsonError converts the error of decoding the JSON config
+func jsonError(configPath string, content []byte, err error) ValidationErrors {
+	if err == nil {
+		return nil
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	line := 0
+	switch {
+	case errors.As(err, &syntaxErr):
+		line = lineOfOffset(content, syntaxErr.Offset)
+	case errors.As(err, &typeErr):
+		line = lineOfOffset(content, typeErr.Offset)
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		line = findLine(content, strings.TrimPrefix(err.Error(), "json: unknown field "), 0)
+	}
+
+	return ValidationErrors{{Path: configPath, Line: line, Message: err.Error()}}
+}
+
+// validateJSON decodes the JSON config, returning the first unknown key as a
+// warning, as the decoder stops reporting them after the first
+func validateJSON(configPath string, content []byte, config *Config) (ValidationErrors, ValidationErrors) {
+	if errs := jsonError(configPath, content, json.Unmarshal(content, config)); len(errs) > 0 {
+		return nil, errs
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+
+	return jsonError(configPath, content, decoder.Decode(&Config{})), nil
+}
+
+// validateIgnoredVulns checks the decoded ignore entries are well-formed,
+// returning vulnerabilities that are ignored more than once as warnings
+func validateIgnoredVulns(configPath string, content []byte, config *Config, isTOML bool) (ValidationErrors, ValidationErrors) {
+	var warnings, errs ValidationErrors
+	seen := map[string]int{}
+
+	for i, entry := range config.IgnoredVulns {
+		if entry.ID == "" {
+			line := 0
+			if isTOML {
+				line = findLine(content, "[[IgnoredVulns]]", i)
+			}
+			errs = append(errs, ValidationError{
+				Path:    configPath,
+				Line:    line,
+				Message: fmt.Sprintf("ignore entry %d is missing an id", i+1),
+			})
+
+			continue
+		}
+
+		seen[entry.ID]++
+		if seen[entry.ID] > 1 {
+			warnings = append(warnings, ValidationError{
+				Path:    configPath,
+				Line:    findLine(content, entry.ID, seen[entry.ID]-1),
+				Message: fmt.Sprintf("%s is ignored more than once", entry.ID),
+			})
+		}
+	}
+
+	return warnings, errs
+}
+
+// validateInternalPackages checks that none of the internal package prefixes
+// are empty, as they would match every package
+func validateInternalPackages(configPath string, content []byte, config *Config) ValidationErrors {
+	var errs ValidationErrors
+
+	for i, prefix := range config.InternalPackages {
+		if strings.TrimSpace(prefix) == "" {
+			errs = append(errs, ValidationError{
+				Path:    configPath,
+				Line:    findLine(content, "InternalPackages", 0),
+				Message: fmt.Sprintf("internal package prefix %d is empty", i+1),
+			})
+		}
+	}
+
+	return errs
+}
+
+// validateSeverityOverrides checks that each severity override is for a
+// vulnerability and gives a valid severity or score
+func validateSeverityOverrides(configPath string, content []byte, config *Config, isTOML bool) ValidationErrors {
+	var errs ValidationErrors
+
+	for i, override := range config.SeverityOverrides {
+		line := 0
+		if isTOML {
+			line = findLine(content, "[[SeverityOverrides]]", i)
+		}
+
+		if override.ID == "" {
+			errs = append(errs, ValidationError{
+				Path:    configPath,
+				Line:    line,
+				Message: fmt.Sprintf("severity override %d is missing an id", i+1),
+			})
+		}
+
+		if override.Severity != "" && !slices.Contains(models.Severities, strings.ToUpper(override.Severity)) {
+			errs = append(errs, ValidationError{
+				Path:    configPath,
+				Line:    findLine(content, override.Severity, 0),
+				Message: fmt.Sprintf("severity override %d has an unknown severity %q", i+1, override.Severity),
+			})
+		}
+
+		if override.Score < 0 || override.Score > 10 {
+			errs = append(errs, ValidationError{
+				Path:    configPath,
+				Line:    line,
+				Message: fmt.Sprintf("severity override %d has a score that is not between 0 and 10", i+1),
+			})
+		}
+
+		if override.Severity == "" && override.Score == 0 {
+			errs = append(errs, ValidationError{
+				Path:    configPath,
+				Line:    line,
+				Message: fmt.Sprintf("severity override %d is missing a severity or score", i+1),
+			})
+		}
+	}
+
+	return errs
+}
+
+// validateScoring checks that the scoring multipliers are for known conditions
+// and would not make scores negative
+func validateScoring(configPath string, content []byte, config *Config) ValidationErrors {
+	var errs ValidationErrors
+
+	conditions := make([]string, 0, len(config.Scoring.Multipliers))
+	for condition := range config.Scoring.Multipliers {
+		conditions = append(conditions, condition)
+	}
+	sort.Strings(conditions)
+
+	for _, condition := range conditions {
+		if !slices.Contains(ScoringConditions, condition) {
+			errs = append(errs, ValidationError{
+				Path:    configPath,
+				Line:    findLine(content, condition, 0),
+				Message: fmt.Sprintf("unknown scoring condition %q, expected one of %s", condition, strings.Join(ScoringConditions, ", ")),
+			})
+
+			continue
+		}
+
+		if config.Scoring.Multipliers[condition] < 0 {
+			errs = append(errs, ValidationError{
+				Path:    configPath,
+				Line:    findLine(content, condition, 0),
+				Message: fmt.Sprintf("the multiplier for %q is negative", condition),
+			})
+		}
+	}
+
+	return errs
+}
+
+// validateFailOnSeverity checks that the severity that fails the scan is a
+// known severity
+func validateFailOnSeverity(configPath string, content []byte, config *Config) ValidationErrors {
+	if config.FailOnSeverity == "" || models.NormalizeSeverity(config.FailOnSeverity) != "" {
+		return nil
+	}
+
+	return ValidationErrors{{
+		Path:    configPath,
+		Line:    findLine(content, "FailOnSeverity", 0),
+		Message: fmt.Sprintf("unknown severity %q to fail on", config.FailOnSeverity),
+	}}
+}
+
+// validateLicenses checks that each license of the license policy is a single
+// license, optionally with an exception
+func validateLicenses(configPath string, content []byte, config *Config) ValidationErrors {
+	var errs ValidationErrors
+
+	for _, entries := range [][]string{config.Licenses.Allow, config.Licenses.Deny} {
+		for _, entry := range entries {
+			if _, err := licenses.NewPolicy([]string{entry}, nil); err != nil {
+				errs = append(errs, ValidationError{
+					Path:    configPath,
+					Line:    findLine(content, entry, 0),
+					Message: fmt.Sprintf("invalid license %v", err),
+				})
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateIgnoredPaths checks that each ignored path has a pattern, as an
+// empty pattern would never match anything
+func validateIgnoredPaths(configPath string, content []byte, config *Config, isTOML bool) ValidationErrors {
+	var errs ValidationErrors
+
+	for i, entry := range config.IgnoredPaths {
+		if strings.TrimSpace(entry.Path) != "" {
+			continue
+		}
+
+		line := 0
+		if isTOML {
+			line = findLine(content, "[[IgnoredPaths]]", i)
+		}
+		errs = append(errs, ValidationError{
+			Path:    configPath,
+			Line:    line,
+			Message: fmt.Sprintf("ignored path %d is missing a path", i+1),
+		})
+	}
+
+	return errs
+}
+
+// validateParseAs checks that each entry to parse files as a lockfile has a
+// path, and a parser that exists
+func validateParseAs(configPath string, content []byte, config *Config, isTOML bool) ValidationErrors {
+	var errs ValidationErrors
+
+	for i, entry := range config.ParseAs {
+		var message string
+		switch {
+		case strings.TrimSpace(entry.Path) == "":
+			message = fmt.Sprintf("parse as entry %d is missing a path", i+1)
+		case !slices.Contains(lockfile.ListParsers(), entry.Parser):
+			message = fmt.Sprintf("unknown parser %q to parse %s as", entry.Parser, entry.Path)
+		default:
+			continue
+		}
+
+		line := 0
+		if isTOML {
+			line = findLine(content, "[[ParseAs]]", i)
+		}
+		errs = append(errs, ValidationError{Path: configPath, Line: line, Message: message})
+	}
+
+	return errs
+}
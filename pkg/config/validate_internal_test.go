@@ -0,0 +1,403 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// expectValidationErrors checks the errors have the expected paths and lines,
+// and messages that start with the expected ones, as messages from the
+// underlying decoders can vary slightly
+func expectValidationErrors(t *testing.T, kind string, got ValidationErrors, expected ValidationErrors) {
+	t.Helper()
+
+	if len(got) != len(expected) {
+		t.Fatalf("%s not equal: %s", kind, cmp.Diff(expected, got))
+	}
+	for i, want := range expected {
+		if got[i].Path != want.Path || got[i].Line != want.Line || !strings.HasPrefix(got[i].Message, want.Message) {
+			t.Errorf("%s not equal: %s", kind, cmp.Diff(expected, got))
+		}
+	}
+}
+
+func TestDecodeConfig_Validation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		path     string
+		content  string
+		warnings ValidationErrors
+		expected ValidationErrors
+	}{
+		{
+			name: "valid toml",
+			path: "osv-scanner.toml",
+			content: `
+[[IgnoredVulns]]
+id = "GO-2022-0968"
+reason = "No ssh servers"
+`,
+			expected: nil,
+		},
+		{
+			name: "unknown toml key",
+			path: "osv-scanner.toml",
+			content: `
+[[IgnoredVulns]]
+id = "GO-2022-0968"
+reasn = "No ssh servers"
+`,
+			warnings: ValidationErrors{
+				{Path: "osv-scanner.toml", Line: 4, Message: `unknown key "IgnoredVulns.reasn"`},
+			},
+		},
+		{
+			name: "unknown toml table",
+			path: "osv-scanner.toml",
+			content: `
+[[IgnoredVulns]]
+id = "GO-2022-0968"
+
+[[IngoredVulns]]
+id = "GO-2022-1059"
+`,
+			warnings: ValidationErrors{
+				{Path: "osv-scanner.toml", Line: 5, Message: `unknown key "IngoredVulns"`},
+			},
+		},
+		{
+			name: "missing toml id",
+			path: "osv-scanner.toml",
+			content: `
+[[IgnoredVulns]]
+id = "GO-2022-0968"
+
+[[IgnoredVulns]]
+reason = "No ssh servers"
+`,
+			expected: ValidationErrors{
+				{Path: "osv-scanner.toml", Line: 5, Message: "ignore entry 2 is missing an id"},
+			},
+		},
+		{
+			name: "missing id in toml with another extension",
+			path: "osv-scanner.conf",
+			content: `
+[[IgnoredVulns]]
+id = "GO-2022-0968"
+
+[[IgnoredVulns]]
+reason = "No ssh servers"
+`,
+			expected: ValidationErrors{
+				{Path: "osv-scanner.conf", Line: 5, Message: "ignore entry 2 is missing an id"},
+			},
+		},
+		{
+			name: "empty internal package prefix",
+			path: "osv-scanner.toml",
+			content: `
+InternalPackages = ["@acme", ""]
+
+[[IgnoredVulns]]
+id = "GO-2022-0968"
+`,
+			expected: ValidationErrors{
+				{Path: "osv-scanner.toml", Line: 2, Message: "internal package prefix 2 is empty"},
+			},
+		},
+		{
+			name: "duplicate toml id",
+			path: "osv-scanner.toml",
+			content: `
+[[IgnoredVulns]]
+id = "GO-2022-0968"
+
+[[IgnoredVulns]]
+id = "GO-2022-0968"
+`,
+			warnings: ValidationErrors{
+				{Path: "osv-scanner.toml", Line: 6, Message: "GO-2022-0968 is ignored more than once"},
+			},
+		},
+		{
+			name: "valid scoring",
+			path: "osv-scanner.toml",
+			content: `
+[[SeverityOverrides]]
+id = "GHSA-1234"
+severity = "low"
+reason = "Only reachable from the admin panel"
+
+[Scoring]
+knownExploited = ["CVE-2021-44228"]
+
+[Scoring.multipliers]
+dev = 0.5
+known-exploited = 2.0
+`,
+			expected: nil,
+		},
+		{
+			name: "invalid severity overrides",
+			path: "osv-scanner.toml",
+			content: `
+[[SeverityOverrides]]
+severity = "SEVERE"
+
+[[SeverityOverrides]]
+id = "GHSA-1234"
+score = 11.0
+`,
+			expected: ValidationErrors{
+				{Path: "osv-scanner.toml", Line: 2, Message: "severity override 1 is missing an id"},
+				{Path: "osv-scanner.toml", Line: 3, Message: `severity override 1 has an unknown severity "SEVERE"`},
+				{Path: "osv-scanner.toml", Line: 5, Message: "severity override 2 has a score that is not between 0 and 10"},
+			},
+		},
+		{
+			name: "invalid scoring multipliers",
+			path: "osv-scanner.yaml",
+			content: `
+Scoring:
+  multipliers:
+    dev: -1
+    reachable: 2
+`,
+			expected: ValidationErrors{
+				{Path: "osv-scanner.yaml", Line: 4, Message: `the multiplier for "dev" is negative`},
+				{Path: "osv-scanner.yaml", Line: 5, Message: `unknown scoring condition "reachable"`},
+			},
+		},
+		{
+			name:     "valid severity to fail on",
+			path:     "osv-scanner.toml",
+			content:  "FailOnSeverity = \"moderate\"\n",
+			expected: nil,
+		},
+		{
+			name: "invalid severity to fail on",
+			path: "osv-scanner.yaml",
+			content: `
+IgnoredVulns: []
+FailOnSeverity: severe
+`,
+			expected: ValidationErrors{
+				{Path: "osv-scanner.yaml", Line: 3, Message: `unknown severity "severe" to fail on`},
+			},
+		},
+		{
+			name: "missing ignored path",
+			path: "osv-scanner.toml",
+			content: `
+[[IgnoredPaths]]
+path = "examples/"
+
+[[IgnoredPaths]]
+reason = "Test fixtures"
+`,
+			expected: ValidationErrors{
+				{Path: "osv-scanner.toml", Line: 5, Message: "ignored path 2 is missing a path"},
+			},
+		},
+		{
+			name: "invalid parse as entries",
+			path: "osv-scanner.toml",
+			content: `
+[[ParseAs]]
+path = "renamed-requirements.txt"
+parser = "requirements.txt"
+
+[[ParseAs]]
+parser = "yarn.lock"
+
+[[ParseAs]]
+path = "deps.lock"
+parser = "deps.lock"
+`,
+			expected: ValidationErrors{
+				{Path: "osv-scanner.toml", Line: 6, Message: "parse as entry 2 is missing a path"},
+				{Path: "osv-scanner.toml", Line: 9, Message: `unknown parser "deps.lock" to parse deps.lock as`},
+			},
+		},
+		{
+			name: "unknown yaml key",
+			path: "osv-scanner.yaml",
+			content: `
+IgnoredVulns:
+  - id: GO-2022-0968
+    reasn: No ssh servers
+`,
+			warnings: ValidationErrors{
+				{Path: "osv-scanner.yaml", Line: 4, Message: "field reasn not found in type config.IgnoreEntry"},
+			},
+		},
+		{
+			name: "bad yaml type",
+			path: "osv-scanner.yaml",
+			content: `
+IgnoredVulns:
+  id: GO-2022-0968
+`,
+			expected: ValidationErrors{
+				{Path: "osv-scanner.yaml", Line: 3, Message: "cannot unmarshal !!map into []config.IgnoreEntry"},
+			},
+		},
+		{
+			name: "unknown json key",
+			path: "osv-scanner.json",
+			content: `{
+  "IgnoredVulns": [
+    {
+      "id": "GO-2022-0968",
+      "reasn": "No ssh servers"
+    }
+  ]
+}`,
+			warnings: ValidationErrors{
+				{Path: "osv-scanner.json", Line: 5, Message: `json: unknown field "reasn"`},
+			},
+		},
+		{
+			name: "valid licenses",
+			path: "osv-scanner.toml",
+			content: `
+[Licenses]
+allow = ["MIT", "Apache-2.0", "GPL-2.0-only WITH Classpath-exception-2.0"]
+deny = ["AGPL-3.0-only"]
+`,
+			expected: nil,
+		},
+		{
+			name: "invalid licenses",
+			path: "osv-scanner.yaml",
+			content: `
+Licenses:
+  allow:
+    - MIT
+    - MIT OR Apache-2.0
+  deny:
+    - "GPL-2.0-only WITH"
+`,
+			expected: ValidationErrors{
+				{Path: "osv-scanner.yaml", Line: 5, Message: `invalid license "MIT OR Apache-2.0"`},
+				{Path: "osv-scanner.yaml", Line: 7, Message: `invalid license "GPL-2.0-only WITH"`},
+			},
+		},
+		{
+			name: "bad json type",
+			path: "osv-scanner.json",
+			content: `{
+  "IgnoredVulns": [
+    {
+      "id": 1
+    }
+  ]
+}`,
+			expected: ValidationErrors{
+				{Path: "osv-scanner.json", Line: 4, Message: "json: cannot unmarshal number into Go struct field"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var config Config
+			warnings, err := decodeConfig(tt.path, []byte(tt.content), &config)
+
+			expectValidationErrors(t, "Warnings", warnings, tt.warnings)
+
+			if tt.expected == nil {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+
+				return
+			}
+
+			var errs ValidationErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("Expected ValidationErrors, got %v", err)
+			}
+
+			expectValidationErrors(t, "Errors", errs, tt.expected)
+		})
+	}
+}
+
+func TestDecodeConfig_KeepsEntriesWithWarnings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path    string
+		content string
+	}{
+		{
+			path: "osv-scanner.toml",
+			content: `
+[[IgnoredVulns]]
+id = "GO-2022-0968"
+reasn = "No ssh servers"
+
+[[IgnoredVulns]]
+id = "GO-2022-0968"
+
+[[IngoredVulns]]
+id = "GO-2022-1059"
+`,
+		},
+		{
+			path: "osv-scanner.yaml",
+			content: `
+IgnoredVulns:
+  - id: GO-2022-0968
+    reasn: No ssh servers
+  - id: GO-2022-0968
+IngoredVulns:
+  - id: GO-2022-1059
+`,
+		},
+		{
+			path: "osv-scanner.json",
+			content: `{
+  "IgnoredVulns": [
+    { "id": "GO-2022-0968", "reasn": "No ssh servers" },
+    { "id": "GO-2022-0968" }
+  ],
+  "IngoredVulns": [{ "id": "GO-2022-1059" }]
+}`,
+		},
+	}
+
+	expected := []IgnoreEntry{{ID: "GO-2022-0968"}, {ID: "GO-2022-0968"}}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			var config Config
+			warnings, err := decodeConfig(tt.path, []byte(tt.content), &config)
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(warnings) == 0 {
+				t.Errorf("Expected warnings, got none")
+			}
+
+			if diff := cmp.Diff(expected, config.IgnoredVulns); diff != "" {
+				t.Errorf("IgnoredVulns not equal: %s", diff)
+			}
+		})
+	}
+}
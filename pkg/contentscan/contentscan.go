@@ -0,0 +1,120 @@
+// Package contentscan checks the content of lockfiles against the OSV
+// database without touching the filesystem or running any commands, so that
+// it can be used where neither are available, such as in a browser or a
+// serverless function when compiled to WebAssembly.
+//
+// Requests to the OSV API are sent with osv.HTTPClient, which can be replaced
+// to change how they are fetched.
+package contentscan
+
+import (
+	"github.com/google/osv-scanner/pkg/grouper"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+)
+
+// File is a lockfile that has already been read
+type File struct {
+	// Path is the name of the lockfile, which is used to find the parser for
+	// it unless ParseAs is set, and to describe where packages come from
+	Path    string `json:"path"`
+	ParseAs string `json:"parseAs,omitempty"`
+	Content []byte `json:"content"`
+}
+
+// Scan checks the packages of the given lockfiles for known vulnerabilities.
+// Lockfiles that cannot be parsed are recorded as parse failures rather than
+// stopping the others from being checked.
+func Scan(files []File) (models.VulnerabilityResults, error) {
+	query := osv.BatchedQuery{}
+	var failures []models.ParseFailure
+
+	for _, file := range files {
+		parsed, err := lockfile.ParseContent(file.Path, file.ParseAs, file.Content)
+		if err != nil {
+			failures = append(failures, models.ParseFailure{
+				Path:   file.Path,
+				Parser: file.ParseAs,
+				Error:  err.Error(),
+			})
+
+			continue
+		}
+
+		query.Queries = append(query.Queries, Queries(parsed)...)
+	}
+
+	results := models.VulnerabilityResults{Results: []models.PackageSource{}}
+
+	if len(query.Queries) > 0 {
+		resp, err := osv.MakeRequest(query)
+		if err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+
+		hydrated, err := osv.Hydrate(resp)
+		if err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+
+		results = Group(query, hydrated)
+	}
+
+	results.ParseFailures = failures
+
+	return results, nil
+}
+
+// Queries returns the queries for the packages of the parsed lockfile
+func Queries(parsed lockfile.Lockfile) []*osv.Query {
+	queries := make([]*osv.Query, 0, len(parsed.Packages))
+
+	for _, pkg := range parsed.Packages {
+		query := osv.MakePkgRequest(pkg)
+		query.Source = models.SourceInfo{
+			Path: parsed.FilePath,
+			Type: "lockfile",
+		}
+		queries = append(queries, query)
+	}
+
+	return queries
+}
+
+// Group groups the vulnerabilities found for the queries by the lockfiles
+// that the packages come from, in the order that the lockfiles were queried
+func Group(query osv.BatchedQuery, resp *osv.HydratedBatchedResponse) models.VulnerabilityResults {
+	results := models.VulnerabilityResults{Results: []models.PackageSource{}}
+	sources := map[models.SourceInfo]int{}
+
+	for i, query := range query.Queries {
+		if i >= len(resp.Results) || len(resp.Results[i].Vulns) == 0 {
+			continue
+		}
+
+		pkg := models.PackageVulns{
+			Package: models.PackageInfo{
+				Name:      query.Package.Name,
+				Version:   query.Version,
+				Ecosystem: query.Package.Ecosystem,
+			},
+			Vulnerabilities: resp.Results[i].Vulns,
+			Metadata:        query.Metadata,
+		}
+		pkg.Groups = grouper.Group(grouper.ConvertVulnerabilityToIDAliases(pkg.Vulnerabilities))
+		for j := range pkg.Groups {
+			pkg.Groups[j].Fingerprint = models.Fingerprint(query.Source.Path, pkg.Package, pkg.Groups[j].IDs)
+		}
+
+		index, ok := sources[query.Source]
+		if !ok {
+			index = len(results.Results)
+			sources[query.Source] = index
+			results.Results = append(results.Results, models.PackageSource{Source: query.Source})
+		}
+		results.Results[index].Packages = append(results.Results[index].Packages, pkg)
+	}
+
+	return results
+}
@@ -0,0 +1,100 @@
+package contentscan_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/contentscan"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+)
+
+func TestScan_ParseFailures(t *testing.T) {
+	t.Parallel()
+
+	results, err := contentscan.Scan([]contentscan.File{
+		{Path: "package-lock.json", Content: []byte("this is not json")},
+		{Path: "unknown.txt", Content: []byte("")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results.Results) != 0 {
+		t.Errorf("expected no results, but got %v", results.Results)
+	}
+
+	var paths []string
+	for _, failure := range results.ParseFailures {
+		paths = append(paths, failure.Path)
+	}
+
+	if diff := cmp.Diff([]string{"package-lock.json", "unknown.txt"}, paths); diff != "" {
+		t.Errorf("unexpected parse failures (-want +got):\n%s", diff)
+	}
+}
+
+func TestQueries(t *testing.T) {
+	t.Parallel()
+
+	parsed, err := lockfile.ParseContent("requirements.txt", "", []byte("flask==2.0.0\nrequests==2.25.1\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	queries := contentscan.Queries(parsed)
+
+	if len(queries) != 2 {
+		t.Fatalf("expected 2 queries, but got %d", len(queries))
+	}
+
+	for _, query := range queries {
+		want := models.SourceInfo{Path: "requirements.txt", Type: "lockfile"}
+		if query.Source != want {
+			t.Errorf("expected the source of %s to be %v, but got %v", query.Package.Name, want, query.Source)
+		}
+	}
+}
+
+func TestGroup(t *testing.T) {
+	t.Parallel()
+
+	first := models.SourceInfo{Path: "b/package-lock.json", Type: "lockfile"}
+	second := models.SourceInfo{Path: "a/package-lock.json", Type: "lockfile"}
+
+	query := osv.BatchedQuery{Queries: []*osv.Query{
+		{Package: osv.Package{Name: "lodash", Ecosystem: "npm"}, Version: "4.17.20", Source: first},
+		{Package: osv.Package{Name: "left-pad", Ecosystem: "npm"}, Version: "1.0.0", Source: first},
+		{Package: osv.Package{Name: "minimist", Ecosystem: "npm"}, Version: "1.2.5", Source: second},
+	}}
+	resp := &osv.HydratedBatchedResponse{Results: []osv.Response{
+		{Vulns: []models.Vulnerability{{ID: "GHSA-1", Aliases: []string{"CVE-1"}}, {ID: "CVE-1"}}},
+		{},
+		{Vulns: []models.Vulnerability{{ID: "GHSA-2"}}},
+	}}
+
+	results := contentscan.Group(query, resp)
+
+	var got []string
+	for _, source := range results.Results {
+		for _, pkg := range source.Packages {
+			for _, group := range pkg.Groups {
+				got = append(got, source.Source.Path+" "+pkg.Package.Name+" "+group.IDs[0])
+
+				if group.Fingerprint == "" {
+					t.Errorf("expected %s to be fingerprinted", pkg.Package.Name)
+				}
+			}
+		}
+	}
+
+	want := []string{
+		"b/package-lock.json lodash GHSA-1",
+		"a/package-lock.json minimist GHSA-2",
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected grouping (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,165 @@
+package gitauth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"gopkg.in/yaml.v2"
+)
+
+// defaultTokenUsername is used for token authentication over https when no
+// username is configured, which is accepted by GitHub and most other hosts
+const defaultTokenUsername = "x-access-token"
+
+// HostCredentials describes how to authenticate with a single git host
+type HostCredentials struct {
+	// Username is used for token authentication, and for ssh if the url does not have one
+	Username string `yaml:"username"`
+	// Token is used to authenticate https clones; prefer TokenEnv to keep it
+	// out of the credentials file
+	Token    string `yaml:"token"`
+	TokenEnv string `yaml:"tokenEnv"`
+	// SSHKey is the path to a private key, such as a deploy key, for ssh clones
+	SSHKey              string `yaml:"sshKey"`
+	SSHKeyPassphraseEnv string `yaml:"sshKeyPassphraseEnv"`
+	// SSHAgent uses the keys of the running ssh agent for ssh clones
+	SSHAgent bool `yaml:"sshAgent"`
+	// KnownHosts is the path to a known_hosts file to verify the host against,
+	// instead of the default ~/.ssh/known_hosts
+	KnownHosts string `yaml:"knownHosts"`
+}
+
+// Credentials are how to authenticate with each git host when cloning, keyed by host name
+type Credentials struct {
+	Hosts map[string]HostCredentials `yaml:"hosts"`
+}
+
+var errInvalidCredentials = errors.New("invalid git credentials")
+
+// Load reads git credentials from a YAML file
+func Load(path string) (Credentials, error) {
+	var credentials Credentials
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return credentials, err
+	}
+
+	if err := yaml.UnmarshalStrict(content, &credentials); err != nil {
+		return credentials, fmt.Errorf("%w: %v", errInvalidCredentials, err)
+	}
+
+	for host, creds := range credentials.Hosts {
+		if creds.SSHKey != "" && creds.SSHAgent {
+			return credentials, fmt.Errorf("%w: %s cannot use both sshKey and sshAgent", errInvalidCredentials, host)
+		}
+		if creds.Token != "" && creds.TokenEnv != "" {
+			return credentials, fmt.Errorf("%w: %s cannot use both token and tokenEnv", errInvalidCredentials, host)
+		}
+	}
+
+	return credentials, nil
+}
+
+// hostCredentials returns the credentials for the given host, falling back to
+// the GITHUB_TOKEN environment variable for github.com
+func (c Credentials) hostCredentials(host string) HostCredentials {
+	if creds, ok := c.Hosts[host]; ok {
+		return creds
+	}
+
+	if host == "github.com" {
+		return HostCredentials{TokenEnv: "GITHUB_TOKEN"}
+	}
+
+	return HostCredentials{}
+}
+
+// AuthFor returns how to authenticate when cloning the repository at the
+// given url, or nil if no authentication is needed (or the defaults of the
+// transport should be used). Tokens are only sent over https, so that they
+// are never sent in the clear to a plain http url.
+func (c Credentials) AuthFor(url string) (transport.AuthMethod, error) {
+	endpoint, err := transport.NewEndpoint(url)
+	if err != nil {
+		return nil, err
+	}
+
+	creds := c.hostCredentials(endpoint.Host)
+
+	switch endpoint.Protocol {
+	case "https":
+		return creds.httpAuth(), nil
+	case "ssh":
+		return creds.sshAuth(endpoint.User)
+	default:
+		return nil, nil
+	}
+}
+
+func (creds HostCredentials) httpAuth() transport.AuthMethod {
+	token := creds.Token
+	if creds.TokenEnv != "" {
+		token = os.Getenv(creds.TokenEnv)
+	}
+
+	if token == "" {
+		return nil
+	}
+
+	username := creds.Username
+	if username == "" {
+		username = defaultTokenUsername
+	}
+
+	return &githttp.BasicAuth{Username: username, Password: token}
+}
+
+func (creds HostCredentials) sshAuth(user string) (transport.AuthMethod, error) {
+	if user == "" {
+		user = creds.Username
+	}
+	if user == "" {
+		user = gitssh.DefaultUsername
+	}
+
+	var callbackHelper *gitssh.HostKeyCallbackHelper
+
+	if creds.KnownHosts != "" {
+		callback, err := gitssh.NewKnownHostsCallback(creds.KnownHosts)
+		if err != nil {
+			return nil, fmt.Errorf("could not read known hosts: %w", err)
+		}
+		callbackHelper = &gitssh.HostKeyCallbackHelper{HostKeyCallback: callback}
+	}
+
+	switch {
+	case creds.SSHKey != "":
+		keys, err := gitssh.NewPublicKeysFromFile(user, creds.SSHKey, os.Getenv(creds.SSHKeyPassphraseEnv))
+		if err != nil {
+			return nil, fmt.Errorf("could not read ssh key %s: %w", creds.SSHKey, err)
+		}
+		if callbackHelper != nil {
+			keys.HostKeyCallbackHelper = *callbackHelper
+		}
+
+		return keys, nil
+	case creds.SSHAgent || callbackHelper != nil:
+		agent, err := gitssh.NewSSHAgentAuth(user)
+		if err != nil {
+			return nil, fmt.Errorf("could not use ssh agent: %w", err)
+		}
+		if callbackHelper != nil {
+			agent.HostKeyCallbackHelper = *callbackHelper
+		}
+
+		return agent, nil
+	default:
+		// go-git uses the ssh agent by default
+		return nil, nil
+	}
+}
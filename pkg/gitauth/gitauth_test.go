@@ -0,0 +1,186 @@
+package gitauth_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/gitauth"
+
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+)
+
+func writeFile(t *testing.T, name string, content []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		t.Fatalf("could not write %s: %v", name, err)
+	}
+
+	return path
+}
+
+func writeDeployKey(t *testing.T) string {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("could not marshal key: %v", err)
+	}
+
+	return writeFile(t, "deploy_key", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	path := writeFile(t, "credentials.yaml", []byte(`
+hosts:
+  github.com:
+    tokenEnv: MY_GITHUB_TOKEN
+  gitlab.example.com:
+    username: oauth2
+    token: glpat-123
+  bitbucket.org:
+    sshAgent: true
+`))
+
+	got, err := gitauth.Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := gitauth.Credentials{
+		Hosts: map[string]gitauth.HostCredentials{
+			"github.com":         {TokenEnv: "MY_GITHUB_TOKEN"},
+			"gitlab.example.com": {Username: "oauth2", Token: "glpat-123"},
+			"bitbucket.org":      {SSHAgent: true},
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("credentials mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []string{
+		"hosts:\n  github.com:\n    tokn: abc\n",
+		"hosts:\n  github.com:\n    token: abc\n    tokenEnv: GITHUB_TOKEN\n",
+		"hosts:\n  github.com:\n    sshKey: key\n    sshAgent: true\n",
+	}
+
+	for _, content := range tests {
+		if _, err := gitauth.Load(writeFile(t, "credentials.yaml", []byte(content))); err == nil {
+			t.Errorf("expected an error for %q", content)
+		}
+	}
+}
+
+func TestCredentials_AuthFor_Token(t *testing.T) {
+	t.Parallel()
+
+	credentials := gitauth.Credentials{
+		Hosts: map[string]gitauth.HostCredentials{
+			"gitlab.example.com": {Username: "oauth2", Token: "glpat-123"},
+			"git.example.com":    {Token: "abc"},
+		},
+	}
+
+	auth, err := credentials.AuthFor("https://gitlab.example.com/group/project.git")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(&githttp.BasicAuth{Username: "oauth2", Password: "glpat-123"}, auth); diff != "" {
+		t.Errorf("auth mismatch (-want +got):\n%s", diff)
+	}
+
+	auth, err = credentials.AuthFor("https://git.example.com/project.git")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(&githttp.BasicAuth{Username: "x-access-token", Password: "abc"}, auth); diff != "" {
+		t.Errorf("auth mismatch (-want +got):\n%s", diff)
+	}
+
+	auth, err = credentials.AuthFor("https://public.example.com/project.git")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if auth != nil {
+		t.Errorf("expected no auth for an unknown host, got %v", auth)
+	}
+}
+
+//nolint:paralleltest // sets an environment variable
+func TestCredentials_AuthFor_PlainHTTP(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "ghp-123")
+
+	credentials := gitauth.Credentials{
+		Hosts: map[string]gitauth.HostCredentials{
+			"gitlab.example.com": {Username: "oauth2", Token: "glpat-123"},
+		},
+	}
+
+	for _, url := range []string{"http://github.com/google/osv-scanner.git", "http://gitlab.example.com/group/project.git"} {
+		auth, err := credentials.AuthFor(url)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if auth != nil {
+			t.Errorf("expected no auth to be sent in the clear to %s, got %v", url, auth)
+		}
+	}
+
+	auth, err := credentials.AuthFor("https://github.com/google/osv-scanner.git")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(&githttp.BasicAuth{Username: "x-access-token", Password: "ghp-123"}, auth); diff != "" {
+		t.Errorf("auth mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCredentials_AuthFor_DeployKey(t *testing.T) {
+	t.Parallel()
+
+	credentials := gitauth.Credentials{
+		Hosts: map[string]gitauth.HostCredentials{
+			"github.com": {SSHKey: writeDeployKey(t)},
+		},
+	}
+
+	for _, url := range []string{"git@github.com:my-org/private.git", "ssh://git@github.com/my-org/private.git"} {
+		auth, err := credentials.AuthFor(url)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", url, err)
+		}
+
+		keys, ok := auth.(*gitssh.PublicKeys)
+		if !ok {
+			t.Fatalf("expected public key auth for %s, got %T", url, auth)
+		}
+		if keys.User != "git" {
+			t.Errorf("expected user git for %s, got %s", url, keys.User)
+		}
+	}
+
+	credentials.Hosts["github.com"] = gitauth.HostCredentials{SSHKey: "does-not-exist"}
+	if _, err := credentials.AuthFor("git@github.com:my-org/private.git"); err == nil {
+		t.Errorf("expected an error for a missing key")
+	}
+}
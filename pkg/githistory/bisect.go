@@ -0,0 +1,146 @@
+package githistory
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// ErrPackageNotFound is returned when the package is not in the committed lockfile
+var ErrPackageNotFound = errors.New("package not found")
+
+// Commit describes the git commit that made a change
+type Commit struct {
+	Hash    string    `json:"hash"`
+	Author  string    `json:"author"`
+	Email   string    `json:"email"`
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
+
+func commitFrom(commit *object.Commit) Commit {
+	return Commit{
+		Hash:    commit.Hash.String(),
+		Author:  commit.Author.Name,
+		Email:   commit.Author.Email,
+		Time:    commit.Author.When.UTC(),
+		Message: commit.Message,
+	}
+}
+
+// Introduction describes the commit that introduced a version of a package to a lockfile
+type Introduction struct {
+	Lockfile string `json:"lockfile"`
+	Package  string `json:"package"`
+	Version  string `json:"version"`
+	Commit   Commit `json:"commit"`
+}
+
+// lockfileAt returns the packages in the lockfile as of the given commit, or
+// nil if the lockfile did not exist then
+func lockfileAt(commit *object.Commit, rel string, parseAs string) ([]lockfile.PackageDetails, error) {
+	file, err := commit.File(rel)
+	if errors.Is(err, object.ErrFileNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := file.Contents()
+	if err != nil {
+		return nil, err
+	}
+
+	// parsers read from disk, and find which parser to use by the file name
+	dir, err := os.MkdirTemp("", "osv-scanner-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, filepath.Base(rel))
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		return nil, err
+	}
+
+	parser, parsedAs := lockfile.FindParser(path, parseAs)
+	if parser == nil {
+		return nil, fmt.Errorf("%w for %s", lockfile.ErrParserNotFound, parsedAs)
+	}
+
+	return parser(path)
+}
+
+func hasPackage(packages []lockfile.PackageDetails, name string, version string) (string, bool) {
+	for _, pkg := range packages {
+		if pkg.Name == name && (version == "" || pkg.Version == version) {
+			return pkg.Version, true
+		}
+	}
+
+	return "", false
+}
+
+// FindIntroduction walks the history of the lockfile at the given path to
+// find the commit that introduced the given version of a package, being the
+// oldest commit since which the lockfile has always included that version.
+// If version is empty, the version currently in the lockfile is used.
+func FindIntroduction(lockfilePath string, parseAs string, name string, version string) (Introduction, error) {
+	repo, rel, err := openRepository(lockfilePath)
+	if err != nil {
+		return Introduction{}, err
+	}
+
+	commits, err := repo.Log(&git.LogOptions{FileName: &rel, Order: git.LogOrderCommitterTime})
+	if err != nil {
+		return Introduction{}, err
+	}
+	defer commits.Close()
+
+	var introducedBy *object.Commit
+
+	// commits are walked from the newest to the oldest
+	err = commits.ForEach(func(commit *object.Commit) error {
+		packages, err := lockfileAt(commit, rel, parseAs)
+		if err != nil {
+			return fmt.Errorf("could not read %s at %s: %w", rel, commit.Hash, err)
+		}
+
+		foundVersion, found := hasPackage(packages, name, version)
+		if !found {
+			return storer.ErrStop
+		}
+
+		version = foundVersion
+		introducedBy = commit
+
+		return nil
+	})
+	if err != nil {
+		return Introduction{}, err
+	}
+
+	if introducedBy == nil {
+		if version == "" {
+			return Introduction{}, fmt.Errorf("%w: %s is not in the committed %s", ErrPackageNotFound, name, rel)
+		}
+
+		return Introduction{}, fmt.Errorf("%w: %s@%s is not in the committed %s", ErrPackageNotFound, name, version, rel)
+	}
+
+	return Introduction{
+		Lockfile: rel,
+		Package:  name,
+		Version:  version,
+		Commit:   commitFrom(introducedBy),
+	}, nil
+}
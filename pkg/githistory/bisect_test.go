@@ -0,0 +1,82 @@
+package githistory_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/githistory"
+)
+
+func TestFindIntroduction(t *testing.T) {
+	t.Parallel()
+
+	dir, hashes := makeRepository(t, []testCommit{
+		{author: "alice", message: "add flask", files: map[string]string{"requirements.txt": "flask==1.0.0\n"}},
+		{author: "bob", message: "add django", files: map[string]string{"requirements.txt": "flask==1.0.0\ndjango==2.0.0\n"}},
+		{author: "carol", message: "bump flask", files: map[string]string{"requirements.txt": "flask==1.1.0\ndjango==2.0.0\n"}},
+		{author: "dave", message: "readme", files: map[string]string{"README.md": "hello"}},
+		{author: "erin", message: "drop django", files: map[string]string{"requirements.txt": "flask==1.1.0\n"}},
+		{author: "frank", message: "restore django", files: map[string]string{"requirements.txt": "flask==1.1.0\ndjango==2.0.0\n"}},
+	})
+
+	lockfilePath := filepath.Join(dir, "requirements.txt")
+
+	tests := []struct {
+		name       string
+		pkg        string
+		version    string
+		wantCommit string
+		wantAuthor string
+		wantVer    string
+	}{
+		{name: "bumped version", pkg: "flask", version: "1.1.0", wantCommit: hashes[2], wantAuthor: "carol", wantVer: "1.1.0"},
+		{name: "current version", pkg: "flask", wantCommit: hashes[2], wantAuthor: "carol", wantVer: "1.1.0"},
+		{name: "re-added package", pkg: "django", version: "2.0.0", wantCommit: hashes[5], wantAuthor: "frank", wantVer: "2.0.0"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			introduction, err := githistory.FindIntroduction(lockfilePath, "", tt.pkg, tt.version)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if introduction.Commit.Hash != tt.wantCommit {
+				t.Errorf("expected commit %s, got %s", tt.wantCommit, introduction.Commit.Hash)
+			}
+			if introduction.Commit.Author != tt.wantAuthor || introduction.Commit.Email != tt.wantAuthor+"@example.com" {
+				t.Errorf("expected author %s, got %s <%s>", tt.wantAuthor, introduction.Commit.Author, introduction.Commit.Email)
+			}
+			if introduction.Version != tt.wantVer || introduction.Lockfile != "requirements.txt" {
+				t.Errorf("unexpected introduction %+v", introduction)
+			}
+		})
+	}
+}
+
+func TestFindIntroduction_NotFound(t *testing.T) {
+	t.Parallel()
+
+	dir, _ := makeRepository(t, []testCommit{
+		{author: "alice", message: "add flask", files: map[string]string{"requirements.txt": "flask==1.0.0\n"}},
+		{author: "bob", message: "bump flask", files: map[string]string{"requirements.txt": "flask==1.1.0\n"}},
+	})
+
+	_, err := githistory.FindIntroduction(filepath.Join(dir, "requirements.txt"), "", "flask", "1.0.0")
+	if !errors.Is(err, githistory.ErrPackageNotFound) {
+		t.Errorf("expected ErrPackageNotFound, got %v", err)
+	}
+}
+
+func TestFindIntroduction_NotInRepository(t *testing.T) {
+	t.Parallel()
+
+	_, err := githistory.FindIntroduction(filepath.Join(t.TempDir(), "requirements.txt"), "", "flask", "")
+	if !errors.Is(err, githistory.ErrNotInRepository) {
+		t.Errorf("expected ErrNotInRepository, got %v", err)
+	}
+}
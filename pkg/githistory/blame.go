@@ -0,0 +1,125 @@
+package githistory
+
+import (
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// versionSearchDistance is how many lines after a package's name its version
+// is looked for, for lockfiles that put them on separate lines
+const versionSearchDistance = 10
+
+// FindVersionLine returns the line (starting from 1) of the given lines that
+// pins the given version of a package, or 0 if it cannot be found.
+//
+// Lockfiles don't record where each package is declared, so this looks for
+// the first line with the name that either has the version too or is shortly
+// followed by a line with the version, falling back to the first line with
+// just the name.
+func FindVersionLine(lines []string, name string, version string) int {
+	if name == "" {
+		return 0
+	}
+
+	nameLine := 0
+
+	for i, line := range lines {
+		if !strings.Contains(line, name) {
+			continue
+		}
+		if nameLine == 0 {
+			nameLine = i + 1
+		}
+		if version == "" {
+			break
+		}
+
+		for j := i; j < len(lines) && j <= i+versionSearchDistance; j++ {
+			if strings.Contains(lines[j], version) {
+				return j + 1
+			}
+		}
+	}
+
+	return nameLine
+}
+
+// FileBlame is the blame of the committed version of a file
+type FileBlame struct {
+	// Lines are the lines of the file as of the latest commit
+	Lines []string
+
+	repo    *git.Repository
+	hashes  []plumbing.Hash
+	commits map[plumbing.Hash]Commit
+}
+
+// BlameFile blames the version of the file at the given path as of the
+// latest commit in the git repository it is in
+func BlameFile(path string) (*FileBlame, error) {
+	repo, rel, err := openRepository(path)
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := git.Blame(commit, rel)
+	if err != nil {
+		return nil, err
+	}
+
+	blame := &FileBlame{
+		Lines:   make([]string, 0, len(result.Lines)),
+		repo:    repo,
+		hashes:  make([]plumbing.Hash, 0, len(result.Lines)),
+		commits: map[plumbing.Hash]Commit{},
+	}
+
+	for _, line := range result.Lines {
+		blame.Lines = append(blame.Lines, line.Text)
+		blame.hashes = append(blame.hashes, line.Hash)
+	}
+
+	return blame, nil
+}
+
+// Line returns the commit that last changed the given line (starting from 1)
+func (b *FileBlame) Line(line int) (Commit, bool) {
+	if line < 1 || line > len(b.hashes) {
+		return Commit{}, false
+	}
+
+	hash := b.hashes[line-1]
+	if commit, ok := b.commits[hash]; ok {
+		return commit, true
+	}
+
+	commit, err := b.repo.CommitObject(hash)
+	if err != nil {
+		return Commit{}, false
+	}
+
+	b.commits[hash] = commitFrom(commit)
+
+	return b.commits[hash], true
+}
+
+// PackageLine returns the line that pins the given version of a package, and
+// the commit that last changed it
+func (b *FileBlame) PackageLine(name string, version string) (int, Commit, bool) {
+	line := FindVersionLine(b.Lines, name, version)
+	commit, ok := b.Line(line)
+
+	return line, commit, ok
+}
@@ -0,0 +1,76 @@
+package githistory_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/githistory"
+)
+
+func TestFindVersionLine(t *testing.T) {
+	t.Parallel()
+
+	yarnLock := strings.Split(`# yarn lockfile v1
+
+"@babel/code-frame@^7.0.0":
+  version "7.18.6"
+
+lodash@^4.17.0:
+  version "4.17.15"
+  resolved "https://registry.yarnpkg.com/lodash/-/lodash-4.17.15.tgz"`, "\n")
+
+	requirements := strings.Split("django==2.0.0\nflask==1.0.0\n", "\n")
+
+	tests := []struct {
+		name    string
+		lines   []string
+		pkg     string
+		version string
+		want    int
+	}{
+		{name: "same line", lines: requirements, pkg: "flask", version: "1.0.0", want: 2},
+		{name: "version on a later line", lines: yarnLock, pkg: "lodash", version: "4.17.15", want: 7},
+		{name: "version not found", lines: yarnLock, pkg: "lodash", version: "1.0.0", want: 6},
+		{name: "package not found", lines: requirements, pkg: "requests", version: "2.0.0", want: 0},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := githistory.FindVersionLine(tt.lines, tt.pkg, tt.version); got != tt.want {
+				t.Errorf("expected line %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBlameFile(t *testing.T) {
+	t.Parallel()
+
+	dir, hashes := makeRepository(t, []testCommit{
+		{author: "alice", message: "add deps", files: map[string]string{"requirements.txt": "flask==1.0.0\ndjango==2.0.0\n"}},
+		{author: "bob", message: "bump django", files: map[string]string{"requirements.txt": "flask==1.0.0\ndjango==2.2.0\n"}},
+	})
+
+	blame, err := githistory.BlameFile(filepath.Join(dir, "requirements.txt"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	line, commit, ok := blame.PackageLine("django", "2.2.0")
+	if !ok || line != 2 || commit.Hash != hashes[1] || commit.Author != "bob" {
+		t.Errorf("expected django to be blamed on bob at line 2, got %d %+v", line, commit)
+	}
+
+	line, commit, ok = blame.PackageLine("flask", "1.0.0")
+	if !ok || line != 1 || commit.Hash != hashes[0] || commit.Email != "alice@example.com" {
+		t.Errorf("expected flask to be blamed on alice at line 1, got %d %+v", line, commit)
+	}
+
+	if _, _, ok := blame.PackageLine("requests", "2.0.0"); ok {
+		t.Errorf("did not expect requests to be found")
+	}
+}
@@ -0,0 +1,53 @@
+package githistory
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// ErrNotInRepository is returned when a lockfile is not within a git repository
+var ErrNotInRepository = errors.New("not in a git repository")
+
+// openRepository opens the git repository that contains the given file,
+// returning it along with the slash-separated path of the file within it
+func openRepository(path string) (*git.Repository, string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	repo, err := git.PlainOpenWithOptions(filepath.Dir(path), &git.PlainOpenOptions{DetectDotGit: true, EnableDotGitCommonDir: true})
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		return nil, "", fmt.Errorf("%s is %w", path, ErrNotInRepository)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, "", err
+	}
+
+	// resolve symlinks (such as /tmp on macOS) so the path is relative to the worktree
+	root, err := filepath.EvalSymlinks(worktree.Filesystem.Root())
+	if err != nil {
+		return nil, "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, "", err
+	}
+
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return repo, filepath.ToSlash(rel), nil
+}
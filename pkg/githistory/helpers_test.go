@@ -0,0 +1,76 @@
+package githistory_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+type testCommit struct {
+	author  string
+	message string
+	// files maps the paths to write to their content, with an empty content
+	// meaning the file should be removed
+	files map[string]string
+}
+
+// makeRepository creates a git repository with the given commits, returning
+// its directory and the hashes of the commits in order
+func makeRepository(t *testing.T, commits []testCommit) (string, []string) {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("could not create repository: %v", err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("could not get worktree: %v", err)
+	}
+
+	when := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	hashes := make([]string, 0, len(commits))
+
+	for _, commit := range commits {
+		for name, content := range commit.files {
+			path := filepath.Join(dir, filepath.FromSlash(name))
+
+			if content == "" {
+				if _, err := worktree.Remove(name); err != nil {
+					t.Fatalf("could not remove %s: %v", name, err)
+				}
+
+				continue
+			}
+
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatalf("could not create directory: %v", err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				t.Fatalf("could not write %s: %v", name, err)
+			}
+			if _, err := worktree.Add(name); err != nil {
+				t.Fatalf("could not add %s: %v", name, err)
+			}
+		}
+
+		when = when.Add(24 * time.Hour)
+		signature := &object.Signature{Name: commit.author, Email: commit.author + "@example.com", When: when}
+
+		hash, err := worktree.Commit(commit.message, &git.CommitOptions{Author: signature, Committer: signature})
+		if err != nil {
+			t.Fatalf("could not commit: %v", err)
+		}
+
+		hashes = append(hashes, hash.String())
+	}
+
+	return dir, hashes
+}
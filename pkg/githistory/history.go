@@ -0,0 +1,226 @@
+package githistory
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Checkpoint is a commit in the history of a repository that its lockfiles
+// are scanned at
+type Checkpoint struct {
+	// Tag is the name of the tag of the commit, if it was found by its tag
+	Tag    string `json:"tag,omitempty"`
+	Commit Commit `json:"commit"`
+}
+
+// Name is the tag of the checkpoint if it has one, or its abbreviated hash
+func (c Checkpoint) Name() string {
+	if c.Tag != "" {
+		return c.Tag
+	}
+	if len(c.Commit.Hash) > 12 {
+		return c.Commit.Hash[:12]
+	}
+
+	return c.Commit.Hash
+}
+
+// Snapshot is the packages of a lockfile as of a checkpoint
+type Snapshot struct {
+	Checkpoint Checkpoint
+	// Packages is nil if the lockfile did not exist at the checkpoint
+	Packages []lockfile.PackageDetails
+}
+
+// headHistory returns the commits reachable from the head of the repository,
+// from the oldest to the newest
+func headHistory(repo *git.Repository) ([]*object.Commit, error) {
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+
+	commits, err := repo.Log(&git.LogOptions{From: head.Hash(), Order: git.LogOrderCommitterTime})
+	if err != nil {
+		return nil, err
+	}
+	defer commits.Close()
+
+	var history []*object.Commit
+	err = commits.ForEach(func(commit *object.Commit) error {
+		history = append(history, commit)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(history)-1; i < j; i, j = i+1, j-1 {
+		history[i], history[j] = history[j], history[i]
+	}
+
+	return history, nil
+}
+
+// tagCheckpoints returns a checkpoint for each tag of a commit in the history,
+// in the order of the history, along with the last commit of the history if
+// it has not been tagged yet
+func tagCheckpoints(repo *git.Repository, history []*object.Commit) ([]Checkpoint, error) {
+	positions := make(map[plumbing.Hash]int, len(history))
+	for i, commit := range history {
+		positions[commit.Hash] = i
+	}
+
+	type tagged struct {
+		name     string
+		position int
+	}
+	var tags []tagged
+
+	iter, err := repo.Tags()
+	if err != nil {
+		return nil, err
+	}
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		hash := ref.Hash()
+		// annotated tags point to a tag object rather than to the commit
+		if tag, err := repo.TagObject(hash); err == nil {
+			commit, err := tag.Commit()
+			if err != nil {
+				// tags of anything other than a commit are not checkpoints
+				return nil //nolint:nilerr
+			}
+			hash = commit.Hash
+		} else if !errors.Is(err, plumbing.ErrObjectNotFound) {
+			return err
+		}
+
+		// tags of commits that are not in the history, such as those of
+		// other branches, did not lead to it
+		if position, ok := positions[hash]; ok {
+			tags = append(tags, tagged{name: ref.Name().Short(), position: position})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		if tags[i].position != tags[j].position {
+			return tags[i].position < tags[j].position
+		}
+
+		return tags[i].name < tags[j].name
+	})
+
+	checkpoints := make([]Checkpoint, 0, len(tags)+1)
+	last := -1
+	for _, tag := range tags {
+		// commits with several tags are only scanned once, by their first
+		if tag.position == last {
+			continue
+		}
+		last = tag.position
+		checkpoints = append(checkpoints, Checkpoint{Tag: tag.name, Commit: commitFrom(history[tag.position])})
+	}
+
+	if len(history) > 0 && last != len(history)-1 {
+		checkpoints = append(checkpoints, Checkpoint{Commit: commitFrom(history[len(history)-1])})
+	}
+
+	return checkpoints, nil
+}
+
+// everyCheckpoints returns a checkpoint for every nth commit of the history,
+// counting back from the last commit so that it is always included
+func everyCheckpoints(history []*object.Commit, every int) []Checkpoint {
+	var checkpoints []Checkpoint
+	for i, commit := range history {
+		if (len(history)-1-i)%every == 0 {
+			checkpoints = append(checkpoints, Checkpoint{Commit: commitFrom(commit)})
+		}
+	}
+
+	return checkpoints
+}
+
+// LockfileHistory parses the lockfile at the given path as of each checkpoint
+// of the history of the git repository that it is in, from the oldest to the
+// newest. The checkpoints are the tags of the commits that led to the latest
+// commit if every is 0, or otherwise every nth of those commits, along with
+// the latest commit itself.
+func LockfileHistory(lockfilePath string, parseAs string, every int) ([]Snapshot, error) {
+	repo, rel, err := openRepository(lockfilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := headHistory(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var checkpoints []Checkpoint
+	if every > 0 {
+		checkpoints = everyCheckpoints(history, every)
+	} else {
+		checkpoints, err = tagCheckpoints(repo, history)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the lockfile is usually the same at many checkpoints, so each version
+	// of it is only parsed once
+	parsed := map[plumbing.Hash][]lockfile.PackageDetails{}
+	snapshots := make([]Snapshot, 0, len(checkpoints))
+
+	for _, checkpoint := range checkpoints {
+		commit, err := repo.CommitObject(plumbing.NewHash(checkpoint.Commit.Hash))
+		if err != nil {
+			return nil, err
+		}
+
+		snapshot := Snapshot{Checkpoint: checkpoint}
+
+		file, err := commit.File(rel)
+		switch {
+		case errors.Is(err, object.ErrFileNotFound):
+		case err != nil:
+			return nil, err
+		default:
+			packages, ok := parsed[file.Hash]
+			if !ok {
+				content, err := file.Contents()
+				if err != nil {
+					return nil, err
+				}
+
+				lf, err := lockfile.ParseContent(rel, parseAs, []byte(content))
+				if err != nil {
+					return nil, fmt.Errorf("could not read %s at %s: %w", rel, checkpoint.Name(), err)
+				}
+				packages = lf.Packages
+				if packages == nil {
+					packages = []lockfile.PackageDetails{}
+				}
+				parsed[file.Hash] = packages
+			}
+			snapshot.Packages = packages
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, nil
+}
@@ -0,0 +1,121 @@
+package githistory_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/githistory"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// snapshotSummary describes a snapshot as the name of its checkpoint and the
+// versions of flask in the lockfile, with "-" for a missing lockfile
+func snapshotSummary(snapshots []githistory.Snapshot) []string {
+	summary := make([]string, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		versions := "-"
+		if snapshot.Packages != nil {
+			versions = ""
+			for _, pkg := range snapshot.Packages {
+				if pkg.Name == "flask" {
+					versions += pkg.Version
+				}
+			}
+		}
+		summary = append(summary, snapshot.Checkpoint.Name()+" "+versions)
+	}
+
+	return summary
+}
+
+func TestLockfileHistory(t *testing.T) {
+	t.Parallel()
+
+	dir, hashes := makeRepository(t, []testCommit{
+		{author: "alice", message: "readme", files: map[string]string{"README.md": "hello"}},
+		{author: "alice", message: "add flask", files: map[string]string{"requirements.txt": "flask==1.0.0\n"}},
+		{author: "bob", message: "add django", files: map[string]string{"requirements.txt": "flask==1.0.0\ndjango==2.0.0\n"}},
+		{author: "carol", message: "bump flask", files: map[string]string{"requirements.txt": "flask==1.1.0\ndjango==2.0.0\n"}},
+		{author: "dave", message: "readme", files: map[string]string{"README.md": "hello world"}},
+		{author: "erin", message: "bump flask", files: map[string]string{"requirements.txt": "flask==2.0.0\ndjango==2.0.0\n"}},
+	})
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatalf("could not open repository: %v", err)
+	}
+	tags := map[string]string{"v0.1.0": hashes[0], "v1.0.0": hashes[2], "v1.0.1": hashes[2], "v1.1.0": hashes[4]}
+	for name, hash := range tags {
+		var opts *git.CreateTagOptions
+		// annotated tags point to a tag object rather than the commit
+		if name == "v1.1.0" {
+			opts = &git.CreateTagOptions{
+				Message: "release " + name,
+				Tagger:  &object.Signature{Name: "alice", Email: "alice@example.com", When: time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)},
+			}
+		}
+		if _, err := repo.CreateTag(name, plumbing.NewHash(hash), opts); err != nil {
+			t.Fatalf("could not tag %s: %v", name, err)
+		}
+	}
+
+	lockfilePath := filepath.Join(dir, "requirements.txt")
+
+	tests := []struct {
+		name  string
+		every int
+		want  []string
+	}{
+		{
+			name: "tags",
+			want: []string{"v0.1.0 -", "v1.0.0 1.0.0", "v1.1.0 1.1.0", hashes[5][:12] + " 2.0.0"},
+		},
+		{
+			name:  "every other commit",
+			every: 2,
+			want:  []string{hashes[1][:12] + " 1.0.0", hashes[3][:12] + " 1.1.0", hashes[5][:12] + " 2.0.0"},
+		},
+		{
+			name:  "every commit",
+			every: 1,
+			want: []string{
+				hashes[0][:12] + " -",
+				hashes[1][:12] + " 1.0.0",
+				hashes[2][:12] + " 1.0.0",
+				hashes[3][:12] + " 1.1.0",
+				hashes[4][:12] + " 1.1.0",
+				hashes[5][:12] + " 2.0.0",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			snapshots, err := githistory.LockfileHistory(lockfilePath, "", tt.every)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, snapshotSummary(snapshots)); diff != "" {
+				t.Errorf("LockfileHistory() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLockfileHistory_NotInRepository(t *testing.T) {
+	t.Parallel()
+
+	_, err := githistory.LockfileHistory(filepath.Join(t.TempDir(), "requirements.txt"), "", 0)
+	if err == nil {
+		t.Errorf("expected an error")
+	}
+}
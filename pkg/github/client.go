@@ -0,0 +1,67 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/rest"
+)
+
+const (
+	// DefaultBaseURL is the URL of the public GitHub REST API.
+	DefaultBaseURL = "https://api.github.com"
+	// perPage is the number of items requested per page when listing
+	perPage = 100
+)
+
+// Client is a minimal client for the parts of the GitHub REST API used by osv-scanner
+type Client struct {
+	BaseURL    string
+	Token      string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a client for the public GitHub API, authenticated with
+// the token in the GITHUB_TOKEN environment variable (if any)
+func NewClient() *Client {
+	return &Client{
+		BaseURL:    DefaultBaseURL,
+		Token:      os.Getenv("GITHUB_TOKEN"),
+		HTTPClient: rest.HTTPClient,
+	}
+}
+
+// SplitRepository splits an "owner/repo" string into its owner and name
+func SplitRepository(repository string) (string, string, error) {
+	return rest.SplitRepository(repository, "owner/repo")
+}
+
+// do sends a request to the given API path, encoding `body` as JSON if it is
+// not nil and decoding the response into `out` if it is not nil
+func (c *Client) do(ctx context.Context, method string, path string, body any, out any) error {
+	header := http.Header{}
+	header.Set("Accept", "application/vnd.github+json")
+	header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.Token != "" {
+		header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	return rest.Do(ctx, c.HTTPClient, "github", rest.Request{
+		Method: method,
+		URL:    strings.TrimSuffix(c.BaseURL, "/") + path,
+		Path:   path,
+		Header: header,
+		Body:   body,
+	}, out)
+}
+
+// listAll fetches every page of the given listing endpoint, calling `collect`
+// with the items decoded from each page
+func listAll[T any](ctx context.Context, c *Client, path string, query url.Values, collect func([]T)) error {
+	return rest.ListAll(perPage, query, func(query url.Values, items *[]T) error {
+		return c.do(ctx, http.MethodGet, path+"?"+query.Encode(), nil, items)
+	}, collect)
+}
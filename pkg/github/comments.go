@@ -0,0 +1,49 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type issueComment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+// UpsertIssueComment updates the first comment on the given issue or pull
+// request that contains `marker` to have the given body, creating a new
+// comment if there is no such comment
+func (c *Client) UpsertIssueComment(ctx context.Context, owner string, repo string, number int, marker string, body string) error {
+	repoPath := fmt.Sprintf("/repos/%s/%s", url.PathEscape(owner), url.PathEscape(repo))
+	commentsPath := fmt.Sprintf("%s/issues/%d/comments", repoPath, number)
+
+	var existing *issueComment
+
+	err := listAll(ctx, c, commentsPath, url.Values{}, func(comments []issueComment) {
+		for i := range comments {
+			if existing == nil && strings.Contains(comments[i].Body, marker) {
+				existing = &comments[i]
+			}
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list comments: %w", err)
+	}
+
+	payload := map[string]string{"body": body}
+
+	if existing != nil {
+		err = c.do(ctx, http.MethodPatch, fmt.Sprintf("%s/issues/comments/%d", repoPath, existing.ID), payload, nil)
+	} else {
+		err = c.do(ctx, http.MethodPost, commentsPath, payload, nil)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to write comment: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+type dependabotAlert struct {
+	DismissedReason  string `json:"dismissed_reason"`
+	DismissedComment string `json:"dismissed_comment"`
+	Dependency       struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		ManifestPath string `json:"manifest_path"`
+	} `json:"dependency"`
+	SecurityAdvisory struct {
+		GHSAID      string `json:"ghsa_id"`
+		CVEID       string `json:"cve_id"`
+		Identifiers []struct {
+			Value string `json:"value"`
+		} `json:"identifiers"`
+	} `json:"security_advisory"`
+}
+
+type codeScanningAlert struct {
+	DismissedReason  string `json:"dismissed_reason"`
+	DismissedComment string `json:"dismissed_comment"`
+	Rule             struct {
+		ID string `json:"id"`
+	} `json:"rule"`
+	MostRecentInstance struct {
+		Location struct {
+			Path string `json:"path"`
+		} `json:"location"`
+	} `json:"most_recent_instance"`
+}
+
+// Dismissal is an alert that has been dismissed on GitHub
+type Dismissal struct {
+	// IDs are the ids of the advisory the alert was raised for, including its
+	// aliases, such as its CVE
+	IDs    []string
+	Reason string
+	// Ecosystem and Package are the OSV ecosystem and name of the package the
+	// alert was raised for, if known
+	Ecosystem string
+	Package   string
+	// ManifestPath is the path within the repository of the manifest or
+	// lockfile the alert was raised for, if known
+	ManifestPath string
+}
+
+// dependabotEcosystems maps the ecosystems of Dependabot alerts to the
+// ecosystems used by OSV
+var dependabotEcosystems = map[string]string{
+	"composer": "Packagist",
+	"erlang":   "Hex",
+	"go":       "Go",
+	"maven":    "Maven",
+	"npm":      "npm",
+	"nuget":    "NuGet",
+	"pip":      "PyPI",
+	"pub":      "Pub",
+	"rubygems": "RubyGems",
+	"rust":     "crates.io",
+}
+
+// advisoryIDRegexp matches the ids of advisories, such as "GHSA-xxxx-xxxx-xxxx",
+// "CVE-2022-1234" and "GO-2022-0968", rather than the ids of code scanning
+// rules such as "js/sql-injection"
+var advisoryIDRegexp = regexp.MustCompile(`^(GHSA(-[23456789cfghjmpqrvwx]{4}){3}|[A-Z][A-Z0-9]*-\d{4}-[\w.-]+)$`)
+
+func dismissalReason(reason string, comment string) string {
+	if comment == "" {
+		return "dismissed on GitHub as " + reason
+	}
+
+	return fmt.Sprintf("dismissed on GitHub as %s: %s", reason, comment)
+}
+
+// appendID adds the id to the ids if it is not empty or already present
+func appendID(ids []string, id string) []string {
+	if id == "" || slices.Contains(ids, id) {
+		return ids
+	}
+
+	return append(ids, id)
+}
+
+// dependabotDismissal is the dismissal of the Dependabot alert, which only
+// applies to the package and manifest the alert was raised for
+func dependabotDismissal(alert dependabotAlert) Dismissal {
+	var ids []string
+	ids = appendID(ids, alert.SecurityAdvisory.GHSAID)
+	ids = appendID(ids, alert.SecurityAdvisory.CVEID)
+	for _, identifier := range alert.SecurityAdvisory.Identifiers {
+		ids = appendID(ids, identifier.Value)
+	}
+
+	ecosystem := alert.Dependency.Package.Ecosystem
+	if osvEcosystem, ok := dependabotEcosystems[strings.ToLower(ecosystem)]; ok {
+		ecosystem = osvEcosystem
+	}
+
+	return Dismissal{
+		IDs:          ids,
+		Reason:       dismissalReason(alert.DismissedReason, alert.DismissedComment),
+		Ecosystem:    ecosystem,
+		Package:      alert.Dependency.Package.Name,
+		ManifestPath: alert.Dependency.ManifestPath,
+	}
+}
+
+// DismissedAlerts returns every Dependabot alert that has been dismissed in
+// the given repository, and every code scanning alert whose rule is an
+// advisory, such as those uploaded by osv-scanner, so that triage done in
+// GitHub is respected by the scan
+func (c *Client) DismissedAlerts(ctx context.Context, owner string, repo string) ([]Dismissal, error) {
+	var dismissals []Dismissal
+
+	repoPath := fmt.Sprintf("/repos/%s/%s", url.PathEscape(owner), url.PathEscape(repo))
+
+	err := listAll(ctx, c, repoPath+"/dependabot/alerts", url.Values{"state": {"dismissed"}}, func(alerts []dependabotAlert) {
+		for _, alert := range alerts {
+			if dismissal := dependabotDismissal(alert); len(dismissal.IDs) > 0 {
+				dismissals = append(dismissals, dismissal)
+			}
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dismissed dependabot alerts: %w", err)
+	}
+
+	err = listAll(ctx, c, repoPath+"/code-scanning/alerts", url.Values{"state": {"dismissed"}}, func(alerts []codeScanningAlert) {
+		for _, alert := range alerts {
+			if !advisoryIDRegexp.MatchString(alert.Rule.ID) {
+				continue
+			}
+
+			dismissals = append(dismissals, Dismissal{
+				IDs:          []string{alert.Rule.ID},
+				Reason:       dismissalReason(alert.DismissedReason, alert.DismissedComment),
+				ManifestPath: alert.MostRecentInstance.Location.Path,
+			})
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dismissed code scanning alerts: %w", err)
+	}
+
+	return dismissals, nil
+}
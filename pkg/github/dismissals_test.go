@@ -0,0 +1,124 @@
+package github_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/github"
+)
+
+func TestClient_DismissedAlerts(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/dependabot/alerts", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer my-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("state") != "dismissed" {
+			t.Errorf("expected only dismissed alerts to be requested, got %s", r.URL.RawQuery)
+		}
+
+		_ = json.NewEncoder(w).Encode([]map[string]any{
+			{
+				"dismissed_reason":  "tolerable_risk",
+				"dismissed_comment": "No ssh servers",
+				"dependency": map[string]any{
+					"package":       map[string]any{"ecosystem": "go", "name": "golang.org/x/crypto"},
+					"manifest_path": "services/api/go.mod",
+				},
+				"security_advisory": map[string]any{
+					"ghsa_id": "GHSA-gwc9-m7rh-j2ww",
+					"cve_id":  "CVE-2022-27191",
+					"identifiers": []map[string]any{
+						{"type": "GHSA", "value": "GHSA-gwc9-m7rh-j2ww"},
+						{"type": "CVE", "value": "CVE-2022-27191"},
+					},
+				},
+			},
+			{
+				"dismissed_reason":  "inaccurate",
+				"security_advisory": map[string]any{"ghsa_id": "GHSA-m2qf-hxjv-5gpq"},
+			},
+		})
+	})
+	mux.HandleFunc("/repos/owner/repo/code-scanning/alerts", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]map[string]any{
+			{
+				"dismissed_reason": "won't fix",
+				"rule":             map[string]any{"id": "GO-2022-1059"},
+			},
+			{
+				"dismissed_reason":     "false positive",
+				"rule":                 map[string]any{"id": "GHSA-m2qf-hxjv-5gpq"},
+				"most_recent_instance": map[string]any{"location": map[string]any{"path": "web/package-lock.json"}},
+			},
+			{
+				"dismissed_reason": "false positive",
+				"rule":             map[string]any{"id": "js/sql-injection"},
+			},
+		})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := &github.Client{BaseURL: server.URL, Token: "my-token"}
+
+	got, err := client.DismissedAlerts(context.Background(), "owner", "repo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []github.Dismissal{
+		{
+			IDs:          []string{"GHSA-gwc9-m7rh-j2ww", "CVE-2022-27191"},
+			Reason:       "dismissed on GitHub as tolerable_risk: No ssh servers",
+			Ecosystem:    "Go",
+			Package:      "golang.org/x/crypto",
+			ManifestPath: "services/api/go.mod",
+		},
+		{IDs: []string{"GHSA-m2qf-hxjv-5gpq"}, Reason: "dismissed on GitHub as inaccurate"},
+		{IDs: []string{"GO-2022-1059"}, Reason: "dismissed on GitHub as won't fix"},
+		{IDs: []string{"GHSA-m2qf-hxjv-5gpq"}, Reason: "dismissed on GitHub as false positive", ManifestPath: "web/package-lock.json"},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("DismissedAlerts() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestClient_DismissedAlerts_Error(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := &github.Client{BaseURL: server.URL}
+
+	if _, err := client.DismissedAlerts(context.Background(), "owner", "repo"); err == nil {
+		t.Errorf("expected an error when the repository cannot be found")
+	}
+}
+
+func TestSplitRepository(t *testing.T) {
+	t.Parallel()
+
+	owner, repo, err := github.SplitRepository("google/osv-scanner")
+	if err != nil || owner != "google" || repo != "osv-scanner" {
+		t.Errorf("unexpected result: %s, %s, %v", owner, repo, err)
+	}
+
+	for _, invalid := range []string{"", "google", "google/", "/osv-scanner", "google/osv-scanner/extra"} {
+		if _, _, err := github.SplitRepository(invalid); err == nil {
+			t.Errorf("expected %q to be invalid", invalid)
+		}
+	}
+}
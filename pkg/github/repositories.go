@@ -0,0 +1,32 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// Repository is a repository hosted on GitHub
+type Repository struct {
+	FullName string `json:"full_name"`
+	CloneURL string `json:"clone_url"`
+	Private  bool   `json:"private"`
+	Fork     bool   `json:"fork"`
+	Archived bool   `json:"archived"`
+}
+
+// OrganizationRepositories returns every repository in the given organization
+// that the client can see
+func (c *Client) OrganizationRepositories(ctx context.Context, org string) ([]Repository, error) {
+	var repositories []Repository
+
+	path := fmt.Sprintf("/orgs/%s/repos", url.PathEscape(org))
+	err := listAll(ctx, c, path, url.Values{"type": {"all"}}, func(page []Repository) {
+		repositories = append(repositories, page...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories of %s: %w", org, err)
+	}
+
+	return repositories, nil
+}
@@ -0,0 +1,76 @@
+package github_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/github"
+)
+
+func TestClient_OrganizationRepositories(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orgs/my-org/repos", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("type") != "all" {
+			t.Errorf("expected all repositories to be requested, got %s", r.URL.RawQuery)
+		}
+
+		// the first page is full, so a second page is requested
+		count := 100
+		if r.URL.Query().Get("page") == "2" {
+			count = 3
+		}
+
+		repos := make([]map[string]any, 0, count)
+		for i := 0; i < count; i++ {
+			repos = append(repos, map[string]any{
+				"full_name": fmt.Sprintf("my-org/repo-%s-%d", r.URL.Query().Get("page"), i),
+				"clone_url": fmt.Sprintf("https://github.com/my-org/repo-%s-%d.git", r.URL.Query().Get("page"), i),
+				"archived":  i == 0,
+			})
+		}
+
+		_ = json.NewEncoder(w).Encode(repos)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := &github.Client{BaseURL: server.URL}
+
+	repos, err := client.OrganizationRepositories(context.Background(), "my-org")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repos) != 103 {
+		t.Fatalf("expected 103 repositories, got %d", len(repos))
+	}
+
+	last := repos[len(repos)-1]
+	if last.FullName != "my-org/repo-2-2" || last.CloneURL != "https://github.com/my-org/repo-2-2.git" {
+		t.Errorf("unexpected repository %+v", last)
+	}
+
+	if !repos[100].Archived || repos[101].Archived {
+		t.Errorf("expected archived to be decoded")
+	}
+}
+
+func TestClient_OrganizationRepositories_Error(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	client := &github.Client{BaseURL: server.URL}
+
+	if _, err := client.OrganizationRepositories(context.Background(), "missing"); err == nil {
+		t.Errorf("expected an error")
+	}
+}
@@ -0,0 +1,79 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/rest"
+)
+
+const (
+	// DefaultBaseURL is the URL of the GitLab.com REST API.
+	DefaultBaseURL = "https://gitlab.com/api/v4"
+	// perPage is the number of items requested per page when listing
+	perPage = 100
+)
+
+// Client is a minimal client for the parts of the GitLab REST API used by osv-scanner
+type Client struct {
+	BaseURL string
+	// Token is a personal, project or group access token
+	Token string
+	// JobToken is a CI job token, used if Token is not set
+	JobToken   string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a client for the GitLab instance the current CI job is
+// running on (or GitLab.com), authenticated with the token in GITLAB_TOKEN or
+// the job token in CI_JOB_TOKEN
+func NewClient() *Client {
+	baseURL := os.Getenv("CI_API_V4_URL")
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Client{
+		BaseURL:    baseURL,
+		Token:      os.Getenv("GITLAB_TOKEN"),
+		JobToken:   os.Getenv("CI_JOB_TOKEN"),
+		HTTPClient: rest.HTTPClient,
+	}
+}
+
+// projectPath returns the API path of a project, which can be identified by
+// either its numeric id or its full path (e.g. "group/project")
+func projectPath(project string) string {
+	return "/projects/" + url.PathEscape(project)
+}
+
+// do sends a request to the given API path, encoding `body` as JSON if it is
+// not nil and decoding the response into `out` if it is not nil
+func (c *Client) do(ctx context.Context, method string, path string, body any, out any) error {
+	header := http.Header{}
+	switch {
+	case c.Token != "":
+		header.Set("PRIVATE-TOKEN", c.Token)
+	case c.JobToken != "":
+		header.Set("JOB-TOKEN", c.JobToken)
+	}
+
+	return rest.Do(ctx, c.HTTPClient, "gitlab", rest.Request{
+		Method: method,
+		URL:    strings.TrimSuffix(c.BaseURL, "/") + path,
+		Path:   path,
+		Header: header,
+		Body:   body,
+	}, out)
+}
+
+// listAll fetches every page of the given listing endpoint, calling `collect`
+// with the items decoded from each page
+func listAll[T any](ctx context.Context, c *Client, path string, query url.Values, collect func([]T)) error {
+	return rest.ListAll(perPage, query, func(query url.Values, items *[]T) error {
+		return c.do(ctx, http.MethodGet, path+"?"+query.Encode(), nil, items)
+	}, collect)
+}
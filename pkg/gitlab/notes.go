@@ -0,0 +1,48 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type note struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+// UpsertMergeRequestNote updates the first note on the given merge request
+// that contains `marker` to have the given body, creating a new note if there
+// is no such note
+func (c *Client) UpsertMergeRequestNote(ctx context.Context, project string, iid int, marker string, body string) error {
+	notesPath := fmt.Sprintf("%s/merge_requests/%d/notes", projectPath(project), iid)
+
+	var existing *note
+
+	err := listAll(ctx, c, notesPath, url.Values{}, func(notes []note) {
+		for i := range notes {
+			if existing == nil && strings.Contains(notes[i].Body, marker) {
+				existing = &notes[i]
+			}
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list merge request notes: %w", err)
+	}
+
+	payload := map[string]string{"body": body}
+
+	if existing != nil {
+		err = c.do(ctx, http.MethodPut, fmt.Sprintf("%s/%d", notesPath, existing.ID), payload, nil)
+	} else {
+		err = c.do(ctx, http.MethodPost, notesPath, payload, nil)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to write merge request note: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package gitlab_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/gitlab"
+)
+
+func TestClient_UpsertMergeRequestNote(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		existing       []map[string]any
+		expectedMethod string
+		expectedPath   string
+	}{
+		{
+			name:           "no existing note",
+			existing:       []map[string]any{{"id": 1, "body": "LGTM"}},
+			expectedMethod: http.MethodPost,
+			expectedPath:   "/projects/group/project/merge_requests/7/notes",
+		},
+		{
+			name:           "existing note",
+			existing:       []map[string]any{{"id": 1, "body": "LGTM"}, {"id": 2, "body": "<!-- marker -->\nold results"}},
+			expectedMethod: http.MethodPut,
+			expectedPath:   "/projects/group/project/merge_requests/7/notes/2",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotMethod, gotPath, gotBody string
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("JOB-TOKEN") != "job-token" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				if r.Method == http.MethodGet {
+					_ = json.NewEncoder(w).Encode(tt.existing)
+					return
+				}
+
+				var payload map[string]string
+				_ = json.NewDecoder(r.Body).Decode(&payload)
+
+				gotMethod, gotPath, gotBody = r.Method, r.URL.Path, payload["body"]
+				w.WriteHeader(http.StatusCreated)
+			}))
+			defer server.Close()
+
+			client := &gitlab.Client{BaseURL: server.URL, JobToken: "job-token"}
+
+			err := client.UpsertMergeRequestNote(context.Background(), "group/project", 7, "<!-- marker -->", "<!-- marker -->\nnew results")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if gotMethod != tt.expectedMethod || gotPath != tt.expectedPath {
+				t.Errorf("expected %s %s, got %s %s", tt.expectedMethod, tt.expectedPath, gotMethod, gotPath)
+			}
+
+			if gotBody != "<!-- marker -->\nnew results" {
+				t.Errorf("unexpected note body %q", gotBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package host
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Application is an application that is installed outside of the package
+// manager of the operating system, such as with snap or flatpak
+type Application struct {
+	// Kind is how the application is installed, being "snap" or "flatpak"
+	Kind    string
+	Name    string
+	Version string
+	// Path is where the application is installed
+	Path string
+}
+
+// Upstream is the package that an application is a release of, which is
+// what advisories are published for
+type Upstream struct {
+	Ecosystem string
+	Name      string
+}
+
+// Upstreams maps applications, keyed by their kind and name, to the packages
+// that they are releases of with the same versions. Applications are usually
+// published without any record of what they are built from, so only those
+// listed here can be checked against advisories.
+var Upstreams = map[string]Upstream{
+	"snap:go":        {Ecosystem: "Go", Name: "stdlib"},
+	"snap:kubectl":   {Ecosystem: "Go", Name: "k8s.io/kubernetes"},
+	"snap:kubelet":   {Ecosystem: "Go", Name: "k8s.io/kubernetes"},
+	"snap:kubeadm":   {Ecosystem: "Go", Name: "k8s.io/kubernetes"},
+	"snap:helm":      {Ecosystem: "Go", Name: "helm.sh/helm/v3"},
+	"snap:terraform": {Ecosystem: "Go", Name: "github.com/hashicorp/terraform"},
+	"snap:gh":        {Ecosystem: "Go", Name: "github.com/cli/cli/v2"},
+	"snap:hugo":      {Ecosystem: "Go", Name: "github.com/gohugoio/hugo"},
+	"snap:yq":        {Ecosystem: "Go", Name: "github.com/mikefarah/yq/v4"},
+	"snap:lxd":       {Ecosystem: "Go", Name: "github.com/canonical/lxd"},
+	"snap:certbot":   {Ecosystem: "PyPI", Name: "certbot"},
+	"snap:ansible":   {Ecosystem: "PyPI", Name: "ansible"},
+}
+
+// UpstreamOf returns the package that the application is a release of
+func UpstreamOf(app Application) (Upstream, bool) {
+	upstream, ok := Upstreams[app.Kind+":"+app.Name]
+
+	return upstream, ok
+}
+
+// snapYaml is the metadata of a snap, from its meta/snap.yaml file
+type snapYaml struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+	Type    string `yaml:"type"`
+}
+
+// ListSnaps returns the snaps installed on the filesystem at root, other than
+// the snaps that provide the system itself, such as bases and kernels, whose
+// packages are audited with the rest of the operating system
+func ListSnaps(root string) ([]Application, error) {
+	dirs, err := os.ReadDir(filepath.Join(root, "snap"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not list snaps: %w", err)
+	}
+
+	var apps []Application
+	for _, dir := range dirs {
+		// the current revision of each snap is linked to as "current"
+		path := filepath.Join(root, "snap", dir.Name(), "current")
+		content, err := os.ReadFile(filepath.Join(path, "meta", "snap.yaml"))
+		if err != nil {
+			continue
+		}
+
+		var snap snapYaml
+		if err := yaml.Unmarshal(content, &snap); err != nil {
+			return nil, fmt.Errorf("could not parse snap %s: %w", dir.Name(), err)
+		}
+
+		switch snap.Type {
+		case "base", "core", "os", "snapd", "kernel", "gadget":
+			continue
+		}
+
+		apps = append(apps, Application{Kind: "snap", Name: snap.Name, Version: snap.Version, Path: path})
+	}
+
+	return apps, nil
+}
+
+// flatpakMetainfo is the AppStream metadata that a flatpak describes itself
+// with, including its releases newest first
+type flatpakMetainfo struct {
+	Releases []struct {
+		Version string `xml:"version,attr"`
+	} `xml:"releases>release"`
+}
+
+// flatpakVersion returns the version of the flatpak installed at path, from
+// the newest release in its AppStream metadata
+func flatpakVersion(path string, id string) string {
+	for _, name := range []string{id + ".metainfo.xml", id + ".appdata.xml"} {
+		for _, dir := range []string{"export/share/metainfo", "files/share/metainfo", "files/share/appdata"} {
+			content, err := os.ReadFile(filepath.Join(path, dir, name))
+			if err != nil {
+				continue
+			}
+
+			var metainfo flatpakMetainfo
+			if err := xml.Unmarshal(content, &metainfo); err != nil || len(metainfo.Releases) == 0 {
+				continue
+			}
+
+			return metainfo.Releases[0].Version
+		}
+	}
+
+	return ""
+}
+
+// ListFlatpaks returns the flatpak applications installed system-wide on the
+// filesystem at root, with those installed for a single user not included
+func ListFlatpaks(root string) ([]Application, error) {
+	dirs, err := os.ReadDir(filepath.Join(root, "var/lib/flatpak/app"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not list flatpaks: %w", err)
+	}
+
+	var apps []Application
+	for _, dir := range dirs {
+		// each application has a deployment for its current branch and commit
+		path := filepath.Join(root, "var/lib/flatpak/app", dir.Name(), "current", "active")
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+
+		apps = append(apps, Application{
+			Kind:    "flatpak",
+			Name:    dir.Name(),
+			Version: flatpakVersion(path, dir.Name()),
+			Path:    path,
+		})
+	}
+
+	return apps, nil
+}
+
+// ListApplications returns the snaps and flatpaks installed on the filesystem
+// at root, ordered by their kinds and names
+func ListApplications(root string) ([]Application, error) {
+	snaps, err := ListSnaps(root)
+	if err != nil {
+		return nil, err
+	}
+
+	flatpaks, err := ListFlatpaks(root)
+	if err != nil {
+		return nil, err
+	}
+
+	apps := append(snaps, flatpaks...)
+	sort.Slice(apps, func(i, j int) bool {
+		if apps[i].Kind != apps[j].Kind {
+			return apps[i].Kind < apps[j].Kind
+		}
+
+		return strings.ToLower(apps[i].Name) < strings.ToLower(apps[j].Name)
+	})
+
+	return apps, nil
+}
@@ -0,0 +1,178 @@
+// Package host reads what is installed on a machine, being the release of its
+// operating system, its kernel and its OS packages, so that virtual machines
+// and servers can be audited as well as the projects deployed to them
+package host
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+// ErrUnsupportedOS is returned for releases of operating systems that OSV
+// does not have advisories for, or whose packages cannot be read
+var ErrUnsupportedOS = errors.New("unsupported operating system")
+
+// KernelPackage is the name of the Linux kernel in the "Linux" ecosystem
+const KernelPackage = "Kernel"
+
+// KernelEcosystem is the ecosystem of advisories for the upstream Linux kernel
+const KernelEcosystem = "Linux"
+
+// kernelVersionRegexp matches the upstream version at the start of a kernel
+// release, such as "6.1.55" in "6.1.55-1-amd64"
+var kernelVersionRegexp = regexp.MustCompile(`^\d+\.\d+(\.\d+)?`)
+
+// OSRelease is the identification of an operating system from its os-release
+// file, as described by https://www.freedesktop.org/software/systemd/man/os-release.html
+type OSRelease struct {
+	ID              string
+	IDLike          []string
+	VersionID       string
+	VersionCodename string
+	PrettyName      string
+}
+
+// ParseOSRelease parses the content of an os-release file
+func ParseOSRelease(content string) OSRelease {
+	var release OSRelease
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, `"'`)
+
+		switch key {
+		case "ID":
+			release.ID = value
+		case "ID_LIKE":
+			release.IDLike = strings.Fields(value)
+		case "VERSION_ID":
+			release.VersionID = value
+		case "VERSION_CODENAME":
+			release.VersionCodename = value
+		case "PRETTY_NAME":
+			release.PrettyName = value
+		}
+	}
+
+	return release
+}
+
+// ReadOSRelease reads the os-release file of the filesystem at root, which is
+// "/" for the running host
+func ReadOSRelease(root string) (OSRelease, error) {
+	var lastErr error
+
+	for _, path := range []string{"etc/os-release", "usr/lib/os-release"} {
+		content, err := os.ReadFile(filepath.Join(root, path))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		return ParseOSRelease(string(content)), nil
+	}
+
+	return OSRelease{}, fmt.Errorf("could not read os-release: %w", lastErr)
+}
+
+// Name returns the name of the release for display
+func (o OSRelease) Name() string {
+	if o.PrettyName != "" {
+		return o.PrettyName
+	}
+
+	return strings.TrimSpace(o.ID + " " + o.VersionID)
+}
+
+// Ecosystem returns the OSV ecosystem that the packages of the release are
+// in, including the release so that advisories are matched to it
+func (o OSRelease) Ecosystem() (string, error) {
+	parts := strings.Split(o.VersionID, ".")
+
+	switch o.ID {
+	case "debian":
+		// testing and unstable do not have a version
+		if o.VersionID == "" {
+			return string(lockfile.DebianEcosystem), nil
+		}
+
+		return string(lockfile.DebianEcosystem) + ":" + parts[0], nil
+	case "ubuntu":
+		if o.VersionID == "" {
+			return "", fmt.Errorf("%w: %s does not have a version", ErrUnsupportedOS, o.Name())
+		}
+
+		// long term support releases are the April releases of even years
+		year, err := strconv.Atoi(parts[0])
+		if err == nil && len(parts) == 2 && parts[1] == "04" && year%2 == 0 {
+			return "Ubuntu:" + o.VersionID + ":LTS", nil
+		}
+
+		return "Ubuntu:" + o.VersionID, nil
+	case "alpine":
+		// edge does not have a version, and only the branch is used for others
+		if len(parts) < 2 {
+			return string(lockfile.AlpineEcosystem), nil
+		}
+
+		return string(lockfile.AlpineEcosystem) + ":v" + parts[0] + "." + parts[1], nil
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedOS, o.Name())
+}
+
+// PackageDatabase returns the path within a root filesystem of the database
+// of the OS packages that are installed by the release, and how to parse it
+func (o OSRelease) PackageDatabase() (string, lockfile.PackageDetailsParser, error) {
+	switch o.ID {
+	case "debian", "ubuntu":
+		return "var/lib/dpkg/status", lockfile.ParseDpkgStatus, nil
+	case "alpine":
+		return "lib/apk/db/installed", lockfile.ParseApkInstalled, nil
+	}
+
+	return "", nil, fmt.Errorf("%w: %s", ErrUnsupportedOS, o.Name())
+}
+
+// KernelReleasePath is the path within a root filesystem of the release of
+// the running kernel, which only exists for the running host
+const KernelReleasePath = "proc/sys/kernel/osrelease"
+
+// KernelRelease returns the release of the kernel that is running, such as
+// "6.1.0-13-amd64"
+func KernelRelease(root string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(root, KernelReleasePath))
+	if err != nil {
+		return "", fmt.Errorf("could not read kernel release: %w", err)
+	}
+
+	return strings.TrimSpace(string(content)), nil
+}
+
+// KernelVersion returns the upstream version of a kernel release, being "" if
+// the release does not start with one
+func KernelVersion(release string) string {
+	version := kernelVersionRegexp.FindString(release)
+	if version != "" && strings.Count(version, ".") == 1 {
+		version += ".0"
+	}
+
+	return version
+}
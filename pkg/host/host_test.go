@@ -0,0 +1,178 @@
+package host_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/host"
+)
+
+func TestReadOSRelease(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		root string
+		want host.OSRelease
+	}{
+		{
+			root: "fixtures/debian",
+			want: host.OSRelease{
+				ID:              "debian",
+				VersionID:       "11",
+				VersionCodename: "bullseye",
+				PrettyName:      "Debian GNU/Linux 11 (bullseye)",
+			},
+		},
+		{
+			// only has /usr/lib/os-release
+			root: "fixtures/unknown",
+			want: host.OSRelease{
+				ID:         "example",
+				IDLike:     []string{"rhel", "fedora"},
+				VersionID:  "9.2",
+				PrettyName: "Example Linux 9.2",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.root, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := host.ReadOSRelease(tt.root)
+			if err != nil {
+				t.Fatalf("ReadOSRelease() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ReadOSRelease() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadOSRelease_Missing(t *testing.T) {
+	t.Parallel()
+
+	if _, err := host.ReadOSRelease("fixtures/does-not-exist"); err == nil {
+		t.Errorf("ReadOSRelease() expected an error")
+	}
+}
+
+func TestOSRelease_Ecosystem(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		release host.OSRelease
+		want    string
+		wantErr error
+	}{
+		{name: "debian", release: host.OSRelease{ID: "debian", VersionID: "11"}, want: "Debian:11"},
+		{name: "debian point release", release: host.OSRelease{ID: "debian", VersionID: "12.1"}, want: "Debian:12"},
+		{name: "debian testing", release: host.OSRelease{ID: "debian"}, want: "Debian"},
+		{name: "ubuntu lts", release: host.OSRelease{ID: "ubuntu", VersionID: "22.04"}, want: "Ubuntu:22.04:LTS"},
+		{name: "ubuntu interim", release: host.OSRelease{ID: "ubuntu", VersionID: "23.04"}, want: "Ubuntu:23.04"},
+		{name: "ubuntu without version", release: host.OSRelease{ID: "ubuntu"}, wantErr: host.ErrUnsupportedOS},
+		{name: "alpine", release: host.OSRelease{ID: "alpine", VersionID: "3.18.4"}, want: "Alpine:v3.18"},
+		{name: "alpine edge", release: host.OSRelease{ID: "alpine", VersionID: "20230901"}, want: "Alpine"},
+		{name: "unknown", release: host.OSRelease{ID: "example", VersionID: "9.2"}, wantErr: host.ErrUnsupportedOS},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.release.Ecosystem()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Ecosystem() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Ecosystem() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKernelVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"5.10.0-23-amd64":               "5.10.0",
+		"6.1.55-1-lts":                  "6.1.55",
+		"6.5-rc1":                       "6.5.0",
+		"5.15.133.1-microsoft-standard": "5.15.133",
+		"unknown":                       "",
+	}
+
+	for release, want := range tests {
+		if got := host.KernelVersion(release); got != want {
+			t.Errorf("KernelVersion(%q) = %q, want %q", release, got, want)
+		}
+	}
+}
+
+func TestKernelRelease(t *testing.T) {
+	t.Parallel()
+
+	got, err := host.KernelRelease("fixtures/debian")
+	if err != nil {
+		t.Fatalf("KernelRelease() error = %v", err)
+	}
+	if got != "5.10.0-23-amd64" {
+		t.Errorf("KernelRelease() = %q, want %q", got, "5.10.0-23-amd64")
+	}
+
+	if _, err := host.KernelRelease("fixtures/alpine"); err == nil {
+		t.Errorf("KernelRelease() expected an error for a root without a running kernel")
+	}
+}
+
+func TestListApplications(t *testing.T) {
+	t.Parallel()
+
+	got, err := host.ListApplications("fixtures/ubuntu")
+	if err != nil {
+		t.Fatalf("ListApplications() error = %v", err)
+	}
+
+	want := []host.Application{
+		{
+			Kind:    "flatpak",
+			Name:    "org.gimp.GIMP",
+			Version: "2.10.34",
+			Path:    "fixtures/ubuntu/var/lib/flatpak/app/org.gimp.GIMP/current/active",
+		},
+		{Kind: "snap", Name: "kubectl", Version: "1.28.2", Path: "fixtures/ubuntu/snap/kubectl/current"},
+		{Kind: "snap", Name: "spotify", Version: "1.2.20.1210.g06e2a77a", Path: "fixtures/ubuntu/snap/spotify/current"},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ListApplications() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestListApplications_None(t *testing.T) {
+	t.Parallel()
+
+	got, err := host.ListApplications("fixtures/debian")
+	if err != nil {
+		t.Fatalf("ListApplications() error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("ListApplications() = %v, want none", got)
+	}
+}
+
+func TestListServiceExecutables(t *testing.T) {
+	t.Parallel()
+
+	got := host.ListServiceExecutables("fixtures/ubuntu")
+	want := []string{"fixtures/ubuntu/opt/acme/bin/acme-agent"}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ListServiceExecutables() mismatch (-want +got):\n%s", diff)
+	}
+}
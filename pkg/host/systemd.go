@@ -0,0 +1,84 @@
+package host
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// unitDirectories are where systemd units are installed, by administrators
+// and by packages
+var unitDirectories = []string{"etc/systemd/system", "usr/lib/systemd/system", "lib/systemd/system"}
+
+// unmanagedPrefixes are the directories that software is installed into by
+// hand rather than by the package manager of the operating system
+var unmanagedPrefixes = []string{"/usr/local/", "/opt/", "/srv/", "/home/"}
+
+// execStartPath returns the executable that an ExecStart line of a unit runs,
+// without the prefixes that change how systemd runs it
+func execStartPath(value string) string {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return strings.TrimLeft(fields[0], "-@:+!")
+}
+
+// ListServiceExecutables returns the executables that the systemd services
+// of the filesystem at root run which were not installed by the package
+// manager, as their paths on that filesystem
+func ListServiceExecutables(root string) []string {
+	seen := map[string]bool{}
+	var executables []string
+
+	for _, dir := range unitDirectories {
+		units, err := filepath.Glob(filepath.Join(root, dir, "*.service"))
+		if err != nil {
+			continue
+		}
+
+		for _, unit := range units {
+			file, err := os.Open(unit)
+			if err != nil {
+				continue
+			}
+
+			scanner := bufio.NewScanner(file)
+			for scanner.Scan() {
+				key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+				if !ok || strings.TrimSpace(key) != "ExecStart" {
+					continue
+				}
+
+				path := execStartPath(value)
+				if !isUnmanaged(path) || seen[path] {
+					continue
+				}
+				seen[path] = true
+
+				executable := filepath.Join(root, path)
+				if info, err := os.Stat(executable); err == nil && info.Mode().IsRegular() {
+					executables = append(executables, executable)
+				}
+			}
+			file.Close()
+		}
+	}
+
+	sort.Strings(executables)
+
+	return executables
+}
+
+func isUnmanaged(path string) bool {
+	for _, prefix := range unmanagedPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,230 @@
+// Package licenses parses SPDX license expressions, such as
+// "(MIT OR Apache-2.0) AND BSD-3-Clause", and evaluates them against policies
+// of which licenses are allowed and denied.
+package licenses
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidExpression is returned when a license expression is not valid
+var ErrInvalidExpression = errors.New("invalid license expression")
+
+// Expression is a parsed SPDX license expression, being either a License or a
+// Compound of other expressions
+type Expression interface {
+	// String returns the expression in its normalised form
+	String() string
+}
+
+// License is a single license in an expression, optionally with an exception
+// to it, such as "GPL-2.0-or-later WITH Classpath-exception-2.0"
+type License struct {
+	// ID is the SPDX identifier of the license, or a "LicenseRef-" for
+	// licenses that are not on the SPDX license list
+	ID string
+	// Exception is the SPDX identifier of the exception, if there is one
+	Exception string
+}
+
+func (l License) String() string {
+	if l.Exception != "" {
+		return l.ID + " WITH " + l.Exception
+	}
+
+	return l.ID
+}
+
+// Operator is how the expressions of a Compound are combined
+type Operator string
+
+const (
+	// And requires all of the licenses to be complied with
+	And Operator = "AND"
+	// Or allows a choice of which license to comply with
+	Or Operator = "OR"
+)
+
+// Compound combines expressions, with all of them having to be complied with
+// for And, or any one of them for Or
+type Compound struct {
+	Operator Operator
+	Operands []Expression
+}
+
+func (c Compound) String() string {
+	parts := make([]string, 0, len(c.Operands))
+	for _, operand := range c.Operands {
+		if compound, ok := operand.(Compound); ok && compound.Operator != c.Operator {
+			parts = append(parts, "("+compound.String()+")")
+		} else {
+			parts = append(parts, operand.String())
+		}
+	}
+
+	return strings.Join(parts, " "+string(c.Operator)+" ")
+}
+
+// deprecatedGNU matches the deprecated identifiers of the GNU licenses, which
+// do not say if later versions are allowed unless followed by a "+"
+var deprecatedGNU = regexp.MustCompile(`(?i)^(A?GPL|LGPL|GFDL)-(\d\.\d)(\+?)$`)
+
+// normaliseID replaces deprecated identifiers with their current ones, and the
+// "+" suffix of the GNU licenses with "-or-later"
+func normaliseID(id string) string {
+	if match := deprecatedGNU.FindStringSubmatch(id); match != nil {
+		name := strings.ToUpper(match[1])
+		if match[3] == "+" {
+			return name + "-" + match[2] + "-or-later"
+		}
+
+		return name + "-" + match[2] + "-only"
+	}
+
+	return id
+}
+
+// tokenize splits an expression into its parentheses, operators and license
+// identifiers
+func tokenize(expression string) []string {
+	expression = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expression)
+
+	return strings.Fields(expression)
+}
+
+// operator returns the operator that the token is, if it is one; operators
+// are either all upper or all lower case
+func operator(token string) string {
+	switch token {
+	case "AND", "and":
+		return "AND"
+	case "OR", "or":
+		return "OR"
+	case "WITH", "with":
+		return "WITH"
+	default:
+		return ""
+	}
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+
+	return ""
+}
+
+func (p *parser) next() string {
+	token := p.peek()
+	p.pos++
+
+	return token
+}
+
+// parseOr parses operands joined by OR, which binds the loosest
+func (p *parser) parseOr() (Expression, error) {
+	return p.parseCompound(Or, p.parseAnd)
+}
+
+// parseAnd parses operands joined by AND, which binds tighter than OR
+func (p *parser) parseAnd() (Expression, error) {
+	return p.parseCompound(And, p.parseWith)
+}
+
+func (p *parser) parseCompound(op Operator, operand func() (Expression, error)) (Expression, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	operands := []Expression{first}
+	for operator(p.peek()) == string(op) {
+		p.next()
+
+		next, err := operand()
+		if err != nil {
+			return nil, err
+		}
+
+		// flatten nested operands of the same operator, as they are associative
+		if compound, ok := next.(Compound); ok && compound.Operator == op {
+			operands = append(operands, compound.Operands...)
+		} else {
+			operands = append(operands, next)
+		}
+	}
+
+	if len(operands) == 1 {
+		return first, nil
+	}
+
+	return Compound{Operator: op, Operands: operands}, nil
+}
+
+// parseWith parses a license with an optional exception, or a parenthesised
+// expression
+func (p *parser) parseWith() (Expression, error) {
+	token := p.next()
+
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("%w: expected a license but the expression ended", ErrInvalidExpression)
+	case token == "(":
+		expression, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("%w: missing closing parenthesis", ErrInvalidExpression)
+		}
+
+		return expression, nil
+	case token == ")" || operator(token) != "":
+		return nil, fmt.Errorf("%w: expected a license but got %q", ErrInvalidExpression, token)
+	}
+
+	license := License{ID: normaliseID(token)}
+
+	if operator(p.peek()) == "WITH" {
+		p.next()
+
+		exception := p.next()
+		if exception == "" || exception == "(" || exception == ")" || operator(exception) != "" {
+			return nil, fmt.Errorf("%w: expected an exception after WITH", ErrInvalidExpression)
+		}
+		license.Exception = exception
+	}
+
+	return license, nil
+}
+
+// Parse parses an SPDX license expression, in which WITH binds tighter than
+// AND, which binds tighter than OR. Deprecated identifiers of the GNU
+// licenses, such as "GPL-2.0+", are replaced with their current ones.
+func Parse(expression string) (Expression, error) {
+	p := &parser{tokens: tokenize(expression)}
+
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("%w: the expression is empty", ErrInvalidExpression)
+	}
+
+	parsed, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidExpression, p.peek())
+	}
+
+	return parsed, nil
+}
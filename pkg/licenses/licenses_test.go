@@ -0,0 +1,188 @@
+package licenses_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/licenses"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expression string
+		want       string
+	}{
+		{expression: "MIT", want: "MIT"},
+		{expression: "MIT OR Apache-2.0", want: "MIT OR Apache-2.0"},
+		{expression: "mit or apache-2.0", want: "mit OR apache-2.0"},
+		{expression: "MIT AND BSD-3-Clause OR Apache-2.0", want: "(MIT AND BSD-3-Clause) OR Apache-2.0"},
+		{expression: "MIT AND (BSD-3-Clause OR Apache-2.0)", want: "MIT AND (BSD-3-Clause OR Apache-2.0)"},
+		{expression: "((MIT))", want: "MIT"},
+		{expression: "(MIT OR ISC) OR Apache-2.0", want: "MIT OR ISC OR Apache-2.0"},
+		{expression: "GPL-2.0+ WITH Classpath-exception-2.0 OR MIT", want: "GPL-2.0-or-later WITH Classpath-exception-2.0 OR MIT"},
+		{expression: "LGPL-2.1", want: "LGPL-2.1-only"},
+		{expression: "LicenseRef-Proprietary", want: "LicenseRef-Proprietary"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.expression, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := licenses.Parse(tt.expression)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("Parse() = %q, want %q", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []string{
+		"",
+		"MIT OR",
+		"AND MIT",
+		"(MIT OR Apache-2.0",
+		"MIT OR Apache-2.0)",
+		"MIT Apache-2.0",
+		"MIT WITH",
+		"MIT WITH (Classpath-exception-2.0)",
+	}
+
+	for _, expression := range tests {
+		expression := expression
+		t.Run(expression, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := licenses.Parse(expression)
+			if !errors.Is(err, licenses.ErrInvalidExpression) {
+				t.Errorf("expected ErrInvalidExpression but got %v", err)
+			}
+		})
+	}
+}
+
+func TestPolicy_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		allow      []string
+		deny       []string
+		expression string
+		want       licenses.Verdict
+	}{
+		{
+			name:       "no policy",
+			expression: "GPL-3.0-only",
+			want:       licenses.Verdict{Allowed: true, Licenses: []string{"GPL-3.0-only"}},
+		},
+		{
+			name:       "allowed",
+			allow:      []string{"MIT"},
+			expression: "mit",
+			want:       licenses.Verdict{Allowed: true, Licenses: []string{"mit"}},
+		},
+		{
+			name:       "not allowed",
+			allow:      []string{"MIT"},
+			expression: "ISC",
+			want:       licenses.Verdict{Allowed: false, Licenses: []string{"ISC"}},
+		},
+		{
+			name:       "dual licensed with one denied",
+			deny:       []string{"GPL-3.0-only"},
+			expression: "GPL-3.0-only OR MIT",
+			want:       licenses.Verdict{Allowed: true, Licenses: []string{"MIT"}},
+		},
+		{
+			name:       "dual licensed with both denied",
+			deny:       []string{"GPL-3.0-only", "AGPL-3.0-only"},
+			expression: "GPL-3.0-only OR AGPL-3.0-only",
+			want:       licenses.Verdict{Allowed: false, Licenses: []string{"GPL-3.0-only", "AGPL-3.0-only"}},
+		},
+		{
+			name:       "conjunction with one denied",
+			deny:       []string{"GPL-3.0-only"},
+			expression: "MIT AND GPL-3.0-only",
+			want:       licenses.Verdict{Allowed: false, Licenses: []string{"GPL-3.0-only"}},
+		},
+		{
+			name:       "nested choice",
+			allow:      []string{"MIT", "Apache-2.0"},
+			expression: "MIT AND (GPL-2.0-only OR Apache-2.0)",
+			want:       licenses.Verdict{Allowed: true, Licenses: []string{"MIT", "Apache-2.0"}},
+		},
+		{
+			name:       "deprecated identifiers",
+			deny:       []string{"GPL-2.0"},
+			expression: "GPL-2.0-only",
+			want:       licenses.Verdict{Allowed: false, Licenses: []string{"GPL-2.0-only"}},
+		},
+		{
+			name:       "denied license with any exception",
+			deny:       []string{"GPL-2.0-only"},
+			expression: "GPL-2.0-only WITH Classpath-exception-2.0",
+			want:       licenses.Verdict{Allowed: false, Licenses: []string{"GPL-2.0-only WITH Classpath-exception-2.0"}},
+		},
+		{
+			name:       "allowed specific exception of a denied license",
+			allow:      []string{"MIT", "GPL-2.0-only WITH Classpath-exception-2.0"},
+			deny:       []string{"GPL-2.0-only"},
+			expression: "GPL-2.0-only WITH Classpath-exception-2.0",
+			want:       licenses.Verdict{Allowed: true, Licenses: []string{"GPL-2.0-only WITH Classpath-exception-2.0"}},
+		},
+		{
+			name:       "allowed specific exception does not allow the license",
+			allow:      []string{"GPL-2.0-only WITH Classpath-exception-2.0"},
+			expression: "GPL-2.0-only",
+			want:       licenses.Verdict{Allowed: false, Licenses: []string{"GPL-2.0-only"}},
+		},
+		{
+			name:       "denial takes precedence",
+			allow:      []string{"MIT"},
+			deny:       []string{"MIT"},
+			expression: "MIT",
+			want:       licenses.Verdict{Allowed: false, Licenses: []string{"MIT"}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			policy, err := licenses.NewPolicy(tt.allow, tt.deny)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			expression, err := licenses.Parse(tt.expression)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, policy.Evaluate(expression)); diff != "" {
+				t.Errorf("Evaluate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewPolicy_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := licenses.NewPolicy([]string{"MIT OR Apache-2.0"}, nil)
+	if !errors.Is(err, licenses.ErrInvalidExpression) {
+		t.Errorf("expected ErrInvalidExpression but got %v", err)
+	}
+}
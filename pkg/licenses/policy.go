@@ -0,0 +1,134 @@
+package licenses
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Policy decides which licenses are acceptable, by licenses being denied or,
+// when there are any allowed licenses, by them not being allowed
+type Policy struct {
+	allow []License
+	deny  []License
+}
+
+// Verdict is the result of evaluating an expression against a policy
+type Verdict struct {
+	// Allowed is if the expression can be complied with under the policy
+	Allowed bool
+	// Licenses are those that would be complied with if the expression is
+	// allowed, or otherwise those that stopped it from being allowed
+	Licenses []string
+}
+
+func parseEntries(entries []string) ([]License, error) {
+	licenses := make([]License, 0, len(entries))
+
+	for _, entry := range entries {
+		parsed, err := Parse(entry)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", entry, err)
+		}
+
+		license, ok := parsed.(License)
+		if !ok {
+			return nil, fmt.Errorf("%q: %w: policies must list single licenses", entry, ErrInvalidExpression)
+		}
+
+		licenses = append(licenses, license)
+	}
+
+	return licenses, nil
+}
+
+// NewPolicy creates a policy from lists of allowed and denied licenses, which
+// are either SPDX identifiers, matching the license with or without any
+// exception, or a license with a specific exception, like
+// "GPL-2.0-only WITH Classpath-exception-2.0"
+func NewPolicy(allow []string, deny []string) (Policy, error) {
+	allowed, err := parseEntries(allow)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	denied, err := parseEntries(deny)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	return Policy{allow: allowed, deny: denied}, nil
+}
+
+// find returns if the license is listed, either with its specific exception
+// or as the license with any exception
+func find(list []License, license License) (specific bool, general bool) {
+	for _, entry := range list {
+		if !strings.EqualFold(entry.ID, license.ID) {
+			continue
+		}
+
+		if entry.Exception == "" {
+			general = true
+		} else if strings.EqualFold(entry.Exception, license.Exception) {
+			specific = true
+		}
+	}
+
+	return specific, general
+}
+
+// allows reports if the policy allows the license, with entries for the
+// license with its specific exception taking precedence over those for the
+// license in general, and denials taking precedence over allowances
+func (p Policy) allows(license License) bool {
+	specificDeny, generalDeny := find(p.deny, license)
+	specificAllow, generalAllow := find(p.allow, license)
+
+	switch {
+	case specificDeny:
+		return false
+	case specificAllow:
+		return true
+	case generalDeny:
+		return false
+	case generalAllow:
+		return true
+	}
+
+	return len(p.allow) == 0
+}
+
+// Evaluate reports if the expression can be complied with under the policy,
+// with AND requiring all of its licenses to be allowed, while OR only needs
+// one of its alternatives to be, preferring the first that is
+func (p Policy) Evaluate(expression Expression) Verdict {
+	switch expression := expression.(type) {
+	case License:
+		return Verdict{Allowed: p.allows(expression), Licenses: []string{expression.String()}}
+	case Compound:
+		var allowed, denied []string
+
+		for _, operand := range expression.Operands {
+			verdict := p.Evaluate(operand)
+
+			if !verdict.Allowed {
+				denied = append(denied, verdict.Licenses...)
+
+				continue
+			}
+
+			if expression.Operator == Or {
+				return verdict
+			}
+			allowed = append(allowed, verdict.Licenses...)
+		}
+
+		if len(denied) > 0 {
+			return Verdict{Allowed: false, Licenses: denied}
+		}
+
+		return Verdict{Allowed: true, Licenses: allowed}
+	}
+
+	return Verdict{}
+}
@@ -3,6 +3,7 @@ package lockfile
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
@@ -71,13 +72,11 @@ func parseApkPackageGroup(group []string, pathToLockfile string) PackageDetails
 }
 
 func ParseApkInstalled(pathToLockfile string) ([]PackageDetails, error) {
-	file, err := os.Open(pathToLockfile)
-	if err != nil {
-		return []PackageDetails{}, fmt.Errorf("could not open %s: %w", pathToLockfile, err)
-	}
-	defer file.Close()
+	return openLockfile(pathToLockfile, parseApkInstalledContent)
+}
 
-	scanner := bufio.NewScanner(file)
+func parseApkInstalledContent(pathToLockfile string, content io.Reader) ([]PackageDetails, error) {
+	scanner := bufio.NewScanner(content)
 
 	packageGroups := groupApkPackageLines(scanner)
 
@@ -109,7 +108,7 @@ func ParseApkInstalled(pathToLockfile string) ([]PackageDetails, error) {
 // FromApkInstalled attempts to parse the given file as an "apk-installed" lockfile
 // used by the Alpine Package Keeper (apk) to record installed packages.
 func FromApkInstalled(pathToInstalled string) (Lockfile, error) {
-	packages, err := ParseApkInstalled(pathToInstalled)
+	packages, err := safeParse(ParseApkInstalled, pathToInstalled)
 
 	sort.Slice(packages, func(i, j int) bool {
 		if packages[i].Name == packages[j].Name {
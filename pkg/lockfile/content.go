@@ -0,0 +1,83 @@
+package lockfile
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+// PackageDetailsContentParser parses the content of a lockfile that has
+// already been read, which lets lockfiles be parsed where there is no
+// filesystem to read them from, such as in a browser. The path is only used to
+// describe the lockfile in errors.
+type PackageDetailsContentParser = func(pathToLockfile string, content io.Reader) ([]PackageDetails, error)
+
+// this is an optimisation and read-only
+var contentParsers = map[string]PackageDetailsContentParser{
+	"buildscript-gradle.lockfile": parseGradleLockContent,
+	"Cargo.lock":                  parseCargoLockContent,
+	"composer.lock":               parseComposerLockContent,
+	"conan.lock":                  parseConanLockContent,
+	"Gemfile.lock":                parseGemfileLockContent,
+	"go.mod":                      parseGoLockContent,
+	"gradle.lockfile":             parseGradleLockContent,
+	"mix.lock":                    parseMixLockContent,
+	"Pipfile.lock":                parsePipenvLockContent,
+	"package-lock.json":           parseNpmLockContent,
+	"packages.lock.json":          parseNuGetLockContent,
+	"pnpm-lock.yaml":              parsePnpmLockContent,
+	"poetry.lock":                 parsePoetryLockContent,
+	"pom.xml":                     parseMavenLockContent,
+	"pubspec.lock":                parsePubspecLockContent,
+	"requirements.txt":            parseRequirementsTxtContent,
+	"yarn.lock":                   parseYarnLockContent,
+}
+
+// FindContentParser returns the parser for the content of a lockfile, in the
+// same way that FindParser returns the parser for a lockfile on disk
+func FindContentParser(pathToLockfile string, parseAs string) (PackageDetailsContentParser, string) {
+	_, parsedAs := FindParser(pathToLockfile, parseAs)
+
+	return contentParsers[parsedAs], parsedAs
+}
+
+// ParseContent extracts the package details from the content of a lockfile,
+// in the same way that Parse does for a lockfile on disk. Files next to the
+// lockfile that some parsers read for more information, such as the Gemfile
+// of a Gemfile.lock, are not read.
+func ParseContent(pathToLockfile string, parseAs string, content []byte) (Lockfile, error) {
+	parser, parsedAs := FindContentParser(pathToLockfile, parseAs)
+
+	if parser == nil {
+		return Lockfile{}, parserNotFound(pathToLockfile, parseAs)
+	}
+
+	return parseWith(func(pathToLockfile string) ([]PackageDetails, error) {
+		return parser(pathToLockfile, bytes.NewReader(content))
+	}, pathToLockfile, parseAs, parsedAs)
+}
+
+// readLockfile parses the lockfile at the given path with the content parser,
+// with the lockfile being described as not readable if it cannot be opened
+func readLockfile(pathToLockfile string, parse PackageDetailsContentParser) ([]PackageDetails, error) {
+	file, err := os.Open(pathToLockfile)
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not read %s: %w", pathToLockfile, err)
+	}
+	defer file.Close()
+
+	return parse(pathToLockfile, file)
+}
+
+// openLockfile parses the lockfile at the given path with the content parser,
+// for the parsers that describe a lockfile that cannot be opened as such
+func openLockfile(pathToLockfile string, parse PackageDetailsContentParser) ([]PackageDetails, error) {
+	file, err := os.Open(pathToLockfile)
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not open %s: %w", pathToLockfile, err)
+	}
+	defer file.Close()
+
+	return parse(pathToLockfile, file)
+}
@@ -0,0 +1,86 @@
+package lockfile_test
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestFindContentParser(t *testing.T) {
+	t.Parallel()
+
+	for _, file := range lockfile.ListParsers() {
+		parser, parsedAs := lockfile.FindContentParser("/path/to/my/"+file, "")
+
+		if parser == nil {
+			t.Errorf("Expected a content parser to be found for %s but did not", file)
+		}
+
+		if file != parsedAs {
+			t.Errorf("Expected parsedAs to be %s but got %s instead", file, parsedAs)
+		}
+	}
+}
+
+func TestParseContent_ParserNotFound(t *testing.T) {
+	t.Parallel()
+
+	_, err := lockfile.ParseContent("/path/to/my/", "", []byte{})
+
+	if !errors.Is(err, lockfile.ErrParserNotFound) {
+		t.Errorf("Did not get the expected ErrParserNotFound error - got %v instead", err)
+	}
+}
+
+func TestParseContent_MatchesParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path    string
+		parseAs string
+	}{
+		{path: "fixtures/npm/one-package-dev.v2.json", parseAs: "package-lock.json"},
+		{path: "fixtures/yarn/two-packages.v1.lock", parseAs: "yarn.lock"},
+		{path: "fixtures/cargo/two-packages.lock", parseAs: "Cargo.lock"},
+		{path: "fixtures/pip/multiple-packages-mixed.txt", parseAs: "requirements.txt"},
+		{path: "fixtures/maven/interpolation.xml", parseAs: "pom.xml"},
+		{path: "fixtures/gradle/5-pkg", parseAs: "gradle.lockfile"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			expected, err := lockfile.Parse(tt.path, tt.parseAs)
+			if err != nil {
+				t.Fatalf("Got unexpected error: %v", err)
+			}
+
+			content, err := os.ReadFile(tt.path)
+			if err != nil {
+				t.Fatalf("could not read fixture: %v", err)
+			}
+
+			actual, err := lockfile.ParseContent(tt.path, tt.parseAs, content)
+			if err != nil {
+				t.Fatalf("Got unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(expected, actual) {
+				t.Errorf("Expected parsing the content to be the same as parsing the file\nwant: %+v\ngot:  %+v", expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseContent_InvalidContent(t *testing.T) {
+	t.Parallel()
+
+	_, err := lockfile.ParseContent("package-lock.json", "", []byte("this is not json"))
+
+	expectErrContaining(t, err, "could not parse package-lock.json")
+}
@@ -0,0 +1,111 @@
+package lockfile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const DebianEcosystem Ecosystem = "Debian"
+
+// parseDpkgStatusParagraph returns the package described by a paragraph of a
+// dpkg status file, along with if it is installed. Debian advisories are for
+// source packages, so the package is named and versioned after its source if
+// that is recorded.
+func parseDpkgStatusParagraph(paragraph []string) (PackageDetails, bool) {
+	var pkg = PackageDetails{
+		Ecosystem: DebianEcosystem,
+		CompareAs: DebianEcosystem,
+	}
+	installed := false
+	source := ""
+
+	// File SPECS: https://man7.org/linux/man-pages/man5/deb-control.5.html
+	for _, line := range paragraph {
+		switch {
+		case strings.HasPrefix(line, "Package:"):
+			pkg.Name = strings.TrimSpace(strings.TrimPrefix(line, "Package:"))
+		case strings.HasPrefix(line, "Version:"):
+			pkg.Version = strings.TrimSpace(strings.TrimPrefix(line, "Version:"))
+		case strings.HasPrefix(line, "Source:"):
+			source = strings.TrimSpace(strings.TrimPrefix(line, "Source:"))
+		case strings.HasPrefix(line, "Status:"):
+			status := strings.Fields(strings.TrimPrefix(line, "Status:"))
+			installed = len(status) > 0 && status[len(status)-1] == "installed"
+		}
+	}
+
+	// the source is either just its name, or its name and version if that is
+	// different to the version of the binary package, as in "glibc (2.31-13)"
+	if source != "" {
+		name, version, hasVersion := strings.Cut(source, " ")
+		pkg.Name = name
+		if hasVersion {
+			pkg.Version = strings.Trim(strings.TrimSpace(version), "()")
+		}
+	}
+
+	return pkg, installed
+}
+
+func ParseDpkgStatus(pathToLockfile string) ([]PackageDetails, error) {
+	return openLockfile(pathToLockfile, parseDpkgStatusContent)
+}
+
+func parseDpkgStatusContent(pathToLockfile string, content io.Reader) ([]PackageDetails, error) {
+	scanner := bufio.NewScanner(content)
+	// descriptions of packages can have long lines
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	// paragraphs are separated by blank lines, the same as the records of an
+	// apk installed file
+	paragraphs := groupApkPackageLines(scanner)
+
+	packages := make([]PackageDetails, 0, len(paragraphs))
+	// binary packages that are built from the same source are only included once
+	seen := make(map[string]bool)
+
+	for _, paragraph := range paragraphs {
+		pkg, installed := parseDpkgStatusParagraph(paragraph)
+
+		if !installed || pkg.Name == "" || pkg.Version == "" {
+			continue
+		}
+
+		key := pkg.Name + "@" + pkg.Version
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		packages = append(packages, pkg)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return packages, fmt.Errorf("error while scanning %s: %w", pathToLockfile, err)
+	}
+
+	return packages, nil
+}
+
+// FromDpkgStatus attempts to parse the given file as a "dpkg-status" file used
+// by the Debian package manager (dpkg) to record installed packages.
+func FromDpkgStatus(pathToStatus string) (Lockfile, error) {
+	packages, err := safeParse(ParseDpkgStatus, pathToStatus)
+
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].Name == packages[j].Name {
+			return packages[i].Version < packages[j].Version
+		}
+
+		return packages[i].Name < packages[j].Name
+	})
+
+	return Lockfile{
+		FilePath: pathToStatus,
+		ParsedAs: "dpkg-status",
+		Packages: packages,
+	}, err
+}
@@ -0,0 +1,7 @@
+module my-library
+
+go 1.21
+
+toolchain go1.21.0
+
+require github.com/BurntSushi/toml v1.0.0
@@ -0,0 +1,7 @@
+module my-library
+
+go 1.21
+
+flux-capacitor 1.21
+
+require github.com/BurntSushi/toml v1.0.0
@@ -0,0 +1,130 @@
+package lockfile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// gemfileDefaultGroup is the group of gems that are not declared in any other
+const gemfileDefaultGroup = "default"
+
+var (
+	gemfileGemRegexp   = regexp.MustCompile(`^\s*gem\s*\(?\s*["']([^"']+)["'](.*)$`)
+	gemfileGroupRegexp = regexp.MustCompile(`^\s*group\s*\(?(.*?)\)?\s+do\b`)
+	// gemfileGroupOptionRegexp matches the "group" and "groups" options of a
+	// gem, in both the new and the hash rocket syntax
+	gemfileGroupOptionRegexp = regexp.MustCompile(`:?\bgroups?(?::|\s*=>)\s*(\[[^\]]*\]|:\w+|["'][^"']*["'])`)
+	gemfileSymbolRegexp      = regexp.MustCompile(`(?::|["'])(\w+)`)
+	gemfileBlockRegexp       = regexp.MustCompile(`\bdo(\s*\|[^|]*\|)?\s*(#.*)?$`)
+	gemfileEndRegexp         = regexp.MustCompile(`^\s*end\b`)
+	// gemfileKeywordRegexp matches the other constructs that are closed with
+	// an "end", so that they are not mistaken for the end of a group
+	gemfileKeywordRegexp = regexp.MustCompile(`^\s*(if|unless|case|begin|while|until|def)\b`)
+)
+
+// gemfilePath returns the path of the Gemfile that a lockfile was locked from,
+// which is "" if the lockfile is not named after one
+func gemfilePath(pathToLockfile string) string {
+	if strings.HasSuffix(pathToLockfile, ".locked") {
+		return strings.TrimSuffix(pathToLockfile, ".locked") + ".rb"
+	}
+
+	if !strings.HasSuffix(pathToLockfile, ".lock") {
+		return ""
+	}
+
+	return strings.TrimSuffix(pathToLockfile, ".lock")
+}
+
+// groupNames returns the names of the groups in the arguments of a group
+// block or the value of a group option, ignoring any other options
+func groupNames(args string) []string {
+	var names []string
+
+	for _, arg := range strings.Split(args, ",") {
+		arg = strings.TrimSpace(arg)
+		// options of a group block, such as "optional: true", are not groups
+		if strings.Contains(arg, ": ") || strings.Contains(arg, "=>") {
+			continue
+		}
+
+		if matched := gemfileSymbolRegexp.FindStringSubmatch(arg); matched != nil {
+			names = append(names, matched[1])
+		}
+	}
+
+	return names
+}
+
+// parseGemfileGroups returns the groups that each gem of the Gemfile at the
+// given path is declared in, or nil if there is no Gemfile there. As a
+// Gemfile is Ruby code, only the common forms of declaring gems and their
+// groups are understood.
+func parseGemfileGroups(path string) (map[string][]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", path, err)
+	}
+
+	groups := make(map[string][]string)
+	// blocks holds the groups of each block the current line is in, which is
+	// nil for blocks that are not groups, such as platforms or sources
+	var blocks [][]string
+
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		if gemfileEndRegexp.MatchString(line) {
+			if len(blocks) > 0 {
+				blocks = blocks[:len(blocks)-1]
+			}
+
+			continue
+		}
+
+		if matched := gemfileGemRegexp.FindStringSubmatch(line); matched != nil {
+			var declared []string
+			if option := gemfileGroupOptionRegexp.FindStringSubmatch(matched[2]); option != nil {
+				declared = groupNames(strings.Trim(option[1], "[]"))
+			}
+			for _, block := range blocks {
+				declared = append(declared, block...)
+			}
+			if len(declared) == 0 {
+				declared = []string{gemfileDefaultGroup}
+			}
+
+			groups[matched[1]] = append(groups[matched[1]], declared...)
+
+			continue
+		}
+
+		if gemfileKeywordRegexp.MatchString(line) {
+			blocks = append(blocks, nil)
+
+			continue
+		}
+
+		if !gemfileBlockRegexp.MatchString(line) {
+			continue
+		}
+
+		if matched := gemfileGroupRegexp.FindStringSubmatch(line); matched != nil {
+			blocks = append(blocks, groupNames(matched[1]))
+		} else {
+			blocks = append(blocks, nil)
+		}
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,84 @@
+package lockfile
+
+import (
+	"debug/buildinfo"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"strings"
+)
+
+// goBinaryPackages returns the modules that the build info of a Go binary
+// records it as being built from, along with the standard library of the Go
+// version it was built with, using the replacements of modules that were
+// replaced. Modules that are only known by a local path, and so do not have a
+// version, are not included, nor is the main module when it was built from a
+// checkout rather than being installed at a version.
+func goBinaryPackages(info *debug.BuildInfo) []PackageDetails {
+	var packages []PackageDetails
+
+	add := func(module *debug.Module) {
+		if module == nil {
+			return
+		}
+		if module.Replace != nil {
+			module = module.Replace
+		}
+		if module.Version == "" || module.Version == "(devel)" {
+			return
+		}
+
+		packages = append(packages, PackageDetails{
+			Name:      module.Path,
+			Version:   strings.TrimPrefix(module.Version, "v"),
+			Ecosystem: GoEcosystem,
+			CompareAs: GoEcosystem,
+		})
+	}
+
+	add(&info.Main)
+	for _, dep := range info.Deps {
+		add(dep)
+	}
+
+	// the Go version can be followed by the experiments the binary was built
+	// with, as in "go1.20.3 X:nocoverageredesign", while binaries built with
+	// development versions of Go, as in "devel go1.21-abc", are not released
+	version, _, _ := strings.Cut(info.GoVersion, " ")
+	if strings.HasPrefix(version, "go") {
+		packages = append(packages, PackageDetails{
+			Name:      "stdlib",
+			Version:   strings.TrimPrefix(version, "go"),
+			Ecosystem: GoEcosystem,
+			CompareAs: GoEcosystem,
+		})
+	}
+
+	return packages
+}
+
+// FromGoBinary attempts to read the build info that is embedded in compiled
+// Go binaries from the given file, returning the modules the binary was built
+// from as its packages
+func FromGoBinary(pathToBinary string) (Lockfile, error) {
+	info, err := buildinfo.ReadFile(pathToBinary)
+	if err != nil {
+		return Lockfile{}, fmt.Errorf("could not read build info of %s: %w", pathToBinary, err)
+	}
+
+	packages := goBinaryPackages(info)
+
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].Name == packages[j].Name {
+			return packages[i].Version < packages[j].Version
+		}
+
+		return packages[i].Name < packages[j].Name
+	})
+
+	return Lockfile{
+		FilePath: pathToBinary,
+		ParsedAs: "go-binary",
+		Packages: packages,
+	}, nil
+}
@@ -0,0 +1,76 @@
+package lockfile
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGoBinaryPackages(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		info *debug.BuildInfo
+		want []PackageDetails
+	}{
+		{
+			name: "installed at a version",
+			info: &debug.BuildInfo{
+				GoVersion: "go1.20.3",
+				Main:      debug.Module{Path: "example.com/tool", Version: "v1.2.0"},
+				Deps: []*debug.Module{
+					{Path: "golang.org/x/text", Version: "v0.3.7"},
+				},
+			},
+			want: []PackageDetails{
+				{Name: "example.com/tool", Version: "1.2.0", Ecosystem: GoEcosystem, CompareAs: GoEcosystem},
+				{Name: "golang.org/x/text", Version: "0.3.7", Ecosystem: GoEcosystem, CompareAs: GoEcosystem},
+				{Name: "stdlib", Version: "1.20.3", Ecosystem: GoEcosystem, CompareAs: GoEcosystem},
+			},
+		},
+		{
+			name: "built from a checkout with replacements",
+			info: &debug.BuildInfo{
+				GoVersion: "go1.21.0 X:nocoverageredesign",
+				Main:      debug.Module{Path: "example.com/tool", Version: "(devel)"},
+				Deps: []*debug.Module{
+					{
+						Path:    "golang.org/x/text",
+						Version: "v0.3.7",
+						Replace: &debug.Module{Path: "example.com/text", Version: "v0.3.8"},
+					},
+					{
+						Path:    "example.com/local",
+						Version: "v1.0.0",
+						Replace: &debug.Module{Path: "../local"},
+					},
+				},
+			},
+			want: []PackageDetails{
+				{Name: "example.com/text", Version: "0.3.8", Ecosystem: GoEcosystem, CompareAs: GoEcosystem},
+				{Name: "stdlib", Version: "1.21.0", Ecosystem: GoEcosystem, CompareAs: GoEcosystem},
+			},
+		},
+		{
+			name: "built with a development version of go",
+			info: &debug.BuildInfo{
+				GoVersion: "devel go1.22-abc123",
+				Main:      debug.Module{Path: "example.com/tool"},
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tt.want, goBinaryPackages(tt.info)); diff != "" {
+				t.Errorf("goBinaryPackages() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package lockfile_test
+
+import (
+	"os"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestFromGoBinary_NotABinary(t *testing.T) {
+	t.Parallel()
+
+	_, err := lockfile.FromGoBinary("fixtures/go/one-package.mod")
+
+	expectErrContaining(t, err, "could not read build info")
+}
+
+func TestFromGoBinary_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	_, err := lockfile.FromGoBinary("fixtures/go/does-not-exist")
+
+	expectErrContaining(t, err, "could not read build info")
+}
+
+func TestFromGoBinary(t *testing.T) {
+	t.Parallel()
+
+	// the test binary is itself a Go binary, built from the modules that it
+	// records having been built from
+	path, err := os.Executable()
+	if err != nil {
+		t.Fatalf("could not find the test binary: %v", err)
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		t.Skip("the test binary was built without build info")
+	}
+
+	parsed, err := lockfile.FromGoBinary(path)
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	if parsed.ParsedAs != "go-binary" {
+		t.Errorf("expected to be parsed as a go-binary, but was parsed as %s", parsed.ParsedAs)
+	}
+
+	want := []lockfile.PackageDetails{{
+		Name:      "stdlib",
+		Version:   strings.TrimPrefix(strings.Fields(info.GoVersion)[0], "go"),
+		Ecosystem: lockfile.GoEcosystem,
+		CompareAs: lockfile.GoEcosystem,
+	}}
+	for _, dep := range info.Deps {
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		want = append(want, lockfile.PackageDetails{
+			Name:      dep.Path,
+			Version:   strings.TrimPrefix(dep.Version, "v"),
+			Ecosystem: lockfile.GoEcosystem,
+			CompareAs: lockfile.GoEcosystem,
+		})
+	}
+
+	expectPackages(t, parsed.Packages, want)
+}
@@ -3,6 +3,7 @@ package lockfile_test
 import (
 	"fmt"
 	"github.com/google/osv-scanner/pkg/lockfile"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -29,9 +30,21 @@ func packageToString(pkg lockfile.PackageDetails) string {
 	return fmt.Sprintf("%s@%s (%s, %s)", pkg.Name, pkg.Version, pkg.Ecosystem, commit)
 }
 
+// samePackage reports if the packages are the same, comparing the content of
+// their metadata rather than where it is stored
+func samePackage(a lockfile.PackageDetails, b lockfile.PackageDetails) bool {
+	if !reflect.DeepEqual(a.Metadata, b.Metadata) {
+		return false
+	}
+
+	a.Metadata, b.Metadata = nil, nil
+
+	return a == b
+}
+
 func hasPackage(packages []lockfile.PackageDetails, pkg lockfile.PackageDetails) bool {
 	for _, details := range packages {
-		if details == pkg {
+		if samePackage(details, pkg) {
 			return true
 		}
 	}
@@ -39,6 +52,30 @@ func hasPackage(packages []lockfile.PackageDetails, pkg lockfile.PackageDetails)
 	return false
 }
 
+// withoutLocations removes where each package is declared
+func withoutLocations(packages []lockfile.PackageDetails) []lockfile.PackageDetails {
+	stripped := make([]lockfile.PackageDetails, 0, len(packages))
+
+	for _, pkg := range packages {
+		pkg.Line, pkg.Column = 0, 0
+		stripped = append(stripped, pkg)
+	}
+
+	return stripped
+}
+
+// withoutMetadata removes the metadata of each package
+func withoutMetadata(packages []lockfile.PackageDetails) []lockfile.PackageDetails {
+	stripped := make([]lockfile.PackageDetails, 0, len(packages))
+
+	for _, pkg := range packages {
+		pkg.Metadata = nil
+		stripped = append(stripped, pkg)
+	}
+
+	return stripped
+}
+
 func expectPackage(t *testing.T, packages []lockfile.PackageDetails, pkg lockfile.PackageDetails) {
 	t.Helper()
 
@@ -87,3 +124,20 @@ func expectPackages(t *testing.T, actualPackages []lockfile.PackageDetails, expe
 		}
 	}
 }
+
+// expectPackagesIgnoringLocations is like expectPackages, except that where
+// the packages are declared is not compared, for tests that are not about it
+func expectPackagesIgnoringLocations(t *testing.T, actualPackages []lockfile.PackageDetails, expectedPackages []lockfile.PackageDetails) {
+	t.Helper()
+
+	expectPackages(t, withoutLocations(actualPackages), expectedPackages)
+}
+
+// expectPackagesIgnoringDetails is like expectPackages, except that neither
+// where the packages are declared nor their metadata is compared, for tests
+// that are only about which packages are found
+func expectPackagesIgnoringDetails(t *testing.T, actualPackages []lockfile.PackageDetails, expectedPackages []lockfile.PackageDetails) {
+	t.Helper()
+
+	expectPackages(t, withoutMetadata(withoutLocations(actualPackages)), expectedPackages)
+}
@@ -3,14 +3,25 @@ package lockfile
 import (
 	"fmt"
 	"github.com/BurntSushi/toml"
-	"os"
+	"io"
+	"strings"
 )
 
 type CargoLockPackage struct {
-	Name    string `toml:"name"`
-	Version string `toml:"version"`
+	Name     string `toml:"name"`
+	Version  string `toml:"version"`
+	Source   string `toml:"source"`
+	Checksum string `toml:"checksum"`
+	// Dependencies are the packages this package depends on, each being its
+	// name optionally followed by its version and source
+	Dependencies []string `toml:"dependencies"`
 }
 
+// cargoGitSourcePrefix is the prefix of the sources of packages that are
+// fetched from a git repository rather than a registry, which end with the
+// commit that was locked as their fragment
+const cargoGitSourcePrefix = "git+"
+
 type CargoLockFile struct {
 	Version  int                `toml:"version"`
 	Packages []CargoLockPackage `toml:"package"`
@@ -19,9 +30,13 @@ type CargoLockFile struct {
 const CargoEcosystem Ecosystem = "crates.io"
 
 func ParseCargoLock(pathToLockfile string) ([]PackageDetails, error) {
+	return readLockfile(pathToLockfile, parseCargoLockContent)
+}
+
+func parseCargoLockContent(pathToLockfile string, content io.Reader) ([]PackageDetails, error) {
 	var parsedLockfile *CargoLockFile
 
-	lockfileContents, err := os.ReadFile(pathToLockfile)
+	lockfileContents, err := io.ReadAll(content)
 
 	if err != nil {
 		return []PackageDetails{}, fmt.Errorf("could not read %s: %w", pathToLockfile, err)
@@ -36,11 +51,25 @@ func ParseCargoLock(pathToLockfile string) ([]PackageDetails, error) {
 	packages := make([]PackageDetails, 0, len(parsedLockfile.Packages))
 
 	for _, lockPackage := range parsedLockfile.Packages {
+		var metadata *PackageMetadata
+		if lockPackage.Checksum != "" {
+			metadata = &PackageMetadata{Integrity: lockPackage.Checksum}
+		}
+
+		commit := ""
+		if strings.HasPrefix(lockPackage.Source, cargoGitSourcePrefix) {
+			var repository string
+			repository, commit, _ = strings.Cut(strings.TrimPrefix(lockPackage.Source, cargoGitSourcePrefix), "#")
+			metadata = &PackageMetadata{Resolved: repository}
+		}
+
 		packages = append(packages, PackageDetails{
 			Name:      lockPackage.Name,
 			Version:   lockPackage.Version,
+			Commit:    commit,
 			Ecosystem: CargoEcosystem,
 			CompareAs: CargoEcosystem,
+			Metadata:  metadata,
 		})
 	}
 
@@ -44,7 +44,7 @@ func TestParseCargoLock_OnePackage(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "addr2line",
 			Version:   "0.15.2",
@@ -63,7 +63,7 @@ func TestParseCargoLock_TwoPackages(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "addr2line",
 			Version:   "0.15.2",
@@ -88,7 +88,7 @@ func TestParseCargoLock_PackageWithBuildString(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "wasi",
 			Version:   "0.10.2+wasi-snapshot-preview1",
@@ -97,3 +97,57 @@ func TestParseCargoLock_PackageWithBuildString(t *testing.T) {
 		},
 	})
 }
+
+func TestParseCargoLock_Metadata(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCargoLock("fixtures/cargo/one-package.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "addr2line",
+			Version:   "0.15.2",
+			Ecosystem: lockfile.CargoEcosystem,
+			CompareAs: lockfile.CargoEcosystem,
+			Metadata: &lockfile.PackageMetadata{
+				Integrity: "e7a2e47a1fbe209ee101dd6d61285226744c6c8d3c21c8dc878ba6cb9f467f3a",
+			},
+		},
+	})
+}
+
+func TestParseCargoLock_GitDependency(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCargoLock("fixtures/cargo/git-dependency.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "addr2line",
+			Version:   "0.15.2",
+			Ecosystem: lockfile.CargoEcosystem,
+			CompareAs: lockfile.CargoEcosystem,
+			Metadata: &lockfile.PackageMetadata{
+				Integrity: "e7a2e47a1fbe209ee101dd6d61285226744c6c8d3c21c8dc878ba6cb9f467f3a",
+			},
+		},
+		{
+			Name:      "regex",
+			Version:   "1.5.4",
+			Commit:    "9f9f693768c584971a4d53bc3c586c33ed3a6831",
+			Ecosystem: lockfile.CargoEcosystem,
+			CompareAs: lockfile.CargoEcosystem,
+			Metadata: &lockfile.PackageMetadata{
+				Resolved: "https://github.com/rust-lang/regex?rev=9f9f693",
+			},
+		},
+	})
+}
@@ -1,17 +1,36 @@
 package lockfile
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
-	"os"
+	"io"
+	"strings"
 )
 
+// ComposerRequirements are the packages that a package requires, keyed by
+// their names, which Composer writes as an empty array if there are none
+type ComposerRequirements map[string]string
+
+func (r *ComposerRequirements) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("[]")) {
+		*r = nil
+
+		return nil
+	}
+
+	return json.Unmarshal(data, (*map[string]string)(r))
+}
+
 type ComposerPackage struct {
 	Name    string `json:"name"`
 	Version string `json:"version"`
 	Dist    struct {
 		Reference string `json:"reference"`
+		URL       string `json:"url"`
+		Shasum    string `json:"shasum"`
 	} `json:"dist"`
+	Require ComposerRequirements `json:"require"`
 }
 
 type ComposerLock struct {
@@ -21,10 +40,66 @@ type ComposerLock struct {
 
 const ComposerEcosystem Ecosystem = "Packagist"
 
+const (
+	// composerDefaultGroup is the group of the packages in "packages"
+	composerDefaultGroup = "default"
+	// composerDevGroup is the group of the packages in "packages-dev", which
+	// are not installed with "composer install --no-dev"
+	composerDevGroup = "dev"
+)
+
+// isComposerPlatformPackage reports if the name is of a platform package,
+// which is provided by the PHP installation rather than installed by Composer.
+// Unlike other packages, these do not have a vendor, so "php-http/client" is
+// not one.
+func isComposerPlatformPackage(name string) bool {
+	if strings.Contains(name, "/") {
+		return false
+	}
+
+	return name == "php" ||
+		strings.HasPrefix(name, "php-") ||
+		strings.HasPrefix(name, "ext-") ||
+		strings.HasPrefix(name, "lib-")
+}
+
+// composerPlatformRequirements returns the requirements of a package that
+// are on platform packages, or nil if there are none
+func composerPlatformRequirements(composerPackage ComposerPackage) map[string]string {
+	var requirements map[string]string
+
+	for name, constraint := range composerPackage.Require {
+		if !isComposerPlatformPackage(name) {
+			continue
+		}
+
+		if requirements == nil {
+			requirements = make(map[string]string)
+		}
+		requirements[name] = constraint
+	}
+
+	return requirements
+}
+
+func composerMetadata(composerPackage ComposerPackage, scope DependencyScope, group string) *PackageMetadata {
+	return &PackageMetadata{
+		Scope:                scope,
+		Resolved:             composerPackage.Dist.URL,
+		Integrity:            composerPackage.Dist.Shasum,
+		Groups:               []string{group},
+		PlatformRequirements: composerPlatformRequirements(composerPackage),
+	}
+}
+
 func ParseComposerLock(pathToLockfile string) ([]PackageDetails, error) {
+	return readLockfile(pathToLockfile, parseComposerLockContent)
+}
+
+func parseComposerLockContent(pathToLockfile string, content io.Reader) ([]PackageDetails, error) {
 	var parsedLockfile *ComposerLock
 
-	lockfileContents, err := os.ReadFile(pathToLockfile)
+	lockfileContents, err := io.ReadAll(content)
 
 	if err != nil {
 		return []PackageDetails{}, fmt.Errorf("could not read %s: %w", pathToLockfile, err)
@@ -50,6 +125,7 @@ func ParseComposerLock(pathToLockfile string) ([]PackageDetails, error) {
 			Commit:    composerPackage.Dist.Reference,
 			Ecosystem: ComposerEcosystem,
 			CompareAs: ComposerEcosystem,
+			Metadata:  composerMetadata(composerPackage, ScopeProduction, composerDefaultGroup),
 		})
 	}
 
@@ -60,6 +136,7 @@ func ParseComposerLock(pathToLockfile string) ([]PackageDetails, error) {
 			Commit:    composerPackage.Dist.Reference,
 			Ecosystem: ComposerEcosystem,
 			CompareAs: ComposerEcosystem,
+			Metadata:  composerMetadata(composerPackage, ScopeDevelopment, composerDevGroup),
 		})
 	}
 
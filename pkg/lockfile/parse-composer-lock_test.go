@@ -44,7 +44,7 @@ func TestParseComposerLock_OnePackage(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "sentry/sdk",
 			Version:   "2.0.4",
@@ -64,7 +64,7 @@ func TestParseComposerLock_OnePackageDev(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "sentry/sdk",
 			Version:   "2.0.4",
@@ -84,7 +84,7 @@ func TestParseComposerLock_TwoPackages(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "sentry/sdk",
 			Version:   "2.0.4",
@@ -111,7 +111,7 @@ func TestParseComposerLock_TwoPackagesAlt(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "sentry/sdk",
 			Version:   "2.0.4",
@@ -128,3 +128,86 @@ func TestParseComposerLock_TwoPackagesAlt(t *testing.T) {
 		},
 	})
 }
+
+func TestParseComposerLock_Metadata(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseComposerLock("fixtures/composer/one-package-dev.json")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "sentry/sdk",
+			Version:   "2.0.4",
+			Commit:    "4c115873c86ad5bd0ac6d962db70ca53bf8fb874",
+			Ecosystem: lockfile.ComposerEcosystem,
+			CompareAs: lockfile.ComposerEcosystem,
+			Metadata: &lockfile.PackageMetadata{
+				Scope:    lockfile.ScopeDevelopment,
+				Resolved: "https://api.github.com/repos/getsentry/sentry-php-sdk/zipball/4c115873c86ad5bd0ac6d962db70ca53bf8fb874",
+				Groups:   []string{"dev"},
+			},
+		},
+	})
+}
+
+func TestParseComposerLock_PlatformRequirements(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseComposerLock("fixtures/composer/platform-requirements.json")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "guzzlehttp/guzzle",
+			Version:   "7.5.0",
+			Commit:    "b50a2a1251152e43f6a37f0fa053e730a67d25ba",
+			Ecosystem: lockfile.ComposerEcosystem,
+			CompareAs: lockfile.ComposerEcosystem,
+			Metadata: &lockfile.PackageMetadata{
+				Scope:    lockfile.ScopeProduction,
+				Resolved: "https://api.github.com/repos/guzzle/guzzle/zipball/b50a2a1251152e43f6a37f0fa053e730a67d25ba",
+				Groups:   []string{"default"},
+				PlatformRequirements: map[string]string{
+					"ext-json": "*",
+					"php":      "^7.2.5 || ^8.0",
+				},
+			},
+		},
+		{
+			Name:      "guzzlehttp/promises",
+			Version:   "1.5.2",
+			Commit:    "b94b2807d85443f9719887892882d0329d1e2598",
+			Ecosystem: lockfile.ComposerEcosystem,
+			CompareAs: lockfile.ComposerEcosystem,
+			Metadata: &lockfile.PackageMetadata{
+				Scope:    lockfile.ScopeProduction,
+				Resolved: "https://api.github.com/repos/guzzle/promises/zipball/b94b2807d85443f9719887892882d0329d1e2598",
+				Groups:   []string{"default"},
+			},
+		},
+		{
+			Name:      "phpunit/phpunit",
+			Version:   "9.5.27",
+			Commit:    "a2bc7ffdca99f92d959b3f2270529334030bba38",
+			Ecosystem: lockfile.ComposerEcosystem,
+			CompareAs: lockfile.ComposerEcosystem,
+			Metadata: &lockfile.PackageMetadata{
+				Scope:    lockfile.ScopeDevelopment,
+				Resolved: "https://api.github.com/repos/sebastianbergmann/phpunit/zipball/a2bc7ffdca99f92d959b3f2270529334030bba38",
+				Groups:   []string{"dev"},
+				PlatformRequirements: map[string]string{
+					"ext-dom":      "*",
+					"ext-mbstring": "*",
+					"php":          ">=7.3",
+				},
+			},
+		},
+	})
+}
@@ -3,7 +3,7 @@ package lockfile
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"io"
 	"strings"
 )
 
@@ -173,9 +173,13 @@ func parseConanLock(lockfile ConanLockFile) []PackageDetails {
 }
 
 func ParseConanLock(pathToLockfile string) ([]PackageDetails, error) {
+	return readLockfile(pathToLockfile, parseConanLockContent)
+}
+
+func parseConanLockContent(pathToLockfile string, content io.Reader) ([]PackageDetails, error) {
 	var parsedLockfile *ConanLockFile
 
-	lockfileContents, err := os.ReadFile(pathToLockfile)
+	lockfileContents, err := io.ReadAll(content)
 	if err != nil {
 		return []PackageDetails{}, fmt.Errorf("could not read %s: %w", pathToLockfile, err)
 	}
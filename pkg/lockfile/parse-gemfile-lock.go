@@ -2,9 +2,10 @@ package lockfile
 
 import (
 	"fmt"
+	"io"
 	"log"
-	"os"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -42,15 +43,33 @@ type gemfileLockfileParser struct {
 
 	// holds the commit of the gem that is currently being parsed, if found
 	currentGemCommit string
+	// holds the number of the line that is currently being parsed
+	currentLine int
+
+	// holds the names of the gems listed in the DEPENDENCIES section, which
+	// is nil if there is no such section
+	directDependencies map[string]struct{}
+	// holds the names of the gems that each gem depends on, as listed under
+	// its spec
+	specDependencies map[string][]string
 }
 
-func (parser *gemfileLockfileParser) addDependency(name string, version string) {
+func (parser *gemfileLockfileParser) addDependency(name string, version string, platform string) {
+	var metadata *PackageMetadata
+	if platform != "" {
+		metadata = &PackageMetadata{Platform: platform}
+	}
+
 	parser.dependencies = append(parser.dependencies, PackageDetails{
 		Name:      name,
 		Version:   version,
 		Ecosystem: BundlerEcosystem,
 		CompareAs: BundlerEcosystem,
 		Commit:    parser.currentGemCommit,
+		Line:      parser.currentLine,
+		// only specs indented by four spaces are dependencies
+		Column:   5,
+		Metadata: metadata,
 	})
 }
 
@@ -70,8 +89,22 @@ func (parser *gemfileLockfileParser) parseSpec(line string) {
 		log.Fatal("Weird error when parsing spec in Gemfile.lock (unexpectedly had no spaces) - please report this")
 	}
 
-	if len(spaces) == 4 {
-		parser.addDependency(results[2], results[3])
+	switch len(spaces) {
+	case 4:
+		parser.addDependency(results[2], results[3], results[4])
+	case 6:
+		// the dependencies of a spec are listed under it, with their
+		// requirements rather than a version
+		if len(parser.dependencies) == 0 {
+			return
+		}
+		if parser.specDependencies == nil {
+			parser.specDependencies = make(map[string][]string)
+		}
+
+		spec := parser.dependencies[len(parser.dependencies)-1].Name
+		name, _, _ := strings.Cut(results[2], " ")
+		parser.specDependencies[spec] = append(parser.specDependencies[spec], name)
 	}
 }
 
@@ -104,6 +137,92 @@ func (parser *gemfileLockfileParser) parseSource(line string) {
 	parser.parseSpec(line)
 }
 
+func (parser *gemfileLockfileParser) parseDependency(line string) {
+	// dependencies are listed as "  name", optionally followed by their
+	// requirements in brackets, and a "!" if they come from a non-default source
+	dependencyRegexp := regexp.MustCompile(`^ {2}([^ !]+)`)
+
+	if matched := dependencyRegexp.FindStringSubmatch(line); matched != nil {
+		parser.directDependencies[matched[1]] = struct{}{}
+	}
+}
+
+// markRelations records if each gem is a direct dependency, if the lockfile
+// lists which gems are
+func (parser *gemfileLockfileParser) markRelations() {
+	if parser.directDependencies == nil {
+		return
+	}
+
+	for i, dependency := range parser.dependencies {
+		relation := RelationTransitive
+		if _, ok := parser.directDependencies[dependency.Name]; ok {
+			relation = RelationDirect
+		}
+
+		parser.metadataOf(i).Relation = relation
+	}
+}
+
+// metadataOf returns the metadata of the ith dependency, adding it if the
+// dependency has none yet
+func (parser *gemfileLockfileParser) metadataOf(i int) *PackageMetadata {
+	if parser.dependencies[i].Metadata == nil {
+		parser.dependencies[i].Metadata = &PackageMetadata{}
+	}
+
+	return parser.dependencies[i].Metadata
+}
+
+// markGroups records the groups of the Gemfile that each gem is depended on
+// in, following the dependencies of each gem that the Gemfile declares, with
+// gems that are only in the development and test groups being dev scoped
+func (parser *gemfileLockfileParser) markGroups(gemfileGroups map[string][]string) {
+	groups := make(map[string]map[string]bool)
+
+	for direct := range parser.directDependencies {
+		declared, ok := gemfileGroups[direct]
+		if !ok {
+			declared = []string{gemfileDefaultGroup}
+		}
+
+		queue := []string{direct}
+		seen := map[string]bool{}
+		for len(queue) > 0 {
+			name := queue[0]
+			queue = queue[1:]
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			if groups[name] == nil {
+				groups[name] = make(map[string]bool)
+			}
+			for _, group := range declared {
+				groups[name][group] = true
+			}
+			queue = append(queue, parser.specDependencies[name]...)
+		}
+	}
+
+	for i, dependency := range parser.dependencies {
+		if len(groups[dependency.Name]) == 0 {
+			continue
+		}
+
+		metadata := parser.metadataOf(i)
+		metadata.Scope = ScopeDevelopment
+		for group := range groups[dependency.Name] {
+			metadata.Groups = append(metadata.Groups, group)
+			if group != "development" && group != "test" {
+				metadata.Scope = ScopeProduction
+			}
+		}
+		sort.Strings(metadata.Groups)
+	}
+}
+
 func isNotIndented(line string) bool {
 	re := regexp.MustCompile(`^\S`)
 
@@ -113,6 +232,7 @@ func isNotIndented(line string) bool {
 func (parser *gemfileLockfileParser) parseLineBasedOnState(line string) {
 	switch parser.state {
 	case parserStateDependency:
+		parser.parseDependency(line)
 	case parserStatePlatform:
 		break
 	case parserStateRuby:
@@ -127,11 +247,17 @@ func (parser *gemfileLockfileParser) parseLineBasedOnState(line string) {
 }
 
 func (parser *gemfileLockfileParser) parse(contents string) {
-	lineMatcher := regexp.MustCompile(`(?:\r?\n)+`)
+	lineMatcher := regexp.MustCompile(`\r?\n`)
 
 	lines := lineMatcher.Split(contents, -1)
 
-	for _, line := range lines {
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+
+		parser.currentLine = i + 1
+
 		if isSourceSection(line) {
 			// clear the stateful package details,
 			// since we're now parsing a new group
@@ -145,6 +271,7 @@ func (parser *gemfileLockfileParser) parse(contents string) {
 		switch line {
 		case lockfileSectionDEPENDENCIES:
 			parser.state = parserStateDependency
+			parser.directDependencies = make(map[string]struct{})
 		case lockfileSectionPLATFORMS:
 			parser.state = parserStatePlatform
 		case lockfileSectionRUBY:
@@ -164,15 +291,38 @@ func (parser *gemfileLockfileParser) parse(contents string) {
 }
 
 func ParseGemfileLock(pathToLockfile string) ([]PackageDetails, error) {
+	return readLockfile(pathToLockfile, func(pathToLockfile string, content io.Reader) ([]PackageDetails, error) {
+		return parseGemfileLock(pathToLockfile, content, true)
+	})
+}
+
+// parseGemfileLockContent parses the content of a Gemfile.lock without the
+// Gemfile next to it, so the groups of its gems are not known
+func parseGemfileLockContent(pathToLockfile string, content io.Reader) ([]PackageDetails, error) {
+	return parseGemfileLock(pathToLockfile, content, false)
+}
+
+func parseGemfileLock(pathToLockfile string, content io.Reader, withGemfile bool) ([]PackageDetails, error) {
 	var parser gemfileLockfileParser
 
-	bytes, err := os.ReadFile(pathToLockfile)
+	bytes, err := io.ReadAll(content)
 
 	if err != nil {
 		return []PackageDetails{}, fmt.Errorf("could not read %s: %w", pathToLockfile, err)
 	}
 
 	parser.parse(string(bytes))
+	parser.markRelations()
+
+	if withGemfile && parser.directDependencies != nil {
+		gemfileGroups, err := parseGemfileGroups(gemfilePath(pathToLockfile))
+		if err != nil {
+			return []PackageDetails{}, err
+		}
+		if gemfileGroups != nil {
+			parser.markGroups(gemfileGroups)
+		}
+	}
 
 	return parser.dependencies, nil
 }
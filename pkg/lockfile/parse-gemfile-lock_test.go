@@ -59,7 +59,7 @@ func TestParseGemfileLock_OneGem(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "ast",
 			Version:   "2.4.2",
@@ -78,7 +78,7 @@ func TestParseGemfileLock_SomeGems(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "coderay",
 			Version:   "1.1.3",
@@ -109,7 +109,7 @@ func TestParseGemfileLock_MultipleGems(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "bundler-audit",
 			Version:   "0.9.0.1",
@@ -158,7 +158,7 @@ func TestParseGemfileLock_Rails(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "actioncable",
 			Version:   "7.0.2.2",
@@ -447,7 +447,7 @@ func TestParseGemfileLock_Rubocop(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "ast",
 			Version:   "2.4.2",
@@ -520,7 +520,7 @@ func TestParseGemfileLock_HasLocalGem(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "backbone-on-rails",
 			Version:   "1.2.0.0",
@@ -713,7 +713,7 @@ func TestParseGemfileLock_HasGitGem(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "hanami-controller",
 			Version:   "2.0.0.alpha1",
@@ -751,3 +751,117 @@ func TestParseGemfileLock_HasGitGem(t *testing.T) {
 		},
 	})
 }
+
+func TestParseGemfileLock_Locations(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGemfileLock("fixtures/bundler/some-gems.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "coderay",
+			Version:   "1.1.3",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+			Line:      4,
+			Column:    5,
+			Metadata:  &lockfile.PackageMetadata{Relation: lockfile.RelationTransitive},
+		},
+		{
+			Name:      "method_source",
+			Version:   "1.0.0",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+			Line:      5,
+			Column:    5,
+			Metadata:  &lockfile.PackageMetadata{Relation: lockfile.RelationTransitive},
+		},
+		{
+			Name:      "pry",
+			Version:   "0.14.1",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+			Line:      6,
+			Column:    5,
+			Metadata:  &lockfile.PackageMetadata{Relation: lockfile.RelationDirect},
+		},
+	})
+}
+
+func TestParseGemfileLock_Metadata(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGemfileLock("fixtures/bundler/some-gems.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackagesIgnoringLocations(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "coderay",
+			Version:   "1.1.3",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+			Metadata:  &lockfile.PackageMetadata{Relation: lockfile.RelationTransitive},
+		},
+		{
+			Name:      "method_source",
+			Version:   "1.0.0",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+			Metadata:  &lockfile.PackageMetadata{Relation: lockfile.RelationTransitive},
+		},
+		{
+			Name:      "pry",
+			Version:   "0.14.1",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+			Metadata:  &lockfile.PackageMetadata{Relation: lockfile.RelationDirect},
+		},
+	})
+}
+
+func TestParseGemfileLock_PlatformsAndGroups(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGemfileLock("fixtures/bundler/groups/Gemfile.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	gem := func(name string, version string, relation lockfile.DependencyRelation, scope lockfile.DependencyScope, platform string, groups ...string) lockfile.PackageDetails {
+		return lockfile.PackageDetails{
+			Name:      name,
+			Version:   version,
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+			Metadata: &lockfile.PackageMetadata{
+				Relation: relation,
+				Scope:    scope,
+				Platform: platform,
+				Groups:   groups,
+			},
+		}
+	}
+
+	expectPackagesIgnoringLocations(t, packages, []lockfile.PackageDetails{
+		gem("bootsnap", "1.16.0", lockfile.RelationDirect, lockfile.ScopeProduction, "", "production"),
+		gem("byebug", "11.1.3", lockfile.RelationDirect, lockfile.ScopeDevelopment, "", "development", "test"),
+		gem("capybara", "3.39.2", lockfile.RelationDirect, lockfile.ScopeDevelopment, "", "test"),
+		gem("msgpack", "1.7.2", lockfile.RelationTransitive, lockfile.ScopeProduction, "", "production"),
+		gem("nokogiri", "1.15.4", lockfile.RelationTransitive, lockfile.ScopeProduction, "", "default", "test"),
+		gem("nokogiri", "1.15.4", lockfile.RelationTransitive, lockfile.ScopeProduction, "arm64-darwin", "default", "test"),
+		gem("nokogiri", "1.15.4", lockfile.RelationTransitive, lockfile.ScopeProduction, "x86_64-linux", "default", "test"),
+		gem("pg", "1.5.4", lockfile.RelationDirect, lockfile.ScopeProduction, "", "default"),
+		gem("racc", "1.7.1", lockfile.RelationTransitive, lockfile.ScopeProduction, "", "default", "test"),
+		gem("rails", "7.0.8", lockfile.RelationDirect, lockfile.ScopeProduction, "", "default"),
+		gem("rspec-rails", "6.0.3", lockfile.RelationDirect, lockfile.ScopeDevelopment, "", "development", "test"),
+		gem("web-console", "4.2.1", lockfile.RelationDirect, lockfile.ScopeDevelopment, "", "development"),
+	})
+}
@@ -3,7 +3,7 @@ package lockfile
 import (
 	"fmt"
 	"golang.org/x/mod/modfile"
-	"os"
+	"io"
 	"strings"
 )
 
@@ -19,8 +19,21 @@ func deduplicatePackages(packages map[string]PackageDetails) map[string]PackageD
 	return details
 }
 
+// goModPosition returns the line and column that a directive starts at
+func goModPosition(syntax *modfile.Line) (int, int) {
+	if syntax == nil {
+		return 0, 0
+	}
+
+	return syntax.Start.Line, syntax.Start.LineRune
+}
+
 func ParseGoLock(pathToLockfile string) ([]PackageDetails, error) {
-	lockfileContents, err := os.ReadFile(pathToLockfile)
+	return readLockfile(pathToLockfile, parseGoLockContent)
+}
+
+func parseGoLockContent(pathToLockfile string, content io.Reader) ([]PackageDetails, error) {
+	lockfileContents, err := io.ReadAll(content)
 
 	if err != nil {
 		return []PackageDetails{}, fmt.Errorf("could not read %s: %w", pathToLockfile, err)
@@ -28,6 +41,12 @@ func ParseGoLock(pathToLockfile string) ([]PackageDetails, error) {
 
 	parsedLockfile, err := modfile.Parse(pathToLockfile, lockfileContents, nil)
 
+	// directives that were added by versions of the Go tool newer than the
+	// modfile package knows of, such as "toolchain" was, are unknown
+	if err != nil && strings.Contains(err.Error(), "unknown directive") {
+		return []PackageDetails{}, fmt.Errorf("could not parse %s: %w: %v", pathToLockfile, ErrUnsupportedLockfileVersion, err)
+	}
+
 	if err != nil {
 		return []PackageDetails{}, fmt.Errorf("could not parse %s: %w", pathToLockfile, err)
 	}
@@ -35,11 +54,20 @@ func ParseGoLock(pathToLockfile string) ([]PackageDetails, error) {
 	packages := map[string]PackageDetails{}
 
 	for _, require := range parsedLockfile.Require {
+		line, column := goModPosition(require.Syntax)
+		relation := RelationDirect
+		if require.Indirect {
+			relation = RelationTransitive
+		}
+
 		packages[require.Mod.Path+"@"+require.Mod.Version] = PackageDetails{
 			Name:      require.Mod.Path,
 			Version:   strings.TrimPrefix(require.Mod.Version, "v"),
 			Ecosystem: GoEcosystem,
 			CompareAs: GoEcosystem,
+			Line:      line,
+			Column:    column,
+			Metadata:  &PackageMetadata{Relation: relation},
 		}
 	}
 
@@ -64,12 +92,19 @@ func ParseGoLock(pathToLockfile string) ([]PackageDetails, error) {
 			}
 		}
 
+		// replaced modules are declared by the replace directive
+		line, column := goModPosition(replace.Syntax)
 		for _, replacement := range replacements {
 			packages[replacement] = PackageDetails{
 				Name:      replace.New.Path,
 				Version:   strings.TrimPrefix(replace.New.Version, "v"),
 				Ecosystem: GoEcosystem,
 				CompareAs: GoEcosystem,
+				Line:      line,
+				Column:    column,
+				// the replacement is depended on in the same way as the
+				// module that it replaces
+				Metadata: packages[replacement].Metadata,
 			}
 		}
 	}
@@ -1,6 +1,7 @@
 package lockfile_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/google/osv-scanner/pkg/lockfile"
@@ -24,6 +25,36 @@ func TestParseGoLock_Invalid(t *testing.T) {
 	expectPackages(t, packages, []lockfile.PackageDetails{})
 }
 
+func TestParseGoLock_UnknownDirective(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGoLock("fixtures/go/unknown-directive.mod")
+
+	if !errors.Is(err, lockfile.ErrUnsupportedLockfileVersion) {
+		t.Errorf("Expected to get ErrUnsupportedLockfileVersion, but got \"%v\"", err)
+	}
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseGoLock_Toolchain(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGoLock("fixtures/go/toolchain.mod")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "github.com/BurntSushi/toml",
+			Version:   "1.0.0",
+			Ecosystem: lockfile.GoEcosystem,
+			CompareAs: lockfile.GoEcosystem,
+		},
+	})
+}
+
 func TestParseGoLock_NoPackages(t *testing.T) {
 	t.Parallel()
 
@@ -45,7 +76,7 @@ func TestParseGoLock_OnePackage(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "github.com/BurntSushi/toml",
 			Version:   "1.0.0",
@@ -64,7 +95,7 @@ func TestParseGoLock_TwoPackages(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "github.com/BurntSushi/toml",
 			Version:   "1.0.0",
@@ -89,7 +120,7 @@ func TestParseGoLock_IndirectPackages(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "github.com/BurntSushi/toml",
 			Version:   "1.0.0",
@@ -132,7 +163,7 @@ func TestParseGoLock_Replacements_One(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "example.com/fork/net",
 			Version:   "1.4.5",
@@ -151,7 +182,7 @@ func TestParseGoLock_Replacements_Mixed(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "example.com/fork/net",
 			Version:   "1.4.5",
@@ -176,7 +207,7 @@ func TestParseGoLock_Replacements_Local(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "./fork/net",
 			Version:   "",
@@ -201,7 +232,7 @@ func TestParseGoLock_Replacements_Different(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "example.com/fork/foe",
 			Version:   "1.4.5",
@@ -226,7 +257,7 @@ func TestParseGoLock_Replacements_NotRequired(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "golang.org/x/net",
 			Version:   "0.5.6",
@@ -251,12 +282,82 @@ func TestParseGoLock_Replacements_NoVersion(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "example.com/fork/net",
+			Version:   "1.4.5",
+			Ecosystem: lockfile.GoEcosystem,
+			CompareAs: lockfile.GoEcosystem,
+		},
+	})
+}
+
+func TestParseGoLock_Locations(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGoLock("fixtures/go/replace-one.mod")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
 	expectPackages(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "example.com/fork/net",
 			Version:   "1.4.5",
 			Ecosystem: lockfile.GoEcosystem,
 			CompareAs: lockfile.GoEcosystem,
+			Line:      5,
+			Column:    1,
+			Metadata:  &lockfile.PackageMetadata{Relation: lockfile.RelationDirect},
+		},
+	})
+}
+
+func TestParseGoLock_Metadata(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGoLock("fixtures/go/indirect-packages.mod")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackagesIgnoringLocations(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "github.com/BurntSushi/toml",
+			Version:   "1.0.0",
+			Ecosystem: lockfile.GoEcosystem,
+			CompareAs: lockfile.GoEcosystem,
+			Metadata:  &lockfile.PackageMetadata{Relation: lockfile.RelationDirect},
+		},
+		{
+			Name:      "gopkg.in/yaml.v2",
+			Version:   "2.4.0",
+			Ecosystem: lockfile.GoEcosystem,
+			CompareAs: lockfile.GoEcosystem,
+			Metadata:  &lockfile.PackageMetadata{Relation: lockfile.RelationDirect},
+		},
+		{
+			Name:      "github.com/mattn/go-colorable",
+			Version:   "0.1.9",
+			Ecosystem: lockfile.GoEcosystem,
+			CompareAs: lockfile.GoEcosystem,
+			Metadata:  &lockfile.PackageMetadata{Relation: lockfile.RelationTransitive},
+		},
+		{
+			Name:      "github.com/mattn/go-isatty",
+			Version:   "0.0.14",
+			Ecosystem: lockfile.GoEcosystem,
+			CompareAs: lockfile.GoEcosystem,
+			Metadata:  &lockfile.PackageMetadata{Relation: lockfile.RelationTransitive},
+		},
+		{
+			Name:      "golang.org/x/sys",
+			Version:   "0.0.0-20210630005230-0f9fa26af87c",
+			Ecosystem: lockfile.GoEcosystem,
+			CompareAs: lockfile.GoEcosystem,
+			Metadata:  &lockfile.PackageMetadata{Relation: lockfile.RelationTransitive},
 		},
 	})
 }
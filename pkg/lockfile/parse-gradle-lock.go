@@ -3,6 +3,7 @@ package lockfile
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 )
@@ -37,16 +38,14 @@ func parseToGradlePackageDetail(line string) (PackageDetails, error) {
 }
 
 func ParseGradleLock(pathToLockfile string) ([]PackageDetails, error) {
-	file, err := os.Open(pathToLockfile)
-	if err != nil {
-		return []PackageDetails{}, fmt.Errorf("could not open %s: %w", pathToLockfile, err)
-	}
-	defer file.Close()
+	return openLockfile(pathToLockfile, parseGradleLockContent)
+}
 
+func parseGradleLockContent(pathToLockfile string, content io.Reader) ([]PackageDetails, error) {
 	pkgs := make([]PackageDetails, 0)
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(content)
 
-	for scanner.Scan() {
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
 		lockLine := strings.TrimSpace(scanner.Text())
 		if !isGradleLockFileDepLine(lockLine) {
 			continue
@@ -57,6 +56,8 @@ func ParseGradleLock(pathToLockfile string) ([]PackageDetails, error) {
 			fmt.Fprintf(os.Stderr, "failed to parse lockline: %s\n", err.Error())
 			continue
 		}
+		pkg.Line = lineNumber
+		pkg.Column = indentColumn(scanner.Text())
 
 		pkgs = append(pkgs, pkg)
 	}
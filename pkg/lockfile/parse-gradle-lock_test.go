@@ -48,7 +48,7 @@ func TestParseGradleLock_OnePackage(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringLocations(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "org.springframework.security:spring-security-crypto",
 			Version:   "5.7.3",
@@ -67,7 +67,7 @@ func TestParseGradleLock_MultiplePackage(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringLocations(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "org.springframework.boot:spring-boot-autoconfigure",
 			Version:   "2.7.4",
@@ -111,7 +111,7 @@ func TestParseGradleLock_WithInvalidLines(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringLocations(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "org.springframework.boot:spring-boot-autoconfigure",
 			Version:   "2.7.4",
@@ -126,3 +126,24 @@ func TestParseGradleLock_WithInvalidLines(t *testing.T) {
 		},
 	})
 }
+
+func TestParseGradleLock_Locations(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGradleLock("fixtures/gradle/one-pkg")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "org.springframework.security:spring-security-crypto",
+			Version:   "5.7.3",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+			Line:      4,
+			Column:    1,
+		},
+	})
+}
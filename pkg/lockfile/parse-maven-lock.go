@@ -3,6 +3,7 @@ package lockfile
 import (
 	"encoding/xml"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
 )
@@ -93,9 +94,13 @@ func (p *MavenLockProperties) UnmarshalXML(d *xml.Decoder, start xml.StartElemen
 }
 
 func ParseMavenLock(pathToLockfile string) ([]PackageDetails, error) {
+	return readLockfile(pathToLockfile, parseMavenLockContent)
+}
+
+func parseMavenLockContent(pathToLockfile string, content io.Reader) ([]PackageDetails, error) {
 	var parsedLockfile *MavenLockFile
 
-	lockfileContents, err := os.ReadFile(pathToLockfile)
+	lockfileContents, err := io.ReadAll(content)
 
 	if err != nil {
 		return []PackageDetails{}, fmt.Errorf("could not read %s: %w", pathToLockfile, err)
@@ -3,6 +3,7 @@ package lockfile
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
 	"strings"
@@ -11,19 +12,17 @@ import (
 const MixEcosystem Ecosystem = "Hex"
 
 func ParseMixLock(pathToLockfile string) ([]PackageDetails, error) {
-	file, err := os.Open(pathToLockfile)
-	if err != nil {
-		return []PackageDetails{}, fmt.Errorf("could not open %s: %w", pathToLockfile, err)
-	}
-	defer file.Close()
+	return openLockfile(pathToLockfile, parseMixLockContent)
+}
 
+func parseMixLockContent(pathToLockfile string, content io.Reader) ([]PackageDetails, error) {
 	re := regexp.MustCompile(`^ +"(\w+)": \{.+,$`)
 
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(content)
 
 	var packages []PackageDetails
 
-	for scanner.Scan() {
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
 		line := scanner.Text()
 
 		match := re.FindStringSubmatch(line)
@@ -66,6 +65,8 @@ func ParseMixLock(pathToLockfile string) ([]PackageDetails, error) {
 			Ecosystem: MixEcosystem,
 			CompareAs: MixEcosystem,
 			Commit:    commit,
+			Line:      lineNumber,
+			Column:    indentColumn(line),
 		})
 	}
 
@@ -44,7 +44,7 @@ func TestParseNpmLock_v1_OnePackage(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "wrappy",
 			Version:   "1.0.2",
@@ -63,7 +63,7 @@ func TestParseNpmLock_v1_OnePackageDev(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "wrappy",
 			Version:   "1.0.2",
@@ -82,7 +82,7 @@ func TestParseNpmLock_v1_TwoPackages(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "wrappy",
 			Version:   "1.0.2",
@@ -107,7 +107,7 @@ func TestParseNpmLock_v1_ScopedPackages(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "wrappy",
 			Version:   "1.0.2",
@@ -132,7 +132,7 @@ func TestParseNpmLock_v1_NestedDependencies(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "postcss",
 			Version:   "6.0.23",
@@ -175,6 +175,9 @@ func TestParseNpmLock_v1_NestedDependenciesDup(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
+	// only which packages are found is checked
+	packages = withoutMetadata(packages)
+
 	// todo: convert to using expectPackages w/ listing all expected packages
 	if len(packages) != 39 {
 		t.Errorf("Expected to get two packages, but got %d", len(packages))
@@ -211,7 +214,7 @@ func TestParseNpmLock_v1_Commits(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "@segment/analytics.js-integration-facebook-pixel",
 			Version:   "",
@@ -329,7 +332,7 @@ func TestParseNpmLock_v1_Files(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "lodash",
 			Version:   "1.3.1",
@@ -346,3 +349,27 @@ func TestParseNpmLock_v1_Files(t *testing.T) {
 		},
 	})
 }
+
+func TestParseNpmLock_v1_Metadata(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseNpmLock("fixtures/npm/one-package-dev.v1.json")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "wrappy",
+			Version:   "1.0.2",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+			Metadata: &lockfile.PackageMetadata{
+				Scope:     lockfile.ScopeDevelopment,
+				Resolved:  "https://registry.npmjs.org/wrappy/-/wrappy-1.0.2.tgz",
+				Integrity: "sha1-tSQ9jz7BqjXxNkYFvA0QNuMKtp8=",
+			},
+		},
+	})
+}
@@ -1,6 +1,7 @@
 package lockfile_test
 
 import (
+	"errors"
 	"github.com/google/osv-scanner/pkg/lockfile"
 	"testing"
 )
@@ -23,6 +24,17 @@ func TestParseNpmLock_v2_InvalidJson(t *testing.T) {
 	expectPackages(t, packages, []lockfile.PackageDetails{})
 }
 
+func TestParseNpmLock_UnsupportedVersion(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseNpmLock("fixtures/npm/unsupported.v4.json")
+
+	if !errors.Is(err, lockfile.ErrUnsupportedLockfileVersion) {
+		t.Errorf("Expected to get ErrUnsupportedLockfileVersion, but got \"%v\"", err)
+	}
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
 func TestParseNpmLock_v2_NoPackages(t *testing.T) {
 	t.Parallel()
 
@@ -44,7 +56,7 @@ func TestParseNpmLock_v2_OnePackage(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "wrappy",
 			Version:   "1.0.2",
@@ -63,7 +75,7 @@ func TestParseNpmLock_v2_OnePackageDev(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "wrappy",
 			Version:   "1.0.2",
@@ -82,7 +94,7 @@ func TestParseNpmLock_v2_TwoPackages(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "wrappy",
 			Version:   "1.0.2",
@@ -107,7 +119,7 @@ func TestParseNpmLock_v2_ScopedPackages(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "wrappy",
 			Version:   "1.0.2",
@@ -132,7 +144,7 @@ func TestParseNpmLock_v2_NestedDependencies(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "postcss",
 			Version:   "6.0.23",
@@ -175,7 +187,7 @@ func TestParseNpmLock_v2_NestedDependenciesDup(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "supports-color",
 			Version:   "6.1.0",
@@ -200,7 +212,7 @@ func TestParseNpmLock_v2_Commits(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "@segment/analytics.js-integration-facebook-pixel",
 			Version:   "2.4.1",
@@ -311,7 +323,7 @@ func TestParseNpmLock_v2_Files(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "etag",
 			Version:   "1.8.0",
@@ -335,3 +347,28 @@ func TestParseNpmLock_v2_Files(t *testing.T) {
 		},
 	})
 }
+
+func TestParseNpmLock_v2_Metadata(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseNpmLock("fixtures/npm/one-package-dev.v2.json")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "wrappy",
+			Version:   "1.0.2",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+			Metadata: &lockfile.PackageMetadata{
+				Scope:     lockfile.ScopeDevelopment,
+				Relation:  lockfile.RelationDirect,
+				Resolved:  "https://registry.npmjs.org/wrappy/-/wrappy-1.0.2.tgz",
+				Integrity: "sha1-tSQ9jz7BqjXxNkYFvA0QNuMKtp8=",
+			},
+		},
+	})
+}
@@ -3,20 +3,30 @@ package lockfile
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"io"
 	"path"
 	"strings"
 )
 
 type NpmLockDependency struct {
 	Version      string                       `json:"version"`
+	Resolved     string                       `json:"resolved,omitempty"`
+	Integrity    string                       `json:"integrity,omitempty"`
+	Dev          bool                         `json:"dev,omitempty"`
+	Optional     bool                         `json:"optional,omitempty"`
 	Dependencies map[string]NpmLockDependency `json:"dependencies,omitempty"`
 }
 
 type NpmLockPackage struct {
-	Version      string            `json:"version"`
-	Resolved     string            `json:"resolved"`
-	Dependencies map[string]string `json:"dependencies"`
+	Version              string            `json:"version"`
+	Resolved             string            `json:"resolved"`
+	Integrity            string            `json:"integrity,omitempty"`
+	Dev                  bool              `json:"dev,omitempty"`
+	Optional             bool              `json:"optional,omitempty"`
+	DevOptional          bool              `json:"devOptional,omitempty"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies,omitempty"`
+	OptionalDependencies map[string]string `json:"optionalDependencies,omitempty"`
 }
 
 type NpmLockfile struct {
@@ -29,6 +39,9 @@ type NpmLockfile struct {
 
 const NpmEcosystem Ecosystem = "npm"
 
+// npmMaxLockfileVersion is the newest version of package-lock.json that is supported
+const npmMaxLockfileVersion = 3
+
 func pkgDetailsMapToSlice(m map[string]PackageDetails) []PackageDetails {
 	details := make([]PackageDetails, 0, len(m))
 
@@ -47,12 +60,33 @@ func mergePkgDetailsMap(m1 map[string]PackageDetails, m2 map[string]PackageDetai
 	}
 
 	for name, detail := range m2 {
-		details[name] = detail
+		addPkgDetails(details, name, detail)
 	}
 
 	return details
 }
 
+// addPkgDetails adds the details of a package, merging its metadata with that
+// of the same package if it has already been added from elsewhere
+func addPkgDetails(details map[string]PackageDetails, key string, detail PackageDetails) {
+	if existing, ok := details[key]; ok {
+		detail.Metadata = mergeMetadata(existing.Metadata, detail.Metadata)
+	}
+
+	details[key] = detail
+}
+
+func npmScope(dev bool, optional bool) DependencyScope {
+	switch {
+	case dev:
+		return ScopeDevelopment
+	case optional:
+		return ScopeOptional
+	default:
+		return ScopeProduction
+	}
+}
+
 func parseNpmLockDependencies(dependencies map[string]NpmLockDependency) map[string]PackageDetails {
 	details := map[string]PackageDetails{}
 
@@ -81,13 +115,18 @@ func parseNpmLockDependencies(dependencies map[string]NpmLockDependency) map[str
 			}
 		}
 
-		details[name+"@"+version] = PackageDetails{
+		addPkgDetails(details, name+"@"+version, PackageDetails{
 			Name:      name,
 			Version:   finalVersion,
 			Ecosystem: NpmEcosystem,
 			CompareAs: NpmEcosystem,
 			Commit:    commit,
-		}
+			Metadata: &PackageMetadata{
+				Scope:     npmScope(detail.Dev, detail.Optional),
+				Resolved:  detail.Resolved,
+				Integrity: detail.Integrity,
+			},
+		})
 	}
 
 	return details
@@ -104,6 +143,25 @@ func extractNpmPackageName(name string) string {
 	return pkgName
 }
 
+// npmRelation returns if the package at the given path is a direct dependency
+// of the root package, which is only known if the lockfile includes it
+func npmRelation(packages map[string]NpmLockPackage, namePath string, name string) DependencyRelation {
+	root, ok := packages[""]
+	if !ok {
+		return RelationUnknown
+	}
+
+	if namePath == "node_modules/"+name {
+		for _, dependencies := range []map[string]string{root.Dependencies, root.DevDependencies, root.OptionalDependencies} {
+			if _, ok := dependencies[name]; ok {
+				return RelationDirect
+			}
+		}
+	}
+
+	return RelationTransitive
+}
+
 func parseNpmLockPackages(packages map[string]NpmLockPackage) map[string]PackageDetails {
 	details := map[string]PackageDetails{}
 
@@ -122,13 +180,19 @@ func parseNpmLockPackages(packages map[string]NpmLockPackage) map[string]Package
 			finalVersion = commit
 		}
 
-		details[finalName+"@"+finalVersion] = PackageDetails{
+		addPkgDetails(details, finalName+"@"+finalVersion, PackageDetails{
 			Name:      finalName,
 			Version:   detail.Version,
 			Ecosystem: NpmEcosystem,
 			CompareAs: NpmEcosystem,
 			Commit:    commit,
-		}
+			Metadata: &PackageMetadata{
+				Scope:     npmScope(detail.Dev || detail.DevOptional, detail.Optional),
+				Relation:  npmRelation(packages, namePath, finalName),
+				Resolved:  detail.Resolved,
+				Integrity: detail.Integrity,
+			},
+		})
 	}
 
 	return details
@@ -143,9 +207,13 @@ func parseNpmLock(lockfile NpmLockfile) map[string]PackageDetails {
 }
 
 func ParseNpmLock(pathToLockfile string) ([]PackageDetails, error) {
+	return readLockfile(pathToLockfile, parseNpmLockContent)
+}
+
+func parseNpmLockContent(pathToLockfile string, content io.Reader) ([]PackageDetails, error) {
 	var parsedLockfile *NpmLockfile
 
-	lockfileContents, err := os.ReadFile(pathToLockfile)
+	lockfileContents, err := io.ReadAll(content)
 
 	if err != nil {
 		return []PackageDetails{}, fmt.Errorf("could not read %s: %w", pathToLockfile, err)
@@ -157,5 +225,9 @@ func ParseNpmLock(pathToLockfile string) ([]PackageDetails, error) {
 		return []PackageDetails{}, fmt.Errorf("could not parse %s: %w", pathToLockfile, err)
 	}
 
+	if parsedLockfile.Version > npmMaxLockfileVersion {
+		return []PackageDetails{}, fmt.Errorf("could not parse %s: %w", pathToLockfile, ErrUnsupportedLockfileVersion)
+	}
+
 	return pkgDetailsMapToSlice(parseNpmLock(*parsedLockfile)), nil
 }
@@ -3,7 +3,7 @@ package lockfile
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"io"
 )
 
 type NuGetLockPackage struct {
@@ -48,9 +48,13 @@ func parseNuGetLock(lockfile NuGetLockfile) ([]PackageDetails, error) {
 }
 
 func ParseNuGetLock(pathToLockfile string) ([]PackageDetails, error) {
+	return readLockfile(pathToLockfile, parseNuGetLockContent)
+}
+
+func parseNuGetLockContent(pathToLockfile string, content io.Reader) ([]PackageDetails, error) {
 	var parsedLockfile *NuGetLockfile
 
-	lockfileContents, err := os.ReadFile(pathToLockfile)
+	lockfileContents, err := io.ReadAll(content)
 
 	if err != nil {
 		return []PackageDetails{}, fmt.Errorf("could not read %s: %w", pathToLockfile, err)
@@ -63,7 +67,7 @@ func ParseNuGetLock(pathToLockfile string) ([]PackageDetails, error) {
 	}
 
 	if parsedLockfile.Version != 1 {
-		return []PackageDetails{}, fmt.Errorf("could not parse %s: unsupported lock file version", pathToLockfile)
+		return []PackageDetails{}, fmt.Errorf("could not parse %s: %w", pathToLockfile, ErrUnsupportedLockfileVersion)
 	}
 
 	return parseNuGetLock(*parsedLockfile)
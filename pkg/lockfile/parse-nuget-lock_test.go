@@ -1,6 +1,7 @@
 package lockfile_test
 
 import (
+	"errors"
 	"github.com/google/osv-scanner/pkg/lockfile"
 	"testing"
 )
@@ -11,5 +12,9 @@ func TestParseNuGetLock_InvalidVersion(t *testing.T) {
 	packages, err := lockfile.ParseNuGetLock("fixtures/nuget/empty.v0.json")
 
 	expectErrContaining(t, err, "unsupported lock file version")
+
+	if !errors.Is(err, lockfile.ErrUnsupportedLockfileVersion) {
+		t.Errorf("Expected to get ErrUnsupportedLockfileVersion, but got \"%v\"", err)
+	}
 	expectPackages(t, packages, []lockfile.PackageDetails{})
 }
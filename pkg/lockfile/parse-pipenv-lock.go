@@ -3,7 +3,7 @@ package lockfile
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"io"
 )
 
 type PipenvPackage struct {
@@ -18,9 +18,13 @@ type PipenvLock struct {
 const PipenvEcosystem = PipEcosystem
 
 func ParsePipenvLock(pathToLockfile string) ([]PackageDetails, error) {
+	return readLockfile(pathToLockfile, parsePipenvLockContent)
+}
+
+func parsePipenvLockContent(pathToLockfile string, content io.Reader) ([]PackageDetails, error) {
 	var parsedLockfile *PipenvLock
 
-	lockfileContents, err := os.ReadFile(pathToLockfile)
+	lockfileContents, err := io.ReadAll(content)
 
 	if err != nil {
 		return []PackageDetails{}, fmt.Errorf("could not read %s: %w", pathToLockfile, err)
@@ -49,6 +53,7 @@ func ParsePipenvLock(pathToLockfile string) ([]PackageDetails, error) {
 			Version:   pipenvPackage.Version[2:],
 			Ecosystem: PipenvEcosystem,
 			CompareAs: PipenvEcosystem,
+			Metadata:  &PackageMetadata{Scope: ScopeProduction},
 		})
 	}
 
@@ -58,6 +63,7 @@ func ParsePipenvLock(pathToLockfile string) ([]PackageDetails, error) {
 			Version:   pipenvPackage.Version[2:],
 			Ecosystem: PipenvEcosystem,
 			CompareAs: PipenvEcosystem,
+			Metadata:  &PackageMetadata{Scope: ScopeDevelopment},
 		})
 	}
 
@@ -45,7 +45,7 @@ func TestParsePipenvLock_OnePackage(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "markupsafe",
 			Version:   "2.1.1",
@@ -64,7 +64,7 @@ func TestParsePipenvLock_OnePackageDev(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "markupsafe",
 			Version:   "2.1.1",
@@ -83,7 +83,7 @@ func TestParsePipenvLock_TwoPackages(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "itsdangerous",
 			Version:   "2.1.2",
@@ -108,7 +108,7 @@ func TestParsePipenvLock_TwoPackagesAlt(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "itsdangerous",
 			Version:   "2.1.2",
@@ -135,3 +135,23 @@ func TestParsePipenvLock_PackageWithoutVersion(t *testing.T) {
 
 	expectPackages(t, packages, []lockfile.PackageDetails{})
 }
+
+func TestParsePipenvLock_Metadata(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePipenvLock("fixtures/pipenv/one-package-dev.json")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "markupsafe",
+			Version:   "2.1.1",
+			Ecosystem: lockfile.PipenvEcosystem,
+			CompareAs: lockfile.PipenvEcosystem,
+			Metadata:  &lockfile.PackageMetadata{Scope: lockfile.ScopeDevelopment},
+		},
+	})
+}
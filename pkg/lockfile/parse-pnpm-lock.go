@@ -3,8 +3,9 @@ package lockfile
 import (
 	"fmt"
 	"gopkg.in/yaml.v2"
-	"os"
+	"io"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -26,8 +27,37 @@ type PnpmLockfile struct {
 	Packages map[string]PnpmLockPackage `yaml:"packages,omitempty"`
 }
 
+// UnmarshalYAML parses the lockfile version as a number, as from v6 it is
+// written as a string such as '6.0'
+func (l *PnpmLockfile) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw struct {
+		Version  string                     `yaml:"lockfileVersion"`
+		Packages map[string]PnpmLockPackage `yaml:"packages,omitempty"`
+	}
+
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	l.Packages = raw.Packages
+	l.Version = 0
+
+	if raw.Version != "" {
+		version, err := strconv.ParseFloat(raw.Version, 64)
+		if err != nil {
+			return fmt.Errorf("invalid lockfileVersion %q: %w", raw.Version, err)
+		}
+		l.Version = version
+	}
+
+	return nil
+}
+
 const PnpmEcosystem = NpmEcosystem
 
+// pnpmMaxLockfileVersion is the newest major version of pnpm-lock.yaml that is supported
+const pnpmMaxLockfileVersion = 9
+
 func startsWithNumber(str string) bool {
 	matcher := regexp.MustCompile(`^\d`)
 
@@ -36,13 +66,25 @@ func startsWithNumber(str string) bool {
 
 // extractPnpmPackageNameAndVersion parses a dependency path, attempting to
 // extract the name and version of the package it represents
-func extractPnpmPackageNameAndVersion(dependencyPath string) (string, string) {
+func extractPnpmPackageNameAndVersion(dependencyPath string, lockfileVersion float64) (string, string) {
+	if lockfileVersion >= 6 {
+		return extractPnpmPackageNameAndVersionV6(dependencyPath)
+	}
+
 	parts := strings.Split(dependencyPath, "/")
 	var name string
 
+	if len(parts) < 2 {
+		return "", ""
+	}
+
 	parts = parts[1:]
 
 	if strings.HasPrefix(parts[0], "@") {
+		if len(parts) < 2 {
+			return "", ""
+		}
+
 		name = strings.Join(parts[:2], "/")
 		parts = parts[2:]
 	} else {
@@ -69,11 +111,42 @@ func extractPnpmPackageNameAndVersion(dependencyPath string) (string, string) {
 	return name, version
 }
 
+// extractPnpmPackageNameAndVersionV6 parses a dependency path of a v6 or later
+// lockfile, which is written as "/name@version" in v6 and as "name@version"
+// from v9, followed by the versions of any peer dependencies in parentheses.
+// The version of packages that are not from a registry is where they were
+// resolved from, such as a tarball, in which case only the name is returned.
+func extractPnpmPackageNameAndVersionV6(dependencyPath string) (string, string) {
+	dependencyPath = strings.TrimPrefix(dependencyPath, "/")
+
+	if i := strings.Index(dependencyPath, "("); i != -1 {
+		dependencyPath = dependencyPath[:i]
+	}
+
+	if dependencyPath == "" {
+		return "", ""
+	}
+
+	// the "@" that a scoped package starts with is part of its name
+	i := strings.Index(dependencyPath[1:], "@") + 1
+	if i == 0 {
+		return "", ""
+	}
+
+	name, version := dependencyPath[:i], dependencyPath[i+1:]
+
+	if !startsWithNumber(version) {
+		return name, ""
+	}
+
+	return name, version
+}
+
 func parsePnpmLock(lockfile PnpmLockfile) []PackageDetails {
 	packages := make([]PackageDetails, 0, len(lockfile.Packages))
 
 	for s, pkg := range lockfile.Packages {
-		name, version := extractPnpmPackageNameAndVersion(s)
+		name, version := extractPnpmPackageNameAndVersion(s, lockfile.Version)
 
 		// "name" is only present if it's not in the dependency path and takes
 		// priority over whatever name we think we've extracted (if any)
@@ -115,9 +188,13 @@ func parsePnpmLock(lockfile PnpmLockfile) []PackageDetails {
 }
 
 func ParsePnpmLock(pathToLockfile string) ([]PackageDetails, error) {
+	return readLockfile(pathToLockfile, parsePnpmLockContent)
+}
+
+func parsePnpmLockContent(pathToLockfile string, content io.Reader) ([]PackageDetails, error) {
 	var parsedLockfile *PnpmLockfile
 
-	lockfileContents, err := os.ReadFile(pathToLockfile)
+	lockfileContents, err := io.ReadAll(content)
 
 	if err != nil {
 		return []PackageDetails{}, fmt.Errorf("could not read %s: %w", pathToLockfile, err)
@@ -129,5 +206,9 @@ func ParsePnpmLock(pathToLockfile string) ([]PackageDetails, error) {
 		return []PackageDetails{}, fmt.Errorf("could not parse %s: %w", pathToLockfile, err)
 	}
 
+	if parsedLockfile != nil && int(parsedLockfile.Version) > pnpmMaxLockfileVersion {
+		return []PackageDetails{}, fmt.Errorf("could not parse %s: %w", pathToLockfile, ErrUnsupportedLockfileVersion)
+	}
+
 	return parsePnpmLock(*parsedLockfile), nil
 }
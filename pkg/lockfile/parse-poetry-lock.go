@@ -3,7 +3,9 @@ package lockfile
 import (
 	"fmt"
 	"github.com/BurntSushi/toml"
-	"os"
+	"io"
+	"strconv"
+	"strings"
 )
 
 type PoetryLockPackageSource struct {
@@ -15,19 +17,43 @@ type PoetryLockPackage struct {
 	Name    string                  `toml:"name"`
 	Version string                  `toml:"version"`
 	Source  PoetryLockPackageSource `toml:"source"`
+	// Category is either "main" or "dev", and is not included by newer
+	// versions of Poetry
+	Category string `toml:"category"`
+}
+
+type PoetryLockMetadata struct {
+	LockVersion string `toml:"lock-version"`
 }
 
 type PoetryLockFile struct {
 	Version  int                 `toml:"version"`
 	Packages []PoetryLockPackage `toml:"package"`
+	Metadata PoetryLockMetadata  `toml:"metadata"`
 }
 
 const PoetryEcosystem = PipEcosystem
 
+// poetryMaxLockVersion is the newest major version of poetry.lock that is supported
+const poetryMaxLockVersion = 2
+
+// isSupportedPoetryLockVersion reports if the major version of the lock-version
+// is supported, which is assumed if it is missing or not a number
+func isSupportedPoetryLockVersion(lockVersion string) bool {
+	major, _, _ := strings.Cut(lockVersion, ".")
+	version, err := strconv.Atoi(major)
+
+	return err != nil || version <= poetryMaxLockVersion
+}
+
 func ParsePoetryLock(pathToLockfile string) ([]PackageDetails, error) {
+	return readLockfile(pathToLockfile, parsePoetryLockContent)
+}
+
+func parsePoetryLockContent(pathToLockfile string, content io.Reader) ([]PackageDetails, error) {
 	var parsedLockfile *PoetryLockFile
 
-	lockfileContents, err := os.ReadFile(pathToLockfile)
+	lockfileContents, err := io.ReadAll(content)
 
 	if err != nil {
 		return []PackageDetails{}, fmt.Errorf("could not read %s: %w", pathToLockfile, err)
@@ -39,16 +65,29 @@ func ParsePoetryLock(pathToLockfile string) ([]PackageDetails, error) {
 		return []PackageDetails{}, fmt.Errorf("could not parse %s: %w", pathToLockfile, err)
 	}
 
+	if !isSupportedPoetryLockVersion(parsedLockfile.Metadata.LockVersion) {
+		return []PackageDetails{}, fmt.Errorf("could not parse %s: %w", pathToLockfile, ErrUnsupportedLockfileVersion)
+	}
+
 	packages := make([]PackageDetails, 0, len(parsedLockfile.Packages))
 
 	for _, lockPackage := range parsedLockfile.Packages {
-		packages = append(packages, PackageDetails{
+		pkg := PackageDetails{
 			Name:      lockPackage.Name,
 			Version:   lockPackage.Version,
 			Commit:    lockPackage.Source.Commit,
 			Ecosystem: PoetryEcosystem,
 			CompareAs: PoetryEcosystem,
-		})
+		}
+
+		switch lockPackage.Category {
+		case "main":
+			pkg.Metadata = &PackageMetadata{Scope: ScopeProduction}
+		case "dev":
+			pkg.Metadata = &PackageMetadata{Scope: ScopeDevelopment}
+		}
+
+		packages = append(packages, pkg)
 	}
 
 	return packages, nil
@@ -1,6 +1,7 @@
 package lockfile_test
 
 import (
+	"errors"
 	"github.com/google/osv-scanner/pkg/lockfile"
 	"testing"
 )
@@ -23,6 +24,17 @@ func TestParsePoetryLock_InvalidToml(t *testing.T) {
 	expectPackages(t, packages, []lockfile.PackageDetails{})
 }
 
+func TestParsePoetryLock_UnsupportedVersion(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePoetryLock("fixtures/poetry/unsupported.lock")
+
+	if !errors.Is(err, lockfile.ErrUnsupportedLockfileVersion) {
+		t.Errorf("Expected to get ErrUnsupportedLockfileVersion, but got \"%v\"", err)
+	}
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
 func TestParsePoetryLock_NoPackages(t *testing.T) {
 	t.Parallel()
 
@@ -44,7 +56,7 @@ func TestParsePoetryLock_OnePackage(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "numpy",
 			Version:   "1.23.3",
@@ -63,7 +75,7 @@ func TestParsePoetryLock_TwoPackages(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "proto-plus",
 			Version:   "1.22.0",
@@ -88,7 +100,7 @@ func TestParsePoetryLock_PackageWithMetadata(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "emoji",
 			Version:   "2.0.0",
@@ -107,7 +119,7 @@ func TestParsePoetryLock_PackageWithGitSource(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "ike",
 			Version:   "0.2.0",
@@ -127,7 +139,7 @@ func TestParsePoetryLock_PackageWithLegacySource(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "appdirs",
 			Version:   "1.4.4",
@@ -137,3 +149,23 @@ func TestParsePoetryLock_PackageWithLegacySource(t *testing.T) {
 		},
 	})
 }
+
+func TestParsePoetryLock_Metadata(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePoetryLock("fixtures/poetry/one-package.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "numpy",
+			Version:   "1.23.3",
+			Ecosystem: lockfile.PoetryEcosystem,
+			CompareAs: lockfile.PoetryEcosystem,
+			Metadata:  &lockfile.PackageMetadata{Scope: lockfile.ScopeProduction},
+		},
+	})
+}
@@ -3,7 +3,7 @@ package lockfile
 import (
 	"fmt"
 	"gopkg.in/yaml.v2"
-	"os"
+	"io"
 )
 
 type PubspecLockDescription struct {
@@ -61,9 +61,13 @@ type PubspecLockfile struct {
 const PubEcosystem Ecosystem = "Pub"
 
 func ParsePubspecLock(pathToLockfile string) ([]PackageDetails, error) {
+	return readLockfile(pathToLockfile, parsePubspecLockContent)
+}
+
+func parsePubspecLockContent(pathToLockfile string, content io.Reader) ([]PackageDetails, error) {
 	var parsedLockfile *PubspecLockfile
 
-	lockfileContents, err := os.ReadFile(pathToLockfile)
+	lockfileContents, err := io.ReadAll(content)
 
 	if err != nil {
 		return []PackageDetails{}, fmt.Errorf("could not read %s: %w", pathToLockfile, err)
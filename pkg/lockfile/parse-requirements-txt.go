@@ -3,7 +3,7 @@ package lockfile
 import (
 	"bufio"
 	"fmt"
-	"os"
+	"io"
 	"regexp"
 	"strings"
 )
@@ -92,24 +92,26 @@ func isNotRequirementLine(line string) bool {
 }
 
 func ParseRequirementsTxt(pathToLockfile string) ([]PackageDetails, error) {
-	var packages []PackageDetails
+	return openLockfile(pathToLockfile, parseRequirementsTxtContent)
+}
 
-	file, err := os.Open(pathToLockfile)
-	if err != nil {
-		return packages, fmt.Errorf("could not open %s: %w", pathToLockfile, err)
-	}
-	defer file.Close()
+func parseRequirementsTxtContent(pathToLockfile string, content io.Reader) ([]PackageDetails, error) {
+	var packages []PackageDetails
 
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(content)
 
-	for scanner.Scan() {
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
 		line := removeComments(scanner.Text())
 
 		if isNotRequirementLine(line) {
 			continue
 		}
 
-		packages = append(packages, parseLine(line))
+		pkg := parseLine(line)
+		pkg.Line = lineNumber
+		pkg.Column = indentColumn(scanner.Text())
+
+		packages = append(packages, pkg)
 	}
 
 	if err := scanner.Err(); err != nil {
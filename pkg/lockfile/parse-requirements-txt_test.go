@@ -47,7 +47,7 @@ func TestParseRequirementsTxt_OneRequirementUnconstrained(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringLocations(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "flask",
 			Version:   "0.0.0",
@@ -66,7 +66,7 @@ func TestParseRequirementsTxt_OneRequirementConstrained(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringLocations(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "django",
 			Version:   "2.2.24",
@@ -85,7 +85,7 @@ func TestParseRequirementsTxt_MultipleRequirementsConstrained(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringLocations(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "astroid",
 			Version:   "2.5.1",
@@ -176,7 +176,7 @@ func TestParseRequirementsTxt_MultipleRequirementsMixed(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringLocations(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "flask",
 			Version:   "0.0.0",
@@ -237,7 +237,7 @@ func TestParseRequirementsTxt_FileFormatExample(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringLocations(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "pytest",
 			Version:   "0.0.0",
@@ -304,7 +304,7 @@ func TestParseRequirementsTxt_WithAddedSupport(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringLocations(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "twisted",
 			Version:   "20.3.0",
@@ -323,7 +323,7 @@ func TestParseRequirementsTxt_NonNormalizedNames(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringLocations(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "zope-interface",
 			Version:   "5.4.0",
@@ -344,3 +344,38 @@ func TestParseRequirementsTxt_NonNormalizedNames(t *testing.T) {
 		},
 	})
 }
+
+func TestParseRequirementsTxt_Locations(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseRequirementsTxt("fixtures/pip/file-format-example.txt")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackage(t, packages, lockfile.PackageDetails{
+		Name:      "docopt",
+		Version:   "0.6.1",
+		Ecosystem: lockfile.PipEcosystem,
+		CompareAs: lockfile.PipEcosystem,
+		Line:      9,
+		Column:    1,
+	})
+	expectPackage(t, packages, lockfile.PackageDetails{
+		Name:      "mopidy-dirble",
+		Version:   "1.1",
+		Ecosystem: lockfile.PipEcosystem,
+		CompareAs: lockfile.PipEcosystem,
+		Line:      12,
+		Column:    1,
+	})
+	expectPackage(t, packages, lockfile.PackageDetails{
+		Name:      "green",
+		Version:   "0.0.0",
+		Ecosystem: lockfile.PipEcosystem,
+		CompareAs: lockfile.PipEcosystem,
+		Line:      24,
+		Column:    1,
+	})
+}
@@ -35,7 +35,7 @@ func TestParseYarnLock_v1_OnePackage(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "balanced-match",
 			Version:   "1.0.2",
@@ -54,7 +54,7 @@ func TestParseYarnLock_v1_TwoPackages(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "concat-stream",
 			Version:   "1.6.2",
@@ -79,7 +79,7 @@ func TestParseYarnLock_v1_MultipleVersions(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "define-properties",
 			Version:   "1.1.3",
@@ -116,7 +116,7 @@ func TestParseYarnLock_v1_MultipleConstraints(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "@babel/code-frame",
 			Version:   "7.12.13",
@@ -141,7 +141,7 @@ func TestParseYarnLock_v1_ScopedPackages(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "@babel/code-frame",
 			Version:   "7.12.11",
@@ -166,7 +166,7 @@ func TestParseYarnLock_v1_VersionsWithBuildString(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "css-tree",
 			Version:   "1.0.0-alpha.37",
@@ -209,7 +209,7 @@ func TestParseYarnLock_v1_Commits(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "mine1",
 			Version:   "1.0.0-alpha.37",
@@ -348,7 +348,7 @@ func TestParseYarnLock_v1_Files(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "etag",
 			Version:   "1.8.1",
@@ -393,3 +393,63 @@ func TestParseYarnLock_v1_Files(t *testing.T) {
 		},
 	})
 }
+
+func TestParseYarnLock_v1_Locations(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseYarnLock("fixtures/yarn/two-packages.v1.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "concat-map",
+			Version:   "0.0.1",
+			Ecosystem: lockfile.YarnEcosystem,
+			CompareAs: lockfile.YarnEcosystem,
+			Line:      5,
+			Column:    1,
+			Metadata: &lockfile.PackageMetadata{
+				Resolved:  "https://registry.yarnpkg.com/concat-map/-/concat-map-0.0.1.tgz#d8a96bd77fd68df7793a73036a3ba0d5405d477b",
+				Integrity: "sha1-2Klr13/Wjfd5OnMDajug1UBdR3s=",
+			},
+		},
+		{
+			Name:      "concat-stream",
+			Version:   "1.6.2",
+			Ecosystem: lockfile.YarnEcosystem,
+			CompareAs: lockfile.YarnEcosystem,
+			Line:      10,
+			Column:    1,
+			Metadata: &lockfile.PackageMetadata{
+				Resolved:  "https://registry.npmjs.org/concat-stream/-/concat-stream-1.6.2.tgz",
+				Integrity: "sha512-27HBghJxjiZtIk3Ycvn/4kbJk/1uZuJFfuPEns6LaEvpvG1f0hTea8lilrouyo9mVc2GWdcEZ8OLoGmSADlrCw==",
+			},
+		},
+	})
+}
+
+func TestParseYarnLock_v1_Metadata(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseYarnLock("fixtures/yarn/one-package.v1.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackagesIgnoringLocations(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "balanced-match",
+			Version:   "1.0.2",
+			Ecosystem: lockfile.YarnEcosystem,
+			CompareAs: lockfile.YarnEcosystem,
+			Metadata: &lockfile.PackageMetadata{
+				Resolved:  "https://registry.yarnpkg.com/balanced-match/-/balanced-match-1.0.2.tgz#e83e3a7e3f300b34cb9d87f615fa0cbf357690ee",
+				Integrity: "sha512-3oSeUO0TMV67hN1AmbXsK4yaqU7tjiHlbxRDZOpH0KW9+CeX4bRAaX0Anxt0tx2MrpRpWwQaPwIlISEJhYU5Pw==",
+			},
+		},
+	})
+}
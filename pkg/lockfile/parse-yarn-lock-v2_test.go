@@ -35,7 +35,7 @@ func TestParseYarnLock_v2_OnePackage(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "balanced-match",
 			Version:   "1.0.2",
@@ -54,7 +54,7 @@ func TestParseYarnLock_v2_TwoPackages(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "compare-func",
 			Version:   "2.0.0",
@@ -79,7 +79,7 @@ func TestParseYarnLock_v2_MultipleVersions(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "debug",
 			Version:   "4.3.3",
@@ -110,7 +110,7 @@ func TestParseYarnLock_v2_ScopedPackages(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "@babel/cli",
 			Version:   "7.16.8",
@@ -141,7 +141,7 @@ func TestParseYarnLock_v2_VersionsWithBuildString(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "@nicolo-ribaudo/chokidar-2",
 			Version:   "2.1.8-no-fsevents.3",
@@ -172,7 +172,7 @@ func TestParseYarnLock_v2_Commits(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "@my-scope/my-first-package",
 			Version:   "0.0.6",
@@ -234,7 +234,7 @@ func TestParseYarnLock_v2_Files(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	expectPackages(t, packages, []lockfile.PackageDetails{
+	expectPackagesIgnoringDetails(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "my-package",
 			Version:   "0.0.2",
@@ -244,3 +244,26 @@ func TestParseYarnLock_v2_Files(t *testing.T) {
 		},
 	})
 }
+
+func TestParseYarnLock_v2_Metadata(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseYarnLock("fixtures/yarn/one-package.v2.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackagesIgnoringLocations(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "balanced-match",
+			Version:   "1.0.2",
+			Ecosystem: lockfile.YarnEcosystem,
+			CompareAs: lockfile.YarnEcosystem,
+			Metadata: &lockfile.PackageMetadata{
+				Resolved:  "balanced-match@npm:1.0.2",
+				Integrity: "9706c088a283058a8a99e0bf91b0a2f75497f185980d9ffa8b304de1d9e58ebda7c72c07ebf01dadedaac5b2907b2c6f566f660d62bd336c3468e960403b9d65",
+			},
+		},
+	})
+}
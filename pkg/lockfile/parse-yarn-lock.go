@@ -3,6 +3,7 @@ package lockfile
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"net/url"
 	"os"
 	"regexp"
@@ -15,11 +16,15 @@ func shouldSkipYarnLine(line string) bool {
 	return line == "" || strings.HasPrefix(line, "#")
 }
 
-func groupYarnPackageLines(scanner *bufio.Scanner) [][]string {
+// groupYarnPackageLines returns the lines of each dependency, along with the
+// line number that each dependency starts on
+func groupYarnPackageLines(scanner *bufio.Scanner) ([][]string, []int) {
 	var groups [][]string
+	var starts []int
 	var group []string
+	start := 1
 
-	for scanner.Scan() {
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
 		line := scanner.Text()
 
 		if shouldSkipYarnLine(line) {
@@ -30,8 +35,10 @@ func groupYarnPackageLines(scanner *bufio.Scanner) [][]string {
 		if !strings.HasPrefix(line, " ") {
 			if len(group) > 0 {
 				groups = append(groups, group)
+				starts = append(starts, start)
 			}
 			group = make([]string, 0)
+			start = lineNumber
 		}
 
 		group = append(group, line)
@@ -39,9 +46,10 @@ func groupYarnPackageLines(scanner *bufio.Scanner) [][]string {
 
 	if len(group) > 0 {
 		groups = append(groups, group)
+		starts = append(starts, start)
 	}
 
-	return groups
+	return groups, starts
 }
 
 func extractYarnPackageName(str string) string {
@@ -92,6 +100,20 @@ func determineYarnPackageResolution(group []string) string {
 	return ""
 }
 
+func determineYarnPackageIntegrity(group []string) string {
+	re := regexp.MustCompile(`^ {2}(?:integrity|checksum:) "?([^ '"]+)"?$`)
+
+	for _, s := range group {
+		matched := re.FindStringSubmatch(s)
+
+		if matched != nil {
+			return matched[1]
+		}
+	}
+
+	return ""
+}
+
 func tryExtractCommit(resolution string) string {
 	// language=GoRegExp
 	matchers := []string{
@@ -167,19 +189,21 @@ func parseYarnPackageGroup(group []string) PackageDetails {
 		Ecosystem: YarnEcosystem,
 		CompareAs: YarnEcosystem,
 		Commit:    tryExtractCommit(resolution),
+		Metadata: &PackageMetadata{
+			Resolved:  resolution,
+			Integrity: determineYarnPackageIntegrity(group),
+		},
 	}
 }
 
 func ParseYarnLock(pathToLockfile string) ([]PackageDetails, error) {
-	file, err := os.Open(pathToLockfile)
-	if err != nil {
-		return []PackageDetails{}, fmt.Errorf("could not open %s: %w", pathToLockfile, err)
-	}
-	defer file.Close()
+	return openLockfile(pathToLockfile, parseYarnLockContent)
+}
 
-	scanner := bufio.NewScanner(file)
+func parseYarnLockContent(pathToLockfile string, content io.Reader) ([]PackageDetails, error) {
+	scanner := bufio.NewScanner(content)
 
-	packageGroups := groupYarnPackageLines(scanner)
+	packageGroups, starts := groupYarnPackageLines(scanner)
 
 	if err := scanner.Err(); err != nil {
 		return []PackageDetails{}, fmt.Errorf("error while scanning %s: %w", pathToLockfile, err)
@@ -187,12 +211,16 @@ func ParseYarnLock(pathToLockfile string) ([]PackageDetails, error) {
 
 	packages := make([]PackageDetails, 0, len(packageGroups))
 
-	for _, group := range packageGroups {
+	for i, group := range packageGroups {
 		if group[0] == "__metadata:" {
 			continue
 		}
 
-		packages = append(packages, parseYarnPackageGroup(group))
+		pkg := parseYarnPackageGroup(group)
+		pkg.Line = starts[i]
+		pkg.Column = 1
+
+		packages = append(packages, pkg)
 	}
 
 	return packages, nil
@@ -51,8 +51,95 @@ func ListParsers() []string {
 	return ps
 }
 
+// ParserInfo describes a parser that lockfiles can be parsed with
+type ParserInfo struct {
+	// Name is the name of the lockfile that the parser is for, which is also
+	// what the lockfile is given as to parse other files as one
+	Name string
+	// Extension is the extension of the lockfile, including the leading dot,
+	// or "" if it does not have one
+	Extension string
+	// Ecosystem is the ecosystem that the packages of the lockfile are in
+	Ecosystem Ecosystem
+}
+
+// parserEcosystems are the ecosystems of the packages that each parser finds
+var parserEcosystems = map[string]Ecosystem{
+	"buildscript-gradle.lockfile": MavenEcosystem,
+	"Cargo.lock":                  CargoEcosystem,
+	"composer.lock":               ComposerEcosystem,
+	"conan.lock":                  ConanEcosystem,
+	"Gemfile.lock":                BundlerEcosystem,
+	"go.mod":                      GoEcosystem,
+	"gradle.lockfile":             MavenEcosystem,
+	"mix.lock":                    MixEcosystem,
+	"Pipfile.lock":                PipenvEcosystem,
+	"package-lock.json":           NpmEcosystem,
+	"packages.lock.json":          NuGetEcosystem,
+	"pnpm-lock.yaml":              PnpmEcosystem,
+	"poetry.lock":                 PoetryEcosystem,
+	"pom.xml":                     MavenEcosystem,
+	"pubspec.lock":                PubEcosystem,
+	"requirements.txt":            PipEcosystem,
+	"yarn.lock":                   YarnEcosystem,
+}
+
+// Parsers describes every parser that lockfiles can be parsed with, in the
+// same order as ListParsers
+func Parsers() []ParserInfo {
+	names := ListParsers()
+	infos := make([]ParserInfo, 0, len(names))
+
+	for _, name := range names {
+		infos = append(infos, ParserInfo{
+			Name:      name,
+			Extension: filepath.Ext(name),
+			Ecosystem: parserEcosystems[name],
+		})
+	}
+
+	return infos
+}
+
 var ErrParserNotFound = errors.New("could not determine parser")
 
+// ErrUnsupportedLockfileVersion is returned by parsers when they recognise
+// a lockfile but do not support its format version
+var ErrUnsupportedLockfileVersion = errors.New("unsupported lock file version")
+
+// ErrParserPanicked is returned when a parser panics while parsing a lockfile,
+// which usually means the lockfile is malformed or truncated
+var ErrParserPanicked = errors.New("parser panicked")
+
+// safeParse calls the given parser, converting any panic into an error so that
+// one malformed lockfile cannot abort a whole scan
+func safeParse(parser PackageDetailsParser, pathToLockfile string) (packages []PackageDetails, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			packages = []PackageDetails{}
+			err = fmt.Errorf("could not parse %s: %w: %v", pathToLockfile, ErrParserPanicked, r)
+		}
+	}()
+
+	packages, err = parser(pathToLockfile)
+	recordDeclaringFile(packages, pathToLockfile)
+
+	return packages, err
+}
+
+// recordDeclaringFile records the lockfile that each package was parsed from
+// in its metadata
+func recordDeclaringFile(packages []PackageDetails, pathToLockfile string) {
+	for i := range packages {
+		var metadata PackageMetadata
+		if packages[i].Metadata != nil {
+			metadata = *packages[i].Metadata
+		}
+		metadata.DeclaringFile = pathToLockfile
+		packages[i].Metadata = &metadata
+	}
+}
+
 type Packages []PackageDetails
 
 func toSliceOfEcosystems(ecosystemsMap map[Ecosystem]struct{}) []Ecosystem {
@@ -126,14 +213,82 @@ func Parse(pathToLockfile string, parseAs string) (Lockfile, error) {
 	parser, parsedAs := FindParser(pathToLockfile, parseAs)
 
 	if parser == nil {
-		if parseAs != "" {
-			return Lockfile{}, fmt.Errorf("%w, requested %s", ErrParserNotFound, parseAs)
+		return Lockfile{}, parserNotFound(pathToLockfile, parseAs)
+	}
+
+	return parseWith(parser, pathToLockfile, parseAs, parsedAs)
+}
+
+// ParseBest parses the lockfile with each of the candidate parsers, for files
+// that could be one of several kinds of lockfile, returning the lockfile of
+// the parser that best matches it. Parsers that fail do not match, and of the
+// rest the one that found the most packages is preferred, discounting any
+// packages whose names could not be those of a package, as parsers for
+// line-based formats tend to find in files of other formats. When several
+// match as well, the earliest candidate is preferred, and if every candidate
+// fails the error of the first is returned. Without any candidates, the
+// parser is selected based on the name of the file as with Parse.
+func ParseBest(pathToLockfile string, candidates []string) (Lockfile, error) {
+	if len(candidates) == 0 {
+		return Parse(pathToLockfile, "")
+	}
+
+	var best Lockfile
+	var bestScore int
+	var firstErr error
+	found := false
+
+	for _, parseAs := range candidates {
+		parsed, err := Parse(pathToLockfile, parseAs)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+
+			continue
+		}
+
+		if score := matchScore(parsed.Packages); !found || score > bestScore {
+			best, bestScore = parsed, score
+			found = true
+		}
+	}
+
+	if !found {
+		return Lockfile{}, firstErr
+	}
+
+	return best, nil
+}
+
+// matchScore scores how well the parser that found the packages matches the
+// lockfile they were found in, by how many of them have names that could be
+// the name of a package less how many do not
+func matchScore(packages []PackageDetails) int {
+	score := 0
+
+	for _, pkg := range packages {
+		if pkg.Name != "" && !strings.ContainsAny(pkg.Name, " \t\"'{}[](),;=<>") {
+			score++
+		} else {
+			score--
 		}
+	}
+
+	return score
+}
 
-		return Lockfile{}, fmt.Errorf("%w for %s", ErrParserNotFound, pathToLockfile)
+func parserNotFound(pathToLockfile string, parseAs string) error {
+	if parseAs != "" {
+		return fmt.Errorf("%w, requested %s", ErrParserNotFound, parseAs)
 	}
 
-	packages, err := parser(pathToLockfile)
+	return fmt.Errorf("%w for %s", ErrParserNotFound, pathToLockfile)
+}
+
+// parseWith parses the lockfile with the given parser, sorting its packages
+func parseWith(parser PackageDetailsParser, pathToLockfile string, parseAs string, parsedAs string) (Lockfile, error) {
+	packages, err := safeParse(parser, pathToLockfile)
 
 	if err != nil && parseAs != "" {
 		err = fmt.Errorf("(parsing as %s) %w", parsedAs, err)
@@ -0,0 +1,47 @@
+package lockfile
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSafeParse_RecoversFromPanics(t *testing.T) {
+	t.Parallel()
+
+	packages, err := safeParse(func(pathToLockfile string) ([]PackageDetails, error) {
+		var parts []string
+
+		return []PackageDetails{{Name: parts[1]}}, nil
+	}, "/path/to/my/lockfile")
+
+	if !errors.Is(err, ErrParserPanicked) {
+		t.Fatalf("Expected to get ErrParserPanicked, but got \"%v\"", err)
+	}
+
+	if !strings.Contains(err.Error(), "/path/to/my/lockfile") {
+		t.Errorf("Expected error to mention the lockfile path, but got \"%v\"", err)
+	}
+
+	if len(packages) != 0 {
+		t.Errorf("Expected no packages, but got %d", len(packages))
+	}
+}
+
+func TestSafeParse_PassesThroughResults(t *testing.T) {
+	t.Parallel()
+
+	want := []PackageDetails{{Name: "my-package", Version: "1.0.0", Ecosystem: NpmEcosystem}}
+
+	packages, err := safeParse(func(pathToLockfile string) ([]PackageDetails, error) {
+		return want, nil
+	}, "/path/to/my/lockfile")
+
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	if len(packages) != 1 || packages[0] != want[0] {
+		t.Errorf("Expected %v, but got %v", want, packages)
+	}
+}
@@ -3,6 +3,7 @@ package lockfile_test
 import (
 	"errors"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -159,6 +160,101 @@ func TestListParsers(t *testing.T) {
 	}
 }
 
+func TestParsers(t *testing.T) {
+	t.Parallel()
+
+	parsers := lockfile.Parsers()
+
+	if len(parsers) != len(lockfile.ListParsers()) {
+		t.Fatalf("Expected every parser to be described, got %d", len(parsers))
+	}
+
+	for i, name := range lockfile.ListParsers() {
+		if parsers[i].Name != name {
+			t.Errorf("Expected parser %d to be %s, but got %s", i, name, parsers[i].Name)
+		}
+		if parsers[i].Ecosystem == "" {
+			t.Errorf("Expected %s to have an ecosystem", name)
+		}
+	}
+
+	want := lockfile.ParserInfo{Name: "requirements.txt", Extension: ".txt", Ecosystem: lockfile.PipEcosystem}
+	for _, parser := range parsers {
+		if parser.Name == want.Name && parser != want {
+			t.Errorf("Expected %+v, but got %+v", want, parser)
+		}
+	}
+}
+
+func TestParseBest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path       string
+		candidates []string
+		parsedAs   string
+		packages   int
+		wantErr    bool
+	}{
+		{
+			path:       "fixtures/pipenv/two-packages.json",
+			candidates: []string{"requirements.txt", "Pipfile.lock"},
+			parsedAs:   "Pipfile.lock",
+			packages:   2,
+		},
+		{
+			path:       "fixtures/pip/multiple-packages-mixed.txt",
+			candidates: []string{"Pipfile.lock", "poetry.lock", "requirements.txt"},
+			parsedAs:   "requirements.txt",
+			packages:   8,
+		},
+		{
+			path:       "fixtures/poetry/two-packages.lock",
+			candidates: []string{"requirements.txt", "Cargo.lock", "poetry.lock"},
+			parsedAs:   "poetry.lock",
+			packages:   2,
+		},
+		{
+			path:       "fixtures/pip/empty.txt",
+			candidates: []string{"Pipfile.lock", "requirements.txt", "poetry.lock"},
+			parsedAs:   "requirements.txt",
+			packages:   0,
+		},
+		{
+			path:       "fixtures/pipenv/not-json.txt",
+			candidates: []string{"Pipfile.lock", "package-lock.json"},
+			wantErr:    true,
+		},
+		{
+			path:    "fixtures/npm/one-package.v2.json",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			parsed, err := lockfile.ParseBest(tt.path, tt.candidates)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected to get an error but did not")
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatalf("Got unexpected error: %v", err)
+			}
+
+			if parsed.ParsedAs != tt.parsedAs || len(parsed.Packages) != tt.packages {
+				t.Errorf("Expected %d packages parsed as %s, but got %d parsed as %s", tt.packages, tt.parsedAs, len(parsed.Packages), parsed.ParsedAs)
+			}
+		})
+	}
+}
+
 func TestLockfile_String(t *testing.T) {
 	t.Parallel()
 
@@ -300,3 +396,23 @@ func TestPackages_Ecosystems(t *testing.T) {
 		})
 	}
 }
+
+func TestParse_RecordsDeclaringFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.FromSlash("fixtures/npm/one-package-dev.v2.json")
+	lockf, err := lockfile.Parse(path, "package-lock.json")
+
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	for _, pkg := range lockf.Packages {
+		if pkg.Metadata == nil || pkg.Metadata.DeclaringFile != path {
+			t.Errorf("Expected %s to be declared by %s, but got %+v", pkg.Name, path, pkg.Metadata)
+		}
+		if pkg.Metadata != nil && pkg.Metadata.Scope != lockfile.ScopeDevelopment {
+			t.Errorf("Expected the metadata recorded by the parser to be kept, but got %+v", pkg.Metadata)
+		}
+	}
+}
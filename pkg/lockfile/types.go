@@ -1,13 +1,147 @@
 package lockfile
 
+import (
+	"sort"
+	"strings"
+)
+
 type PackageDetails struct {
 	Name      string    `json:"name"`
 	Version   string    `json:"version"`
 	Commit    string    `json:"commit,omitempty"`
 	Ecosystem Ecosystem `json:"ecosystem,omitempty"`
 	CompareAs Ecosystem `json:"compareAs,omitempty"`
+	// Line and Column are where the package is declared in the lockfile,
+	// starting from 1, or 0 if the parser does not record them
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+	// Metadata is any further information that the lockfile records about
+	// the package. It is always set when parsing with Parse, which records
+	// the lockfile the package was declared in, and is otherwise nil if the
+	// parser does not record any.
+	Metadata *PackageMetadata `json:"metadata,omitempty"`
 }
 
 type Ecosystem string
 
 type PackageDetailsParser = func(pathToLockfile string) ([]PackageDetails, error)
+
+// DependencyScope is the scope that a package is depended on in
+type DependencyScope string
+
+const (
+	ScopeUnknown     DependencyScope = ""
+	ScopeProduction  DependencyScope = "prod"
+	ScopeDevelopment DependencyScope = "dev"
+	ScopeOptional    DependencyScope = "optional"
+)
+
+// DependencyRelation is whether a package is depended on by the project
+// itself, or only by its dependencies
+type DependencyRelation string
+
+const (
+	RelationUnknown    DependencyRelation = ""
+	RelationDirect     DependencyRelation = "direct"
+	RelationTransitive DependencyRelation = "transitive"
+)
+
+// PackageMetadata is information about a package that only some lockfiles
+// record, with each field being empty if it is not known
+type PackageMetadata struct {
+	Scope    DependencyScope    `json:"scope,omitempty"`
+	Relation DependencyRelation `json:"relation,omitempty"`
+	// Resolved is where the package was resolved to, which is usually the
+	// URL it is downloaded from
+	Resolved string `json:"resolved,omitempty"`
+	// Integrity is the hash recorded to verify the content of the package,
+	// in whatever format the lockfile uses
+	Integrity string `json:"integrity,omitempty"`
+	// DeclaringFile is the path of the lockfile that the package was parsed
+	// from, which is only set when parsing with Parse
+	DeclaringFile string `json:"declaringFile,omitempty"`
+	// Platform is the platform that a platform-specific build of the package
+	// is for, such as "x86_64-linux" for a gem with native extensions
+	Platform string `json:"platform,omitempty"`
+	// Groups are the groups of the manifest that the package is depended on
+	// in, directly or not, such as "development" for a Gemfile
+	Groups []string `json:"groups,omitempty"`
+	// PlatformRequirements are the versions of the runtime and its extensions
+	// that the package requires, such as "php" and "ext-json" for a Composer
+	// package, keyed by their names
+	PlatformRequirements map[string]string `json:"platformRequirements,omitempty"`
+}
+
+func scopeRank(scope DependencyScope) int {
+	switch scope {
+	case ScopeProduction:
+		return 3
+	case ScopeOptional:
+		return 2
+	case ScopeDevelopment:
+		return 1
+	case ScopeUnknown:
+	}
+
+	return 0
+}
+
+// mergeMetadata combines the metadata of a package that is depended on in more
+// than one place, so that it is direct if any of them are, and is in the
+// widest of their scopes
+func mergeMetadata(a *PackageMetadata, b *PackageMetadata) *PackageMetadata {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	merged := *b
+
+	if a.Relation == RelationDirect || merged.Relation == RelationUnknown {
+		merged.Relation = a.Relation
+	}
+	if scopeRank(a.Scope) > scopeRank(merged.Scope) {
+		merged.Scope = a.Scope
+	}
+	if merged.Resolved == "" {
+		merged.Resolved = a.Resolved
+	}
+	if merged.Integrity == "" {
+		merged.Integrity = a.Integrity
+	}
+	if merged.DeclaringFile == "" {
+		merged.DeclaringFile = a.DeclaringFile
+	}
+	if merged.Platform == "" {
+		merged.Platform = a.Platform
+	}
+	merged.Groups = mergeGroups(a.Groups, merged.Groups)
+	if merged.PlatformRequirements == nil {
+		merged.PlatformRequirements = a.PlatformRequirements
+	}
+
+	return &merged
+}
+
+// mergeGroups returns the sorted union of the given groups
+func mergeGroups(a []string, b []string) []string {
+	seen := map[string]bool{}
+	var merged []string
+	for _, group := range append(append([]string{}, a...), b...) {
+		if !seen[group] {
+			seen[group] = true
+			merged = append(merged, group)
+		}
+	}
+	sort.Strings(merged)
+
+	return merged
+}
+
+// indentColumn returns the column (starting from 1) of the first character of
+// the line that is not a space or tab
+func indentColumn(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t")) + 1
+}
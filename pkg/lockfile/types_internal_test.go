@@ -0,0 +1,28 @@
+package lockfile
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMergeGroups(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a    []string
+		b    []string
+		want []string
+	}{
+		{a: nil, b: nil, want: nil},
+		{a: nil, b: []string{"test", "dev", "test"}, want: []string{"dev", "test"}},
+		{a: []string{"test", "default"}, b: nil, want: []string{"default", "test"}},
+		{a: []string{"test", "default"}, b: []string{"dev", "test"}, want: []string{"default", "dev", "test"}},
+	}
+
+	for _, tt := range tests {
+		if diff := cmp.Diff(tt.want, mergeGroups(tt.a, tt.b)); diff != "" {
+			t.Errorf("mergeGroups(%v, %v) mismatch (-want +got):\n%s", tt.a, tt.b, diff)
+		}
+	}
+}
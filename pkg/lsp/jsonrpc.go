@@ -0,0 +1,124 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"sync"
+)
+
+// JSON-RPC error codes, as defined by the specification
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+var errMissingContentLength = errors.New("message is missing a Content-Length header")
+
+// message is a JSON-RPC 2.0 request, notification or response, which are told
+// apart by which of ID and Method are set
+type message struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  any              `json:"result,omitempty"`
+	Error   *responseError   `json:"error,omitempty"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *responseError) Error() string {
+	return fmt.Sprintf("%s (%d)", e.Message, e.Code)
+}
+
+// readMessage reads a message framed with a Content-Length header, as used by
+// the base protocol of the Language Server Protocol
+func readMessage(r *bufio.Reader) (message, error) {
+	header, err := textproto.NewReader(r).ReadMIMEHeader()
+	if err != nil {
+		//nolint:wrapcheck // io.EOF is how the end of the stream is signalled
+		return message{}, err
+	}
+
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return message{}, errMissingContentLength
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return message{}, fmt.Errorf("failed to read message: %w", err)
+	}
+
+	var msg message
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return message{}, &responseError{Code: codeParseError, Message: err.Error()}
+	}
+
+	return msg, nil
+}
+
+// conn writes messages to the client, which may be done from multiple
+// goroutines as scans finish in the background
+type conn struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (c *conn) write(msg message) error {
+	msg.JSONRPC = "2.0"
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n%s", len(body), body); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+
+	return nil
+}
+
+// notify sends a notification, which the client does not respond to
+func (c *conn) notify(method string, params any) error {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s params: %w", method, err)
+	}
+
+	return c.write(message{Method: method, Params: raw})
+}
+
+// reply responds to the request with the given ID, with either the result or
+// the error; a nil result is sent as null, as a result is required on success
+func (c *conn) reply(id *json.RawMessage, result any, err error) error {
+	if err != nil {
+		var rpcErr *responseError
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &responseError{Code: codeInternalError, Message: err.Error()}
+		}
+
+		return c.write(message{ID: id, Error: rpcErr})
+	}
+
+	if result == nil {
+		result = json.RawMessage("null")
+	}
+
+	return c.write(message{ID: id, Result: result})
+}
@@ -0,0 +1,606 @@
+package lsp
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"unicode/utf16"
+
+	"github.com/google/osv-scanner/internal/semantic"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osvscanner"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+// ErrExitWithoutShutdown is returned by Server.Serve when the client asks the
+// server to exit without first asking it to shut down
+var ErrExitWithoutShutdown = errors.New("exit was requested without a shutdown")
+
+// ScanFunc scans the lockfile at the given path, returning the results
+type ScanFunc func(path string, r output.Reporter) (models.VulnerabilityResults, error)
+
+// ScanLockfile scans the lockfile at the given path with osvscanner.DoScan
+func ScanLockfile(path string, r output.Reporter) (models.VulnerabilityResults, error) {
+	results, err := osvscanner.DoScan(osvscanner.ScannerActions{LockfilePaths: []string{path}}, r)
+
+	// finding vulnerabilities (or nothing at all) is still a successful scan
+	if errors.Is(err, osvscanner.VulnerabilitiesFoundErr) || errors.Is(err, osvscanner.NoPackagesFoundErr) {
+		err = nil
+	}
+
+	//nolint:wrapcheck
+	return results, err
+}
+
+// Server is a language server that publishes diagnostics for the vulnerable
+// packages in the lockfiles that are open in an editor, along with code
+// actions that upgrade them to a version that fixes the vulnerabilities.
+//
+// Lockfiles are scanned when they are opened and each time they are saved,
+// with the results being cached by the content of the lockfile so that only
+// lockfiles which have changed are scanned again
+type Server struct {
+	// Version is reported to clients along with the name of the server
+	Version string
+	// Scan is used to scan each lockfile, defaulting to ScanLockfile
+	Scan     ScanFunc
+	Reporter output.Reporter
+
+	conn     *conn
+	shutdown bool
+
+	mu sync.Mutex
+	// documents are the lockfiles that are open in the editor, by URI
+	documents map[string]*document
+	// scans are the latest scan of each lockfile, by path
+	scans map[string]scan
+
+	// scanning ensures only one lockfile is scanned at a time, with pending
+	// tracking the scans that have not finished yet
+	scanning sync.Mutex
+	pending  sync.WaitGroup
+}
+
+type document struct {
+	path string
+	// text is the content of the document in the editor, which can include
+	// changes that have not been saved (and so have not been scanned) yet
+	text string
+	// hash is the hash of the content that was most recently scanned, so that
+	// results from earlier scans that finish later can be discarded
+	hash [sha256.Size]byte
+}
+
+type scan struct {
+	hash        [sha256.Size]byte
+	lines       []string
+	diagnostics []output.Diagnostic
+}
+
+func (s *Server) scanner() ScanFunc {
+	if s.Scan == nil {
+		return ScanLockfile
+	}
+
+	return s.Scan
+}
+
+func (s *Server) reporter() output.Reporter {
+	if s.Reporter == nil {
+		return output.NewVoidReporter()
+	}
+
+	return s.Reporter
+}
+
+// Serve reads messages from the client until it asks the server to exit or
+// the input is closed, writing responses and notifications to out
+func (s *Server) Serve(in io.Reader, out io.Writer) error {
+	s.conn = &conn{w: out}
+	s.documents = make(map[string]*document)
+	s.scans = make(map[string]scan)
+
+	defer s.pending.Wait()
+
+	reader := bufio.NewReader(in)
+	for {
+		msg, err := readMessage(reader)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		var rpcErr *responseError
+		if errors.As(err, &rpcErr) {
+			null := json.RawMessage("null")
+			if err := s.conn.reply(&null, nil, rpcErr); err != nil {
+				return err
+			}
+
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return ErrExitWithoutShutdown
+			}
+
+			return nil
+		}
+
+		result, err := s.handle(msg.Method, msg.Params)
+
+		// notifications are not responded to, even if they fail
+		if msg.ID == nil {
+			if err != nil && !errors.Is(err, errUnknownMethod) {
+				s.log(MessageError, fmt.Sprintf("Failed to handle %s: %v", msg.Method, err))
+			}
+
+			continue
+		}
+
+		if errors.Is(err, errUnknownMethod) {
+			err = &responseError{Code: codeMethodNotFound, Message: "method not found: " + msg.Method}
+		}
+
+		if err := s.conn.reply(msg.ID, result, err); err != nil {
+			return err
+		}
+	}
+}
+
+var errUnknownMethod = errors.New("unknown method")
+
+func (s *Server) handle(method string, params json.RawMessage) (any, error) {
+	if s.shutdown {
+		return nil, &responseError{Code: codeInvalidRequest, Message: "server is shutting down"}
+	}
+
+	switch method {
+	case "initialize":
+		return InitializeResult{
+			Capabilities: ServerCapabilities{
+				TextDocumentSync: TextDocumentSyncOptions{
+					OpenClose: true,
+					Change:    TextDocumentSyncKindFull,
+					Save:      SaveOptions{IncludeText: false},
+				},
+				CodeActionProvider: CodeActionOptions{CodeActionKinds: []string{"quickfix"}},
+			},
+			ServerInfo: ServerInfo{Name: "osv-scanner", Version: s.Version},
+		}, nil
+	case "initialized":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+
+		return nil, nil
+	case "textDocument/didOpen":
+		var p DidOpenTextDocumentParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		s.didOpen(p)
+
+		return nil, nil
+	case "textDocument/didChange":
+		var p DidChangeTextDocumentParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		s.didChange(p)
+
+		return nil, nil
+	case "textDocument/didSave":
+		var p DidSaveTextDocumentParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		s.scanDocument(p.TextDocument.URI)
+
+		return nil, nil
+	case "textDocument/didClose":
+		var p DidCloseTextDocumentParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+
+		return nil, s.didClose(p)
+	case "textDocument/codeAction":
+		var p CodeActionParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+
+		return s.codeActions(p), nil
+	}
+
+	return nil, errUnknownMethod
+}
+
+func decodeParams(params json.RawMessage, v any) error {
+	if err := json.Unmarshal(params, v); err != nil {
+		return &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+
+	return nil
+}
+
+// log sends a message for the client to show in its log of the server
+func (s *Server) log(messageType int, msg string) {
+	// there is nothing more that can be done if the client can't be written to
+	_ = s.conn.notify("window/logMessage", LogMessageParams{Type: messageType, Message: msg})
+}
+
+func (s *Server) didOpen(p DidOpenTextDocumentParams) {
+	path, err := uriToPath(p.TextDocument.URI)
+	if err != nil {
+		s.log(MessageWarning, err.Error())
+
+		return
+	}
+
+	// only documents that can be scanned are tracked
+	if parser, _ := lockfile.FindParser(path, ""); parser == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.documents[p.TextDocument.URI] = &document{path: path, text: p.TextDocument.Text}
+	s.mu.Unlock()
+
+	s.scanDocument(p.TextDocument.URI)
+}
+
+func (s *Server) didChange(p DidChangeTextDocumentParams) {
+	if len(p.ContentChanges) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if doc, ok := s.documents[p.TextDocument.URI]; ok {
+		doc.text = p.ContentChanges[len(p.ContentChanges)-1].Text
+	}
+}
+
+func (s *Server) didClose(p DidCloseTextDocumentParams) error {
+	s.mu.Lock()
+	_, ok := s.documents[p.TextDocument.URI]
+	delete(s.documents, p.TextDocument.URI)
+	s.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	// clear the diagnostics of the document, as its results are only kept
+	// up to date while it is open
+	return s.conn.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI:         p.TextDocument.URI,
+		Diagnostics: []Diagnostic{},
+	})
+}
+
+// scanDocument scans the lockfile of the document as it is saved on disk in
+// the background, publishing the diagnostics once it has been scanned unless
+// the lockfile has not changed since it was last scanned
+func (s *Server) scanDocument(uri string) {
+	s.mu.Lock()
+	doc, ok := s.documents[uri]
+	if !ok {
+		s.mu.Unlock()
+
+		return
+	}
+	path := doc.path
+	s.mu.Unlock()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		s.log(MessageError, fmt.Sprintf("Failed to read %s: %v", path, err))
+
+		return
+	}
+	hash := sha256.Sum256(content)
+
+	s.mu.Lock()
+	doc.hash = hash
+	cached, ok := s.scans[path]
+	s.mu.Unlock()
+
+	if ok && cached.hash == hash {
+		s.publish(uri, cached)
+
+		return
+	}
+
+	s.pending.Add(1)
+	go func() {
+		defer s.pending.Done()
+
+		s.scanning.Lock()
+		results, err := s.scanner()(path, s.reporter())
+		s.scanning.Unlock()
+
+		if err != nil {
+			s.log(MessageError, fmt.Sprintf("Failed to scan %s: %v", path, err))
+
+			return
+		}
+
+		result := scan{
+			hash:        hash,
+			lines:       strings.Split(string(content), "\n"),
+			diagnostics: diagnosticsFor(path, &results),
+		}
+
+		s.mu.Lock()
+		latest := doc.hash == hash
+		if latest {
+			s.scans[path] = result
+		}
+		s.mu.Unlock()
+
+		if latest {
+			s.publish(uri, result)
+		}
+	}()
+}
+
+// diagnosticsFor returns the diagnostics of the lockfile at the given path
+func diagnosticsFor(path string, results *models.VulnerabilityResults) []output.Diagnostic {
+	var diagnostics []output.Diagnostic
+	for _, diagnostic := range output.Diagnostics(results) {
+		if filepath.Clean(diagnostic.File) == filepath.Clean(path) {
+			diagnostics = append(diagnostics, diagnostic)
+		}
+	}
+
+	return diagnostics
+}
+
+func (s *Server) publish(uri string, result scan) {
+	s.mu.Lock()
+	_, open := s.documents[uri]
+	s.mu.Unlock()
+
+	if !open {
+		return
+	}
+
+	diagnostics := make([]Diagnostic, 0, len(result.diagnostics))
+	for _, diagnostic := range result.diagnostics {
+		diagnostics = append(diagnostics, protocolDiagnostic(diagnostic, result.lines))
+	}
+
+	// there is nothing more that can be done if the client can't be written to
+	_ = s.conn.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: diagnostics,
+	})
+}
+
+func protocolSeverity(severity string) int {
+	switch severity {
+	case "error":
+		return SeverityError
+	case "information":
+		return SeverityInformation
+	default:
+		return SeverityWarning
+	}
+}
+
+// protocolDiagnostic converts a diagnostic from the 1-based byte offsets used
+// by the diagnostics output to the 0-based UTF-16 offsets used by the protocol
+func protocolDiagnostic(diagnostic output.Diagnostic, lines []string) Diagnostic {
+	var line string
+	if diagnostic.Line <= len(lines) {
+		line = lines[diagnostic.Line-1]
+	}
+
+	return Diagnostic{
+		Range: Range{
+			Start: Position{Line: diagnostic.Line - 1, Character: utf16Offset(line, diagnostic.Column-1)},
+			End:   Position{Line: diagnostic.EndLine - 1, Character: utf16Offset(line, diagnostic.EndColumn-1)},
+		},
+		Severity: protocolSeverity(diagnostic.Severity),
+		Code:     diagnostic.Code,
+		Source:   diagnostic.Source,
+		Message:  diagnostic.Message,
+	}
+}
+
+// utf16Offset converts a byte offset within the line into the number of UTF-16
+// code units that come before it
+func utf16Offset(line string, offset int) int {
+	if offset > len(line) {
+		offset = len(line)
+	}
+
+	return len(utf16.Encode([]rune(line[:offset])))
+}
+
+// codeActions returns an action for each vulnerable package declared within
+// the range that upgrades it to the lowest version that fixes all of its
+// vulnerabilities that have been fixed
+func (s *Server) codeActions(p CodeActionParams) []CodeAction {
+	s.mu.Lock()
+	doc, ok := s.documents[p.TextDocument.URI]
+	var result scan
+	var text string
+	if ok {
+		result = s.scans[doc.path]
+		text = doc.text
+	}
+	s.mu.Unlock()
+
+	actions := []CodeAction{}
+	if !ok {
+		return actions
+	}
+
+	// the diagnostics of each package are grouped, so that a single upgrade
+	// is offered for all of its vulnerabilities
+	type upgrade struct {
+		pkg         models.PackageInfo
+		line        int
+		version     string
+		diagnostics []Diagnostic
+	}
+	var upgrades []*upgrade
+
+	for _, diagnostic := range result.diagnostics {
+		line := diagnostic.Line - 1
+		if line < p.Range.Start.Line || line > p.Range.End.Line {
+			continue
+		}
+
+		fixed := nearestFix(diagnostic.Package, diagnostic.FixedVersions)
+		if fixed == "" {
+			continue
+		}
+
+		var u *upgrade
+		for _, existing := range upgrades {
+			if existing.line == line && existing.pkg == diagnostic.Package {
+				u = existing
+				break
+			}
+		}
+		if u == nil {
+			u = &upgrade{pkg: diagnostic.Package, line: line, version: fixed}
+			upgrades = append(upgrades, u)
+		} else if compareVersions(u.pkg.Ecosystem, u.version, fixed) < 0 {
+			u.version = fixed
+		}
+		u.diagnostics = append(u.diagnostics, protocolDiagnostic(diagnostic, result.lines))
+	}
+
+	lines := strings.Split(text, "\n")
+	for _, u := range upgrades {
+		edit, ok := upgradeEdit(lines, u.line, u.pkg.Version, u.version)
+		if !ok {
+			continue
+		}
+
+		actions = append(actions, CodeAction{
+			Title:       fmt.Sprintf("Upgrade %s to %s", u.pkg.Name, u.version),
+			Kind:        "quickfix",
+			Diagnostics: u.diagnostics,
+			IsPreferred: true,
+			Edit: WorkspaceEdit{
+				Changes: map[string][]TextEdit{p.TextDocument.URI: {edit}},
+			},
+		})
+	}
+
+	return actions
+}
+
+// compareVersions compares two versions of a package in the given ecosystem,
+// treating them as equal if the ecosystem is not supported
+func compareVersions(ecosystem string, a string, b string) int {
+	v, err := semantic.Parse(a, semantic.Ecosystem(ecosystem))
+	if err != nil {
+		return 0
+	}
+
+	return v.CompareStr(b)
+}
+
+// nearestFix returns the lowest of the fixed versions that is greater than the
+// current version of the package, or the first of them if the versions of the
+// ecosystem cannot be compared
+func nearestFix(pkg models.PackageInfo, fixedVersions []string) string {
+	if _, err := semantic.Parse(pkg.Version, semantic.Ecosystem(pkg.Ecosystem)); err != nil {
+		if len(fixedVersions) > 0 {
+			return fixedVersions[0]
+		}
+
+		return ""
+	}
+
+	var nearest string
+	for _, fixed := range fixedVersions {
+		if compareVersions(pkg.Ecosystem, pkg.Version, fixed) >= 0 {
+			continue
+		}
+		if nearest == "" || compareVersions(pkg.Ecosystem, nearest, fixed) > 0 {
+			nearest = fixed
+		}
+	}
+
+	return nearest
+}
+
+// upgradeEdit returns an edit replacing the version on the given line of the
+// document, as long as the version is still there
+func upgradeEdit(lines []string, line int, version string, fixed string) (TextEdit, bool) {
+	if line >= len(lines) || version == "" {
+		return TextEdit{}, false
+	}
+
+	text := lines[line]
+	for start := 0; start < len(text); {
+		i := strings.Index(text[start:], version)
+		if i == -1 {
+			break
+		}
+		i += start
+		end := i + len(version)
+
+		// make sure the whole version is matched, so that "1.0.0" is not
+		// found within "11.0.0", while allowing for prefixes like "v1.0.0"
+		if (i == 0 || !isVersionChar(text[i-1]) || text[i-1] == 'v') && (end == len(text) || !isVersionChar(text[end])) {
+			return TextEdit{
+				Range: Range{
+					Start: Position{Line: line, Character: utf16Offset(text, i)},
+					End:   Position{Line: line, Character: utf16Offset(text, end)},
+				},
+				NewText: fixed,
+			}, true
+		}
+
+		start = i + 1
+	}
+
+	return TextEdit{}, false
+}
+
+func isVersionChar(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '.' || c == '-' || c == '+'
+}
+
+// uriToPath returns the path of a file URI
+func uriToPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("invalid document URI %q: %w", uri, err)
+	}
+	if u.Scheme != "file" {
+		return "", fmt.Errorf("unsupported document URI %q, only file URIs are supported", uri)
+	}
+
+	path := u.Path
+	// paths on Windows are given as /C:/path/to/file
+	if runtime.GOOS == "windows" && len(path) > 2 && path[0] == '/' && path[2] == ':' {
+		path = path[1:]
+	}
+
+	return filepath.FromSlash(path), nil
+}
@@ -0,0 +1,302 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+const packageLock = `{
+  "packages": {
+    "node_modules/minimist": {
+      "version": "0.0.8"
+    }
+  }
+}
+`
+
+// client drives a server over pipes, as an editor would
+type client struct {
+	t      *testing.T
+	in     *io.PipeWriter
+	out    *bufio.Reader
+	nextID int
+}
+
+func startServer(t *testing.T, server *Server) (*client, chan error) {
+	t.Helper()
+
+	clientIn, serverOut := io.Pipe()
+	serverIn, clientOut := io.Pipe()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Serve(serverIn, serverOut)
+		serverOut.Close()
+	}()
+
+	return &client{t: t, in: clientOut, out: bufio.NewReader(clientIn)}, done
+}
+
+func (c *client) send(id *int, method string, params any) {
+	c.t.Helper()
+
+	msg := map[string]any{"jsonrpc": "2.0", "method": method, "params": params}
+	if id != nil {
+		msg["id"] = *id
+	}
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		c.t.Fatalf("could not encode message: %v", err)
+	}
+
+	if _, err := fmt.Fprintf(c.in, "Content-Length: %d\r\n\r\n%s", len(body), body); err != nil {
+		c.t.Fatalf("could not send message: %v", err)
+	}
+}
+
+func (c *client) notify(method string, params any) {
+	c.t.Helper()
+	c.send(nil, method, params)
+}
+
+// request sends a request and returns the response to it
+func (c *client) request(method string, params any) message {
+	c.t.Helper()
+
+	c.nextID++
+	id := c.nextID
+	c.send(&id, method, params)
+
+	for {
+		msg := c.read()
+		if msg.ID != nil && string(*msg.ID) == fmt.Sprint(id) {
+			return msg
+		}
+	}
+}
+
+// receive returns the params of the next notification of the given method
+func (c *client) receive(method string, params any) {
+	c.t.Helper()
+
+	for {
+		msg := c.read()
+		if msg.Method != method {
+			continue
+		}
+
+		if err := json.Unmarshal(msg.Params, params); err != nil {
+			c.t.Fatalf("could not decode %s params: %v", method, err)
+		}
+
+		return
+	}
+}
+
+func (c *client) read() message {
+	c.t.Helper()
+
+	msg, err := readMessage(c.out)
+	if err != nil {
+		c.t.Fatalf("could not read message: %v", err)
+	}
+
+	return msg
+}
+
+func decodeResult[T any](t *testing.T, msg message) T {
+	t.Helper()
+
+	var result T
+	if msg.Error != nil {
+		t.Fatalf("unexpected error response: %v", msg.Error)
+	}
+
+	raw, _ := json.Marshal(msg.Result)
+	if err := json.Unmarshal(raw, &result); err != nil {
+		t.Fatalf("could not decode result: %v", err)
+	}
+
+	return result
+}
+
+func fakeScanner(scans *int32) ScanFunc {
+	return func(path string, r output.Reporter) (models.VulnerabilityResults, error) {
+		atomic.AddInt32(scans, 1)
+
+		var vuln models.Vulnerability
+		err := json.Unmarshal([]byte(`{
+			"id": "GHSA-vh95-rmgr-6w4m",
+			"summary": "Prototype Pollution in minimist",
+			"affected": [{
+				"package": {"ecosystem": "npm", "name": "minimist"},
+				"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "0.2.1"}, {"introduced": "1.0.0"}, {"fixed": "1.2.3"}]}]
+			}],
+			"database_specific": {"severity": "HIGH"}
+		}`), &vuln)
+		if err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+
+		return models.VulnerabilityResults{
+			Results: []models.PackageSource{{
+				Source: models.SourceInfo{Path: path, Type: "lockfile"},
+				Packages: []models.PackageVulns{{
+					Package:         models.PackageInfo{Name: "minimist", Version: "0.0.8", Ecosystem: "npm"},
+					Vulnerabilities: []models.Vulnerability{vuln},
+					Groups:          []models.GroupInfo{{IDs: []string{vuln.ID}}},
+					Location:        &models.SourceLocation{Line: 4, Column: 19, EndColumn: 24},
+				}},
+			}},
+		}, nil
+	}
+}
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "package-lock.json")
+	if err := os.WriteFile(path, []byte(packageLock), 0600); err != nil {
+		t.Fatalf("could not write lockfile: %v", err)
+	}
+	uri := "file://" + filepath.ToSlash(path)
+
+	var scans int32
+	c, done := startServer(t, &Server{Version: "1.2.3", Scan: fakeScanner(&scans)})
+
+	initialized := decodeResult[InitializeResult](t, c.request("initialize", map[string]any{}))
+	if initialized.ServerInfo.Name != "osv-scanner" || initialized.Capabilities.TextDocumentSync.Change != TextDocumentSyncKindFull {
+		t.Errorf("unexpected initialize result: %+v", initialized)
+	}
+	c.notify("initialized", map[string]any{})
+
+	c.notify("textDocument/didOpen", DidOpenTextDocumentParams{
+		TextDocument: TextDocumentItem{URI: uri, LanguageID: "json", Version: 1, Text: packageLock},
+	})
+
+	var published PublishDiagnosticsParams
+	c.receive("textDocument/publishDiagnostics", &published)
+
+	want := PublishDiagnosticsParams{
+		URI: uri,
+		Diagnostics: []Diagnostic{{
+			Range:    Range{Start: Position{Line: 3, Character: 18}, End: Position{Line: 3, Character: 23}},
+			Severity: SeverityError,
+			Code:     "GHSA-vh95-rmgr-6w4m",
+			Source:   "osv-scanner",
+			Message:  "minimist@0.0.8 is affected by GHSA-vh95-rmgr-6w4m: Prototype Pollution in minimist (fixed in 0.2.1, 1.2.3)",
+		}},
+	}
+	if diff := cmp.Diff(want, published); diff != "" {
+		t.Errorf("published diagnostics mismatch (-want +got):\n%s", diff)
+	}
+
+	actions := decodeResult[[]CodeAction](t, c.request("textDocument/codeAction", CodeActionParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+		Range:        Range{Start: Position{Line: 3}, End: Position{Line: 3, Character: 10}},
+	}))
+	wantActions := []CodeAction{{
+		Title:       "Upgrade minimist to 0.2.1",
+		Kind:        "quickfix",
+		Diagnostics: want.Diagnostics,
+		IsPreferred: true,
+		Edit: WorkspaceEdit{Changes: map[string][]TextEdit{uri: {{
+			Range:   Range{Start: Position{Line: 3, Character: 18}, End: Position{Line: 3, Character: 23}},
+			NewText: "0.2.1",
+		}}}},
+	}}
+	if diff := cmp.Diff(wantActions, actions); diff != "" {
+		t.Errorf("code actions mismatch (-want +got):\n%s", diff)
+	}
+
+	// saving without changing the lockfile reuses the previous scan
+	c.notify("textDocument/didSave", DidSaveTextDocumentParams{TextDocument: TextDocumentIdentifier{URI: uri}})
+	c.receive("textDocument/publishDiagnostics", &published)
+	if got := atomic.LoadInt32(&scans); got != 1 {
+		t.Errorf("expected an unchanged lockfile to not be rescanned, but it was scanned %d times", got)
+	}
+
+	if err := os.WriteFile(path, []byte(packageLock+"\n"), 0600); err != nil {
+		t.Fatalf("could not write lockfile: %v", err)
+	}
+	c.notify("textDocument/didSave", DidSaveTextDocumentParams{TextDocument: TextDocumentIdentifier{URI: uri}})
+	c.receive("textDocument/publishDiagnostics", &published)
+	if got := atomic.LoadInt32(&scans); got != 2 {
+		t.Errorf("expected a changed lockfile to be rescanned, but it was scanned %d times", got)
+	}
+
+	c.notify("textDocument/didClose", DidCloseTextDocumentParams{TextDocument: TextDocumentIdentifier{URI: uri}})
+	c.receive("textDocument/publishDiagnostics", &published)
+	if len(published.Diagnostics) != 0 {
+		t.Errorf("expected diagnostics to be cleared when the lockfile is closed, got %v", published.Diagnostics)
+	}
+
+	if msg := c.request("workspace/symbol", map[string]any{}); msg.Error == nil || msg.Error.Code != codeMethodNotFound {
+		t.Errorf("expected unsupported requests to fail with method not found, got %+v", msg)
+	}
+
+	c.request("shutdown", nil)
+	c.notify("exit", nil)
+
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error from Serve: %v", err)
+	}
+}
+
+func TestNearestFix(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		version string
+		fixed   []string
+		want    string
+	}{
+		{version: "0.0.8", fixed: []string{"0.2.1", "1.2.3"}, want: "0.2.1"},
+		{version: "1.1.0", fixed: []string{"0.2.1", "1.2.3"}, want: "1.2.3"},
+		{version: "1.1.0", fixed: []string{"1.10.0", "1.2.3"}, want: "1.2.3"},
+		{version: "2.0.0", fixed: []string{"0.2.1", "1.2.3"}, want: ""},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.version, func(t *testing.T) {
+			t.Parallel()
+
+			pkg := models.PackageInfo{Name: "minimist", Version: tt.version, Ecosystem: "npm"}
+			if got := nearestFix(pkg, tt.fixed); got != tt.want {
+				t.Errorf("nearestFix(%s, %v) = %q, want %q", tt.version, tt.fixed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpgradeEdit(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{`    "version": "11.0.0", "resolved": "1.0.0"`, `require example.com/mod v1.0.0`}
+
+	edit, ok := upgradeEdit(lines, 0, "1.0.0", "1.0.1")
+	if !ok || edit.Range.Start.Character != 38 {
+		t.Errorf("expected the whole version to be replaced, got %+v", edit)
+	}
+
+	edit, ok = upgradeEdit(lines, 1, "1.0.0", "1.0.1")
+	if !ok || edit.Range.Start.Character != 25 {
+		t.Errorf("expected a prefixed version to be replaced, got %+v", edit)
+	}
+
+	if _, ok := upgradeEdit(lines, 1, "2.0.0", "2.0.1"); ok {
+		t.Errorf("expected no edit when the version is not on the line")
+	}
+}
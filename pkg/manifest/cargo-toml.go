@@ -0,0 +1,86 @@
+package manifest
+
+import (
+	"sort"
+
+	"github.com/BurntSushi/toml"
+)
+
+type cargoToml struct {
+	Dependencies      map[string]interface{} `toml:"dependencies"`
+	DevDependencies   map[string]interface{} `toml:"dev-dependencies"`
+	BuildDependencies map[string]interface{} `toml:"build-dependencies"`
+	Target            map[string]struct {
+		Dependencies      map[string]interface{} `toml:"dependencies"`
+		DevDependencies   map[string]interface{} `toml:"dev-dependencies"`
+		BuildDependencies map[string]interface{} `toml:"build-dependencies"`
+	} `toml:"target"`
+}
+
+// cargoDependency returns the crate and version requirement of a dependency,
+// which is either just the requirement or a table that can also rename the
+// crate; dependencies on paths and git repositories have no requirement
+func cargoDependency(key string, declared interface{}) (string, string) {
+	switch declared := declared.(type) {
+	case string:
+		return key, declared
+	case map[string]interface{}:
+		name := key
+		if pkg, ok := declared["package"].(string); ok {
+			name = pkg
+		}
+		if _, ok := declared["git"]; ok {
+			return name, ""
+		}
+		version, _ := declared["version"].(string)
+
+		return name, version
+	}
+
+	return key, ""
+}
+
+func parseCargoToml(content []byte) ([]Dependency, error) {
+	var manifest cargoToml
+	if _, err := toml.Decode(string(content), &manifest); err != nil {
+		return nil, err
+	}
+
+	var dependencies []Dependency
+	add := func(declared map[string]interface{}, dev bool) {
+		keys := make([]string, 0, len(declared))
+		for key := range declared {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			name, requirement := cargoDependency(key, declared[key])
+			if requirement == "" {
+				continue
+			}
+
+			dependencies = append(dependencies, Dependency{Name: name, Requirement: requirement, Dev: dev})
+		}
+	}
+
+	add(manifest.Dependencies, false)
+	add(manifest.BuildDependencies, false)
+
+	targets := make([]string, 0, len(manifest.Target))
+	for target := range manifest.Target {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+	for _, target := range targets {
+		add(manifest.Target[target].Dependencies, false)
+		add(manifest.Target[target].BuildDependencies, false)
+	}
+
+	add(manifest.DevDependencies, true)
+	for _, target := range targets {
+		add(manifest.Target[target].DevDependencies, true)
+	}
+
+	return dependencies, nil
+}
@@ -0,0 +1,107 @@
+// Package manifest reads the dependencies that manifests such as package.json
+// and Cargo.toml files declare, and resolves their requirements to versions,
+// for projects whose dependencies have not been locked
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrNotManifest is returned when a file is not a supported manifest
+var ErrNotManifest = errors.New("not a supported manifest")
+
+// Dependency is a package that a manifest declares a dependency on
+type Dependency struct {
+	Name      string
+	Ecosystem string
+	// Requirement is the range of versions that the manifest allows, such as
+	// "^4.17.0"
+	Requirement string
+	// Dev is set for dependencies that are only needed for development
+	Dev bool
+}
+
+type manifestFormat struct {
+	ecosystem string
+	parse     func(content []byte) ([]Dependency, error)
+	// lockfiles are the names of the lockfiles that lock the dependencies of
+	// the manifest, which can be in the directory of a workspace above it
+	lockfiles []string
+}
+
+var formats = map[string]manifestFormat{
+	"package.json": {
+		ecosystem: "npm",
+		parse:     parsePackageJSON,
+		lockfiles: []string{"package-lock.json", "npm-shrinkwrap.json", "yarn.lock", "pnpm-lock.yaml"},
+	},
+	"Cargo.toml": {
+		ecosystem: "crates.io",
+		parse:     parseCargoToml,
+		lockfiles: []string{"Cargo.lock"},
+	},
+}
+
+// IsManifest reports if the file at the given path is a supported manifest
+func IsManifest(path string) bool {
+	_, ok := formats[filepath.Base(path)]
+
+	return ok
+}
+
+// Parse returns the dependencies that the manifest at the given path declares
+func Parse(path string) ([]Dependency, error) {
+	format, ok := formats[filepath.Base(path)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotManifest, path)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", path, err)
+	}
+
+	dependencies, err := format.parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", path, err)
+	}
+
+	for i := range dependencies {
+		dependencies[i].Ecosystem = format.ecosystem
+	}
+
+	return dependencies, nil
+}
+
+// FindLockfile returns the path of the lockfile that locks the dependencies
+// of the manifest at the given path, looking in its directory and those above
+// it for workspaces, or "" if there is none
+func FindLockfile(path string) string {
+	format, ok := formats[filepath.Base(path)]
+	if !ok {
+		return ""
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return ""
+	}
+
+	for {
+		for _, name := range format.lockfiles {
+			lockfilePath := filepath.Join(dir, name)
+			if _, err := os.Stat(lockfilePath); err == nil {
+				return lockfilePath
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,141 @@
+package manifest_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/manifest"
+)
+
+func TestParse_PackageJSON(t *testing.T) {
+	t.Parallel()
+
+	dependencies, err := manifest.Parse("fixtures/npm/package.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []manifest.Dependency{
+		{Name: "lodash", Ecosystem: "npm", Requirement: "^4.17.0"},
+		{Name: "fsevents", Ecosystem: "npm", Requirement: "~2.3.2"},
+		{Name: "jest", Ecosystem: "npm", Requirement: ">=29.0.0 <30", Dev: true},
+	}
+
+	if diff := cmp.Diff(want, dependencies); diff != "" {
+		t.Errorf("Parse() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParse_CargoToml(t *testing.T) {
+	t.Parallel()
+
+	dependencies, err := manifest.Parse("fixtures/cargo/Cargo.toml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []manifest.Dependency{
+		{Name: "rand", Ecosystem: "crates.io", Requirement: "0.8"},
+		{Name: "serde", Ecosystem: "crates.io", Requirement: "1.0"},
+		{Name: "tokio", Ecosystem: "crates.io", Requirement: "1.28"},
+		{Name: "winapi", Ecosystem: "crates.io", Requirement: "0.3"},
+		{Name: "criterion", Ecosystem: "crates.io", Requirement: "0.5", Dev: true},
+	}
+
+	if diff := cmp.Diff(want, dependencies); diff != "" {
+		t.Errorf("Parse() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParse_NotManifest(t *testing.T) {
+	t.Parallel()
+
+	_, err := manifest.Parse("fixtures/npm-locked/package-lock.json")
+	if !errors.Is(err, manifest.ErrNotManifest) {
+		t.Errorf("expected ErrNotManifest but got %v", err)
+	}
+}
+
+func TestFindLockfile(t *testing.T) {
+	t.Parallel()
+
+	abs := func(path string) string {
+		t.Helper()
+
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return abs
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "fixtures/npm/package.json", want: ""},
+		{path: "fixtures/npm-locked/package.json", want: abs("fixtures/npm-locked/package-lock.json")},
+		{path: "fixtures/npm-workspace/packages/app/package.json", want: abs("fixtures/npm-workspace/yarn.lock")},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			if got := manifest.FindLockfile(tt.path); got != tt.want {
+				t.Errorf("FindLockfile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	npmVersions := []string{"0.0.3", "0.0.4", "0.2.0", "0.2.5", "0.3.0", "1.0.0", "1.2.0", "1.2.9", "1.3.0", "2.0.0-beta.1", "2.0.0-beta.2", "2.0.0", "2.1.0"}
+
+	tests := []struct {
+		requirement string
+		ecosystem   string
+		want        string
+	}{
+		{requirement: "^1.2.0", ecosystem: "npm", want: "1.3.0"},
+		{requirement: "~1.2.0", ecosystem: "npm", want: "1.2.9"},
+		{requirement: "^0.2.1", ecosystem: "npm", want: "0.2.5"},
+		{requirement: "^0.0.3", ecosystem: "npm", want: "0.0.3"},
+		{requirement: "1.2.0", ecosystem: "npm", want: "1.2.0"},
+		{requirement: "1.x", ecosystem: "npm", want: "1.3.0"},
+		{requirement: "1", ecosystem: "npm", want: "1.3.0"},
+		{requirement: "*", ecosystem: "npm", want: "2.1.0"},
+		{requirement: "latest", ecosystem: "npm", want: "2.1.0"},
+		{requirement: ">= 1.0.0 < 2", ecosystem: "npm", want: "1.3.0"},
+		{requirement: ">1.2", ecosystem: "npm", want: "2.1.0"},
+		{requirement: "<=1.2", ecosystem: "npm", want: "1.2.9"},
+		{requirement: "1.0.0 - 1.2", ecosystem: "npm", want: "1.2.9"},
+		{requirement: "^0.2.0 || ^1.0.0", ecosystem: "npm", want: "1.3.0"},
+		{requirement: "2.0.0-beta.1", ecosystem: "npm", want: "2.0.0-beta.1"},
+		{requirement: ">=2.0.0-beta.1 <2.0.0", ecosystem: "npm", want: "2.0.0-beta.2"},
+		{requirement: "^3.0.0", ecosystem: "npm", want: ""},
+		{requirement: "not a version", ecosystem: "npm", want: ""},
+		{requirement: "1.2", ecosystem: "crates.io", want: "1.3.0"},
+		{requirement: "0.2", ecosystem: "crates.io", want: "0.2.5"},
+		{requirement: "=1.2.0", ecosystem: "crates.io", want: "1.2.0"},
+		{requirement: ">=1.0, <1.3", ecosystem: "crates.io", want: "1.2.9"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.ecosystem+" "+tt.requirement, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := manifest.Resolve(manifest.Dependency{Name: "example", Ecosystem: tt.ecosystem, Requirement: tt.requirement}, npmVersions)
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("Resolve() = (%q, %v), want %q", got, ok, tt.want)
+			}
+		})
+	}
+}
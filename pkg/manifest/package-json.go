@@ -0,0 +1,57 @@
+package manifest
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+type packageJSON struct {
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+}
+
+// npmRegistryRequirement reports if the requirement is a range of versions
+// from the registry, rather than a path, URL, git repository, workspace or
+// alias of another package
+func npmRegistryRequirement(requirement string) bool {
+	for _, prefix := range []string{"file:", "link:", "workspace:", "npm:", "git", "http:", "https:", "github:"} {
+		if strings.HasPrefix(requirement, prefix) {
+			return false
+		}
+	}
+
+	// "user/repo" is shorthand for a GitHub repository
+	return !strings.Contains(requirement, "/")
+}
+
+func parsePackageJSON(content []byte) ([]Dependency, error) {
+	var manifest packageJSON
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, err
+	}
+
+	var dependencies []Dependency
+	add := func(declared map[string]string, dev bool) {
+		names := make([]string, 0, len(declared))
+		for name := range declared {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if !npmRegistryRequirement(declared[name]) {
+				continue
+			}
+
+			dependencies = append(dependencies, Dependency{Name: name, Requirement: declared[name], Dev: dev})
+		}
+	}
+
+	add(manifest.Dependencies, false)
+	add(manifest.OptionalDependencies, false)
+	add(manifest.DevDependencies, true)
+
+	return dependencies, nil
+}
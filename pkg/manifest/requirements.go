@@ -0,0 +1,289 @@
+package manifest
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/semantic"
+)
+
+// comparator is a bound on versions, such as ">=1.2.0"
+type comparator struct {
+	// op is one of ">=", ">", "<=" or "<"
+	op      string
+	version string
+}
+
+// partialVersion is a version that can be missing its minor and patch, such
+// as the "1.2" of "~1.2"
+type partialVersion struct {
+	parts      [3]int
+	given      int
+	prerelease string
+}
+
+var partialVersionPattern = regexp.MustCompile(`^v?(\d+|[xX*])(?:\.(\d+|[xX*]))?(?:\.(\d+|[xX*]))?(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+func parsePartialVersion(str string) (partialVersion, bool) {
+	match := partialVersionPattern.FindStringSubmatch(str)
+	if match == nil {
+		return partialVersion{}, false
+	}
+
+	var v partialVersion
+	for i := 0; i < 3; i++ {
+		n, err := strconv.Atoi(match[i+1])
+		if err != nil {
+			// the parts after a wildcard or missing part are also wildcards
+			break
+		}
+		v.parts[i] = n
+		v.given++
+	}
+	if v.given == 3 {
+		v.prerelease = match[4]
+	}
+
+	return v, true
+}
+
+func (v partialVersion) String() string {
+	str := strconv.Itoa(v.parts[0]) + "." + strconv.Itoa(v.parts[1]) + "." + strconv.Itoa(v.parts[2])
+	if v.prerelease != "" {
+		str += "-" + v.prerelease
+	}
+
+	return str
+}
+
+// bump returns the lowest version that is after every version that matches
+// the first n parts of v, such as 1.3.0 for the first two parts of 1.2.3
+func (v partialVersion) bump(n int) partialVersion {
+	bumped := partialVersion{given: 3}
+	copy(bumped.parts[:n], v.parts[:n])
+	bumped.parts[n-1]++
+
+	return bumped
+}
+
+// caretUpper returns the upper bound of ^v, which allows changes that do not
+// modify the left-most non-zero part
+func (v partialVersion) caretUpper() partialVersion {
+	switch {
+	case v.parts[0] > 0 || v.given == 1:
+		return v.bump(1)
+	case v.parts[1] > 0 || v.given == 2:
+		return v.bump(2)
+	default:
+		return v.bump(3)
+	}
+}
+
+// tildeUpper returns the upper bound of ~v, which allows patch changes, or
+// minor changes if only the major part is given
+func (v partialVersion) tildeUpper() partialVersion {
+	if v.given == 1 {
+		return v.bump(1)
+	}
+
+	return v.bump(2)
+}
+
+// parseComparators parses one of the parts of a requirement, such as "^1.2",
+// into the bounds it places on versions, with bareCaret treating a version
+// without an operator as a caret requirement, as Cargo does
+func parseComparators(term string, bareCaret bool) ([]comparator, bool) {
+	op := ""
+	for _, prefix := range []string{">=", "<=", ">", "<", "=", "^", "~>", "~"} {
+		if strings.HasPrefix(term, prefix) {
+			op = prefix
+			term = strings.TrimSpace(strings.TrimPrefix(term, prefix))
+
+			break
+		}
+	}
+	if op == "" && bareCaret {
+		op = "^"
+	}
+
+	v, ok := parsePartialVersion(term)
+	if !ok {
+		return nil, false
+	}
+
+	if v.given == 0 {
+		// "*" and the like allow any version, as do bounds on them
+		return nil, true
+	}
+
+	switch op {
+	case ">=":
+		return []comparator{{">=", v.String()}}, true
+	case ">":
+		if v.given < 3 {
+			return []comparator{{">=", v.bump(v.given).String()}}, true
+		}
+
+		return []comparator{{">", v.String()}}, true
+	case "<":
+		return []comparator{{"<", v.String()}}, true
+	case "<=":
+		if v.given < 3 {
+			return []comparator{{"<", v.bump(v.given).String()}}, true
+		}
+
+		return []comparator{{"<=", v.String()}}, true
+	case "^":
+		return []comparator{{">=", v.String()}, {"<", v.caretUpper().String()}}, true
+	case "~", "~>":
+		return []comparator{{">=", v.String()}, {"<", v.tildeUpper().String()}}, true
+	}
+
+	// an exact version, or a range of versions with wildcards
+	if v.given < 3 {
+		return []comparator{{">=", v.String()}, {"<", v.bump(v.given).String()}}, true
+	}
+
+	return []comparator{{">=", v.String()}, {"<=", v.String()}}, true
+}
+
+// requirementSet is a set of comparators that a version must satisfy all of
+type requirementSet []comparator
+
+// parseRequirement parses a requirement into the sets of comparators that it
+// allows versions to satisfy any of, with npm and Cargo syntax differing in
+// how comparators are separated and what a bare version means
+func parseRequirement(requirement string, ecosystem string) ([]requirementSet, bool) {
+	requirement = strings.TrimSpace(requirement)
+	if requirement == "" || requirement == "latest" {
+		requirement = "*"
+	}
+
+	cargo := ecosystem == "crates.io"
+
+	var sets []requirementSet
+	for _, alternative := range strings.Split(requirement, "||") {
+		var set requirementSet
+
+		var terms []string
+		if cargo {
+			terms = strings.Split(alternative, ",")
+		} else if from, to, ok := strings.Cut(strings.TrimSpace(alternative), " - "); ok {
+			// a hyphen range, which includes every version matching its end
+			terms = []string{">=" + strings.TrimSpace(from), "<=" + strings.TrimSpace(to)}
+		} else {
+			terms = splitNpmTerms(alternative)
+		}
+
+		for _, term := range terms {
+			term = strings.TrimSpace(term)
+			if term == "" {
+				continue
+			}
+
+			comparators, ok := parseComparators(term, cargo)
+			if !ok {
+				return nil, false
+			}
+			set = append(set, comparators...)
+		}
+
+		sets = append(sets, set)
+	}
+
+	return sets, true
+}
+
+// splitNpmTerms splits the whitespace separated terms of an npm range,
+// keeping operators together with the versions they are separated from
+func splitNpmTerms(alternative string) []string {
+	var terms []string
+	pending := ""
+	for _, field := range strings.Fields(alternative) {
+		if strings.Trim(field, "<>=^~") == "" {
+			pending += field
+
+			continue
+		}
+		terms = append(terms, pending+field)
+		pending = ""
+	}
+
+	return terms
+}
+
+// allowsPrerelease reports if a pre-release is allowed by the set, which is
+// only when one of its comparators is a pre-release of the same version
+func (set requirementSet) allowsPrerelease(version partialVersion) bool {
+	for _, c := range set {
+		bound, ok := parsePartialVersion(c.version)
+		if ok && bound.prerelease != "" && bound.parts == version.parts {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (set requirementSet) matches(version string, ecosystem string) bool {
+	v, ok := parsePartialVersion(version)
+	if !ok || v.given < 3 {
+		return false
+	}
+	if v.prerelease != "" && !set.allowsPrerelease(v) {
+		return false
+	}
+
+	parsed, err := semantic.Parse(version, semantic.Ecosystem(ecosystem))
+	if err != nil {
+		return false
+	}
+
+	for _, c := range set {
+		cmp := parsed.CompareStr(c.version)
+		switch {
+		case c.op == ">=" && cmp < 0,
+			c.op == ">" && cmp <= 0,
+			c.op == "<=" && cmp > 0,
+			c.op == "<" && cmp >= 0:
+			return false
+		}
+	}
+
+	return true
+}
+
+// Resolve returns the highest of the given versions that the requirement of
+// the dependency allows, as the package manager would pick when installing
+// it afresh, reporting false if none of them are allowed or the requirement
+// is not understood
+func Resolve(dependency Dependency, versions []string) (string, bool) {
+	sets, ok := parseRequirement(dependency.Requirement, dependency.Ecosystem)
+	if !ok {
+		return "", false
+	}
+
+	best := ""
+	var bestParsed semantic.Version
+	for _, version := range versions {
+		allowed := false
+		for _, set := range sets {
+			if set.matches(version, dependency.Ecosystem) {
+				allowed = true
+
+				break
+			}
+		}
+		if !allowed {
+			continue
+		}
+
+		if bestParsed == nil || bestParsed.CompareStr(version) < 0 {
+			bestParsed = semantic.MustParse(version, semantic.Ecosystem(dependency.Ecosystem))
+			best = version
+		}
+	}
+
+	return best, best != ""
+}
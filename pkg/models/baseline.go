@@ -0,0 +1,98 @@
+package models
+
+// findingKeys identify a group of vulnerabilities in a package, being the
+// fingerprint of the group so that findings are matched in the same way as
+// by anything else that tracks them across scans. Groups from results that
+// were written before fingerprints were recorded are instead identified by
+// each of their ids along with the name and ecosystem of their package.
+func findingKeys(pkg PackageInfo, group GroupInfo) []string {
+	if group.Fingerprint != "" {
+		return []string{group.Fingerprint}
+	}
+
+	keys := make([]string, 0, len(group.IDs))
+	for _, id := range group.IDs {
+		keys = append(keys, pkg.Ecosystem+"/"+pkg.Name+"/"+id)
+	}
+
+	return keys
+}
+
+// NewSince returns the results of each source with any vulnerabilities that
+// are also present in `base` removed, dropping packages and sources that are
+// left with no vulnerabilities, other than incomplete sources so that it is
+// still known that they could not be fully checked
+func (vulns *VulnerabilityResults) NewSince(base *VulnerabilityResults) []PackageSource {
+	known := map[string]bool{}
+	for _, source := range base.Results {
+		for _, pkg := range source.Packages {
+			for _, group := range pkg.Groups {
+				for _, key := range findingKeys(pkg.Package, group) {
+					known[key] = true
+				}
+			}
+		}
+	}
+
+	var filtered []PackageSource
+
+	for _, source := range vulns.Results {
+		packages := []PackageVulns{}
+
+		for _, pkg := range source.Packages {
+			newIDs := map[string]bool{}
+			var groups []GroupInfo
+
+			// vulnerabilities are grouped with their aliases, so a group is only
+			// new if none of the vulnerabilities within it were already known,
+			// including by a base without fingerprints
+			for _, group := range pkg.Groups {
+				keys := findingKeys(pkg.Package, group)
+				if group.Fingerprint != "" {
+					keys = append(keys, findingKeys(pkg.Package, GroupInfo{IDs: group.IDs})...)
+				}
+
+				isKnown := false
+				for _, key := range keys {
+					if known[key] {
+						isKnown = true
+						break
+					}
+				}
+
+				if isKnown {
+					continue
+				}
+
+				groups = append(groups, group)
+				for _, id := range group.IDs {
+					newIDs[id] = true
+				}
+			}
+
+			if len(groups) == 0 {
+				continue
+			}
+
+			var kept []Vulnerability
+			for _, vuln := range pkg.Vulnerabilities {
+				if newIDs[vuln.ID] {
+					kept = append(kept, vuln)
+				}
+			}
+
+			pkg.Vulnerabilities = kept
+			pkg.Groups = groups
+			packages = append(packages, pkg)
+		}
+
+		if len(packages) == 0 && !source.Incomplete {
+			continue
+		}
+
+		source.Packages = packages
+		filtered = append(filtered, source)
+	}
+
+	return filtered
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"math"
+	"strings"
+)
+
+// cvssV3Weights are the weights of the values of the base metrics of CVSS v3,
+// other than privileges required, which depends on the scope
+var cvssV3Weights = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"UI": {"N": 0.85, "R": 0.62},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+}
+
+// cvssV3PrivilegesRequired are the weights of privileges required, which are
+// higher when the scope is changed
+var cvssV3PrivilegesRequired = map[string][2]float64{
+	"N": {0.85, 0.85},
+	"L": {0.62, 0.68},
+	"H": {0.27, 0.5},
+}
+
+// cvssRoundUp rounds up to one decimal place, as defined by the CVSS v3.1
+// specification to avoid floating point errors
+func cvssRoundUp(value float64) float64 {
+	scaled := int(math.Round(value * 100000))
+	if scaled%10000 == 0 {
+		return float64(scaled) / 100000
+	}
+
+	return float64(scaled/10000+1) / 10
+}
+
+// CVSSV3BaseScore calculates the base score of a CVSS v3 vector, such as
+// "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", reporting if the vector
+// is valid
+func CVSSV3BaseScore(vector string) (float64, bool) {
+	parts := strings.Split(vector, "/")
+	if len(parts) == 0 || !strings.HasPrefix(parts[0], "CVSS:3.") {
+		return 0, false
+	}
+
+	metrics := map[string]string{}
+	for _, part := range parts[1:] {
+		metric, value, ok := strings.Cut(part, ":")
+		if !ok {
+			return 0, false
+		}
+		metrics[metric] = value
+	}
+
+	weights := map[string]float64{}
+	for metric, values := range cvssV3Weights {
+		weight, ok := values[metrics[metric]]
+		if !ok {
+			return 0, false
+		}
+		weights[metric] = weight
+	}
+
+	changed := metrics["S"] == "C"
+	if !changed && metrics["S"] != "U" {
+		return 0, false
+	}
+
+	privileges, ok := cvssV3PrivilegesRequired[metrics["PR"]]
+	if !ok {
+		return 0, false
+	}
+	privilegesRequired := privileges[0]
+	if changed {
+		privilegesRequired = privileges[1]
+	}
+
+	iss := 1 - (1-weights["C"])*(1-weights["I"])*(1-weights["A"])
+	impact := 6.42 * iss
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0, true
+	}
+
+	exploitability := 8.22 * weights["AV"] * weights["AC"] * privilegesRequired * weights["UI"]
+	if changed {
+		return cvssRoundUp(math.Min(1.08*(impact+exploitability), 10)), true
+	}
+
+	return cvssRoundUp(math.Min(impact+exploitability, 10)), true
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Fingerprint returns an identifier for a finding that stays the same across
+// scans, so long as the vulnerability is still present in the same package
+// from the same source - in particular, it does not change when the package
+// is updated to another vulnerable version.
+//
+// sourcePath should be relative to the root of the project being scanned, so
+// that the fingerprint is the same regardless of where the project is checked out.
+func Fingerprint(sourcePath string, pkg PackageInfo, ids []string) string {
+	sortedIDs := make([]string, len(ids))
+	copy(sortedIDs, ids)
+	sort.Strings(sortedIDs)
+
+	hash := sha256.New()
+	for _, part := range []string{
+		filepath.ToSlash(sourcePath),
+		pkg.Ecosystem,
+		pkg.Name,
+		strings.Join(sortedIDs, ","),
+	} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,70 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	pkg := models.PackageInfo{Name: "minimist", Version: "0.0.8", Ecosystem: "npm"}
+	fingerprint := models.Fingerprint("path/to/package-lock.json", pkg, []string{"GHSA-vh95-rmgr-6w4m", "CVE-2020-7598"})
+
+	if len(fingerprint) != 64 {
+		t.Errorf("expected a sha256 hex digest, got %s", fingerprint)
+	}
+
+	same := []struct {
+		name string
+		got  string
+	}{
+		{
+			name: "ids in a different order",
+			got:  models.Fingerprint("path/to/package-lock.json", pkg, []string{"CVE-2020-7598", "GHSA-vh95-rmgr-6w4m"}),
+		},
+		{
+			name: "different version",
+			got: models.Fingerprint(
+				"path/to/package-lock.json",
+				models.PackageInfo{Name: "minimist", Version: "1.2.0", Ecosystem: "npm"},
+				[]string{"GHSA-vh95-rmgr-6w4m", "CVE-2020-7598"},
+			),
+		},
+	}
+
+	for _, tt := range same {
+		if tt.got != fingerprint {
+			t.Errorf("expected fingerprint to be the same with %s", tt.name)
+		}
+	}
+
+	different := []struct {
+		name string
+		got  string
+	}{
+		{
+			name: "different source",
+			got:  models.Fingerprint("path/to/other/package-lock.json", pkg, []string{"GHSA-vh95-rmgr-6w4m", "CVE-2020-7598"}),
+		},
+		{
+			name: "different package",
+			got: models.Fingerprint(
+				"path/to/package-lock.json",
+				models.PackageInfo{Name: "minimist2", Version: "0.0.8", Ecosystem: "npm"},
+				[]string{"GHSA-vh95-rmgr-6w4m", "CVE-2020-7598"},
+			),
+		},
+		{
+			name: "different ids",
+			got:  models.Fingerprint("path/to/package-lock.json", pkg, []string{"GHSA-vh95-rmgr-6w4m"}),
+		},
+	}
+
+	for _, tt := range different {
+		if tt.got == fingerprint {
+			t.Errorf("expected fingerprint to be different with %s", tt.name)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// FixedVersions returns the versions that fix the vulnerability for the given
+// package, in the order they are listed by the database
+func (v Vulnerability) FixedVersions(pkg PackageInfo) []string {
+	var fixed []string
+
+	for _, affected := range v.Affected {
+		if affected.Package.Name != pkg.Name || !strings.EqualFold(affected.Package.Ecosystem, pkg.Ecosystem) {
+			continue
+		}
+
+		for _, r := range affected.Ranges {
+			for _, event := range r.Events {
+				if event.Fixed != "" && !slices.Contains(fixed, event.Fixed) {
+					fixed = append(fixed, event.Fixed)
+				}
+			}
+		}
+	}
+
+	return fixed
+}
@@ -5,6 +5,307 @@ import "time"
 // Combined vulnerabilities found for the scanned packages
 type VulnerabilityResults struct {
 	Results []PackageSource `json:"results"`
+	// ParseFailures lists the files that were found but could not be parsed
+	ParseFailures []ParseFailure `json:"parseFailures,omitempty"`
+	// Skipped lists the inputs that were found but could not be scanned
+	Skipped []SkippedSource `json:"skipped,omitempty"`
+	// Targets summarises the results of each target, when scanning a targets file
+	Targets []TargetSummary `json:"targets,omitempty"`
+	// RegistryIssues lists the packages that do not match their public
+	// registry, when registries are checked
+	RegistryIssues []RegistryIssue `json:"registryIssues,omitempty"`
+	// SuspiciousPackages lists the packages that are known to be malicious,
+	// or whose names are likely typosquats when typosquats are detected
+	SuspiciousPackages []SuspiciousPackage `json:"suspiciousPackages,omitempty"`
+	// UnusedIgnores lists the ignores of the config files that were used
+	// which did not match any of the vulnerabilities found
+	UnusedIgnores []UnusedIgnore `json:"unusedIgnores,omitempty"`
+	// Outdated lists the packages that are behind the latest version on their
+	// registry, when packages are checked for being outdated
+	Outdated []OutdatedPackage `json:"outdated,omitempty"`
+	// Deprecated lists the packages that have been deprecated, yanked or are
+	// unmaintained, when packages are checked for being deprecated
+	Deprecated []DeprecatedPackage `json:"deprecated,omitempty"`
+	// Scorecards lists the OpenSSF Scorecards of the direct dependencies,
+	// when they are fetched
+	Scorecards []PackageScorecard `json:"scorecards,omitempty"`
+	// Upgrades describes what upgrading each vulnerable package to its
+	// suggested fix involves, when upgrades are analyzed
+	Upgrades []UpgradeImpact `json:"upgrades,omitempty"`
+	// Timings lists the time spent parsing and querying the packages of each
+	// source from the slowest to the fastest, when the scan is profiled
+	Timings []SourceTiming `json:"timings,omitempty"`
+	// Inventory lists every package that was found, whether or not it is
+	// vulnerable, when an inventory is collected
+	Inventory []InventoryPackage `json:"inventory,omitempty"`
+	// Reachability lists whether the code of each Go module calls the
+	// vulnerable symbols of the vulnerabilities of its packages, when calls
+	// are analyzed
+	Reachability []VulnerabilityReachability `json:"reachability,omitempty"`
+	// LicenseViolations lists the packages whose licenses are not allowed by
+	// the license policy of their config, when licenses are checked
+	LicenseViolations []LicenseViolation `json:"licenseViolations,omitempty"`
+	// History lists when each vulnerability of the packages of lockfiles that
+	// are committed to git first affected them, when their history is scanned
+	History []VulnerabilityHistory `json:"history,omitempty"`
+	// Plan lists the inputs that would be scanned, and those that would be
+	// skipped, when a scan is only planned
+	Plan []PlannedInput `json:"plan,omitempty"`
+}
+
+// PlannedInput is an input that a scan would scan, or would skip
+type PlannedInput struct {
+	Source SourceInfo `json:"source"`
+	// Parser is what a lockfile would be parsed as, or the parsers that it
+	// would be parsed with the best match of, separated by "|"
+	Parser string `json:"parser,omitempty"`
+	// Skipped is why the input would not be scanned, if it would not be
+	Skipped string `json:"skipped,omitempty"`
+}
+
+// VulnerabilityHistory describes when a vulnerability that affects a package
+// of a lockfile first affected it, out of the commits of the history of the
+// lockfile's repository that were scanned
+type VulnerabilityHistory struct {
+	Source  SourceInfo  `json:"source"`
+	Package PackageInfo `json:"package"`
+	ID      string      `json:"id"`
+	// Tag is the first tag at which the lockfile had an affected version of
+	// the package, which is empty if that was at an untagged commit
+	Tag    string    `json:"tag,omitempty"`
+	Commit string    `json:"commit"`
+	Date   time.Time `json:"date"`
+	// Version is the affected version of the package at that commit
+	Version string `json:"version"`
+}
+
+// LicenseViolation describes a package whose licenses are not allowed by the
+// license policy of the config of its source
+type LicenseViolation struct {
+	Source  SourceInfo  `json:"source"`
+	Package PackageInfo `json:"package"`
+	// License is the SPDX expression of the licenses of the package
+	License string `json:"license"`
+	// Denied are the licenses of the expression that are not allowed
+	Denied []string `json:"denied"`
+}
+
+// VulnerabilityReachability is whether the vulnerability of a Go package can
+// be reached from the code of the module that depends on it
+type VulnerabilityReachability struct {
+	Source  SourceInfo  `json:"source"`
+	Package PackageInfo `json:"package"`
+	ID      string      `json:"id"`
+	// Verdict is "called" if the module calls the vulnerable symbols,
+	// "imported" if it only imports the vulnerable packages, "not-imported"
+	// if it does neither itself, or "unknown" if it could not be analyzed
+	Verdict string `json:"verdict"`
+	// Symbols are the vulnerable symbols that are called, qualified by the
+	// import paths of their packages
+	Symbols []string `json:"symbols,omitempty"`
+}
+
+// InventoryPackage is a package that was found in a source, or the commit that
+// a git repository was at
+type InventoryPackage struct {
+	Source  SourceInfo  `json:"source"`
+	Package PackageInfo `json:"package"`
+	// PURL is the package URL of the package, if its ecosystem has one
+	PURL string `json:"purl,omitempty"`
+	// Commit is the commit of a git repository, which has no package
+	Commit string `json:"commit,omitempty"`
+}
+
+// SourceTiming is the time spent on the packages of a single source, with
+// time spent querying batches of several sources being split between them by
+// how many of the packages each has. Durations are in nanoseconds.
+type SourceTiming struct {
+	Source   SourceInfo    `json:"source"`
+	Packages int           `json:"packages"`
+	Parse    time.Duration `json:"parseDuration"`
+	Query    time.Duration `json:"queryDuration"`
+}
+
+// Total is the time spent on the source altogether
+func (t SourceTiming) Total() time.Duration {
+	return t.Parse + t.Query
+}
+
+// UpgradeImpact describes upgrading a vulnerable package to the lowest version
+// that fixes all of its vulnerabilities that have been fixed, and what else
+// in its dependency graph changes as a result
+type UpgradeImpact struct {
+	Source  SourceInfo  `json:"source"`
+	Package PackageInfo `json:"package"`
+	// FixedVersion is the version that the package should be upgraded to
+	FixedVersion string `json:"fixedVersion"`
+	// Bump is the most significant part of the version that the upgrade
+	// changes, being one of "major", "minor" or "patch", or "other" for
+	// versions that do not differ in those parts, such as pre-releases
+	Bump string `json:"bump"`
+	// Fixes are the vulnerabilities that the upgrade fixes, while Unfixed
+	// are those of the package that no version has been published to fix
+	Fixes   []string `json:"fixes"`
+	Unfixed []string `json:"unfixed,omitempty"`
+	// DependenciesKnown is if the dependencies of both versions could be
+	// resolved, without which Changes is empty
+	DependenciesKnown bool `json:"dependenciesKnown"`
+	// Changes are the packages that the package depends on, directly or
+	// transitively, which are added, removed or change version by upgrading
+	Changes []DependencyChange `json:"changes,omitempty"`
+}
+
+// Breaking reports if the upgrade is likely to need changes to be made to
+// use it, by it or any of the dependencies it changes being a major bump
+func (u UpgradeImpact) Breaking() bool {
+	if u.Bump == "major" {
+		return true
+	}
+
+	for _, change := range u.Changes {
+		if change.Bump == "major" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DependencyChange is a package in the dependency graph of an upgraded package
+// whose version changes, with From being empty for packages that are added
+// and To being empty for packages that are removed
+type DependencyChange struct {
+	Name      string `json:"name"`
+	Ecosystem string `json:"ecosystem"`
+	From      string `json:"from,omitempty"`
+	To        string `json:"to,omitempty"`
+	// Bump is how significantly the version changes, in the same way as the
+	// Bump of an UpgradeImpact, or empty for packages added or removed
+	Bump string `json:"bump,omitempty"`
+}
+
+// PackageScorecard is the OpenSSF Scorecard of the source repository of a
+// package
+type PackageScorecard struct {
+	Source    SourceInfo  `json:"source"`
+	Package   PackageInfo `json:"package"`
+	Scorecard Scorecard   `json:"scorecard"`
+}
+
+// Scorecard summarises how well a project follows security and maintenance
+// practices, as assessed by OpenSSF Scorecard
+type Scorecard struct {
+	// Project is the source repository that was assessed, such as
+	// "github.com/google/osv-scanner"
+	Project string `json:"project"`
+	// Date is when the project was last assessed
+	Date string `json:"date,omitempty"`
+	// OverallScore is out of 10
+	OverallScore float64          `json:"overallScore"`
+	Checks       []ScorecardCheck `json:"checks,omitempty"`
+}
+
+// Check returns the check with the given name, such as "Maintained"
+func (s Scorecard) Check(name string) (ScorecardCheck, bool) {
+	for _, check := range s.Checks {
+		if check.Name == name {
+			return check, true
+		}
+	}
+
+	return ScorecardCheck{}, false
+}
+
+// ScorecardCheck is the outcome of one of the checks of a Scorecard
+type ScorecardCheck struct {
+	Name string `json:"name"`
+	// Score is out of 10, or -1 if the check was inconclusive
+	Score  int    `json:"score"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// DeprecatedPackage describes a package that should no longer be used
+// according to its registry
+type DeprecatedPackage struct {
+	Source  SourceInfo  `json:"source"`
+	Package PackageInfo `json:"package"`
+	// Kind is one of "deprecated", "yanked" or "unmaintained"
+	Kind string `json:"kind"`
+	// Message is the reason given by the maintainers, if there is one
+	Message string `json:"message,omitempty"`
+}
+
+// OutdatedPackage describes a package that is behind the latest version that
+// is published on its registry
+type OutdatedPackage struct {
+	Source  SourceInfo  `json:"source"`
+	Package PackageInfo `json:"package"`
+	Latest  string      `json:"latest"`
+	// Behind is the most significant part of the version that differs from
+	// the latest, being one of "major", "minor" or "patch", or "other" for
+	// versions that do not differ in those parts, such as pre-releases
+	Behind string `json:"behind"`
+	// MajorVersionsBehind is how many major versions the package is behind,
+	// if its version has one
+	MajorVersionsBehind int `json:"majorVersionsBehind,omitempty"`
+}
+
+// UnusedIgnore describes an ignore of a config file that no longer matches
+// any vulnerabilities, and so can be removed
+type UnusedIgnore struct {
+	ConfigPath string `json:"configPath"`
+	// ID is the vulnerability that is ignored, for ignores by ID
+	ID string `json:"id,omitempty"`
+	// Path is the pattern of paths that are ignored, for ignores by path
+	Path   string `json:"path,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// RegistryIssue describes a package in a lockfile that does not exist on its
+// public registry, or whose content there differs from what was locked, or an
+// internal package whose name is also published there
+type RegistryIssue struct {
+	Source  SourceInfo  `json:"source"`
+	Package PackageInfo `json:"package"`
+	// Kind is one of "missing-package", "missing-version",
+	// "integrity-mismatch" or "dependency-confusion"
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"`
+}
+
+// SuspiciousPackage describes a package that has been reported as malicious,
+// or whose name is so close to that of a popular package that it is likely to
+// be imitating it
+type SuspiciousPackage struct {
+	Source  SourceInfo  `json:"source"`
+	Package PackageInfo `json:"package"`
+	// Kind is either "malicious" or "typosquat"
+	Kind string `json:"kind"`
+	// IDs are the malicious package advisories of a malicious package
+	IDs []string `json:"ids,omitempty"`
+	// SimilarTo is the popular package that a typosquat is named after
+	SimilarTo string `json:"similarTo,omitempty"`
+}
+
+// TargetSummary describes the outcome of scanning one target of a targets file
+type TargetSummary struct {
+	Name            string `json:"name"`
+	Vulnerabilities int    `json:"vulnerabilities"`
+	// Error is set if the target could not be scanned
+	Error string `json:"error,omitempty"`
+}
+
+// SkippedSource describes an input that could not be (fully) scanned
+type SkippedSource struct {
+	Source SourceInfo `json:"source"`
+	Reason string     `json:"reason"`
+}
+
+// ParseFailure describes a lockfile or SBOM that could not be parsed
+type ParseFailure struct {
+	Path   string `json:"path"`
+	Parser string `json:"parser"`
+	Error  string `json:"error"`
 }
 
 // Flatten the grouped/nested vulnerability results into one flat array.
@@ -64,7 +365,20 @@ type Vulnerability struct {
 		Type string `json:"type"`
 		URL  string `json:"url"`
 	} `json:"references"`
+	// SeverityScores are the scores of the vulnerability in the scoring
+	// systems that the database uses, such as CVSS
+	SeverityScores   []SeverityScore        `json:"severity,omitempty"`
 	DatabaseSpecific map[string]interface{} `json:"database_specific,omitempty"`
+	// Withdrawn is when the advisory was withdrawn, such as for having been
+	// found to be invalid, or nil if it has not been
+	Withdrawn *time.Time `json:"withdrawn,omitempty"`
+}
+
+// SeverityScore is the score of a vulnerability in a scoring system, such as
+// the vector of a CVSS_V3 score
+type SeverityScore struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
 }
 
 type SourceInfo struct {
@@ -80,6 +394,11 @@ func (s SourceInfo) String() string {
 type PackageSource struct {
 	Source   SourceInfo     `json:"source"`
 	Packages []PackageVulns `json:"packages"`
+	// Incomplete is set when some packages from this source could not be
+	// checked, e.g. because the OSV API was unavailable
+	Incomplete bool `json:"incomplete,omitempty"`
+	// Target is the name of the target this source belongs to, when scanning a targets file
+	Target string `json:"target,omitempty"`
 }
 
 // Vulnerabilities grouped by package
@@ -87,10 +406,129 @@ type PackageVulns struct {
 	Package         PackageInfo     `json:"package"`
 	Vulnerabilities []Vulnerability `json:"vulnerabilities"`
 	Groups          []GroupInfo     `json:"groups"`
+	// WorkspaceMembers are the members of a monorepo workspace that the
+	// package is attributed to, if it is part of one
+	WorkspaceMembers []WorkspaceMember `json:"workspaceMembers,omitempty"`
+	// Owners are the owners of the source the package was found in, as given
+	// by the CODEOWNERS file of the repository the source is in
+	Owners []string `json:"owners,omitempty"`
+	// Blame describes the commit that last changed the line of the lockfile
+	// that pins this version of the package, if blame was requested
+	Blame *BlameInfo `json:"blame,omitempty"`
+	// Location is where the package is declared within its source file, if
+	// it could be found
+	Location *SourceLocation `json:"location,omitempty"`
+	// Metadata is any further information that the source of the package
+	// records about it
+	Metadata *PackageMetadata `json:"metadata,omitempty"`
+	// Component is the component of the SBOM that the package was found in,
+	// if its source is an SBOM
+	Component *SBOMComponent `json:"sbomComponent,omitempty"`
+}
+
+// SBOMComponent identifies a component within an SBOM
+type SBOMComponent struct {
+	// BOMRef is the bom-ref of a CycloneDX component or the SPDXID of an SPDX
+	// package, if it has one
+	BOMRef string `json:"bomRef,omitempty"`
+	// Index is the position of the component amongst the components (or
+	// packages) of the SBOM, starting from 0
+	Index int `json:"index"`
+}
+
+// PackageMetadata is information about a package that only some sources
+// record, with each field being empty if it is not known
+type PackageMetadata struct {
+	// Scope is the scope the package is depended on in, being one of "prod",
+	// "dev" or "optional"
+	Scope string `json:"scope,omitempty"`
+	// Relation is either "direct" if the project depends on the package
+	// itself, or "transitive" if only its dependencies do
+	Relation string `json:"relation,omitempty"`
+	// Resolved is where the package was resolved to, which is usually the
+	// URL it is downloaded from
+	Resolved string `json:"resolved,omitempty"`
+	// Integrity is the hash recorded to verify the content of the package,
+	// in whatever format the source uses
+	Integrity string `json:"integrity,omitempty"`
+	// DeclaringFile is the path of the file that declares the package
+	DeclaringFile string `json:"declaringFile,omitempty"`
+	// Platform is the platform that a platform-specific build of the package
+	// is for
+	Platform string `json:"platform,omitempty"`
+	// Groups are the groups of the manifest that the package is depended on in
+	Groups []string `json:"groups,omitempty"`
+	// PlatformRequirements are the versions of the runtime and its extensions
+	// that the package requires, keyed by their names
+	PlatformRequirements map[string]string `json:"platformRequirements,omitempty"`
+}
+
+// SourceLocation is a position within a source file, with both lines and
+// columns starting from 1
+type SourceLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	// EndColumn is the column just after the end of the declaration
+	EndColumn int `json:"endColumn"`
+}
+
+// BlameInfo describes the commit that last changed a line of a lockfile
+type BlameInfo struct {
+	Line   int       `json:"line"`
+	Commit string    `json:"commit"`
+	Author string    `json:"author"`
+	Email  string    `json:"email"`
+	Date   time.Time `json:"date"`
+}
+
+// WorkspaceMember is a package or module within a monorepo workspace
+type WorkspaceMember struct {
+	// Workspace is the tool that manages the workspace, e.g. "npm" or "cargo"
+	Workspace string `json:"workspace"`
+	Name      string `json:"name"`
+	// Path is the directory of the member, relative to the workspace root
+	Path   string   `json:"path"`
+	Owners []string `json:"owners,omitempty"`
 }
 
 type GroupInfo struct {
 	IDs []string `json:"ids"`
+	// Fingerprint identifies this finding across scans, see Fingerprint
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Severity is the effective severity of the group, after any overrides
+	// and scoring from config, or "" if it is not known
+	Severity string `json:"severity,omitempty"`
+	// Score is the effective score of the group out of 10, after any
+	// overrides and scoring from config, or 0 if it is not known
+	Score float64 `json:"score,omitempty"`
+	// Modified is when the most recently modified of the advisories of the
+	// group was last modified, or nil if none of them say
+	Modified *time.Time `json:"modified,omitempty"`
+	// Withdrawn is set when every advisory of the group has been withdrawn,
+	// which are only kept when asked to be and never fail the scan
+	Withdrawn bool `json:"withdrawn,omitempty"`
+	// CWEs are the weaknesses that the advisories of the group are instances
+	// of, such as "CWE-79"
+	CWEs []string `json:"cwes,omitempty"`
+	// Exploits are the links to exploits or proofs of concept of the group
+	// given by the references of its advisories
+	Exploits []string `json:"exploits,omitempty"`
+	// AffectedSymbols are the functions and other symbols of the package that
+	// the advisories of the group say are vulnerable, see AffectedSymbols
+	AffectedSymbols []string `json:"affectedSymbols,omitempty"`
+	// SymbolUsages are the places in the code of the project that look like
+	// they use the affected symbols, when they are searched for
+	SymbolUsages []SymbolUsage `json:"symbolUsages,omitempty"`
+}
+
+// SymbolUsage is a line of code that appears to use an affected symbol of a
+// vulnerability, found by searching for its name rather than by analyzing the
+// code, so it is evidence that the vulnerable code is reachable but not proof
+type SymbolUsage struct {
+	Symbol string `json:"symbol"`
+	Path   string `json:"path"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
 }
 
 // Specific package information
@@ -0,0 +1,127 @@
+package models
+
+import "strings"
+
+// Severities in order from least to most severe
+var Severities = []string{"LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+// Severity returns the severity of the vulnerability as given by the database
+// it comes from, normalised to one of Severities, or "" if it is unknown
+func (v Vulnerability) Severity() string {
+	severity, _ := v.DatabaseSpecific["severity"].(string)
+
+	return NormalizeSeverity(severity)
+}
+
+// NormalizeSeverity returns the given severity as one of Severities, treating
+// "MODERATE" as "MEDIUM" and ignoring case, or "" if it is not one of them
+func NormalizeSeverity(severity string) string {
+	switch strings.ToUpper(strings.TrimSpace(severity)) {
+	case "LOW":
+		return "LOW"
+	case "MODERATE", "MEDIUM":
+		return "MEDIUM"
+	case "HIGH":
+		return "HIGH"
+	case "CRITICAL":
+		return "CRITICAL"
+	default:
+		return ""
+	}
+}
+
+// severityRank returns how severe the given severity is, with unknown
+// severities ranking lowest
+func severityRank(severity string) int {
+	for i, s := range Severities {
+		if s == severity {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+// SeverityAtLeast reports if the severity is at least as severe as the
+// threshold, with unknown severities being less severe than any other
+func SeverityAtLeast(severity string, threshold string) bool {
+	return severityRank(severity) >= severityRank(threshold)
+}
+
+// HighestSeverity returns the most severe of the given vulnerabilities'
+// severities, or "" if none of them are known
+func HighestSeverity(vulns []Vulnerability) string {
+	highest := ""
+
+	for _, vuln := range vulns {
+		if severity := vuln.Severity(); severityRank(severity) > severityRank(highest) {
+			highest = severity
+		}
+	}
+
+	return highest
+}
+
+// severityScores are the scores that represent each severity when nothing
+// more precise is known, being the middle of the range of CVSS scores that
+// the severity covers
+var severityScores = map[string]float64{
+	"LOW":      2.0,
+	"MEDIUM":   5.5,
+	"HIGH":     8.0,
+	"CRITICAL": 9.5,
+}
+
+// ScoreOfSeverity returns the score that represents the given severity, or 0
+// if it is unknown
+func ScoreOfSeverity(severity string) float64 {
+	return severityScores[severity]
+}
+
+// SeverityOfScore returns the severity of a score out of 10, using the same
+// ranges as CVSS, or "" if the score is 0
+func SeverityOfScore(score float64) string {
+	switch {
+	case score >= 9:
+		return "CRITICAL"
+	case score >= 7:
+		return "HIGH"
+	case score >= 4:
+		return "MEDIUM"
+	case score > 0:
+		return "LOW"
+	default:
+		return ""
+	}
+}
+
+// Score returns the score of the vulnerability out of 10, being its CVSS v3
+// base score if it has one, or otherwise the score that represents its
+// severity, or 0 if neither are known
+func (v Vulnerability) Score() float64 {
+	for _, severity := range v.SeverityScores {
+		if severity.Type != "CVSS_V3" {
+			continue
+		}
+
+		if score, ok := CVSSV3BaseScore(severity.Score); ok {
+			return score
+		}
+	}
+
+	return ScoreOfSeverity(v.Severity())
+}
+
+// HighestScore returns the highest of the given vulnerabilities' scores, or 0
+// if none of them are known
+func HighestScore(vulns []Vulnerability) float64 {
+	highest := 0.0
+
+	for _, vuln := range vulns {
+		if score := vuln.Score(); score > highest {
+			highest = score
+		}
+	}
+
+	return highest
+}
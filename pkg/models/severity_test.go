@@ -0,0 +1,108 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func vulnWithSeverity(severity string) models.Vulnerability {
+	return models.Vulnerability{DatabaseSpecific: map[string]interface{}{"severity": severity}}
+}
+
+func TestHighestSeverity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		vulns []models.Vulnerability
+		want  string
+	}{
+		{name: "no vulnerabilities", vulns: nil, want: ""},
+		{name: "unknown severity", vulns: []models.Vulnerability{{}, vulnWithSeverity("bad")}, want: ""},
+		{name: "moderate is medium", vulns: []models.Vulnerability{vulnWithSeverity("MODERATE")}, want: "MEDIUM"},
+		{
+			name:  "highest wins",
+			vulns: []models.Vulnerability{vulnWithSeverity("LOW"), vulnWithSeverity("critical"), vulnWithSeverity("HIGH")},
+			want:  "CRITICAL",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := models.HighestSeverity(tt.vulns); got != tt.want {
+				t.Errorf("HighestSeverity() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCVSSV3BaseScore(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		vector string
+		want   float64
+		ok     bool
+	}{
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", want: 9.8, ok: true},
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", want: 10, ok: true},
+		{vector: "CVSS:3.0/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N", want: 6.1, ok: true},
+		{vector: "CVSS:3.1/AV:L/AC:H/PR:H/UI:R/S:U/C:L/I:N/A:N", want: 1.8, ok: true},
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N", want: 0, ok: true},
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:U/C:H/I:N/A:N", want: 6.5, ok: true},
+		{vector: "AV:N/AC:L/Au:N/C:P/I:P/A:P", ok: false},
+		{vector: "CVSS:3.1/AV:X/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", ok: false},
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/C:H/I:H/A:H", ok: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.vector, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := models.CVSSV3BaseScore(tt.vector)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("CVSSV3BaseScore() = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestVulnerability_Score(t *testing.T) {
+	t.Parallel()
+
+	withVector := vulnWithSeverity("LOW")
+	withVector.SeverityScores = []models.SeverityScore{
+		{Type: "CVSS_V2", Score: "AV:N/AC:L/Au:N/C:P/I:P/A:P"},
+		{Type: "CVSS_V3", Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"},
+	}
+
+	tests := []struct {
+		name string
+		vuln models.Vulnerability
+		want float64
+	}{
+		{name: "cvss v3 vector", vuln: withVector, want: 9.8},
+		{name: "severity only", vuln: vulnWithSeverity("HIGH"), want: 8.0},
+		{name: "unknown", vuln: models.Vulnerability{}, want: 0},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.vuln.Score(); got != tt.want {
+				t.Errorf("Score() = %v, want %v", got, tt.want)
+			}
+
+			if got := models.SeverityOfScore(tt.vuln.Score()); got != models.SeverityOfScore(tt.want) {
+				t.Errorf("SeverityOfScore() = %q, want %q", got, models.SeverityOfScore(tt.want))
+			}
+		})
+	}
+}
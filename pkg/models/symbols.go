@@ -0,0 +1,86 @@
+package models
+
+import (
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// AffectedSymbols returns the functions, methods and other symbols of the
+// given package that the vulnerability is in, as listed by the ecosystem
+// specific details of the database it comes from: the "imports" of Go
+// vulnerabilities, the "affects" of RustSec advisories and the
+// "affected_functions" of others. Go packages that are affected as a whole
+// are listed by their import path.
+func (v Vulnerability) AffectedSymbols(pkg PackageInfo) []string {
+	var symbols []string
+	add := func(symbol string) {
+		if symbol != "" && !slices.Contains(symbols, symbol) {
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	for _, affected := range v.Affected {
+		if affected.Package.Name != pkg.Name || !strings.EqualFold(affected.Package.Ecosystem, pkg.Ecosystem) {
+			continue
+		}
+
+		imports, _ := affected.EcosystemSpecific["imports"].([]interface{})
+		for _, entry := range imports {
+			entry, _ := entry.(map[string]interface{})
+			path, _ := entry["path"].(string)
+			if path == "" {
+				continue
+			}
+
+			names := stringList(entry["symbols"])
+			if len(names) == 0 {
+				add(path)
+			}
+			for _, name := range names {
+				add(path + "." + name)
+			}
+		}
+
+		affects, _ := affected.EcosystemSpecific["affects"].(map[string]interface{})
+		for _, function := range stringList(affects["functions"]) {
+			add(function)
+		}
+
+		for _, function := range stringList(affected.EcosystemSpecific["affected_functions"]) {
+			add(function)
+		}
+	}
+
+	return symbols
+}
+
+// stringList returns the strings of a list decoded from JSON, ignoring any
+// other values
+func stringList(value interface{}) []string {
+	list, _ := value.([]interface{})
+
+	var strs []string
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+
+	return strs
+}
+
+// AllAffectedSymbols returns the affected symbols of the given package of all
+// of the given vulnerabilities, in the order they are first found
+func AllAffectedSymbols(pkg PackageInfo, vulns []Vulnerability) []string {
+	var symbols []string
+	for _, vuln := range vulns {
+		for _, symbol := range vuln.AffectedSymbols(pkg) {
+			if !slices.Contains(symbols, symbol) {
+				symbols = append(symbols, symbol)
+			}
+		}
+	}
+
+	return symbols
+}
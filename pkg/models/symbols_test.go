@@ -0,0 +1,62 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestVulnerability_AffectedSymbols(t *testing.T) {
+	t.Parallel()
+
+	var vulns []models.Vulnerability
+	err := json.Unmarshal([]byte(`[
+		{
+			"id": "GO-2021-0113",
+			"affected": [
+				{
+					"package": {"ecosystem": "Go", "name": "golang.org/x/text"},
+					"ecosystem_specific": {"imports": [
+						{"path": "golang.org/x/text/language", "symbols": ["MatchStrings", "Parse"]},
+						{"path": "golang.org/x/text/internal/tag"}
+					]}
+				},
+				{
+					"package": {"ecosystem": "Go", "name": "golang.org/x/net"},
+					"ecosystem_specific": {"imports": [{"path": "golang.org/x/net/html", "symbols": ["Parse"]}]}
+				}
+			]
+		},
+		{
+			"id": "RUSTSEC-2021-0078",
+			"affected": [{
+				"package": {"ecosystem": "crates.io", "name": "hyper"},
+				"ecosystem_specific": {"affects": {"arch": [], "os": [], "functions": ["hyper::server::conn::Http::serve_connection"]}}
+			}]
+		},
+		{
+			"id": "GHSA-1",
+			"affected": [{
+				"package": {"ecosystem": "crates.io", "name": "hyper"},
+				"ecosystem_specific": {"affected_functions": ["hyper::server::conn::Http::serve_connection", "hyper::Body::wrap"]}
+			}]
+		}
+	]`), &vulns)
+	if err != nil {
+		t.Fatalf("could not parse vulnerabilities: %v", err)
+	}
+
+	text := models.PackageInfo{Name: "golang.org/x/text", Version: "0.3.5", Ecosystem: "Go"}
+	want := []string{"golang.org/x/text/language.MatchStrings", "golang.org/x/text/language.Parse", "golang.org/x/text/internal/tag"}
+	if diff := cmp.Diff(want, vulns[0].AffectedSymbols(text)); diff != "" {
+		t.Errorf("AffectedSymbols() mismatch (-want +got):\n%s", diff)
+	}
+
+	hyper := models.PackageInfo{Name: "hyper", Version: "0.14.9", Ecosystem: "crates.io"}
+	want = []string{"hyper::server::conn::Http::serve_connection", "hyper::Body::wrap"}
+	if diff := cmp.Diff(want, models.AllAffectedSymbols(hyper, vulns)); diff != "" {
+		t.Errorf("AllAffectedSymbols() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// CWEs returns the IDs of the weaknesses (CWEs) that the vulnerability is an
+// instance of as given by the database it comes from, such as in the
+// "cwe_ids" of GitHub advisories, normalised to the form "CWE-79"
+func (v Vulnerability) CWEs() []string {
+	var cwes []string
+
+	add := func(databaseSpecific map[string]interface{}) {
+		ids, _ := databaseSpecific["cwe_ids"].([]interface{})
+		for _, id := range ids {
+			s, _ := id.(string)
+			if cwe := NormalizeCWE(s); cwe != "" && !slices.Contains(cwes, cwe) {
+				cwes = append(cwes, cwe)
+			}
+		}
+	}
+
+	add(v.DatabaseSpecific)
+	for _, affected := range v.Affected {
+		add(affected.DatabaseSpecific)
+	}
+
+	return cwes
+}
+
+// NormalizeCWE returns the given CWE in the form "CWE-79", accepting it with
+// or without the prefix and ignoring case, or "" if it is not a CWE
+func NormalizeCWE(cwe string) string {
+	cwe = strings.ToUpper(strings.TrimSpace(cwe))
+	cwe = strings.TrimPrefix(cwe, "CWE-")
+
+	if n, err := strconv.Atoi(cwe); err != nil || n <= 0 {
+		return ""
+	}
+
+	return "CWE-" + cwe
+}
+
+// exploitHosts are sites whose pages are exploits or proofs of concept
+var exploitHosts = []string{"exploit-db.com", "packetstormsecurity.com", "packetstormsecurity.org"}
+
+// exploitPath matches the paths of links that are named as exploits or proofs
+// of concept, such as "/user/CVE-2021-44228-PoC" or "/exploits/1234"
+var exploitPath = regexp.MustCompile(`(?i)(^|[^a-z])(exploits?|poc)([^a-z]|$)`)
+
+// ExploitReferences returns the links of the vulnerability's references that
+// are to exploits or proofs of concept of it, being those of the "EVIDENCE"
+// type and those on known exploit sites or named as such
+func (v Vulnerability) ExploitReferences() []string {
+	var exploits []string
+
+	for _, ref := range v.References {
+		if !isExploitReference(ref.Type, ref.URL) || slices.Contains(exploits, ref.URL) {
+			continue
+		}
+		exploits = append(exploits, ref.URL)
+	}
+
+	return exploits
+}
+
+// isExploitReference reports if a reference of the given type and link is to
+// an exploit or proof of concept
+func isExploitReference(typ string, link string) bool {
+	if strings.EqualFold(typ, "EVIDENCE") {
+		return true
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+	for _, exploitHost := range exploitHosts {
+		if host == exploitHost || strings.HasSuffix(host, "."+exploitHost) {
+			return true
+		}
+	}
+
+	return exploitPath.MatchString(u.Path)
+}
+
+// AllCWEs returns the CWEs of all of the given vulnerabilities, in the order
+// they are first found
+func AllCWEs(vulns []Vulnerability) []string {
+	var cwes []string
+	for _, vuln := range vulns {
+		for _, cwe := range vuln.CWEs() {
+			if !slices.Contains(cwes, cwe) {
+				cwes = append(cwes, cwe)
+			}
+		}
+	}
+
+	return cwes
+}
+
+// AllExploitReferences returns the exploit references of all of the given
+// vulnerabilities, in the order they are first found
+func AllExploitReferences(vulns []Vulnerability) []string {
+	var exploits []string
+	for _, vuln := range vulns {
+		for _, exploit := range vuln.ExploitReferences() {
+			if !slices.Contains(exploits, exploit) {
+				exploits = append(exploits, exploit)
+			}
+		}
+	}
+
+	return exploits
+}
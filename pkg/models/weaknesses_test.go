@@ -0,0 +1,60 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestVulnerability_CWEsAndExploits(t *testing.T) {
+	t.Parallel()
+
+	var vulns []models.Vulnerability
+	err := json.Unmarshal([]byte(`[
+		{
+			"id": "GHSA-jfh8-c2jp-5v3q",
+			"affected": [{"database_specific": {"cwe_ids": ["CWE-917", "cwe-502"]}}],
+			"references": [
+				{"type": "ADVISORY", "url": "https://nvd.nist.gov/vuln/detail/CVE-2021-44228"},
+				{"type": "WEB", "url": "http://packetstormsecurity.com/files/165225/Apache-Log4j2-2.14.1-Remote-Code-Execution.html"},
+				{"type": "WEB", "url": "https://github.com/cyberxml/log4j-poc"},
+				{"type": "WEB", "url": "https://www.exploit-db.com/exploits/50592"},
+				{"type": "WEB", "url": "https://github.com/apache/pocketknife"}
+			],
+			"database_specific": {"cwe_ids": ["CWE-20", "CWE-400", "CWE-502", "NVD-CWE-noinfo"]}
+		},
+		{
+			"id": "CVE-2021-44228",
+			"references": [
+				{"type": "EVIDENCE", "url": "https://example.com/writeup"},
+				{"type": "WEB", "url": "https://www.exploit-db.com/exploits/50592"}
+			],
+			"database_specific": {"cwe_ids": ["20", 917]}
+		}
+	]`), &vulns)
+	if err != nil {
+		t.Fatalf("could not parse vulnerabilities: %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"CWE-20", "CWE-400", "CWE-502", "CWE-917"}, vulns[0].CWEs()); diff != "" {
+		t.Errorf("CWEs() mismatch (-want +got):\n%s", diff)
+	}
+
+	wantExploits := []string{
+		"http://packetstormsecurity.com/files/165225/Apache-Log4j2-2.14.1-Remote-Code-Execution.html",
+		"https://github.com/cyberxml/log4j-poc",
+		"https://www.exploit-db.com/exploits/50592",
+	}
+	if diff := cmp.Diff(wantExploits, vulns[0].ExploitReferences()); diff != "" {
+		t.Errorf("ExploitReferences() mismatch (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff([]string{"CWE-20", "CWE-400", "CWE-502", "CWE-917"}, models.AllCWEs(vulns)); diff != "" {
+		t.Errorf("AllCWEs() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(append(wantExploits, "https://example.com/writeup"), models.AllExploitReferences(vulns)); diff != "" {
+		t.Errorf("AllExploitReferences() mismatch (-want +got):\n%s", diff)
+	}
+}
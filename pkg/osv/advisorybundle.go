@@ -0,0 +1,171 @@
+package osv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// AdvisoryBundle keeps the full details of the vulnerabilities that a scan
+// found in a file alongside what was scanned, such as a `.osv-scan-cache` at
+// the root of a repository, so that later scans of it, whether locally or in CI,
+// do not fetch them again. Unlike CommitCache and QueryCache, the bundle caches
+// what vulnerabilities are rather than which packages they affect.
+type AdvisoryBundle struct {
+	path string
+	ttl  time.Duration
+	now  func() time.Time
+	get  func(ctx context.Context, id string) (*models.Vulnerability, error)
+
+	mu         sync.Mutex
+	advisories map[string]bundledAdvisory
+	// used are the IDs of the advisories that have been hydrated since the
+	// bundle was loaded, which are the only ones that are saved
+	used map[string]bool
+}
+
+// bundledAdvisory is the full details of a vulnerability, as of when it was
+// fetched
+type bundledAdvisory struct {
+	Vuln    models.Vulnerability `json:"vuln"`
+	Fetched time.Time            `json:"fetched"`
+}
+
+type advisoryBundleFile struct {
+	Advisories map[string]bundledAdvisory `json:"advisories"`
+}
+
+// LoadAdvisoryBundle loads the bundle stored at path, being empty if it does
+// not exist yet. Advisories are fetched again once the OSV API reports them
+// as having been modified since they were bundled, or when that is not known,
+// once they have been bundled for longer than ttl, unless it is 0.
+func LoadAdvisoryBundle(path string, ttl time.Duration) (*AdvisoryBundle, error) {
+	bundle := &AdvisoryBundle{
+		path:       path,
+		ttl:        ttl,
+		now:        time.Now,
+		get:        GetWithContext,
+		advisories: map[string]bundledAdvisory{},
+		used:       map[string]bool{},
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return bundle, nil
+	}
+	if err != nil {
+		return bundle, fmt.Errorf("could not read advisory bundle: %w", err)
+	}
+
+	var file advisoryBundleFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return bundle, fmt.Errorf("could not read advisory bundle: %w", err)
+	}
+	if file.Advisories != nil {
+		bundle.advisories = file.Advisories
+	}
+
+	return bundle, nil
+}
+
+// stale reports if the bundled advisory is older than the vulnerability that
+// was returned by the OSV API
+func (b *AdvisoryBundle) stale(bundled bundledAdvisory, vuln MinimalVulnerability) bool {
+	if !vuln.Modified.IsZero() {
+		return bundled.Vuln.Modified.Before(vuln.Modified)
+	}
+
+	return b.ttl > 0 && b.now().Sub(bundled.Fetched) >= b.ttl
+}
+
+// fetch returns the full details of the vulnerability from the bundle if it
+// is up to date, and from the OSV API otherwise, bundling them
+func (b *AdvisoryBundle) fetch(ctx context.Context, vuln MinimalVulnerability) (*models.Vulnerability, error) {
+	b.mu.Lock()
+	bundled, ok := b.advisories[vuln.ID]
+	if ok && !b.stale(bundled, vuln) {
+		b.used[vuln.ID] = true
+		b.mu.Unlock()
+
+		return &bundled.Vuln, nil
+	}
+	b.mu.Unlock()
+
+	fullVuln, err := b.get(ctx, vuln.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.advisories[vuln.ID] = bundledAdvisory{Vuln: *fullVuln, Fetched: b.now().UTC()}
+	b.used[vuln.ID] = true
+
+	return fullVuln, nil
+}
+
+// Hydrate fills the results of the batched response with the full details of
+// the vulnerabilities, in the same way as Hydrate, except that those in the
+// bundle which are up to date are not fetched again
+func (b *AdvisoryBundle) Hydrate(resp *BatchedResponse) (*HydratedBatchedResponse, error) {
+	return b.HydrateWithContext(context.Background(), resp)
+}
+
+// HydrateWithContext is like Hydrate, but abandons fetching the
+// vulnerabilities that are not in the bundle once ctx is done
+func (b *AdvisoryBundle) HydrateWithContext(ctx context.Context, resp *BatchedResponse) (*HydratedBatchedResponse, error) {
+	return hydrateWith(resp, func(vuln MinimalVulnerability) (*models.Vulnerability, error) {
+		return b.fetch(ctx, vuln)
+	})
+}
+
+// Save writes the advisories that have been hydrated since the bundle was
+// loaded back to where it was loaded from, leaving out those that are no
+// longer referenced by what was scanned
+func (b *AdvisoryBundle) Save() error {
+	b.mu.Lock()
+	file := advisoryBundleFile{Advisories: map[string]bundledAdvisory{}}
+	for id := range b.used {
+		file.Advisories[id] = b.advisories[id]
+	}
+	b.mu.Unlock()
+
+	content, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not save advisory bundle: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(b.path), 0o755); err != nil {
+		return fmt.Errorf("could not save advisory bundle: %w", err)
+	}
+
+	// write to a temporary file first, so that an interrupted save does not
+	// leave behind a truncated bundle
+	tmp, err := os.CreateTemp(filepath.Dir(b.path), filepath.Base(b.path)+".*")
+	if err != nil {
+		return fmt.Errorf("could not save advisory bundle: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("could not save advisory bundle: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), b.path); err != nil {
+		return fmt.Errorf("could not save advisory bundle: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package osv
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// fakeGet returns the vulnerabilities with the given IDs as last modified at
+// the given times, recording each ID that it is asked for
+func fakeGet(modified map[string]time.Time, fetched *[]string) func(string) (*models.Vulnerability, error) {
+	return func(id string) (*models.Vulnerability, error) {
+		*fetched = append(*fetched, id)
+
+		at, ok := modified[id]
+		if !ok {
+			return nil, errors.New("unknown vulnerability")
+		}
+
+		return &models.Vulnerability{ID: id, Modified: at}, nil
+	}
+}
+
+func loadTestBundle(t *testing.T, path string, now time.Time, get func(string) (*models.Vulnerability, error)) *AdvisoryBundle {
+	t.Helper()
+
+	bundle, err := LoadAdvisoryBundle(path, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bundle.now = func() time.Time { return now }
+	bundle.get = func(_ context.Context, id string) (*models.Vulnerability, error) {
+		return get(id)
+	}
+
+	return bundle
+}
+
+func TestAdvisoryBundle_Hydrate(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ".osv-scan-cache")
+	now := time.Date(2023, 2, 1, 12, 0, 0, 0, time.UTC)
+	jan := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	var fetched []string
+	bundle := loadTestBundle(t, path, now, fakeGet(map[string]time.Time{"OSV-1": jan, "OSV-2": jan, "OSV-3": jan}, &fetched))
+	_, err := bundle.Hydrate(&BatchedResponse{Results: []MinimalResponse{
+		{Vulns: []MinimalVulnerability{{ID: "OSV-1", Modified: jan}, {ID: "OSV-2", Modified: jan}}},
+		{Vulns: []MinimalVulnerability{{ID: "OSV-3"}}},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := bundle.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// OSV-1 is up to date, OSV-2 has been modified since it was bundled, and it
+	// is not known if OSV-3 has been but it was bundled too long ago
+	fetched = nil
+	bundle = loadTestBundle(t, path, now.Add(48*time.Hour), fakeGet(map[string]time.Time{"OSV-2": feb, "OSV-3": feb}, &fetched))
+	hydrated, err := bundle.Hydrate(&BatchedResponse{Results: []MinimalResponse{
+		{Vulns: []MinimalVulnerability{{ID: "OSV-1", Modified: jan}, {ID: "OSV-2", Modified: feb}, {ID: "OSV-3"}}},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"OSV-2", "OSV-3"}, fetched); diff != "" {
+		t.Errorf("fetched mismatch (-want +got):\n%s", diff)
+	}
+
+	want := &HydratedBatchedResponse{Results: []Response{{Vulns: []models.Vulnerability{
+		{ID: "OSV-1", Modified: jan},
+		{ID: "OSV-2", Modified: feb},
+		{ID: "OSV-3", Modified: feb},
+	}}}}
+	if diff := cmp.Diff(want, hydrated); diff != "" {
+		t.Errorf("Hydrate() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestAdvisoryBundle_Save(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "nested", ".osv-scan-cache")
+	now := time.Date(2023, 2, 1, 12, 0, 0, 0, time.UTC)
+	modified := map[string]time.Time{"OSV-1": now, "OSV-2": now}
+
+	var fetched []string
+	bundle := loadTestBundle(t, path, now, fakeGet(modified, &fetched))
+	if _, err := bundle.Hydrate(&BatchedResponse{Results: []MinimalResponse{
+		{Vulns: []MinimalVulnerability{{ID: "OSV-1"}, {ID: "OSV-2"}}},
+	}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := bundle.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// advisories that are no longer referenced are left out of the bundle
+	bundle = loadTestBundle(t, path, now, fakeGet(modified, &fetched))
+	if _, err := bundle.Hydrate(&BatchedResponse{Results: []MinimalResponse{
+		{Vulns: []MinimalVulnerability{{ID: "OSV-2"}}},
+	}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := bundle.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	bundle = loadTestBundle(t, path, now, fakeGet(nil, &fetched))
+	if _, ok := bundle.advisories["OSV-2"]; !ok || len(bundle.advisories) != 1 {
+		t.Errorf("expected only OSV-2 to be bundled, got %v", bundle.advisories)
+	}
+	if diff := cmp.Diff([]string{"OSV-1", "OSV-2"}, fetched); diff != "" {
+		t.Errorf("fetched mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLoadAdvisoryBundle_Invalid(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ".osv-scan-cache")
+	if err := os.WriteFile(path, []byte(`{"advisories":`), 0o600); err != nil {
+		t.Fatalf("could not write bundle: %v", err)
+	}
+
+	bundle, err := LoadAdvisoryBundle(path, time.Hour)
+	if err == nil {
+		t.Errorf("expected an error for a truncated bundle")
+	}
+	if bundle == nil || len(bundle.advisories) != 0 {
+		t.Errorf("expected an empty bundle to still be usable")
+	}
+}
@@ -0,0 +1,141 @@
+package osv
+
+import (
+	"context"
+	"crypto/sha1" //nolint:gosec // Maven repositories identify artifacts by their SHA-1
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ArtifactQueryEndpoint is the URL for finding the package versions that an
+// artifact is a release of by its hash, as OSV cannot be queried by hash itself
+const ArtifactQueryEndpoint = "https://api.deps.dev/v3alpha/query"
+
+// ArtifactHash is a hash of the content of an artifact, such as a jar, wheel
+// or binary
+type ArtifactHash struct {
+	// Type is either "SHA256" or "SHA1"
+	Type  string
+	Value []byte
+}
+
+func (h ArtifactHash) String() string {
+	return fmt.Sprintf("%s:%x", strings.ToLower(h.Type), h.Value)
+}
+
+// HashArtifact returns the hashes of the artifact that can be queried, in the
+// order they should be tried: SHA-256, followed by the SHA-1 that Maven
+// repositories record
+func HashArtifact(r io.Reader) ([]ArtifactHash, error) {
+	sha256Hash := sha256.New()
+	sha1Hash := sha1.New() //nolint:gosec
+
+	if _, err := io.Copy(io.MultiWriter(sha256Hash, sha1Hash), r); err != nil {
+		return nil, err
+	}
+
+	return []ArtifactHash{
+		{Type: "SHA256", Value: sha256Hash.Sum(nil)},
+		{Type: "SHA1", Value: sha1Hash.Sum(nil)},
+	}, nil
+}
+
+// depsDevEcosystems maps the package management systems of deps.dev onto the
+// ecosystems of OSV
+var depsDevEcosystems = map[string]string{
+	"GO":    "Go",
+	"NPM":   "npm",
+	"CARGO": "crates.io",
+	"MAVEN": "Maven",
+	"PYPI":  "PyPI",
+	"NUGET": "NuGet",
+}
+
+type artifactQueryResponse struct {
+	Results []struct {
+		Version struct {
+			VersionKey struct {
+				System  string `json:"system"`
+				Name    string `json:"name"`
+				Version string `json:"version"`
+			} `json:"versionKey"`
+		} `json:"version"`
+	} `json:"results"`
+}
+
+// QueryArtifact returns a query for each package version that an artifact
+// with the given hash is a release of, which is empty if it is not known.
+// The request is abandoned once ctx is done.
+func QueryArtifact(ctx context.Context, hash ArtifactHash) ([]*Query, error) {
+	return queryArtifact(ctx, ArtifactQueryEndpoint, hash)
+}
+
+func queryArtifact(ctx context.Context, endpoint string, hash ArtifactHash) ([]*Query, error) {
+	params := url.Values{}
+	params.Set("hash.type", hash.Type)
+	params.Set("hash.value", base64.StdEncoding.EncodeToString(hash.Value))
+
+	resp, err := makeRetryRequest(ctx, func() (*http.Response, error) {
+		return getWithContext(ctx, HTTPClient, endpoint+"?"+params.Encode())
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer drainAndClose(resp.Body)
+
+	// an artifact that has never been seen is reported as not being found
+	if resp.StatusCode == http.StatusNotFound {
+		return []*Query{}, nil
+	}
+
+	if err := checkResponseError(resp); err != nil {
+		return nil, err
+	}
+
+	var artifactResp artifactQueryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&artifactResp); err != nil {
+		return nil, err
+	}
+
+	queries := []*Query{}
+	seen := map[string]bool{}
+
+	for _, result := range artifactResp.Results {
+		key := result.Version.VersionKey
+		ecosystem, ok := depsDevEcosystems[key.System]
+		if !ok {
+			continue
+		}
+
+		// the same version can be found in more than one result, such as when
+		// it is published to more than one repository
+		id := ecosystem + "/" + key.Name + "@" + key.Version
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		version := key.Version
+		if ecosystem == "Go" {
+			// versions of Go modules are queried without their "v" prefix, in
+			// the same way as they are read from go.mod files
+			version = strings.TrimPrefix(version, "v")
+		}
+
+		queries = append(queries, &Query{
+			Version: version,
+			Package: Package{
+				Name:      key.Name,
+				Ecosystem: ecosystem,
+			},
+		})
+	}
+
+	return queries, nil
+}
@@ -0,0 +1,88 @@
+package osv
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHashArtifact(t *testing.T) {
+	t.Parallel()
+
+	hashes, err := HashArtifact(strings.NewReader("hello world"))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		"sha256:b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9",
+		"sha1:2aae6c35c94fcfb415dbe95f408b9ce91ee846ed",
+	}
+
+	got := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		got = append(got, hash.String())
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("HashArtifact() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestQueryArtifact(t *testing.T) {
+	t.Parallel()
+
+	hash := ArtifactHash{Type: "SHA256", Value: []byte{1, 2, 3}}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("hash.type") != hash.Type || r.URL.Query().Get("hash.value") != base64.StdEncoding.EncodeToString(hash.Value) {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		result := func(system string, name string, version string) string {
+			return fmt.Sprintf(`{"version": {"versionKey": {"system": %q, "name": %q, "version": %q}}}`, system, name, version)
+		}
+
+		fmt.Fprintf(w, `{"results": [%s, %s, %s, %s]}`,
+			result("MAVEN", "org.apache.logging.log4j:log4j-core", "2.14.1"),
+			result("MAVEN", "org.apache.logging.log4j:log4j-core", "2.14.1"),
+			result("GO", "golang.org/x/text", "v0.3.5"),
+			result("UNKNOWN", "something", "1.0.0"),
+		)
+	}))
+	defer server.Close()
+
+	queries, err := queryArtifact(context.Background(), server.URL, hash)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []*Query{
+		{Version: "2.14.1", Package: Package{Name: "org.apache.logging.log4j:log4j-core", Ecosystem: "Maven"}},
+		{Version: "0.3.5", Package: Package{Name: "golang.org/x/text", Ecosystem: "Go"}},
+	}
+
+	if diff := cmp.Diff(want, queries); diff != "" {
+		t.Errorf("queryArtifact() mismatch (-want +got):\n%s", diff)
+	}
+
+	queries, err = queryArtifact(context.Background(), server.URL, ArtifactHash{Type: "SHA1", Value: []byte{4, 5, 6}})
+
+	if err != nil {
+		t.Fatalf("unexpected error for an unknown artifact: %v", err)
+	}
+
+	if len(queries) != 0 {
+		t.Errorf("expected no queries for an unknown artifact, got %d", len(queries))
+	}
+}
@@ -0,0 +1,115 @@
+package osv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ClientOptions tune the HTTP client used to talk to the OSV API
+type ClientOptions struct {
+	// Timeout is the longest a single request (including reading the
+	// response) can take, with 0 meaning there is no limit
+	Timeout time.Duration
+	// MaxIdleConnsPerHost is how many connections to a host are kept open for
+	// reuse once their requests are complete
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept open for reuse
+	IdleConnTimeout time.Duration
+	// TLSHandshakeTimeout is the longest a TLS handshake can take
+	TLSHandshakeTimeout time.Duration
+	// ResponseHeaderTimeout is the longest to wait for the headers of a
+	// response once the request has been sent, with 0 meaning there is no limit
+	ResponseHeaderTimeout time.Duration
+}
+
+// DefaultClientOptions returns the options used for HTTPClient, which keep
+// enough connections open for the concurrent querybatch requests and the
+// many sequential vulnerability lookups that follow them
+func DefaultClientOptions() ClientOptions {
+	return ClientOptions{
+		Timeout:             2 * time.Minute,
+		MaxIdleConnsPerHost: DefaultRequestWorkers * 2,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+}
+
+// NewHTTPClient creates a client with the given options, on top of a transport
+// that otherwise behaves like http.DefaultTransport, including using HTTP/2
+// and respecting the proxy environment variables
+func NewHTTPClient(options ClientOptions) *http.Client {
+	//nolint:forcetypeassert // http.DefaultTransport is always a *http.Transport
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ForceAttemptHTTP2 = true
+	transport.MaxIdleConnsPerHost = options.MaxIdleConnsPerHost
+	transport.IdleConnTimeout = options.IdleConnTimeout
+	transport.TLSHandshakeTimeout = options.TLSHandshakeTimeout
+	transport.ResponseHeaderTimeout = options.ResponseHeaderTimeout
+	if options.MaxIdleConnsPerHost > transport.MaxIdleConns {
+		transport.MaxIdleConns = options.MaxIdleConnsPerHost
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   options.Timeout,
+	}
+}
+
+// HTTPClient is shared by all requests to the OSV API so that connections are
+// reused between them, and can be replaced to change how requests are sent
+var HTTPClient = NewHTTPClient(DefaultClientOptions())
+
+// doJSON sends a request to the given URL using HTTPClient, with `body` as the
+// JSON request body if it is not nil, and decodes the JSON response into `out`.
+// The request is abandoned once ctx is done.
+func doJSON(ctx context.Context, method string, url string, body []byte, out any) error {
+	resp, err := makeRetryRequest(ctx, func() (*http.Response, error) {
+		var reqBody io.Reader
+		if body != nil {
+			// a fresh reader is needed for each attempt
+			reqBody = bytes.NewReader(body)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+		if err != nil {
+			return nil, err
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		return HTTPClient.Do(req)
+	})
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(resp.Body)
+
+	if err := checkResponseError(resp); err != nil {
+		return err
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// getWithContext sends a GET request to the given URL using httpClient, which
+// is abandoned once ctx is done
+func getWithContext(ctx context.Context, httpClient *http.Client, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return httpClient.Do(req)
+}
+
+// drainAndClose reads anything left in the body before closing it, as the
+// connection can only be reused once the body has been read completely
+func drainAndClose(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, body)
+	body.Close()
+}
@@ -0,0 +1,117 @@
+package osv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	t.Parallel()
+
+	client := NewHTTPClient(ClientOptions{
+		Timeout:             time.Minute,
+		MaxIdleConnsPerHost: 500,
+		IdleConnTimeout:     time.Second,
+		TLSHandshakeTimeout: 2 * time.Second,
+	})
+
+	if client.Timeout != time.Minute {
+		t.Errorf("expected timeout of 1m, got %v", client.Timeout)
+	}
+
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expected a *http.Transport, got %T", client.Transport)
+	}
+
+	if !transport.ForceAttemptHTTP2 {
+		t.Errorf("expected HTTP/2 to be attempted")
+	}
+	if transport.MaxIdleConnsPerHost != 500 {
+		t.Errorf("expected 500 idle connections per host, got %d", transport.MaxIdleConnsPerHost)
+	}
+	if transport.MaxIdleConns < 500 {
+		t.Errorf("expected at least 500 idle connections, got %d", transport.MaxIdleConns)
+	}
+	if transport.IdleConnTimeout != time.Second {
+		t.Errorf("expected idle timeout of 1s, got %v", transport.IdleConnTimeout)
+	}
+	if transport.TLSHandshakeTimeout != 2*time.Second {
+		t.Errorf("expected TLS handshake timeout of 2s, got %v", transport.TLSHandshakeTimeout)
+	}
+}
+
+//nolint:paralleltest // replaces the shared HTTPClient
+func TestDoJSON_ReusesConnections(t *testing.T) {
+	var connections int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// trailing whitespace after the JSON is left unread by the decoder, and
+		// is too large to be drained automatically when the body is closed
+		fmt.Fprintf(w, `{"id": %q}`+strings.Repeat("\n", 4*1024*1024), r.URL.Path)
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&connections, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	original := HTTPClient
+	HTTPClient = NewHTTPClient(DefaultClientOptions())
+	defer func() { HTTPClient = original }()
+
+	for i := 0; i < 10; i++ {
+		var out struct {
+			ID string `json:"id"`
+		}
+		if err := doJSON(context.Background(), http.MethodGet, fmt.Sprintf("%s/GHSA-%d", server.URL, i), nil, &out); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := fmt.Sprintf("/GHSA-%d", i); out.ID != want {
+			t.Errorf("expected %s, got %s", want, out.ID)
+		}
+	}
+
+	if connections != 1 {
+		t.Errorf("expected a single connection to be used, got %d", connections)
+	}
+}
+
+//nolint:paralleltest // replaces the shared HTTPClient
+func TestDoJSON_Cancelled(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	original := HTTPClient
+	HTTPClient = NewHTTPClient(DefaultClientOptions())
+	defer func() { HTTPClient = original }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	var out struct{}
+	err := doJSON(ctx, http.MethodGet, server.URL, nil, &out)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the request to be cancelled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Errorf("expected the request not to be retried, took %v", elapsed)
+	}
+	if requests != 0 {
+		t.Errorf("expected no requests to be sent, got %d", requests)
+	}
+}
@@ -0,0 +1,219 @@
+package osv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// CommitCache remembers which vulnerabilities affect each git commit that has
+// been queried, so that repositories and submodules that are pinned to the same
+// commit are not queried again every scan. Only the IDs of the vulnerabilities
+// are cached, so they still have to be hydrated.
+type CommitCache struct {
+	path string
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex
+	commits map[string]cachedCommit
+}
+
+// cachedCommit is the IDs of the vulnerabilities that affect a commit, as of
+// when it was queried
+type cachedCommit struct {
+	Vulns   []string  `json:"vulns"`
+	Fetched time.Time `json:"fetched"`
+}
+
+type commitCacheFile struct {
+	Commits map[string]cachedCommit `json:"commits"`
+}
+
+// LoadCommitCache loads the cache stored at path, being empty if it does not
+// exist yet. Commits are queried again once they have been cached for longer
+// than ttl, unless it is 0.
+func LoadCommitCache(path string, ttl time.Duration) (*CommitCache, error) {
+	cache := &CommitCache{
+		path:    path,
+		ttl:     ttl,
+		now:     time.Now,
+		commits: map[string]cachedCommit{},
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cache, nil
+	}
+	if err != nil {
+		return cache, fmt.Errorf("could not read commit cache: %w", err)
+	}
+
+	var file commitCacheFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return cache, fmt.Errorf("could not read commit cache: %w", err)
+	}
+	if file.Commits != nil {
+		cache.commits = file.Commits
+	}
+
+	return cache, nil
+}
+
+// expired reports if the commit was cached too long ago to be used
+func (c *CommitCache) expired(cached cachedCommit) bool {
+	return c.ttl > 0 && c.now().Sub(cached.Fetched) >= c.ttl
+}
+
+// Get returns the vulnerabilities that affect the commit, reporting false if
+// it has not been cached or has expired
+func (c *CommitCache) Get(commit string) ([]MinimalVulnerability, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, ok := c.commits[commit]
+	if !ok || c.expired(cached) {
+		return nil, false
+	}
+
+	vulns := make([]MinimalVulnerability, 0, len(cached.Vulns))
+	for _, id := range cached.Vulns {
+		vulns = append(vulns, MinimalVulnerability{ID: id})
+	}
+
+	return vulns, true
+}
+
+// Put caches the vulnerabilities that affect the commit
+func (c *CommitCache) Put(commit string, vulns []MinimalVulnerability) {
+	ids := make([]string, 0, len(vulns))
+	for _, vuln := range vulns {
+		ids = append(ids, vuln.ID)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.commits[commit] = cachedCommit{Vulns: ids, Fetched: c.now().UTC()}
+}
+
+// Save writes the cache back to where it was loaded from, leaving out the
+// commits that have expired
+func (c *CommitCache) Save() error {
+	c.mu.Lock()
+	file := commitCacheFile{Commits: map[string]cachedCommit{}}
+	for commit, cached := range c.commits {
+		if !c.expired(cached) {
+			file.Commits[commit] = cached
+		}
+	}
+	c.mu.Unlock()
+
+	content, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not save commit cache: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("could not save commit cache: %w", err)
+	}
+
+	// write to a temporary file first, so that an interrupted save does not
+	// leave behind a truncated cache
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return fmt.Errorf("could not save commit cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("could not save commit cache: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), c.path)
+}
+
+// Wrap returns a function that sends batches with send, except for the queries
+// of commits that are cached which are answered from the cache instead, and
+// caches the responses to the queries of the other commits
+func (c *CommitCache) Wrap(send func(BatchedQuery) (*BatchedResponse, error)) func(BatchedQuery) (*BatchedResponse, error) {
+	return wrapCache(
+		send,
+		func(query *Query) ([]MinimalVulnerability, bool) {
+			if query.Commit == "" {
+				return nil, false
+			}
+
+			return c.Get(query.Commit)
+		},
+		func(query *Query, vulns []MinimalVulnerability) {
+			if query.Commit != "" {
+				c.Put(query.Commit, vulns)
+			}
+		},
+	)
+}
+
+// wrapCache returns a function that sends batches with send, except for the
+// queries that get answers from a cache, and puts the responses to the other
+// queries that did not fail
+func wrapCache(
+	send func(BatchedQuery) (*BatchedResponse, error),
+	get func(*Query) ([]MinimalVulnerability, bool),
+	put func(*Query, []MinimalVulnerability),
+) func(BatchedQuery) (*BatchedResponse, error) {
+	return func(request BatchedQuery) (*BatchedResponse, error) {
+		resp := &BatchedResponse{Results: make([]MinimalResponse, len(request.Queries))}
+
+		// indexes maps the queries that are sent onto their index in the
+		// original batch
+		var indexes []int
+		var uncached BatchedQuery
+		for i, query := range request.Queries {
+			if vulns, ok := get(query); ok {
+				resp.Results[i].Vulns = vulns
+
+				continue
+			}
+
+			indexes = append(indexes, i)
+			uncached.Queries = append(uncached.Queries, query)
+		}
+
+		if len(uncached.Queries) == 0 {
+			return resp, nil
+		}
+
+		sent, err := send(uncached)
+		failed := map[int]bool{}
+
+		var partialErr *PartialResponseError
+		if errors.As(err, &partialErr) {
+			mapped := &PartialResponseError{Err: partialErr.Err}
+			for _, i := range partialErr.FailedQueries {
+				failed[i] = true
+				mapped.FailedQueries = append(mapped.FailedQueries, indexes[i])
+			}
+			err = mapped
+		} else if err != nil {
+			return sent, err
+		}
+
+		for i, query := range uncached.Queries {
+			resp.Results[indexes[i]] = sent.Results[i]
+			if !failed[i] {
+				put(query, sent.Results[i].Vulns)
+			}
+		}
+
+		return resp, err
+	}
+}
@@ -0,0 +1,175 @@
+package osv
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeSend responds to commit queries with the vulnerabilities given for them,
+// recording each batch that it is sent and failing the queries of commits that
+// are not given
+func fakeSend(vulns map[string][]string, sent *[][]string) func(BatchedQuery) (*BatchedResponse, error) {
+	return func(request BatchedQuery) (*BatchedResponse, error) {
+		resp := &BatchedResponse{Results: make([]MinimalResponse, len(request.Queries))}
+		partialErr := &PartialResponseError{}
+
+		var batch []string
+		for i, query := range request.Queries {
+			batch = append(batch, query.Commit+query.Package.Name)
+
+			ids, ok := vulns[query.Commit]
+			if query.Commit != "" && !ok {
+				partialErr.add(i, errors.New("unknown commit"))
+
+				continue
+			}
+			for _, id := range ids {
+				resp.Results[i].Vulns = append(resp.Results[i].Vulns, MinimalVulnerability{ID: id})
+			}
+		}
+		*sent = append(*sent, batch)
+
+		if partialErr.Err != nil {
+			return resp, partialErr
+		}
+
+		return resp, nil
+	}
+}
+
+func TestCommitCache_Wrap(t *testing.T) {
+	t.Parallel()
+
+	cache, err := LoadCommitCache(filepath.Join(t.TempDir(), "commits.json"), time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var sent [][]string
+	send := cache.Wrap(fakeSend(map[string][]string{"abc": {"OSV-1"}, "def": nil}, &sent))
+
+	request := BatchedQuery{Queries: []*Query{
+		MakeCommitRequest("abc"),
+		{Package: Package{Name: "lodash", Ecosystem: "npm"}, Version: "4.17.20"},
+		MakeCommitRequest("def"),
+	}}
+
+	for i := 0; i < 2; i++ {
+		resp, err := send(request)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := [][]string{{"OSV-1"}, nil, nil}
+		if diff := cmp.Diff(want, resultIDs(resp)); diff != "" {
+			t.Errorf("response %d mismatch (-want +got):\n%s", i, diff)
+		}
+	}
+
+	want := [][]string{{"abc", "lodash", "def"}, {"lodash"}}
+	if diff := cmp.Diff(want, sent); diff != "" {
+		t.Errorf("sent batches mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCommitCache_Wrap_PartialFailure(t *testing.T) {
+	t.Parallel()
+
+	cache, err := LoadCommitCache(filepath.Join(t.TempDir(), "commits.json"), time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cache.Put("abc", []MinimalVulnerability{{ID: "OSV-1"}})
+
+	var sent [][]string
+	send := cache.Wrap(fakeSend(map[string][]string{"def": {"OSV-2"}}, &sent))
+
+	resp, err := send(BatchedQuery{Queries: []*Query{
+		MakeCommitRequest("abc"),
+		MakeCommitRequest("unknown"),
+		MakeCommitRequest("def"),
+	}})
+
+	var partialErr *PartialResponseError
+	if !errors.As(err, &partialErr) {
+		t.Fatalf("expected a *PartialResponseError, got %v", err)
+	}
+	if diff := cmp.Diff([]int{1}, partialErr.FailedQueries); diff != "" {
+		t.Errorf("FailedQueries mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([][]string{{"OSV-1"}, nil, {"OSV-2"}}, resultIDs(resp)); diff != "" {
+		t.Errorf("response mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, ok := cache.Get("unknown"); ok {
+		t.Errorf("expected the commit that failed to not be cached")
+	}
+	if _, ok := cache.Get("def"); !ok {
+		t.Errorf("expected the commit that succeeded to be cached")
+	}
+}
+
+func TestCommitCache_Expiry(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "cache", "commits.json")
+	now := time.Date(2023, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	cache, err := LoadCommitCache(path, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cache.now = func() time.Time { return now }
+	cache.Put("old", nil)
+	cache.now = func() time.Time { return now.Add(20 * time.Hour) }
+	cache.Put("new", []MinimalVulnerability{{ID: "OSV-1"}})
+	cache.now = func() time.Time { return now.Add(30 * time.Hour) }
+
+	if _, ok := cache.Get("old"); ok {
+		t.Errorf("expected the old commit to have expired")
+	}
+
+	if err := cache.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loaded, err := LoadCommitCache(path, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	loaded.now = cache.now
+
+	if _, ok := loaded.commits["old"]; ok {
+		t.Errorf("expected the old commit to not have been saved")
+	}
+
+	vulns, ok := loaded.Get("new")
+	if !ok {
+		t.Fatalf("expected the new commit to have been saved")
+	}
+	if diff := cmp.Diff([]MinimalVulnerability{{ID: "OSV-1"}}, vulns); diff != "" {
+		t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLoadCommitCache_Invalid(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "commits.json")
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatalf("could not write cache: %v", err)
+	}
+
+	cache, err := LoadCommitCache(path, time.Hour)
+	if err == nil {
+		t.Errorf("expected an error")
+	}
+	if cache == nil || len(cache.commits) != 0 {
+		t.Errorf("expected an empty cache to still be returned")
+	}
+}
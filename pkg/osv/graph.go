@@ -0,0 +1,122 @@
+package osv
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+type depsDevPackageResponse struct {
+	Versions []struct {
+		VersionKey struct {
+			Version string `json:"version"`
+		} `json:"versionKey"`
+	} `json:"versions"`
+}
+
+type depsDevDependenciesResponse struct {
+	Nodes []struct {
+		VersionKey struct {
+			System  string `json:"system"`
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"versionKey"`
+		// Relation is one of "SELF", "DIRECT" or "INDIRECT"
+		Relation string `json:"relation"`
+	} `json:"nodes"`
+}
+
+// depsDevVersion adds the "v" prefix that deps.dev expects onto the versions
+// of Go modules, which are read from go.mod files without it
+func depsDevVersion(ecosystem string, version string) string {
+	if ecosystem == "Go" {
+		return "v" + strings.TrimPrefix(version, "v")
+	}
+
+	return version
+}
+
+// FetchVersions returns the versions of a package that deps.dev knows of, or
+// nil if it does not know of the package. The request is abandoned once ctx is
+// done.
+func FetchVersions(ctx context.Context, ecosystem string, name string) ([]string, error) {
+	return fetchVersions(ctx, DepsDevEndpoint, ecosystem, name)
+}
+
+func fetchVersions(ctx context.Context, endpoint string, ecosystem string, name string) ([]string, error) {
+	system, ok := depsDevSystem(ecosystem)
+	if !ok {
+		return nil, nil
+	}
+
+	var packageResp depsDevPackageResponse
+	found, err := getDepsDev(ctx, endpoint, "/systems/"+system+"/packages/"+url.PathEscape(name), &packageResp)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	versions := make([]string, 0, len(packageResp.Versions))
+	for _, version := range packageResp.Versions {
+		if ecosystem == "Go" {
+			versions = append(versions, strings.TrimPrefix(version.VersionKey.Version, "v"))
+		} else {
+			versions = append(versions, version.VersionKey.Version)
+		}
+	}
+
+	return versions, nil
+}
+
+// FetchDependencyGraph returns a query for the given package version and for
+// each of the packages it depends on, directly or transitively, as resolved
+// by deps.dev, or nil if deps.dev does not know of the version. The package
+// itself is marked as a direct dependency, with all others being transitive.
+// The request is abandoned once ctx is done.
+func FetchDependencyGraph(ctx context.Context, ecosystem string, name string, version string) ([]*Query, error) {
+	return fetchDependencyGraph(ctx, DepsDevEndpoint, ecosystem, name, version)
+}
+
+func fetchDependencyGraph(ctx context.Context, endpoint string, ecosystem string, name string, version string) ([]*Query, error) {
+	system, ok := depsDevSystem(ecosystem)
+	if !ok {
+		return nil, nil
+	}
+
+	var graph depsDevDependenciesResponse
+	path := "/systems/" + system + "/packages/" + url.PathEscape(name) + "/versions/" + url.PathEscape(depsDevVersion(ecosystem, version)) + ":dependencies"
+	found, err := getDepsDev(ctx, endpoint, path, &graph)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	queries := make([]*Query, 0, len(graph.Nodes))
+	for _, node := range graph.Nodes {
+		nodeEcosystem, ok := depsDevEcosystems[node.VersionKey.System]
+		if !ok {
+			continue
+		}
+
+		relation := "transitive"
+		if node.Relation == "SELF" {
+			relation = "direct"
+		}
+
+		nodeVersion := node.VersionKey.Version
+		if nodeEcosystem == "Go" {
+			nodeVersion = strings.TrimPrefix(nodeVersion, "v")
+		}
+
+		queries = append(queries, &Query{
+			Version: nodeVersion,
+			Package: Package{
+				Name:      node.VersionKey.Name,
+				Ecosystem: nodeEcosystem,
+			},
+			Metadata: &models.PackageMetadata{Relation: relation},
+		})
+	}
+
+	return queries, nil
+}
@@ -0,0 +1,79 @@
+package osv
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestFetchVersions(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/systems/GO/packages/golang.org%2Fx%2Ftext":
+			_, _ = w.Write([]byte(`{"versions": [{"versionKey": {"version": "v0.3.5"}}, {"versionKey": {"version": "v0.3.6"}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	versions, err := fetchVersions(context.Background(), server.URL, "Go", "golang.org/x/text")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"0.3.5", "0.3.6"}, versions); diff != "" {
+		t.Errorf("fetchVersions() mismatch (-want +got):\n%s", diff)
+	}
+
+	versions, err = fetchVersions(context.Background(), server.URL, "npm", "wrappyy")
+	if err != nil || versions != nil {
+		t.Errorf("expected no versions for an unknown package, got %v (%v)", versions, err)
+	}
+}
+
+func TestFetchDependencyGraph(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/systems/NPM/packages/express/versions/4.18.2:dependencies":
+			_, _ = w.Write([]byte(`{"nodes": [
+				{"versionKey": {"system": "NPM", "name": "express", "version": "4.18.2"}, "relation": "SELF"},
+				{"versionKey": {"system": "NPM", "name": "body-parser", "version": "1.20.1"}, "relation": "DIRECT"},
+				{"versionKey": {"system": "NPM", "name": "bytes", "version": "3.1.2"}, "relation": "INDIRECT"}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	graph, err := fetchDependencyGraph(context.Background(), server.URL, "npm", "express", "4.18.2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	query := func(name string, version string, relation string) *Query {
+		return &Query{
+			Version:  version,
+			Package:  Package{Name: name, Ecosystem: "npm"},
+			Metadata: &models.PackageMetadata{Relation: relation},
+		}
+	}
+	want := []*Query{
+		query("express", "4.18.2", "direct"),
+		query("body-parser", "1.20.1", "transitive"),
+		query("bytes", "3.1.2", "transitive"),
+	}
+
+	if diff := cmp.Diff(want, graph); diff != "" {
+		t.Errorf("fetchDependencyGraph() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,37 @@
+package osv
+
+import (
+	"context"
+	"net/url"
+)
+
+// FetchLicenses returns the SPDX expressions of the licenses of the given
+// package version, or nil if deps.dev does not know of them. The request is
+// abandoned once ctx is done.
+func FetchLicenses(ctx context.Context, ecosystem string, name string, version string) ([]string, error) {
+	return fetchLicenses(ctx, DepsDevEndpoint, ecosystem, name, version)
+}
+
+func fetchLicenses(ctx context.Context, endpoint string, ecosystem string, name string, version string) ([]string, error) {
+	system, ok := depsDevSystem(ecosystem)
+	if !ok {
+		return nil, nil
+	}
+
+	var versionResp depsDevVersionResponse
+	found, err := getDepsDev(ctx, endpoint, "/systems/"+system+"/packages/"+url.PathEscape(name)+"/versions/"+url.PathEscape(depsDevVersion(ecosystem, version)), &versionResp)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	var licenses []string
+	for _, license := range versionResp.Licenses {
+		// deps.dev says when the license could not be identified, which is
+		// the same as it not being known
+		if license != "" && license != "non-standard" {
+			licenses = append(licenses, license)
+		}
+	}
+
+	return licenses, nil
+}
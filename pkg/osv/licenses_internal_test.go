@@ -0,0 +1,57 @@
+package osv
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFetchLicenses(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/systems/NPM/packages/@babel%2Fcore/versions/7.20.0":
+			_, _ = w.Write([]byte(`{"licenses": ["MIT"]}`))
+		case "/systems/GO/packages/golang.org%2Fx%2Ftext/versions/v0.3.5":
+			_, _ = w.Write([]byte(`{"licenses": ["BSD-3-Clause", "non-standard"]}`))
+		case "/systems/PYPI/packages/internal-tool/versions/1.0.0":
+			_, _ = w.Write([]byte(`{"licenses": []}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		ecosystem string
+		pkg       string
+		version   string
+		want      []string
+	}{
+		{ecosystem: "npm", pkg: "@babel/core", version: "7.20.0", want: []string{"MIT"}},
+		{ecosystem: "Go", pkg: "golang.org/x/text", version: "0.3.5", want: []string{"BSD-3-Clause"}},
+		{ecosystem: "PyPI", pkg: "internal-tool", version: "1.0.0", want: nil},
+		{ecosystem: "npm", pkg: "left-pad", version: "9.9.9", want: nil},
+		{ecosystem: "Packagist", pkg: "monolog/monolog", version: "2.0.0", want: nil},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.pkg, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := fetchLicenses(context.Background(), server.URL, tt.ecosystem, tt.pkg, tt.version)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("fetchLicenses() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,421 @@
+package osv
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/osv-scanner/internal/semantic"
+	"github.com/google/osv-scanner/pkg/models"
+
+	"golang.org/x/exp/slices"
+)
+
+// OfflineDatabaseURL is where the exports of the OSV database are downloaded
+// from, with the vulnerabilities of each ecosystem being in "<ecosystem>/all.zip"
+const OfflineDatabaseURL = "https://osv-vulnerabilities.storage.googleapis.com"
+
+// downloadClient downloads the exports, which can take far longer than the
+// timeout of HTTPClient for the larger ecosystems, so only connecting and
+// waiting for the response to start are bounded, with the download otherwise
+// being abandoned through its context
+var downloadClient = NewHTTPClient(ClientOptions{
+	IdleConnTimeout:       90 * time.Second,
+	TLSHandshakeTimeout:   10 * time.Second,
+	ResponseHeaderTimeout: 30 * time.Second,
+})
+
+// ErrOfflineDatabaseMissing is returned when the local database does not have
+// the export of an ecosystem, and it is not being downloaded
+var ErrOfflineDatabaseMissing = errors.New("offline database not found")
+
+// ErrOfflineQueryUnsupported is returned for queries that cannot be matched
+// against the local database, such as those of commits
+var ErrOfflineQueryUnsupported = errors.New("query is not supported offline")
+
+// LocalDatabase matches queries against exports of the OSV database that are
+// stored locally, rather than sending them to the API, loading the export of
+// each ecosystem the first time a package of it is queried
+type LocalDatabase struct {
+	path        string
+	download    bool
+	downloadURL string
+
+	mu         sync.Mutex
+	ecosystems map[string]*localEcosystem
+	byID       map[string]*models.Vulnerability
+}
+
+// localEcosystem holds the vulnerabilities of an ecosystem by the names of
+// the packages they affect
+type localEcosystem struct {
+	byName map[string][]*models.Vulnerability
+	err    error
+}
+
+// NewLocalDatabase creates a database of the exports stored in the directory
+// at path, laid out as they are at OfflineDatabaseURL. If download is set, the
+// exports of ecosystems that are not stored yet are downloaded into it.
+func NewLocalDatabase(path string, download bool) *LocalDatabase {
+	return &LocalDatabase{
+		path:        path,
+		download:    download,
+		downloadURL: OfflineDatabaseURL,
+		ecosystems:  map[string]*localEcosystem{},
+		byID:        map[string]*models.Vulnerability{},
+	}
+}
+
+// baseEcosystem returns the ecosystem without any release, such as "Debian"
+// for "Debian:11", which is what the exports are organised by
+func baseEcosystem(ecosystem string) string {
+	base, _, _ := strings.Cut(ecosystem, ":")
+
+	return base
+}
+
+// exportPath returns where the export of the ecosystem is stored
+func (db *LocalDatabase) exportPath(ecosystem string) string {
+	return filepath.Join(db.path, baseEcosystem(ecosystem), "all.zip")
+}
+
+// Download stores the latest export of the ecosystem in the database,
+// replacing the one that is stored already if there is one. The download is
+// abandoned once ctx is done.
+func (db *LocalDatabase) Download(ctx context.Context, ecosystem string) error {
+	ecosystem = baseEcosystem(ecosystem)
+	dest := db.exportPath(ecosystem)
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("could not create offline database: %w", err)
+	}
+
+	resp, err := makeRetryRequest(ctx, func() (*http.Response, error) {
+		return getWithContext(ctx, downloadClient, db.downloadURL+"/"+url.PathEscape(ecosystem)+"/all.zip")
+	})
+	if err != nil {
+		return fmt.Errorf("could not download the offline database of %s: %w", ecosystem, err)
+	}
+	defer drainAndClose(resp.Body)
+
+	if err := checkResponseError(resp); err != nil {
+		return fmt.Errorf("could not download the offline database of %s: %w", ecosystem, err)
+	}
+
+	// write to a temporary file first, so that an interrupted download does
+	// not leave behind a truncated export
+	tmp, err := os.CreateTemp(filepath.Dir(dest), "all-*.zip")
+	if err != nil {
+		return fmt.Errorf("could not download the offline database of %s: %w", ecosystem, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, resp.Body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("could not download the offline database of %s: %w", ecosystem, err)
+	}
+
+	return os.Rename(tmp.Name(), dest)
+}
+
+// readExport reads the vulnerabilities of the export at path
+func readExport(path string) ([]*models.Vulnerability, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	vulns := make([]*models.Vulnerability, 0, len(archive.File))
+	for _, file := range archive.File {
+		if !strings.HasSuffix(file.Name, ".json") {
+			continue
+		}
+
+		content, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+
+		var vuln models.Vulnerability
+		err = json.NewDecoder(content).Decode(&vuln)
+		content.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Name, err)
+		}
+
+		vulns = append(vulns, &vuln)
+	}
+
+	return vulns, nil
+}
+
+// load returns the vulnerabilities of the ecosystem, reading its export the
+// first time it is needed and downloading it first if it is missing and the
+// database is allowed to
+func (db *LocalDatabase) load(ctx context.Context, ecosystem string) *localEcosystem {
+	ecosystem = baseEcosystem(ecosystem)
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if loaded, ok := db.ecosystems[ecosystem]; ok {
+		return loaded
+	}
+
+	loaded := &localEcosystem{byName: map[string][]*models.Vulnerability{}}
+	db.ecosystems[ecosystem] = loaded
+
+	path := db.exportPath(ecosystem)
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		if !db.download {
+			loaded.err = fmt.Errorf("%w: %s has no export of %s", ErrOfflineDatabaseMissing, db.path, ecosystem)
+
+			return loaded
+		}
+
+		if err := db.Download(ctx, ecosystem); err != nil {
+			loaded.err = err
+
+			return loaded
+		}
+	}
+
+	vulns, err := readExport(path)
+	if err != nil {
+		loaded.err = fmt.Errorf("could not read the offline database of %s: %w", ecosystem, err)
+
+		return loaded
+	}
+
+	for _, vuln := range vulns {
+		db.byID[vuln.ID] = vuln
+
+		var names []string
+		for _, affected := range vuln.Affected {
+			name := affected.Package.Name
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+				loaded.byName[name] = append(loaded.byName[name], vuln)
+			}
+		}
+	}
+
+	return loaded
+}
+
+// matchesEcosystem reports if the ecosystem of an affected package is that of
+// the query, with a query without a release matching every release
+func matchesEcosystem(affected string, queried string) bool {
+	if strings.EqualFold(affected, queried) {
+		return true
+	}
+
+	return !strings.Contains(queried, ":") && strings.EqualFold(baseEcosystem(affected), queried)
+}
+
+// rangeEvent is an event of an affected range, with version being the version
+// it applies at
+type rangeEvent struct {
+	kind    string
+	version string
+	parsed  semantic.Version
+}
+
+// affectedByRange reports if the version is within the range given by the
+// events, which are applied in order of their versions, as described by the
+// OSV schema
+func affectedByRange(version semantic.Version, ecosystem semantic.Ecosystem, events []rangeEvent) bool {
+	for i := range events {
+		if events[i].version == "0" {
+			continue
+		}
+
+		parsed, err := semantic.Parse(events[i].version, ecosystem)
+		if err != nil {
+			return false
+		}
+		events[i].parsed = parsed
+	}
+
+	sort.SliceStable(events, func(a, b int) bool {
+		switch {
+		case events[a].parsed == nil:
+			return events[b].parsed != nil
+		case events[b].parsed == nil:
+			return false
+		}
+
+		return events[a].parsed.CompareStr(events[b].version) < 0
+	})
+
+	affected := false
+	for _, event := range events {
+		cmp := 1
+		if event.parsed != nil {
+			cmp = version.CompareStr(event.version)
+		}
+
+		switch event.kind {
+		case "introduced":
+			if cmp >= 0 {
+				affected = true
+			}
+		case "fixed":
+			if cmp >= 0 {
+				affected = false
+			}
+		case "last_affected":
+			if cmp > 0 {
+				affected = false
+			}
+		}
+	}
+
+	return affected
+}
+
+// IsAffected reports if the version of the package is affected by the
+// vulnerability, either by being listed or being within one of its ranges.
+// Ranges of git commits are not checked, as they cannot be resolved offline.
+func IsAffected(vuln *models.Vulnerability, ecosystem string, name string, version string) bool {
+	semanticEcosystem := semantic.Ecosystem(baseEcosystem(ecosystem))
+
+	for _, affected := range vuln.Affected {
+		if affected.Package.Name != name || !matchesEcosystem(affected.Package.Ecosystem, ecosystem) {
+			continue
+		}
+
+		if slices.Contains(affected.Versions, version) {
+			return true
+		}
+
+		for _, r := range affected.Ranges {
+			if r.Type == "GIT" {
+				continue
+			}
+
+			eco := semanticEcosystem
+			if r.Type == "SEMVER" {
+				eco = "npm"
+			}
+
+			parsed, err := semantic.Parse(version, eco)
+			if err != nil {
+				continue
+			}
+
+			var events []rangeEvent
+			for _, event := range r.Events {
+				switch {
+				case event.Introduced != "":
+					events = append(events, rangeEvent{kind: "introduced", version: event.Introduced})
+				case event.Fixed != "":
+					events = append(events, rangeEvent{kind: "fixed", version: event.Fixed})
+				case event.LastAffected != "":
+					events = append(events, rangeEvent{kind: "last_affected", version: event.LastAffected})
+				}
+			}
+
+			if affectedByRange(parsed, eco, events) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// MakeRequest matches the queries against the database, returning the IDs of
+// the vulnerabilities that affect each of them like the API does. Only queries
+// with the name and ecosystem of a package are supported, with queries of
+// commits and purls never being matched.
+//
+// Queries that are not supported, or of ecosystems whose exports could not be
+// loaded, are left without vulnerabilities, and a *PartialResponseError is
+// returned for them.
+func (db *LocalDatabase) MakeRequest(request BatchedQuery) (*BatchedResponse, error) {
+	return db.MakeRequestWithContext(context.Background(), request)
+}
+
+// MakeRequestWithContext is like MakeRequest, but abandons the downloads of
+// exports once ctx is done
+func (db *LocalDatabase) MakeRequestWithContext(ctx context.Context, request BatchedQuery) (*BatchedResponse, error) {
+	resp := &BatchedResponse{Results: make([]MinimalResponse, len(request.Queries))}
+	partialErr := &PartialResponseError{}
+
+	for i, query := range request.Queries {
+		if query.Package.Name == "" || query.Package.Ecosystem == "" {
+			partialErr.add(i, fmt.Errorf("%w: only packages with a name and ecosystem can be matched", ErrOfflineQueryUnsupported))
+
+			continue
+		}
+
+		ecosystem := db.load(ctx, query.Package.Ecosystem)
+		if ecosystem.err != nil {
+			partialErr.add(i, ecosystem.err)
+
+			continue
+		}
+
+		for _, vuln := range ecosystem.byName[query.Package.Name] {
+			if IsAffected(vuln, query.Package.Ecosystem, query.Package.Name, query.Version) {
+				resp.Results[i].Vulns = append(resp.Results[i].Vulns, MinimalVulnerability{ID: vuln.ID})
+			}
+		}
+	}
+
+	if partialErr.Err != nil {
+		return resp, partialErr
+	}
+
+	return resp, nil
+}
+
+// Hydrate fills the results of the batched response with the full details of
+// the vulnerabilities from the database, in the same way as Hydrate
+func (db *LocalDatabase) Hydrate(resp *BatchedResponse) (*HydratedBatchedResponse, error) {
+	hydrated := HydratedBatchedResponse{}
+	partialErr := &PartialResponseError{}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	for i, response := range resp.Results {
+		result := Response{}
+		failed := false
+		for _, vuln := range response.Vulns {
+			fullVuln, ok := db.byID[vuln.ID]
+			if !ok {
+				if !failed {
+					partialErr.add(i, fmt.Errorf("%w: %s is not in the offline database", ErrOfflineDatabaseMissing, vuln.ID))
+					failed = true
+				}
+				fullVuln = &models.Vulnerability{ID: vuln.ID}
+			}
+
+			result.Vulns = append(result.Vulns, *fullVuln)
+		}
+		hydrated.Results = append(hydrated.Results, result)
+	}
+
+	if partialErr.Err != nil {
+		return &hydrated, partialErr
+	}
+
+	return &hydrated, nil
+}
@@ -0,0 +1,289 @@
+package osv
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// writeExport writes an export of the ecosystem with the given advisories,
+// keyed by their ids, into the database at dir
+func writeExport(t *testing.T, dir string, ecosystem string, advisories map[string]string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Join(dir, ecosystem), 0o755); err != nil {
+		t.Fatalf("could not create export: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ecosystem, "all.zip"), makeExport(t, advisories), 0o600); err != nil {
+		t.Fatalf("could not write export: %v", err)
+	}
+}
+
+func makeExport(t *testing.T, advisories map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for id, advisory := range advisories {
+		f, err := w.Create(id + ".json")
+		if err != nil {
+			t.Fatalf("could not create export: %v", err)
+		}
+		if _, err := f.Write([]byte(advisory)); err != nil {
+			t.Fatalf("could not create export: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("could not create export: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+var offlineAdvisories = map[string]string{
+	"GHSA-1": `{
+		"id": "GHSA-1",
+		"affected": [{
+			"package": {"ecosystem": "npm", "name": "lodash"},
+			"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "4.17.21"}]}]
+		}]
+	}`,
+	"GHSA-2": `{
+		"id": "GHSA-2",
+		"affected": [{
+			"package": {"ecosystem": "npm", "name": "lodash"},
+			"ranges": [{"type": "SEMVER", "events": [{"introduced": "4.0.0"}, {"last_affected": "4.17.4"}]}]
+		}]
+	}`,
+	"GHSA-3": `{
+		"id": "GHSA-3",
+		"affected": [{
+			"package": {"ecosystem": "npm", "name": "minimist"},
+			"versions": ["1.2.0"]
+		}]
+	}`,
+}
+
+func offlineQuery(ecosystem string, name string, version string) *Query {
+	return &Query{Package: Package{Ecosystem: ecosystem, Name: name}, Version: version}
+}
+
+func resultIDs(resp *BatchedResponse) [][]string {
+	ids := make([][]string, 0, len(resp.Results))
+	for _, result := range resp.Results {
+		var vulnIDs []string
+		for _, vuln := range result.Vulns {
+			vulnIDs = append(vulnIDs, vuln.ID)
+		}
+		ids = append(ids, vulnIDs)
+	}
+
+	return ids
+}
+
+func TestLocalDatabase_MakeRequest(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeExport(t, dir, "npm", offlineAdvisories)
+	writeExport(t, dir, "Debian", map[string]string{
+		"DSA-1": `{
+			"id": "DSA-1",
+			"affected": [{
+				"package": {"ecosystem": "Debian:11", "name": "openssl"},
+				"ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "1.1.1n-0+deb11u1"}]}]
+			}]
+		}`,
+	})
+
+	db := NewLocalDatabase(dir, false)
+	resp, err := db.MakeRequest(BatchedQuery{Queries: []*Query{
+		offlineQuery("npm", "lodash", "4.17.4"),
+		offlineQuery("npm", "lodash", "4.17.20"),
+		offlineQuery("npm", "lodash", "4.17.21"),
+		offlineQuery("npm", "lodash", "3.10.1"),
+		offlineQuery("npm", "minimist", "1.2.0"),
+		offlineQuery("npm", "minimist", "1.2.6"),
+		offlineQuery("Debian:11", "openssl", "1.1.1k-1+deb11u1"),
+		offlineQuery("Debian:10", "openssl", "1.1.1k-1+deb11u1"),
+		offlineQuery("Debian", "openssl", "1.1.1n-0+deb11u1"),
+	}})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := [][]string{
+		{"GHSA-1", "GHSA-2"},
+		{"GHSA-1"},
+		nil,
+		{"GHSA-1"},
+		{"GHSA-3"},
+		nil,
+		{"DSA-1"},
+		nil,
+		nil,
+	}
+
+	got := resultIDs(resp)
+	for _, ids := range got {
+		if len(ids) == 2 && ids[0] > ids[1] {
+			ids[0], ids[1] = ids[1], ids[0]
+		}
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("MakeRequest() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLocalDatabase_MakeRequest_Missing(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeExport(t, dir, "npm", offlineAdvisories)
+
+	db := NewLocalDatabase(dir, false)
+	resp, err := db.MakeRequest(BatchedQuery{Queries: []*Query{
+		offlineQuery("PyPI", "flask", "1.0.0"),
+		offlineQuery("npm", "minimist", "1.2.0"),
+	}})
+
+	var partialErr *PartialResponseError
+	if !errors.As(err, &partialErr) {
+		t.Fatalf("expected a *PartialResponseError, got %v", err)
+	}
+	if !errors.Is(err, ErrOfflineDatabaseMissing) {
+		t.Errorf("expected ErrOfflineDatabaseMissing, got %v", err)
+	}
+	if diff := cmp.Diff([]int{0}, partialErr.FailedQueries); diff != "" {
+		t.Errorf("FailedQueries mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([][]string{nil, {"GHSA-3"}}, resultIDs(resp)); diff != "" {
+		t.Errorf("MakeRequest() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLocalDatabase_MakeRequest_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeExport(t, dir, "npm", offlineAdvisories)
+
+	db := NewLocalDatabase(dir, false)
+	resp, err := db.MakeRequest(BatchedQuery{Queries: []*Query{
+		{Commit: "abc"},
+		offlineQuery("npm", "minimist", "1.2.0"),
+		{Package: Package{PURL: "pkg:generic/openssl@1.1.1"}},
+	}})
+
+	var partialErr *PartialResponseError
+	if !errors.As(err, &partialErr) {
+		t.Fatalf("expected a *PartialResponseError, got %v", err)
+	}
+	if !errors.Is(err, ErrOfflineQueryUnsupported) {
+		t.Errorf("expected ErrOfflineQueryUnsupported, got %v", err)
+	}
+	if diff := cmp.Diff([]int{0, 2}, partialErr.FailedQueries); diff != "" {
+		t.Errorf("FailedQueries mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([][]string{nil, {"GHSA-3"}, nil}, resultIDs(resp)); diff != "" {
+		t.Errorf("MakeRequest() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLocalDatabase_Download(t *testing.T) {
+	t.Parallel()
+
+	export := makeExport(t, offlineAdvisories)
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		if r.URL.Path != "/npm/all.zip" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+		_, _ = w.Write(export)
+	}))
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	db := NewLocalDatabase(dir, true)
+	db.downloadURL = server.URL
+
+	resp, err := db.MakeRequest(BatchedQuery{Queries: []*Query{
+		offlineQuery("npm", "minimist", "1.2.0"),
+		offlineQuery("npm", "lodash", "4.17.20"),
+	}})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"/npm/all.zip"}, requested); diff != "" {
+		t.Errorf("requests mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([][]string{{"GHSA-3"}, {"GHSA-1"}}, resultIDs(resp)); diff != "" {
+		t.Errorf("MakeRequest() mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "npm", "all.zip")); err != nil {
+		t.Errorf("expected the export to be stored: %v", err)
+	}
+}
+
+func TestDownloadClient_HasNoTotalTimeout(t *testing.T) {
+	t.Parallel()
+
+	if downloadClient.Timeout != 0 {
+		t.Errorf("expected exports to be downloaded without a total timeout, got %v", downloadClient.Timeout)
+	}
+
+	//nolint:forcetypeassert // the client is always created by NewHTTPClient
+	if downloadClient.Transport.(*http.Transport).ResponseHeaderTimeout <= 0 {
+		t.Errorf("expected waiting for the response to start to be bounded")
+	}
+}
+
+func TestLocalDatabase_Hydrate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeExport(t, dir, "npm", offlineAdvisories)
+
+	db := NewLocalDatabase(dir, false)
+	resp, err := db.MakeRequest(BatchedQuery{Queries: []*Query{
+		offlineQuery("npm", "minimist", "1.2.0"),
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Results = append(resp.Results, MinimalResponse{Vulns: []MinimalVulnerability{{ID: "GHSA-404"}}})
+
+	hydrated, err := db.Hydrate(resp)
+
+	var partialErr *PartialResponseError
+	if !errors.As(err, &partialErr) {
+		t.Fatalf("expected a *PartialResponseError, got %v", err)
+	}
+	if diff := cmp.Diff([]int{1}, partialErr.FailedQueries); diff != "" {
+		t.Errorf("FailedQueries mismatch (-want +got):\n%s", diff)
+	}
+
+	if len(hydrated.Results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(hydrated.Results))
+	}
+	if got := hydrated.Results[0].Vulns[0]; got.ID != "GHSA-3" || len(got.Affected) != 1 || got.Affected[0].Package.Name != "minimist" {
+		t.Errorf("expected GHSA-3 to be hydrated, got %+v", got)
+	}
+	if got := hydrated.Results[1].Vulns[0].ID; got != "GHSA-404" {
+		t.Errorf("expected GHSA-404 to be kept, got %s", got)
+	}
+}
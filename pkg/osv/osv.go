@@ -1,11 +1,12 @@
 package osv
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/google/osv-scanner/pkg/lockfile"
@@ -22,6 +23,9 @@ const (
 	// maxQueriesPerRequest splits up querybatch into multiple requests if
 	// number of queries exceed this number
 	maxQueriesPerRequest = 1000
+	// DefaultRequestWorkers is how many requests of a split up querybatch are
+	// sent at the same time, unless otherwise specified
+	DefaultRequestWorkers = 4
 )
 
 // Package represents a package identifier for OSV.
@@ -37,6 +41,15 @@ type Query struct {
 	Package Package           `json:"package,omitempty"`
 	Version string            `json:"version,omitempty"`
 	Source  models.SourceInfo `json:"-"`
+	// PackageName is the name of the package that a commit query is for, if
+	// it is known, as the API cannot be queried by both at the same time
+	PackageName string `json:"-"`
+	// Location is where the package is declared within its source, if known
+	Location *models.SourceLocation `json:"-"`
+	// Metadata is any further information the source records about the package
+	Metadata *models.PackageMetadata `json:"-"`
+	// Component is the SBOM component the package was found in, if any
+	Component *models.SBOMComponent `json:"-"`
 }
 
 // BatchedQuery represents a batched query to OSV.
@@ -47,6 +60,9 @@ type BatchedQuery struct {
 // MinimalVulnerability represents an unhydrated vulnerability entry from OSV.
 type MinimalVulnerability struct {
 	ID string `json:"id"`
+	// Modified is when the vulnerability was last modified, which is not known
+	// for vulnerabilities that were answered from a cache
+	Modified time.Time `json:"modified"`
 }
 
 // Response represents a full response from OSV.
@@ -121,64 +137,138 @@ func checkResponseError(resp *http.Response) error {
 	return fmt.Errorf("server response error: %s", string(respBuf))
 }
 
+// PartialResponseError is returned alongside a response when some of the
+// queries could not be answered. The response still has one result per query,
+// with the results of the failed queries left empty.
+type PartialResponseError struct {
+	// FailedQueries holds the indexes of the queries that failed
+	FailedQueries []int
+	// Err is the first error that was encountered
+	Err error
+}
+
+func (e *PartialResponseError) Error() string {
+	return fmt.Sprintf("%d queries failed: %v", len(e.FailedQueries), e.Err)
+}
+
+func (e *PartialResponseError) Unwrap() error {
+	return e.Err
+}
+
+func (e *PartialResponseError) add(index int, err error) {
+	if e.Err == nil {
+		e.Err = err
+	}
+	e.FailedQueries = append(e.FailedQueries, index)
+}
+
 // MakeRequest sends a batched query to osv.dev
+//
+// If a chunk of the batch fails, the remaining chunks are still sent and a
+// *PartialResponseError is returned along with the response.
 func MakeRequest(request BatchedQuery) (*BatchedResponse, error) {
+	return MakeRequestWithWorkers(request, DefaultRequestWorkers)
+}
+
+// MakeRequestWithWorkers sends a batched query to osv.dev, with up to `workers`
+// of the requests it is split into being sent at the same time.
+//
+// The results are in the same order as the queries regardless of the order
+// the requests complete in, and like MakeRequest a *PartialResponseError is
+// returned along with the response if some of the chunks fail.
+func MakeRequestWithWorkers(request BatchedQuery, workers int) (*BatchedResponse, error) {
+	return MakeRequestWithContext(context.Background(), request, workers)
+}
+
+// MakeRequestWithContext is like MakeRequestWithWorkers, but abandons the
+// requests once ctx is done, with the chunks that were not sent failing with
+// the error of ctx
+func MakeRequestWithContext(ctx context.Context, request BatchedQuery, workers int) (*BatchedResponse, error) {
+	return makeConcurrentRequest(request, workers, func(queries []*Query) (*BatchedResponse, error) {
+		return makeChunkRequest(ctx, queries)
+	})
+}
+
+func makeConcurrentRequest(
+	request BatchedQuery,
+	workers int,
+	send func([]*Query) (*BatchedResponse, error),
+) (*BatchedResponse, error) {
 	// API has a limit of 1000 bulk query per request
 	queryChunks := chunkBy(request.Queries, maxQueriesPerRequest)
-	var totalOsvResp BatchedResponse
-	for _, queries := range queryChunks {
-		requestBytes, err := json.Marshal(BatchedQuery{Queries: queries})
-		if err != nil {
-			return nil, err
-		}
-		requestBuf := bytes.NewBuffer(requestBytes)
-
-		resp, err := makeRetryRequest(func() (*http.Response, error) {
-			// We do not need a specific context
-			//nolint:noctx
-			return http.Post(QueryEndpoint, "application/json", requestBuf)
-		})
-		if err != nil {
-			return nil, err
-		}
-		defer resp.Body.Close()
+	responses := make([]*BatchedResponse, len(queryChunks))
+	errs := make([]error, len(queryChunks))
 
-		if err := checkResponseError(resp); err != nil {
-			return nil, err
-		}
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(queryChunks) {
+		workers = len(queryChunks)
+	}
 
-		var osvResp BatchedResponse
-		decoder := json.NewDecoder(resp.Body)
-		err = decoder.Decode(&osvResp)
-		if err != nil {
-			return nil, err
+	chunkIndexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range chunkIndexes {
+				responses[i], errs[i] = send(queryChunks[i])
+			}
+		}()
+	}
+	for i := range queryChunks {
+		chunkIndexes <- i
+	}
+	close(chunkIndexes)
+	wg.Wait()
+
+	var totalOsvResp BatchedResponse
+	partialErr := &PartialResponseError{}
+	offset := 0
+	for i, queries := range queryChunks {
+		osvResp := responses[i]
+		if errs[i] != nil {
+			for j := range queries {
+				partialErr.add(offset+j, errs[i])
+			}
+			osvResp = &BatchedResponse{Results: make([]MinimalResponse, len(queries))}
 		}
 
 		totalOsvResp.Results = append(totalOsvResp.Results, osvResp.Results...)
+		offset += len(queries)
+	}
+
+	if partialErr.Err != nil {
+		return &totalOsvResp, partialErr
 	}
 
 	return &totalOsvResp, nil
 }
 
-// Get a Vulnerability for the given ID.
-func Get(id string) (*models.Vulnerability, error) {
-	resp, err := makeRetryRequest(func() (*http.Response, error) {
-		//nolint:noctx
-		return http.Get(GetEndpoint + "/" + id)
-	})
+func makeChunkRequest(ctx context.Context, queries []*Query) (*BatchedResponse, error) {
+	requestBytes, err := json.Marshal(BatchedQuery{Queries: queries})
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if err := checkResponseError(resp); err != nil {
+	var osvResp BatchedResponse
+	if err := doJSON(ctx, http.MethodPost, QueryEndpoint, requestBytes, &osvResp); err != nil {
 		return nil, err
 	}
 
+	return &osvResp, nil
+}
+
+// Get a Vulnerability for the given ID.
+func Get(id string) (*models.Vulnerability, error) {
+	return GetWithContext(context.Background(), id)
+}
+
+// GetWithContext is like Get, but abandons the request once ctx is done
+func GetWithContext(ctx context.Context, id string) (*models.Vulnerability, error) {
 	var vuln models.Vulnerability
-	decoder := json.NewDecoder(resp.Body)
-	err = decoder.Decode(&vuln)
-	if err != nil {
+	if err := doJSON(ctx, http.MethodGet, GetEndpoint+"/"+id, nil, &vuln); err != nil {
 		return nil, err
 	}
 
@@ -187,27 +277,56 @@ func Get(id string) (*models.Vulnerability, error) {
 
 // Hydrate fills the results of the batched response with the full
 // Vulnerability details.
+//
+// Vulnerabilities that could not be fetched are left with only their ID set,
+// and a *PartialResponseError is returned along with the response.
 func Hydrate(resp *BatchedResponse) (*HydratedBatchedResponse, error) {
+	return HydrateWithContext(context.Background(), resp)
+}
+
+// HydrateWithContext is like Hydrate, but abandons the requests once ctx is
+// done, leaving the vulnerabilities that were not fetched with only their ID
+func HydrateWithContext(ctx context.Context, resp *BatchedResponse) (*HydratedBatchedResponse, error) {
+	return hydrateWith(resp, func(vuln MinimalVulnerability) (*models.Vulnerability, error) {
+		return GetWithContext(ctx, vuln.ID)
+	})
+}
+
+// hydrateWith fills the results of the batched response with the full
+// Vulnerability details returned by get, in the same way as Hydrate
+func hydrateWith(resp *BatchedResponse, get func(vuln MinimalVulnerability) (*models.Vulnerability, error)) (*HydratedBatchedResponse, error) {
 	// TODO(ochang): Parallelize requests, or implement batch GET.
 	hydrated := HydratedBatchedResponse{}
+	partialErr := &PartialResponseError{}
 
-	for _, response := range resp.Results {
+	for i, response := range resp.Results {
 		result := Response{}
+		failed := false
 		for _, vuln := range response.Vulns {
-			vuln, err := Get(vuln.ID)
+			fullVuln, err := get(vuln)
 			if err != nil {
-				return nil, err
+				if !failed {
+					partialErr.add(i, err)
+					failed = true
+				}
+				fullVuln = &models.Vulnerability{ID: vuln.ID}
 			}
 
-			result.Vulns = append(result.Vulns, *vuln)
+			result.Vulns = append(result.Vulns, *fullVuln)
 		}
 		hydrated.Results = append(hydrated.Results, result)
 	}
 
+	if partialErr.Err != nil {
+		return &hydrated, partialErr
+	}
+
 	return &hydrated, nil
 }
 
-func makeRetryRequest(action func() (*http.Response, error)) (*http.Response, error) {
+// makeRetryRequest retries the request a few times if it fails, other than
+// once ctx is done
+func makeRetryRequest(ctx context.Context, action func() (*http.Response, error)) (*http.Response, error) {
 	var resp *http.Response
 	var err error
 	retries := 3
@@ -216,7 +335,12 @@ func makeRetryRequest(action func() (*http.Response, error)) (*http.Response, er
 		if err == nil {
 			break
 		}
-		time.Sleep(time.Second)
+
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(time.Second):
+		}
 	}
 
 	return resp, err
@@ -0,0 +1,105 @@
+package osv
+
+import (
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func makeQueries(count int) BatchedQuery {
+	query := BatchedQuery{}
+	for i := 0; i < count; i++ {
+		query.Queries = append(query.Queries, &Query{
+			Package: Package{Name: "pkg", Ecosystem: "npm"},
+			Version: strconv.Itoa(i),
+		})
+	}
+
+	return query
+}
+
+// echoSend responds to each query with a vulnerability named after its
+// version, with earlier chunks taking longer so that they complete last
+func echoSend(inFlight *int32, maxInFlight *int32) func([]*Query) (*BatchedResponse, error) {
+	return func(queries []*Query) (*BatchedResponse, error) {
+		current := atomic.AddInt32(inFlight, 1)
+		defer atomic.AddInt32(inFlight, -1)
+		for {
+			seen := atomic.LoadInt32(maxInFlight)
+			if current <= seen || atomic.CompareAndSwapInt32(maxInFlight, seen, current) {
+				break
+			}
+		}
+
+		first, _ := strconv.Atoi(queries[0].Version)
+		time.Sleep(time.Duration(5-first/maxQueriesPerRequest) * 10 * time.Millisecond)
+
+		resp := &BatchedResponse{}
+		for _, query := range queries {
+			resp.Results = append(resp.Results, MinimalResponse{
+				Vulns: []MinimalVulnerability{{ID: "OSV-" + query.Version}},
+			})
+		}
+
+		return resp, nil
+	}
+}
+
+func TestMakeConcurrentRequest_Ordering(t *testing.T) {
+	t.Parallel()
+
+	var inFlight, maxInFlight int32
+	query := makeQueries(maxQueriesPerRequest*4 + 10)
+
+	resp, err := makeConcurrentRequest(query, 3, echoSend(&inFlight, &maxInFlight))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(resp.Results) != len(query.Queries) {
+		t.Fatalf("expected %d results, got %d", len(query.Queries), len(resp.Results))
+	}
+
+	for i, result := range resp.Results {
+		if want := "OSV-" + strconv.Itoa(i); result.Vulns[0].ID != want {
+			t.Fatalf("expected result %d to be %s, got %s", i, want, result.Vulns[0].ID)
+		}
+	}
+
+	if maxInFlight < 2 || maxInFlight > 3 {
+		t.Errorf("expected between 2 and 3 requests in flight at once, got %d", maxInFlight)
+	}
+}
+
+func TestMakeConcurrentRequest_PartialFailure(t *testing.T) {
+	t.Parallel()
+
+	query := makeQueries(maxQueriesPerRequest*2 + 5)
+
+	resp, err := makeConcurrentRequest(query, 4, func(queries []*Query) (*BatchedResponse, error) {
+		if queries[0].Version == strconv.Itoa(maxQueriesPerRequest) {
+			return nil, errors.New("server error")
+		}
+
+		return &BatchedResponse{Results: make([]MinimalResponse, len(queries))}, nil
+	})
+
+	var partialErr *PartialResponseError
+	if !errors.As(err, &partialErr) {
+		t.Fatalf("expected a PartialResponseError, got %v", err)
+	}
+
+	if len(resp.Results) != len(query.Queries) {
+		t.Fatalf("expected %d results, got %d", len(query.Queries), len(resp.Results))
+	}
+
+	if len(partialErr.FailedQueries) != maxQueriesPerRequest {
+		t.Fatalf("expected %d failed queries, got %d", maxQueriesPerRequest, len(partialErr.FailedQueries))
+	}
+
+	if first := partialErr.FailedQueries[0]; first != maxQueriesPerRequest {
+		t.Errorf("expected the first failed query to be %d, got %d", maxQueriesPerRequest, first)
+	}
+}
@@ -0,0 +1,205 @@
+package osv
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// QueryCache remembers which vulnerabilities affect each version of a package
+// that has been queried, so that repeated scans of the same packages, such as
+// on every CI run, do not query them again. Each response is stored in its own
+// file of a directory, named by the hash of the package, ecosystem and version
+// it is for. Only the IDs of the vulnerabilities are cached, so they still have
+// to be hydrated.
+type QueryCache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+
+	mu sync.Mutex
+	// pending are the responses that have been cached since the cache was last
+	// saved, keyed by the hashes of their keys
+	pending map[string]cachedResponse
+}
+
+// cachedResponse is the IDs of the vulnerabilities that affect a package, as
+// of when it was queried
+type cachedResponse struct {
+	// Key is what was queried, which is kept to tell responses whose keys
+	// have the same hash apart
+	Key     string    `json:"key"`
+	Vulns   []string  `json:"vulns"`
+	Fetched time.Time `json:"fetched"`
+}
+
+// NewQueryCache returns a cache of the responses stored in dir, which is
+// created when the cache is saved if it does not exist yet. Packages are
+// queried again once they have been cached for longer than ttl, unless it is 0.
+func NewQueryCache(dir string, ttl time.Duration) *QueryCache {
+	return &QueryCache{
+		dir:     dir,
+		ttl:     ttl,
+		now:     time.Now,
+		pending: map[string]cachedResponse{},
+	}
+}
+
+// queryCacheKey returns the key that the response to the query is cached
+// under, reporting false for queries that are not cached, being those of
+// commits, which are cached by CommitCache instead
+func queryCacheKey(query *Query) (string, bool) {
+	switch {
+	case query.Commit != "":
+		return "", false
+	case query.Package.PURL != "":
+		return query.Package.PURL + "@" + query.Version, true
+	case query.Package.Name != "":
+		return query.Package.Name + "@" + query.Version + "/" + query.Package.Ecosystem, true
+	default:
+		return "", false
+	}
+}
+
+// hashKey returns the hash that the response for the key is stored by
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// path returns where the response with the hash is stored, with responses
+// spread across subdirectories so that none of them has too many files
+func (c *QueryCache) path(hash string) string {
+	return filepath.Join(c.dir, hash[:2], hash+".json")
+}
+
+// expired reports if the response was cached too long ago to be used
+func (c *QueryCache) expired(cached cachedResponse) bool {
+	return c.ttl > 0 && c.now().Sub(cached.Fetched) >= c.ttl
+}
+
+// Get returns the vulnerabilities that affect the package of the query,
+// reporting false if its response has not been cached, has expired or cannot
+// be read
+func (c *QueryCache) Get(query *Query) ([]MinimalVulnerability, bool) {
+	key, ok := queryCacheKey(query)
+	if !ok {
+		return nil, false
+	}
+	hash := hashKey(key)
+
+	c.mu.Lock()
+	cached, ok := c.pending[hash]
+	c.mu.Unlock()
+
+	if !ok {
+		content, err := os.ReadFile(c.path(hash))
+		if err != nil {
+			return nil, false
+		}
+		if err := json.Unmarshal(content, &cached); err != nil {
+			return nil, false
+		}
+	}
+
+	if cached.Key != key || c.expired(cached) {
+		return nil, false
+	}
+
+	vulns := make([]MinimalVulnerability, 0, len(cached.Vulns))
+	for _, id := range cached.Vulns {
+		vulns = append(vulns, MinimalVulnerability{ID: id})
+	}
+
+	return vulns, true
+}
+
+// Put caches the vulnerabilities that affect the package of the query, until
+// the cache is saved
+func (c *QueryCache) Put(query *Query, vulns []MinimalVulnerability) {
+	key, ok := queryCacheKey(query)
+	if !ok {
+		return
+	}
+
+	ids := make([]string, 0, len(vulns))
+	for _, vuln := range vulns {
+		ids = append(ids, vuln.ID)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pending[hashKey(key)] = cachedResponse{Key: key, Vulns: ids, Fetched: c.now().UTC()}
+}
+
+// writeResponse writes the response with the hash to its file
+func (c *QueryCache) writeResponse(hash string, cached cachedResponse) error {
+	content, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+
+	path := c.path(hash)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// write to a temporary file first, so that an interrupted save or another
+	// scan reading the cache at the same time never sees a truncated response
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Save writes the responses that have been cached since it was last saved to
+// the directory of the cache, returning the first error if any of them could
+// not be written
+func (c *QueryCache) Save() error {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = map[string]cachedResponse{}
+	c.mu.Unlock()
+
+	failed := 0
+	var firstErr error
+	for hash, cached := range pending {
+		if err := c.writeResponse(hash, cached); err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	if firstErr != nil {
+		return fmt.Errorf("could not save %d responses to the query cache: %w", failed, firstErr)
+	}
+
+	return nil
+}
+
+// Wrap returns a function that sends batches with send, except for the queries
+// of packages that are cached which are answered from the cache instead, and
+// caches the responses to the queries of the other packages
+func (c *QueryCache) Wrap(send func(BatchedQuery) (*BatchedResponse, error)) func(BatchedQuery) (*BatchedResponse, error) {
+	return wrapCache(send, c.Get, c.Put)
+}
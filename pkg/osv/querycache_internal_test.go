@@ -0,0 +1,116 @@
+package osv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestQueryCache_Wrap(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	request := BatchedQuery{Queries: []*Query{
+		{Package: Package{Name: "lodash", Ecosystem: "npm"}, Version: "4.17.20"},
+		MakeCommitRequest("abc"),
+		{Package: Package{Name: "lodash", Ecosystem: "npm"}, Version: "4.17.21"},
+		MakePURLRequest("pkg:pypi/flask@1.0.0"),
+	}}
+
+	var sent [][]string
+	send := NewQueryCache(dir, time.Hour).Wrap(fakeSend(map[string][]string{"abc": {"OSV-1"}}, &sent))
+	if _, err := send(request); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the responses are only read from the directory once they are saved
+	cache := NewQueryCache(dir, time.Hour)
+	cache.Put(request.Queries[0], []MinimalVulnerability{{ID: "GHSA-1"}})
+	cache.Put(request.Queries[3], nil)
+	if err := cache.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	send = NewQueryCache(dir, time.Hour).Wrap(fakeSend(map[string][]string{"abc": {"OSV-1"}}, &sent))
+	resp, err := send(request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff([][]string{{"GHSA-1"}, {"OSV-1"}, nil, nil}, resultIDs(resp)); diff != "" {
+		t.Errorf("response mismatch (-want +got):\n%s", diff)
+	}
+
+	want := [][]string{{"lodash", "abc", "lodash", ""}, {"abc", "lodash"}}
+	if diff := cmp.Diff(want, sent); diff != "" {
+		t.Errorf("sent batches mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestQueryCache_Expiry(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "cache")
+	now := time.Date(2023, 2, 1, 12, 0, 0, 0, time.UTC)
+	oldQuery := &Query{Package: Package{Name: "flask", Ecosystem: "PyPI"}, Version: "1.0.0"}
+	newQuery := &Query{Package: Package{Name: "flask", Ecosystem: "PyPI"}, Version: "2.0.0"}
+
+	cache := NewQueryCache(dir, 24*time.Hour)
+	cache.now = func() time.Time { return now }
+	cache.Put(oldQuery, nil)
+	cache.now = func() time.Time { return now.Add(20 * time.Hour) }
+	cache.Put(newQuery, []MinimalVulnerability{{ID: "OSV-1"}})
+
+	if err := cache.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loaded := NewQueryCache(dir, 24*time.Hour)
+	loaded.now = func() time.Time { return now.Add(30 * time.Hour) }
+
+	if _, ok := loaded.Get(oldQuery); ok {
+		t.Errorf("expected the old version to have expired")
+	}
+
+	vulns, ok := loaded.Get(newQuery)
+	if !ok {
+		t.Fatalf("expected the new version to have been saved")
+	}
+	if diff := cmp.Diff([]MinimalVulnerability{{ID: "OSV-1"}}, vulns); diff != "" {
+		t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+	}
+
+	loaded.ttl = 0
+	if _, ok := loaded.Get(oldQuery); !ok {
+		t.Errorf("expected responses to never expire without a ttl")
+	}
+}
+
+func TestQueryCache_Get_Invalid(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	query := &Query{Package: Package{Name: "lodash", Ecosystem: "npm"}, Version: "4.17.20"}
+	key, _ := queryCacheKey(query)
+
+	cache := NewQueryCache(dir, time.Hour)
+	path := cache.path(hashKey(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("could not create directory: %v", err)
+	}
+
+	for name, content := range map[string]string{
+		"truncated":   `{"key":`,
+		"another key": `{"key":"lodash@4.17.21/npm","vulns":[],"fetched":"` + time.Now().UTC().Format(time.RFC3339) + `"}`,
+	} {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("could not write response: %v", err)
+		}
+		if _, ok := cache.Get(query); ok {
+			t.Errorf("expected a %s response to not be used", name)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package osv
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// DepsDevEndpoint is the URL of the deps.dev API, which links package versions
+// to their source repositories and the OpenSSF Scorecard of those
+const DepsDevEndpoint = "https://api.deps.dev/v3alpha"
+
+type depsDevVersionResponse struct {
+	// Licenses are the SPDX expressions of the licenses of the version
+	Licenses        []string `json:"licenses"`
+	RelatedProjects []struct {
+		ProjectKey struct {
+			ID string `json:"id"`
+		} `json:"projectKey"`
+		RelationType string `json:"relationType"`
+	} `json:"relatedProjects"`
+}
+
+type depsDevProjectResponse struct {
+	Scorecard *struct {
+		Date   string `json:"date"`
+		Checks []struct {
+			Name   string `json:"name"`
+			Score  int    `json:"score"`
+			Reason string `json:"reason"`
+		} `json:"checks"`
+		OverallScore float64 `json:"overallScore"`
+	} `json:"scorecard"`
+}
+
+// depsDevSystem returns the package management system of deps.dev that the
+// ecosystem is known as, if deps.dev has one for it
+func depsDevSystem(ecosystem string) (string, bool) {
+	for system, eco := range depsDevEcosystems {
+		if eco == ecosystem {
+			return system, true
+		}
+	}
+
+	return "", false
+}
+
+// getDepsDev decodes the response of deps.dev for the given path into v,
+// returning false if deps.dev does not know about what was asked for
+func getDepsDev(ctx context.Context, endpoint string, path string, v interface{}) (bool, error) {
+	resp, err := makeRetryRequest(ctx, func() (*http.Response, error) {
+		return getWithContext(ctx, HTTPClient, endpoint+path)
+	})
+	if err != nil {
+		return false, err
+	}
+	defer drainAndClose(resp.Body)
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+
+	if err := checkResponseError(resp); err != nil {
+		return false, err
+	}
+
+	return true, json.NewDecoder(resp.Body).Decode(v)
+}
+
+// FetchScorecard returns the OpenSSF Scorecard of the source repository of the
+// given package version, or nil if deps.dev does not know of one. The requests
+// are abandoned once ctx is done.
+func FetchScorecard(ctx context.Context, ecosystem string, name string, version string) (*models.Scorecard, error) {
+	return fetchScorecard(ctx, DepsDevEndpoint, ecosystem, name, version)
+}
+
+func fetchScorecard(ctx context.Context, endpoint string, ecosystem string, name string, version string) (*models.Scorecard, error) {
+	system, ok := depsDevSystem(ecosystem)
+	if !ok {
+		return nil, nil
+	}
+
+	var versionResp depsDevVersionResponse
+	found, err := getDepsDev(ctx, endpoint, "/systems/"+system+"/packages/"+url.PathEscape(name)+"/versions/"+url.PathEscape(depsDevVersion(ecosystem, version)), &versionResp)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	project := ""
+	for _, related := range versionResp.RelatedProjects {
+		if related.RelationType == "SOURCE_REPO" {
+			project = related.ProjectKey.ID
+
+			break
+		}
+	}
+	if project == "" {
+		return nil, nil
+	}
+
+	var projectResp depsDevProjectResponse
+	found, err = getDepsDev(ctx, endpoint, "/projects/"+url.PathEscape(project), &projectResp)
+	if err != nil || !found || projectResp.Scorecard == nil {
+		return nil, err
+	}
+
+	scorecard := &models.Scorecard{
+		Project:      project,
+		Date:         projectResp.Scorecard.Date,
+		OverallScore: projectResp.Scorecard.OverallScore,
+	}
+	for _, check := range projectResp.Scorecard.Checks {
+		scorecard.Checks = append(scorecard.Checks, models.ScorecardCheck{
+			Name:   check.Name,
+			Score:  check.Score,
+			Reason: check.Reason,
+		})
+	}
+
+	return scorecard, nil
+}
@@ -0,0 +1,105 @@
+package osv
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestFetchScorecard(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/systems/GO/packages/golang.org%2Fx%2Ftext/versions/v0.3.5":
+			_, _ = w.Write([]byte(`{"relatedProjects": [
+				{"projectKey": {"id": "github.com/golang/go"}, "relationType": "ISSUE_TRACKER"},
+				{"projectKey": {"id": "github.com/golang/text"}, "relationType": "SOURCE_REPO"}
+			]}`))
+		case "/projects/github.com%2Fgolang%2Ftext":
+			_, _ = w.Write([]byte(`{"scorecard": {
+				"date": "2023-06-05T00:00:00Z",
+				"overallScore": 6.2,
+				"checks": [
+					{"name": "Maintained", "score": 10, "reason": "30 commit(s) out of 30 and 4 issue activity out of 30 found in the last 90 days"},
+					{"name": "Fuzzing", "score": -1, "reason": "internal error"}
+				]
+			}}`))
+		case "/systems/NPM/packages/left-pad/versions/1.3.0":
+			_, _ = w.Write([]byte(`{"relatedProjects": []}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name      string
+		ecosystem string
+		pkg       string
+		version   string
+		want      *models.Scorecard
+	}{
+		{
+			name:      "package with a scorecard",
+			ecosystem: "Go",
+			pkg:       "golang.org/x/text",
+			version:   "0.3.5",
+			want: &models.Scorecard{
+				Project:      "github.com/golang/text",
+				Date:         "2023-06-05T00:00:00Z",
+				OverallScore: 6.2,
+				Checks: []models.ScorecardCheck{
+					{Name: "Maintained", Score: 10, Reason: "30 commit(s) out of 30 and 4 issue activity out of 30 found in the last 90 days"},
+					{Name: "Fuzzing", Score: -1, Reason: "internal error"},
+				},
+			},
+		},
+		{name: "package without a source repository", ecosystem: "npm", pkg: "left-pad", version: "1.3.0", want: nil},
+		{name: "unknown package", ecosystem: "npm", pkg: "wrappyy", version: "1.0.0", want: nil},
+		{name: "unsupported ecosystem", ecosystem: "Packagist", pkg: "some/package", version: "1.0.0", want: nil},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := fetchScorecard(context.Background(), server.URL, tt.ecosystem, tt.pkg, tt.version)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("fetchScorecard() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFetchScorecard_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- struct{}{}
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-requests
+		cancel()
+	}()
+
+	_, err := fetchScorecard(ctx, server.URL, "npm", "left-pad", "1.3.0")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the request to be abandoned, got %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package osvscanner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+// scanBackend is where the queries of a scan are sent and the details of the
+// vulnerabilities they match are fetched from, along with the caches of them
+type scanBackend struct {
+	hydrate func(resp *osv.BatchedResponse) (*osv.HydratedBatchedResponse, error)
+
+	advisoryBundle *osv.AdvisoryBundle
+	commitCache    *osv.CommitCache
+	queryCache     *osv.QueryCache
+}
+
+// newScanBackend sets up the backend that the actions ask for, having the
+// stream send its batches to it
+func newScanBackend(ctx context.Context, r output.Reporter, actions ScannerActions, stream *queryStream) *scanBackend {
+	backend := &scanBackend{
+		hydrate: func(resp *osv.BatchedResponse) (*osv.HydratedBatchedResponse, error) {
+			return osv.HydrateWithContext(ctx, resp)
+		},
+	}
+
+	if actions.OfflineDatabasePath != "" {
+		database := osv.NewLocalDatabase(actions.OfflineDatabasePath, actions.DownloadOfflineDatabases)
+		stream.send = func(batch osv.BatchedQuery) (*osv.BatchedResponse, error) {
+			return database.MakeRequestWithContext(ctx, offlineQueries(batch))
+		}
+		backend.hydrate = database.Hydrate
+
+		// the offline database is no slower than any of the caches
+		return backend
+	}
+
+	if actions.AdvisoryBundlePath != "" {
+		bundle, err := osv.LoadAdvisoryBundle(actions.AdvisoryBundlePath, actions.AdvisoryBundleTTL)
+		if err != nil {
+			r.PrintText(fmt.Sprintf("%v, advisories will be fetched again\n", err))
+		}
+		backend.advisoryBundle = bundle
+		backend.hydrate = func(resp *osv.BatchedResponse) (*osv.HydratedBatchedResponse, error) {
+			return bundle.HydrateWithContext(ctx, resp)
+		}
+	}
+
+	if actions.CommitCachePath != "" {
+		cache, err := osv.LoadCommitCache(actions.CommitCachePath, actions.CommitCacheTTL)
+		if err != nil {
+			r.PrintText(fmt.Sprintf("%v, commits will be queried again\n", err))
+		}
+		backend.commitCache = cache
+		stream.send = cache.Wrap(stream.send)
+	}
+
+	if actions.QueryCachePath != "" {
+		backend.queryCache = osv.NewQueryCache(actions.QueryCachePath, actions.QueryCacheTTL)
+		stream.send = backend.queryCache.Wrap(stream.send)
+	}
+
+	return backend
+}
+
+// saveQueries saves the caches of the responses to queries, once every batch
+// has been sent
+func (b *scanBackend) saveQueries(r output.Reporter) {
+	if b.commitCache != nil {
+		if err := b.commitCache.Save(); err != nil {
+			r.PrintText(fmt.Sprintf("%v\n", err))
+		}
+	}
+	if b.queryCache != nil {
+		if err := b.queryCache.Save(); err != nil {
+			r.PrintText(fmt.Sprintf("%v\n", err))
+		}
+	}
+}
+
+// saveAdvisories saves the bundle of advisories, once they have been hydrated
+func (b *scanBackend) saveAdvisories(r output.Reporter) {
+	if b.advisoryBundle != nil {
+		if err := b.advisoryBundle.Save(); err != nil {
+			r.PrintText(fmt.Sprintf("%v\n", err))
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package osvscanner
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+// loadBaseline reads the JSON results of a previous scan to compare the
+// results of the scan to
+func loadBaseline(path string) (*models.VulnerabilityResults, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read baseline: %w", err)
+	}
+
+	parsed, err := output.ParseJSONResults(content)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse baseline from %s: %w", path, err)
+	}
+
+	return &parsed.VulnerabilityResults, nil
+}
+
+// applyBaseline removes the vulnerabilities that are present in the baseline
+// from the results, so that only new vulnerabilities are reported and can
+// fail the scan, returning how many were removed
+func applyBaseline(results *models.VulnerabilityResults, baseline *models.VulnerabilityResults) int {
+	before := len(results.Flatten())
+	results.Results = results.NewSince(baseline)
+	if results.Results == nil {
+		results.Results = []models.PackageSource{}
+	}
+
+	return before - len(results.Flatten())
+}
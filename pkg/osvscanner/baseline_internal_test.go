@@ -0,0 +1,142 @@
+package osvscanner
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func lockfileResults(path string, packages ...models.PackageVulns) models.VulnerabilityResults {
+	return models.VulnerabilityResults{
+		Results: []models.PackageSource{{
+			Source:   models.SourceInfo{Path: path, Type: "lockfile"},
+			Packages: packages,
+		}},
+	}
+}
+
+func vulnerablePackage(name string, version string, ids ...string) models.PackageVulns {
+	pkg := models.PackageVulns{Package: models.PackageInfo{Name: name, Version: version, Ecosystem: "npm"}}
+	for _, id := range ids {
+		pkg.Vulnerabilities = append(pkg.Vulnerabilities, models.Vulnerability{ID: id})
+		pkg.Groups = append(pkg.Groups, models.GroupInfo{IDs: []string{id}})
+	}
+
+	return pkg
+}
+
+func TestApplyBaseline(t *testing.T) {
+	t.Parallel()
+
+	// the baseline was written by a scan of another checkout, at an older
+	// version of the package
+	baseline := lockfileResults("other/package-lock.json", vulnerablePackage("minimist", "0.0.5", "GHSA-vh95-rmgr-6w4m"))
+
+	results := lockfileResults("/app/package-lock.json",
+		vulnerablePackage("minimist", "0.0.8", "GHSA-vh95-rmgr-6w4m", "GHSA-xvch-5gv4-984h"),
+		vulnerablePackage("lodash", "4.17.15", "GHSA-p6mc-m468-83gw"),
+	)
+	results.ParseFailures = []models.ParseFailure{{Path: "/app/yarn.lock"}}
+
+	if n := applyBaseline(&results, &baseline); n != 1 {
+		t.Errorf("expected 1 vulnerability to be filtered, got %d", n)
+	}
+
+	want := lockfileResults("/app/package-lock.json",
+		vulnerablePackage("minimist", "0.0.8", "GHSA-xvch-5gv4-984h"),
+		vulnerablePackage("lodash", "4.17.15", "GHSA-p6mc-m468-83gw"),
+	)
+	want.ParseFailures = []models.ParseFailure{{Path: "/app/yarn.lock"}}
+	if diff := cmp.Diff(want, results); diff != "" {
+		t.Errorf("applyBaseline() mismatch (-want +got):\n%s", diff)
+	}
+
+	if n := applyBaseline(&results, &results); n != 2 || len(results.Results) != 0 || results.Results == nil {
+		t.Errorf("expected every vulnerability to be filtered, got %d and %v", n, results.Results)
+	}
+}
+
+func TestLoadBaseline(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"baseline.json": `{"results":[{"source":{"path":"package-lock.json","type":"lockfile"},"packages":[{"package":{"name":"lodash","version":"4.17.15","ecosystem":"npm"},"vulnerabilities":[{"id":"GHSA-p6mc-m468-83gw"}],"groups":[{"ids":["GHSA-p6mc-m468-83gw"]}]}]}]}`,
+		"newer.json":    `{"schemaVersion":999,"results":[]}`,
+	})
+
+	baseline, err := loadBaseline(filepath.Join(dir, "baseline.json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := len(baseline.Flatten()); got != 1 {
+		t.Errorf("expected 1 vulnerability in the baseline, got %d", got)
+	}
+
+	for name, wantErr := range map[string]string{
+		"newer.json":   "could not parse baseline",
+		"missing.json": "could not read baseline",
+	} {
+		_, err := loadBaseline(filepath.Join(dir, name))
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("expected an error containing %q for %s, got %v", wantErr, name, err)
+		}
+	}
+}
+
+func TestApplyBaseline_KeepsIncompleteSources(t *testing.T) {
+	t.Parallel()
+
+	baseline := lockfileResults("/app/package-lock.json", vulnerablePackage("minimist", "0.0.8", "GHSA-vh95-rmgr-6w4m"))
+
+	results := lockfileResults("/app/package-lock.json", vulnerablePackage("minimist", "0.0.8", "GHSA-vh95-rmgr-6w4m"))
+	results.Results[0].Incomplete = true
+
+	if n := applyBaseline(&results, &baseline); n != 1 {
+		t.Errorf("expected 1 vulnerability to be filtered, got %d", n)
+	}
+
+	want := []models.PackageSource{{
+		Source:     models.SourceInfo{Path: "/app/package-lock.json", Type: "lockfile"},
+		Packages:   []models.PackageVulns{},
+		Incomplete: true,
+	}}
+	if diff := cmp.Diff(want, results.Results); diff != "" {
+		t.Errorf("applyBaseline() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestApplyBaseline_Fingerprints(t *testing.T) {
+	t.Parallel()
+
+	fingerprinted := func(root string, path string, version string) models.VulnerabilityResults {
+		pkg := vulnerablePackage("minimist", version, "GHSA-vh95-rmgr-6w4m")
+		source := filepath.Join(root, path)
+		pkg.Groups[0].Fingerprint = models.Fingerprint(relativeToScanRoot([]string{root}, source), pkg.Package, pkg.Groups[0].IDs)
+
+		return lockfileResults(source, pkg)
+	}
+
+	// the baseline was written by a scan of another checkout
+	baseline := fingerprinted(filepath.FromSlash("/ci/checkout"), filepath.FromSlash("web/package-lock.json"), "0.0.5")
+
+	same := fingerprinted(filepath.FromSlash("/home/me/project"), filepath.FromSlash("web/package-lock.json"), "0.0.8")
+	if n := applyBaseline(&same, &baseline); n != 1 {
+		t.Errorf("expected the finding in the same lockfile to be filtered, got %d", n)
+	}
+
+	other := fingerprinted(filepath.FromSlash("/home/me/project"), filepath.FromSlash("api/package-lock.json"), "0.0.5")
+	if n := applyBaseline(&other, &baseline); n != 0 {
+		t.Errorf("expected the finding in another lockfile to be kept, got %d filtered", n)
+	}
+
+	// baselines written before findings had fingerprints still apply
+	legacy := lockfileResults("package-lock.json", vulnerablePackage("minimist", "0.0.5", "GHSA-vh95-rmgr-6w4m"))
+	other = fingerprinted(filepath.FromSlash("/home/me/project"), filepath.FromSlash("api/package-lock.json"), "0.0.8")
+	if n := applyBaseline(&other, &legacy); n != 1 {
+		t.Errorf("expected the finding to be filtered by a baseline without fingerprints, got %d", n)
+	}
+}
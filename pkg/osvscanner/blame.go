@@ -0,0 +1,51 @@
+package osvscanner
+
+import (
+	"fmt"
+
+	"github.com/google/osv-scanner/pkg/githistory"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+// annotateBlame adds the commit that last changed the line pinning each
+// vulnerable package to the results, for lockfiles that are committed to git
+func annotateBlame(r output.Reporter, results *models.VulnerabilityResults) {
+	for i := range results.Results {
+		source := &results.Results[i]
+		if source.Source.Type != "lockfile" || len(source.Packages) == 0 {
+			continue
+		}
+
+		blame, err := githistory.BlameFile(source.Source.Path)
+		if err != nil {
+			r.PrintText(fmt.Sprintf("Could not blame %s: %v\n", source.Source.Path, err))
+			continue
+		}
+
+		for j := range source.Packages {
+			pkg := &source.Packages[j]
+
+			var line int
+			var commit githistory.Commit
+			var ok bool
+			if pkg.Location != nil {
+				line = pkg.Location.Line
+				commit, ok = blame.Line(line)
+			} else {
+				line, commit, ok = blame.PackageLine(pkg.Package.Name, pkg.Package.Version)
+			}
+			if !ok {
+				continue
+			}
+
+			pkg.Blame = &models.BlameInfo{
+				Line:   line,
+				Commit: commit.Hash,
+				Author: commit.Author,
+				Email:  commit.Email,
+				Date:   commit.Time,
+			}
+		}
+	}
+}
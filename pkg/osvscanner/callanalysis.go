@@ -0,0 +1,338 @@
+package osvscanner
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+// The verdicts of the call analysis of a vulnerability
+const (
+	verdictCalled      = "called"
+	verdictImported    = "imported"
+	verdictNotImported = "not-imported"
+	verdictUnknown     = "unknown"
+)
+
+// goImport is a package that a vulnerability of a Go module affects, along
+// with its symbols that are vulnerable, with no symbols meaning that all of
+// them are
+type goImport struct {
+	path    string
+	symbols []string
+	// prefix matches the packages within path as well, for vulnerabilities
+	// that do not say which packages of the module they affect
+	prefix bool
+}
+
+// matches reports if the import path is of a package that is affected
+func (imp goImport) matches(path string) bool {
+	return path == imp.path || (imp.prefix && strings.HasPrefix(path, imp.path+"/"))
+}
+
+// vulnerableImports returns the packages of the module that the vulnerability
+// affects, as listed by the "imports" of its Go specific details, being the
+// whole module if it does not list any
+func vulnerableImports(vuln models.Vulnerability, module string) []goImport {
+	var imports []goImport
+
+	for _, affected := range vuln.Affected {
+		if affected.Package.Ecosystem != string(lockfile.GoEcosystem) || affected.Package.Name != module {
+			continue
+		}
+
+		listed, _ := affected.EcosystemSpecific["imports"].([]interface{})
+		for _, entry := range listed {
+			entry, _ := entry.(map[string]interface{})
+			path, _ := entry["path"].(string)
+			if path == "" {
+				continue
+			}
+
+			imp := goImport{path: path}
+			symbols, _ := entry["symbols"].([]interface{})
+			for _, symbol := range symbols {
+				if symbol, ok := symbol.(string); ok {
+					imp.symbols = append(imp.symbols, symbol)
+				}
+			}
+			imports = append(imports, imp)
+		}
+	}
+
+	if len(imports) == 0 {
+		return []goImport{{path: module, prefix: true}}
+	}
+
+	return imports
+}
+
+// majorVersionSuffix matches the last element of the import paths of major
+// versions of modules, such as "v2"
+var majorVersionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+
+// goPackageName guesses the name of the package at the import path, as the
+// packages that are imported are not parsed to find out
+func goPackageName(path string) string {
+	elements := strings.Split(path, "/")
+	name := elements[len(elements)-1]
+	if len(elements) > 1 && majorVersionSuffix.MatchString(name) {
+		name = elements[len(elements)-2]
+	}
+
+	// such as gopkg.in/yaml.v3
+	if i := strings.Index(name, ".v"); i > 0 {
+		name = name[:i]
+	}
+	name = strings.TrimPrefix(strings.TrimPrefix(name, "go-"), "go.")
+	name = strings.TrimSuffix(name, "-go")
+	name = strings.TrimSuffix(name, ".go")
+
+	return strings.NewReplacer("-", "", ".", "").Replace(name)
+}
+
+// goModuleUsage is what the code of a Go module uses of the packages it
+// imports, keyed by their import paths
+type goModuleUsage struct {
+	imported map[string]bool
+	// selected are the names used from each package, such as its functions
+	selected map[string]map[string]bool
+	// methods are the names selected from anything in the files that import
+	// each package, which includes the methods of its types that are called
+	// as they cannot be told apart without type checking
+	methods map[string]map[string]bool
+}
+
+func addName(names map[string]map[string]bool, path string, name string) {
+	if names[path] == nil {
+		names[path] = map[string]bool{}
+	}
+	names[path][name] = true
+}
+
+// analyzeGoModule parses the Go code of the module in dir, other than its
+// tests and any modules nested within it, to find what it uses of the
+// packages it imports
+func analyzeGoModule(dir string) (*goModuleUsage, error) {
+	usage := &goModuleUsage{
+		imported: map[string]bool{},
+		selected: map[string]map[string]bool{},
+		methods:  map[string]map[string]bool{},
+	}
+	fset := token.NewFileSet()
+
+	err := filepath.WalkDir(dir, func(path string, info fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			name := info.Name()
+			if path != dir && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			if path != dir {
+				if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+					return filepath.SkipDir
+				}
+			}
+
+			return nil
+		}
+
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			// the module would not build with a file that cannot be parsed,
+			// so it is not part of what is built, such as a template
+			return nil //nolint:nilerr
+		}
+		usage.addFile(file)
+
+		return nil
+	})
+
+	return usage, err
+}
+
+// addFile records what the file uses of the packages that it imports
+func (u *goModuleUsage) addFile(file *ast.File) {
+	names := map[string]string{}
+	var dotImports []string
+	var paths []string
+
+	for _, spec := range file.Imports {
+		path := strings.Trim(spec.Path.Value, "\"`")
+		u.imported[path] = true
+		paths = append(paths, path)
+
+		switch {
+		case spec.Name == nil:
+			names[goPackageName(path)] = path
+		case spec.Name.Name == ".":
+			dotImports = append(dotImports, path)
+		case spec.Name.Name != "_":
+			names[spec.Name.Name] = path
+		}
+	}
+
+	selectors := map[string]bool{}
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.SelectorExpr:
+			selectors[node.Sel.Name] = true
+			if ident, ok := node.X.(*ast.Ident); ok {
+				if path, ok := names[ident.Name]; ok {
+					addName(u.selected, path, node.Sel.Name)
+				}
+			}
+		case *ast.Ident:
+			// the names of packages that are dot imported are used as is
+			for _, path := range dotImports {
+				addName(u.selected, path, node.Name)
+			}
+		}
+
+		return true
+	})
+
+	for _, path := range paths {
+		for name := range selectors {
+			addName(u.methods, path, name)
+		}
+	}
+}
+
+// verdict returns if the code of the module calls the vulnerable symbols of
+// the packages, along with which of them it calls
+func (u *goModuleUsage) verdict(imports []goImport) (string, []string) {
+	var paths []string
+	for path := range u.imported {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	imported := false
+	var called []string
+	for _, imp := range imports {
+		for _, path := range paths {
+			if !imp.matches(path) {
+				continue
+			}
+			imported = true
+
+			if len(imp.symbols) == 0 {
+				if len(u.selected[path]) > 0 {
+					called = append(called, path)
+				}
+
+				continue
+			}
+
+			for _, symbol := range imp.symbols {
+				name := symbol
+				// methods are named after their type, such as "Client.Do"
+				if i := strings.LastIndex(symbol, "."); i != -1 {
+					name = symbol[i+1:]
+					if !u.methods[path][name] {
+						continue
+					}
+				} else if !u.selected[path][name] {
+					continue
+				}
+				called = append(called, path+"."+symbol)
+			}
+		}
+	}
+
+	switch {
+	case len(called) > 0:
+		return verdictCalled, called
+	case imported:
+		return verdictImported, nil
+	default:
+		return verdictNotImported, nil
+	}
+}
+
+// analyzeCalls works out if the vulnerabilities of the packages of each Go
+// module that was scanned are reachable from its code, by looking at what it
+// uses of the packages they affect
+func analyzeCalls(r output.Reporter, vulnResult *models.VulnerabilityResults) []models.VulnerabilityReachability {
+	var reachability []models.VulnerabilityReachability
+	modules := map[string]*goModuleUsage{}
+
+	for _, res := range vulnResult.Results {
+		if res.Source.Type != "lockfile" || filepath.Base(res.Source.Path) != "go.mod" {
+			continue
+		}
+
+		dir := filepath.Dir(res.Source.Path)
+		usage, ok := modules[dir]
+		if !ok {
+			var err error
+			usage, err = analyzeGoModule(dir)
+			if err != nil {
+				r.PrintText(fmt.Sprintf("Failed to analyze the calls of %s: %v\n", dir, err))
+				usage = nil
+			}
+			modules[dir] = usage
+		}
+
+		for _, pkg := range res.Packages {
+			if pkg.Package.Ecosystem != string(lockfile.GoEcosystem) {
+				continue
+			}
+
+			for _, vuln := range pkg.Vulnerabilities {
+				result := models.VulnerabilityReachability{
+					Source:  res.Source,
+					Package: pkg.Package,
+					ID:      vuln.ID,
+					Verdict: verdictUnknown,
+				}
+
+				imports := vulnerableImports(vuln, pkg.Package.Name)
+				// the standard library is not a module that can be imported
+				// as a whole, so it has to say which packages are affected
+				if usage != nil && !(pkg.Package.Name == "stdlib" && imports[0].prefix) {
+					result.Verdict, result.Symbols = usage.verdict(imports)
+				}
+
+				reachability = append(reachability, result)
+			}
+		}
+	}
+
+	return reachability
+}
+
+// sortReachability orders the reachability of vulnerabilities by their source,
+// package and ID
+func sortReachability(reachability []models.VulnerabilityReachability) {
+	sort.SliceStable(reachability, func(a, b int) bool {
+		ra, rb := reachability[a], reachability[b]
+		if ra.Source.Path != rb.Source.Path {
+			return ra.Source.Path < rb.Source.Path
+		}
+		if ra.Package.Name != rb.Package.Name {
+			return ra.Package.Name < rb.Package.Name
+		}
+
+		return ra.ID < rb.ID
+	})
+}
@@ -0,0 +1,197 @@
+package osvscanner
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+// writeModule writes the files of a Go module to a temporary directory,
+// returning the path of its go.mod
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	files["go.mod"] = "module example.com/app\n"
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("could not create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("could not write %s: %v", name, err)
+		}
+	}
+
+	return filepath.Join(dir, "go.mod")
+}
+
+// goVulnerability makes a vulnerability of the Go module with the given
+// "imports" in its ecosystem specific details
+func goVulnerability(t *testing.T, id string, module string, imports string) models.Vulnerability {
+	t.Helper()
+
+	var vuln models.Vulnerability
+	content := `{"id": "` + id + `", "affected": [{"package": {"ecosystem": "Go", "name": "` + module + `"}`
+	if imports != "" {
+		content += `, "ecosystem_specific": {"imports": ` + imports + `}`
+	}
+	content += `}]}`
+
+	if err := json.Unmarshal([]byte(content), &vuln); err != nil {
+		t.Fatalf("could not make vulnerability: %v", err)
+	}
+
+	return vuln
+}
+
+func TestGoPackageName(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"net/http":                        "http",
+		"github.com/gin-gonic/gin":        "gin",
+		"gopkg.in/yaml.v3":                "yaml",
+		"github.com/go-git/go-git/v5":     "git",
+		"github.com/mattn/go-sqlite3":     "sqlite3",
+		"github.com/satori/go.uuid":       "uuid",
+		"github.com/go-chi/chi/v5":        "chi",
+		"golang.org/x/net/html/charset":   "charset",
+		"github.com/jackc/pgx/v4/pgxpool": "pgxpool",
+	}
+	for path, want := range tests {
+		if got := goPackageName(path); got != want {
+			t.Errorf("goPackageName(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestAnalyzeCalls(t *testing.T) {
+	t.Parallel()
+
+	gomod := writeModule(t, map[string]string{
+		"main.go": `package main
+
+import (
+	"net/http"
+
+	"golang.org/x/text/language"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func main() {
+	_ = language.Make("en")
+	var v interface{}
+	_ = yaml.Unmarshal(nil, &v)
+	resp, _ := http.Get("https://example.com")
+	resp.Body.Close()
+}
+`,
+		"internal/client/client.go": `package client
+
+import (
+	"golang.org/x/net/http2"
+)
+
+func New(s *http2.Server) {
+	s.ServeConn(nil, nil)
+}
+`,
+		"internal/client/client_test.go": `package client
+
+import "github.com/gin-gonic/gin"
+
+var _ = gin.Default()
+`,
+		"tools/go.mod": "module example.com/app/tools\n",
+		"tools/tools.go": `package tools
+
+import "github.com/gin-gonic/gin"
+
+var _ = gin.Default()
+`,
+		"lib/lib.go": `package lib
+
+import _ "golang.org/x/crypto/ssh"
+`,
+	})
+
+	source := models.SourceInfo{Path: gomod, Type: "lockfile"}
+	pkg := func(name string, vulns ...models.Vulnerability) models.PackageVulns {
+		return models.PackageVulns{
+			Package:         models.PackageInfo{Name: name, Version: "1.0.0", Ecosystem: "Go"},
+			Vulnerabilities: vulns,
+		}
+	}
+
+	results := &models.VulnerabilityResults{Results: []models.PackageSource{{
+		Source: source,
+		Packages: []models.PackageVulns{
+			pkg("golang.org/x/text",
+				goVulnerability(t, "GO-1", "golang.org/x/text", `[{"path": "golang.org/x/text/language", "symbols": ["Parse", "MatchStrings"]}]`),
+				goVulnerability(t, "GO-2", "golang.org/x/text", `[{"path": "golang.org/x/text/language", "symbols": ["Make"]}]`),
+				goVulnerability(t, "GO-3", "golang.org/x/text", `[{"path": "golang.org/x/text/encoding/unicode"}]`),
+			),
+			pkg("gopkg.in/yaml.v2", goVulnerability(t, "GO-4", "gopkg.in/yaml.v2", "")),
+			pkg("golang.org/x/net", goVulnerability(t, "GO-5", "golang.org/x/net", `[{"path": "golang.org/x/net/http2", "symbols": ["Server.ServeConn"]}]`)),
+			pkg("golang.org/x/crypto", goVulnerability(t, "GO-6", "golang.org/x/crypto", `[{"path": "golang.org/x/crypto/ssh", "symbols": ["NewServerConn"]}]`)),
+			pkg("github.com/gin-gonic/gin", goVulnerability(t, "GO-7", "github.com/gin-gonic/gin", "")),
+			pkg("stdlib",
+				goVulnerability(t, "GO-8", "stdlib", `[{"path": "net/http", "symbols": ["Get"]}]`),
+				goVulnerability(t, "GO-9", "stdlib", ""),
+			),
+		},
+	}}}
+
+	r := output.NewReporter(io.Discard, io.Discard, "table")
+	got := analyzeCalls(r, results)
+
+	verdict := func(name string, id string, verdict string, symbols ...string) models.VulnerabilityReachability {
+		return models.VulnerabilityReachability{
+			Source:  source,
+			Package: models.PackageInfo{Name: name, Version: "1.0.0", Ecosystem: "Go"},
+			ID:      id,
+			Verdict: verdict,
+			Symbols: symbols,
+		}
+	}
+
+	want := []models.VulnerabilityReachability{
+		verdict("golang.org/x/text", "GO-1", "imported"),
+		verdict("golang.org/x/text", "GO-2", "called", "golang.org/x/text/language.Make"),
+		verdict("golang.org/x/text", "GO-3", "not-imported"),
+		verdict("gopkg.in/yaml.v2", "GO-4", "called", "gopkg.in/yaml.v2"),
+		verdict("golang.org/x/net", "GO-5", "called", "golang.org/x/net/http2.Server.ServeConn"),
+		verdict("golang.org/x/crypto", "GO-6", "imported"),
+		verdict("github.com/gin-gonic/gin", "GO-7", "not-imported"),
+		verdict("stdlib", "GO-8", "called", "net/http.Get"),
+		verdict("stdlib", "GO-9", "unknown"),
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("analyzeCalls() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestAnalyzeCalls_OtherSources(t *testing.T) {
+	t.Parallel()
+
+	results := &models.VulnerabilityResults{Results: []models.PackageSource{{
+		Source: models.SourceInfo{Path: "/app/package-lock.json", Type: "lockfile"},
+		Packages: []models.PackageVulns{{
+			Package:         models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+			Vulnerabilities: []models.Vulnerability{{ID: "GHSA-35jh-r3h4-6jhm"}},
+		}},
+	}}}
+
+	r := output.NewReporter(io.Discard, io.Discard, "table")
+	if got := analyzeCalls(r, results); len(got) != 0 {
+		t.Errorf("expected only Go modules to be analyzed, got %v", got)
+	}
+}
@@ -0,0 +1,148 @@
+package osvscanner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/osv-scanner/pkg/config"
+	"github.com/google/osv-scanner/pkg/licenses"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/output"
+	"github.com/google/osv-scanner/pkg/registry"
+)
+
+// packageChecks are the checks of every package that is scanned, rather than
+// only the vulnerable ones, which inspect each batch of queries before it is
+// sent. Each optional check is nil unless it was asked for.
+type packageChecks struct {
+	verifier     *registryVerifier
+	outdated     *outdatedChecker
+	deprecations *deprecationChecker
+	scorecards   *scorecardFetcher
+	licenses     *licenseChecker
+	inventory    *inventoryCollector
+	detector     *typosquatDetector
+	usage        *ignoreUsage
+}
+
+// tracked returns the inspector with the time it takes being recorded as the
+// given phase of the profile
+func tracked(profile *Profile, phase string, inspect func(osv.BatchedQuery)) func(osv.BatchedQuery) {
+	return func(batch osv.BatchedQuery) {
+		done := profile.Track(phase)
+		inspect(batch)
+		done()
+	}
+}
+
+// licensePolicyFor returns a function returning the license policy of the
+// config of the source of a query, if it has one, which does not apply to
+// internal packages
+func licensePolicyFor(configManager *config.ConfigManager, r output.Reporter) func(query *osv.Query) (licenses.Policy, bool) {
+	return func(query *osv.Query) (licenses.Policy, bool) {
+		configToUse := configManager.Get(r, query.Source.Path)
+		if !configToUse.HasLicensePolicy() || configToUse.IsInternalPackage(query.Package.Name) {
+			return licenses.Policy{}, false
+		}
+		// the policy is validated when the config is loaded
+		policy, err := configToUse.LicensePolicy()
+
+		return policy, err == nil
+	}
+}
+
+// newPackageChecks creates the checks that the actions ask for, adding them to
+// the inspectors of the stream
+func newPackageChecks(ctx context.Context, actions ScannerActions, r output.Reporter, configManager *config.ConfigManager, stream *queryStream) *packageChecks {
+	checks := &packageChecks{usage: newIgnoreUsage()}
+	isInternal := isInternalPackage(configManager, r)
+
+	if actions.VerifyRegistry {
+		checks.verifier = newRegistryVerifier(ctx, registry.NewChecker())
+		checks.verifier.isInternal = isInternal
+		stream.inspectors = append(stream.inspectors, tracked(actions.Profile, "verifying registries", checks.verifier.inspect))
+	}
+
+	if actions.CheckOutdated {
+		checks.outdated = newOutdatedChecker(ctx, registry.NewChecker())
+		checks.outdated.isInternal = isInternal
+		stream.inspectors = append(stream.inspectors, tracked(actions.Profile, "checking for outdated packages", checks.outdated.inspect))
+	}
+
+	if actions.CheckDeprecated {
+		checks.deprecations = newDeprecationChecker(ctx, registry.NewChecker())
+		checks.deprecations.isInternal = isInternal
+		stream.inspectors = append(stream.inspectors, tracked(actions.Profile, "checking for deprecated packages", checks.deprecations.inspect))
+	}
+
+	if actions.CollectInventory || actions.InventoryOnly {
+		checks.inventory = newInventoryCollector()
+		stream.inspectors = append(stream.inspectors, checks.inventory.inspect)
+	}
+
+	if actions.FetchScorecards {
+		checks.scorecards = newScorecardFetcher(ctx, osv.FetchScorecard)
+		stream.inspectors = append(stream.inspectors, tracked(actions.Profile, "fetching scorecards", checks.scorecards.inspect))
+	}
+
+	if actions.CheckLicenses {
+		checks.licenses = newLicenseChecker(ctx, osv.FetchLicenses)
+		checks.licenses.policyFor = licensePolicyFor(configManager, r)
+		stream.inspectors = append(stream.inspectors, tracked(actions.Profile, "checking licenses", checks.licenses.inspect))
+	}
+
+	stream.inspectors = append(stream.inspectors, checks.usage.inspect)
+
+	if actions.DetectTyposquats {
+		checks.detector = newTyposquatDetector()
+		stream.inspectors = append(stream.inspectors, checks.detector.inspect)
+	}
+
+	if actions.InventoryOnly {
+		// the packages are only listed, so none of them are queried or checked
+		stream.inspectors = []func(osv.BatchedQuery){checks.inventory.inspect}
+		stream.send = func(batch osv.BatchedQuery) (*osv.BatchedResponse, error) {
+			return &osv.BatchedResponse{Results: make([]osv.MinimalResponse, len(batch.Queries))}, nil
+		}
+	}
+
+	return checks
+}
+
+// addResults adds the findings of the checks to the results, reporting how
+// many packages could not be checked by each of them
+func (c *packageChecks) addResults(r output.Reporter, results *models.VulnerabilityResults) {
+	if c.verifier != nil {
+		c.verifier.reportFailures(r, "Failed to check %d packages against their registries: %v")
+		results.RegistryIssues = c.verifier.findings()
+	}
+	if c.outdated != nil {
+		c.outdated.reportFailures(r, "Failed to look up the latest versions of %d packages: %v")
+		results.Outdated = c.outdated.findings()
+	}
+	if c.deprecations != nil {
+		c.deprecations.reportFailures(r, "Failed to check if %d packages are deprecated: %v")
+		results.Deprecated = c.deprecations.findings()
+	}
+	if c.scorecards != nil {
+		c.scorecards.reportFailures(r, "Failed to fetch the scorecards of %d packages: %v")
+		results.Scorecards = c.scorecards.findings()
+	}
+	if c.licenses != nil {
+		c.licenses.reportFailures(r, "Failed to look up the licenses of %d packages: %v")
+		if c.licenses.unknown > 0 {
+			r.PrintText(fmt.Sprintf("The licenses of %d packages are not known, so could not be checked\n", c.licenses.unknown))
+		}
+		results.LicenseViolations = c.licenses.findings()
+	}
+	if c.inventory != nil {
+		results.Inventory = c.inventory.sortedPackages()
+	}
+
+	results.SuspiciousPackages = maliciousPackages(*results)
+	if c.detector != nil {
+		results.SuspiciousPackages = append(results.SuspiciousPackages, c.detector.typosquats...)
+	}
+	sortSuspiciousPackages(results.SuspiciousPackages)
+}
@@ -0,0 +1,209 @@
+package osvscanner
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/semantic"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+var (
+	composerHyphenRangeRegexp = regexp.MustCompile(`^\s*(\S+)\s+-\s+(\S+)\s*$`)
+	composerConstraintRegexp  = regexp.MustCompile(`^(\^|~|>=|<=|!=|==|<>|>|<|=)?\s*v?([^@\s]*)(@\w+)?$`)
+)
+
+// versionComponents returns the numeric components at the start of a
+// version, such as [7, 4] for "7.4.*"
+func versionComponents(version string) []int {
+	var components []int
+	for _, part := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			break
+		}
+		components = append(components, n)
+	}
+
+	return components
+}
+
+// padVersion adds zero components to a version so that it has at least three,
+// as Composer treats "8" and "8.0.0" as the same version while a comparison
+// of them as PHP versions does not
+func padVersion(version string) string {
+	for n := len(strings.Split(version, ".")); n < 3; n++ {
+		version += ".0"
+	}
+
+	return version
+}
+
+func joinComponents(components []int) string {
+	parts := make([]string, 0, len(components))
+	for _, c := range components {
+		parts = append(parts, strconv.Itoa(c))
+	}
+
+	return padVersion(strings.Join(parts, "."))
+}
+
+// nextComponents returns the components that come after every version that
+// starts with the first n components, such as [7, 5] for n = 2 of [7, 4, 1]
+func nextComponents(components []int, n int) []int {
+	next := append([]int{}, components[:n]...)
+	next[n-1]++
+
+	return next
+}
+
+// satisfiesComposerTerm reports if the version satisfies a single term of a
+// Composer constraint, such as ">=7.2.5", "^8.0" or "7.4.*", along with if
+// the term could be understood
+func satisfiesComposerTerm(version semantic.Version, term string) (bool, bool) {
+	matched := composerConstraintRegexp.FindStringSubmatch(term)
+	if matched == nil {
+		return false, false
+	}
+
+	operator, bound := matched[1], matched[2]
+	if bound == "*" || bound == "" {
+		return true, true
+	}
+
+	atLeast := func(v string) bool { return version.CompareStr(v) >= 0 }
+	below := func(v string) bool { return version.CompareStr(v) < 0 }
+
+	components := versionComponents(bound)
+	if len(components) == 0 {
+		return false, false
+	}
+
+	if strings.HasSuffix(bound, ".*") || strings.HasSuffix(bound, ".x") {
+		return atLeast(joinComponents(components)) && below(joinComponents(nextComponents(components, len(components)))), true
+	}
+
+	switch operator {
+	case "^":
+		// the first non-zero component cannot change
+		n := 1
+		for n < len(components) && components[n-1] == 0 {
+			n++
+		}
+
+		return atLeast(padVersion(bound)) && below(joinComponents(nextComponents(components, n))), true
+	case "~":
+		// the last given component can change, or the major if only it is given
+		n := len(components) - 1
+		if n == 0 {
+			n = 1
+		}
+
+		return atLeast(padVersion(bound)) && below(joinComponents(nextComponents(components, n))), true
+	case ">=":
+		return atLeast(padVersion(bound)), true
+	case ">":
+		return version.CompareStr(padVersion(bound)) > 0, true
+	case "<=":
+		return version.CompareStr(padVersion(bound)) <= 0, true
+	case "<":
+		return below(padVersion(bound)), true
+	case "!=", "<>":
+		return version.CompareStr(padVersion(bound)) != 0, true
+	}
+
+	return version.CompareStr(padVersion(bound)) == 0, true
+}
+
+// satisfiesComposerConstraint reports if the version satisfies a Composer
+// constraint such as "^7.2.5 || ^8.0", which is assumed if the constraint
+// cannot be understood so that it is not reported as unmet by mistake
+func satisfiesComposerConstraint(version string, constraint string) bool {
+	v := semantic.MustParse(padVersion(version), lockfile.ComposerEcosystem)
+
+	understood := true
+	for _, alternative := range strings.Split(strings.ReplaceAll(constraint, "||", "|"), "|") {
+		var terms []string
+		if matched := composerHyphenRangeRegexp.FindStringSubmatch(alternative); matched != nil {
+			// a partial upper bound includes every version that starts with it
+			upper := "<=" + matched[2]
+			if n := len(versionComponents(matched[2])); n > 0 && n < 3 {
+				upper = "<" + joinComponents(nextComponents(versionComponents(matched[2]), n))
+			}
+			terms = []string{">=" + matched[1], upper}
+		} else {
+			// operators can be separated from their versions by spaces, which
+			// also separate the terms that must all be satisfied
+			fields := strings.FieldsFunc(alternative, func(r rune) bool { return r == ',' || r == ' ' })
+			for i := 0; i < len(fields); i++ {
+				if strings.Trim(fields[i], "^~<>=!") == "" && i+1 < len(fields) {
+					fields[i+1] = fields[i] + fields[i+1]
+					continue
+				}
+				terms = append(terms, fields[i])
+			}
+		}
+
+		satisfied := true
+		for _, term := range terms {
+			ok, known := satisfiesComposerTerm(v, term)
+			if !known {
+				understood = false
+				satisfied = false
+
+				break
+			}
+			if !ok {
+				satisfied = false
+
+				break
+			}
+		}
+
+		if satisfied {
+			return true
+		}
+	}
+
+	return !understood
+}
+
+// unmetPlatformRequirements returns the platform requirements of a package
+// that the deployed PHP installation does not meet, which are only checked
+// for the parts of it that are known
+func (s packageScope) unmetPlatformRequirements(pkg lockfile.PackageDetails) []string {
+	if pkg.Metadata == nil || (s.phpVersion == "" && len(s.phpExtensions) == 0) {
+		return nil
+	}
+
+	var unmet []string
+	for name, constraint := range pkg.Metadata.PlatformRequirements {
+		switch {
+		case name == "php":
+			if s.phpVersion != "" && !satisfiesComposerConstraint(s.phpVersion, constraint) {
+				unmet = append(unmet, name+" "+constraint)
+			}
+		case strings.HasPrefix(name, "ext-"):
+			if len(s.phpExtensions) > 0 && !s.hasPHPExtension(strings.TrimPrefix(name, "ext-")) {
+				unmet = append(unmet, name)
+			}
+		}
+	}
+	sort.Strings(unmet)
+
+	return unmet
+}
+
+// hasPHPExtension reports if the extension is installed, allowing it to have
+// been given with or without the "ext-" prefix
+func (s packageScope) hasPHPExtension(extension string) bool {
+	for _, installed := range s.phpExtensions {
+		if strings.EqualFold(strings.TrimPrefix(installed, "ext-"), extension) {
+			return true
+		}
+	}
+
+	return false
+}
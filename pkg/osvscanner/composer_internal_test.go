@@ -0,0 +1,111 @@
+package osvscanner
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func Test_satisfiesComposerConstraint(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		version    string
+		constraint string
+		want       bool
+	}{
+		{version: "8.1.2", constraint: "*", want: true},
+		{version: "8.1.2", constraint: ">=7.3", want: true},
+		{version: "7.2.0", constraint: ">=7.3", want: false},
+		{version: "8.0", constraint: "^8.0", want: true},
+		{version: "8", constraint: "^8.0", want: true},
+		{version: "9.0.0", constraint: "^8.0", want: false},
+		{version: "7.4.33", constraint: "^7.2.5 || ^8.0", want: true},
+		{version: "7.1.0", constraint: "^7.2.5 || ^8.0", want: false},
+		{version: "8.2.0", constraint: "^7.2.5|^8.0", want: true},
+		{version: "0.4.0", constraint: "^0.3", want: false},
+		{version: "7.4.1", constraint: "~7.4", want: true},
+		{version: "7.5.0", constraint: "~7.4.1", want: false},
+		{version: "7.4.9", constraint: "7.4.*", want: true},
+		{version: "7.5.0", constraint: "7.4.*", want: false},
+		{version: "7.4.0", constraint: ">=7.1 <8.0", want: true},
+		{version: "8.0.0", constraint: ">=7.1,<8.0", want: false},
+		{version: "8.0.0", constraint: ">= 7.1, < 8.0", want: false},
+		{version: "7.4.0", constraint: "7.1 - 7.4", want: true},
+		{version: "7.5.0", constraint: "7.1 - 7.4", want: false},
+		{version: "8.1.0", constraint: "8.1.0", want: true},
+		{version: "8.1.0", constraint: "!=8.1.0", want: false},
+		{version: "8.1.0", constraint: ">=8.0@dev", want: true},
+		// constraints that cannot be understood are assumed to be met
+		{version: "8.1.0", constraint: "dev-main", want: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.version+" "+tt.constraint, func(t *testing.T) {
+			t.Parallel()
+
+			if got := satisfiesComposerConstraint(tt.version, tt.constraint); got != tt.want {
+				t.Errorf("satisfiesComposerConstraint(%q, %q) = %v, want %v", tt.version, tt.constraint, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_packageScope_unmetPlatformRequirements(t *testing.T) {
+	t.Parallel()
+
+	pkg := lockfile.PackageDetails{
+		Name:      "phpunit/phpunit",
+		Version:   "9.5.27",
+		Ecosystem: lockfile.ComposerEcosystem,
+		Metadata: &lockfile.PackageMetadata{
+			PlatformRequirements: map[string]string{
+				"php":          ">=7.3",
+				"ext-dom":      "*",
+				"ext-mbstring": "*",
+				"lib-libxml":   "*",
+			},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		scope packageScope
+		want  []string
+	}{
+		{
+			name:  "nothing known about the platform",
+			scope: packageScope{},
+			want:  nil,
+		},
+		{
+			name:  "version that is met",
+			scope: packageScope{phpVersion: "8.1"},
+			want:  nil,
+		},
+		{
+			name:  "version that is not met",
+			scope: packageScope{phpVersion: "7.2.34"},
+			want:  []string{"php >=7.3"},
+		},
+		{
+			name:  "missing extensions",
+			scope: packageScope{phpVersion: "7.2.34", phpExtensions: []string{"ext-DOM", "json"}},
+			want:  []string{"ext-mbstring", "php >=7.3"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tt.scope.unmetPlatformRequirements(pkg)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unmetPlatformRequirements() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
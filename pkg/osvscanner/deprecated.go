@@ -0,0 +1,93 @@
+package osvscanner
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/registry"
+)
+
+// deprecationChecker looks up if the packages of lockfiles and SBOMs have
+// been deprecated, yanked or are unmaintained as they are scanned, looking up
+// each version of a package only once
+type deprecationChecker struct {
+	lookupTracker[registry.Package, registry.Deprecation, models.DeprecatedPackage]
+	// isInternal reports if a package is an internal package, which is not
+	// published on its public registry to be looked up
+	isInternal func(query *osv.Query) bool
+}
+
+func newDeprecationChecker(ctx context.Context, checker *registry.Checker) *deprecationChecker {
+	deprecation := func(pkg registry.Package) (registry.Deprecation, error) {
+		return checker.Deprecation(ctx, pkg)
+	}
+
+	return &deprecationChecker{
+		lookupTracker: newLookupTracker(deprecation, sortDeprecatedPackages),
+	}
+}
+
+// inspect looks up the deprecation of each package of a lockfile or SBOM in
+// the batch
+func (c *deprecationChecker) inspect(batch osv.BatchedQuery) {
+	queries := make(chan *osv.Query)
+	var wg sync.WaitGroup
+
+	for i := 0; i < registryWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for query := range queries {
+				c.inspectQuery(query)
+			}
+		}()
+	}
+
+	for _, query := range batch.Queries {
+		if (query.Source.Type != "lockfile" && query.Source.Type != "sbom") || query.Package.Name == "" || query.Version == "" || !registry.Supported(query.Package.Ecosystem) {
+			continue
+		}
+		if c.isInternal != nil && c.isInternal(query) || !c.firstSeen(query.Source, queryPackage(query)) {
+			continue
+		}
+
+		queries <- query
+	}
+	close(queries)
+	wg.Wait()
+}
+
+func (c *deprecationChecker) inspectQuery(query *osv.Query) {
+	deprecation, err := c.get(registry.Package{
+		Name:      query.Package.Name,
+		Version:   query.Version,
+		Ecosystem: query.Package.Ecosystem,
+	})
+	if err != nil || deprecation.Kind == "" {
+		return
+	}
+
+	c.add(models.DeprecatedPackage{
+		Source:  query.Source,
+		Package: queryPackage(query),
+		Kind:    string(deprecation.Kind),
+		Message: deprecation.Message,
+	})
+}
+
+func sortDeprecatedPackages(deprecated []models.DeprecatedPackage) {
+	sort.SliceStable(deprecated, func(a, b int) bool {
+		da, db := deprecated[a], deprecated[b]
+		if da.Source.Path != db.Source.Path {
+			return da.Source.Path < db.Source.Path
+		}
+		if da.Package.Name != db.Package.Name {
+			return da.Package.Name < db.Package.Name
+		}
+
+		return da.Package.Version < db.Package.Version
+	})
+}
@@ -0,0 +1,70 @@
+package osvscanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/registry"
+)
+
+func Test_deprecationChecker(t *testing.T) {
+	t.Parallel()
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		switch r.URL.Path {
+		case "/request/2.88.2":
+			_, _ = w.Write([]byte(`{"deprecated": "request has been deprecated"}`))
+		case "/wrappy/1.0.2":
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	checker := newDeprecationChecker(context.Background(), &registry.Checker{
+		Client:   server.Client(),
+		BaseURLs: map[string]string{"npm": server.URL},
+	})
+	checker.isInternal = func(query *osv.Query) bool {
+		return query.Package.Name == "@acme/utils"
+	}
+
+	lockfileA := models.SourceInfo{Path: "/a/package-lock.json", Type: "lockfile"}
+	lockfileB := models.SourceInfo{Path: "/b/package-lock.json", Type: "lockfile"}
+	query := func(source models.SourceInfo, name string, version string, ecosystem string) *osv.Query {
+		return &osv.Query{Source: source, Version: version, Package: osv.Package{Name: name, Ecosystem: ecosystem}}
+	}
+
+	checker.inspect(osv.BatchedQuery{Queries: []*osv.Query{
+		query(lockfileB, "request", "2.88.2", "npm"),
+		query(lockfileA, "request", "2.88.2", "npm"),
+		query(lockfileA, "wrappy", "1.0.2", "npm"),
+		query(lockfileA, "@acme/utils", "0.1.0", "npm"),
+		query(lockfileA, "some/package", "1.0.0", "Packagist"),
+	}})
+
+	request := models.PackageInfo{Name: "request", Version: "2.88.2", Ecosystem: "npm"}
+	want := []models.DeprecatedPackage{
+		{Source: lockfileA, Package: request, Kind: "deprecated", Message: "request has been deprecated"},
+		{Source: lockfileB, Package: request, Kind: "deprecated", Message: "request has been deprecated"},
+	}
+
+	if diff := cmp.Diff(want, checker.findings()); diff != "" {
+		t.Errorf("findings() mismatch (-want +got):\n%s", diff)
+	}
+
+	// each version of a package is only looked up once
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("expected 2 requests to the registry, got %d", got)
+	}
+}
@@ -0,0 +1,155 @@
+package osvscanner
+
+import (
+	"context"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/config"
+	"github.com/google/osv-scanner/pkg/github"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+// dismissals are the alerts that have been dismissed on GitHub, which are
+// ignored for the packages and manifests they were raised for
+type dismissals []github.Dismissal
+
+// loadGitHubDismissals fetches the dismissed alerts of the given "owner/repo"
+func loadGitHubDismissals(ctx context.Context, repository string) (dismissals, error) {
+	owner, repo, err := github.SplitRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	return github.NewClient().DismissedAlerts(ctx, owner, repo)
+}
+
+// repositoryPath returns the slash-separated path of the file relative to the
+// root of the git repository it is in, or the path itself if it is not in one
+func repositoryPath(file string) string {
+	root, ok := repositoryRoot(filepath.Dir(file))
+	if !ok {
+		return filepath.ToSlash(file)
+	}
+
+	rel, err := filepath.Rel(root, file)
+	if err != nil {
+		return filepath.ToSlash(file)
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// inManifestDir reports if the file is in the same directory as the manifest,
+// as alerts can be raised for the manifest of a lockfile rather than the
+// lockfile itself, such as the package.json of a package-lock.json. Files
+// outside of a repository are matched by the end of their directory.
+func inManifestDir(file string, manifestPath string) bool {
+	manifestDir := path.Dir(manifestPath)
+	dir := path.Dir(file)
+
+	return dir == manifestDir || path.IsAbs(dir) && manifestDir != "." && strings.HasSuffix(dir, "/"+manifestDir)
+}
+
+// appliesTo reports if the dismissal applies to the package of the query,
+// which is found in the file at the given path within its repository
+func appliesTo(dismissal github.Dismissal, query *osv.Query, file string) bool {
+	if dismissal.Package != "" {
+		name := query.Package.Name
+		if name == "" {
+			name = query.PackageName
+		}
+		if !strings.EqualFold(name, dismissal.Package) {
+			return false
+		}
+		if dismissal.Ecosystem != "" && query.Package.Ecosystem != "" && !strings.EqualFold(query.Package.Ecosystem, dismissal.Ecosystem) {
+			return false
+		}
+	}
+
+	if dismissal.ManifestPath != "" {
+		return file != "" && inManifestDir(file, dismissal.ManifestPath)
+	}
+
+	return true
+}
+
+// ignoresFor returns a config that ignores the vulnerabilities of the
+// dismissals that apply to the package of the query, by any of their ids
+func (d dismissals) ignoresFor(query *osv.Query) config.Config {
+	var ignores config.Config
+	if len(d) == 0 {
+		return ignores
+	}
+
+	file := declaringFile(query)
+	if file != "" {
+		file = repositoryPath(file)
+	}
+
+	for _, dismissal := range d {
+		if !appliesTo(dismissal, query, file) {
+			continue
+		}
+		for _, id := range dismissal.IDs {
+			ignores.IgnoredVulns = append(ignores.IgnoredVulns, config.IgnoreEntry{ID: id, Reason: dismissal.Reason})
+		}
+	}
+
+	return ignores
+}
+
+// dropDismissedAliases removes the vulnerabilities that were not dismissed by
+// their own id, but by one of their aliases, which are only known once the
+// vulnerabilities have been hydrated, returning how many were removed
+func dropDismissedAliases(r output.Reporter, query osv.BatchedQuery, resp *osv.HydratedBatchedResponse, d dismissals) int {
+	if len(d) == 0 {
+		return 0
+	}
+
+	hidden := map[string]config.IgnoreEntry{}
+	dropped := 0
+
+	for i, result := range resp.Results {
+		ignores := d.ignoresFor(query.Queries[i])
+		if len(ignores.IgnoredVulns) == 0 {
+			continue
+		}
+
+		var kept []models.Vulnerability
+		for _, vuln := range result.Vulns {
+			ignored := false
+			for _, alias := range vuln.Aliases {
+				if ignore, entry := ignores.ShouldIgnore(alias); ignore {
+					hidden[vuln.ID] = entry
+					ignored = true
+
+					break
+				}
+			}
+
+			if ignored {
+				dropped++
+				continue
+			}
+			kept = append(kept, vuln)
+		}
+		resp.Results[i].Vulns = kept
+	}
+
+	ids := make([]string, 0, len(hidden))
+	for id := range hidden {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		r.PrintText(output.Localize(r, "%s has been filtered out because: %s", id, hidden[id].Reason) + "\n")
+	}
+
+	return dropped
+}
@@ -0,0 +1,114 @@
+package osvscanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+func TestDismissals_IgnoresFor(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	d := dismissals{
+		{
+			IDs:          []string{"GHSA-1", "CVE-1"},
+			Reason:       "dismissed on GitHub as tolerable_risk",
+			Ecosystem:    "npm",
+			Package:      "minimist",
+			ManifestPath: "services/api/package.json",
+		},
+		{IDs: []string{"GHSA-2"}, Reason: "dismissed on GitHub as inaccurate"},
+	}
+
+	lockfile := func(path string, name string, ecosystem string) *osv.Query {
+		return &osv.Query{
+			Package: osv.Package{Name: name, Ecosystem: ecosystem},
+			Source:  models.SourceInfo{Path: filepath.Join(dir, path), Type: "lockfile"},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query *osv.Query
+		want  []string
+	}{
+		{
+			name:  "package in the manifest's directory",
+			query: lockfile("services/api/package-lock.json", "Minimist", "npm"),
+			want:  []string{"GHSA-1", "CVE-1", "GHSA-2"},
+		},
+		{
+			name:  "package in another manifest",
+			query: lockfile("services/web/package-lock.json", "minimist", "npm"),
+			want:  []string{"GHSA-2"},
+		},
+		{
+			name:  "another package in the manifest",
+			query: lockfile("services/api/package-lock.json", "lodash", "npm"),
+			want:  []string{"GHSA-2"},
+		},
+		{
+			name:  "package of another ecosystem",
+			query: lockfile("services/api/package-lock.json", "minimist", "PyPI"),
+			want:  []string{"GHSA-2"},
+		},
+		{
+			name:  "package without a declaring file",
+			query: &osv.Query{Package: osv.Package{Name: "minimist", Ecosystem: "npm"}, Source: models.SourceInfo{Path: "node:18", Type: "docker"}},
+			want:  []string{"GHSA-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+			for _, entry := range d.ignoresFor(tt.query).IgnoredVulns {
+				got = append(got, entry.ID)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ignoresFor() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDropDismissedAliases(t *testing.T) {
+	t.Parallel()
+
+	d := dismissals{{IDs: []string{"GHSA-1", "CVE-1"}, Reason: "dismissed on GitHub as inaccurate", Package: "minimist"}}
+
+	query := osv.BatchedQuery{Queries: []*osv.Query{
+		{Package: osv.Package{Name: "minimist", Ecosystem: "npm"}},
+		{Package: osv.Package{Name: "lodash", Ecosystem: "npm"}},
+	}}
+	resp := &osv.HydratedBatchedResponse{Results: []osv.Response{
+		{Vulns: []models.Vulnerability{{ID: "OSV-1", Aliases: []string{"CVE-1"}}, {ID: "OSV-2", Aliases: []string{"CVE-2"}}}},
+		{Vulns: []models.Vulnerability{{ID: "OSV-1", Aliases: []string{"CVE-1"}}}},
+	}}
+
+	if n := dropDismissedAliases(output.NewVoidReporter(), query, resp, d); n != 1 {
+		t.Errorf("expected 1 vulnerability to be dropped, got %d", n)
+	}
+
+	want := &osv.HydratedBatchedResponse{Results: []osv.Response{
+		{Vulns: []models.Vulnerability{{ID: "OSV-2", Aliases: []string{"CVE-2"}}}},
+		{Vulns: []models.Vulnerability{{ID: "OSV-1", Aliases: []string{"CVE-1"}}}},
+	}}
+	if diff := cmp.Diff(want, resp); diff != "" {
+		t.Errorf("dropDismissedAliases() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,122 @@
+package osvscanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/host"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+// apkDatabasePath is the path within the filesystem of an Alpine image of the
+// database of the packages that apk has installed
+const apkDatabasePath = "lib/apk/db/installed"
+
+// dockerImageFiles are the files that are copied out of images to find out
+// what they are based on
+var dockerImageFiles = []string{"etc/os-release", "usr/lib/os-release", apkDatabasePath}
+
+// copyFromDockerImage copies the files at the given paths within the image
+// into a temporary directory laid out like the root of its filesystem,
+// skipping those that the image does not have. The image is never run, so
+// that images without a shell or the tools of their package manager can be
+// read as well.
+func copyFromDockerImage(ctx context.Context, image string, paths []string) (string, error) {
+	// the entrypoint is never run, but images without a command need one to
+	// create a container from them
+	out, err := exec.CommandContext(ctx, "docker", "create", "--entrypoint", "/bin/true", image).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("failed to create container from %s: %s", image, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+
+		return "", fmt.Errorf("failed to create container from %s: %w", image, err)
+	}
+	container := strings.TrimSpace(string(out))
+	// the container is removed even once ctx is done so it is not left behind
+	//nolint:errcheck // the container was never started, so there is nothing to stop
+	defer exec.Command("docker", "rm", container).Run()
+
+	root, err := os.MkdirTemp("", "osv-scanner-docker-")
+	if err != nil {
+		return "", err
+	}
+
+	for _, path := range paths {
+		dest := filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			os.RemoveAll(root)
+			return "", err
+		}
+
+		// images that do not have the file are told apart by it not being
+		// copied, so failures are not errors
+		_ = exec.CommandContext(ctx, "docker", "cp", "-L", container+":/"+path, dest).Run()
+	}
+
+	if err := ctx.Err(); err != nil {
+		os.RemoveAll(root)
+		return "", err
+	}
+
+	return root, nil
+}
+
+// scanDocker adds the OS packages installed in the docker image to `query`,
+// reading the apk database of Alpine based images and otherwise asking dpkg
+// for the packages of what is assumed to be a Debian based image
+func scanDocker(ctx context.Context, r output.Reporter, query *osv.BatchedQuery, image string) error {
+	root, err := copyFromDockerImage(ctx, image, dockerImageFiles)
+	if err != nil {
+		r.PrintError(fmt.Sprintf("Failed to read docker image: %s\n", err))
+		return err
+	}
+	defer os.RemoveAll(root)
+
+	if _, err := os.Stat(filepath.Join(root, apkDatabasePath)); err != nil {
+		return scanDebianDocker(ctx, r, query, image)
+	}
+
+	return scanAlpineDocker(r, query, image, root)
+}
+
+// scanAlpineDocker adds the packages in the apk database of the image, which
+// has been copied to root, checking them against the advisories for its
+// release of Alpine if it can be told from its os-release file
+func scanAlpineDocker(r output.Reporter, query *osv.BatchedQuery, image string, root string) error {
+	ecosystem := string(lockfile.AlpineEcosystem)
+	if release, err := host.ReadOSRelease(root); err == nil && release.ID == "alpine" {
+		if releaseEcosystem, err := release.Ecosystem(); err == nil {
+			ecosystem = releaseEcosystem
+		}
+	}
+
+	packages, err := lockfile.ParseApkInstalled(filepath.Join(root, apkDatabasePath))
+	if err != nil {
+		r.PrintError(fmt.Sprintf("Failed to read the apk database of %s: %s\n", image, err))
+		return err
+	}
+
+	for _, pkg := range packages {
+		pkg.Ecosystem = lockfile.Ecosystem(ecosystem)
+
+		pkgQuery := osv.MakePkgRequest(pkg)
+		pkgQuery.Source = models.SourceInfo{
+			Path: image,
+			Type: "docker",
+		}
+		query.Queries = append(query.Queries, pkgQuery)
+	}
+	r.PrintText(fmt.Sprintf("Scanned Alpine docker image with %d packages\n", len(packages)))
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package osvscanner
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+func TestScanAlpineDocker(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		osRelease string
+		want      string
+	}{
+		{name: "release", osRelease: "ID=alpine\nVERSION_ID=3.18.4\n", want: "Alpine:v3.18"},
+		{name: "no os-release", want: "Alpine"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			root := t.TempDir()
+			files := map[string]string{
+				apkDatabasePath: "P:musl\nV:1.2.4-r1\n\nP:busybox\nV:1.36.1-r2\n",
+			}
+			if tt.osRelease != "" {
+				files["etc/os-release"] = tt.osRelease
+			}
+			writeFiles(t, root, files)
+
+			var query osv.BatchedQuery
+			if err := scanAlpineDocker(output.NewVoidReporter(), &query, "alpine:3.18", root); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			type pkg struct{ Name, Version, Ecosystem, Path, Type string }
+			var got []pkg
+			for _, q := range query.Queries {
+				got = append(got, pkg{q.Package.Name, q.Version, q.Package.Ecosystem, q.Source.Path, q.Source.Type})
+			}
+
+			want := []pkg{
+				{"musl", "1.2.4-r1", tt.want, "alpine:3.18", "docker"},
+				{"busybox", "1.36.1-r2", tt.want, "alpine:3.18", "docker"},
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("scanAlpineDocker() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package osvscanner
+
+import (
+	"errors"
+)
+
+// ErrLockfileParse is matched (via errors.Is) by any LockfileParseError
+var ErrLockfileParse = errors.New("failed to parse lockfile")
+
+// ErrSBOMParse is matched (via errors.Is) by any SBOMParseError
+var ErrSBOMParse = errors.New("failed to parse sbom")
+
+// ErrConfigInvalid is matched (via errors.Is) by any ConfigError
+var ErrConfigInvalid = errors.New("invalid config")
+
+// ErrAPIUnavailable is matched (via errors.Is) by any APIError
+var ErrAPIUnavailable = errors.New("osv api unavailable")
+
+// ErrIncompleteScan is returned in strict mode when some inputs were skipped
+var ErrIncompleteScan = errors.New("scan was incomplete")
+
+// ErrRegistryMismatch is returned when registries are checked and some of the
+// packages do not exist on their registry, have different content there, or
+// are internal packages whose names are published there
+var ErrRegistryMismatch = errors.New("packages do not match their registry")
+
+// ErrLicenseViolation is returned when licenses are checked and some of the
+// packages are under licenses that the license policy of their config does not
+// allow
+var ErrLicenseViolation = errors.New("packages violate the license policy")
+
+// ErrLimitExceeded is matched (via errors.Is) when an input is skipped for
+// exceeding one of the configured limits
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// LockfileParseError is returned when a lockfile could not be read or parsed
+type LockfileParseError struct {
+	Path    string
+	ParseAs string
+	Err     error
+}
+
+func (e *LockfileParseError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *LockfileParseError) Unwrap() error {
+	return e.Err
+}
+
+func (e *LockfileParseError) Is(target error) bool {
+	return target == ErrLockfileParse
+}
+
+// SBOMParseError is returned when an SBOM could not be read or parsed
+type SBOMParseError struct {
+	Path string
+	Err  error
+}
+
+func (e *SBOMParseError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *SBOMParseError) Unwrap() error {
+	return e.Err
+}
+
+func (e *SBOMParseError) Is(target error) bool {
+	return target == ErrSBOMParse
+}
+
+// ConfigError is returned when a config file could not be loaded
+type ConfigError struct {
+	Path string
+	Err  error
+}
+
+func (e *ConfigError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ConfigError) Is(target error) bool {
+	return target == ErrConfigInvalid
+}
+
+// APIError is returned when querying or hydrating against the OSV API fails
+type APIError struct {
+	Err error
+}
+
+func (e *APIError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+func (e *APIError) Is(target error) bool {
+	return target == ErrAPIUnavailable
+}
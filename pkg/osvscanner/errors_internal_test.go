@@ -0,0 +1,68 @@
+package osvscanner
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDoScan_LockfileParseError(t *testing.T) {
+	t.Parallel()
+
+	_, err := DoScan(ScannerActions{
+		LockfilePaths: []string{"composer.lock:../lockfile/fixtures/composer/not-json.txt"},
+	}, nil)
+
+	if !errors.Is(err, ErrLockfileParse) {
+		t.Fatalf("expected error to be ErrLockfileParse, got %v", err)
+	}
+
+	var parseErr *LockfileParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected error to be a LockfileParseError, got %T", err)
+	}
+
+	if parseErr.ParseAs != "composer.lock" {
+		t.Errorf("expected ParseAs to be composer.lock, got %s", parseErr.ParseAs)
+	}
+
+	if errors.Is(err, ErrAPIUnavailable) {
+		t.Errorf("did not expect error to be ErrAPIUnavailable")
+	}
+}
+
+func TestDoScan_ConfigError(t *testing.T) {
+	t.Parallel()
+
+	_, err := DoScan(ScannerActions{
+		ConfigOverridePath: "../../fixtures/does-not-exist.toml",
+	}, nil)
+
+	if !errors.Is(err, ErrConfigInvalid) {
+		t.Fatalf("expected error to be ErrConfigInvalid, got %v", err)
+	}
+
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) {
+		t.Fatalf("expected error to be a ConfigError, got %T", err)
+	}
+
+	if configErr.Path != "../../fixtures/does-not-exist.toml" {
+		t.Errorf("unexpected path %s", configErr.Path)
+	}
+}
+
+func TestDoScan_MissingArtifact(t *testing.T) {
+	t.Parallel()
+
+	_, err := DoScan(ScannerActions{
+		ArtifactPaths: []string{"../../fixtures/does-not-exist.jar"},
+	}, nil)
+
+	if err == nil {
+		t.Fatalf("expected an error for a missing artifact")
+	}
+
+	if errors.Is(err, ErrAPIUnavailable) {
+		t.Errorf("did not expect error to be ErrAPIUnavailable, got %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package osvscanner
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	formatconfig "github.com/go-git/go-git/v5/plumbing/format/config"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+// readGitIgnorePatterns parses the patterns of an ignore file, which apply
+// to the paths within the domain
+func readGitIgnorePatterns(reader io.Reader, domain []string) ([]gitignore.Pattern, error) {
+	var patterns []gitignore.Pattern
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		patterns = append(patterns, gitignore.ParsePattern(line, domain))
+	}
+
+	return patterns, scanner.Err()
+}
+
+// readGitIgnoreFile parses the patterns of the ignore file at path, if it
+// exists
+func readGitIgnoreFile(path string, domain []string) ([]gitignore.Pattern, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+	defer f.Close()
+
+	return readGitIgnorePatterns(f, domain)
+}
+
+// userGitConfigPaths returns the system and global config files of git, in
+// the order that git reads them
+func userGitConfigPaths() []string {
+	paths := []string{"/etc/gitconfig"}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = ""
+	}
+
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		paths = append(paths, filepath.Join(xdg, "git", "config"))
+	} else if home != "" {
+		paths = append(paths, filepath.Join(home, ".config", "git", "config"))
+	}
+	if home != "" {
+		paths = append(paths, filepath.Join(home, ".gitconfig"))
+	}
+
+	return paths
+}
+
+// defaultExcludesFile returns where git looks for the user's global ignore
+// file when core.excludesFile is not set
+func defaultExcludesFile() string {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "git", "ignore")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".config", "git", "ignore")
+	}
+
+	return ""
+}
+
+// readGitConfig parses the git config file at path, returning nil if it does
+// not exist or cannot be parsed, as git is not needed to scan
+func readGitConfig(path string) *formatconfig.Config {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	raw := formatconfig.New()
+	if err := formatconfig.NewDecoder(f).Decode(raw); err != nil {
+		return nil
+	}
+
+	return raw
+}
+
+// excludesFile returns the ignore file set by the last core.excludesFile of
+// the configs, which are in the order that git reads them, or fallback if
+// none of them set it
+func excludesFile(configs []*formatconfig.Config, fallback string) string {
+	path := fallback
+	for _, raw := range configs {
+		if raw == nil || !raw.HasSection("core") {
+			continue
+		}
+		if value := raw.Section("core").Options.Get("excludesfile"); value != "" {
+			path = value
+		}
+	}
+
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+
+	return path
+}
+
+// readRepositoryExcludes parses the patterns that apply to the worktree of
+// the repository at root other than those of its .gitignore files, being its
+// user's global ignore file and the info/exclude file of the repository, in
+// increasing order of priority
+func readRepositoryExcludes(repo *git.Repository, root string) ([]gitignore.Pattern, error) {
+	domain := []string{"."}
+
+	paths := userGitConfigPaths()
+	configs := make([]*formatconfig.Config, 0, len(paths)+1)
+	for _, path := range paths {
+		configs = append(configs, readGitConfig(path))
+	}
+	if cfg, err := repo.Config(); err == nil {
+		configs = append(configs, cfg.Raw)
+	}
+
+	var patterns []gitignore.Pattern
+	if path := excludesFile(configs, defaultExcludesFile()); path != "" {
+		global, err := readGitIgnoreFile(path, domain)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, global...)
+	}
+
+	// the info/exclude file of repositories whose .git is a directory of
+	// their worktree is read along with their .gitignore files, while that
+	// of linked worktrees and submodules is in the git directory elsewhere
+	if info, err := os.Stat(filepath.Join(root, ".git")); err == nil && info.IsDir() {
+		return patterns, nil
+	}
+
+	storage, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return patterns, nil
+	}
+	f, err := storage.Filesystem().Open(storage.Filesystem().Join("info", "exclude"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return patterns, nil
+		}
+
+		return nil, err
+	}
+	defer f.Close()
+
+	exclude, err := readGitIgnorePatterns(f, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(patterns, exclude...), nil
+}
@@ -0,0 +1,137 @@
+package osvscanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	formatconfig "github.com/go-git/go-git/v5/plumbing/format/config"
+)
+
+func parseGitConfig(t *testing.T, content string) *formatconfig.Config {
+	t.Helper()
+
+	raw := formatconfig.New()
+	if err := formatconfig.NewDecoder(strings.NewReader(content)).Decode(raw); err != nil {
+		t.Fatalf("could not parse config: %v", err)
+	}
+
+	return raw
+}
+
+func Test_excludesFile(t *testing.T) {
+	t.Parallel()
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skipf("no home directory: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		configs []string
+		want    string
+	}{
+		{
+			name:    "not set",
+			configs: []string{"[user]\n\tname = someone\n"},
+			want:    "/default/ignore",
+		},
+		{
+			name:    "set globally",
+			configs: []string{"[core]\n\texcludesFile = /global/ignore\n", "[core]\n\tbare = false\n"},
+			want:    "/global/ignore",
+		},
+		{
+			name:    "later configs take precedence",
+			configs: []string{"[core]\n\texcludesfile = /system/ignore\n", "[core]\n\tEXCLUDESFILE = /repo/ignore\n"},
+			want:    "/repo/ignore",
+		},
+		{
+			name:    "home directory",
+			configs: []string{"[core]\n\texcludesFile = ~/.gitignore_global\n"},
+			want:    filepath.Join(home, ".gitignore_global"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configs := []*formatconfig.Config{nil}
+			for _, content := range tt.configs {
+				configs = append(configs, parseGitConfig(t, content))
+			}
+
+			if got := excludesFile(configs, "/default/ignore"); got != tt.want {
+				t.Errorf("excludesFile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("could not create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("could not write %s: %v", name, err)
+		}
+	}
+}
+
+func TestScanDir_GitExcludes(t *testing.T) {
+	t.Parallel()
+
+	repoDir, err := filepath.EvalSymlinks(makeRepository(t, map[string]string{"requirements.txt": "flask==1.0.0\n"}))
+	if err != nil {
+		t.Fatalf("could not resolve directory: %v", err)
+	}
+	excludes := filepath.Join(t.TempDir(), "ignore")
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("could not resolve directory: %v", err)
+	}
+	commit := addWorktree(t, repoDir, dir, "feature")
+
+	writeFiles(t, repoDir, map[string]string{
+		".git/info/exclude": "# scratch copies of lockfiles\nscratch/\n",
+		".git/config":       "[core]\n\tbare = false\n\texcludesFile = " + filepath.ToSlash(excludes) + "\n",
+	})
+	if err := os.WriteFile(excludes, []byte("vendored/\n"), 0600); err != nil {
+		t.Fatalf("could not write ignore file: %v", err)
+	}
+
+	files := map[string]string{
+		"requirements.txt":          "flask==1.0.0\n",
+		"scratch/requirements.txt":  "django==1.0.0\n",
+		"vendored/requirements.txt": "requests==2.0.0\n",
+	}
+	writeFiles(t, repoDir, files)
+	writeFiles(t, dir, files)
+
+	t.Run("repository", func(t *testing.T) {
+		t.Parallel()
+
+		want := []string{"git:/@" + commit, "lockfile:/requirements.txt"}
+		if diff := cmp.Diff(want, scanDirQueries(t, repoDir)); diff != "" {
+			t.Errorf("scanDir() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("linked worktree", func(t *testing.T) {
+		t.Parallel()
+
+		want := []string{"git:/@" + commit, "lockfile:/requirements.txt"}
+		if diff := cmp.Diff(want, scanDirQueries(t, dir)); diff != "" {
+			t.Errorf("scanDir() mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
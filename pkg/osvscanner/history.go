@@ -0,0 +1,91 @@
+package osvscanner
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/osv-scanner/pkg/githistory"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+// firstAffected returns the first snapshot at which the lockfile had a version
+// of the package that is affected by the vulnerability, along with that
+// version, with the version the package is at now always being affected
+func firstAffected(snapshots []githistory.Snapshot, pkg models.PackageInfo, vuln models.Vulnerability) (githistory.Checkpoint, string, bool) {
+	for _, snapshot := range snapshots {
+		for _, found := range snapshot.Packages {
+			if found.Name != pkg.Name || string(found.Ecosystem) != pkg.Ecosystem {
+				continue
+			}
+
+			if found.Version == pkg.Version || osv.IsAffected(&vuln, pkg.Ecosystem, found.Name, found.Version) {
+				return snapshot.Checkpoint, found.Version, true
+			}
+		}
+	}
+
+	return githistory.Checkpoint{}, "", false
+}
+
+// scanHistory finds when each vulnerability of the packages of lockfiles that
+// are committed to git first affected them, by parsing the lockfiles at each
+// tag of their history, or at every nth commit if every is more than 0
+func scanHistory(r output.Reporter, results *models.VulnerabilityResults, every int) []models.VulnerabilityHistory {
+	var history []models.VulnerabilityHistory
+
+	for _, res := range results.Results {
+		if res.Source.Type != "lockfile" || len(res.Packages) == 0 {
+			continue
+		}
+
+		snapshots, err := githistory.LockfileHistory(res.Source.Path, "", every)
+		if err != nil {
+			r.PrintText(fmt.Sprintf("Could not scan the history of %s: %v\n", res.Source.Path, err))
+			continue
+		}
+
+		for _, pkg := range res.Packages {
+			for _, vuln := range pkg.Vulnerabilities {
+				// packages whose current version has not been committed are
+				// not in the history
+				checkpoint, version, ok := firstAffected(snapshots, pkg.Package, vuln)
+				if !ok {
+					continue
+				}
+
+				history = append(history, models.VulnerabilityHistory{
+					Source:  res.Source,
+					Package: pkg.Package,
+					ID:      vuln.ID,
+					Tag:     checkpoint.Tag,
+					Commit:  checkpoint.Commit.Hash,
+					Date:    checkpoint.Commit.Time,
+					Version: version,
+				})
+			}
+		}
+	}
+
+	return history
+}
+
+// sortHistory orders the history of vulnerabilities by their source, package
+// and ID
+func sortHistory(history []models.VulnerabilityHistory) {
+	sort.SliceStable(history, func(a, b int) bool {
+		ha, hb := history[a], history[b]
+		if ha.Source.Path != hb.Source.Path {
+			return ha.Source.Path < hb.Source.Path
+		}
+		if ha.Package.Name != hb.Package.Name {
+			return ha.Package.Name < hb.Package.Name
+		}
+		if ha.Package.Version != hb.Package.Version {
+			return ha.Package.Version < hb.Package.Version
+		}
+
+		return ha.ID < hb.ID
+	})
+}
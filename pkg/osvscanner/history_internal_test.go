@@ -0,0 +1,93 @@
+package osvscanner
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/githistory"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func Test_firstAffected(t *testing.T) {
+	t.Parallel()
+
+	snapshot := func(tag string, versions ...string) githistory.Snapshot {
+		s := githistory.Snapshot{Checkpoint: githistory.Checkpoint{Tag: tag}}
+		if versions == nil {
+			return s
+		}
+		s.Packages = []lockfile.PackageDetails{{Name: "django", Version: "1.0.0", Ecosystem: lockfile.PipEcosystem}}
+		for _, version := range versions {
+			s.Packages = append(s.Packages, lockfile.PackageDetails{Name: "flask", Version: version, Ecosystem: lockfile.PipEcosystem})
+		}
+
+		return s
+	}
+	snapshots := []githistory.Snapshot{
+		snapshot("v0.1.0"),
+		snapshot("v0.2.0", "0.12.0"),
+		snapshot("v1.0.0", "1.0.0"),
+		snapshot("v1.1.0", "1.1.0"),
+		snapshot("v2.0.0", "2.0.1"),
+	}
+
+	vuln := func(events string) models.Vulnerability {
+		var v models.Vulnerability
+		content := `{"id": "GHSA-1234", "affected": [{"package": {"ecosystem": "PyPI", "name": "flask"}, "ranges": [{"type": "ECOSYSTEM", "events": ` + events + `}]}]}`
+		if err := json.Unmarshal([]byte(content), &v); err != nil {
+			t.Fatalf("could not parse vulnerability: %v", err)
+		}
+
+		return v
+	}
+	pkg := models.PackageInfo{Name: "flask", Version: "2.0.1", Ecosystem: "PyPI"}
+
+	tests := []struct {
+		name        string
+		vuln        models.Vulnerability
+		wantTag     string
+		wantVersion string
+		wantOK      bool
+	}{
+		{
+			name:        "affected since an earlier version",
+			vuln:        vuln(`[{"introduced": "1.0.0"}, {"fixed": "2.1.0"}]`),
+			wantTag:     "v1.0.0",
+			wantVersion: "1.0.0",
+			wantOK:      true,
+		},
+		{
+			name:        "earlier versions were fixed",
+			vuln:        vuln(`[{"introduced": "0"}, {"fixed": "0.12.3"}, {"introduced": "2.0.0"}]`),
+			wantTag:     "v0.2.0",
+			wantVersion: "0.12.0",
+			wantOK:      true,
+		},
+		{
+			name:        "only the current version",
+			vuln:        vuln(`[{"introduced": "2.0.1"}, {"fixed": "2.0.2"}]`),
+			wantTag:     "v2.0.0",
+			wantVersion: "2.0.1",
+			wantOK:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			checkpoint, version, ok := firstAffected(snapshots, pkg, tt.vuln)
+			if ok != tt.wantOK || checkpoint.Tag != tt.wantTag || version != tt.wantVersion {
+				t.Errorf("firstAffected() = %q, %q, %v, want %q, %q, %v", checkpoint.Tag, version, ok, tt.wantTag, tt.wantVersion, tt.wantOK)
+			}
+		})
+	}
+
+	// versions that have not been committed are not in the history
+	uncommitted := models.PackageInfo{Name: "flask", Version: "3.0.0", Ecosystem: "PyPI"}
+	if _, _, ok := firstAffected(snapshots, uncommitted, vuln(`[{"introduced": "3.0.0"}]`)); ok {
+		t.Errorf("expected an uncommitted version not to be found")
+	}
+}
@@ -0,0 +1,127 @@
+package osvscanner
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/google/osv-scanner/pkg/host"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+// scanHost adds the OS packages installed on the filesystem at root and the
+// kernel that it is running to `query`, checking them against the advisories
+// for the release of the operating system it has. The kernel is only known
+// when auditing the running host, so it is skipped if it cannot be read.
+//
+// Applications installed outside of the package manager are also added, with
+// snaps and flatpaks being checked as the packages they are releases of, and
+// the executables of systemd services being identified by their hashes using
+// `resolve`. Those that cannot be identified are recorded in `issues`.
+func scanHost(r output.Reporter, query *osv.BatchedQuery, issues *scanIssues, limits scanLimits, root string, resolve func(osv.ArtifactHash) ([]*osv.Query, error)) error {
+	release, err := host.ReadOSRelease(root)
+	if err != nil {
+		return err
+	}
+
+	ecosystem, err := release.Ecosystem()
+	if err != nil {
+		return err
+	}
+
+	databasePath, parser, err := release.PackageDatabase()
+	if err != nil {
+		return err
+	}
+	databasePath = filepath.Join(root, databasePath)
+
+	if err := limits.checkFileSize(databasePath); err != nil {
+		return err
+	}
+
+	packages, err := parser(databasePath)
+	if err != nil {
+		return err
+	}
+
+	if err := limits.checkPackages(len(packages)); err != nil {
+		return err
+	}
+
+	for _, pkg := range packages {
+		pkg.Ecosystem = lockfile.Ecosystem(ecosystem)
+
+		pkgQuery := osv.MakePkgRequest(pkg)
+		pkgQuery.Source = models.SourceInfo{
+			Path: databasePath,
+			Type: "host",
+		}
+		query.Queries = append(query.Queries, pkgQuery)
+	}
+
+	kernel := "an unknown kernel"
+	if kernelRelease, err := host.KernelRelease(root); err == nil {
+		if version := host.KernelVersion(kernelRelease); version != "" {
+			query.Queries = append(query.Queries, &osv.Query{
+				Version: version,
+				Package: osv.Package{
+					Name:      host.KernelPackage,
+					Ecosystem: host.KernelEcosystem,
+				},
+				Source: models.SourceInfo{
+					Path: filepath.Join(root, host.KernelReleasePath),
+					Type: "host",
+				},
+			})
+			kernel = "kernel " + kernelRelease
+		}
+	}
+
+	r.PrintText(fmt.Sprintf("Scanned host running %s (%s) with %d packages and %s\n", release.Name(), ecosystem, len(packages), kernel))
+
+	apps, err := host.ListApplications(root)
+	if err != nil {
+		return err
+	}
+	if len(apps) > 0 {
+		r.PrintText(fmt.Sprintf("Scanned host and found %d snap and flatpak applications\n", len(apps)))
+	}
+
+	for _, app := range apps {
+		source := models.SourceInfo{Path: app.Path, Type: "host"}
+
+		upstream, ok := host.UpstreamOf(app)
+		if !ok || app.Version == "" {
+			r.PrintText(fmt.Sprintf("Skipping %s %s: it is not known what package it is a release of\n", app.Kind, app.Name))
+			issues.skip(source, fmt.Sprintf("the %s %s is not known to be a release of a package", app.Kind, app.Name))
+
+			continue
+		}
+
+		query.Queries = append(query.Queries, &osv.Query{
+			Version: app.Version,
+			Package: osv.Package{
+				Name:      upstream.Name,
+				Ecosystem: upstream.Ecosystem,
+			},
+			Source: source,
+		})
+	}
+
+	for _, executable := range host.ListServiceExecutables(root) {
+		if err := scanArtifact(r, query, issues, limits, executable, resolve); err != nil {
+			if errors.Is(err, ErrLimitExceeded) {
+				issues.skip(models.SourceInfo{Path: executable, Type: "artifact"}, err.Error())
+
+				continue
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
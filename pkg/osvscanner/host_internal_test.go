@@ -0,0 +1,118 @@
+package osvscanner
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/host"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+func Test_scanHost(t *testing.T) {
+	t.Parallel()
+
+	root := "../host/fixtures/debian"
+	query := &osv.BatchedQuery{}
+
+	if err := scanHost(output.NewVoidReporter(), query, &scanIssues{}, scanLimits{}, root, nil); err != nil {
+		t.Fatalf("scanHost() error = %v", err)
+	}
+
+	packages := models.SourceInfo{Path: filepath.Join(root, "var/lib/dpkg/status"), Type: "host"}
+	kernel := models.SourceInfo{Path: filepath.Join(root, "proc/sys/kernel/osrelease"), Type: "host"}
+
+	want := []*osv.Query{
+		{Version: "1.1.1n-0+deb11u3", Package: osv.Package{Name: "openssl", Ecosystem: "Debian:11"}, Source: packages},
+		{Version: "5.1-2+deb11u1", Package: osv.Package{Name: "bash", Ecosystem: "Debian:11"}, Source: packages},
+		{Version: "5.10.0", Package: osv.Package{Name: "Kernel", Ecosystem: "Linux"}, Source: kernel},
+	}
+
+	if diff := cmp.Diff(want, query.Queries); diff != "" {
+		t.Errorf("scanHost() queries mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_scanHost_WithoutKernel(t *testing.T) {
+	t.Parallel()
+
+	query := &osv.BatchedQuery{}
+
+	if err := scanHost(output.NewVoidReporter(), query, &scanIssues{}, scanLimits{}, "../host/fixtures/alpine", nil); err != nil {
+		t.Fatalf("scanHost() error = %v", err)
+	}
+
+	if len(query.Queries) != 1 {
+		t.Fatalf("expected 1 query, got %d", len(query.Queries))
+	}
+	if got := query.Queries[0].Package; got != (osv.Package{Name: "apk-tools", Ecosystem: "Alpine:v3.18"}) {
+		t.Errorf("unexpected package %v", got)
+	}
+}
+
+func Test_scanHost_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	err := scanHost(output.NewVoidReporter(), &osv.BatchedQuery{}, &scanIssues{}, scanLimits{}, "../host/fixtures/unknown", nil)
+
+	if !errors.Is(err, host.ErrUnsupportedOS) {
+		t.Errorf("scanHost() error = %v, want %v", err, host.ErrUnsupportedOS)
+	}
+}
+
+func Test_scanHost_Applications(t *testing.T) {
+	t.Parallel()
+
+	root := "../host/fixtures/ubuntu"
+	query := &osv.BatchedQuery{}
+	issues := &scanIssues{}
+
+	agent := filepath.Join(root, "opt/acme/bin/acme-agent")
+	resolve := func(_ osv.ArtifactHash) ([]*osv.Query, error) {
+		return []*osv.Query{{Version: "2.4.0", Package: osv.Package{Name: "github.com/acme/agent", Ecosystem: "Go"}}}, nil
+	}
+
+	if err := scanHost(output.NewVoidReporter(), query, issues, scanLimits{}, root, resolve); err != nil {
+		t.Fatalf("scanHost() error = %v", err)
+	}
+
+	want := []*osv.Query{
+		{
+			Version: "3.0.2-0ubuntu1.10",
+			Package: osv.Package{Name: "openssl", Ecosystem: "Ubuntu:22.04:LTS"},
+			Source:  models.SourceInfo{Path: filepath.Join(root, "var/lib/dpkg/status"), Type: "host"},
+		},
+		{
+			Version: "1.28.2",
+			Package: osv.Package{Name: "k8s.io/kubernetes", Ecosystem: "Go"},
+			Source:  models.SourceInfo{Path: filepath.Join(root, "snap/kubectl/current"), Type: "host"},
+		},
+		{
+			Version: "2.4.0",
+			Package: osv.Package{Name: "github.com/acme/agent", Ecosystem: "Go"},
+			Source:  models.SourceInfo{Path: agent, Type: "artifact"},
+		},
+	}
+
+	if diff := cmp.Diff(want, query.Queries); diff != "" {
+		t.Errorf("scanHost() queries mismatch (-want +got):\n%s", diff)
+	}
+
+	wantSkipped := []models.SkippedSource{
+		{
+			Source: models.SourceInfo{Path: filepath.Join(root, "var/lib/flatpak/app/org.gimp.GIMP/current/active"), Type: "host"},
+			Reason: "the flatpak org.gimp.GIMP is not known to be a release of a package",
+		},
+		{
+			Source: models.SourceInfo{Path: filepath.Join(root, "snap/spotify/current"), Type: "host"},
+			Reason: "the snap spotify is not known to be a release of a package",
+		},
+	}
+
+	if diff := cmp.Diff(wantSkipped, issues.skipped); diff != "" {
+		t.Errorf("scanHost() skipped mismatch (-want +got):\n%s", diff)
+	}
+}
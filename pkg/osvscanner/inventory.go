@@ -0,0 +1,117 @@
+package osvscanner
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+// inventoryCollector records every package that is scanned, as the stream
+// only keeps the ones that are vulnerable
+type inventoryCollector struct {
+	seen     map[models.InventoryPackage]bool
+	packages []models.InventoryPackage
+}
+
+func newInventoryCollector() *inventoryCollector {
+	return &inventoryCollector{seen: map[models.InventoryPackage]bool{}}
+}
+
+// inventoryPackage returns what the query is for, reporting false for queries
+// that are not for a package or commit, such as those of artifact hashes
+func inventoryPackage(query *osv.Query) (models.InventoryPackage, bool) {
+	item := models.InventoryPackage{Source: query.Source}
+
+	switch {
+	case query.Commit != "":
+		item.Package.Name = query.PackageName
+		if item.Package.Name == "" {
+			item.Package.Name = filepath.Base(filepath.Clean(query.Source.Path))
+		}
+		item.Commit = query.Commit
+	case query.Package.PURL != "":
+		pkg, err := PURLToPackage(query.Package.PURL)
+		if err != nil {
+			return models.InventoryPackage{}, false
+		}
+		item.Package = pkg
+		item.PURL = query.Package.PURL
+	case query.Package.Name != "":
+		item.Package = models.PackageInfo{
+			Name:      query.Package.Name,
+			Version:   query.Version,
+			Ecosystem: query.Package.Ecosystem,
+		}
+		item.PURL = PackageToPURL(item.Package)
+	default:
+		return models.InventoryPackage{}, false
+	}
+
+	return item, true
+}
+
+// inspect records the packages of the batch that have not been seen already
+func (c *inventoryCollector) inspect(batch osv.BatchedQuery) {
+	for _, query := range batch.Queries {
+		item, ok := inventoryPackage(query)
+		if !ok || c.seen[item] {
+			continue
+		}
+
+		c.seen[item] = true
+		c.packages = append(c.packages, item)
+	}
+}
+
+// sortedPackages returns the packages that have been recorded, ordered by
+// their source and then by their name and version
+func (c *inventoryCollector) sortedPackages() []models.InventoryPackage {
+	sortInventory(c.packages)
+
+	return c.packages
+}
+
+func sortInventory(packages []models.InventoryPackage) {
+	sort.SliceStable(packages, func(a, b int) bool {
+		pa, pb := packages[a], packages[b]
+		if pa.Source.Path != pb.Source.Path {
+			return pa.Source.Path < pb.Source.Path
+		}
+		if pa.Source.Type != pb.Source.Type {
+			return pa.Source.Type < pb.Source.Type
+		}
+		if c := comparePackages(pa.Package, pb.Package); c != 0 {
+			return c < 0
+		}
+
+		return pa.Commit < pb.Commit
+	})
+}
+
+// inventoryResults returns the results of a scan that only lists the packages
+// that were found, along with the inputs that could not be
+func inventoryResults(r output.Reporter, actions ScannerActions, inventory *inventoryCollector, issues scanIssues) (models.VulnerabilityResults, error) {
+	results := models.VulnerabilityResults{
+		Results:       []models.PackageSource{},
+		Inventory:     inventory.sortedPackages(),
+		ParseFailures: issues.parseFailures,
+		Skipped:       issues.skipped,
+	}
+	if actions.Reproducible {
+		makeReproducible(&results)
+	} else {
+		results.Timings = actions.Profile.Sources()
+	}
+
+	r.PrintText(fmt.Sprintf("Found %d packages\n", len(results.Inventory)))
+
+	if actions.Strict && len(issues.skipped) > 0 {
+		return results, fmt.Errorf("%w: %d inputs were skipped", ErrIncompleteScan, len(issues.skipped))
+	}
+
+	return results, nil
+}
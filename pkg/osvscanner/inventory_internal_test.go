@@ -0,0 +1,122 @@
+package osvscanner
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+)
+
+func TestPackageToPURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pkg  models.PackageInfo
+		want string
+	}{
+		{pkg: models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"}, want: "pkg:npm/lodash@4.17.20"},
+		{pkg: models.PackageInfo{Name: "@babel/core", Version: "7.20.0", Ecosystem: "npm"}, want: "pkg:npm/%40babel/core@7.20.0"},
+		{pkg: models.PackageInfo{Name: "org.apache.logging.log4j:log4j-core", Version: "2.14.1", Ecosystem: "Maven"}, want: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"},
+		{pkg: models.PackageInfo{Name: "github.com/gin-gonic/gin", Version: "1.8.1", Ecosystem: "Go"}, want: "pkg:golang/github.com/gin-gonic/gin@1.8.1"},
+		{pkg: models.PackageInfo{Name: "openssl", Version: "1.1.1n-0+deb11u1", Ecosystem: "Debian"}, want: "pkg:deb/debian/openssl@1.1.1n-0+deb11u1"},
+		{pkg: models.PackageInfo{Name: "zlib", Version: "1.2.13", Ecosystem: "ConanCenter"}, want: "pkg:conan/zlib@1.2.13"},
+		{pkg: models.PackageInfo{Name: "http", Version: "0.13.5", Ecosystem: "Pub"}, want: "pkg:pub/http@0.13.5"},
+		{pkg: models.PackageInfo{Name: "openssl", Version: "3.1.0-r0", Ecosystem: "Alpine"}, want: ""},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.pkg.Name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := PackageToPURL(tt.pkg); got != tt.want {
+				t.Errorf("PackageToPURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInventoryCollector(t *testing.T) {
+	t.Parallel()
+
+	lockfileSource := models.SourceInfo{Path: "/app/package-lock.json", Type: "lockfile"}
+	sbom := models.SourceInfo{Path: "/app/bom.json", Type: "sbom"}
+	repo := models.SourceInfo{Path: "/app/", Type: "git"}
+
+	pkgQuery := func(name string, version string) *osv.Query {
+		query := osv.MakePkgRequest(lockfile.PackageDetails{Name: name, Version: version, Ecosystem: lockfile.NpmEcosystem})
+		query.Source = lockfileSource
+
+		return query
+	}
+	purlQuery := osv.MakePURLRequest("pkg:pypi/flask@2.0.0")
+	purlQuery.Source = sbom
+	commitQuery := osv.MakeCommitRequest("9d1b2f0")
+	commitQuery.Source = repo
+
+	collector := newInventoryCollector()
+	collector.inspect(osv.BatchedQuery{Queries: []*osv.Query{
+		pkgQuery("minimist", "1.2.0"),
+		pkgQuery("lodash", "4.17.20"),
+		commitQuery,
+	}})
+	collector.inspect(osv.BatchedQuery{Queries: []*osv.Query{
+		pkgQuery("lodash", "4.17.20"),
+		purlQuery,
+		{Package: osv.Package{}},
+	}})
+
+	want := []models.InventoryPackage{
+		{
+			Source:  repo,
+			Package: models.PackageInfo{Name: "app"},
+			Commit:  "9d1b2f0",
+		},
+		{
+			Source:  sbom,
+			Package: models.PackageInfo{Name: "flask", Version: "2.0.0", Ecosystem: "PyPI"},
+			PURL:    "pkg:pypi/flask@2.0.0",
+		},
+		{
+			Source:  lockfileSource,
+			Package: models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+			PURL:    "pkg:npm/lodash@4.17.20",
+		},
+		{
+			Source:  lockfileSource,
+			Package: models.PackageInfo{Name: "minimist", Version: "1.2.0", Ecosystem: "npm"},
+			PURL:    "pkg:npm/minimist@1.2.0",
+		},
+	}
+
+	if diff := cmp.Diff(want, collector.sortedPackages()); diff != "" {
+		t.Errorf("findings() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDoScan_InventoryOnly(t *testing.T) {
+	t.Parallel()
+
+	// nothing is queried, so this does not need the network
+	results, err := DoScan(ScannerActions{
+		LockfilePaths: []string{"package-lock.json:../lockfile/fixtures/npm/one-package.v2.json"},
+		InventoryOnly: true,
+		Reproducible:  true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []models.InventoryPackage{{
+		Source:  models.SourceInfo{Path: "../lockfile/fixtures/npm/one-package.v2.json", Type: "lockfile"},
+		Package: models.PackageInfo{Name: "wrappy", Version: "1.0.2", Ecosystem: "npm"},
+		PURL:    "pkg:npm/wrappy@1.0.2",
+	}}
+	if diff := cmp.Diff(want, results.Inventory); diff != "" {
+		t.Errorf("Inventory mismatch (-want +got):\n%s", diff)
+	}
+	if len(results.Results) != 0 {
+		t.Errorf("expected no vulnerabilities to be reported, got %d sources", len(results.Results))
+	}
+}
@@ -0,0 +1,143 @@
+package osvscanner
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/osv-scanner/pkg/licenses"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+)
+
+// licenseChecker looks up the licenses of the packages of lockfiles and SBOMs
+// as they are scanned, checking them against the license policy of the config
+// of their source, and looking up each version of a package only once
+type licenseChecker struct {
+	lookupTracker[models.PackageInfo, []string, models.LicenseViolation]
+	// policyFor returns the license policy of the source of the query,
+	// reporting false if its config does not have one
+	policyFor func(query *osv.Query) (licenses.Policy, bool)
+
+	// unknown counts the packages whose licenses are not known
+	unknown int
+}
+
+// licenseCheck is a package whose licenses are to be checked against a policy
+type licenseCheck struct {
+	item   models.InventoryPackage
+	policy licenses.Policy
+}
+
+func newLicenseChecker(ctx context.Context, fetch func(ctx context.Context, ecosystem string, name string, version string) ([]string, error)) *licenseChecker {
+	lookup := func(pkg models.PackageInfo) ([]string, error) {
+		return fetch(ctx, pkg.Ecosystem, pkg.Name, pkg.Version)
+	}
+
+	return &licenseChecker{
+		lookupTracker: newLookupTracker(lookup, sortLicenseViolations),
+	}
+}
+
+// inspect checks the licenses of each package in the batch whose source has
+// a license policy
+func (c *licenseChecker) inspect(batch osv.BatchedQuery) {
+	checks := make(chan licenseCheck)
+	var wg sync.WaitGroup
+
+	for i := 0; i < registryWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for check := range checks {
+				c.check(check)
+			}
+		}()
+	}
+
+	for _, query := range batch.Queries {
+		item, ok := inventoryPackage(query)
+		if !ok || item.Commit != "" || item.Package.Version == "" {
+			continue
+		}
+
+		if !c.firstSeen(item.Source, item.Package) {
+			continue
+		}
+
+		policy, ok := c.policyFor(query)
+		if !ok {
+			continue
+		}
+
+		checks <- licenseCheck{item: item, policy: policy}
+	}
+	close(checks)
+	wg.Wait()
+}
+
+// licenseExpression combines the license expressions of a package, which all
+// apply to it, with those that cannot be parsed being taken as licenses that
+// are only allowed if nothing but denied licenses are listed
+func licenseExpression(found []string) licenses.Expression {
+	operands := make([]licenses.Expression, 0, len(found))
+	for _, license := range found {
+		expression, err := licenses.Parse(license)
+		if err != nil {
+			expression = licenses.License{ID: license}
+		}
+		operands = append(operands, expression)
+	}
+
+	if len(operands) == 1 {
+		return operands[0]
+	}
+
+	return licenses.Compound{Operator: licenses.And, Operands: operands}
+}
+
+func (c *licenseChecker) check(check licenseCheck) {
+	found, err := c.get(check.item.Package)
+	if err != nil {
+		return
+	}
+
+	if len(found) == 0 {
+		c.mu.Lock()
+		c.unknown++
+		c.mu.Unlock()
+
+		return
+	}
+
+	expression := licenseExpression(found)
+	verdict := check.policy.Evaluate(expression)
+	if verdict.Allowed {
+		return
+	}
+
+	c.add(models.LicenseViolation{
+		Source:  check.item.Source,
+		Package: check.item.Package,
+		License: expression.String(),
+		Denied:  verdict.Licenses,
+	})
+}
+
+func sortLicenseViolations(violations []models.LicenseViolation) {
+	sort.SliceStable(violations, func(a, b int) bool {
+		va, vb := violations[a], violations[b]
+		if va.Source.Path != vb.Source.Path {
+			return va.Source.Path < vb.Source.Path
+		}
+		if va.Package.Name != vb.Package.Name {
+			return va.Package.Name < vb.Package.Name
+		}
+		if va.Package.Version != vb.Package.Version {
+			return va.Package.Version < vb.Package.Version
+		}
+
+		return strings.Join(va.Denied, ",") < strings.Join(vb.Denied, ",")
+	})
+}
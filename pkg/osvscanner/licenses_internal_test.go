@@ -0,0 +1,90 @@
+package osvscanner
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/licenses"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+)
+
+func Test_licenseChecker(t *testing.T) {
+	t.Parallel()
+
+	known := map[string][]string{
+		"react":                 {"MIT"},
+		"mongodb-memory-server": {"MIT", "SSPL-1.0"},
+		"left-pad":              {"WTFPL OR MIT"},
+		"node-forge":            {"(BSD-3-Clause OR GPL-2.0-only)"},
+		"custom":                {"see LICENSE.txt"},
+	}
+
+	var fetches int32
+	checker := newLicenseChecker(context.Background(), func(_ context.Context, ecosystem string, name string, version string) ([]string, error) {
+		atomic.AddInt32(&fetches, 1)
+		if name == "flaky" {
+			return nil, errors.New("deps.dev is unavailable")
+		}
+
+		return known[name], nil
+	})
+
+	policy, err := licenses.NewPolicy([]string{"MIT", "Apache-2.0", "BSD-3-Clause"}, []string{"SSPL-1.0"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	checked := models.SourceInfo{Path: "/app/package-lock.json", Type: "lockfile"}
+	unchecked := models.SourceInfo{Path: "/tools/package-lock.json", Type: "lockfile"}
+	checker.policyFor = func(query *osv.Query) (licenses.Policy, bool) {
+		return policy, query.Source == checked
+	}
+
+	query := func(source models.SourceInfo, name string) *osv.Query {
+		return &osv.Query{Source: source, Version: "1.0.0", Package: osv.Package{Name: name, Ecosystem: "npm"}}
+	}
+	sbomQuery := osv.MakePURLRequest("pkg:npm/custom@1.0.0")
+	sbomQuery.Source = checked
+
+	checker.inspect(osv.BatchedQuery{Queries: []*osv.Query{
+		query(checked, "react"),
+		query(checked, "mongodb-memory-server"),
+		query(checked, "left-pad"),
+		query(checked, "node-forge"),
+		query(checked, "unpublished"),
+		query(checked, "flaky"),
+		sbomQuery,
+		query(unchecked, "mongodb-memory-server"),
+		osv.MakeCommitRequest("9d1b2f0"),
+	}})
+	checker.inspect(osv.BatchedQuery{Queries: []*osv.Query{
+		query(checked, "react"),
+	}})
+
+	pkg := func(name string) models.PackageInfo {
+		return models.PackageInfo{Name: name, Version: "1.0.0", Ecosystem: "npm"}
+	}
+	want := []models.LicenseViolation{
+		{Source: checked, Package: pkg("custom"), License: "see LICENSE.txt", Denied: []string{"see LICENSE.txt"}},
+		{Source: checked, Package: pkg("mongodb-memory-server"), License: "MIT AND SSPL-1.0", Denied: []string{"SSPL-1.0"}},
+	}
+
+	if diff := cmp.Diff(want, checker.findings()); diff != "" {
+		t.Errorf("findings() mismatch (-want +got):\n%s", diff)
+	}
+	if checker.unknown != 1 {
+		t.Errorf("expected 1 package to have unknown licenses, got %d", checker.unknown)
+	}
+	if checker.failed != 1 {
+		t.Errorf("expected 1 package to have failed to be looked up, got %d", checker.failed)
+	}
+
+	// only the packages of sources with a policy are looked up, once each
+	if got := atomic.LoadInt32(&fetches); got != 7 {
+		t.Errorf("expected 7 packages to have been looked up, got %d", got)
+	}
+}
@@ -0,0 +1,76 @@
+package osvscanner
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+// scanLimits guard against inputs that would use too much memory to scan,
+// with a zero value meaning there is no limit
+type scanLimits struct {
+	// maxFileSize is the size in bytes of the largest file that will be parsed
+	maxFileSize int64
+	// maxPackages is the most packages that will be queried from one source
+	maxPackages int
+	// memoryBudget is how many bytes the heap can grow to while scanning
+	memoryBudget int64
+}
+
+func limitsFor(actions ScannerActions) scanLimits {
+	return scanLimits{
+		maxFileSize:  actions.MaxFileSize,
+		maxPackages:  actions.MaxPackagesPerSource,
+		memoryBudget: actions.MemoryBudget,
+	}
+}
+
+// checkFileSize returns an error if the file at the given path is too large
+// to be parsed; files that can't be read are left for the parser to report
+func (l scanLimits) checkFileSize(path string) error {
+	if l.maxFileSize <= 0 {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+
+	if info.Size() > l.maxFileSize {
+		return fmt.Errorf("%w: file is %d bytes, which is more than the maximum of %d", ErrLimitExceeded, info.Size(), l.maxFileSize)
+	}
+
+	return nil
+}
+
+// checkPackages returns an error if a source has too many packages to query
+func (l scanLimits) checkPackages(count int) error {
+	if l.maxPackages <= 0 || count <= l.maxPackages {
+		return nil
+	}
+
+	return fmt.Errorf("%w: found %d packages, which is more than the maximum of %d", ErrLimitExceeded, count, l.maxPackages)
+}
+
+// checkMemory returns an error if the heap has grown beyond the memory budget,
+// even after garbage collecting
+func (l scanLimits) checkMemory() error {
+	if l.memoryBudget <= 0 {
+		return nil
+	}
+
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	if int64(stats.HeapAlloc) <= l.memoryBudget {
+		return nil
+	}
+
+	runtime.GC()
+	runtime.ReadMemStats(&stats)
+	if int64(stats.HeapAlloc) <= l.memoryBudget {
+		return nil
+	}
+
+	return fmt.Errorf("%w: scanning used %d bytes of memory, which is more than the budget of %d", ErrLimitExceeded, stats.HeapAlloc, l.memoryBudget)
+}
@@ -0,0 +1,58 @@
+package osvscanner
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_scanLimits(t *testing.T) {
+	t.Parallel()
+
+	limits := scanLimits{maxFileSize: 10, maxPackages: 2}
+
+	if err := limits.checkFileSize("../lockfile/fixtures/pip/multiple-packages-mixed.txt"); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected ErrLimitExceeded for a large file, got %v", err)
+	}
+
+	if err := limits.checkFileSize("../../fixtures/does-not-exist.lock"); err != nil {
+		t.Errorf("expected missing files to be left for the parser, got %v", err)
+	}
+
+	if err := limits.checkPackages(2); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := limits.checkPackages(3); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected ErrLimitExceeded for too many packages, got %v", err)
+	}
+
+	if err := (scanLimits{}).checkPackages(1_000_000); err != nil {
+		t.Errorf("expected no limit by default, got %v", err)
+	}
+
+	if err := (scanLimits{memoryBudget: 1}).checkMemory(); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected ErrLimitExceeded when over the memory budget, got %v", err)
+	}
+}
+
+func TestDoScan_MaxPackagesPerSource(t *testing.T) {
+	t.Parallel()
+
+	results, err := DoScan(ScannerActions{
+		LockfilePaths:        []string{"requirements.txt:../lockfile/fixtures/pip/multiple-packages-mixed.txt"},
+		MaxPackagesPerSource: 1,
+	}, nil)
+
+	if !errors.Is(err, NoPackagesFoundErr) {
+		t.Fatalf("expected NoPackagesFoundErr, got %v", err)
+	}
+
+	if len(results.Skipped) != 1 {
+		t.Fatalf("expected 1 skipped source, got %d", len(results.Skipped))
+	}
+
+	if !strings.HasPrefix(results.Skipped[0].Reason, "limit exceeded: found ") {
+		t.Errorf("unexpected reason: %s", results.Skipped[0].Reason)
+	}
+}
@@ -0,0 +1,123 @@
+package osvscanner
+
+import (
+	"os"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/githistory"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// declarationLocation returns the location that the lockfile parser recorded
+// for the package, which only knows where the declaration starts, so its end
+// is filled in by locateDeclarations
+func declarationLocation(pkg lockfile.PackageDetails) *models.SourceLocation {
+	column := pkg.Column
+	if column == 0 {
+		column = 1
+	}
+
+	return &models.SourceLocation{Line: pkg.Line, Column: column, EndColumn: column}
+}
+
+// locateDeclarations records where each vulnerable package is declared within
+// its lockfile or SBOM, for packages whose location is not already known, and
+// finds the end of the declarations that parsers only know the start of
+func locateDeclarations(results *models.VulnerabilityResults) {
+	for i := range results.Results {
+		source := &results.Results[i]
+		if source.Source.Type != "lockfile" && source.Source.Type != "sbom" {
+			continue
+		}
+
+		var lines []string
+		for j := range source.Packages {
+			pkg := &source.Packages[j]
+			if pkg.Location != nil && pkg.Location.EndColumn > pkg.Location.Column {
+				continue
+			}
+
+			if lines == nil {
+				content, err := os.ReadFile(source.Source.Path)
+				if err != nil {
+					break
+				}
+				lines = strings.Split(string(content), "\n")
+			}
+
+			if pkg.Location != nil {
+				completeDeclaration(lines, pkg.Location, pkg.Package)
+			} else {
+				pkg.Location = findDeclaration(lines, pkg.Package)
+			}
+		}
+	}
+}
+
+// completeDeclaration extends a location that only knows the start of the
+// declaration of the package to cover its name if it follows on the same
+// line, or otherwise the rest of the line
+func completeDeclaration(lines []string, location *models.SourceLocation, pkg models.PackageInfo) {
+	if location.Line > len(lines) {
+		return
+	}
+
+	text := strings.TrimRight(lines[location.Line-1], "\r")
+	start := location.Column - 1
+	if start > len(text) {
+		return
+	}
+
+	if i := strings.Index(text[start:], pkg.Name); pkg.Name != "" && i != -1 {
+		location.Column = start + i + 1
+		location.EndColumn = location.Column + len(pkg.Name)
+
+		return
+	}
+
+	location.EndColumn = len(text) + 1
+}
+
+// findDeclaration returns the location of the name (or failing that, the
+// version) of the package on the line that pins it, or nil if there is none
+func findDeclaration(lines []string, pkg models.PackageInfo) *models.SourceLocation {
+	line := githistory.FindVersionLine(lines, pkg.Name, pkg.Version)
+	if line == 0 {
+		return nil
+	}
+
+	text := lines[line-1]
+	for _, needle := range []string{pkg.Name, pkg.Version} {
+		if i := strings.Index(text, needle); needle != "" && i != -1 {
+			return &models.SourceLocation{Line: line, Column: i + 1, EndColumn: i + 1 + len(needle)}
+		}
+	}
+
+	return &models.SourceLocation{Line: line, Column: 1, EndColumn: len(text) + 1}
+}
+
+// findPURL returns the location of the first occurrence of the given purl
+// within an SBOM, allowing for it being escaped as XML or by Go's JSON
+// encoder, or nil if it does not occur
+func findPURL(lines []string, purl string) *models.SourceLocation {
+	if purl == "" {
+		return nil
+	}
+
+	candidates := []string{
+		purl,
+		strings.ReplaceAll(purl, "&", "&amp;"),
+		strings.ReplaceAll(purl, "&", "\\u0026"),
+	}
+
+	for i, line := range lines {
+		for _, candidate := range candidates {
+			if j := strings.Index(line, candidate); j != -1 {
+				return &models.SourceLocation{Line: i + 1, Column: j + 1, EndColumn: j + 1 + len(candidate)}
+			}
+		}
+	}
+
+	return nil
+}
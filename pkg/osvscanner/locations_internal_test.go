@@ -0,0 +1,70 @@
+package osvscanner
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func Test_locateDeclarations(t *testing.T) {
+	t.Parallel()
+
+	known := &models.SourceLocation{Line: 3, Column: 1, EndColumn: 5}
+	results := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "../lockfile/fixtures/npm/one-package.v1.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{Package: models.PackageInfo{Name: "wrappy", Version: "1.0.2", Ecosystem: "npm"}},
+					{Package: models.PackageInfo{Name: "not-there", Version: "9.9.9", Ecosystem: "npm"}},
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "../lockfile/fixtures/pip/multiple-packages-mixed.txt", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{Package: models.PackageInfo{Name: "numpy", Version: "1.16.0", Ecosystem: "PyPI"}},
+					{Package: models.PackageInfo{Name: "flask", Version: "1.0.0", Ecosystem: "PyPI"}, Location: known},
+					{
+						Package:  models.PackageInfo{Name: "scikit-learn", Version: "0.20.1", Ecosystem: "PyPI"},
+						Location: &models.SourceLocation{Line: 5, Column: 1, EndColumn: 1},
+					},
+					{
+						Package:  models.PackageInfo{Name: "scikit_learn", Version: "0.20.1", Ecosystem: "PyPI"},
+						Location: &models.SourceLocation{Line: 5, Column: 1, EndColumn: 1},
+					},
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "../lockfile/fixtures/does-not-exist.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{Package: models.PackageInfo{Name: "wrappy", Version: "1.0.2", Ecosystem: "npm"}},
+				},
+			},
+		},
+	}
+
+	locateDeclarations(&results)
+
+	want := []*models.SourceLocation{
+		// the version is on a separate line to the name, so it is used instead
+		{Line: 6, Column: 19, EndColumn: 24},
+		nil,
+		{Line: 4, Column: 1, EndColumn: 6},
+		known,
+		// parsers only record where the declaration starts
+		{Line: 5, Column: 1, EndColumn: 13},
+		{Line: 5, Column: 1, EndColumn: 21},
+		nil,
+	}
+	var got []*models.SourceLocation
+	for _, source := range results.Results {
+		for _, pkg := range source.Packages {
+			got = append(got, pkg.Location)
+		}
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("locateDeclarations() mismatch (-want +got):\n%s", diff)
+	}
+}
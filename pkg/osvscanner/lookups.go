@@ -0,0 +1,138 @@
+package osvscanner
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/osv-scanner/pkg/config"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+// lookupTracker is shared by the checks that look something up for each
+// package as it is scanned, such as its registry or its licenses. It looks up
+// each key only once, even when many workers ask for it at the same time, and
+// collects the findings of the check along with how many lookups failed.
+type lookupTracker[K comparable, V any, F any] struct {
+	lookup func(key K) (V, error)
+	// sortFindings orders the findings, as they are found concurrently
+	sortFindings func(findings []F)
+
+	mu      sync.Mutex
+	lookups map[K]*pendingLookup[V]
+	seen    map[string]bool
+	found   []F
+	// failed counts the lookups that failed, with failedErr being the first
+	// error that was encountered
+	failed    int
+	failedErr error
+}
+
+// pendingLookup is the result of a lookup, which is available once done is
+// closed
+type pendingLookup[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+func newLookupTracker[K comparable, V any, F any](lookup func(key K) (V, error), sortFindings func(findings []F)) lookupTracker[K, V, F] {
+	return lookupTracker[K, V, F]{
+		lookup:       lookup,
+		sortFindings: sortFindings,
+		lookups:      map[K]*pendingLookup[V]{},
+		seen:         map[string]bool{},
+	}
+}
+
+// get returns the result of looking up the key, looking it up if it has not
+// been already, or waiting for the lookup if it is in progress. Each failed
+// call is counted, so that the packages that could not be checked are.
+func (t *lookupTracker[K, V, F]) get(key K) (V, error) {
+	t.mu.Lock()
+	l, ok := t.lookups[key]
+	if !ok {
+		l = &pendingLookup[V]{done: make(chan struct{})}
+		t.lookups[key] = l
+	}
+	t.mu.Unlock()
+
+	if ok {
+		<-l.done
+	} else {
+		l.value, l.err = t.lookup(key)
+		close(l.done)
+	}
+
+	if l.err != nil {
+		t.mu.Lock()
+		t.failed++
+		if t.failedErr == nil {
+			t.failedErr = l.err
+		}
+		t.mu.Unlock()
+	}
+
+	return l.value, l.err
+}
+
+// firstSeen reports if this is the first time that the package has been seen
+// in the source, so that packages listed more than once are checked once
+func (t *lookupTracker[K, V, F]) firstSeen(source models.SourceInfo, pkg models.PackageInfo) bool {
+	key := source.Path + "\x00" + pkg.Ecosystem + "\x00" + pkg.Name + "\x00" + pkg.Version
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	seen := t.seen[key]
+	t.seen[key] = true
+
+	return !seen
+}
+
+// add records a finding of the check
+func (t *lookupTracker[K, V, F]) add(finding F) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.found = append(t.found, finding)
+}
+
+// findings returns the findings of the check in order
+func (t *lookupTracker[K, V, F]) findings() []F {
+	found := append([]F{}, t.found...)
+	if t.sortFindings != nil {
+		t.sortFindings(found)
+	}
+
+	return found
+}
+
+// reportFailures prints how many lookups failed, if any did, using the format
+// which is given the count and the first error
+func (t *lookupTracker[K, V, F]) reportFailures(r output.Reporter, format string) {
+	if t.failed > 0 {
+		r.PrintText(fmt.Sprintf(format+"\n", t.failed, t.failedErr))
+	}
+}
+
+// queryPackage is the package of the query
+func queryPackage(query *osv.Query) models.PackageInfo {
+	return models.PackageInfo{
+		Name:      query.Package.Name,
+		Version:   query.Version,
+		Ecosystem: query.Package.Ecosystem,
+	}
+}
+
+// isInternalPackage returns a function reporting if the package of a query is
+// one of the internal packages of the config of its source, which are not
+// published on public registries
+func isInternalPackage(configManager *config.ConfigManager, r output.Reporter) func(query *osv.Query) bool {
+	return func(query *osv.Query) bool {
+		configToUse := configManager.Get(r, query.Source.Path)
+
+		return configToUse.IsInternalPackage(query.Package.Name)
+	}
+}
@@ -0,0 +1,85 @@
+package osvscanner
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+func Test_lookupTracker(t *testing.T) {
+	t.Parallel()
+
+	var lookups int32
+	tracker := newLookupTracker(func(key string) (int, error) {
+		atomic.AddInt32(&lookups, 1)
+
+		if key == "broken" {
+			return 0, errors.New("registry is down")
+		}
+
+		return len(key), nil
+	}, sort.Ints)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if value, err := tracker.get("left-pad"); err == nil {
+				tracker.add(value)
+			}
+			_, _ = tracker.get("broken")
+		}()
+	}
+	wg.Wait()
+	tracker.add(1)
+
+	// each key is only looked up once, however many times it is asked for
+	if got := atomic.LoadInt32(&lookups); got != 2 {
+		t.Errorf("expected 2 lookups, got %d", got)
+	}
+
+	// but every package that could not be checked is counted
+	if tracker.failed != 10 {
+		t.Errorf("expected 10 failures, got %d", tracker.failed)
+	}
+
+	want := []int{1, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8}
+	if diff := cmp.Diff(want, tracker.findings()); diff != "" {
+		t.Errorf("findings() mismatch (-want +got):\n%s", diff)
+	}
+
+	stdout := new(strings.Builder)
+	tracker.reportFailures(output.NewReporter(stdout, new(strings.Builder), ""), "Failed to check %d packages: %v")
+	if got := stdout.String(); got != "Failed to check 10 packages: registry is down\n" {
+		t.Errorf("unexpected report of the failures: %q", got)
+	}
+}
+
+func Test_lookupTracker_firstSeen(t *testing.T) {
+	t.Parallel()
+
+	tracker := newLookupTracker[string, int, int](nil, nil)
+
+	lockfileA := models.SourceInfo{Path: "/a/package-lock.json", Type: "lockfile"}
+	lockfileB := models.SourceInfo{Path: "/b/package-lock.json", Type: "lockfile"}
+	react := models.PackageInfo{Name: "react", Version: "18.2.0", Ecosystem: "npm"}
+
+	got := []bool{
+		tracker.firstSeen(lockfileA, react),
+		tracker.firstSeen(lockfileA, react),
+		tracker.firstSeen(lockfileB, react),
+	}
+
+	if diff := cmp.Diff([]bool{true, false, true}, got); diff != "" {
+		t.Errorf("firstSeen() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,146 @@
+package osvscanner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/osv-scanner/pkg/manifest"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+// manifestResolver resolves the dependencies of manifests that have not been
+// locked to the graphs of packages that deps.dev finds them to depend on,
+// remembering the graph of each version so that it is only fetched once
+type manifestResolver struct {
+	// ctx abandons the requests to deps.dev once it is done
+	ctx      context.Context
+	versions func(ctx context.Context, ecosystem string, name string) ([]string, error)
+	graph    func(ctx context.Context, ecosystem string, name string, version string) ([]*osv.Query, error)
+
+	mu     sync.Mutex
+	graphs map[string][]*osv.Query
+}
+
+func newManifestResolver(ctx context.Context) *manifestResolver {
+	return &manifestResolver{
+		ctx:      ctx,
+		versions: osv.FetchVersions,
+		graph:    osv.FetchDependencyGraph,
+		graphs:   map[string][]*osv.Query{},
+	}
+}
+
+// resolve returns the graph of the highest version that the dependency allows,
+// or nil if no such version is known
+func (m *manifestResolver) resolve(dependency manifest.Dependency) ([]*osv.Query, error) {
+	versions, err := m.versions(m.ctx, dependency.Ecosystem, dependency.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	version, ok := manifest.Resolve(dependency, versions)
+	if !ok {
+		return nil, nil
+	}
+
+	key := dependency.Ecosystem + "/" + dependency.Name + "@" + version
+	m.mu.Lock()
+	graph, ok := m.graphs[key]
+	m.mu.Unlock()
+	if ok {
+		return graph, nil
+	}
+
+	graph, err = m.graph(m.ctx, dependency.Ecosystem, dependency.Name, version)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	m.graphs[key] = graph
+	m.mu.Unlock()
+
+	return graph, nil
+}
+
+// scanManifest resolves the dependencies of the manifest at the given path,
+// which has no lockfile, and adds the packages of their graphs to `query`.
+// As the versions are resolved as they would be when installing afresh, they
+// only approximate what is installed.
+func scanManifest(r output.Reporter, query *osv.BatchedQuery, limits scanLimits, resolver *manifestResolver, path string) error {
+	if err := limits.checkFileSize(path); err != nil {
+		return err
+	}
+
+	dependencies, err := manifest.Parse(path)
+	if err != nil {
+		return err
+	}
+
+	source := models.SourceInfo{Path: path, Type: "manifest"}
+
+	var queries []*osv.Query
+	found := map[string]*osv.Query{}
+
+	for _, dependency := range dependencies {
+		graph, err := resolver.resolve(dependency)
+		if err != nil {
+			return fmt.Errorf("could not resolve %s: %w", dependency.Name, err)
+		}
+		if len(graph) == 0 {
+			r.PrintText(fmt.Sprintf("Could not resolve %s@%s of %s\n", dependency.Name, dependency.Requirement, path))
+
+			continue
+		}
+
+		scope := "prod"
+		if dependency.Dev {
+			scope = "dev"
+		}
+
+		for _, node := range graph {
+			key := node.Package.Ecosystem + "/" + node.Package.Name + "@" + node.Version
+
+			// packages depended on in more than one way are reported once, as
+			// direct and prod dependencies if they are either in any graph
+			if existing, ok := found[key]; ok {
+				if node.Metadata.Relation == "direct" {
+					existing.Metadata.Relation = "direct"
+				}
+				if scope == "prod" {
+					existing.Metadata.Scope = "prod"
+				}
+
+				continue
+			}
+
+			pkgQuery := &osv.Query{
+				Version: node.Version,
+				Package: node.Package,
+				Source:  source,
+				Metadata: &models.PackageMetadata{
+					Scope:         scope,
+					Relation:      node.Metadata.Relation,
+					DeclaringFile: path,
+				},
+			}
+			found[key] = pkgQuery
+			queries = append(queries, pkgQuery)
+		}
+	}
+
+	r.PrintText(fmt.Sprintf("Resolved %s with deps.dev and found %d packages\n", path, len(queries)))
+
+	if err := limits.checkPackages(len(queries)); err != nil {
+		return err
+	}
+	if err := limits.checkMemory(); err != nil {
+		return err
+	}
+
+	query.Queries = append(query.Queries, queries...)
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package osvscanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+func Test_scanManifest(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "package.json")
+	content := `{"dependencies": {"express": "^4.17.0", "ghost": "^1.0.0"}, "devDependencies": {"body-parser": "~1.20.0"}}`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("could not write the manifest: %v", err)
+	}
+
+	node := func(name string, version string, relation string) *osv.Query {
+		return &osv.Query{
+			Version:  version,
+			Package:  osv.Package{Name: name, Ecosystem: "npm"},
+			Metadata: &models.PackageMetadata{Relation: relation},
+		}
+	}
+
+	graphs := 0
+	resolver := &manifestResolver{
+		ctx: context.Background(),
+		versions: func(_ context.Context, ecosystem string, name string) ([]string, error) {
+			switch name {
+			case "express":
+				return []string{"4.17.1", "4.18.2", "5.0.0"}, nil
+			case "body-parser":
+				return []string{"1.20.1", "1.20.2"}, nil
+			}
+
+			return nil, nil
+		},
+		graph: func(_ context.Context, ecosystem string, name string, version string) ([]*osv.Query, error) {
+			graphs++
+
+			switch name + "@" + version {
+			case "express@4.18.2":
+				return []*osv.Query{node("express", "4.18.2", "direct"), node("body-parser", "1.20.1", "transitive"), node("bytes", "3.1.2", "transitive")}, nil
+			case "body-parser@1.20.2":
+				return []*osv.Query{node("body-parser", "1.20.2", "direct"), node("bytes", "3.1.2", "transitive")}, nil
+			}
+
+			return nil, nil
+		},
+		graphs: map[string][]*osv.Query{},
+	}
+
+	var query osv.BatchedQuery
+	if err := scanManifest(output.NewVoidReporter(), &query, scanLimits{}, resolver, path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	source := models.SourceInfo{Path: path, Type: "manifest"}
+	pkg := func(name string, version string, scope string, relation string) *osv.Query {
+		return &osv.Query{
+			Version:  version,
+			Package:  osv.Package{Name: name, Ecosystem: "npm"},
+			Source:   source,
+			Metadata: &models.PackageMetadata{Scope: scope, Relation: relation, DeclaringFile: path},
+		}
+	}
+
+	want := []*osv.Query{
+		pkg("express", "4.18.2", "prod", "direct"),
+		pkg("body-parser", "1.20.1", "prod", "transitive"),
+		// packages in the graphs of both prod and dev dependencies are prod
+		pkg("bytes", "3.1.2", "prod", "transitive"),
+		pkg("body-parser", "1.20.2", "dev", "direct"),
+	}
+
+	if diff := cmp.Diff(want, query.Queries); diff != "" {
+		t.Errorf("scanManifest() mismatch (-want +got):\n%s", diff)
+	}
+
+	if graphs != 2 {
+		t.Errorf("expected 2 graphs to be fetched, got %d", graphs)
+	}
+}
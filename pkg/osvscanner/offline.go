@@ -0,0 +1,31 @@
+package osvscanner
+
+import (
+	"github.com/google/osv-scanner/pkg/osv"
+)
+
+// offlineQueries returns the batch with its queries of purls replaced by
+// queries of the packages they identify, as osv.LocalDatabase can only match
+// queries of packages
+func offlineQueries(batch osv.BatchedQuery) osv.BatchedQuery {
+	queries := make([]*osv.Query, len(batch.Queries))
+
+	for i, query := range batch.Queries {
+		queries[i] = query
+		if query.Package.PURL == "" {
+			continue
+		}
+
+		pkg, err := PURLToPackage(query.Package.PURL)
+		if err != nil {
+			continue
+		}
+
+		converted := *query
+		converted.Package = osv.Package{Name: pkg.Name, Ecosystem: pkg.Ecosystem}
+		converted.Version = pkg.Version
+		queries[i] = &converted
+	}
+
+	return osv.BatchedQuery{Queries: queries}
+}
@@ -2,16 +2,22 @@ package osvscanner
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime/debug"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/google/osv-scanner/internal/sbom"
 	"github.com/google/osv-scanner/pkg/config"
 	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/manifest"
 	"github.com/google/osv-scanner/pkg/models"
 	"github.com/google/osv-scanner/pkg/osv"
 	"github.com/google/osv-scanner/pkg/output"
@@ -21,9 +27,28 @@ import (
 	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
 )
 
+// The policies for git repositories that are found nested within the
+// directories being scanned
+const (
+	// NestedReposScan queries the commit of nested repositories and scans
+	// their files as part of the directory
+	NestedReposScan = "scan"
+	// NestedReposSkip neither queries nor scans nested repositories
+	NestedReposSkip = "skip"
+	// NestedReposBoundary treats nested repositories as submodules, querying
+	// their commit without scanning their files
+	NestedReposBoundary = "boundary"
+)
+
+// NestedRepoPolicies are the policies for nested git repositories
+var NestedRepoPolicies = []string{NestedReposScan, NestedReposSkip, NestedReposBoundary}
+
 type ScannerActions struct {
-	LockfilePaths        []string
-	SBOMPaths            []string
+	LockfilePaths []string
+	SBOMPaths     []string
+	// ArtifactPaths are build outputs such as jars, wheels and binaries, which
+	// are matched to the packages they are a release of by their hash
+	ArtifactPaths        []string
 	DirectoryPaths       []string
 	GitCommits           []string
 	Recursive            bool
@@ -31,6 +56,186 @@ type ScannerActions struct {
 	NoIgnore             bool
 	DockerContainerNames []string
 	ConfigOverridePath   string
+	// NestedRepos is the policy for git repositories nested within the
+	// directories being scanned, defaulting to NestedReposScan
+	NestedRepos string
+	// HostRoot is the root of a filesystem whose operating system, kernel and
+	// OS packages are audited, which is "/" for the host being run on
+	HostRoot string
+	// AllowPartialResults returns the results obtained so far when the OSV API
+	// fails part way through, marking the affected sources as incomplete
+	AllowPartialResults bool
+	// Strict turns inputs that could not be parsed or scanned into a scan
+	// failure, rather than reporting them and continuing
+	Strict bool
+	// GitHubDismissalsRepository is an "owner/repo" whose dismissed Dependabot
+	// and code scanning alerts should be treated as ignored
+	GitHubDismissalsRepository string
+	// TargetsPath is a YAML file listing targets to scan, each with their own
+	// inputs and options, which cannot be combined with other inputs
+	TargetsPath string
+	// GitHubOrganization is a GitHub organization whose repositories should
+	// each be cloned and scanned, which cannot be combined with other inputs
+	GitHubOrganization string
+	// GitCredentialsPath is a YAML file describing how to authenticate with
+	// each git host when cloning remote repositories
+	GitCredentialsPath string
+	// Blame annotates vulnerable packages with the commit that last changed
+	// the line of their lockfile that pins them
+	Blame bool
+	// History reports when each vulnerability of the packages of lockfiles
+	// that are committed to git first affected them, by scanning the
+	// lockfiles at each tag of their history
+	History bool
+	// HistoryEvery scans the history at every nth commit rather than at each
+	// tag, when it is more than 0
+	HistoryEvery int
+	// MaxFileSize is the size in bytes of the largest lockfile or SBOM that
+	// will be parsed, with larger files being skipped
+	MaxFileSize int64
+	// MaxPackagesPerSource is the most packages that will be queried from a
+	// single lockfile or SBOM, with sources that have more being skipped
+	MaxPackagesPerSource int
+	// MemoryBudget is the number of bytes of memory the scan aims to stay
+	// within, with sources that take it beyond the budget being skipped
+	MemoryBudget int64
+	// RequestWorkers is how many requests to the OSV API are sent at the same
+	// time when querying for vulnerabilities, defaulting to
+	// osv.DefaultRequestWorkers
+	RequestWorkers int
+	// ParseWorkers is how many files are scanned at the same time when walking
+	// directories, defaulting to the number of CPUs
+	ParseWorkers int
+	// Profile records how long each phase of the scan takes if it is not nil,
+	// with the time spent on each source being included in the results unless
+	// they are to be reproducible
+	Profile *Profile
+	// VerifyRegistry checks that each package of a lockfile exists on its
+	// public registry with the hash that the lockfile records, if any
+	VerifyRegistry bool
+	// CheckOutdated reports the packages of lockfiles and SBOMs that are
+	// behind the latest version on their public registry
+	CheckOutdated bool
+	// CheckDeprecated reports the packages of lockfiles and SBOMs that have
+	// been deprecated, yanked or are unmaintained according to their registry
+	CheckDeprecated bool
+	// FetchScorecards includes the OpenSSF Scorecards of the direct
+	// dependencies of lockfiles in the results, as found by deps.dev
+	FetchScorecards bool
+	// AnalyzeUpgrades reports what upgrading each vulnerable package to the
+	// version that fixes it involves, including how the packages it depends
+	// on change as resolved by deps.dev
+	AnalyzeUpgrades bool
+	// CheckLicenses looks up the licenses of the packages of lockfiles and
+	// SBOMs with deps.dev, reporting those whose licenses are not allowed by
+	// the license policy of their config
+	CheckLicenses bool
+	// CallAnalysis reports whether the code of each Go module that is scanned
+	// calls the vulnerable symbols of the vulnerabilities of its packages, or
+	// only imports the packages that they affect
+	CallAnalysis bool
+	// FindSymbolUsages searches the code around each lockfile and manifest
+	// for calls of the symbols that the vulnerabilities of its packages
+	// affect, reporting where they appear to be used
+	FindSymbolUsages bool
+	// ResolveManifests scans the manifests of directories that have not been
+	// locked, such as a package.json without a package-lock.json, by having
+	// deps.dev resolve the packages their dependencies depend on
+	ResolveManifests bool
+	// Platform is the platform that is deployed to, such as "x86_64-linux",
+	// with builds of packages for other platforms not being scanned
+	Platform string
+	// ExcludedGroups are the groups of manifests, such as the groups of a
+	// Gemfile, whose packages are not scanned unless they are also in another
+	ExcludedGroups []string
+	// PHPVersion is the version of PHP that is deployed to, which the PHP
+	// requirements of Composer packages are checked against
+	PHPVersion string
+	// PHPExtensions are the PHP extensions that are installed where the
+	// packages are deployed to, which is not checked if there are none
+	PHPExtensions []string
+	// DetectTyposquats reports the packages of lockfiles whose names are
+	// likely typosquats of popular packages
+	DetectTyposquats bool
+	// Reproducible sorts the results and makes their paths relative to the
+	// working directory, so that scans of the same inputs are identical
+	Reproducible bool
+	// FailOnScore only fails the scan for vulnerabilities whose effective
+	// scores are at least this, or are not known, with 0 failing the scan for
+	// any vulnerability
+	FailOnScore float64
+	// FailOnSeverity only fails the scan for vulnerabilities whose effective
+	// severities are at least this, or are not known, as parsed by
+	// ParseSeverity, unless the config of their source sets its own
+	FailOnSeverity string
+	// FailOnlyIfFixAvailable only fails the scan for vulnerabilities that have
+	// been fixed in a later version of their package, allowing those that
+	// cannot be fixed yet to be shipped
+	FailOnlyIfFixAvailable bool
+	// GracePeriods are how many days after their advisories are published
+	// that vulnerabilities of each severity start failing the scan, as parsed
+	// by ParseGracePeriods
+	GracePeriods map[string]int
+	// CollectInventory includes every package that is found in the results,
+	// such as for writing an SBOM of them, rather than only the vulnerable ones
+	CollectInventory bool
+	// InventoryOnly finds and parses the inputs as usual, but lists the
+	// packages that were found in the inventory of the results instead of
+	// querying them for vulnerabilities or checking them in any other way
+	InventoryOnly bool
+	// ParserOverrides parse the files that match them as a lockfile when they
+	// are found while scanning directories, as parsed by ParseParserOverrides,
+	// before any that the config of each file sets
+	ParserOverrides []config.ParseAsEntry
+	// DryRun finds the inputs that would be scanned, and those that would be
+	// skipped, and lists them in the plan of the results without parsing,
+	// querying or checking any of them
+	DryRun bool
+	// CommitCachePath is a file to cache the vulnerabilities of each git commit
+	// that is queried in, so that they are not queried again by later scans
+	// until they have been cached for longer than CommitCacheTTL, if it is set
+	CommitCachePath string
+	CommitCacheTTL  time.Duration
+	// QueryCachePath is a directory to cache the vulnerabilities of each
+	// version of a package that is queried in, so that they are not queried
+	// again by later scans until they have been cached for longer than
+	// QueryCacheTTL, if it is set
+	QueryCachePath string
+	QueryCacheTTL  time.Duration
+	// AdvisoryBundlePath is a file, such as a .osv-scan-cache at the root of a
+	// repository, to keep the full details of the vulnerabilities that are
+	// found in, so that later scans only fetch them again once they have been
+	// modified, or when that is not known, once they have been kept for longer
+	// than AdvisoryBundleTTL, if it is set
+	AdvisoryBundlePath string
+	AdvisoryBundleTTL  time.Duration
+	// BaselinePath is the JSON results of a previous scan, such as of a legacy
+	// codebase with existing findings, whose vulnerabilities are left out of
+	// the results so that only new vulnerabilities are reported and can fail
+	// the scan
+	BaselinePath string
+	// IncludeWithdrawn keeps the advisories that have been withdrawn in the
+	// results, marking the groups of them that are all withdrawn, which never
+	// fail the scan
+	IncludeWithdrawn bool
+	// OfflineDatabasePath is a directory of exports of the OSV database to
+	// match packages against instead of querying the API, as downloaded by
+	// osv.LocalDatabase
+	OfflineDatabasePath string
+	// DownloadOfflineDatabases downloads the exports of the ecosystems that
+	// are missing from OfflineDatabasePath, rather than failing to match the
+	// packages of them
+	DownloadOfflineDatabases bool
+}
+
+// scanIssues collects the inputs that could not be fully scanned
+type scanIssues struct {
+	parseFailures []models.ParseFailure
+	skipped       []models.SkippedSource
+}
+
+func (issues *scanIssues) skip(source models.SourceInfo, reason string) {
+	issues.skipped = append(issues.skipped, models.SkippedSource{Source: source, Reason: reason})
 }
 
 // NoPackagesFoundErr for when no packages is found during a scan.
@@ -43,40 +248,145 @@ var VulnerabilitiesFoundErr = errors.New("vulnerabilities found")
 
 // scanDir walks through the given directory to try to find any relevant files
 // These include:
-//   - Any lockfiles with scanLockfile
+//   - Any lockfiles with scanLockfile, parsed as `overrides` says if they
+//     match one of them
 //   - Any SBOM files with scanSBOMFile
 //   - Any git repositories with scanGit
-func scanDir(r *output.Reporter, query *osv.BatchedQuery, dir string, skipGit bool, recursive bool, useGitIgnore bool) error {
+//   - Any manifests without lockfiles with scanManifest, if `resolver` is set
+//
+// Lockfiles that fail to parse are added to `issues`, unless `strict` is set
+// in which case the walk is stopped with the error.
+//
+// Git repositories nested within subdirectories are handled according to the
+// `nestedRepos` policy, with the repository of the directory itself always
+// being queried unless `skipGit` is set.
+//
+// Files are scanned by `workers` workers as the walk continues, defaulting to
+// the number of CPUs, with the results of each being added in the order they
+// were found so that they are the same however many workers there are.
+func scanDir(r output.Reporter, stream *queryStream, issues *scanIssues, limits scanLimits, scope packageScope, profile *Profile, resolver *manifestResolver, overrides *parserOverrides, dir string, skipGit bool, nestedRepos string, recursive bool, useGitIgnore bool, strict bool, workers int) error {
 	var ignoreMatcher *gitIgnoreMatcher
 	if useGitIgnore {
 		var err error
 		ignoreMatcher, err = parseGitIgnores(dir)
 		if err != nil {
+			// the walk carries on without ignoring anything
 			r.PrintError(fmt.Sprintf("Unable to parse git ignores: %v", err))
-			useGitIgnore = false
 		}
 	}
 
+	pipeline := newDirPipeline(r, workers)
+
+	// the walk only reports through the work it submits, as the results of
+	// earlier work are being reported meanwhile
+	report := func(print func(r output.Reporter)) {
+		_ = pipeline.submit(func(r output.Reporter, _ *osv.BatchedQuery, _ *scanIssues) error {
+			print(r)
+
+			return nil
+		})
+	}
+
+	walk := func() error {
+		// time spent scanning files is recorded against each parser, leaving
+		// the rest of the time spent walking as walking
+		// record the phase up front so that it is reported before the parsing
+		// that is done meanwhile
+		profile.Add("walking", 0)
+		start := time.Now()
+		defer func() {
+			profile.Add("walking", time.Since(start)-pipeline.blocked)
+		}()
+
+		return walkDir(dir, ignoreMatcher, skipGit, nestedRepos, recursive, report, func(found walkFound) error {
+			switch found.kind {
+			case foundGit:
+				return submitGit(pipeline, found.repoDir, found.path)
+			case foundFile:
+				return pipeline.submit(func(r output.Reporter, query *osv.BatchedQuery, issues *scanIssues) error {
+					return scanDirFile(r, query, issues, limits, scope, profile, resolver, found.path, overrides.parseAs(dir, found.path), strict)
+				})
+			}
+
+			return nil
+		})
+	}
+
+	return pipeline.run(walk, func(job *dirJob) error {
+		job.r.Replay(r)
+		stream.pending.Queries = append(stream.pending.Queries, job.query.Queries...)
+		issues.parseFailures = append(issues.parseFailures, job.issues.parseFailures...)
+		issues.skipped = append(issues.skipped, job.issues.skipped...)
+
+		if job.err != nil {
+			return job.err
+		}
+
+		return stream.flush(false)
+	})
+}
+
+// The kinds of paths that walking a directory finds
+const (
+	// foundFile is a file to scan
+	foundFile = "file"
+	// foundGit is a git repository to query the commit of
+	foundGit = "git"
+	// foundSkipped is a file or directory that is not scanned
+	foundSkipped = "skipped"
+)
+
+// The reasons that paths are skipped when walking a directory
+const (
+	skippedIgnored    = "ignored by git"
+	skippedNestedRepo = "nested git repository"
+)
+
+// walkFound is a path that walking a directory found
+type walkFound struct {
+	kind  string
+	path  string
+	isDir bool
+	// repoDir is the directory of a git repository, ending with a separator
+	repoDir string
+	// reason is why a path is skipped
+	reason string
+}
+
+// walkDir walks through the given directory as scanDir does, calling visit
+// with each file to scan, each git repository to query and each file or
+// directory that is skipped, in the order they are found. Problems with the
+// walk are printed with report.
+func walkDir(dir string, ignoreMatcher *gitIgnoreMatcher, skipGit bool, nestedRepos string, recursive bool, report func(print func(r output.Reporter)), visit func(found walkFound) error) error {
 	root := true
 
 	return filepath.WalkDir(dir, func(path string, info os.DirEntry, err error) error {
 		if err != nil {
-			r.PrintText(fmt.Sprintf("Failed to walk %s: %v\n", path, err))
+			report(func(r output.Reporter) {
+				r.PrintText(fmt.Sprintf("Failed to walk %s: %v\n", path, err))
+			})
 			return err
 		}
 
 		path, err = filepath.Abs(path)
 		if err != nil {
-			r.PrintError(fmt.Sprintf("Failed to walk path %s\n", err))
+			report(func(r output.Reporter) {
+				r.PrintError(fmt.Sprintf("Failed to walk path %s\n", err))
+			})
 			return err
 		}
 
-		if useGitIgnore {
+		if ignoreMatcher != nil {
 			match, err := ignoreMatcher.match(path, info.IsDir())
 			if err != nil {
-				r.PrintText(fmt.Sprintf("Failed to resolve gitignore for %s: %v", path, err))
+				report(func(r output.Reporter) {
+					r.PrintText(fmt.Sprintf("Failed to resolve gitignore for %s: %v", path, err))
+				})
 				// Don't skip if we can't parse now - potentially noisy for directories with lots of items
 			} else if match {
+				if err := visit(walkFound{kind: foundSkipped, path: path, isDir: info.IsDir(), reason: skippedIgnored}); err != nil {
+					return err
+				}
 				if info.IsDir() {
 					return filepath.SkipDir
 				}
@@ -85,27 +395,51 @@ func scanDir(r *output.Reporter, query *osv.BatchedQuery, dir string, skipGit bo
 			}
 		}
 
-		if !skipGit && info.IsDir() && info.Name() == ".git" {
-			err := scanGit(r, query, filepath.Dir(path)+"/")
-			if err != nil {
-				r.PrintText(fmt.Sprintf("scan failed for git repository, %s: %v\n", path, err))
-				// Not fatal, so don't return and continue scanning other files
+		// nested repositories are scanned like any other directory by
+		// default, without needing to check every directory for them
+		if !root && recursive && info.IsDir() && info.Name() != ".git" && nestedRepos != "" && nestedRepos != NestedReposScan {
+			if _, err := os.Lstat(filepath.Join(path, ".git")); err == nil || isBareRepository(path) {
+				if nestedRepos == NestedReposBoundary && !skipGit {
+					if err := visit(walkFound{kind: foundGit, path: path, repoDir: path + "/"}); err != nil {
+						return err
+					}
+				}
+				if err := visit(walkFound{kind: foundSkipped, path: path, isDir: true, reason: skippedNestedRepo}); err != nil {
+					return err
+				}
+
+				return filepath.SkipDir
+			}
+		}
+
+		// linked worktrees and submodules have a .git file pointing to
+		// where their repository is, rather than a .git directory
+		if !skipGit && info.Name() == ".git" {
+			if err := visit(walkFound{kind: foundGit, path: path, repoDir: filepath.Dir(path) + "/"}); err != nil {
+				return err
+			}
+
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		// bare repositories have no worktree to scan, but are still at
+		// a commit, and their objects should not be walked
+		if !skipGit && info.IsDir() && isBareRepository(path) {
+			if err := visit(walkFound{kind: foundGit, path: path, repoDir: path + "/"}); err != nil {
+				return err
 			}
 
 			return filepath.SkipDir
 		}
 
 		if !info.IsDir() {
-			if parser, _ := lockfile.FindParser(path, ""); parser != nil {
-				err := scanLockfile(r, query, path, "")
-				if err != nil {
-					r.PrintError(fmt.Sprintf("Attempted to scan lockfile but failed: %s\n", path))
-				}
+			if err := visit(walkFound{kind: foundFile, path: path}); err != nil {
+				return err
 			}
-			// No need to check for error
-			// If scan fails, it means it isn't a valid SBOM file,
-			// so just move onto the next file
-			_ = scanSBOMFile(r, query, path)
 		}
 
 		if !root && !recursive && info.IsDir() {
@@ -117,6 +451,63 @@ func scanDir(r *output.Reporter, query *osv.BatchedQuery, dir string, skipGit bo
 	})
 }
 
+// submitGit submits the scanning of the git repository at repoDir, which was
+// found at path, being skipped with a reason if it cannot be scanned
+func submitGit(pipeline *dirPipeline, repoDir string, path string) error {
+	return pipeline.submit(func(r output.Reporter, query *osv.BatchedQuery, issues *scanIssues) error {
+		err := scanGit(r, query, repoDir)
+		if err != nil {
+			r.PrintText(fmt.Sprintf("scan failed for git repository, %s: %v\n", path, err))
+			// Not fatal, so don't return and continue scanning other files
+			issues.skip(models.SourceInfo{Path: repoDir, Type: "git"}, err.Error())
+		}
+
+		return nil
+	})
+}
+
+// scanDirFile scans a file found while walking a directory as a lockfile, a
+// manifest, an SBOM and a Go binary, in case it is any of them, parsing it as whichever of
+// the lockfiles named by parseAs it best matches if any are given
+func scanDirFile(r output.Reporter, query *osv.BatchedQuery, issues *scanIssues, limits scanLimits, scope packageScope, profile *Profile, resolver *manifestResolver, path string, parseAs []string, strict bool) error {
+	if isLockfile, parsedAs := findDirFileParser(path, parseAs); isLockfile {
+		done := trackParse(r, profile, query, parsedAs, path)
+		err := scanLockfile(r, query, limits, scope, path, parseAs...)
+		done()
+		if errors.Is(err, ErrLimitExceeded) {
+			r.PrintText(fmt.Sprintf("Skipping %s: %v\n", path, err))
+			issues.skip(models.SourceInfo{Path: path, Type: "lockfile"}, err.Error())
+		} else if err != nil {
+			if strict {
+				return &LockfileParseError{Path: path, Err: err}
+			}
+			r.PrintError(fmt.Sprintf("Attempted to scan lockfile but failed: %s\n", path))
+			issues.parseFailures = append(issues.parseFailures, models.ParseFailure{Path: path, Parser: parsedAs, Error: err.Error()})
+		}
+	}
+	if resolver != nil && manifest.IsManifest(path) && manifest.FindLockfile(path) == "" {
+		done := trackParse(r, profile, query, "manifest", path)
+		err := scanManifest(r, query, limits, resolver, path)
+		done()
+		if err != nil {
+			r.PrintText(fmt.Sprintf("Skipping %s: %v\n", path, err))
+			issues.skip(models.SourceInfo{Path: path, Type: "manifest"}, err.Error())
+		}
+	}
+	// No need to check for error
+	// If scan fails, it means it isn't a valid SBOM file,
+	// so just move onto the next file
+	done := trackParse(r, profile, query, "sbom", path)
+	_ = scanSBOMFile(r, query, issues, limits, path)
+	done()
+	// Likewise for files that are not Go binaries
+	done = trackParse(r, profile, query, "go-binary", path)
+	_ = scanGoBinary(r, query, issues, limits, path)
+	done()
+
+	return nil
+}
+
 type gitIgnoreMatcher struct {
 	matcher  gitignore.Matcher
 	repoPath string
@@ -124,20 +515,36 @@ type gitIgnoreMatcher struct {
 
 func parseGitIgnores(dir string) (*gitIgnoreMatcher, error) {
 	// We need to parse .gitignore files from the root of the git repo to correctly identify ignored files
-	// Defaults to current directory if dir is not in a repo or some other error
-	// TODO: Won't parse ignores if dir is not in a git repo, and is not under the current directory (e.g ../path/to)
-	fs := osfs.New(".")
-	if repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true}); err == nil {
-		if tree, err := repo.Worktree(); err == nil {
+	// Defaults to the scanned directory if it is not in a repo or some other error, so that
+	// its own .gitignore files are used wherever it is relative to the current directory
+	root := dir
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		root = filepath.Dir(dir)
+	}
+	fs := osfs.New(root)
+	var patterns []gitignore.Pattern
+	if repo, err := openRepository(dir, true); err == nil {
+		tree, err := repo.Worktree()
+		switch {
+		case err == nil:
 			fs = tree.Filesystem
+			// git also ignores what is excluded by the user's global ignore
+			// file and the info/exclude file of the repository
+			patterns, err = readRepositoryExcludes(repo, fs.Root())
+			if err != nil {
+				return nil, err
+			}
+		case errors.Is(err, git.ErrIsBareRepository):
+			// bare repositories have no ignores of their own, so only those
+			// of the directory are used
 		}
 	}
 
-	patterns, err := gitignore.ReadPatterns(fs, []string{"."})
+	local, err := gitignore.ReadPatterns(fs, []string{"."})
 	if err != nil {
 		return nil, err
 	}
-	matcher := gitignore.NewMatcher(patterns)
+	matcher := gitignore.NewMatcher(append(patterns, local...))
 	path, err := filepath.Abs(fs.Root())
 	if err != nil {
 		return nil, err
@@ -159,19 +566,37 @@ func (m *gitIgnoreMatcher) match(absPath string, isDir bool) (bool, error) {
 	return m.matcher.Match(pathInGitSep, isDir), nil
 }
 
+// findDirFileParser reports if a file found while walking a directory is a
+// lockfile, by it either having a name that a parser is for or being given
+// parsers to parse it with, along with the parsers it would be parsed with
+func findDirFileParser(path string, parseAs []string) (bool, string) {
+	if len(parseAs) > 0 {
+		return true, strings.Join(parseAs, "|")
+	}
+
+	parser, parsedAs := lockfile.FindParser(path, "")
+
+	return parser != nil, parsedAs
+}
+
 // scanLockfile will load, identify, and parse the lockfile path passed in, and add the dependencies specified
-// within to `query`
-func scanLockfile(r *output.Reporter, query *osv.BatchedQuery, path string, parseAs string) error {
-	var err error
+// within to `query`. When several parsers are given with `parseAs`, the
+// lockfile is parsed with the one it best matches.
+func scanLockfile(r output.Reporter, query *osv.BatchedQuery, limits scanLimits, scope packageScope, path string, parseAs ...string) error {
 	var parsedLockfile lockfile.Lockfile
 
+	err := limits.checkFileSize(path)
+	if err != nil {
+		return err
+	}
+
 	// special case for the APK parser because it has a very generic name while
 	// living at a specific location, so it's not included in the map of parsers
 	// used by lockfile.Parse to avoid false-positives when scanning projects
-	if parseAs == "apk-installed" {
+	if len(parseAs) == 1 && parseAs[0] == "apk-installed" {
 		parsedLockfile, err = lockfile.FromApkInstalled(path)
 	} else {
-		parsedLockfile, err = lockfile.Parse(path, parseAs)
+		parsedLockfile, err = lockfile.ParseBest(path, parseAs)
 	}
 
 	if err != nil {
@@ -179,32 +604,83 @@ func scanLockfile(r *output.Reporter, query *osv.BatchedQuery, path string, pars
 	}
 	parsedAsComment := ""
 
-	if parseAs != "" {
-		parsedAsComment = fmt.Sprintf("as a %s ", parseAs)
+	if len(parseAs) > 0 && parseAs[0] != "" {
+		parsedAsComment = fmt.Sprintf("as a %s ", parsedLockfile.ParsedAs)
 	}
 
 	r.PrintText(fmt.Sprintf("Scanned %s file %sand found %d packages\n", path, parsedAsComment, len(parsedLockfile.Packages)))
 
-	for _, pkgDetail := range parsedLockfile.Packages {
+	packages := scope.filter(parsedLockfile.Packages)
+	if skipped := len(parsedLockfile.Packages) - len(packages); skipped > 0 {
+		r.PrintText(fmt.Sprintf("Skipped %d packages of %s that are for other platforms or in excluded groups\n", skipped, path))
+	}
+	for _, pkg := range packages {
+		if unmet := scope.unmetPlatformRequirements(pkg); len(unmet) > 0 {
+			r.PrintText(fmt.Sprintf("%s@%s of %s requires %s, which the platform does not provide\n", pkg.Name, pkg.Version, path, strings.Join(unmet, " and ")))
+		}
+	}
+
+	if err := limits.checkPackages(len(packages)); err != nil {
+		return err
+	}
+	if err := limits.checkMemory(); err != nil {
+		return err
+	}
+
+	for _, pkgDetail := range packages {
 		pkgDetailQuery := osv.MakePkgRequest(pkgDetail)
+		if pkgDetail.Ecosystem == lockfile.CargoEcosystem && pkgDetail.Commit != "" {
+			// crates from git repositories are not releases on crates.io, so
+			// they are checked by the commit they were locked to instead
+			pkgDetailQuery = osv.MakeCommitRequest(pkgDetail.Commit)
+			pkgDetailQuery.PackageName = pkgDetail.Name
+		}
 		pkgDetailQuery.Source = models.SourceInfo{
 			Path: path,
 			Type: "lockfile",
 		}
+		if pkgDetail.Line > 0 {
+			pkgDetailQuery.Location = declarationLocation(pkgDetail)
+		}
+		if pkgDetail.Metadata != nil {
+			pkgDetailQuery.Metadata = packageMetadata(*pkgDetail.Metadata)
+		}
 		query.Queries = append(query.Queries, pkgDetailQuery)
 	}
 
 	return nil
 }
 
+// packageMetadata converts the metadata recorded by a lockfile parser
+func packageMetadata(metadata lockfile.PackageMetadata) *models.PackageMetadata {
+	return &models.PackageMetadata{
+		Scope:                string(metadata.Scope),
+		Relation:             string(metadata.Relation),
+		Resolved:             metadata.Resolved,
+		Integrity:            metadata.Integrity,
+		DeclaringFile:        metadata.DeclaringFile,
+		Platform:             metadata.Platform,
+		Groups:               metadata.Groups,
+		PlatformRequirements: metadata.PlatformRequirements,
+	}
+}
+
 // scanSBOMFile will load, identify, and parse the SBOM path passed in, and add the dependencies specified
 // within to `query`
-func scanSBOMFile(r *output.Reporter, query *osv.BatchedQuery, path string) error {
-	file, err := os.Open(path)
+//
+// Packages with an ecosystem that is not known to OSV are recorded in `issues`,
+// as are SBOMs that exceed the package or memory limits
+func scanSBOMFile(r output.Reporter, query *osv.BatchedQuery, issues *scanIssues, limits scanLimits, path string) error {
+	if err := limits.checkFileSize(path); err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(path)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
+	file := bytes.NewReader(content)
+	lines := strings.Split(string(content), "\n")
 
 	for _, provider := range sbom.Providers {
 		if provider.Name() == "SPDX" &&
@@ -214,12 +690,23 @@ func scanSBOMFile(r *output.Reporter, query *osv.BatchedQuery, path string) erro
 			// Skip if this isn't the case to avoid panics
 			continue
 		}
+		start := len(query.Queries)
 		count := 0
+		var unknown []string
+		source := models.SourceInfo{
+			Path: path,
+			Type: "sbom",
+		}
 		err := provider.GetPackages(file, func(id sbom.Identifier) error {
+			if !isKnownPURLEcosystem(id.PURL) {
+				unknown = append(unknown, id.PURL)
+			}
 			purlQuery := osv.MakePURLRequest(id.PURL)
-			purlQuery.Source = models.SourceInfo{
-				Path: path,
-				Type: "sbom",
+			purlQuery.Source = source
+			purlQuery.Location = findPURL(lines, id.PURL)
+			purlQuery.Component = &models.SBOMComponent{
+				BOMRef: id.BOMRef,
+				Index:  id.Index,
 			}
 			query.Queries = append(query.Queries, purlQuery)
 			count++
@@ -229,6 +716,25 @@ func scanSBOMFile(r *output.Reporter, query *osv.BatchedQuery, path string) erro
 		if err == nil {
 			// Found the right format.
 			r.PrintText(fmt.Sprintf("Scanned %s SBOM and found %d packages\n", provider.Name(), count))
+
+			limitErr := limits.checkPackages(count)
+			if limitErr == nil {
+				limitErr = limits.checkMemory()
+			}
+			if limitErr != nil {
+				query.Queries = query.Queries[:start]
+				r.PrintText(fmt.Sprintf("Skipping %s: %v\n", path, limitErr))
+				issues.skip(source, limitErr.Error())
+
+				return nil
+			}
+
+			// the SBOM is recorded once, however many of its packages are unknown
+			if len(unknown) > 0 {
+				r.PrintText(fmt.Sprintf("%d packages in %s have an ecosystem that is not known to OSV\n", len(unknown), path))
+				issues.skip(source, fmt.Sprintf("unknown ecosystem for %s", strings.Join(unknown, ", ")))
+			}
+
 			return nil
 		}
 
@@ -242,8 +748,122 @@ func scanSBOMFile(r *output.Reporter, query *osv.BatchedQuery, path string) erro
 	return nil
 }
 
+// scanGoBinary reads the build info embedded in the Go binary at the given
+// path, and adds the modules it was built from, along with the standard
+// library of the Go version it was built with, to `query`
+//
+// Binaries that exceed the package or memory limits are recorded in `issues`
+func scanGoBinary(r output.Reporter, query *osv.BatchedQuery, issues *scanIssues, limits scanLimits, path string) error {
+	if err := limits.checkFileSize(path); err != nil {
+		return err
+	}
+
+	binary, err := lockfile.FromGoBinary(path)
+	if err != nil {
+		return err
+	}
+
+	r.PrintText(fmt.Sprintf("Scanned %s Go binary and found %d packages\n", path, len(binary.Packages)))
+
+	source := models.SourceInfo{Path: path, Type: "binary"}
+
+	limitErr := limits.checkPackages(len(binary.Packages))
+	if limitErr == nil {
+		limitErr = limits.checkMemory()
+	}
+	if limitErr != nil {
+		r.PrintText(fmt.Sprintf("Skipping %s: %v\n", path, limitErr))
+		issues.skip(source, limitErr.Error())
+
+		return nil
+	}
+
+	for _, pkgDetail := range binary.Packages {
+		pkgDetailQuery := osv.MakePkgRequest(pkgDetail)
+		pkgDetailQuery.Source = source
+		query.Queries = append(query.Queries, pkgDetailQuery)
+	}
+
+	return nil
+}
+
+// scanArtifact hashes the artifact at the given path, such as a jar, wheel or
+// binary, and adds the package versions that `resolve` finds it to be a
+// release of to `query`, trying each hash in turn until one is found
+//
+// Artifacts that are not known to be a release of any package are added to
+// `issues`, as they cannot be checked for vulnerabilities. Only failing to
+// resolve a hash is an APIError, as the artifact could not be read otherwise.
+func scanArtifact(r output.Reporter, query *osv.BatchedQuery, issues *scanIssues, limits scanLimits, path string, resolve func(osv.ArtifactHash) ([]*osv.Query, error)) error {
+	if err := limits.checkFileSize(path); err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hashes, err := osv.HashArtifact(file)
+	if err != nil {
+		return fmt.Errorf("failed to hash artifact %s: %w", path, err)
+	}
+
+	source := models.SourceInfo{
+		Path: path,
+		Type: "artifact",
+	}
+
+	for _, hash := range hashes {
+		queries, err := resolve(hash)
+		if err != nil {
+			return &APIError{Err: fmt.Errorf("failed to query artifact by hash: %w", err)}
+		}
+		if len(queries) == 0 {
+			continue
+		}
+
+		r.PrintText(fmt.Sprintf("Scanned %s artifact and found %d packages with %s\n", path, len(queries), hash))
+
+		for _, q := range queries {
+			q.Source = source
+			query.Queries = append(query.Queries, q)
+		}
+
+		return nil
+	}
+
+	r.PrintText(fmt.Sprintf("Skipping %s: no package is known to have an artifact with its hash\n", path))
+	issues.skip(source, "no package is known to have an artifact with its hash")
+
+	return nil
+}
+
+// openRepository opens the git repository at dir, or that dir is within if
+// `detect` is set, which can be a linked worktree whose branches are shared
+// with the repository it was added to
+func openRepository(dir string, detect bool) (*git.Repository, error) {
+	return git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{
+		DetectDotGit:          detect,
+		EnableDotGitCommonDir: true,
+	})
+}
+
+// isBareRepository reports if dir is a bare git repository, which has the
+// contents of a .git directory without a worktree
+func isBareRepository(dir string) bool {
+	for _, name := range []string{"HEAD", "objects", "refs"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
 func getCommitSHA(repoDir string) (string, error) {
-	repo, err := git.PlainOpen(repoDir)
+	repo, err := openRepository(repoDir, false)
 	if err != nil {
 		return "", err
 	}
@@ -256,7 +876,7 @@ func getCommitSHA(repoDir string) (string, error) {
 }
 
 // Scan git repository. Expects repoDir to end with /
-func scanGit(r *output.Reporter, query *osv.BatchedQuery, repoDir string) error {
+func scanGit(r output.Reporter, query *osv.BatchedQuery, repoDir string) error {
 	commit, err := getCommitSHA(repoDir)
 	if err != nil {
 		return err
@@ -277,8 +897,8 @@ func scanGitCommit(query *osv.BatchedQuery, commit string, source string) error
 	return nil
 }
 
-func scanDebianDocker(r *output.Reporter, query *osv.BatchedQuery, dockerImageName string) error {
-	cmd := exec.Command("docker", "run", "--rm", "--entrypoint", "/usr/bin/dpkg-query", dockerImageName, "-f", "${Package}###${Version}\\n", "-W")
+func scanDebianDocker(ctx context.Context, r output.Reporter, query *osv.BatchedQuery, dockerImageName string) error {
+	cmd := exec.CommandContext(ctx, "docker", "run", "--rm", "--entrypoint", "/usr/bin/dpkg-query", dockerImageName, "-f", "${Package}###${Version}\\n", "-W")
 	stdout, err := cmd.StdoutPipe()
 
 	if err != nil {
@@ -325,28 +945,179 @@ func scanDebianDocker(r *output.Reporter, query *osv.BatchedQuery, dockerImageNa
 }
 
 // Filters response according to config, returns number of responses removed
-func filterResponse(r *output.Reporter, query osv.BatchedQuery, resp *osv.BatchedResponse, configManager *config.ConfigManager) int {
+//
+// Ignore entries with an expiry are checked against `now`
+func filterResponse(r output.Reporter, query osv.BatchedQuery, resp *osv.BatchedResponse, configManager *config.ConfigManager, remoteIgnores dismissals, now time.Time, usage *ignoreUsage) int {
 	hiddenVulns := map[string]config.IgnoreEntry{}
+	hiddenPaths := map[string]config.IgnorePathEntry{}
+	hiddenByPath := 0
+	inlineIgnores := map[string]config.InlineIgnores{}
+	expired := map[expiredIgnore]bool{}
 
 	for i, result := range resp.Results {
 		var filteredVulns []osv.MinimalVulnerability
-		configToUse := configManager.Get(r, query.Queries[i].Source.Path)
+		source := query.Queries[i].Source
+		configToUse := configManager.Get(r, source.Path)
+		usage.record(configToUse, declaringFile(query.Queries[i]), result.Vulns)
+
+		if ignore, ignorePath := configToUse.ShouldIgnorePathAt(declaringFile(query.Queries[i]), now); ignore && len(result.Vulns) > 0 {
+			hiddenPaths[ignorePath.Path] = ignorePath
+			hiddenByPath += len(result.Vulns)
+			resp.Results[i].Vulns = nil
+
+			continue
+		}
+
+		inlineConfigs := inlineIgnoresFor(inlineIgnores, query.Queries[i])
+		dismissed := remoteIgnores.ignoresFor(query.Queries[i])
 		for _, vuln := range result.Vulns {
-			ignore, ignoreLine := configToUse.ShouldIgnore(vuln.ID)
+			// ignores that have expired are only stale if no other ignore
+			// still applies to the vulnerability
+			var stale []expiredIgnore
+			var ignore bool
+			var ignoreLine config.IgnoreEntry
+			for _, inlineConfig := range inlineConfigs {
+				ignore, ignoreLine = inlineConfig.ShouldIgnoreAt(vuln.ID, now)
+				stale = appendExpired(stale, ignore, ignoreLine, inlineConfig.LoadPath)
+				if ignore {
+					break
+				}
+			}
+			if !ignore {
+				ignore, ignoreLine = configToUse.ShouldIgnoreAt(vuln.ID, now)
+				stale = appendExpired(stale, ignore, ignoreLine, configToUse.LoadPath)
+			}
+			if !ignore {
+				ignore, ignoreLine = dismissed.ShouldIgnoreAt(vuln.ID, now)
+			}
 			if ignore {
 				hiddenVulns[vuln.ID] = ignoreLine
 			} else {
 				filteredVulns = append(filteredVulns, vuln)
+				for _, e := range stale {
+					expired[e] = true
+				}
 			}
 		}
 		resp.Results[i].Vulns = filteredVulns
 	}
 
-	for id, ignoreLine := range hiddenVulns {
-		r.PrintText(fmt.Sprintf("%s has been filtered out because: %s\n", id, ignoreLine.Reason))
+	hiddenIDs := make([]string, 0, len(hiddenVulns))
+	for id := range hiddenVulns {
+		hiddenIDs = append(hiddenIDs, id)
+	}
+	sort.Strings(hiddenIDs)
+
+	for _, id := range hiddenIDs {
+		r.PrintText(output.Localize(r, "%s has been filtered out because: %s", id, hiddenVulns[id].Reason) + "\n")
+	}
+
+	reportExpiredIgnores(r, expired)
+
+	hiddenPatterns := make([]string, 0, len(hiddenPaths))
+	for pattern := range hiddenPaths {
+		hiddenPatterns = append(hiddenPatterns, pattern)
+	}
+	sort.Strings(hiddenPatterns)
+
+	for _, pattern := range hiddenPatterns {
+		r.PrintText(output.Localize(r, "Packages declared in %s have been filtered out because: %s", pattern, hiddenPaths[pattern].Reason) + "\n")
+	}
+
+	return len(hiddenVulns) + hiddenByPath
+}
+
+// declaringFile returns the file that declares the package of the query, if
+// its source is a file that packages are declared in
+func declaringFile(query *osv.Query) string {
+	if query.Source.Type != "lockfile" && query.Source.Type != "sbom" && query.Source.Type != "manifest" {
+		return ""
+	}
+
+	if query.Metadata != nil && query.Metadata.DeclaringFile != "" {
+		return query.Metadata.DeclaringFile
+	}
+
+	return query.Source.Path
+}
+
+// inlineIgnoreManifests are the names of the manifests that lockfiles are
+// locked from, keyed by the names of the lockfiles, which the dependencies of
+// the lockfiles can also be ignored inline in
+var inlineIgnoreManifests = map[string]string{
+	"Gemfile.lock":      "Gemfile",
+	"gems.locked":       "gems.rb",
+	"Pipfile.lock":      "Pipfile",
+	"poetry.lock":       "pyproject.toml",
+	"Cargo.lock":        "Cargo.toml",
+	"mix.lock":          "mix.exs",
+	"pubspec.lock":      "pubspec.yaml",
+	"gradle.lockfile":   "build.gradle",
+	"package-lock.json": "package.json",
+	"yarn.lock":         "package.json",
+	"pnpm-lock.yaml":    "package.json",
+}
+
+// loadInlineIgnores returns the ignores declared with `osv-scanner:ignore`
+// comments in the file at path, caching them in `cache`
+func loadInlineIgnores(cache map[string]config.InlineIgnores, path string) config.InlineIgnores {
+	if ignores, ok := cache[path]; ok {
+		return ignores
+	}
+
+	// a missing or unreadable file just means there are no inline ignores
+	ignores, _ := config.LoadInlineIgnores(path)
+	cache[path] = ignores
+
+	return ignores
+}
+
+// inlineIgnoresFor returns the ignores declared with `osv-scanner:ignore`
+// comments that apply to the package of the query, being those on the line
+// of the lockfile that declares it (or on a line that names it, if it is not
+// known where it is declared), and those on lines of the manifest next to the
+// lockfile that name it
+func inlineIgnoresFor(cache map[string]config.InlineIgnores, query *osv.Query) []config.Config {
+	if query.Source.Type != "lockfile" {
+		return nil
+	}
+
+	name := query.Package.Name
+	if name == "" {
+		name = query.PackageName
+	}
+
+	path := query.Source.Path
+	lockfileIgnores := loadInlineIgnores(cache, path)
+
+	configs := make([]config.Config, 0, 2)
+	if query.Location != nil && query.Location.Line > 0 {
+		configs = append(configs, lockfileIgnores.ForLine(path, query.Location.Line))
+	} else {
+		configs = append(configs, lockfileIgnores.ForPackage(path, name))
+	}
+
+	if manifest, ok := inlineIgnoreManifests[filepath.Base(path)]; ok {
+		manifestPath := filepath.Join(filepath.Dir(path), manifest)
+		configs = append(configs, loadInlineIgnores(cache, manifestPath).ForPackage(manifestPath, name))
 	}
 
-	return len(hiddenVulns)
+	return configs
+}
+
+// partialFailures returns the indexes of the queries that failed if err
+// represents a partial failure that can be tolerated
+func partialFailures(err error, allowPartialResults bool) ([]int, bool) {
+	if !allowPartialResults {
+		return nil, false
+	}
+
+	var partialErr *osv.PartialResponseError
+	if !errors.As(err, &partialErr) {
+		return nil, false
+	}
+
+	return partialErr.FailedQueries, true
 }
 
 func parseLockfilePath(lockfileElem string) (string, string) {
@@ -359,31 +1130,207 @@ func parseLockfilePath(lockfileElem string) (string, string) {
 	return splits[0], splits[1]
 }
 
-// Perform osv scanner action, with optional reporter to output information
-func DoScan(actions ScannerActions, r *output.Reporter) (models.VulnerabilityResults, error) {
+// Perform osv scanner action, with optional reporter to output information,
+// which can be any implementation of output.Reporter such as one that writes
+// to the logger of the program embedding the scanner
+func DoScan(actions ScannerActions, r output.Reporter) (models.VulnerabilityResults, error) {
+	return DoScanWithContext(context.Background(), actions, r)
+}
+
+// DoScanWithContext is like DoScan, but stops the scan once ctx is done,
+// returning the error of ctx. Requests to the OSV API, deps.dev, package
+// registries and GitHub, downloads of offline databases, clones of git
+// repositories and docker commands are abandoned, and otherwise the scan
+// stops after the input that is being scanned when ctx is done.
+func DoScanWithContext(ctx context.Context, actions ScannerActions, r output.Reporter) (models.VulnerabilityResults, error) {
 	if r == nil {
 		r = output.NewVoidReporter()
 	}
 
+	if err := ctx.Err(); err != nil {
+		return models.VulnerabilityResults{}, err
+	}
+
+	if actions.TargetsPath != "" || actions.GitHubOrganization != "" {
+		return doScanTargets(ctx, actions, r)
+	}
+
+	if actions.DryRun {
+		return planScan(r, actions)
+	}
+
 	configManager := config.ConfigManager{
 		DefaultConfig: config.Config{},
 		ConfigMap:     make(map[string]config.Config),
 	}
 
-	var query osv.BatchedQuery
+	var baseline *models.VulnerabilityResults
+	if actions.BaselinePath != "" {
+		var err error
+		baseline, err = loadBaseline(actions.BaselinePath)
+		if err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+	}
+
+	if actions.MemoryBudget > 0 {
+		// have the garbage collector work harder to stay within the budget
+		defer debug.SetMemoryLimit(debug.SetMemoryLimit(actions.MemoryBudget))
+	}
+
+	stream := newQueryStream(ctx, actions.AllowPartialResults, actions.RequestWorkers)
+	stream.profile = actions.Profile
+	backend := newScanBackend(ctx, r, actions, stream)
+	checks := newPackageChecks(ctx, actions, r, &configManager, stream)
 
 	if actions.ConfigOverridePath != "" {
-		err := configManager.UseOverride(actions.ConfigOverridePath)
+		err := configManager.UseOverride(r, actions.ConfigOverridePath)
 		if err != nil {
 			r.PrintError(fmt.Sprintf("Failed to read config file: %s\n", err))
-			return models.VulnerabilityResults{}, err
+			return models.VulnerabilityResults{}, &ConfigError{Path: actions.ConfigOverridePath, Err: err}
+		}
+	}
+
+	var remoteIgnores dismissals
+	if actions.GitHubDismissalsRepository != "" && !actions.InventoryOnly {
+		var err error
+		remoteIgnores, err = loadGitHubDismissals(ctx, actions.GitHubDismissalsRepository)
+		if err != nil {
+			r.PrintError(fmt.Sprintf("Failed to load dismissed alerts from GitHub: %s\n", err))
+			return models.VulnerabilityResults{}, &APIError{Err: err}
+		}
+		r.PrintText(fmt.Sprintf("Loaded %d dismissed alerts from GitHub repository %s\n", len(remoteIgnores), actions.GitHubDismissalsRepository))
+	}
+
+	var issues scanIssues
+	if err := scanInputs(ctx, r, actions, stream, &issues, &configManager); err != nil {
+		return models.VulnerabilityResults{}, err
+	}
+
+	if stream.empty() {
+		results := models.VulnerabilityResults{
+			ParseFailures: issues.parseFailures,
+			Skipped:       issues.skipped,
 		}
+		if actions.Reproducible {
+			makeReproducible(&results)
+		}
+
+		return results, NoPackagesFoundErr
+	}
+
+	if err := stream.flush(true); err != nil {
+		return models.VulnerabilityResults{}, err
+	}
+
+	if actions.InventoryOnly {
+		return inventoryResults(r, actions, checks.inventory, issues)
+	}
+
+	backend.saveQueries(r)
+
+	// only the queries that had vulnerabilities or failed are kept
+	query := &stream.kept
+	resp := &stream.resp
+	incompleteQueries := append([]int{}, stream.failed...)
+	if len(stream.failed) > 0 {
+		r.PrintText(fmt.Sprintf("Failed to query %d packages, results will be incomplete: %v\n", len(stream.failed), stream.failedErr))
+	}
+
+	filtered := filterResponse(r, *query, resp, &configManager, remoteIgnores, scanTime(actions.Reproducible), checks.usage)
+	if filtered > 0 {
+		r.PrintText(output.Localize(r, "Filtered %d vulnerabilities from output", filtered) + "\n")
+	}
+
+	done := actions.Profile.Track("hydrating")
+	hydratedResp, err := backend.hydrate(resp)
+	done()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return models.VulnerabilityResults{}, ctxErr
+	}
+	backend.saveAdvisories(r)
+	if err != nil {
+		failed, ok := partialFailures(err, actions.AllowPartialResults)
+		if !ok {
+			return models.VulnerabilityResults{}, fmt.Errorf("failed to hydrate OSV response: %w", &APIError{Err: err})
+		}
+		r.PrintText(fmt.Sprintf("Failed to fetch vulnerability details for %d packages, results will be incomplete: %v\n", len(failed), err))
+		incompleteQueries = append(incompleteQueries, failed...)
+	}
+
+	if !actions.IncludeWithdrawn {
+		if n := dropWithdrawn(hydratedResp); n > 0 {
+			r.PrintText(fmt.Sprintf("Filtered %d advisories that have been withdrawn\n", n))
+		}
+	}
+	if n := dropDismissedAliases(r, *query, hydratedResp, remoteIgnores); n > 0 {
+		r.PrintText(output.Localize(r, "Filtered %d vulnerabilities from output", n) + "\n")
+	}
+
+	vulnerabilityResults := groupResponseBySource(r, *query, hydratedResp, actions.DirectoryPaths)
+	markIncompleteSources(&vulnerabilityResults, *query, incompleteQueries)
+	attributeWorkspaceMembers(r, &vulnerabilityResults)
+	attributeOwners(r, &vulnerabilityResults)
+	locateDeclarations(&vulnerabilityResults)
+	scoreResults(r, &vulnerabilityResults, &configManager)
+	if baseline != nil {
+		if n := applyBaseline(&vulnerabilityResults, baseline); n > 0 {
+			r.PrintText(fmt.Sprintf("Filtered %d vulnerabilities that are in the baseline\n", n))
+		}
+	}
+	if actions.Blame {
+		annotateBlame(r, &vulnerabilityResults)
+	}
+	vulnerabilityResults.ParseFailures = issues.parseFailures
+	vulnerabilityResults.Skipped = issues.skipped
+	checks.addResults(r, &vulnerabilityResults)
+	analyzeResults(ctx, r, actions, &vulnerabilityResults)
+	// an ignore might match the vulnerabilities of the packages that could
+	// not be checked, so only a complete scan can tell if it is unused
+	if len(incompleteQueries) == 0 {
+		vulnerabilityResults.UnusedIgnores = reportUnusedIgnores(r, checks.usage, &configManager)
+	}
+	if actions.Reproducible {
+		makeReproducible(&vulnerabilityResults)
+	} else {
+		vulnerabilityResults.Timings = actions.Profile.Sources()
+	}
+	sortByScore(r, &vulnerabilityResults, &configManager)
+
+	return vulnerabilityResults, scanResultErr(r, actions, &configManager, vulnerabilityResults)
+}
+
+// scanInputs scans each of the inputs of the actions for packages, sending
+// them to the stream as they are found
+func scanInputs(ctx context.Context, r output.Reporter, actions ScannerActions, stream *queryStream, issues *scanIssues, configManager *config.ConfigManager) error {
+	limits := limitsFor(actions)
+	scope := scopeFor(actions)
+	query := &stream.pending
+	queryArtifact := func(hash osv.ArtifactHash) ([]*osv.Query, error) {
+		return osv.QueryArtifact(ctx, hash)
 	}
 
 	for _, container := range actions.DockerContainerNames {
-		// TODO: Automatically figure out what docker base image
-		// and scan appropriately.
-		_ = scanDebianDocker(r, &query, container)
+		err := scanDocker(ctx, r, query, container)
+		if err != nil {
+			issues.skip(models.SourceInfo{Path: container, Type: "docker"}, err.Error())
+		}
+		if err := stream.flush(false); err != nil {
+			return err
+		}
+	}
+
+	if actions.HostRoot != "" {
+		done := trackParse(r, actions.Profile, query, "host", actions.HostRoot)
+		err := scanHost(r, query, issues, limits, actions.HostRoot, queryArtifact)
+		done()
+		if err != nil {
+			r.PrintError(fmt.Sprintf("Failed to audit host: %s\n", err))
+			issues.skip(models.SourceInfo{Path: actions.HostRoot, Type: "host"}, err.Error())
+		}
+		if err := stream.flush(false); err != nil {
+			return err
+		}
 	}
 
 	for _, lockfileElem := range actions.LockfilePaths {
@@ -391,64 +1338,143 @@ func DoScan(actions ScannerActions, r *output.Reporter) (models.VulnerabilityRes
 		lockfilePath, err := filepath.Abs(lockfilePath)
 		if err != nil {
 			r.PrintError(fmt.Sprintf("Failed to resolved path with error %s\n", err))
-			return models.VulnerabilityResults{}, err
+			return err
 		}
-		err = scanLockfile(r, &query, lockfilePath, parseAs)
-		if err != nil {
-			return models.VulnerabilityResults{}, err
+		_, parsedAs := lockfile.FindParser(lockfilePath, parseAs)
+		done := trackParse(r, actions.Profile, query, parsedAs, lockfilePath)
+		err = scanLockfile(r, query, limits, scope, lockfilePath, parseAs)
+		done()
+		if errors.Is(err, ErrLimitExceeded) {
+			r.PrintText(fmt.Sprintf("Skipping %s: %v\n", lockfilePath, err))
+			issues.skip(models.SourceInfo{Path: lockfilePath, Type: "lockfile"}, err.Error())
+		} else if err != nil {
+			return &LockfileParseError{Path: lockfilePath, ParseAs: parseAs, Err: err}
+		}
+		if err := stream.flush(false); err != nil {
+			return err
 		}
 	}
 
 	for _, sbomElem := range actions.SBOMPaths {
 		sbomElem, err := filepath.Abs(sbomElem)
 		if err != nil {
-			return models.VulnerabilityResults{}, fmt.Errorf("failed to resolved path with error %w", err)
+			return fmt.Errorf("failed to resolved path with error %w", err)
+		}
+		done := trackParse(r, actions.Profile, query, "sbom", sbomElem)
+		err = scanSBOMFile(r, query, issues, limits, sbomElem)
+		done()
+		if errors.Is(err, ErrLimitExceeded) {
+			r.PrintText(fmt.Sprintf("Skipping %s: %v\n", sbomElem, err))
+			issues.skip(models.SourceInfo{Path: sbomElem, Type: "sbom"}, err.Error())
+		} else if err != nil {
+			return &SBOMParseError{Path: sbomElem, Err: err}
 		}
-		err = scanSBOMFile(r, &query, sbomElem)
+		if err := stream.flush(false); err != nil {
+			return err
+		}
+	}
+
+	for _, artifactElem := range actions.ArtifactPaths {
+		artifactElem, err := filepath.Abs(artifactElem)
 		if err != nil {
-			return models.VulnerabilityResults{}, err
+			return fmt.Errorf("failed to resolved path with error %w", err)
+		}
+		done := trackParse(r, actions.Profile, query, "artifact", artifactElem)
+		err = scanArtifact(r, query, issues, limits, artifactElem, queryArtifact)
+		done()
+		if errors.Is(err, ErrLimitExceeded) {
+			r.PrintText(fmt.Sprintf("Skipping %s: %v\n", artifactElem, err))
+			issues.skip(models.SourceInfo{Path: artifactElem, Type: "artifact"}, err.Error())
+		} else if err != nil {
+			return err
+		}
+		if err := stream.flush(false); err != nil {
+			return err
 		}
 	}
 
 	for _, commit := range actions.GitCommits {
-		err := scanGitCommit(&query, commit, "HASH")
+		err := scanGitCommit(query, commit, "HASH")
 		if err != nil {
-			return models.VulnerabilityResults{}, err
+			return err
 		}
 	}
 
+	overrides := newParserOverrides(actions.ParserOverrides, configManager.OverrideConfig)
+
+	var resolver *manifestResolver
+	if actions.ResolveManifests {
+		resolver = newManifestResolver(ctx)
+	}
+
 	for _, dir := range actions.DirectoryPaths {
 		r.PrintText(fmt.Sprintf("Scanning dir %s\n", dir))
-		err := scanDir(r, &query, dir, actions.SkipGit, actions.Recursive, !actions.NoIgnore)
+		err := scanDir(r, stream, issues, limits, scope, actions.Profile, resolver, overrides, dir, actions.SkipGit, actions.NestedRepos, actions.Recursive, !actions.NoIgnore, actions.Strict, actions.ParseWorkers)
 		if err != nil {
-			return models.VulnerabilityResults{}, err
+			return err
 		}
 	}
 
-	if len(query.Queries) == 0 {
-		return models.VulnerabilityResults{}, NoPackagesFoundErr
+	return nil
+}
+
+// analyzeResults runs the analyses of the vulnerable packages that the actions
+// ask for, which need the results of the scan
+func analyzeResults(ctx context.Context, r output.Reporter, actions ScannerActions, results *models.VulnerabilityResults) {
+	if actions.AnalyzeUpgrades {
+		analyzer := newUpgradeAnalyzer(ctx, osv.FetchDependencyGraph)
+		done := actions.Profile.Track("analyzing upgrades")
+		results.Upgrades = analyzer.analyzeUpgrades(*results)
+		done()
+		analyzer.reportFailures(r, "Failed to resolve the dependencies of %d packages, upgrades will be incomplete: %v")
+	}
+	if actions.CallAnalysis {
+		done := actions.Profile.Track("analyzing calls")
+		results.Reachability = analyzeCalls(r, results)
+		done()
 	}
+	if actions.FindSymbolUsages {
+		done := actions.Profile.Track("finding symbol usages")
+		findSymbolUsages(r, results)
+		done()
+	}
+	if actions.History {
+		done := actions.Profile.Track("scanning history")
+		results.History = scanHistory(r, results, actions.HistoryEvery)
+		sortHistory(results.History)
+		done()
+	}
+}
 
-	resp, err := osv.MakeRequest(query)
-	if err != nil {
-		return models.VulnerabilityResults{}, fmt.Errorf("scan failed %w", err)
+// scanResultErr returns the error that the scan should fail with given its
+// results, if any
+func scanResultErr(r output.Reporter, actions ScannerActions, configManager *config.ConfigManager, results models.VulnerabilityResults) error {
+	// if vulnerability exists it should return error, even when the scan was
+	// incomplete, so that the results are still published and exit with 1
+	policy := newFailPolicy(actions)
+	policy.sourceSeverity = func(source models.SourceInfo) string {
+		configToUse := configManager.Get(r, source.Path)
+
+		return models.NormalizeSeverity(configToUse.FailOnSeverity)
+	}
+	if n := policy.countInGracePeriod(results); n > 0 {
+		r.PrintText(fmt.Sprintf("%d vulnerabilities are within their grace period and do not fail the scan\n", n))
+	}
+	if len(results.Flatten()) > 0 && policy.fails(results) {
+		return VulnerabilitiesFoundErr
 	}
 
-	filtered := filterResponse(r, query, resp, &configManager)
-	if filtered > 0 {
-		r.PrintText(fmt.Sprintf("Filtered %d vulnerabilities from output\n", filtered))
+	if actions.Strict && len(results.Skipped) > 0 {
+		return fmt.Errorf("%w: %d inputs were skipped", ErrIncompleteScan, len(results.Skipped))
 	}
 
-	hydratedResp, err := osv.Hydrate(resp)
-	if err != nil {
-		return models.VulnerabilityResults{}, fmt.Errorf("failed to hydrate OSV response: %w", err)
+	if len(results.RegistryIssues) > 0 {
+		return fmt.Errorf("%w: %d packages", ErrRegistryMismatch, len(results.RegistryIssues))
 	}
 
-	vulnerabilityResults := groupResponseBySource(r, query, hydratedResp)
-	// if vulnerability exists it should return error
-	if len(vulnerabilityResults.Results) > 0 {
-		return vulnerabilityResults, VulnerabilitiesFoundErr
+	if len(results.LicenseViolations) > 0 {
+		return fmt.Errorf("%w: %d packages", ErrLicenseViolation, len(results.LicenseViolations))
 	}
 
-	return vulnerabilityResults, nil
+	return nil
 }
@@ -0,0 +1,790 @@
+package osvscanner
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/osv-scanner/pkg/config"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/output"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDoScan_ParseFailures(t *testing.T) {
+	t.Parallel()
+
+	results, err := DoScan(ScannerActions{
+		DirectoryPaths: []string{"../../fixtures/locks-invalid"},
+	}, nil)
+
+	if !errors.Is(err, NoPackagesFoundErr) {
+		t.Fatalf("expected NoPackagesFoundErr, got %v", err)
+	}
+
+	if len(results.ParseFailures) != 1 {
+		t.Fatalf("expected 1 parse failure, got %d", len(results.ParseFailures))
+	}
+
+	failure := results.ParseFailures[0]
+
+	if filepath.Base(failure.Path) != "composer.lock" {
+		t.Errorf("expected failure for composer.lock, got %s", failure.Path)
+	}
+
+	if failure.Parser != "composer.lock" {
+		t.Errorf("expected parser to be composer.lock, got %s", failure.Parser)
+	}
+
+	if failure.Error == "" {
+		t.Errorf("expected failure to have an error message")
+	}
+}
+
+func TestDoScanWithContext_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := DoScanWithContext(ctx, ScannerActions{
+		DirectoryPaths: []string{"../../fixtures/locks-invalid"},
+	}, nil)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestDoScan_ParseFailures_Strict(t *testing.T) {
+	t.Parallel()
+
+	results, err := DoScan(ScannerActions{
+		DirectoryPaths: []string{"../../fixtures/locks-invalid"},
+		Strict:         true,
+	}, nil)
+
+	if !errors.Is(err, ErrLockfileParse) {
+		t.Fatalf("expected ErrLockfileParse, got %v", err)
+	}
+
+	if len(results.ParseFailures) != 0 {
+		t.Errorf("expected no parse failures to be reported, got %d", len(results.ParseFailures))
+	}
+}
+
+// writeOfflineExport writes an export of the ecosystem with the given
+// advisories, keyed by their ids, into the offline database at dir
+func writeOfflineExport(t *testing.T, dir string, ecosystem string, advisories map[string]string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Join(dir, ecosystem), 0o755); err != nil {
+		t.Fatalf("could not create export: %v", err)
+	}
+	f, err := os.Create(filepath.Join(dir, ecosystem, "all.zip"))
+	if err != nil {
+		t.Fatalf("could not create export: %v", err)
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for id, advisory := range advisories {
+		entry, err := w.Create(id + ".json")
+		if err != nil {
+			t.Fatalf("could not create export: %v", err)
+		}
+		if _, err := entry.Write([]byte(advisory)); err != nil {
+			t.Fatalf("could not create export: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("could not create export: %v", err)
+	}
+}
+
+func TestDoScan_Strict_VulnerabilitiesFoundTakesPrecedence(t *testing.T) {
+	t.Parallel()
+
+	// the SBOM has a vulnerable component, and one with an ecosystem that is
+	// not known to OSV which has to be skipped
+	dir := t.TempDir()
+	writeOfflineExport(t, dir, "npm", map[string]string{
+		"GHSA-1": `{
+			"id": "GHSA-1",
+			"affected": [{
+				"package": {"ecosystem": "npm", "name": "ansi-html"},
+				"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "0.0.8"}]}]
+			}]
+		}`,
+	})
+	writeOfflineExport(t, dir, "github", map[string]string{})
+
+	results, err := DoScan(ScannerActions{
+		SBOMPaths:           []string{"../../fixtures/sbom-insecure/unknown-ecosystem.cdx.json"},
+		OfflineDatabasePath: dir,
+		Strict:              true,
+	}, output.NewVoidReporter())
+
+	if !errors.Is(err, VulnerabilitiesFoundErr) {
+		t.Fatalf("expected VulnerabilitiesFoundErr, got %v", err)
+	}
+
+	if len(results.Skipped) != 1 {
+		t.Errorf("expected the skipped component to be reported, got %v", results.Skipped)
+	}
+}
+
+// recordingReporter is a Reporter like those of programs that embed the
+// scanner, which records what is reported to it
+type recordingReporter struct {
+	lines []string
+}
+
+func (r *recordingReporter) PrintText(msg string) {
+	r.lines = append(r.lines, "text: "+strings.TrimSpace(msg))
+}
+
+func (r *recordingReporter) PrintError(msg string) {
+	r.lines = append(r.lines, "error: "+strings.TrimSpace(msg))
+}
+
+func (r *recordingReporter) PrintResult(_ *models.VulnerabilityResults) error {
+	r.lines = append(r.lines, "result")
+
+	return nil
+}
+
+func TestDoScan_CustomReporter(t *testing.T) {
+	t.Parallel()
+
+	r := &recordingReporter{}
+	_, err := DoScan(ScannerActions{
+		DirectoryPaths: []string{"../../fixtures/locks-invalid"},
+		ParseWorkers:   2,
+	}, r)
+
+	if !errors.Is(err, NoPackagesFoundErr) {
+		t.Fatalf("expected NoPackagesFoundErr, got %v", err)
+	}
+
+	path, err := filepath.Abs("../../fixtures/locks-invalid/composer.lock")
+	if err != nil {
+		t.Fatalf("could not resolve path: %v", err)
+	}
+	want := []string{
+		"text: Scanning dir ../../fixtures/locks-invalid",
+		"error: Attempted to scan lockfile but failed: " + path,
+	}
+	if diff := cmp.Diff(want, r.lines); diff != "" {
+		t.Errorf("reported mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestScanSBOMFile_UnknownEcosystems(t *testing.T) {
+	t.Parallel()
+
+	var query osv.BatchedQuery
+	var issues scanIssues
+
+	err := scanSBOMFile(output.NewVoidReporter(), &query, &issues, scanLimits{}, "../../fixtures/sbom-insecure/unknown-ecosystem.cdx.json")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(query.Queries) != 3 {
+		t.Errorf("expected 3 queries, got %d", len(query.Queries))
+	}
+
+	if len(issues.skipped) != 1 {
+		t.Fatalf("expected the SBOM to be skipped once, got %d", len(issues.skipped))
+	}
+
+	if issues.skipped[0].Reason != "unknown ecosystem for pkg:github/someone/some-action@1.0.0, pkg:github/someone/other-action@2.0.0" {
+		t.Errorf("unexpected reason: %s", issues.skipped[0].Reason)
+	}
+}
+
+func TestScanDirFile_GoBinary(t *testing.T) {
+	t.Parallel()
+
+	// the test binary is a Go binary, which is scanned whatever it is named
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatalf("could not find the test binary: %v", err)
+	}
+
+	var query osv.BatchedQuery
+	var issues scanIssues
+	err = scanDirFile(output.NewVoidReporter(), &query, &issues, scanLimits{}, packageScope{}, nil, nil, executable, nil, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	found := map[string]string{}
+	for _, q := range query.Queries {
+		if q.Source.Type != "binary" || q.Source.Path != executable {
+			t.Errorf("unexpected source %v for %s", q.Source, q.Package.Name)
+		}
+		found[q.Package.Name] = q.Package.Ecosystem
+	}
+	for _, name := range []string{"stdlib", "golang.org/x/mod"} {
+		if found[name] != "Go" {
+			t.Errorf("expected %s to be queried as a Go module, got %v", name, found)
+		}
+	}
+
+	query = osv.BatchedQuery{}
+	err = scanDirFile(output.NewVoidReporter(), &query, &issues, scanLimits{maxPackages: 1}, packageScope{}, nil, nil, executable, nil, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(query.Queries) != 0 {
+		t.Errorf("expected a binary with too many packages to not be queried, got %d queries", len(query.Queries))
+	}
+	if len(issues.skipped) != 1 || issues.skipped[0].Source.Type != "binary" {
+		t.Errorf("expected the binary to be skipped, got %v", issues.skipped)
+	}
+}
+
+func TestScanSBOMFile_Components(t *testing.T) {
+	t.Parallel()
+
+	type component struct {
+		Component *models.SBOMComponent
+		Location  *models.SourceLocation
+	}
+
+	tests := []struct {
+		path string
+		want []component
+	}{
+		{
+			path: "../../fixtures/sbom-components/bom.cdx.json",
+			want: []component{
+				{
+					Component: &models.SBOMComponent{BOMRef: "ansi-html-0.0.1", Index: 0},
+					Location:  &models.SourceLocation{Line: 11, Column: 16, EndColumn: 39},
+				},
+				{
+					Component: &models.SBOMComponent{BOMRef: "minimist-0.0.8", Index: 2},
+					Location:  &models.SourceLocation{Line: 23, Column: 16, EndColumn: 56},
+				},
+			},
+		},
+		{
+			path: "../../fixtures/sbom-components/bom.cdx.xml",
+			want: []component{
+				{
+					Component: &models.SBOMComponent{BOMRef: "log4j-core-2.14.1", Index: 0},
+					Location:  &models.SourceLocation{Line: 16, Column: 13, EndColumn: 74},
+				},
+				{
+					Component: &models.SBOMComponent{BOMRef: "jackson-databind-2.9.10", Index: 2},
+					Location:  &models.SourceLocation{Line: 27, Column: 17, EndColumn: 109},
+				},
+			},
+		},
+		{
+			path: "../../fixtures/sbom-components/bom.spdx.json",
+			want: []component{
+				{
+					Component: &models.SBOMComponent{BOMRef: "SPDXRef-Package-ansi-html", Index: 0},
+					Location:  &models.SourceLocation{Line: 21, Column: 32, EndColumn: 55},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(filepath.Base(tt.path), func(t *testing.T) {
+			t.Parallel()
+
+			var query osv.BatchedQuery
+			var issues scanIssues
+
+			err := scanSBOMFile(output.NewVoidReporter(), &query, &issues, scanLimits{}, tt.path)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := make([]component, 0, len(query.Queries))
+			for _, q := range query.Queries {
+				got = append(got, component{Component: q.Component, Location: q.Location})
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("scanSBOMFile() components mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestScanLockfile_CargoGitDependencies(t *testing.T) {
+	t.Parallel()
+
+	var query osv.BatchedQuery
+
+	err := scanLockfile(output.NewVoidReporter(), &query, scanLimits{}, packageScope{}, "../../fixtures/workspaces/cargo-lock/Cargo.lock", "")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got *osv.Query
+	for _, q := range query.Queries {
+		if q.Commit != "" {
+			got = q
+		}
+	}
+
+	if got == nil {
+		t.Fatalf("expected the git dependency to be queried by its commit")
+	}
+
+	if got.Commit != "9f9f693768c584971a4d53bc3c586c33ed3a6831" || got.PackageName != "regex" || got.Package != (osv.Package{}) {
+		t.Errorf("unexpected query for the git dependency: %+v", got)
+	}
+}
+
+func TestScanArtifact(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "app.jar")
+	if err := os.WriteFile(path, []byte("stripped of its metadata"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var tried []string
+	resolve := func(hash osv.ArtifactHash) ([]*osv.Query, error) {
+		tried = append(tried, hash.Type)
+		if hash.Type != "SHA1" {
+			return nil, nil
+		}
+
+		return []*osv.Query{{Version: "2.14.1", Package: osv.Package{Name: "org.apache.logging.log4j:log4j-core", Ecosystem: "Maven"}}}, nil
+	}
+
+	var query osv.BatchedQuery
+	var issues scanIssues
+
+	if err := scanArtifact(output.NewVoidReporter(), &query, &issues, scanLimits{}, path, resolve); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"SHA256", "SHA1"}, tried); diff != "" {
+		t.Errorf("hashes tried mismatch (-want +got):\n%s", diff)
+	}
+	if len(query.Queries) != 1 {
+		t.Fatalf("expected 1 query, got %d", len(query.Queries))
+	}
+	if want := (models.SourceInfo{Path: path, Type: "artifact"}); query.Queries[0].Source != want {
+		t.Errorf("expected source %v, got %v", want, query.Queries[0].Source)
+	}
+	if len(issues.skipped) != 0 {
+		t.Errorf("expected no skipped sources, got %v", issues.skipped)
+	}
+
+	unknown := func(osv.ArtifactHash) ([]*osv.Query, error) { return nil, nil }
+
+	if err := scanArtifact(output.NewVoidReporter(), &query, &issues, scanLimits{}, path, unknown); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(query.Queries) != 1 {
+		t.Errorf("expected no queries to be added for an unknown artifact, got %d", len(query.Queries)-1)
+	}
+	if len(issues.skipped) != 1 || issues.skipped[0].Source.Type != "artifact" {
+		t.Errorf("expected the unknown artifact to be skipped, got %v", issues.skipped)
+	}
+
+	failing := func(osv.ArtifactHash) ([]*osv.Query, error) { return nil, errors.New("service unavailable") }
+
+	if err := scanArtifact(output.NewVoidReporter(), &query, &issues, scanLimits{}, path, failing); !errors.Is(err, ErrAPIUnavailable) {
+		t.Errorf("expected failing to resolve a hash to be ErrAPIUnavailable, got %v", err)
+	}
+}
+
+func Test_filterResponse_InlineIgnores(t *testing.T) {
+	t.Parallel()
+
+	source := models.SourceInfo{Path: "../../fixtures/locks-inline-ignores/requirements.txt", Type: "lockfile"}
+
+	query := osv.BatchedQuery{Queries: []*osv.Query{
+		{Source: source, Package: osv.Package{Name: "flask", Ecosystem: "PyPI"}},
+		{Source: source, Package: osv.Package{Name: "jinja2", Ecosystem: "PyPI"}},
+		{Source: source, Package: osv.Package{Name: "django", Ecosystem: "PyPI"}},
+	}}
+	resp := &osv.BatchedResponse{Results: []osv.MinimalResponse{
+		{Vulns: []osv.MinimalVulnerability{{ID: "PYSEC-2019-179"}, {ID: "PYSEC-2018-66"}}},
+		{Vulns: []osv.MinimalVulnerability{{ID: "GHSA-m2qf-hxjv-5gpq"}}},
+		{Vulns: []osv.MinimalVulnerability{{ID: "GHSA-m2qf-hxjv-5gpq"}, {ID: "PYSEC-2019-179"}}},
+	}}
+
+	configManager := config.ConfigManager{ConfigMap: make(map[string]config.Config)}
+
+	filtered := filterResponse(output.NewVoidReporter(), query, resp, &configManager, nil, time.Now(), newIgnoreUsage())
+
+	if filtered != 2 {
+		t.Errorf("expected 2 vulnerabilities to be filtered, got %d", filtered)
+	}
+
+	if len(resp.Results[0].Vulns) != 1 || resp.Results[0].Vulns[0].ID != "PYSEC-2018-66" {
+		t.Errorf("expected only PYSEC-2018-66 to remain, got %v", resp.Results[0].Vulns)
+	}
+
+	if len(resp.Results[1].Vulns) != 0 {
+		t.Errorf("expected all vulnerabilities to be filtered, got %v", resp.Results[1].Vulns)
+	}
+
+	// the comments only apply to the packages declared on their lines
+	if len(resp.Results[2].Vulns) != 2 {
+		t.Errorf("expected no vulnerabilities of other packages to be filtered, got %v", resp.Results[2].Vulns)
+	}
+}
+
+func Test_filterResponse_InlineIgnores_Manifest(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"Gemfile":      "gem \"rails\" # osv-scanner:ignore GHSA-1 reason=not exposed\ngem \"nokogiri\"\n",
+		"Gemfile.lock": "GEM\n  specs:\n    nokogiri (1.13.0)\n    rails (7.0.0)\n",
+	})
+	source := models.SourceInfo{Path: filepath.Join(dir, "Gemfile.lock"), Type: "lockfile"}
+
+	query := osv.BatchedQuery{Queries: []*osv.Query{
+		{Source: source, Package: osv.Package{Name: "rails", Ecosystem: "RubyGems"}, Location: &models.SourceLocation{Line: 4}},
+		{Source: source, Package: osv.Package{Name: "nokogiri", Ecosystem: "RubyGems"}, Location: &models.SourceLocation{Line: 3}},
+	}}
+	resp := &osv.BatchedResponse{Results: []osv.MinimalResponse{
+		{Vulns: []osv.MinimalVulnerability{{ID: "GHSA-1"}}},
+		{Vulns: []osv.MinimalVulnerability{{ID: "GHSA-1"}}},
+	}}
+
+	configManager := config.ConfigManager{ConfigMap: make(map[string]config.Config)}
+
+	filtered := filterResponse(output.NewVoidReporter(), query, resp, &configManager, nil, time.Now(), newIgnoreUsage())
+
+	if filtered != 1 {
+		t.Errorf("expected 1 vulnerability to be filtered, got %d", filtered)
+	}
+
+	if len(resp.Results[0].Vulns) != 0 {
+		t.Errorf("expected the vulnerability of rails to be filtered, got %v", resp.Results[0].Vulns)
+	}
+
+	if len(resp.Results[1].Vulns) != 1 {
+		t.Errorf("expected the vulnerability of nokogiri to remain, got %v", resp.Results[1].Vulns)
+	}
+}
+
+func Test_filterResponse_IgnoredPaths(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	path := func(rel string) string {
+		return filepath.Join(root, filepath.FromSlash(rel))
+	}
+
+	query := osv.BatchedQuery{Queries: []*osv.Query{
+		{Source: models.SourceInfo{Path: path("examples/app/package-lock.json"), Type: "lockfile"}},
+		{Source: models.SourceInfo{Path: path("package-lock.json"), Type: "lockfile"}},
+		{
+			Source:   models.SourceInfo{Path: path("pom.xml"), Type: "lockfile"},
+			Metadata: &models.PackageMetadata{DeclaringFile: path("examples/demo/pom.xml")},
+		},
+		{Source: models.SourceInfo{Path: path("testdata/package-lock.json"), Type: "lockfile"}},
+	}}
+	resp := &osv.BatchedResponse{Results: []osv.MinimalResponse{
+		{Vulns: []osv.MinimalVulnerability{{ID: "GHSA-1"}, {ID: "GHSA-2"}}},
+		{Vulns: []osv.MinimalVulnerability{{ID: "GHSA-1"}}},
+		{Vulns: []osv.MinimalVulnerability{{ID: "GHSA-3"}}},
+		{Vulns: []osv.MinimalVulnerability{{ID: "GHSA-4"}}},
+	}}
+
+	configManager := config.ConfigManager{OverrideConfig: &config.Config{
+		LoadPath: path("osv-scanner.toml"),
+		IgnoredPaths: []config.IgnorePathEntry{
+			{Path: "examples/", Reason: "examples are not deployed"},
+			{Path: "testdata/", Reason: "expired", IgnoreUntil: time.Now().Add(-time.Hour)},
+		},
+	}}
+
+	filtered := filterResponse(output.NewVoidReporter(), query, resp, &configManager, nil, time.Now(), newIgnoreUsage())
+
+	if filtered != 3 {
+		t.Errorf("expected 3 vulnerabilities to be filtered, got %d", filtered)
+	}
+
+	remaining := make([]int, 0, len(resp.Results))
+	for _, result := range resp.Results {
+		remaining = append(remaining, len(result.Vulns))
+	}
+
+	if diff := cmp.Diff([]int{0, 1, 0, 1}, remaining); diff != "" {
+		t.Errorf("unexpected remaining vulnerabilities (-want +got):\n%s", diff)
+	}
+}
+
+// addWorktree links a worktree of the repository on a new branch at its head
+// to dir, in the same way as `git worktree add`, returning its commit
+func addWorktree(t *testing.T, repoDir string, dir string, branch string) string {
+	t.Helper()
+
+	repo, err := git.PlainOpen(repoDir)
+	if err != nil {
+		t.Fatalf("could not open repository: %v", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("could not get head: %v", err)
+	}
+	ref := plumbing.NewHashReference(plumbing.NewBranchReferenceName(branch), head.Hash())
+	if err := repo.Storer.SetReference(ref); err != nil {
+		t.Fatalf("could not create branch: %v", err)
+	}
+
+	admin := filepath.Join(repoDir, ".git", "worktrees", branch)
+	files := map[string]string{
+		filepath.Join(admin, "HEAD"):      "ref: refs/heads/" + branch + "\n",
+		filepath.Join(admin, "commondir"): "../..\n",
+		filepath.Join(admin, "gitdir"):    filepath.Join(dir, ".git") + "\n",
+		filepath.Join(dir, ".git"):        "gitdir: " + admin + "\n",
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("could not create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("could not write %s: %v", path, err)
+		}
+	}
+
+	return head.Hash().String()
+}
+
+// scanDirQueries scans the directory, returning the source of each query
+// along with the commit of those of git repositories
+func scanDirQueries(t *testing.T, dir string) []string {
+	t.Helper()
+
+	return scanDirQueriesWith(t, dir, NestedReposScan)
+}
+
+func scanDirQueriesWith(t *testing.T, dir string, nestedRepos string) []string {
+	t.Helper()
+
+	r := output.NewReporter(io.Discard, io.Discard, "table")
+	stream := newQueryStream(context.Background(), false, 1)
+	var issues scanIssues
+
+	err := scanDir(r, stream, &issues, scanLimits{}, packageScope{}, nil, nil, nil, dir, false, nestedRepos, true, true, false, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(issues.skipped) > 0 {
+		t.Errorf("expected nothing to be skipped, got %v", issues.skipped)
+	}
+
+	var sources []string
+	for _, query := range stream.pending.Queries {
+		source := query.Source.Type + ":" + filepath.ToSlash(strings.TrimPrefix(query.Source.Path, dir))
+		if query.Commit != "" {
+			source += "@" + query.Commit
+		}
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	return sources
+}
+
+func TestScanDir_LinkedWorktree(t *testing.T) {
+	t.Parallel()
+
+	repoDir := makeRepository(t, map[string]string{
+		".gitignore":       "build/\n",
+		"requirements.txt": "flask==1.0.0\n",
+	})
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("could not resolve directory: %v", err)
+	}
+	commit := addWorktree(t, repoDir, dir, "feature")
+
+	// the checkout of the worktree, with a lockfile that has been changed
+	// and another that has not been committed yet
+	for name, content := range map[string]string{
+		".gitignore":             "build/\n",
+		"requirements.txt":       "flask==1.0.0\nrequests==2.0.0\n",
+		"web/package-lock.json":  `{"lockfileVersion": 2, "packages": {"node_modules/lodash": {"version": "4.17.20"}}}`,
+		"build/requirements.txt": "django==1.0.0\n",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("could not create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("could not write %s: %v", name, err)
+		}
+	}
+
+	want := []string{
+		"git:/@" + commit,
+		"lockfile:/requirements.txt",
+		"lockfile:/requirements.txt",
+		"lockfile:/web/package-lock.json",
+	}
+	if diff := cmp.Diff(want, scanDirQueries(t, dir)); diff != "" {
+		t.Errorf("scanDir() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestScanDir_BareRepository(t *testing.T) {
+	t.Parallel()
+
+	source := makeRepository(t, map[string]string{"requirements.txt": "flask==1.0.0\n"})
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("could not resolve directory: %v", err)
+	}
+
+	repo, err := git.PlainClone(filepath.Join(dir, "mirror.git"), true, &git.CloneOptions{URL: source})
+	if err != nil {
+		t.Fatalf("could not clone repository: %v", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("could not get head: %v", err)
+	}
+
+	want := []string{"git:/mirror.git/@" + head.Hash().String()}
+	if diff := cmp.Diff(want, scanDirQueries(t, dir)); diff != "" {
+		t.Errorf("scanDir() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestScanDir_GitIgnoresOutsideWorkingDirectory(t *testing.T) {
+	t.Parallel()
+
+	// temporary directories are outside of the working directory of tests
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("could not resolve directory: %v", err)
+	}
+
+	for name, content := range map[string]string{
+		".gitignore":                  "build/\n",
+		"requirements.txt":            "flask==1.0.0\n",
+		"build/requirements.txt":      "django==1.0.0\n",
+		"web/.gitignore":              "vendor/\n",
+		"web/package-lock.json":       `{"lockfileVersion": 2, "packages": {"node_modules/lodash": {"version": "4.17.20"}}}`,
+		"web/vendor/requirements.txt": "requests==2.0.0\n",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("could not create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("could not write %s: %v", name, err)
+		}
+	}
+
+	want := []string{
+		"lockfile:/requirements.txt",
+		"lockfile:/web/package-lock.json",
+	}
+	if diff := cmp.Diff(want, scanDirQueries(t, dir)); diff != "" {
+		t.Errorf("scanDir() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestScanDir_NestedRepos(t *testing.T) {
+	t.Parallel()
+
+	dir, err := filepath.EvalSymlinks(makeRepository(t, map[string]string{
+		"requirements.txt": "flask==1.0.0\n",
+	}))
+	if err != nil {
+		t.Fatalf("could not resolve directory: %v", err)
+	}
+	vendored := makeRepository(t, map[string]string{
+		"package-lock.json": `{"lockfileVersion": 2, "packages": {"node_modules/lodash": {"version": "4.17.20"}}}`,
+	})
+	if _, err := git.PlainClone(filepath.Join(dir, "third_party", "lib"), false, &git.CloneOptions{URL: vendored}); err != nil {
+		t.Fatalf("could not clone repository: %v", err)
+	}
+	if _, err := git.PlainClone(filepath.Join(dir, "third_party", "mirror.git"), true, &git.CloneOptions{URL: vendored}); err != nil {
+		t.Fatalf("could not clone repository: %v", err)
+	}
+
+	commit := func(repoDir string) string {
+		t.Helper()
+
+		repo, err := git.PlainOpen(repoDir)
+		if err != nil {
+			t.Fatalf("could not open repository: %v", err)
+		}
+		head, err := repo.Head()
+		if err != nil {
+			t.Fatalf("could not get head: %v", err)
+		}
+
+		return head.Hash().String()
+	}
+	root := "git:/@" + commit(dir)
+	nested := commit(vendored)
+
+	tests := []struct {
+		policy string
+		want   []string
+	}{
+		{
+			policy: NestedReposScan,
+			want: []string{
+				root,
+				"git:/third_party/lib/@" + nested,
+				"git:/third_party/mirror.git/@" + nested,
+				"lockfile:/requirements.txt",
+				"lockfile:/third_party/lib/package-lock.json",
+			},
+		},
+		{
+			policy: NestedReposSkip,
+			want:   []string{root, "lockfile:/requirements.txt"},
+		},
+		{
+			policy: NestedReposBoundary,
+			want: []string{
+				root,
+				"git:/third_party/lib/@" + nested,
+				"git:/third_party/mirror.git/@" + nested,
+				"lockfile:/requirements.txt",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.policy, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tt.want, scanDirQueriesWith(t, dir, tt.policy)); diff != "" {
+				t.Errorf("scanDir() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
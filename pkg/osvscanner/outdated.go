@@ -0,0 +1,138 @@
+package osvscanner
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/google/osv-scanner/internal/semantic"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/registry"
+)
+
+// outdatedChecker compares the packages of lockfiles and SBOMs against the
+// latest versions on their registries as they are scanned, looking up the
+// latest version of each package only once
+type outdatedChecker struct {
+	lookupTracker[registry.Package, string, models.OutdatedPackage]
+	// isInternal reports if a package is an internal package, which is not
+	// published on its public registry to be compared against
+	isInternal func(query *osv.Query) bool
+}
+
+func newOutdatedChecker(ctx context.Context, checker *registry.Checker) *outdatedChecker {
+	latest := func(pkg registry.Package) (string, error) {
+		return checker.Latest(ctx, pkg.Ecosystem, pkg.Name)
+	}
+
+	return &outdatedChecker{
+		lookupTracker: newLookupTracker(latest, sortOutdatedPackages),
+	}
+}
+
+// inspect compares each package of a lockfile or SBOM in the batch against
+// the latest version on its registry
+func (c *outdatedChecker) inspect(batch osv.BatchedQuery) {
+	queries := make(chan *osv.Query)
+	var wg sync.WaitGroup
+
+	for i := 0; i < registryWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for query := range queries {
+				c.inspectQuery(query)
+			}
+		}()
+	}
+
+	for _, query := range batch.Queries {
+		if (query.Source.Type != "lockfile" && query.Source.Type != "sbom") || query.Package.Name == "" || !registry.Supported(query.Package.Ecosystem) {
+			continue
+		}
+		if c.isInternal != nil && c.isInternal(query) || !c.firstSeen(query.Source, queryPackage(query)) {
+			continue
+		}
+
+		queries <- query
+	}
+	close(queries)
+	wg.Wait()
+}
+
+func (c *outdatedChecker) inspectQuery(query *osv.Query) {
+	latest, err := c.get(registry.Package{Name: query.Package.Name, Ecosystem: query.Package.Ecosystem})
+	if err != nil {
+		return
+	}
+
+	behind, majors, outdated := howFarBehind(query.Version, latest, query.Package.Ecosystem)
+	if !outdated {
+		return
+	}
+
+	c.add(models.OutdatedPackage{
+		Source:              query.Source,
+		Package:             queryPackage(query),
+		Latest:              latest,
+		Behind:              behind,
+		MajorVersionsBehind: majors,
+	})
+}
+
+// versionParts matches the major, minor and patch parts of a version
+var versionParts = regexp.MustCompile(`^v?(\d+)(?:\.(\d+))?(?:\.(\d+))?`)
+
+// howFarBehind reports if the version is older than the latest version, along
+// with the most significant part of it that differs and how many major
+// versions it is behind
+func howFarBehind(version string, latest string, ecosystem string) (string, int, bool) {
+	if version == "" || latest == "" {
+		return "", 0, false
+	}
+
+	parsed, err := semantic.Parse(version, semantic.Ecosystem(ecosystem))
+	if err != nil || parsed.CompareStr(latest) >= 0 {
+		return "", 0, false
+	}
+
+	current, newest := versionParts.FindStringSubmatch(version), versionParts.FindStringSubmatch(latest)
+	if current == nil || newest == nil {
+		return "other", 0, true
+	}
+
+	for i, part := range []string{"major", "minor", "patch"} {
+		// missing parts are treated as zero, such as the patch of "1.2"
+		a, _ := strconv.Atoi(current[i+1])
+		b, _ := strconv.Atoi(newest[i+1])
+
+		if a == b {
+			continue
+		}
+
+		if part == "major" && b > a {
+			return part, b - a, true
+		}
+
+		return part, 0, true
+	}
+
+	return "other", 0, true
+}
+
+func sortOutdatedPackages(outdated []models.OutdatedPackage) {
+	sort.SliceStable(outdated, func(a, b int) bool {
+		oa, ob := outdated[a], outdated[b]
+		if oa.Source.Path != ob.Source.Path {
+			return oa.Source.Path < ob.Source.Path
+		}
+		if oa.Package.Name != ob.Package.Name {
+			return oa.Package.Name < ob.Package.Name
+		}
+
+		return oa.Package.Version < ob.Package.Version
+	})
+}
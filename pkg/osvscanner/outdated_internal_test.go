@@ -0,0 +1,115 @@
+package osvscanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/registry"
+)
+
+func Test_howFarBehind(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		version    string
+		latest     string
+		ecosystem  string
+		wantBehind string
+		wantMajors int
+		wantOK     bool
+	}{
+		{version: "1.2.3", latest: "1.2.3", ecosystem: "npm", wantOK: false},
+		{version: "2.0.0", latest: "1.9.0", ecosystem: "npm", wantOK: false},
+		{version: "1.2.3", latest: "1.2.4", ecosystem: "npm", wantBehind: "patch", wantOK: true},
+		{version: "1.2.3", latest: "1.10.0", ecosystem: "npm", wantBehind: "minor", wantOK: true},
+		{version: "1.2.3", latest: "4.0.0", ecosystem: "crates.io", wantBehind: "major", wantMajors: 3, wantOK: true},
+		{version: "1.0.0-beta.1", latest: "1.0.0", ecosystem: "npm", wantBehind: "other", wantOK: true},
+		{version: "2.1", latest: "2.1.1", ecosystem: "PyPI", wantBehind: "patch", wantOK: true},
+		{version: "1.0.0", latest: "", ecosystem: "npm", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.version+" "+tt.latest, func(t *testing.T) {
+			t.Parallel()
+
+			behind, majors, ok := howFarBehind(tt.version, tt.latest, tt.ecosystem)
+			if behind != tt.wantBehind || majors != tt.wantMajors || ok != tt.wantOK {
+				t.Errorf("howFarBehind() = (%q, %d, %v), want (%q, %d, %v)", behind, majors, ok, tt.wantBehind, tt.wantMajors, tt.wantOK)
+			}
+		})
+	}
+}
+
+func Test_outdatedChecker(t *testing.T) {
+	t.Parallel()
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		switch r.URL.Path {
+		case "/wrappy":
+			_, _ = w.Write([]byte(`{"dist-tags": {"latest": "1.0.2"}}`))
+		case "/left-pad":
+			_, _ = w.Write([]byte(`{"dist-tags": {"latest": "1.3.0"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	checker := newOutdatedChecker(context.Background(), &registry.Checker{
+		Client:   server.Client(),
+		BaseURLs: map[string]string{"npm": server.URL},
+	})
+	checker.isInternal = func(query *osv.Query) bool {
+		return query.Package.Name == "@acme/utils"
+	}
+
+	lockfileA := models.SourceInfo{Path: "/a/package-lock.json", Type: "lockfile"}
+	sbom := models.SourceInfo{Path: "/bom.cdx.json", Type: "sbom"}
+	query := func(source models.SourceInfo, name string, version string, ecosystem string) *osv.Query {
+		return &osv.Query{Source: source, Version: version, Package: osv.Package{Name: name, Ecosystem: ecosystem}}
+	}
+
+	checker.inspect(osv.BatchedQuery{Queries: []*osv.Query{
+		query(sbom, "wrappy", "1.0.0", "npm"),
+		query(lockfileA, "wrappy", "1.0.2", "npm"),
+		query(lockfileA, "left-pad", "1.1.0", "npm"),
+		query(lockfileA, "left-pad", "1.1.0", "npm"),
+		query(lockfileA, "@acme/utils", "0.1.0", "npm"),
+		query(lockfileA, "some/package", "1.0.0", "Packagist"),
+		{Source: models.SourceInfo{Path: "/repo", Type: "git"}, Commit: "abc"},
+	}})
+
+	want := []models.OutdatedPackage{
+		{
+			Source:  lockfileA,
+			Package: models.PackageInfo{Name: "left-pad", Version: "1.1.0", Ecosystem: "npm"},
+			Latest:  "1.3.0",
+			Behind:  "minor",
+		},
+		{
+			Source:  sbom,
+			Package: models.PackageInfo{Name: "wrappy", Version: "1.0.0", Ecosystem: "npm"},
+			Latest:  "1.0.2",
+			Behind:  "patch",
+		},
+	}
+
+	if diff := cmp.Diff(want, checker.findings()); diff != "" {
+		t.Errorf("findings() mismatch (-want +got):\n%s", diff)
+	}
+
+	// the latest version of each package is only looked up once
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("expected 2 requests to the registry, got %d", got)
+	}
+}
@@ -0,0 +1,89 @@
+package osvscanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/output"
+	"github.com/google/osv-scanner/pkg/workspaces"
+)
+
+// ownedSourceTypes are the types of sources that are files within a
+// repository, and so can have owners
+var ownedSourceTypes = map[string]bool{
+	"lockfile": true,
+	"manifest": true,
+	"sbom":     true,
+	"artifact": true,
+	"binary":   true,
+}
+
+// repositoryRoot returns the root of the git repository that the directory is
+// in, or false if it is not in one
+func repositoryRoot(dir string) (string, bool) {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// attributeOwners sets the owners of the packages of each source to those
+// that the CODEOWNERS file of the repository the source is in gives for its
+// path, so that findings can be routed to the teams that own them
+func attributeOwners(r output.Reporter, results *models.VulnerabilityResults) {
+	roots := map[string]string{}
+	codeowners := map[string]workspaces.Codeowners{}
+
+	for i := range results.Results {
+		source := &results.Results[i]
+		if !ownedSourceTypes[source.Source.Type] {
+			continue
+		}
+
+		path, err := filepath.Abs(source.Source.Path)
+		if err != nil {
+			continue
+		}
+
+		dir := filepath.Dir(path)
+		root, ok := roots[dir]
+		if !ok {
+			root, _ = repositoryRoot(dir)
+			roots[dir] = root
+		}
+		if root == "" {
+			continue
+		}
+
+		rules, ok := codeowners[root]
+		if !ok {
+			rules, err = workspaces.LoadCodeowners(root)
+			if err != nil {
+				r.PrintText(fmt.Sprintf("Failed to read CODEOWNERS of %s: %v\n", root, err))
+			}
+			codeowners[root] = rules
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			continue
+		}
+		owners := rules.Owners(filepath.ToSlash(rel))
+		if len(owners) == 0 {
+			continue
+		}
+
+		for j := range source.Packages {
+			source.Packages[j].Owners = owners
+		}
+	}
+}
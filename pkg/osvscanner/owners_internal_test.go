@@ -0,0 +1,77 @@
+package osvscanner
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+func Test_attributeOwners(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	repo := filepath.Join(dir, "repo")
+	writeFiles(t, repo, map[string]string{
+		".git/HEAD": "ref: refs/heads/main\n",
+		".github/CODEOWNERS": "* @acme/platform\n" +
+			"/services/billing/ @acme/billing @alice\n" +
+			"*.cdx.json @acme/security\n",
+		"services/billing/package-lock.json": "{}",
+		"services/api/go.mod":                "module example.com/api\n",
+		"sbom/app.cdx.json":                  "{}",
+	})
+	writeFiles(t, dir, map[string]string{
+		"outside/Cargo.lock": "",
+	})
+
+	pkg := models.PackageVulns{Package: models.PackageInfo{Name: "left-pad", Version: "1.0.0", Ecosystem: "npm"}}
+	results := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source:   models.SourceInfo{Path: filepath.Join(repo, "services/billing/package-lock.json"), Type: "lockfile"},
+				Packages: []models.PackageVulns{pkg, pkg},
+			},
+			{
+				Source:   models.SourceInfo{Path: filepath.Join(repo, "services/api/go.mod"), Type: "lockfile"},
+				Packages: []models.PackageVulns{pkg},
+			},
+			{
+				Source:   models.SourceInfo{Path: filepath.Join(repo, "sbom/app.cdx.json"), Type: "sbom"},
+				Packages: []models.PackageVulns{pkg},
+			},
+			{
+				Source:   models.SourceInfo{Path: filepath.Join(dir, "outside/Cargo.lock"), Type: "lockfile"},
+				Packages: []models.PackageVulns{pkg},
+			},
+			{
+				Source:   models.SourceInfo{Path: "alpine:3.18", Type: "docker"},
+				Packages: []models.PackageVulns{pkg},
+			},
+		},
+	}
+
+	attributeOwners(output.NewVoidReporter(), &results)
+
+	want := [][]string{
+		{"@acme/billing", "@alice"},
+		{"@acme/billing", "@alice"},
+		{"@acme/platform"},
+		{"@acme/security"},
+		nil,
+		nil,
+	}
+
+	var got [][]string
+	for _, source := range results.Results {
+		for _, pkg := range source.Packages {
+			got = append(got, pkg.Owners)
+		}
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("owners mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,85 @@
+package osvscanner
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/osv-scanner/pkg/config"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/output"
+
+	"golang.org/x/exp/slices"
+)
+
+// ParseParserOverrides parses overrides of what files found while scanning
+// directories are parsed as, given as "PATH=PARSER" such as
+// "renamed-requirements.txt=requirements.txt", where PATH is a pattern with
+// the same syntax as a .gitignore file relative to the directory being
+// scanned, and PARSER is one of lockfile.ListParsers
+func ParseParserOverrides(entries []string) ([]config.ParseAsEntry, error) {
+	overrides := make([]config.ParseAsEntry, 0, len(entries))
+
+	for _, entry := range entries {
+		path, parser, ok := strings.Cut(entry, "=")
+		path, parser = strings.TrimSpace(path), strings.TrimSpace(parser)
+		if !ok || path == "" {
+			return nil, fmt.Errorf("%q is not a path and parser, expected PATH=PARSER", entry)
+		}
+		if !slices.Contains(lockfile.ListParsers(), parser) {
+			return nil, fmt.Errorf("%q is not a parser, expected one of %s", parser, strings.Join(lockfile.ListParsers(), ", "))
+		}
+
+		overrides = append(overrides, config.ParseAsEntry{Path: path, Parser: parser})
+	}
+
+	return overrides, nil
+}
+
+// parserOverrides finds what the files found while walking directories
+// could be parsed as, by the overrides given to the scan and those of the
+// config that applies to each file
+type parserOverrides struct {
+	entries []config.ParseAsEntry
+
+	// configs are only used to find the parsers of files, so they are loaded
+	// without reporting anything, leaving that to when they are used for
+	// filtering the results
+	mu      sync.Mutex
+	configs config.ConfigManager
+}
+
+func newParserOverrides(entries []config.ParseAsEntry, override *config.Config) *parserOverrides {
+	return &parserOverrides{
+		entries: entries,
+		configs: config.ConfigManager{
+			OverrideConfig: override,
+			DefaultConfig:  config.Config{},
+			ConfigMap:      make(map[string]config.Config),
+		},
+	}
+}
+
+// parseAs returns the parsers that the file at path, which was found while
+// walking dir, could be parsed with, being those of the overrides given to the
+// scan before those of its config, or nothing if it should be parsed by its
+// name
+func (o *parserOverrides) parseAs(dir string, path string) []string {
+	if o == nil {
+		return nil
+	}
+
+	parsers := config.MatchParseAs(o.entries, dir, path)
+
+	o.mu.Lock()
+	configToUse := o.configs.Get(output.NewVoidReporter(), path)
+	o.mu.Unlock()
+
+	for _, parser := range configToUse.ParsersFor(path) {
+		if !slices.Contains(parsers, parser) {
+			parsers = append(parsers, parser)
+		}
+	}
+
+	return parsers
+}
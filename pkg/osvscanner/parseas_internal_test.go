@@ -0,0 +1,100 @@
+package osvscanner
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/config"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+func TestParseParserOverrides(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		entries []string
+		want    []config.ParseAsEntry
+		wantErr string
+	}{
+		{
+			entries: []string{"renamed-requirements.txt=requirements.txt", " locks/*.lock = Cargo.lock "},
+			want: []config.ParseAsEntry{
+				{Path: "renamed-requirements.txt", Parser: "requirements.txt"},
+				{Path: "locks/*.lock", Parser: "Cargo.lock"},
+			},
+		},
+		{entries: []string{"requirements.txt"}, wantErr: `"requirements.txt" is not a path and parser, expected PATH=PARSER`},
+		{entries: []string{"=requirements.txt"}, wantErr: `"=requirements.txt" is not a path and parser, expected PATH=PARSER`},
+		{entries: []string{"deps.lock=deps.lock"}, wantErr: `"deps.lock" is not a parser`},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(strings.Join(tt.entries, ","), func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseParserOverrides(tt.entries)
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseParserOverrides() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseParserOverrides() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestScanDir_ParserOverrides(t *testing.T) {
+	t.Parallel()
+
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("could not resolve directory: %v", err)
+	}
+	writeFiles(t, dir, map[string]string{
+		"renamed-requirements.txt": "flask==1.0.0\n",
+		"api/osv-scanner.toml":     "[[ParseAs]]\npath = \"pins.txt\"\nparser = \"requirements.txt\"\n",
+		"api/pins.txt":             "django==1.0.0\n",
+		"web/pins.txt":             "requests==2.0.0\n",
+		"locked.txt":               `{"default": {"markupsafe": {"version": "==2.1.1"}}, "develop": {}}`,
+	})
+
+	r := output.NewReporter(io.Discard, io.Discard, "table")
+	stream := newQueryStream(context.Background(), false, 1)
+	var issues scanIssues
+	overrides := newParserOverrides([]config.ParseAsEntry{
+		{Path: "renamed-requirements.txt", Parser: "requirements.txt"},
+		// the lines of the lockfile would also be parsed as requirements
+		{Path: "locked.txt", Parser: "requirements.txt"},
+		{Path: "locked.txt", Parser: "Pipfile.lock"},
+	}, nil)
+
+	err = scanDir(r, stream, &issues, scanLimits{}, packageScope{}, nil, nil, overrides, dir, false, NestedReposScan, true, true, false, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []string
+	for _, query := range stream.pending.Queries {
+		got = append(got, filepath.ToSlash(strings.TrimPrefix(query.Source.Path, dir))+":"+query.Package.Name)
+	}
+	sort.Strings(got)
+
+	// the config of the api directory only applies to the files within it
+	want := []string{"/api/pins.txt:django", "/locked.txt:markupsafe", "/renamed-requirements.txt:flask"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("scanDir() mismatch (-want +got):\n%s", diff)
+	}
+}
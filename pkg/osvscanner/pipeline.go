@@ -0,0 +1,127 @@
+package osvscanner
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+// errWalkStopped stops a walk once the results of the work it finds are no
+// longer wanted
+var errWalkStopped = errors.New("walk stopped")
+
+// dirJob is work found while walking a directory, such as parsing a file,
+// which is done with its own query, issues and buffered reporter so that its
+// results can be applied in the order the work was found
+type dirJob struct {
+	work func(r output.Reporter, query *osv.BatchedQuery, issues *scanIssues) error
+	done chan struct{}
+
+	r      *output.BufferedReporter
+	query  osv.BatchedQuery
+	issues scanIssues
+	err    error
+}
+
+// dirPipeline does the work found while walking a directory with a pool of
+// workers as the walk continues, while applying the results of each piece of
+// work in the order it was found, so that scanning with any number of workers
+// reports the same results in the same order
+type dirPipeline struct {
+	// r is the reporter that the results are replayed onto, which the
+	// reporter of each job translates messages as
+	r       output.Reporter
+	jobs    chan *dirJob
+	ordered chan *dirJob
+	stop    chan struct{}
+	wg      sync.WaitGroup
+
+	// blocked is how long the walk has spent waiting for work to be done
+	blocked time.Duration
+}
+
+// newDirPipeline starts `workers` workers, using the number of CPUs if it is
+// not positive
+func newDirPipeline(r output.Reporter, workers int) *dirPipeline {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	p := &dirPipeline{
+		r:    r,
+		jobs: make(chan *dirJob, workers),
+		// enough work is queued to keep every worker busy while the results
+		// of the oldest piece of work are waited on
+		ordered: make(chan *dirJob, 2*workers),
+		stop:    make(chan struct{}),
+	}
+
+	for i := 0; i < workers; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			for job := range p.jobs {
+				job.err = job.work(job.r, &job.query, &job.issues)
+				close(job.done)
+			}
+		}()
+	}
+
+	return p
+}
+
+// submit queues the work to be done, returning errWalkStopped if its results
+// are no longer wanted
+func (p *dirPipeline) submit(work func(r output.Reporter, query *osv.BatchedQuery, issues *scanIssues) error) error {
+	job := &dirJob{work: work, done: make(chan struct{}), r: output.NewBufferedReporter(p.r)}
+
+	start := time.Now()
+	defer func() {
+		p.blocked += time.Since(start)
+	}()
+
+	select {
+	case p.ordered <- job:
+	case <-p.stop:
+		return errWalkStopped
+	}
+	p.jobs <- job
+
+	return nil
+}
+
+// run walks in the background with `walk`, which submits the work it finds,
+// applying the results of each piece of work in order with `apply` until the
+// walk is done or `apply` returns an error
+func (p *dirPipeline) run(walk func() error, apply func(job *dirJob) error) error {
+	walkErr := make(chan error, 1)
+	go func() {
+		walkErr <- walk()
+		close(p.ordered)
+		close(p.jobs)
+	}()
+
+	var err error
+	for job := range p.ordered {
+		// keep draining the queue once stopped, so that the walk can finish
+		if err != nil {
+			continue
+		}
+
+		<-job.done
+		if err = apply(job); err != nil {
+			close(p.stop)
+		}
+	}
+	p.wg.Wait()
+
+	if err != nil {
+		return err
+	}
+
+	return <-walkErr
+}
@@ -0,0 +1,104 @@
+package osvscanner
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+func TestDirPipeline_AppliesInOrder(t *testing.T) {
+	t.Parallel()
+
+	r := output.NewReporter(io.Discard, io.Discard, "table")
+	pipeline := newDirPipeline(r, 8)
+
+	walk := func() error {
+		for i := 0; i < 50; i++ {
+			i := i
+			err := pipeline.submit(func(_ output.Reporter, query *osv.BatchedQuery, _ *scanIssues) error {
+				// finish the work out of order
+				time.Sleep(time.Duration((50-i)%7) * time.Millisecond)
+				query.Queries = append(query.Queries, osv.MakeCommitRequest(fmt.Sprint(i)))
+
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	var got []string
+	err := pipeline.run(walk, func(job *dirJob) error {
+		for _, query := range job.query.Queries {
+			got = append(got, query.Commit)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var want []string
+	for i := 0; i < 50; i++ {
+		want = append(want, fmt.Sprint(i))
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("applied jobs mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDirPipeline_StopsOnError(t *testing.T) {
+	t.Parallel()
+
+	r := output.NewReporter(io.Discard, io.Discard, "table")
+	pipeline := newDirPipeline(r, 2)
+	errParse := errors.New("could not parse")
+
+	submitted := 0
+	walk := func() error {
+		for i := 0; i < 1000; i++ {
+			i := i
+			err := pipeline.submit(func(_ output.Reporter, _ *osv.BatchedQuery, _ *scanIssues) error {
+				if i == 3 {
+					return errParse
+				}
+
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			submitted++
+		}
+
+		return nil
+	}
+
+	applied := 0
+	err := pipeline.run(walk, func(job *dirJob) error {
+		applied++
+
+		return job.err
+	})
+
+	if !errors.Is(err, errParse) {
+		t.Errorf("expected the error of the work, got %v", err)
+	}
+	if applied != 4 {
+		t.Errorf("expected 4 jobs to have been applied, got %d", applied)
+	}
+	if submitted == 1000 {
+		t.Errorf("expected the walk to have been stopped")
+	}
+}
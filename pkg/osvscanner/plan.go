@@ -0,0 +1,168 @@
+package osvscanner
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/google/osv-scanner/pkg/config"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/manifest"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+// planDirFile returns how a file found by walking a directory would be
+// scanned, given what it could be parsed as, reporting false for files that
+// would only be tried as SBOMs and Go binaries, as that depends on their
+// contents
+func planDirFile(limits scanLimits, resolveManifests bool, path string, parseAs []string) (models.PlannedInput, bool) {
+	if isLockfile, parsedAs := findDirFileParser(path, parseAs); isLockfile {
+		input := models.PlannedInput{Source: models.SourceInfo{Path: path, Type: "lockfile"}, Parser: parsedAs}
+		if err := limits.checkFileSize(path); err != nil {
+			input.Skipped = err.Error()
+		}
+
+		return input, true
+	}
+
+	if resolveManifests && manifest.IsManifest(path) && manifest.FindLockfile(path) == "" {
+		return models.PlannedInput{Source: models.SourceInfo{Path: path, Type: "manifest"}}, true
+	}
+
+	return models.PlannedInput{}, false
+}
+
+// planDir walks the directory as scanDir would, without parsing anything, to
+// find the files that would be parsed, the git repositories that would be
+// queried, and the files and directories that would be skipped
+func planDir(r output.Reporter, limits scanLimits, resolveManifests bool, overrides *parserOverrides, dir string, skipGit bool, nestedRepos string, recursive bool, useGitIgnore bool) ([]models.PlannedInput, error) {
+	var ignoreMatcher *gitIgnoreMatcher
+	if useGitIgnore {
+		var err error
+		ignoreMatcher, err = parseGitIgnores(dir)
+		if err != nil {
+			// the walk carries on without ignoring anything
+			r.PrintError(fmt.Sprintf("Unable to parse git ignores: %v", err))
+		}
+	}
+
+	var plan []models.PlannedInput
+	report := func(print func(r output.Reporter)) {
+		print(r)
+	}
+
+	err := walkDir(dir, ignoreMatcher, skipGit, nestedRepos, recursive, report, func(found walkFound) error {
+		switch found.kind {
+		case foundGit:
+			plan = append(plan, models.PlannedInput{Source: models.SourceInfo{Path: found.repoDir, Type: "git"}})
+		case foundFile:
+			if input, ok := planDirFile(limits, resolveManifests, found.path, overrides.parseAs(dir, found.path)); ok {
+				plan = append(plan, input)
+			}
+		case foundSkipped:
+			kind := "file"
+			switch {
+			case found.reason == skippedNestedRepo:
+				kind = "git"
+			case found.isDir:
+				kind = "directory"
+			}
+			plan = append(plan, models.PlannedInput{Source: models.SourceInfo{Path: found.path, Type: kind}, Skipped: found.reason})
+		}
+
+		return nil
+	})
+
+	return plan, err
+}
+
+// planScan returns the inputs that the scan would scan and skip, without
+// parsing or querying any of them
+func planScan(r output.Reporter, actions ScannerActions) (models.VulnerabilityResults, error) {
+	limits := limitsFor(actions)
+	results := models.VulnerabilityResults{Results: []models.PackageSource{}}
+
+	var configManager config.ConfigManager
+	if actions.ConfigOverridePath != "" {
+		if err := configManager.UseOverride(r, actions.ConfigOverridePath); err != nil {
+			r.PrintError(fmt.Sprintf("Failed to read config file: %s\n", err))
+			return models.VulnerabilityResults{}, &ConfigError{Path: actions.ConfigOverridePath, Err: err}
+		}
+	}
+	overrides := newParserOverrides(actions.ParserOverrides, configManager.OverrideConfig)
+
+	for _, container := range actions.DockerContainerNames {
+		results.Plan = append(results.Plan, models.PlannedInput{Source: models.SourceInfo{Path: container, Type: "docker"}})
+	}
+
+	if actions.HostRoot != "" {
+		results.Plan = append(results.Plan, models.PlannedInput{Source: models.SourceInfo{Path: actions.HostRoot, Type: "host"}})
+	}
+
+	for _, lockfileElem := range actions.LockfilePaths {
+		parseAs, lockfilePath := parseLockfilePath(lockfileElem)
+		lockfilePath, err := filepath.Abs(lockfilePath)
+		if err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+
+		input := models.PlannedInput{Source: models.SourceInfo{Path: lockfilePath, Type: "lockfile"}}
+		parser, parsedAs := lockfile.FindParser(lockfilePath, parseAs)
+		switch {
+		// the APK parser is not found by name, as with scanLockfile
+		case parser == nil && parseAs == "":
+			input.Skipped = fmt.Sprintf("%v for %s", lockfile.ErrParserNotFound, lockfilePath)
+		case parser == nil && parseAs != "apk-installed":
+			input.Skipped = fmt.Sprintf("%v, requested %s", lockfile.ErrParserNotFound, parseAs)
+		default:
+			input.Parser = parsedAs
+			if err := limits.checkFileSize(lockfilePath); err != nil {
+				input.Skipped = err.Error()
+			}
+		}
+		results.Plan = append(results.Plan, input)
+	}
+
+	for _, sbomElem := range actions.SBOMPaths {
+		sbomElem, err := filepath.Abs(sbomElem)
+		if err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+		results.Plan = append(results.Plan, models.PlannedInput{Source: models.SourceInfo{Path: sbomElem, Type: "sbom"}})
+	}
+
+	for _, artifactElem := range actions.ArtifactPaths {
+		artifactElem, err := filepath.Abs(artifactElem)
+		if err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+		results.Plan = append(results.Plan, models.PlannedInput{Source: models.SourceInfo{Path: artifactElem, Type: "artifact"}})
+	}
+
+	for _, commit := range actions.GitCommits {
+		results.Plan = append(results.Plan, models.PlannedInput{Source: models.SourceInfo{Path: commit, Type: "git"}})
+	}
+
+	for _, dir := range actions.DirectoryPaths {
+		r.PrintText(fmt.Sprintf("Planning the scan of dir %s\n", dir))
+		plan, err := planDir(r, limits, actions.ResolveManifests, overrides, dir, actions.SkipGit, actions.NestedRepos, actions.Recursive, !actions.NoIgnore)
+		if err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+		results.Plan = append(results.Plan, plan...)
+	}
+
+	if actions.Reproducible {
+		makeReproducible(&results)
+	}
+
+	scanned := 0
+	for _, input := range results.Plan {
+		if input.Skipped == "" {
+			scanned++
+		}
+	}
+	r.PrintText(fmt.Sprintf("Would scan %d inputs and skip %d\n", scanned, len(results.Plan)-scanned))
+
+	return results, nil
+}
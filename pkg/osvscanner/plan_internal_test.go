@@ -0,0 +1,140 @@
+package osvscanner
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+func TestPlanDir(t *testing.T) {
+	t.Parallel()
+
+	dir, err := filepath.EvalSymlinks(makeRepository(t, map[string]string{
+		".gitignore":       "build/\n*.tmp\n",
+		"requirements.txt": "flask==1.0.0\n",
+	}))
+	if err != nil {
+		t.Fatalf("could not resolve directory: %v", err)
+	}
+	writeFiles(t, dir, map[string]string{
+		"build/requirements.txt": "django==1.0.0\n",
+		"web/package-lock.json":  `{"lockfileVersion": 2, "packages": {"node_modules/lodash": {"version": "4.17.20"}}}`,
+		"web/package.json":       `{"dependencies": {"lodash": "^4.17.20"}}`,
+		"README.md":              "# project\n",
+		"lockfile.tmp":           "",
+		"api/package.json":       `{"dependencies": {"express": "^4.0.0"}}`,
+	})
+	vendored := makeRepository(t, map[string]string{"Cargo.lock": ""})
+	if _, err := git.PlainClone(filepath.Join(dir, "third_party", "lib"), false, &git.CloneOptions{URL: vendored}); err != nil {
+		t.Fatalf("could not clone repository: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		nestedRepos string
+		resolve     bool
+		want        []string
+	}{
+		{
+			name:        "scanning nested repositories",
+			nestedRepos: NestedReposScan,
+			want: []string{
+				"git:/",
+				"directory:/build (ignored by git)",
+				"file:/lockfile.tmp (ignored by git)",
+				"lockfile:/requirements.txt as requirements.txt",
+				"git:/third_party/lib/",
+				"lockfile:/third_party/lib/Cargo.lock as Cargo.lock",
+				"lockfile:/web/package-lock.json as package-lock.json",
+			},
+		},
+		{
+			name:        "skipping nested repositories",
+			nestedRepos: NestedReposSkip,
+			resolve:     true,
+			want: []string{
+				"git:/",
+				"manifest:/api/package.json",
+				"directory:/build (ignored by git)",
+				"file:/lockfile.tmp (ignored by git)",
+				"lockfile:/requirements.txt as requirements.txt",
+				"git:/third_party/lib (nested git repository)",
+				"lockfile:/web/package-lock.json as package-lock.json",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := output.NewReporter(io.Discard, io.Discard, "table")
+			plan, err := planDir(r, scanLimits{}, tt.resolve, nil, dir, false, tt.nestedRepos, true, true)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, planEntries(dir, plan)); diff != "" {
+				t.Errorf("planDir() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// planEntries describes each input of the plan by its type and path relative
+// to dir, along with what it would be parsed as or why it would be skipped
+func planEntries(dir string, plan []models.PlannedInput) []string {
+	var entries []string
+	for _, input := range plan {
+		entry := input.Source.Type + ":" + filepath.ToSlash(strings.TrimPrefix(input.Source.Path, dir))
+		if input.Parser != "" {
+			entry += " as " + input.Parser
+		}
+		if input.Skipped != "" {
+			entry += " (" + input.Skipped + ")"
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+func TestDoScan_DryRun(t *testing.T) {
+	t.Parallel()
+
+	results, err := DoScan(ScannerActions{
+		LockfilePaths: []string{
+			"package-lock.json:../lockfile/fixtures/npm/one-package.v2.json",
+			"../lockfile/fixtures/npm/one-package.v2.json",
+		},
+		SBOMPaths:    []string{"../../fixtures/sbom-insecure/unknown-ecosystem.cdx.json"},
+		GitCommits:   []string{"9d1b2f0"},
+		DryRun:       true,
+		Reproducible: true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []models.PlannedInput{
+		{
+			Source: models.SourceInfo{Path: "../lockfile/fixtures/npm/one-package.v2.json", Type: "lockfile"},
+			Parser: "package-lock.json",
+		},
+		{
+			Source:  models.SourceInfo{Path: "../lockfile/fixtures/npm/one-package.v2.json", Type: "lockfile"},
+			Skipped: "could not determine parser for ../lockfile/fixtures/npm/one-package.v2.json",
+		},
+		{Source: models.SourceInfo{Path: "../../fixtures/sbom-insecure/unknown-ecosystem.cdx.json", Type: "sbom"}},
+		{Source: models.SourceInfo{Path: "9d1b2f0", Type: "git"}},
+	}
+	if diff := cmp.Diff(want, results.Plan); diff != "" {
+		t.Errorf("Plan mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,211 @@
+package osvscanner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/osv-scanner/pkg/models"
+
+	"golang.org/x/exp/slices"
+)
+
+// ParseGracePeriods parses grace periods given as "SEVERITY=DAYS", such as
+// "HIGH=7", or as a number of days for every severity that is not given its
+// own, which is stored under "". Severities are one of models.Severities, or
+// "UNKNOWN" for vulnerabilities whose severity is not known.
+func ParseGracePeriods(entries []string) (map[string]int, error) {
+	periods := map[string]int{}
+
+	for _, entry := range entries {
+		severity, days, ok := strings.Cut(entry, "=")
+		if !ok {
+			severity, days = "", entry
+		}
+
+		severity = strings.ToUpper(strings.TrimSpace(severity))
+		if severity == "MODERATE" {
+			severity = "MEDIUM"
+		}
+		if severity != "" && severity != "UNKNOWN" && !slices.Contains(models.Severities, severity) {
+			return nil, fmt.Errorf("%q is not a severity, expected one of %s or UNKNOWN", severity, strings.Join(models.Severities, ", "))
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(days))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%q is not a number of days", days)
+		}
+
+		periods[severity] = n
+	}
+
+	return periods, nil
+}
+
+// ParseSeverity parses the severity that vulnerabilities must have at least
+// to fail the scan, being one of models.Severities or "MODERATE", or "" for
+// any severity
+func ParseSeverity(severity string) (string, error) {
+	if strings.TrimSpace(severity) == "" {
+		return "", nil
+	}
+
+	normalized := models.NormalizeSeverity(severity)
+	if normalized == "" {
+		return "", fmt.Errorf("%q is not a severity, expected one of %s", severity, strings.Join(models.Severities, ", "))
+	}
+
+	return normalized, nil
+}
+
+// failPolicy decides which vulnerabilities fail the scan
+type failPolicy struct {
+	// threshold is the score that vulnerabilities must have at least, unless
+	// their score is not known
+	threshold float64
+	// severity is the severity that vulnerabilities must have at least, unless
+	// their severity is not known
+	severity string
+	// sourceSeverity returns the severity that the config of a source sets
+	// for its vulnerabilities instead of severity, if it sets one
+	sourceSeverity func(source models.SourceInfo) string
+	// fixableOnly only fails for vulnerabilities that have a fix
+	fixableOnly bool
+	// gracePeriods are how many days after being published vulnerabilities
+	// of each severity start failing the scan, see ParseGracePeriods
+	gracePeriods map[string]int
+	now          time.Time
+}
+
+func newFailPolicy(actions ScannerActions) failPolicy {
+	return failPolicy{
+		threshold:    actions.FailOnScore,
+		severity:     actions.FailOnSeverity,
+		fixableOnly:  actions.FailOnlyIfFixAvailable,
+		gracePeriods: actions.GracePeriods,
+		now:          time.Now(),
+	}
+}
+
+// hasFix reports if a version of the package has been published that fixes
+// any of the vulnerabilities of the group
+func hasFix(pkg models.PackageVulns, group models.GroupInfo) bool {
+	var fixedVersions []string
+	for _, vuln := range groupVulnerabilities(pkg, group) {
+		fixedVersions = append(fixedVersions, vuln.FixedVersions(pkg.Package)...)
+	}
+
+	return nearestFix(pkg.Package, fixedVersions) != ""
+}
+
+// published returns when the first of the advisories of the group was
+// published, or the zero time if none of them say
+func published(pkg models.PackageVulns, group models.GroupInfo) time.Time {
+	var first time.Time
+	for _, vuln := range groupVulnerabilities(pkg, group) {
+		if !vuln.Published.IsZero() && (first.IsZero() || vuln.Published.Before(first)) {
+			first = vuln.Published
+		}
+	}
+
+	return first
+}
+
+// inGracePeriod reports if the group was published too recently to fail the
+// scan, with groups that do not say when they were published never being in
+// their grace period
+func (p failPolicy) inGracePeriod(pkg models.PackageVulns, group models.GroupInfo) bool {
+	severity := group.Severity
+	if severity == "" {
+		severity = models.HighestSeverity(groupVulnerabilities(pkg, group))
+	}
+	if severity == "" {
+		severity = "UNKNOWN"
+	}
+
+	days, ok := p.gracePeriods[severity]
+	if !ok {
+		days, ok = p.gracePeriods[""]
+	}
+	if !ok || days == 0 {
+		return false
+	}
+
+	first := published(pkg, group)
+
+	return !first.IsZero() && p.now.Before(first.AddDate(0, 0, days))
+}
+
+// forSource returns the policy for the vulnerabilities of the source, which
+// uses the severity set by its config if there is one
+func (p failPolicy) forSource(source models.SourceInfo) failPolicy {
+	if p.sourceSeverity == nil {
+		return p
+	}
+	if severity := p.sourceSeverity(source); severity != "" {
+		p.severity = severity
+	}
+
+	return p
+}
+
+// applies reports if the group of vulnerabilities is one that the policy
+// fails the scan for, by not having been withdrawn, meeting the score and
+// severity thresholds and having a fix if it must, regardless of when it was
+// published
+func (p failPolicy) applies(pkg models.PackageVulns, group models.GroupInfo) bool {
+	if group.Withdrawn {
+		return false
+	}
+
+	if group.Score != 0 && group.Score < p.threshold {
+		return false
+	}
+
+	if p.severity != "" {
+		severity := group.Severity
+		if severity == "" {
+			severity = models.HighestSeverity(groupVulnerabilities(pkg, group))
+		}
+		if severity != "" && !models.SeverityAtLeast(severity, p.severity) {
+			return false
+		}
+	}
+
+	return !p.fixableOnly || hasFix(pkg, group)
+}
+
+// fails reports if any of the groups of vulnerabilities should fail the scan
+func (p failPolicy) fails(results models.VulnerabilityResults) bool {
+	for _, source := range results.Results {
+		policy := p.forSource(source.Source)
+		for _, pkg := range source.Packages {
+			for _, group := range pkg.Groups {
+				if policy.applies(pkg, group) && !policy.inGracePeriod(pkg, group) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// countInGracePeriod counts the groups of vulnerabilities that are only kept
+// from failing the scan by being in their grace period
+func (p failPolicy) countInGracePeriod(results models.VulnerabilityResults) int {
+	count := 0
+	for _, source := range results.Results {
+		policy := p.forSource(source.Source)
+		for _, pkg := range source.Packages {
+			for _, group := range pkg.Groups {
+				if policy.applies(pkg, group) && policy.inGracePeriod(pkg, group) {
+					count++
+				}
+			}
+		}
+	}
+
+	return count
+}
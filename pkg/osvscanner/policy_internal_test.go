@@ -0,0 +1,242 @@
+package osvscanner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestParseGracePeriods(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		entries []string
+		want    map[string]int
+		wantErr bool
+	}{
+		{name: "none", entries: nil, want: map[string]int{}},
+		{
+			name:    "per severity and default",
+			entries: []string{"critical=0", "HIGH=7", "moderate = 30", "60"},
+			want:    map[string]int{"CRITICAL": 0, "HIGH": 7, "MEDIUM": 30, "": 60},
+		},
+		{name: "unknown severity", entries: []string{"UNKNOWN=14"}, want: map[string]int{"UNKNOWN": 14}},
+		{name: "not a severity", entries: []string{"SEVERE=7"}, wantErr: true},
+		{name: "not a number", entries: []string{"HIGH=a week"}, wantErr: true},
+		{name: "negative", entries: []string{"-1"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseGracePeriods(tt.entries)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseGracePeriods() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseGracePeriods() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFailPolicy_FixAvailable(t *testing.T) {
+	t.Parallel()
+
+	minimist := models.PackageInfo{Name: "minimist", Version: "1.2.0", Ecosystem: "npm"}
+	results := func(vulns ...models.Vulnerability) models.VulnerabilityResults {
+		pkg := models.PackageVulns{Package: minimist, Vulnerabilities: vulns}
+		for _, vuln := range vulns {
+			pkg.Groups = append(pkg.Groups, models.GroupInfo{IDs: []string{vuln.ID}, Score: 7.5})
+		}
+
+		return models.VulnerabilityResults{Results: []models.PackageSource{{Packages: []models.PackageVulns{pkg}}}}
+	}
+
+	fixed := vulnerabilityFixedIn(t, "GHSA-fixed", minimist, "1.2.6")
+	unfixed := vulnerabilityFixedIn(t, "GHSA-unfixed", minimist)
+	// only fixed on an older branch than the version that is used
+	fixedBefore := vulnerabilityFixedIn(t, "GHSA-fixed-before", minimist, "0.2.1")
+
+	tests := []struct {
+		name    string
+		results models.VulnerabilityResults
+		policy  failPolicy
+		want    bool
+	}{
+		{name: "unfixed without the policy", results: results(unfixed), want: true},
+		{name: "unfixed", results: results(unfixed), policy: failPolicy{fixableOnly: true}, want: false},
+		{name: "fixed before the version", results: results(fixedBefore), policy: failPolicy{fixableOnly: true}, want: false},
+		{name: "fixed", results: results(unfixed, fixed), policy: failPolicy{fixableOnly: true}, want: true},
+		{name: "fixed but below threshold", results: results(fixed), policy: failPolicy{threshold: 9, fixableOnly: true}, want: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.policy.fails(tt.results); got != tt.want {
+				t.Errorf("fails() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFailPolicy_GracePeriods(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 6, 15, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time {
+		return now.AddDate(0, 0, -days)
+	}
+
+	lodash := models.PackageInfo{Name: "lodash", Version: "4.17.0", Ecosystem: "npm"}
+	results := func(severity string, published ...time.Time) models.VulnerabilityResults {
+		pkg := models.PackageVulns{Package: lodash}
+		group := models.GroupInfo{Severity: severity}
+		for i, date := range published {
+			vuln := models.Vulnerability{ID: "GHSA-" + string(rune('a'+i)), Published: date}
+			pkg.Vulnerabilities = append(pkg.Vulnerabilities, vuln)
+			group.IDs = append(group.IDs, vuln.ID)
+		}
+		pkg.Groups = []models.GroupInfo{group}
+
+		return models.VulnerabilityResults{Results: []models.PackageSource{{Packages: []models.PackageVulns{pkg}}}}
+	}
+
+	periods := map[string]int{"CRITICAL": 0, "HIGH": 7, "": 30}
+
+	tests := []struct {
+		name    string
+		results models.VulnerabilityResults
+		want    bool
+	}{
+		{name: "critical are never in a grace period", results: results("CRITICAL", daysAgo(0)), want: true},
+		{name: "high within its period", results: results("HIGH", daysAgo(6)), want: false},
+		{name: "high after its period", results: results("HIGH", daysAgo(7)), want: true},
+		{name: "low within the default period", results: results("LOW", daysAgo(29)), want: false},
+		{name: "unknown after the default period", results: results("", daysAgo(31)), want: true},
+		{name: "earliest advisory of the group", results: results("HIGH", daysAgo(1), daysAgo(10)), want: true},
+		{name: "publication not known", results: results("HIGH", time.Time{}), want: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			policy := failPolicy{gracePeriods: periods, now: now}
+
+			if got := policy.fails(tt.results); got != tt.want {
+				t.Errorf("fails() = %v, want %v", got, tt.want)
+			}
+
+			wantCount := 0
+			if !tt.want {
+				wantCount = 1
+			}
+			if got := policy.countInGracePeriod(tt.results); got != wantCount {
+				t.Errorf("countInGracePeriod() = %d, want %d", got, wantCount)
+			}
+		})
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		severity string
+		want     string
+		wantErr  bool
+	}{
+		{severity: "", want: ""},
+		{severity: "high", want: "HIGH"},
+		{severity: "Moderate", want: "MEDIUM"},
+		{severity: "SEVERE", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.severity, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseSeverity(tt.severity)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSeverity() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSeverity() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFailPolicy_Severity(t *testing.T) {
+	t.Parallel()
+
+	lodash := models.PackageInfo{Name: "lodash", Version: "4.17.0", Ecosystem: "npm"}
+	results := func(path string, groupSeverity string, vulnSeverity string) models.VulnerabilityResults {
+		vuln := models.Vulnerability{ID: "GHSA-a", DatabaseSpecific: map[string]interface{}{"severity": vulnSeverity}}
+		pkg := models.PackageVulns{
+			Package:         lodash,
+			Vulnerabilities: []models.Vulnerability{vuln},
+			Groups:          []models.GroupInfo{{IDs: []string{vuln.ID}, Severity: groupSeverity}},
+		}
+
+		return models.VulnerabilityResults{Results: []models.PackageSource{{
+			Source:   models.SourceInfo{Path: path, Type: "lockfile"},
+			Packages: []models.PackageVulns{pkg},
+		}}}
+	}
+
+	configured := func(source models.SourceInfo) string {
+		if source.Path == "/legacy/package-lock.json" {
+			return "CRITICAL"
+		}
+
+		return ""
+	}
+
+	tests := []struct {
+		name    string
+		results models.VulnerabilityResults
+		policy  failPolicy
+		want    bool
+	}{
+		{name: "below the threshold", results: results("/package-lock.json", "MEDIUM", ""), policy: failPolicy{severity: "HIGH"}, want: false},
+		{name: "at the threshold", results: results("/package-lock.json", "HIGH", ""), policy: failPolicy{severity: "HIGH"}, want: true},
+		{name: "above the threshold", results: results("/package-lock.json", "CRITICAL", ""), policy: failPolicy{severity: "HIGH"}, want: true},
+		{name: "severity of the advisories", results: results("/package-lock.json", "", "moderate"), policy: failPolicy{severity: "HIGH"}, want: false},
+		{name: "severity not known", results: results("/package-lock.json", "", ""), policy: failPolicy{severity: "CRITICAL"}, want: true},
+		{
+			name:    "config of the source",
+			results: results("/legacy/package-lock.json", "HIGH", ""),
+			policy:  failPolicy{severity: "LOW", sourceSeverity: configured},
+			want:    false,
+		},
+		{
+			name:    "config of another source",
+			results: results("/package-lock.json", "HIGH", ""),
+			policy:  failPolicy{severity: "HIGH", sourceSeverity: configured},
+			want:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.policy.fails(tt.results); got != tt.want {
+				t.Errorf("fails() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package osvscanner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+// slowParseThreshold is how long parsing a single file can take before it is
+// warned about, as it likely dominates the time spent scanning
+const slowParseThreshold = 10 * time.Second
+
+// slowestSourcesReported is how many of the slowest sources are included in
+// the report of a profile
+const slowestSourcesReported = 5
+
+// Profile records the wall time spent in each phase of a scan, such as walking
+// directories, parsing with each parser, and querying the OSV API, along with
+// the time spent parsing and querying the packages of each source.
+//
+// A nil *Profile records nothing, so it can be passed around unconditionally.
+type Profile struct {
+	mu        sync.Mutex
+	phases    []string
+	durations map[string]time.Duration
+
+	sources     []models.SourceInfo
+	sourceTimes map[models.SourceInfo]*models.SourceTiming
+}
+
+// PhaseTiming is the total time spent in a single phase of a scan
+type PhaseTiming struct {
+	Phase    string
+	Duration time.Duration
+}
+
+func NewProfile() *Profile {
+	return &Profile{
+		durations:   make(map[string]time.Duration),
+		sourceTimes: make(map[models.SourceInfo]*models.SourceTiming),
+	}
+}
+
+// Add records that `d` more time was spent in the given phase
+func (p *Profile) Add(phase string, d time.Duration) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.durations[phase]; !ok {
+		p.phases = append(p.phases, phase)
+	}
+	p.durations[phase] += d
+}
+
+// Track starts timing the given phase, returning a function that records the
+// time spent once it is called
+func (p *Profile) Track(phase string) func() {
+	start := time.Now()
+
+	return func() {
+		p.Add(phase, time.Since(start))
+	}
+}
+
+// addSources records that `d` was spent on the queries, apportioning it across
+// their sources by how many of the queries each has, as time spent querying if
+// `querying` is set and as time spent parsing them into queries otherwise
+func (p *Profile) addSources(queries []*osv.Query, d time.Duration, querying bool) {
+	if p == nil || len(queries) == 0 {
+		return
+	}
+
+	counts := map[models.SourceInfo]int{}
+	var order []models.SourceInfo
+	for _, query := range queries {
+		if counts[query.Source] == 0 {
+			order = append(order, query.Source)
+		}
+		counts[query.Source]++
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, source := range order {
+		timing, ok := p.sourceTimes[source]
+		if !ok {
+			timing = &models.SourceTiming{Source: source}
+			p.sourceTimes[source] = timing
+			p.sources = append(p.sources, source)
+		}
+
+		share := d * time.Duration(counts[source]) / time.Duration(len(queries))
+		if querying {
+			timing.Query += share
+		} else {
+			timing.Parse += share
+			timing.Packages += counts[source]
+		}
+	}
+}
+
+// Sources returns the time spent on each source, from the slowest to the
+// fastest
+func (p *Profile) Sources() []models.SourceTiming {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.sources) == 0 {
+		return nil
+	}
+
+	timings := make([]models.SourceTiming, 0, len(p.sources))
+	for _, source := range p.sources {
+		timings = append(timings, *p.sourceTimes[source])
+	}
+
+	sort.SliceStable(timings, func(a, b int) bool {
+		return timings[a].Total() > timings[b].Total()
+	})
+
+	return timings
+}
+
+// Phases returns the time spent in each phase, in the order they were first
+// recorded
+func (p *Profile) Phases() []PhaseTiming {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	timings := make([]PhaseTiming, 0, len(p.phases))
+	for _, phase := range p.phases {
+		timings = append(timings, PhaseTiming{Phase: phase, Duration: p.durations[phase]})
+	}
+
+	return timings
+}
+
+// String formats the time spent in each phase as an aligned report, ending
+// with the total across all phases
+func (p *Profile) String() string {
+	timings := p.Phases()
+
+	width := len("total")
+	var total time.Duration
+	for _, timing := range timings {
+		if len(timing.Phase) > width {
+			width = len(timing.Phase)
+		}
+		total += timing.Duration
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Scan profile:\n")
+	for _, timing := range timings {
+		fmt.Fprintf(&sb, "  %-*s %10s\n", width, timing.Phase, formatDuration(timing.Duration))
+	}
+	fmt.Fprintf(&sb, "  %-*s %10s\n", width, "total", formatDuration(total))
+
+	sources := p.Sources()
+	if len(sources) > slowestSourcesReported {
+		sources = sources[:slowestSourcesReported]
+	}
+	if len(sources) > 0 {
+		sb.WriteString("Slowest sources:\n")
+		for _, source := range sources {
+			fmt.Fprintf(
+				&sb, "  %10s  %s (parsing %s, querying %s, %d packages)\n",
+				formatDuration(source.Total()), source.Source,
+				formatDuration(source.Parse), formatDuration(source.Query), source.Packages,
+			)
+		}
+	}
+
+	return sb.String()
+}
+
+// formatDuration rounds durations to a precision that is useful for comparing
+// phases, without sub-microsecond noise
+func formatDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond).String()
+	default:
+		return d.Round(time.Microsecond).String()
+	}
+}
+
+// parsingPhase is the phase that parsing with the given parser is recorded as
+func parsingPhase(parser string) string {
+	return fmt.Sprintf("parsing (%s)", parser)
+}
+
+// slowParseWarning returns the warning for parsing the file at path with the
+// given parser having taken `d`, or an empty string if that is not slow
+func slowParseWarning(path string, parser string, d time.Duration) string {
+	if d < slowParseThreshold {
+		return ""
+	}
+
+	return fmt.Sprintf("Warning: parsing %s (%s) took %s, which is unusually slow\n", path, parser, formatDuration(d))
+}
+
+// trackParse starts timing the parsing of the file at path with the given
+// parser, returning a function to call once it has been parsed which records
+// the time spent against the parser's phase and the sources of the queries
+// added since, warns if it was unusually slow, and returns the time spent
+func trackParse(r output.Reporter, profile *Profile, query *osv.BatchedQuery, parser string, path string) func() time.Duration {
+	start := time.Now()
+	first := len(query.Queries)
+
+	return func() time.Duration {
+		d := time.Since(start)
+		profile.Add(parsingPhase(parser), d)
+		if len(query.Queries) > first {
+			profile.addSources(query.Queries[first:], d, false)
+		}
+		if warning := slowParseWarning(path, parser, d); warning != "" {
+			r.PrintText(warning)
+		}
+
+		return d
+	}
+}
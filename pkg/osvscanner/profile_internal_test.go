@@ -0,0 +1,101 @@
+package osvscanner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+)
+
+func TestProfile(t *testing.T) {
+	t.Parallel()
+
+	profile := NewProfile()
+	profile.Add("walking", 2*time.Millisecond)
+	profile.Add(parsingPhase("package-lock.json"), 3*time.Millisecond)
+	profile.Add("walking", time.Millisecond)
+	profile.Add("querying", 1500*time.Millisecond)
+
+	want := []PhaseTiming{
+		{Phase: "walking", Duration: 3 * time.Millisecond},
+		{Phase: "parsing (package-lock.json)", Duration: 3 * time.Millisecond},
+		{Phase: "querying", Duration: 1500 * time.Millisecond},
+	}
+
+	if diff := cmp.Diff(want, profile.Phases()); diff != "" {
+		t.Errorf("Phases() mismatch (-want +got):\n%s", diff)
+	}
+
+	wantReport := "Scan profile:\n" +
+		"  walking                            3ms\n" +
+		"  parsing (package-lock.json)        3ms\n" +
+		"  querying                          1.5s\n" +
+		"  total                           1.506s\n"
+
+	if diff := cmp.Diff(wantReport, profile.String()); diff != "" {
+		t.Errorf("String() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestProfile_Nil(t *testing.T) {
+	t.Parallel()
+
+	var profile *Profile
+	profile.Add("walking", time.Second)
+	profile.Track("querying")()
+
+	profile.addSources([]*osv.Query{{}}, time.Second, false)
+
+	if phases := profile.Phases(); phases != nil {
+		t.Errorf("expected no phases, got %v", phases)
+	}
+	if sources := profile.Sources(); sources != nil {
+		t.Errorf("expected no sources, got %v", sources)
+	}
+}
+
+func TestProfile_Sources(t *testing.T) {
+	t.Parallel()
+
+	lockfile := models.SourceInfo{Path: "/app/package-lock.json", Type: "lockfile"}
+	sbom := models.SourceInfo{Path: "/app/bom.json", Type: "sbom"}
+
+	profile := NewProfile()
+	profile.addSources([]*osv.Query{{Source: lockfile}, {Source: lockfile}, {Source: lockfile}}, 3*time.Millisecond, false)
+	profile.addSources([]*osv.Query{{Source: sbom}}, time.Millisecond, false)
+	profile.addSources([]*osv.Query{{Source: lockfile}, {Source: sbom}, {Source: sbom}, {Source: sbom}}, 40*time.Millisecond, true)
+
+	want := []models.SourceTiming{
+		{Source: sbom, Packages: 1, Parse: time.Millisecond, Query: 30 * time.Millisecond},
+		{Source: lockfile, Packages: 3, Parse: 3 * time.Millisecond, Query: 10 * time.Millisecond},
+	}
+
+	if diff := cmp.Diff(want, profile.Sources()); diff != "" {
+		t.Errorf("Sources() mismatch (-want +got):\n%s", diff)
+	}
+
+	wantReport := "Scan profile:\n" +
+		"  total         0s\n" +
+		"Slowest sources:\n" +
+		"        31ms  sbom:/app/bom.json (parsing 1ms, querying 30ms, 1 packages)\n" +
+		"        13ms  lockfile:/app/package-lock.json (parsing 3ms, querying 10ms, 3 packages)\n"
+
+	if diff := cmp.Diff(wantReport, profile.String()); diff != "" {
+		t.Errorf("String() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSlowParseWarning(t *testing.T) {
+	t.Parallel()
+
+	if got := slowParseWarning("/app/package-lock.json", "package-lock.json", time.Second); got != "" {
+		t.Errorf("expected no warning, got %q", got)
+	}
+
+	want := "Warning: parsing /app/package-lock.json (package-lock.json) took 12.5s, which is unusually slow\n"
+	if got := slowParseWarning("/app/package-lock.json", "package-lock.json", 12500*time.Millisecond); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
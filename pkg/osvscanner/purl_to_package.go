@@ -1,12 +1,15 @@
 package osvscanner
 
 import (
+	"strings"
+
 	"github.com/google/osv-scanner/pkg/models"
 	"github.com/package-url/packageurl-go"
 )
 
 var purlEcosystems = map[string]string{
 	"cargo":    "crates.io",
+	"conan":    "ConanCenter",
 	"deb":      "Debian",
 	"hex":      "Hex",
 	"golang":   "Go",
@@ -15,6 +18,7 @@ var purlEcosystems = map[string]string{
 	"npm":      "npm",
 	"composer": "Packagist",
 	"generic":  "OSS-Fuzz",
+	"pub":      "Pub",
 	"pypi":     "PyPI",
 	"gem":      "RubyGems",
 }
@@ -35,3 +39,45 @@ func PURLToPackage(purl string) (models.PackageInfo, error) {
 		Version:   parsedPURL.Version,
 	}, nil
 }
+
+// PackageToPURL returns the package URL of the package, or an empty string if
+// its ecosystem does not have a package URL type
+func PackageToPURL(pkg models.PackageInfo) string {
+	purlType := ""
+	for t, ecosystem := range purlEcosystems {
+		if ecosystem == pkg.Ecosystem && t != "generic" {
+			purlType = t
+		}
+	}
+	if purlType == "" {
+		return ""
+	}
+
+	namespace, name := "", pkg.Name
+	switch purlType {
+	case "maven":
+		if group, artifact, ok := strings.Cut(pkg.Name, ":"); ok {
+			namespace, name = group, artifact
+		}
+	case "deb":
+		namespace = "debian"
+	case "npm", "golang", "composer":
+		if i := strings.LastIndex(pkg.Name, "/"); i >= 0 {
+			namespace, name = pkg.Name[:i], pkg.Name[i+1:]
+		}
+	}
+
+	return packageurl.NewPackageURL(purlType, namespace, name, pkg.Version, nil, "").ToString()
+}
+
+// isKnownPURLEcosystem checks if the given purl is valid and has a type that
+// maps to an ecosystem known to OSV
+func isKnownPURLEcosystem(purl string) bool {
+	parsedPURL, err := packageurl.FromString(purl)
+	if err != nil {
+		return false
+	}
+	_, ok := purlEcosystems[parsedPURL.Type]
+
+	return ok
+}
@@ -0,0 +1,109 @@
+package osvscanner
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/registry"
+)
+
+// registryWorkers is how many packages are checked against their registries
+// at the same time
+const registryWorkers = 8
+
+// registryVerifier checks the packages of lockfiles against their public
+// registries as they are scanned, remembering the outcome for each version so
+// that packages found in more than one lockfile are only checked once
+type registryVerifier struct {
+	lookupTracker[registry.Package, registry.Result, models.RegistryIssue]
+	// isInternal reports if a package is an internal package, which is
+	// checked to be absent from its public registry instead
+	isInternal func(query *osv.Query) bool
+}
+
+func newRegistryVerifier(ctx context.Context, checker *registry.Checker) *registryVerifier {
+	check := func(pkg registry.Package) (registry.Result, error) {
+		return checker.Check(ctx, pkg)
+	}
+
+	return &registryVerifier{
+		lookupTracker: newLookupTracker(check, sortRegistryIssues),
+	}
+}
+
+// inspect checks each package of a lockfile in the batch against its registry
+func (v *registryVerifier) inspect(batch osv.BatchedQuery) {
+	packages := make(chan registryQuery)
+	var wg sync.WaitGroup
+
+	for i := 0; i < registryWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for pkg := range packages {
+				v.inspectQuery(pkg.query, pkg.pkg)
+			}
+		}()
+	}
+
+	for _, query := range batch.Queries {
+		if query.Source.Type != "lockfile" || query.Package.Name == "" || !registry.Supported(query.Package.Ecosystem) {
+			continue
+		}
+
+		pkg := registry.Package{
+			Name:      query.Package.Name,
+			Version:   query.Version,
+			Ecosystem: query.Package.Ecosystem,
+		}
+		if v.isInternal != nil && v.isInternal(query) {
+			// only the name of an internal package matters, so that it is
+			// checked once whatever its version
+			pkg = registry.Package{Name: pkg.Name, Ecosystem: pkg.Ecosystem, Internal: true}
+		} else if query.Metadata != nil {
+			pkg.Integrity = query.Metadata.Integrity
+		}
+
+		packages <- registryQuery{query: query, pkg: pkg}
+	}
+	close(packages)
+	wg.Wait()
+}
+
+// registryQuery is a query for a package of a lockfile, along with the
+// package to check against its registry
+type registryQuery struct {
+	query *osv.Query
+	pkg   registry.Package
+}
+
+func (v *registryVerifier) inspectQuery(query *osv.Query, pkg registry.Package) {
+	result, err := v.get(pkg)
+	if err != nil || result.Status == registry.StatusOK || result.Status == registry.StatusUnsupported {
+		return
+	}
+
+	v.add(models.RegistryIssue{
+		Source:  query.Source,
+		Package: queryPackage(query),
+		Kind:    string(result.Status),
+		Detail:  result.Detail,
+	})
+}
+
+func sortRegistryIssues(issues []models.RegistryIssue) {
+	sort.SliceStable(issues, func(a, b int) bool {
+		ia, ib := issues[a], issues[b]
+		if ia.Source.Path != ib.Source.Path {
+			return ia.Source.Path < ib.Source.Path
+		}
+		if ia.Package.Name != ib.Package.Name {
+			return ia.Package.Name < ib.Package.Name
+		}
+
+		return ia.Package.Version < ib.Package.Version
+	})
+}
@@ -0,0 +1,142 @@
+package osvscanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/registry"
+)
+
+func Test_registryVerifier(t *testing.T) {
+	t.Parallel()
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		switch r.URL.Path {
+		case "/wrappy/1.0.2":
+			_, _ = w.Write([]byte(`{"dist": {"shasum": "b5243d8f3ec1aa35f1364605bc0d1036e30ab69f"}}`))
+		case "/left-pad":
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	verifier := newRegistryVerifier(context.Background(), &registry.Checker{
+		Client:   server.Client(),
+		BaseURLs: map[string]string{"npm": server.URL},
+	})
+
+	lockfileA := models.SourceInfo{Path: "/a/package-lock.json", Type: "lockfile"}
+	lockfileB := models.SourceInfo{Path: "/b/package-lock.json", Type: "lockfile"}
+	query := func(source models.SourceInfo, name string, version string, ecosystem string) *osv.Query {
+		return &osv.Query{Source: source, Version: version, Package: osv.Package{Name: name, Ecosystem: ecosystem}}
+	}
+	tampered := query(lockfileB, "wrappy", "1.0.2", "npm")
+	tampered.Metadata = &models.PackageMetadata{Integrity: "sha1-AAAAAAAAAAAAAAAAAAAAAAAAAAA="}
+
+	verifier.inspect(osv.BatchedQuery{Queries: []*osv.Query{
+		query(lockfileA, "wrappy", "1.0.2", "npm"),
+		query(lockfileB, "wrappy", "1.0.2", "npm"),
+		tampered,
+		query(lockfileA, "left-pad", "9.9.9", "npm"),
+		query(lockfileB, "wrappyy", "1.0.2", "npm"),
+		query(lockfileA, "some/package", "1.0.0", "Packagist"),
+		query(models.SourceInfo{Path: "/bom.cdx.json", Type: "sbom"}, "ghost", "1.0.0", "npm"),
+	}})
+
+	want := []models.RegistryIssue{
+		{
+			Source:  lockfileA,
+			Package: models.PackageInfo{Name: "left-pad", Version: "9.9.9", Ecosystem: "npm"},
+			Kind:    "missing-version",
+		},
+		{
+			Source:  lockfileB,
+			Package: models.PackageInfo{Name: "wrappy", Version: "1.0.2", Ecosystem: "npm"},
+			Kind:    "integrity-mismatch",
+		},
+		{
+			Source:  lockfileB,
+			Package: models.PackageInfo{Name: "wrappyy", Version: "1.0.2", Ecosystem: "npm"},
+			Kind:    "missing-package",
+		},
+	}
+
+	got := verifier.findings()
+	for i := range got {
+		if got[i].Detail == "" {
+			t.Errorf("expected a detail for %s", got[i].Package.Name)
+		}
+		got[i].Detail = ""
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("registryVerifier issues mismatch (-want +got):\n%s", diff)
+	}
+
+	// wrappy without an integrity is only checked once, and each of the
+	// missing packages takes two requests
+	if got := atomic.LoadInt32(&requests); got != 6 {
+		t.Errorf("expected 6 requests to the registry, got %d", got)
+	}
+}
+
+func Test_registryVerifier_InternalPackages(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/acme-billing", "/acme-billing/1.0.0":
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	verifier := newRegistryVerifier(context.Background(), &registry.Checker{
+		Client:   server.Client(),
+		BaseURLs: map[string]string{"npm": server.URL},
+	})
+	verifier.isInternal = func(query *osv.Query) bool {
+		return strings.HasPrefix(query.Package.Name, "acme-")
+	}
+
+	lockfile := models.SourceInfo{Path: "/a/package-lock.json", Type: "lockfile"}
+	query := func(name string, version string) *osv.Query {
+		return &osv.Query{Source: lockfile, Version: version, Package: osv.Package{Name: name, Ecosystem: "npm"}}
+	}
+
+	verifier.inspect(osv.BatchedQuery{Queries: []*osv.Query{
+		query("acme-billing", "1.0.0"),
+		query("acme-utils", "2.0.0"),
+	}})
+
+	want := []models.RegistryIssue{{
+		Source:  lockfile,
+		Package: models.PackageInfo{Name: "acme-billing", Version: "1.0.0", Ecosystem: "npm"},
+		Kind:    "dependency-confusion",
+	}}
+
+	got := verifier.findings()
+	for i := range got {
+		got[i].Detail = ""
+	}
+
+	// acme-utils is not published, but as an internal package that is expected
+	// rather than being reported as missing
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("registryVerifier issues mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,162 @@
+package osvscanner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/config"
+	"github.com/google/osv-scanner/pkg/gitauth"
+	"github.com/google/osv-scanner/pkg/lockfile"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// sparseCheckoutNames are the names of files other than lockfiles that are
+// checked out, as they affect how the lockfiles are scanned or reported
+var sparseCheckoutNames = map[string]bool{
+	".gitignore":          true,
+	"CODEOWNERS":          true,
+	"package.json":        true,
+	"pnpm-workspace.yaml": true,
+	"go.work":             true,
+	"Cargo.toml":          true,
+}
+
+// isScanRelevant reports if the file at the given slash-separated path might
+// be read when scanning, being a lockfile, SBOM, or config file
+func isScanRelevant(filePath string) bool {
+	name := path.Base(filePath)
+	lowerName := strings.ToLower(name)
+
+	if parser, _ := lockfile.FindParser(name, ""); parser != nil {
+		return true
+	}
+
+	switch {
+	case sparseCheckoutNames[name], config.IsConfigFileName(name):
+		return true
+	case strings.Contains(lowerName, ".spdx"):
+		return true
+	case strings.Contains(lowerName, "bom") || strings.Contains(lowerName, "cyclonedx"):
+		// CycloneDX SBOMs don't have a fixed name, but are usually named after
+		// the format or an "sbom"
+		return strings.HasSuffix(lowerName, ".json") || strings.HasSuffix(lowerName, ".xml")
+	}
+
+	return false
+}
+
+// isLocalPath reports if the slash-separated path stays within its root
+func isLocalPath(filePath string) bool {
+	if filePath == "" || path.IsAbs(filePath) {
+		return false
+	}
+
+	for _, part := range strings.Split(filePath, "/") {
+		if part == ".." {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkoutFile writes the given file from the repository into dir
+func checkoutFile(dir string, file *object.File) error {
+	target := filepath.Join(dir, filepath.FromSlash(file.Name))
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	reader, err := file.Reader()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, reader)
+
+	return err
+}
+
+// sparseCheckout writes out only the files of HEAD that are relevant to
+// scanning, rather than the whole worktree
+func sparseCheckout(dir string, repo *git.Repository) error {
+	head, err := repo.Head()
+	if err != nil {
+		return err
+	}
+
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return err
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return err
+	}
+
+	return tree.Files().ForEach(func(file *object.File) error {
+		if !file.Mode.IsFile() || !isLocalPath(file.Name) || !isScanRelevant(file.Name) {
+			return nil
+		}
+
+		return checkoutFile(dir, file)
+	})
+}
+
+// CloneRepository makes a shallow clone of the git repository at the given
+// URL into a new temporary directory, authenticating with the given
+// credentials; the caller should remove the directory once they are done with it.
+//
+// Only the files that are relevant to scanning are checked out, which saves
+// a lot of time and disk space when scanning many repositories.
+func CloneRepository(url string, credentials gitauth.Credentials) (string, error) {
+	return CloneRepositoryWithContext(context.Background(), url, credentials)
+}
+
+// CloneRepositoryWithContext is like CloneRepository, but abandons the clone
+// once ctx is done
+func CloneRepositoryWithContext(ctx context.Context, url string, credentials gitauth.Credentials) (string, error) {
+	auth, err := credentials.AuthFor(url)
+	if err != nil {
+		return "", fmt.Errorf("could not clone %s: %w", url, err)
+	}
+
+	dir, err := os.MkdirTemp("", "osv-scanner-")
+	if err != nil {
+		return "", err
+	}
+
+	repo, err := git.PlainCloneContext(ctx, dir, false, &git.CloneOptions{
+		URL:          url,
+		Depth:        1,
+		SingleBranch: true,
+		NoCheckout:   true,
+		Auth:         auth,
+	})
+	if err == nil {
+		err = sparseCheckout(dir, repo)
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+
+		return "", fmt.Errorf("could not clone %s: %w", url, err)
+	}
+
+	return dir, nil
+}
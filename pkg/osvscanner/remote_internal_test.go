@@ -0,0 +1,128 @@
+package osvscanner
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/gitauth"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func Test_isScanRelevant(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]bool{
+		"package-lock.json":             true,
+		"services/api/go.mod":           true,
+		"services/api/osv-scanner.toml": true,
+		"sbom/vendor.spdx.json":         true,
+		"bom.xml":                       true,
+		"dist/app.cyclonedx.json":       true,
+		"packages/web/package.json":     true,
+		".github/CODEOWNERS":            true,
+		"README.md":                     false,
+		"src/main.go":                   false,
+		"docs/bomb.png":                 false,
+	}
+
+	for path, want := range tests {
+		if got := isScanRelevant(path); got != want {
+			t.Errorf("isScanRelevant(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+// makeRepository creates a git repository with the given files committed
+func makeRepository(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("could not create repository: %v", err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("could not get worktree: %v", err)
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("could not create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("could not write %s: %v", name, err)
+		}
+		if _, err := worktree.Add(name); err != nil {
+			t.Fatalf("could not add %s: %v", name, err)
+		}
+	}
+
+	_, err = worktree.Commit("initial commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatalf("could not commit: %v", err)
+	}
+
+	return dir
+}
+
+func TestCloneRepository_SparseCheckout(t *testing.T) {
+	t.Parallel()
+
+	source := makeRepository(t, map[string]string{
+		"README.md":                     "# project",
+		"src/main.go":                   "package main",
+		"go.mod":                        "module example.com/project",
+		"services/api/requirements.txt": "flask==1.0.0",
+		"services/api/osv-scanner.toml": "",
+		"services/api/app.py":           "print('hi')",
+	})
+
+	dir, err := CloneRepository(source, gitauth.Credentials{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var files []string
+	err = filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && entry.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if !entry.IsDir() {
+			rel, _ := filepath.Rel(dir, path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("could not walk clone: %v", err)
+	}
+
+	sort.Strings(files)
+
+	want := []string{"go.mod", "services/api/osv-scanner.toml", "services/api/requirements.txt"}
+	if diff := cmp.Diff(want, files); diff != "" {
+		t.Errorf("checked out files mismatch (-want +got):\n%s", diff)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "services/api/requirements.txt"))
+	if err != nil || string(content) != "flask==1.0.0" {
+		t.Errorf("unexpected content %q (%v)", content, err)
+	}
+}
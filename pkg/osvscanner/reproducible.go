@@ -0,0 +1,204 @@
+package osvscanner
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// scanTime is the time that ignore entries are checked against, which when
+// reproducible is frozen to the SOURCE_DATE_EPOCH environment variable if set,
+// following https://reproducible-builds.org/specs/source-date-epoch/
+func scanTime(reproducible bool) time.Time {
+	if reproducible {
+		if epoch, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64); err == nil {
+			return time.Unix(epoch, 0).UTC()
+		}
+	}
+
+	return time.Now()
+}
+
+// reproduciblePath makes an absolute path relative to the scan root (being the
+// working directory), using forward slashes regardless of the platform
+func reproduciblePath(path string) string {
+	if !filepath.IsAbs(path) {
+		return filepath.ToSlash(path)
+	}
+
+	return filepath.ToSlash(relativeToWorkingDir(path))
+}
+
+// reproducibleMessage replaces the absolute form of the given path within a
+// message with its reproducible form
+func reproducibleMessage(msg string, path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return msg
+	}
+
+	return strings.ReplaceAll(msg, abs, reproduciblePath(path))
+}
+
+// makeReproducible rewrites the results so that scanning the same inputs from
+// the same scan root always produces identical results, regardless of where
+// the scan root is or the order that sources were found and responded to
+func makeReproducible(results *models.VulnerabilityResults) {
+	for i := range results.Results {
+		source := &results.Results[i]
+		source.Source.Path = reproduciblePath(source.Source.Path)
+
+		for j := range source.Packages {
+			sortPackageVulns(&source.Packages[j])
+			if metadata := source.Packages[j].Metadata; metadata != nil && metadata.DeclaringFile != "" {
+				metadata.DeclaringFile = reproduciblePath(metadata.DeclaringFile)
+			}
+		}
+
+		sort.SliceStable(source.Packages, func(a, b int) bool {
+			return comparePackages(source.Packages[a].Package, source.Packages[b].Package) < 0
+		})
+	}
+
+	sort.SliceStable(results.Results, func(a, b int) bool {
+		sa, sb := results.Results[a], results.Results[b]
+		if sa.Target != sb.Target {
+			return sa.Target < sb.Target
+		}
+		if sa.Source.Path != sb.Source.Path {
+			return sa.Source.Path < sb.Source.Path
+		}
+
+		return sa.Source.Type < sb.Source.Type
+	})
+
+	for i := range results.ParseFailures {
+		failure := &results.ParseFailures[i]
+		// parsers tend to include the path in their errors too
+		failure.Error = reproducibleMessage(failure.Error, failure.Path)
+		failure.Path = reproduciblePath(failure.Path)
+	}
+	sort.SliceStable(results.ParseFailures, func(a, b int) bool {
+		return results.ParseFailures[a].Path < results.ParseFailures[b].Path
+	})
+
+	for i := range results.Skipped {
+		skipped := &results.Skipped[i]
+		skipped.Reason = reproducibleMessage(skipped.Reason, skipped.Source.Path)
+		skipped.Source.Path = reproduciblePath(skipped.Source.Path)
+	}
+	sort.SliceStable(results.Skipped, func(a, b int) bool {
+		sa, sb := results.Skipped[a], results.Skipped[b]
+		if sa.Source.Path != sb.Source.Path {
+			return sa.Source.Path < sb.Source.Path
+		}
+
+		return sa.Reason < sb.Reason
+	})
+
+	for i := range results.RegistryIssues {
+		results.RegistryIssues[i].Source.Path = reproduciblePath(results.RegistryIssues[i].Source.Path)
+	}
+	sortRegistryIssues(results.RegistryIssues)
+
+	for i := range results.Outdated {
+		results.Outdated[i].Source.Path = reproduciblePath(results.Outdated[i].Source.Path)
+	}
+	sortOutdatedPackages(results.Outdated)
+
+	for i := range results.Deprecated {
+		results.Deprecated[i].Source.Path = reproduciblePath(results.Deprecated[i].Source.Path)
+	}
+	sortDeprecatedPackages(results.Deprecated)
+
+	for i := range results.Scorecards {
+		results.Scorecards[i].Source.Path = reproduciblePath(results.Scorecards[i].Source.Path)
+	}
+	sortPackageScorecards(results.Scorecards)
+
+	for i := range results.Upgrades {
+		results.Upgrades[i].Source.Path = reproduciblePath(results.Upgrades[i].Source.Path)
+	}
+	sortUpgradeImpacts(results.Upgrades)
+
+	for i := range results.Reachability {
+		results.Reachability[i].Source.Path = reproduciblePath(results.Reachability[i].Source.Path)
+	}
+	sortReachability(results.Reachability)
+
+	for i := range results.LicenseViolations {
+		results.LicenseViolations[i].Source.Path = reproduciblePath(results.LicenseViolations[i].Source.Path)
+	}
+	sortLicenseViolations(results.LicenseViolations)
+
+	for i := range results.History {
+		results.History[i].Source.Path = reproduciblePath(results.History[i].Source.Path)
+	}
+	sortHistory(results.History)
+
+	for i := range results.Inventory {
+		results.Inventory[i].Source.Path = reproduciblePath(results.Inventory[i].Source.Path)
+	}
+	sortInventory(results.Inventory)
+
+	for i := range results.SuspiciousPackages {
+		results.SuspiciousPackages[i].Source.Path = reproduciblePath(results.SuspiciousPackages[i].Source.Path)
+	}
+	sortSuspiciousPackages(results.SuspiciousPackages)
+
+	// the plan is kept in the order that inputs would be scanned in
+	for i := range results.Plan {
+		results.Plan[i].Skipped = reproducibleMessage(results.Plan[i].Skipped, results.Plan[i].Source.Path)
+		results.Plan[i].Source.Path = reproduciblePath(results.Plan[i].Source.Path)
+	}
+
+	for i := range results.UnusedIgnores {
+		results.UnusedIgnores[i].ConfigPath = reproduciblePath(results.UnusedIgnores[i].ConfigPath)
+	}
+}
+
+// sortPackageVulns sorts the vulnerabilities, groups and workspace members of
+// a package, along with the IDs and aliases within them
+func sortPackageVulns(pkg *models.PackageVulns) {
+	for i := range pkg.Vulnerabilities {
+		sort.Strings(pkg.Vulnerabilities[i].Aliases)
+	}
+	sort.SliceStable(pkg.Vulnerabilities, func(a, b int) bool {
+		return pkg.Vulnerabilities[a].ID < pkg.Vulnerabilities[b].ID
+	})
+
+	for i := range pkg.Groups {
+		sort.Strings(pkg.Groups[i].IDs)
+	}
+	sort.SliceStable(pkg.Groups, func(a, b int) bool {
+		return strings.Join(pkg.Groups[a].IDs, ",") < strings.Join(pkg.Groups[b].IDs, ",")
+	})
+
+	sort.SliceStable(pkg.WorkspaceMembers, func(a, b int) bool {
+		ma, mb := pkg.WorkspaceMembers[a], pkg.WorkspaceMembers[b]
+		if ma.Workspace != mb.Workspace {
+			return ma.Workspace < mb.Workspace
+		}
+
+		return ma.Path < mb.Path
+	})
+}
+
+func comparePackages(a models.PackageInfo, b models.PackageInfo) int {
+	for _, pair := range [][2]string{
+		{a.Ecosystem, b.Ecosystem},
+		{a.Name, b.Name},
+		{a.Version, b.Version},
+	} {
+		if c := strings.Compare(pair[0], pair[1]); c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,95 @@
+package osvscanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestMakeReproducible(t *testing.T) {
+	t.Parallel()
+
+	workingDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("could not get working directory: %v", err)
+	}
+
+	abs := func(path string) string {
+		return filepath.Join(workingDir, filepath.FromSlash(path))
+	}
+	pkg := func(name string, ids ...string) models.PackageVulns {
+		pkg := models.PackageVulns{Package: models.PackageInfo{Name: name, Version: "1.0.0", Ecosystem: "npm"}}
+		for _, id := range ids {
+			pkg.Vulnerabilities = append(pkg.Vulnerabilities, models.Vulnerability{ID: id})
+		}
+		pkg.Groups = []models.GroupInfo{{IDs: append([]string{}, ids...)}}
+
+		return pkg
+	}
+
+	results := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source:   models.SourceInfo{Path: abs("web/package-lock.json"), Type: "lockfile"},
+				Packages: []models.PackageVulns{pkg("lodash", "GHSA-b", "GHSA-a"), pkg("axios", "GHSA-c")},
+			},
+			{
+				Source:   models.SourceInfo{Path: abs("api/package-lock.json"), Type: "lockfile"},
+				Packages: []models.PackageVulns{pkg("minimist", "GHSA-d")},
+			},
+		},
+		ParseFailures: []models.ParseFailure{
+			{Path: abs("b/yarn.lock"), Parser: "yarn.lock", Error: "could not parse " + abs("b/yarn.lock")},
+			{Path: abs("a/yarn.lock"), Parser: "yarn.lock", Error: "bad"},
+		},
+		Skipped: []models.SkippedSource{
+			{Source: models.SourceInfo{Path: abs("sbom.spdx.json"), Type: "sbom"}, Reason: "unknown ecosystem for pkg:b"},
+			{Source: models.SourceInfo{Path: abs("sbom.spdx.json"), Type: "sbom"}, Reason: "unknown ecosystem for pkg:a"},
+		},
+	}
+
+	makeReproducible(&results)
+
+	want := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source:   models.SourceInfo{Path: "api/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{pkg("minimist", "GHSA-d")},
+			},
+			{
+				Source:   models.SourceInfo{Path: "web/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{pkg("axios", "GHSA-c"), pkg("lodash", "GHSA-a", "GHSA-b")},
+			},
+		},
+		ParseFailures: []models.ParseFailure{
+			{Path: "a/yarn.lock", Parser: "yarn.lock", Error: "bad"},
+			{Path: "b/yarn.lock", Parser: "yarn.lock", Error: "could not parse b/yarn.lock"},
+		},
+		Skipped: []models.SkippedSource{
+			{Source: models.SourceInfo{Path: "sbom.spdx.json", Type: "sbom"}, Reason: "unknown ecosystem for pkg:a"},
+			{Source: models.SourceInfo{Path: "sbom.spdx.json", Type: "sbom"}, Reason: "unknown ecosystem for pkg:b"},
+		},
+	}
+
+	if diff := cmp.Diff(want, results); diff != "" {
+		t.Errorf("makeReproducible() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+//nolint:paralleltest // sets an environment variable
+func TestScanTime(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1685577600")
+
+	want := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	if got := scanTime(true); !got.Equal(want) {
+		t.Errorf("expected reproducible scan time to be %v, got %v", want, got)
+	}
+
+	if got := scanTime(false); got.Equal(want) {
+		t.Errorf("expected scan time to be the current time, got %v", got)
+	}
+}
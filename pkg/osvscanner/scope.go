@@ -0,0 +1,112 @@
+package osvscanner
+
+import (
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+// packageScope narrows the packages of lockfiles that are scanned down to the
+// ones that are deployed
+type packageScope struct {
+	// platform is the platform that is deployed to, with builds of packages
+	// for other platforms being skipped
+	platform string
+	// excludedGroups are the groups of manifests whose packages are skipped,
+	// unless they are also depended on in a group that is not excluded
+	excludedGroups []string
+	// phpVersion and phpExtensions describe the PHP installation that is
+	// deployed to, which the platform requirements of packages are checked
+	// against without affecting which packages are scanned
+	phpVersion    string
+	phpExtensions []string
+}
+
+func scopeFor(actions ScannerActions) packageScope {
+	return packageScope{
+		platform:       actions.Platform,
+		excludedGroups: actions.ExcludedGroups,
+		phpVersion:     actions.PHPVersion,
+		phpExtensions:  actions.PHPExtensions,
+	}
+}
+
+// matchesPlatform reports if a build for the given platform runs on the
+// deployed platform, allowing either to be more specific than the other such
+// as with "x86_64-linux" and "x86_64-linux-gnu"
+func matchesPlatform(built string, deployed string) bool {
+	return built == deployed ||
+		strings.HasPrefix(built, deployed+"-") ||
+		strings.HasPrefix(deployed, built+"-")
+}
+
+func platformOf(pkg lockfile.PackageDetails) string {
+	if pkg.Metadata == nil {
+		return ""
+	}
+
+	return pkg.Metadata.Platform
+}
+
+// excluded reports if every group that the package is depended on in is
+// excluded, which is never the case for packages without known groups
+func (s packageScope) excluded(pkg lockfile.PackageDetails) bool {
+	if len(s.excludedGroups) == 0 || pkg.Metadata == nil || len(pkg.Metadata.Groups) == 0 {
+		return false
+	}
+
+	for _, group := range pkg.Metadata.Groups {
+		isExcluded := false
+		for _, excluded := range s.excludedGroups {
+			if group == excluded {
+				isExcluded = true
+				break
+			}
+		}
+
+		if !isExcluded {
+			return false
+		}
+	}
+
+	return true
+}
+
+// filter returns the packages that are in scope. When a platform is given,
+// packages that have a build for it are only scanned as that build, as that
+// is what package managers install in place of the platform independent one.
+func (s packageScope) filter(packages []lockfile.PackageDetails) []lockfile.PackageDetails {
+	if s.platform == "" && len(s.excludedGroups) == 0 {
+		return packages
+	}
+
+	hasPlatformBuild := map[string]bool{}
+	if s.platform != "" {
+		for _, pkg := range packages {
+			if platform := platformOf(pkg); platform != "" && matchesPlatform(platform, s.platform) {
+				hasPlatformBuild[pkg.Name+"@"+pkg.Version] = true
+			}
+		}
+	}
+
+	inScope := make([]lockfile.PackageDetails, 0, len(packages))
+	for _, pkg := range packages {
+		if s.excluded(pkg) {
+			continue
+		}
+
+		if s.platform != "" {
+			platform := platformOf(pkg)
+			if platform != "" && !matchesPlatform(platform, s.platform) {
+				continue
+			}
+			if platform == "" && hasPlatformBuild[pkg.Name+"@"+pkg.Version] {
+				continue
+			}
+		}
+
+		inScope = append(inScope, pkg)
+	}
+
+	return inScope
+}
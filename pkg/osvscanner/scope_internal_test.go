@@ -0,0 +1,112 @@
+package osvscanner
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func Test_matchesPlatform(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		built    string
+		deployed string
+		want     bool
+	}{
+		{built: "x86_64-linux", deployed: "x86_64-linux", want: true},
+		{built: "x86_64-linux", deployed: "x86_64-linux-gnu", want: true},
+		{built: "x86_64-linux-musl", deployed: "x86_64-linux", want: true},
+		{built: "arm64-darwin", deployed: "x86_64-linux", want: false},
+		{built: "x86_64-linux", deployed: "x86_64", want: true},
+		{built: "x86_64-linuxx", deployed: "x86_64-linux", want: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.built+" on "+tt.deployed, func(t *testing.T) {
+			t.Parallel()
+
+			if got := matchesPlatform(tt.built, tt.deployed); got != tt.want {
+				t.Errorf("matchesPlatform(%q, %q) = %v, want %v", tt.built, tt.deployed, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_packageScope_filter(t *testing.T) {
+	t.Parallel()
+
+	gem := func(name string, platform string, groups ...string) lockfile.PackageDetails {
+		return lockfile.PackageDetails{
+			Name:      name,
+			Version:   "1.0.0",
+			Ecosystem: lockfile.BundlerEcosystem,
+			Metadata:  &lockfile.PackageMetadata{Platform: platform, Groups: groups},
+		}
+	}
+
+	packages := []lockfile.PackageDetails{
+		gem("nokogiri", "", "default"),
+		gem("nokogiri", "arm64-darwin", "default"),
+		gem("nokogiri", "x86_64-linux", "default"),
+		gem("rails", "", "default"),
+		gem("rspec", "", "test"),
+		gem("pry", "", "development", "test"),
+		gem("rack-test", "", "default", "test"),
+		{Name: "unknown", Version: "1.0.0", Ecosystem: lockfile.BundlerEcosystem},
+	}
+
+	tests := []struct {
+		name  string
+		scope packageScope
+		want  []lockfile.PackageDetails
+	}{
+		{
+			name:  "no scope",
+			scope: packageScope{},
+			want:  packages,
+		},
+		{
+			name:  "platform with a build",
+			scope: packageScope{platform: "x86_64-linux-gnu"},
+			want: []lockfile.PackageDetails{
+				packages[2], packages[3], packages[4], packages[5], packages[6], packages[7],
+			},
+		},
+		{
+			name:  "platform without a build",
+			scope: packageScope{platform: "x86_64-mingw32"},
+			want: []lockfile.PackageDetails{
+				packages[0], packages[3], packages[4], packages[5], packages[6], packages[7],
+			},
+		},
+		{
+			name:  "excluded groups",
+			scope: packageScope{excludedGroups: []string{"development", "test"}},
+			want: []lockfile.PackageDetails{
+				packages[0], packages[1], packages[2], packages[3], packages[6], packages[7],
+			},
+		},
+		{
+			name:  "platform and excluded groups",
+			scope: packageScope{platform: "arm64-darwin", excludedGroups: []string{"test"}},
+			want: []lockfile.PackageDetails{
+				packages[1], packages[3], packages[5], packages[6], packages[7],
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tt.scope.filter(packages)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("filter() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package osvscanner
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+)
+
+// scorecardFetcher fetches the OpenSSF Scorecards of the direct dependencies
+// of lockfiles as they are scanned, fetching each version of a package only
+// once
+type scorecardFetcher struct {
+	lookupTracker[models.PackageInfo, *models.Scorecard, models.PackageScorecard]
+}
+
+func newScorecardFetcher(ctx context.Context, fetch func(ctx context.Context, ecosystem string, name string, version string) (*models.Scorecard, error)) *scorecardFetcher {
+	scorecard := func(pkg models.PackageInfo) (*models.Scorecard, error) {
+		return fetch(ctx, pkg.Ecosystem, pkg.Name, pkg.Version)
+	}
+
+	return &scorecardFetcher{
+		lookupTracker: newLookupTracker(scorecard, sortPackageScorecards),
+	}
+}
+
+// inspect fetches the scorecard of each direct dependency in the batch, which
+// are only known for lockfiles that record how packages are depended on
+func (f *scorecardFetcher) inspect(batch osv.BatchedQuery) {
+	queries := make(chan *osv.Query)
+	var wg sync.WaitGroup
+
+	for i := 0; i < registryWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for query := range queries {
+				f.inspectQuery(query)
+			}
+		}()
+	}
+
+	for _, query := range batch.Queries {
+		if query.Package.Name == "" || query.Metadata == nil || query.Metadata.Relation != "direct" {
+			continue
+		}
+		if !f.firstSeen(query.Source, queryPackage(query)) {
+			continue
+		}
+
+		queries <- query
+	}
+	close(queries)
+	wg.Wait()
+}
+
+func (f *scorecardFetcher) inspectQuery(query *osv.Query) {
+	pkg := queryPackage(query)
+
+	scorecard, err := f.get(pkg)
+	if err != nil || scorecard == nil {
+		return
+	}
+
+	f.add(models.PackageScorecard{
+		Source:    query.Source,
+		Package:   pkg,
+		Scorecard: *scorecard,
+	})
+}
+
+func sortPackageScorecards(scorecards []models.PackageScorecard) {
+	sort.SliceStable(scorecards, func(a, b int) bool {
+		sa, sb := scorecards[a], scorecards[b]
+		if sa.Source.Path != sb.Source.Path {
+			return sa.Source.Path < sb.Source.Path
+		}
+		if sa.Package.Name != sb.Package.Name {
+			return sa.Package.Name < sb.Package.Name
+		}
+
+		return sa.Package.Version < sb.Package.Version
+	})
+}
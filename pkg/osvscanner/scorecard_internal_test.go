@@ -0,0 +1,61 @@
+package osvscanner
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+)
+
+func Test_scorecardFetcher(t *testing.T) {
+	t.Parallel()
+
+	var fetches int32
+	fetcher := newScorecardFetcher(context.Background(), func(_ context.Context, ecosystem string, name string, version string) (*models.Scorecard, error) {
+		atomic.AddInt32(&fetches, 1)
+
+		if name == "react" {
+			return &models.Scorecard{Project: "github.com/facebook/react", OverallScore: 7.4}, nil
+		}
+
+		return nil, nil
+	})
+
+	lockfileA := models.SourceInfo{Path: "/a/package-lock.json", Type: "lockfile"}
+	lockfileB := models.SourceInfo{Path: "/b/package-lock.json", Type: "lockfile"}
+	query := func(source models.SourceInfo, name string, relation string) *osv.Query {
+		q := &osv.Query{Source: source, Version: "1.0.0", Package: osv.Package{Name: name, Ecosystem: "npm"}}
+		if relation != "" {
+			q.Metadata = &models.PackageMetadata{Relation: relation}
+		}
+
+		return q
+	}
+
+	fetcher.inspect(osv.BatchedQuery{Queries: []*osv.Query{
+		query(lockfileB, "react", "direct"),
+		query(lockfileA, "react", "direct"),
+		query(lockfileA, "left-pad", "direct"),
+		query(lockfileA, "loose-envify", "transitive"),
+		query(lockfileA, "scheduler", ""),
+	}})
+
+	react := models.PackageInfo{Name: "react", Version: "1.0.0", Ecosystem: "npm"}
+	scorecard := models.Scorecard{Project: "github.com/facebook/react", OverallScore: 7.4}
+	want := []models.PackageScorecard{
+		{Source: lockfileA, Package: react, Scorecard: scorecard},
+		{Source: lockfileB, Package: react, Scorecard: scorecard},
+	}
+
+	if diff := cmp.Diff(want, fetcher.findings()); diff != "" {
+		t.Errorf("findings() mismatch (-want +got):\n%s", diff)
+	}
+
+	// only direct dependencies are fetched, and each version only once
+	if got := atomic.LoadInt32(&fetches); got != 2 {
+		t.Errorf("expected 2 scorecards to be fetched, got %d", got)
+	}
+}
@@ -0,0 +1,88 @@
+package osvscanner
+
+import (
+	"sort"
+
+	"github.com/google/osv-scanner/pkg/config"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/output"
+
+	"golang.org/x/exp/slices"
+)
+
+// scoreResults sets the effective score and severity of each group of
+// vulnerabilities, applying the severity overrides and scoring of the config
+// for their source
+func scoreResults(r output.Reporter, results *models.VulnerabilityResults, configManager *config.ConfigManager) {
+	for i := range results.Results {
+		source := &results.Results[i]
+		configToUse := configManager.Get(r, source.Source.Path)
+
+		for j := range source.Packages {
+			pkg := &source.Packages[j]
+
+			for k := range pkg.Groups {
+				group := &pkg.Groups[k]
+				base := models.HighestScore(groupVulnerabilities(*pkg, *group))
+				group.Score, group.Severity = configToUse.Score(group.IDs, base, pkg.Metadata, len(group.Exploits) > 0)
+			}
+		}
+	}
+}
+
+// groupVulnerabilities returns the vulnerabilities of the package that are in
+// the given group
+func groupVulnerabilities(pkg models.PackageVulns, group models.GroupInfo) []models.Vulnerability {
+	var vulns []models.Vulnerability
+	for _, vuln := range pkg.Vulnerabilities {
+		if slices.Contains(group.IDs, vuln.ID) {
+			vulns = append(vulns, vuln)
+		}
+	}
+
+	return vulns
+}
+
+// highestGroupScore returns the highest score of the groups of the package
+func highestGroupScore(pkg models.PackageVulns) float64 {
+	highest := 0.0
+	for _, group := range pkg.Groups {
+		if group.Score > highest {
+			highest = group.Score
+		}
+	}
+
+	return highest
+}
+
+// sortByScore orders the packages of each source whose config has custom
+// scoring by their highest scores, and their groups by their scores, most
+// severe first. Ties keep their existing order.
+func sortByScore(r output.Reporter, results *models.VulnerabilityResults, configManager *config.ConfigManager) {
+	for i := range results.Results {
+		source := &results.Results[i]
+		configToUse := configManager.Get(r, source.Source.Path)
+
+		if !configToUse.HasCustomScoring() {
+			continue
+		}
+
+		for j := range source.Packages {
+			groups := source.Packages[j].Groups
+			sort.SliceStable(groups, func(a, b int) bool {
+				return groups[a].Score > groups[b].Score
+			})
+		}
+
+		sort.SliceStable(source.Packages, func(a, b int) bool {
+			return highestGroupScore(source.Packages[a]) > highestGroupScore(source.Packages[b])
+		})
+	}
+}
+
+// meetsScoreThreshold reports if any of the groups of vulnerabilities have a
+// score of at least the threshold, with groups whose scores are not known
+// always meeting it. Every group meets a threshold of 0.
+func meetsScoreThreshold(results models.VulnerabilityResults, threshold float64) bool {
+	return failPolicy{threshold: threshold}.fails(results)
+}
@@ -0,0 +1,136 @@
+package osvscanner
+
+import (
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/config"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+func scoredPackage(name string, scope string, vulns ...models.Vulnerability) models.PackageVulns {
+	pkg := models.PackageVulns{
+		Package:         models.PackageInfo{Name: name, Version: "1.0.0", Ecosystem: "npm"},
+		Vulnerabilities: vulns,
+		Metadata:        &models.PackageMetadata{Scope: scope},
+	}
+	for _, vuln := range vulns {
+		pkg.Groups = append(pkg.Groups, models.GroupInfo{IDs: []string{vuln.ID}})
+	}
+
+	return pkg
+}
+
+func vulnWithVector(id string, vector string) models.Vulnerability {
+	return models.Vulnerability{
+		ID:             id,
+		SeverityScores: []models.SeverityScore{{Type: "CVSS_V3", Score: vector}},
+	}
+}
+
+func TestScoreResults(t *testing.T) {
+	t.Parallel()
+
+	configManager := &config.ConfigManager{OverrideConfig: &config.Config{
+		SeverityOverrides: []config.SeverityOverride{{ID: "GHSA-overridden", Severity: "LOW"}},
+		Scoring:           config.Scoring{Multipliers: map[string]float64{"dev": 0.5}},
+	}}
+	r := output.NewReporter(io.Discard, io.Discard, "")
+
+	critical := "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"
+	results := models.VulnerabilityResults{Results: []models.PackageSource{{
+		Source: models.SourceInfo{Path: "package-lock.json", Type: "lockfile"},
+		Packages: []models.PackageVulns{
+			scoredPackage("jest", "dev", vulnWithVector("GHSA-dev", critical)),
+			scoredPackage("lodash", "prod", vulnWithVector("GHSA-overridden", critical), models.Vulnerability{ID: "GHSA-unknown"}),
+			scoredPackage("axios", "prod", vulnWithVector("GHSA-prod", critical)),
+		},
+	}}}
+
+	scoreResults(r, &results, configManager)
+	sortByScore(r, &results, configManager)
+
+	type scored struct {
+		ID       string
+		Score    float64
+		Severity string
+	}
+	var got []scored
+	for _, pkg := range results.Results[0].Packages {
+		for _, group := range pkg.Groups {
+			got = append(got, scored{ID: group.IDs[0], Score: group.Score, Severity: group.Severity})
+		}
+	}
+
+	want := []scored{
+		{ID: "GHSA-prod", Score: 9.8, Severity: "CRITICAL"},
+		{ID: "GHSA-dev", Score: 4.9, Severity: "MEDIUM"},
+		{ID: "GHSA-overridden", Score: 2, Severity: "LOW"},
+		{ID: "GHSA-unknown", Score: 0, Severity: ""},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("scoreResults() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSortByScore_WithoutCustomScoring(t *testing.T) {
+	t.Parallel()
+
+	configManager := &config.ConfigManager{OverrideConfig: &config.Config{}}
+	r := output.NewReporter(io.Discard, io.Discard, "")
+
+	results := models.VulnerabilityResults{Results: []models.PackageSource{{
+		Source: models.SourceInfo{Path: "package-lock.json", Type: "lockfile"},
+		Packages: []models.PackageVulns{
+			scoredPackage("lodash", "", vulnWithVector("GHSA-low", "CVSS:3.1/AV:L/AC:H/PR:H/UI:R/S:U/C:L/I:N/A:N")),
+			scoredPackage("axios", "", vulnWithVector("GHSA-critical", "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H")),
+		},
+	}}}
+
+	scoreResults(r, &results, configManager)
+	sortByScore(r, &results, configManager)
+
+	if name := results.Results[0].Packages[0].Package.Name; name != "lodash" {
+		t.Errorf("expected the order of packages to be kept, but %s is first", name)
+	}
+}
+
+func TestMeetsScoreThreshold(t *testing.T) {
+	t.Parallel()
+
+	results := func(scores ...float64) models.VulnerabilityResults {
+		pkg := models.PackageVulns{}
+		for _, score := range scores {
+			pkg.Groups = append(pkg.Groups, models.GroupInfo{Score: score})
+		}
+
+		return models.VulnerabilityResults{Results: []models.PackageSource{{Packages: []models.PackageVulns{pkg}}}}
+	}
+
+	tests := []struct {
+		name      string
+		results   models.VulnerabilityResults
+		threshold float64
+		want      bool
+	}{
+		{name: "no threshold", results: results(1.5), threshold: 0, want: true},
+		{name: "below threshold", results: results(1.5, 6.9), threshold: 7, want: false},
+		{name: "at threshold", results: results(1.5, 7), threshold: 7, want: true},
+		{name: "unknown score", results: results(1.5, 0), threshold: 7, want: true},
+		{name: "no vulnerabilities", results: models.VulnerabilityResults{}, threshold: 0, want: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := meetsScoreThreshold(tt.results, tt.threshold); got != tt.want {
+				t.Errorf("meetsScoreThreshold() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
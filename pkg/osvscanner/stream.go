@@ -0,0 +1,121 @@
+package osvscanner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/osv-scanner/pkg/osv"
+)
+
+// streamBatchSize is how many queries are collected for each request worker
+// before they are sent, which matches the most the API accepts in a single
+// request
+const streamBatchSize = 1000
+
+// queryStream sends queries to the OSV API in batches as inputs are scanned,
+// keeping only the queries that matched vulnerabilities or could not be
+// checked, so that memory stays flat however many packages are found
+type queryStream struct {
+	// ctx stops batches from being sent once it is done
+	ctx context.Context
+	// pending are the queries that have been collected but not yet sent
+	pending   osv.BatchedQuery
+	batchSize int
+	// send sends a batch of queries, defaulting to osv.MakeRequestWithContext
+	send                func(osv.BatchedQuery) (*osv.BatchedResponse, error)
+	allowPartialResults bool
+	// profile records the time spent sending batches as querying
+	profile *Profile
+	// inspectors are called with each batch before it is sent, for checks
+	// that need every package rather than only the vulnerable ones
+	inspectors []func(osv.BatchedQuery)
+
+	// kept are the queries that have been sent and are still of interest,
+	// with resp holding the response to each of them
+	kept osv.BatchedQuery
+	resp osv.BatchedResponse
+	// failed holds the indexes of the kept queries that could not be checked,
+	// with failedErr being the first error that was encountered
+	failed    []int
+	failedErr error
+	// sent is the total number of queries that have been sent
+	sent int
+}
+
+// newQueryStream returns a stream that sends batches split across `workers`
+// concurrent requests, using osv.DefaultRequestWorkers if it is not positive,
+// until ctx is done
+func newQueryStream(ctx context.Context, allowPartialResults bool, workers int) *queryStream {
+	if workers <= 0 {
+		workers = osv.DefaultRequestWorkers
+	}
+
+	return &queryStream{
+		ctx:       ctx,
+		batchSize: streamBatchSize * workers,
+		send: func(query osv.BatchedQuery) (*osv.BatchedResponse, error) {
+			return osv.MakeRequestWithContext(ctx, query, workers)
+		},
+		allowPartialResults: allowPartialResults,
+	}
+}
+
+// empty reports if no queries have been collected at all
+func (s *queryStream) empty() bool {
+	return s.sent == 0 && len(s.pending.Queries) == 0
+}
+
+// flush sends the pending queries if a full batch has been collected, or if
+// `force` is set and there are any pending. As it is called after each input
+// is scanned, it fails once the context of the stream is done so that scans
+// stop promptly when they are cancelled.
+func (s *queryStream) flush(force bool) error {
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+
+	if len(s.pending.Queries) == 0 || (!force && len(s.pending.Queries) < s.batchSize) {
+		return nil
+	}
+
+	batch := s.pending
+	s.pending = osv.BatchedQuery{}
+
+	for _, inspect := range s.inspectors {
+		inspect(batch)
+	}
+
+	start := time.Now()
+	resp, err := s.send(batch)
+	s.profile.Add("querying", time.Since(start))
+	s.profile.addSources(batch.Queries, time.Since(start), true)
+	failed := map[int]bool{}
+	if err != nil {
+		indexes, ok := partialFailures(err, s.allowPartialResults)
+		if !ok {
+			return fmt.Errorf("scan failed %w", &APIError{Err: err})
+		}
+		for _, i := range indexes {
+			failed[i] = true
+		}
+		if s.failedErr == nil {
+			s.failedErr = err
+		}
+	}
+
+	for i, query := range batch.Queries {
+		if failed[i] {
+			s.failed = append(s.failed, len(s.kept.Queries))
+		} else if len(resp.Results[i].Vulns) == 0 {
+			continue
+		}
+
+		s.kept.Queries = append(s.kept.Queries, query)
+		s.resp.Results = append(s.resp.Results, resp.Results[i])
+	}
+
+	s.sent += len(batch.Queries)
+
+	return nil
+}
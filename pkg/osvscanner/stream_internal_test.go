@@ -0,0 +1,128 @@
+package osvscanner
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/osv"
+)
+
+// fakeSend responds to each query for a package named "vulnerable" with a
+// vulnerability, failing any query for a package named "failing"
+func fakeSend(batches *[]int) func(osv.BatchedQuery) (*osv.BatchedResponse, error) {
+	return func(query osv.BatchedQuery) (*osv.BatchedResponse, error) {
+		*batches = append(*batches, len(query.Queries))
+
+		resp := &osv.BatchedResponse{Results: make([]osv.MinimalResponse, len(query.Queries))}
+		partialErr := &osv.PartialResponseError{}
+
+		for i, q := range query.Queries {
+			switch q.Package.Name {
+			case "vulnerable":
+				resp.Results[i].Vulns = []osv.MinimalVulnerability{{ID: "OSV-" + q.Version}}
+			case "failing":
+				partialErr.FailedQueries = append(partialErr.FailedQueries, i)
+				partialErr.Err = errors.New("server error")
+			}
+		}
+
+		if partialErr.Err != nil {
+			return resp, partialErr
+		}
+
+		return resp, nil
+	}
+}
+
+func queryFor(name string, version string) *osv.Query {
+	return &osv.Query{Package: osv.Package{Name: name, Ecosystem: "npm"}, Version: version}
+}
+
+func Test_queryStream_flush(t *testing.T) {
+	t.Parallel()
+
+	var batches []int
+	stream := newQueryStream(context.Background(), true, 1)
+	stream.batchSize = 2
+	stream.send = fakeSend(&batches)
+
+	queries := []*osv.Query{
+		queryFor("safe", "1"),
+		queryFor("vulnerable", "2"),
+		queryFor("failing", "3"),
+		queryFor("safe", "4"),
+		queryFor("vulnerable", "5"),
+	}
+
+	for _, query := range queries {
+		stream.pending.Queries = append(stream.pending.Queries, query)
+		if err := stream.flush(false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(stream.pending.Queries) != 1 {
+		t.Errorf("expected 1 query to be pending, got %d", len(stream.pending.Queries))
+	}
+
+	if err := stream.flush(true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff([]int{2, 2, 1}, batches); diff != "" {
+		t.Errorf("batches mismatch (-want +got):\n%s", diff)
+	}
+
+	if stream.sent != 5 {
+		t.Errorf("expected 5 queries to have been sent, got %d", stream.sent)
+	}
+
+	want := []*osv.Query{queries[1], queries[2], queries[4]}
+	if diff := cmp.Diff(want, stream.kept.Queries); diff != "" {
+		t.Errorf("kept queries mismatch (-want +got):\n%s", diff)
+	}
+
+	if len(stream.resp.Results) != 3 || stream.resp.Results[2].Vulns[0].ID != "OSV-5" {
+		t.Errorf("unexpected responses %+v", stream.resp.Results)
+	}
+
+	if diff := cmp.Diff([]int{1}, stream.failed); diff != "" {
+		t.Errorf("failed queries mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_queryStream_flush_Error(t *testing.T) {
+	t.Parallel()
+
+	var batches []int
+	stream := newQueryStream(context.Background(), false, 1)
+	stream.send = fakeSend(&batches)
+	stream.pending.Queries = []*osv.Query{queryFor("failing", "1")}
+
+	err := stream.flush(true)
+	if !errors.Is(err, ErrAPIUnavailable) {
+		t.Errorf("expected ErrAPIUnavailable, got %v", err)
+	}
+}
+
+func Test_queryStream_flush_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var batches []int
+	stream := newQueryStream(ctx, false, 1)
+	stream.send = fakeSend(&batches)
+	stream.pending.Queries = []*osv.Query{queryFor("vulnerable", "1")}
+
+	cancel()
+
+	if err := stream.flush(true); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if len(batches) != 0 {
+		t.Errorf("expected no batches to be sent, got %v", batches)
+	}
+}
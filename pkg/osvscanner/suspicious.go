@@ -0,0 +1,125 @@
+package osvscanner
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/typosquat"
+)
+
+// maliciousPrefix is the prefix of the IDs of the advisories in OSV that
+// report packages as malicious, rather than as having a vulnerability
+const maliciousPrefix = "MAL-"
+
+// typosquatDetector checks the names of the packages of lockfiles as they are
+// scanned, reporting each likely typosquat once for each lockfile it is in
+type typosquatDetector struct {
+	mu         sync.Mutex
+	seen       map[string]bool
+	typosquats []models.SuspiciousPackage
+}
+
+func newTyposquatDetector() *typosquatDetector {
+	return &typosquatDetector{seen: map[string]bool{}}
+}
+
+// inspect checks the name of each package of a lockfile in the batch
+func (d *typosquatDetector) inspect(batch osv.BatchedQuery) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, query := range batch.Queries {
+		if query.Source.Type != "lockfile" || query.Package.Name == "" {
+			continue
+		}
+
+		key := query.Source.Path + "\x00" + query.Package.Ecosystem + "\x00" + query.Package.Name + "\x00" + query.Version
+		if d.seen[key] {
+			continue
+		}
+		d.seen[key] = true
+
+		similarTo, ok := typosquat.Detect(query.Package.Ecosystem, query.Package.Name)
+		if !ok {
+			continue
+		}
+
+		d.typosquats = append(d.typosquats, models.SuspiciousPackage{
+			Source: query.Source,
+			Package: models.PackageInfo{
+				Name:      query.Package.Name,
+				Version:   query.Version,
+				Ecosystem: query.Package.Ecosystem,
+			},
+			Kind:      "typosquat",
+			SimilarTo: similarTo,
+		})
+	}
+}
+
+// maliciousPackages returns the packages in the results that have advisories
+// reporting them as malicious, either directly or as an alias
+func maliciousPackages(results models.VulnerabilityResults) []models.SuspiciousPackage {
+	var packages []models.SuspiciousPackage
+
+	for _, source := range results.Results {
+		for _, pkg := range source.Packages {
+			var ids []string
+			for _, vuln := range pkg.Vulnerabilities {
+				for _, id := range append([]string{vuln.ID}, vuln.Aliases...) {
+					if strings.HasPrefix(id, maliciousPrefix) {
+						ids = append(ids, id)
+					}
+				}
+			}
+
+			if len(ids) == 0 {
+				continue
+			}
+
+			sort.Strings(ids)
+			packages = append(packages, models.SuspiciousPackage{
+				Source:  source.Source,
+				Package: pkg.Package,
+				Kind:    "malicious",
+				IDs:     dedupeSorted(ids),
+			})
+		}
+	}
+
+	return packages
+}
+
+// dedupeSorted removes the repeated strings from a sorted slice
+func dedupeSorted(values []string) []string {
+	deduped := values[:0]
+	for i, v := range values {
+		if i == 0 || v != values[i-1] {
+			deduped = append(deduped, v)
+		}
+	}
+
+	return deduped
+}
+
+// sortSuspiciousPackages orders the packages by their sources and names, with
+// malicious packages before typosquats of the same package
+func sortSuspiciousPackages(packages []models.SuspiciousPackage) {
+	sort.SliceStable(packages, func(a, b int) bool {
+		pa, pb := packages[a], packages[b]
+		if pa.Source.Path != pb.Source.Path {
+			return pa.Source.Path < pb.Source.Path
+		}
+		if pa.Package.Name != pb.Package.Name {
+			return pa.Package.Name < pb.Package.Name
+		}
+		if pa.Package.Version != pb.Package.Version {
+			return pa.Package.Version < pb.Package.Version
+		}
+
+		return pa.Kind < pb.Kind
+	})
+}
@@ -0,0 +1,87 @@
+package osvscanner
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+)
+
+func Test_typosquatDetector(t *testing.T) {
+	t.Parallel()
+
+	lockfileA := models.SourceInfo{Path: "/a/package-lock.json", Type: "lockfile"}
+	lockfileB := models.SourceInfo{Path: "/b/requirements.txt", Type: "lockfile"}
+	query := func(source models.SourceInfo, name string, version string, ecosystem string) *osv.Query {
+		return &osv.Query{Source: source, Version: version, Package: osv.Package{Name: name, Ecosystem: ecosystem}}
+	}
+
+	detector := newTyposquatDetector()
+	detector.inspect(osv.BatchedQuery{Queries: []*osv.Query{
+		query(lockfileA, "lodash", "4.17.21", "npm"),
+		query(lockfileA, "lodahs", "1.0.0", "npm"),
+		query(lockfileB, "reqeusts", "2.0.0", "PyPI"),
+		query(models.SourceInfo{Path: "/bom.cdx.json", Type: "sbom"}, "expres", "1.0.0", "npm"),
+	}})
+	// the same packages in later batches are only reported once
+	detector.inspect(osv.BatchedQuery{Queries: []*osv.Query{
+		query(lockfileA, "lodahs", "1.0.0", "npm"),
+	}})
+
+	want := []models.SuspiciousPackage{
+		{
+			Source:    lockfileA,
+			Package:   models.PackageInfo{Name: "lodahs", Version: "1.0.0", Ecosystem: "npm"},
+			Kind:      "typosquat",
+			SimilarTo: "lodash",
+		},
+		{
+			Source:    lockfileB,
+			Package:   models.PackageInfo{Name: "reqeusts", Version: "2.0.0", Ecosystem: "PyPI"},
+			Kind:      "typosquat",
+			SimilarTo: "requests",
+		},
+	}
+
+	if diff := cmp.Diff(want, detector.typosquats); diff != "" {
+		t.Errorf("typosquats mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_maliciousPackages(t *testing.T) {
+	t.Parallel()
+
+	lockfile := models.SourceInfo{Path: "/a/package-lock.json", Type: "lockfile"}
+	results := models.VulnerabilityResults{
+		Results: []models.PackageSource{{
+			Source: lockfile,
+			Packages: []models.PackageVulns{
+				{
+					Package: models.PackageInfo{Name: "left-pad", Version: "1.0.0", Ecosystem: "npm"},
+					Vulnerabilities: []models.Vulnerability{
+						{ID: "GHSA-xxxx-xxxx-xxxx"},
+					},
+				},
+				{
+					Package: models.PackageInfo{Name: "lodahs", Version: "1.0.0", Ecosystem: "npm"},
+					Vulnerabilities: []models.Vulnerability{
+						{ID: "MAL-2023-2", Aliases: []string{"GHSA-yyyy-yyyy-yyyy"}},
+						{ID: "GHSA-zzzz-zzzz-zzzz", Aliases: []string{"MAL-2023-1", "MAL-2023-2"}},
+					},
+				},
+			},
+		}},
+	}
+
+	want := []models.SuspiciousPackage{{
+		Source:  lockfile,
+		Package: models.PackageInfo{Name: "lodahs", Version: "1.0.0", Ecosystem: "npm"},
+		Kind:    "malicious",
+		IDs:     []string{"MAL-2023-1", "MAL-2023-2"},
+	}}
+
+	if diff := cmp.Diff(want, maliciousPackages(results)); diff != "" {
+		t.Errorf("maliciousPackages() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,222 @@
+package osvscanner
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+// maxSymbolUsages is how many usages of the affected symbols of a group are
+// reported, as a few are enough to show that the vulnerable code is used
+const maxSymbolUsages = 10
+
+// maxSymbolSearchFileSize is the size of the largest source file that is
+// searched, as larger files are usually generated or minified
+const maxSymbolSearchFileSize = 1 << 20
+
+// sourceExtensions are the extensions of the source files of each ecosystem
+// that the affected symbols of its packages are searched for in
+var sourceExtensions = map[lockfile.Ecosystem][]string{
+	lockfile.GoEcosystem:       {".go"},
+	lockfile.NpmEcosystem:      {".js", ".mjs", ".cjs", ".jsx", ".ts", ".mts", ".cts", ".tsx"},
+	lockfile.PipEcosystem:      {".py"},
+	lockfile.CargoEcosystem:    {".rs"},
+	lockfile.MavenEcosystem:    {".java", ".kt", ".scala", ".groovy"},
+	lockfile.BundlerEcosystem:  {".rb"},
+	lockfile.ComposerEcosystem: {".php"},
+	lockfile.NuGetEcosystem:    {".cs", ".fs", ".vb"},
+	lockfile.PubEcosystem:      {".dart"},
+	lockfile.MixEcosystem:      {".ex", ".exs"},
+	lockfile.ConanEcosystem:    {".c", ".cc", ".cpp", ".cxx", ".h", ".hpp"},
+}
+
+// skippedSearchDirs are the directories that are not searched for usages,
+// being dependencies, build output and test data rather than project code
+var skippedSearchDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"target":       true,
+	"build":        true,
+	"dist":         true,
+	"testdata":     true,
+	"__pycache__":  true,
+}
+
+// identifierRegexp matches the names that can be searched for
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// symbolSearchName returns the name that the affected symbol is used by in
+// code, being the last element of its qualified name such as "Parse" of
+// "golang.org/x/text/language.Parse" or "serve_connection" of
+// "hyper::server::conn::Http::serve_connection", or "" if it is not a
+// function or method, such as a Go package that is affected as a whole
+func symbolSearchName(symbol string) string {
+	name := symbol
+	if i := strings.LastIndex(name, "::"); i != -1 {
+		name = name[i+2:]
+	} else if i := strings.LastIndex(name, "."); i != -1 {
+		name = name[i+1:]
+	}
+
+	// names without a qualifier are too likely to be something else
+	if name == symbol || !identifierRegexp.MatchString(name) {
+		return ""
+	}
+
+	return name
+}
+
+// symbolCallRegexp matches calls of any of the names, capturing the name
+func symbolCallRegexp(names []string) *regexp.Regexp {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, regexp.QuoteMeta(name))
+	}
+
+	return regexp.MustCompile(`(?:^|[^A-Za-z0-9_$])(` + strings.Join(quoted, "|") + `)\s*\(`)
+}
+
+// sourceFiles returns the source files with the given extensions within dir,
+// in the order they are walked
+func sourceFiles(dir string, extensions []string) []string {
+	var files []string
+
+	_ = filepath.WalkDir(dir, func(path string, info fs.DirEntry, err error) error {
+		if err != nil {
+			return nil //nolint:nilerr // unreadable directories are skipped
+		}
+
+		if info.IsDir() {
+			name := info.Name()
+			if path != dir && (skippedSearchDirs[name] || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		for _, ext := range extensions {
+			if strings.HasSuffix(path, ext) {
+				files = append(files, path)
+
+				break
+			}
+		}
+
+		return nil
+	})
+
+	return files
+}
+
+// searchSymbols returns the lines of the files that call any of the affected
+// symbols, up to maxSymbolUsages of them
+func searchSymbols(files []string, symbols []string) []models.SymbolUsage {
+	bySearchName := map[string]string{}
+	var names []string
+	for _, symbol := range symbols {
+		name := symbolSearchName(symbol)
+		if name == "" {
+			continue
+		}
+		if _, ok := bySearchName[name]; !ok {
+			bySearchName[name] = symbol
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+	re := symbolCallRegexp(names)
+
+	var usages []models.SymbolUsage
+	for _, path := range files {
+		if info, err := os.Stat(path); err != nil || info.Size() > maxSymbolSearchFileSize {
+			continue
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxSymbolSearchFileSize)
+		for line := 1; scanner.Scan(); line++ {
+			for _, match := range re.FindAllStringSubmatchIndex(scanner.Text(), -1) {
+				name := scanner.Text()[match[2]:match[3]]
+				usages = append(usages, models.SymbolUsage{
+					Symbol: bySearchName[name],
+					Path:   path,
+					Line:   line,
+					Column: match[2] + 1,
+				})
+
+				if len(usages) == maxSymbolUsages {
+					file.Close()
+					return usages
+				}
+			}
+		}
+		file.Close()
+	}
+
+	return usages
+}
+
+// findSymbolUsages searches the code around each lockfile and manifest for
+// calls of the affected symbols of the vulnerabilities of its packages,
+// adding them to the groups of the vulnerabilities. Only the names of the
+// symbols are looked for, in the source files of the ecosystem of the
+// package, so this is cheap evidence of whether the vulnerable code is used
+// rather than an analysis of the program.
+func findSymbolUsages(r output.Reporter, vulnResult *models.VulnerabilityResults) {
+	files := map[string][]string{}
+	found := 0
+
+	for i := range vulnResult.Results {
+		source := &vulnResult.Results[i]
+		if source.Source.Type != "lockfile" && source.Source.Type != "manifest" {
+			continue
+		}
+		dir := filepath.Dir(source.Source.Path)
+
+		for j := range source.Packages {
+			pkg := &source.Packages[j]
+			ecosystem := lockfile.Ecosystem(pkg.Package.Ecosystem)
+			extensions, ok := sourceExtensions[ecosystem]
+			if !ok {
+				continue
+			}
+
+			for k := range pkg.Groups {
+				group := &pkg.Groups[k]
+				if len(group.AffectedSymbols) == 0 {
+					continue
+				}
+
+				key := dir + "\x00" + string(ecosystem)
+				if _, ok := files[key]; !ok {
+					files[key] = sourceFiles(dir, extensions)
+				}
+
+				group.SymbolUsages = searchSymbols(files[key], group.AffectedSymbols)
+				if len(group.SymbolUsages) > 0 {
+					found++
+				}
+			}
+		}
+	}
+
+	r.PrintText(fmt.Sprintf("Found usages of the affected symbols of %d vulnerabilities\n", found))
+}
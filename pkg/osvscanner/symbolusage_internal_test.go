@@ -0,0 +1,76 @@
+package osvscanner
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+func TestSymbolSearchName(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"golang.org/x/text/language.Parse":            "Parse",
+		"net/http.Client.Do":                          "Do",
+		"hyper::server::conn::Http::serve_connection": "serve_connection",
+		"golang.org/x/text/internal/tag":              "",
+		"net/http":                                    "",
+		"setKey":                                      "",
+		"lodash.template":                             "template",
+		"django.utils.html.urlize":                    "urlize",
+		"org.apache.logging.log4j.core.lookup.JndiLookup.lookup": "lookup",
+	}
+
+	for symbol, want := range tests {
+		if got := symbolSearchName(symbol); got != want {
+			t.Errorf("symbolSearchName(%q) = %q, want %q", symbol, got, want)
+		}
+	}
+}
+
+func TestFindSymbolUsages(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/app\n",
+		"main.go": "package main\n\nimport \"golang.org/x/text/language\"\n\n" +
+			"func main() {\n\ttag, _ := language.Parse(\"en\")\n\t_ = tag\n\t_ = Parsed(1)\n}\n",
+		"internal/match.go":        "package internal\n\nfunc m() { language.MatchStrings(nil, \"en\") }\n",
+		"vendor/golang.org/x/a.go": "package a\n\nfunc f() { language.Parse(\"en\") }\n",
+		"app.js":                   "language.Parse('en')\n",
+	})
+
+	results := models.VulnerabilityResults{Results: []models.PackageSource{{
+		Source: models.SourceInfo{Path: filepath.Join(dir, "go.mod"), Type: "lockfile"},
+		Packages: []models.PackageVulns{{
+			Package: models.PackageInfo{Name: "golang.org/x/text", Version: "0.3.5", Ecosystem: "Go"},
+			Groups: []models.GroupInfo{
+				{
+					IDs:             []string{"GO-2021-0113"},
+					AffectedSymbols: []string{"golang.org/x/text/language.MatchStrings", "golang.org/x/text/language.Parse"},
+				},
+				{IDs: []string{"GO-2020-0015"}, AffectedSymbols: []string{"golang.org/x/text/encoding/unicode"}},
+				{IDs: []string{"GO-2022-1059"}},
+			},
+		}},
+	}}}
+
+	findSymbolUsages(output.NewVoidReporter(), &results)
+
+	groups := results.Results[0].Packages[0].Groups
+	want := []models.SymbolUsage{
+		{Symbol: "golang.org/x/text/language.MatchStrings", Path: filepath.Join(dir, "internal", "match.go"), Line: 3, Column: 21},
+		{Symbol: "golang.org/x/text/language.Parse", Path: filepath.Join(dir, "main.go"), Line: 6, Column: 21},
+	}
+	if diff := cmp.Diff(want, groups[0].SymbolUsages); diff != "" {
+		t.Errorf("findSymbolUsages() mismatch (-want +got):\n%s", diff)
+	}
+
+	if groups[1].SymbolUsages != nil || groups[2].SymbolUsages != nil {
+		t.Errorf("expected no usages of packages that are affected as a whole, got %v and %v", groups[1].SymbolUsages, groups[2].SymbolUsages)
+	}
+}
@@ -0,0 +1,334 @@
+package osvscanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/google/osv-scanner/pkg/gitauth"
+	"github.com/google/osv-scanner/pkg/github"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/output"
+
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v2"
+)
+
+// Target is a set of inputs that are scanned together with their own options,
+// as listed in a targets file
+type Target struct {
+	Name        string   `yaml:"name"`
+	Directories []string `yaml:"directories"`
+	Lockfiles   []string `yaml:"lockfiles"`
+	SBOMs       []string `yaml:"sboms"`
+	Docker      []string `yaml:"docker"`
+	Artifacts   []string `yaml:"artifacts"`
+	// Git lists remote repositories, which are cloned and scanned recursively
+	Git       []string `yaml:"git"`
+	Recursive bool     `yaml:"recursive"`
+	SkipGit   bool     `yaml:"skipGit"`
+	NoIgnore  bool     `yaml:"noIgnore"`
+	Config    string   `yaml:"config"`
+	// NestedRepos is the policy for nested git repositories, defaulting to
+	// the one the scan was run with
+	NestedRepos string `yaml:"nestedRepos"`
+}
+
+var errInvalidTargets = errors.New("invalid targets file")
+
+var errOrganizationWithOtherInputs = errors.New("scanning a GitHub organization cannot be combined with other inputs")
+
+// LoadTargets reads the targets to scan from a YAML file, resolving any
+// relative paths against the directory the file is in
+func LoadTargets(path string) ([]Target, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		Targets []Target `yaml:"targets"`
+	}
+
+	if err := yaml.UnmarshalStrict(content, &file); err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidTargets, err)
+	}
+
+	base := filepath.Dir(path)
+	resolve := func(p string) string {
+		if p == "" || filepath.IsAbs(p) {
+			return p
+		}
+
+		return filepath.Join(base, p)
+	}
+
+	seen := map[string]bool{}
+
+	for i, target := range file.Targets {
+		if target.Name == "" {
+			return nil, fmt.Errorf("%w: target %d does not have a name", errInvalidTargets, i+1)
+		}
+		if seen[target.Name] {
+			return nil, fmt.Errorf("%w: %s is listed more than once", errInvalidTargets, target.Name)
+		}
+		seen[target.Name] = true
+
+		if target.NestedRepos != "" && !slices.Contains(NestedRepoPolicies, target.NestedRepos) {
+			return nil, fmt.Errorf("%w: %s has an unknown nestedRepos policy %q", errInvalidTargets, target.Name, target.NestedRepos)
+		}
+
+		for j, dir := range target.Directories {
+			target.Directories[j] = resolve(dir)
+		}
+		for j, sbom := range target.SBOMs {
+			target.SBOMs[j] = resolve(sbom)
+		}
+		for j, artifact := range target.Artifacts {
+			target.Artifacts[j] = resolve(artifact)
+		}
+		for j, lockfileElem := range target.Lockfiles {
+			parseAs, lockfilePath := parseLockfilePath(lockfileElem)
+			target.Lockfiles[j] = parseAs + ":" + resolve(lockfilePath)
+		}
+		file.Targets[i].Config = resolve(target.Config)
+	}
+
+	return file.Targets, nil
+}
+
+// hasDirectInputs reports if any inputs have been given other than a targets file
+func (actions ScannerActions) hasDirectInputs() bool {
+	return len(actions.LockfilePaths) > 0 ||
+		len(actions.SBOMPaths) > 0 ||
+		len(actions.ArtifactPaths) > 0 ||
+		len(actions.DirectoryPaths) > 0 ||
+		len(actions.GitCommits) > 0 ||
+		len(actions.DockerContainerNames) > 0 ||
+		actions.HostRoot != ""
+}
+
+// targetActions returns the actions to scan the given target with, cloning
+// any remote repositories it lists. The returned function removes the clones.
+func targetActions(ctx context.Context, actions ScannerActions, target Target, credentials gitauth.Credentials) (ScannerActions, func(), error) {
+	targetActions := actions
+	targetActions.TargetsPath = ""
+	targetActions.DirectoryPaths = append([]string{}, target.Directories...)
+	targetActions.LockfilePaths = target.Lockfiles
+	targetActions.SBOMPaths = target.SBOMs
+	targetActions.DockerContainerNames = target.Docker
+	targetActions.HostRoot = ""
+	targetActions.ArtifactPaths = target.Artifacts
+	targetActions.GitCommits = nil
+	targetActions.Recursive = target.Recursive
+	targetActions.SkipGit = target.SkipGit
+	targetActions.NoIgnore = target.NoIgnore
+	if target.Config != "" {
+		targetActions.ConfigOverridePath = target.Config
+	}
+	if target.NestedRepos != "" {
+		targetActions.NestedRepos = target.NestedRepos
+	}
+
+	var clones []string
+	cleanup := func() {
+		for _, dir := range clones {
+			os.RemoveAll(dir)
+		}
+	}
+
+	for _, url := range target.Git {
+		dir, err := CloneRepositoryWithContext(ctx, url, credentials)
+		if err != nil {
+			cleanup()
+
+			return targetActions, func() {}, err
+		}
+
+		clones = append(clones, dir)
+		targetActions.DirectoryPaths = append(targetActions.DirectoryPaths, dir)
+		targetActions.Recursive = true
+	}
+
+	return targetActions, cleanup, nil
+}
+
+// organizationTargets returns a target for each repository in the given
+// GitHub organization, skipping those that are archived or forks
+func organizationTargets(ctx context.Context, r output.Reporter, client *github.Client, org string) ([]Target, error) {
+	repositories, err := client.OrganizationRepositories(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]Target, 0, len(repositories))
+	skipped := 0
+
+	for _, repository := range repositories {
+		if repository.Archived || repository.Fork {
+			skipped++
+			continue
+		}
+
+		targets = append(targets, Target{Name: repository.FullName, Git: []string{repository.CloneURL}})
+	}
+
+	r.PrintText(fmt.Sprintf("Found %d repositories in %s", len(targets), org))
+	if skipped > 0 {
+		r.PrintText(fmt.Sprintf(", skipping %d that are archived or forks", skipped))
+	}
+	r.PrintText("\n")
+
+	return targets, nil
+}
+
+// loadTargets returns the targets to scan, either from the targets file or
+// the repositories of a GitHub organization
+func loadTargets(ctx context.Context, actions ScannerActions, r output.Reporter) ([]Target, error) {
+	if actions.GitHubOrganization != "" {
+		if actions.hasDirectInputs() || actions.TargetsPath != "" {
+			return nil, errOrganizationWithOtherInputs
+		}
+
+		return organizationTargets(ctx, r, github.NewClient(), actions.GitHubOrganization)
+	}
+
+	if actions.hasDirectInputs() {
+		return nil, &ConfigError{
+			Path: actions.TargetsPath,
+			Err:  fmt.Errorf("%w: cannot be combined with other inputs", errInvalidTargets),
+		}
+	}
+
+	targets, err := LoadTargets(actions.TargetsPath)
+	if err != nil {
+		r.PrintError(fmt.Sprintf("Failed to read targets file: %s\n", err))
+		return nil, &ConfigError{Path: actions.TargetsPath, Err: err}
+	}
+
+	return targets, nil
+}
+
+// doScanTargets scans each target separately, and then combines the results
+func doScanTargets(ctx context.Context, actions ScannerActions, r output.Reporter) (models.VulnerabilityResults, error) {
+	targets, err := loadTargets(ctx, actions, r)
+	if err != nil {
+		return models.VulnerabilityResults{}, err
+	}
+
+	var credentials gitauth.Credentials
+	if actions.GitCredentialsPath != "" {
+		credentials, err = gitauth.Load(actions.GitCredentialsPath)
+		if err != nil {
+			r.PrintError(fmt.Sprintf("Failed to read git credentials: %s\n", err))
+			return models.VulnerabilityResults{}, &ConfigError{Path: actions.GitCredentialsPath, Err: err}
+		}
+	}
+
+	results := models.VulnerabilityResults{Results: []models.PackageSource{}}
+	foundPackages := false
+	// whether vulnerabilities fail the scan depends on the config of the
+	// target that they were found in, so is decided by the scan of each
+	failing := false
+
+	for _, target := range targets {
+		r.PrintText(fmt.Sprintf("Scanning target %s\n", target.Name))
+
+		summary := models.TargetSummary{Name: target.Name}
+
+		targetActions, cleanup, err := targetActions(ctx, actions, target, credentials)
+		var targetResults models.VulnerabilityResults
+		if err == nil {
+			targetResults, err = DoScanWithContext(ctx, targetActions, r)
+			cleanup()
+		}
+		// cancelled scans stop rather than skipping the remaining targets
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return results, ctxErr
+		}
+
+		switch {
+		case err == nil, errors.Is(err, VulnerabilitiesFoundErr), errors.Is(err, ErrRegistryMismatch), errors.Is(err, ErrLicenseViolation):
+			foundPackages = true
+			failing = failing || errors.Is(err, VulnerabilitiesFoundErr)
+		case errors.Is(err, NoPackagesFoundErr):
+		default:
+			if actions.Strict {
+				return results, fmt.Errorf("failed to scan target %s: %w", target.Name, err)
+			}
+
+			summary.Error = err.Error()
+			results.Skipped = append(results.Skipped, models.SkippedSource{
+				Source: models.SourceInfo{Path: target.Name, Type: "target"},
+				Reason: err.Error(),
+			})
+		}
+
+		for i := range targetResults.Results {
+			targetResults.Results[i].Target = target.Name
+		}
+
+		summary.Vulnerabilities = len(targetResults.Flatten())
+
+		results.Results = append(results.Results, targetResults.Results...)
+		results.ParseFailures = append(results.ParseFailures, targetResults.ParseFailures...)
+		results.Skipped = append(results.Skipped, targetResults.Skipped...)
+		results.RegistryIssues = append(results.RegistryIssues, targetResults.RegistryIssues...)
+		results.SuspiciousPackages = append(results.SuspiciousPackages, targetResults.SuspiciousPackages...)
+		results.Outdated = append(results.Outdated, targetResults.Outdated...)
+		results.Deprecated = append(results.Deprecated, targetResults.Deprecated...)
+		results.Scorecards = append(results.Scorecards, targetResults.Scorecards...)
+		results.Upgrades = append(results.Upgrades, targetResults.Upgrades...)
+		results.Reachability = append(results.Reachability, targetResults.Reachability...)
+		results.LicenseViolations = append(results.LicenseViolations, targetResults.LicenseViolations...)
+		results.History = append(results.History, targetResults.History...)
+		results.Inventory = append(results.Inventory, targetResults.Inventory...)
+		results.Plan = append(results.Plan, targetResults.Plan...)
+		results.Targets = append(results.Targets, summary)
+	}
+
+	vulnerable, failed := 0, 0
+	for _, summary := range results.Targets {
+		switch {
+		case summary.Error != "":
+			failed++
+			r.PrintText(output.Localize(r, "Target %s could not be scanned: %s", summary.Name, summary.Error) + "\n")
+		case summary.Vulnerabilities > 0:
+			vulnerable++
+			fallthrough
+		default:
+			r.PrintText(output.Localize(r, "Target %s has %d vulnerabilities", summary.Name, summary.Vulnerabilities) + "\n")
+		}
+	}
+	// the profile is shared by the scans of every target, so holds the
+	// timings of all of them already
+	if !actions.Reproducible {
+		results.Timings = actions.Profile.Sources()
+	}
+
+	r.PrintText(output.Localize(
+		r,
+		"Scanned %d targets: %d with vulnerabilities, %d could not be scanned",
+		len(results.Targets), vulnerable, failed,
+	) + "\n")
+
+	if failing {
+		return results, VulnerabilitiesFoundErr
+	}
+
+	if len(results.RegistryIssues) > 0 {
+		return results, fmt.Errorf("%w: %d packages", ErrRegistryMismatch, len(results.RegistryIssues))
+	}
+
+	if len(results.LicenseViolations) > 0 {
+		return results, fmt.Errorf("%w: %d packages", ErrLicenseViolation, len(results.LicenseViolations))
+	}
+
+	if !foundPackages {
+		return results, NoPackagesFoundErr
+	}
+
+	return results, nil
+}
@@ -0,0 +1,128 @@
+package osvscanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/github"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+func writeTargetsFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "targets.yaml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("could not write targets file: %v", err)
+	}
+
+	return path
+}
+
+func TestLoadTargets(t *testing.T) {
+	t.Parallel()
+
+	path := writeTargetsFile(t, `
+targets:
+  - name: api
+    directories: [services/api]
+    lockfiles: [requirements.txt:deps/requirements.txt, /abs/go.mod]
+    recursive: true
+  - name: web
+    git: [https://example.com/web.git]
+    config: osv-scanner.toml
+`)
+	base := filepath.Dir(path)
+
+	targets, err := LoadTargets(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Target{
+		{
+			Name:        "api",
+			Directories: []string{filepath.Join(base, "services/api")},
+			Lockfiles: []string{
+				"requirements.txt:" + filepath.Join(base, "deps/requirements.txt"),
+				":/abs/go.mod",
+			},
+			Recursive: true,
+		},
+		{
+			Name:   "web",
+			Git:    []string{"https://example.com/web.git"},
+			Config: filepath.Join(base, "osv-scanner.toml"),
+		},
+	}
+
+	if diff := cmp.Diff(want, targets); diff != "" {
+		t.Errorf("targets mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLoadTargets_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "missing name", content: "targets:\n  - directories: [.]\n"},
+		{name: "duplicate name", content: "targets:\n  - name: a\n  - name: a\n"},
+		{name: "unknown key", content: "targets:\n  - name: a\n    directory: .\n"},
+		{name: "unknown nested repos policy", content: "targets:\n  - name: a\n    nestedRepos: ignore\n"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := LoadTargets(writeTargetsFile(t, tt.content))
+			if !errors.Is(err, errInvalidTargets) {
+				t.Errorf("expected errInvalidTargets, got %v", err)
+			}
+		})
+	}
+}
+
+func Test_organizationTargets(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/orgs/my-org/repos" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode([]map[string]any{
+			{"full_name": "my-org/api", "clone_url": "https://github.com/my-org/api.git"},
+			{"full_name": "my-org/old", "clone_url": "https://github.com/my-org/old.git", "archived": true},
+			{"full_name": "my-org/fork", "clone_url": "https://github.com/my-org/fork.git", "fork": true},
+			{"full_name": "my-org/web", "clone_url": "https://github.com/my-org/web.git", "private": true},
+		})
+	}))
+	defer server.Close()
+
+	targets, err := organizationTargets(context.Background(), output.NewVoidReporter(), &github.Client{BaseURL: server.URL}, "my-org")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Target{
+		{Name: "my-org/api", Git: []string{"https://github.com/my-org/api.git"}},
+		{Name: "my-org/web", Git: []string{"https://github.com/my-org/web.git"}},
+	}
+
+	if diff := cmp.Diff(want, targets); diff != "" {
+		t.Errorf("targets mismatch (-want +got):\n%s", diff)
+	}
+}
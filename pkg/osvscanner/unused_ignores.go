@@ -0,0 +1,170 @@
+package osvscanner
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/osv-scanner/pkg/config"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+// ignoreUsage records which of the ignores of each config file match the
+// vulnerabilities found in the sources that the config applies to, so that
+// the ignores that no longer match anything can be reported
+type ignoreUsage struct {
+	// configs are keyed by the paths they were loaded from
+	configs map[string]config.Config
+	// ids are the IDs of the vulnerabilities found for each config
+	ids map[string]map[string]bool
+	// paths are the ignored paths of each config that matched a package
+	// with vulnerabilities
+	paths map[string]map[string]bool
+	// sources are the paths of every source that was queried, including
+	// those without any vulnerabilities
+	sources map[string]bool
+}
+
+func newIgnoreUsage() *ignoreUsage {
+	return &ignoreUsage{
+		configs: map[string]config.Config{},
+		ids:     map[string]map[string]bool{},
+		paths:   map[string]map[string]bool{},
+		sources: map[string]bool{},
+	}
+}
+
+// inspect notes the sources of a batch of queries, so that the configs of
+// sources without any vulnerabilities can be checked for unused ignores too
+func (u *ignoreUsage) inspect(batch osv.BatchedQuery) {
+	for _, query := range batch.Queries {
+		u.sources[query.Source.Path] = true
+	}
+}
+
+// add starts tracking the usage of the ignores of the config, returning if
+// it has any to track
+func (u *ignoreUsage) add(configToUse config.Config) bool {
+	// configs that were not loaded from a file have no ignores to clean up
+	if configToUse.LoadPath == "" {
+		return false
+	}
+
+	if _, ok := u.configs[configToUse.LoadPath]; !ok {
+		u.configs[configToUse.LoadPath] = configToUse
+		u.ids[configToUse.LoadPath] = map[string]bool{}
+		u.paths[configToUse.LoadPath] = map[string]bool{}
+	}
+
+	return true
+}
+
+// record notes the vulnerabilities found for a package declared in the given
+// file, which the config applies to. Ignores are matched regardless of if
+// they have expired, as they are still in use until the vulnerability is gone.
+func (u *ignoreUsage) record(configToUse config.Config, declaringFile string, vulns []osv.MinimalVulnerability) {
+	if len(vulns) == 0 || !u.add(configToUse) {
+		return
+	}
+
+	configPath := configToUse.LoadPath
+
+	for _, vuln := range vulns {
+		u.ids[configPath][vuln.ID] = true
+	}
+
+	for _, entry := range configToUse.IgnoredPathsMatching(declaringFile) {
+		u.paths[configPath][entry.Path] = true
+	}
+}
+
+// unused returns the ignores of the recorded configs that did not match any
+// vulnerabilities, ordered by config and then as they appear in the config
+func (u *ignoreUsage) unused() []models.UnusedIgnore {
+	configPaths := make([]string, 0, len(u.configs))
+	for configPath := range u.configs {
+		configPaths = append(configPaths, configPath)
+	}
+	sort.Strings(configPaths)
+
+	var unused []models.UnusedIgnore
+	for _, configPath := range configPaths {
+		configToUse := u.configs[configPath]
+
+		for _, entry := range configToUse.IgnoredVulns {
+			if !u.ids[configPath][entry.ID] {
+				unused = append(unused, models.UnusedIgnore{ConfigPath: configPath, ID: entry.ID, Reason: entry.Reason})
+			}
+		}
+
+		for _, entry := range configToUse.IgnoredPaths {
+			if !u.paths[configPath][entry.Path] {
+				unused = append(unused, models.UnusedIgnore{ConfigPath: configPath, Path: entry.Path, Reason: entry.Reason})
+			}
+		}
+	}
+
+	return unused
+}
+
+// reportUnusedIgnores prints the ignores of the configs of the queried sources
+// that did not match any vulnerabilities, returning them to be included in
+// the results
+func reportUnusedIgnores(r output.Reporter, usage *ignoreUsage, configManager *config.ConfigManager) []models.UnusedIgnore {
+	for source := range usage.sources {
+		usage.add(configManager.Get(r, source))
+	}
+
+	unused := usage.unused()
+
+	for _, ignore := range unused {
+		ignored := ignore.ID
+		if ignored == "" {
+			ignored = ignore.Path
+		}
+		r.PrintText(output.Localize(r, "The ignore for %s in %s does not match any vulnerabilities", ignored, ignore.ConfigPath) + "\n")
+	}
+
+	return unused
+}
+
+// expiredIgnore is an ignore of a vulnerability that was found after the
+// ignore's ignoreUntil date had passed, so is no longer honored
+type expiredIgnore struct {
+	// configPath is the config file or lockfile that the ignore is in
+	configPath string
+	id         string
+	until      time.Time
+}
+
+// appendExpired adds the ignore of a vulnerability from the file at
+// configPath to `expired` if it matched the vulnerability but was not
+// honored, which is only the case when it has expired
+func appendExpired(expired []expiredIgnore, ignored bool, entry config.IgnoreEntry, configPath string) []expiredIgnore {
+	if ignored || entry.ID == "" || entry.IgnoreUntil.IsZero() || configPath == "" {
+		return expired
+	}
+
+	return append(expired, expiredIgnore{configPath: configPath, id: entry.ID, until: entry.IgnoreUntil})
+}
+
+// reportExpiredIgnores warns about the ignores that have expired while their
+// vulnerabilities are still found, so that they can be renewed or removed
+func reportExpiredIgnores(r output.Reporter, expired map[expiredIgnore]bool) {
+	ignores := make([]expiredIgnore, 0, len(expired))
+	for ignore := range expired {
+		ignores = append(ignores, ignore)
+	}
+	sort.Slice(ignores, func(i, j int) bool {
+		if ignores[i].configPath != ignores[j].configPath {
+			return ignores[i].configPath < ignores[j].configPath
+		}
+
+		return ignores[i].id < ignores[j].id
+	})
+
+	for _, ignore := range ignores {
+		r.PrintText(output.Localize(r, "The ignore for %s in %s expired on %s, so it is reported again", ignore.id, ignore.configPath, ignore.until.Format("2006-01-02")) + "\n")
+	}
+}
@@ -0,0 +1,118 @@
+package osvscanner
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/config"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+func TestIgnoreUsage_Unused(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	configPath := filepath.Join(root, "osv-scanner.toml")
+
+	configToUse := config.Config{
+		LoadPath: configPath,
+		IgnoredVulns: []config.IgnoreEntry{
+			{ID: "GHSA-used", Reason: "not exploitable"},
+			{ID: "GHSA-stale", Reason: "fixed long ago"},
+			{ID: "GHSA-expired", IgnoreUntil: time.Now().Add(-time.Hour)},
+		},
+		IgnoredPaths: []config.IgnorePathEntry{
+			{Path: "examples/", Reason: "examples are not deployed"},
+			{Path: "docs/", Reason: "docs are not deployed"},
+		},
+	}
+
+	usage := newIgnoreUsage()
+	usage.record(configToUse, filepath.Join(root, "package-lock.json"), []osv.MinimalVulnerability{{ID: "GHSA-used"}, {ID: "GHSA-expired"}})
+	usage.record(configToUse, filepath.Join(root, "examples", "package-lock.json"), []osv.MinimalVulnerability{{ID: "GHSA-other"}})
+	// packages without vulnerabilities do not use the ignored paths they match
+	usage.record(configToUse, filepath.Join(root, "docs", "package-lock.json"), nil)
+	// configs that were not loaded from files are not reported
+	usage.record(config.Config{IgnoredVulns: []config.IgnoreEntry{{ID: "GHSA-default"}}}, "package-lock.json", []osv.MinimalVulnerability{{ID: "GHSA-used"}})
+
+	want := []models.UnusedIgnore{
+		{ConfigPath: configPath, ID: "GHSA-stale", Reason: "fixed long ago"},
+		{ConfigPath: configPath, Path: "docs/", Reason: "docs are not deployed"},
+	}
+
+	if diff := cmp.Diff(want, usage.unused()); diff != "" {
+		t.Errorf("unused() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReportUnusedIgnores_SourcesWithoutVulnerabilities(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "osv-scanner.toml")
+	configManager := &config.ConfigManager{OverrideConfig: &config.Config{
+		LoadPath:     configPath,
+		IgnoredVulns: []config.IgnoreEntry{{ID: "GHSA-stale"}},
+	}}
+
+	usage := newIgnoreUsage()
+	usage.inspect(osv.BatchedQuery{Queries: []*osv.Query{
+		{Source: models.SourceInfo{Path: "package-lock.json", Type: "lockfile"}},
+	}})
+
+	want := []models.UnusedIgnore{{ConfigPath: configPath, ID: "GHSA-stale"}}
+
+	if diff := cmp.Diff(want, reportUnusedIgnores(output.NewVoidReporter(), usage, configManager)); diff != "" {
+		t.Errorf("reportUnusedIgnores() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_filterResponse_ExpiredIgnores(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	configPath := filepath.Join(root, "osv-scanner.toml")
+	source := models.SourceInfo{Path: filepath.Join(root, "package-lock.json"), Type: "lockfile"}
+
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	configManager := config.ConfigManager{OverrideConfig: &config.Config{
+		LoadPath: configPath,
+		IgnoredVulns: []config.IgnoreEntry{
+			{ID: "GHSA-1", IgnoreUntil: now.AddDate(0, 1, 0)},
+			{ID: "GHSA-2", IgnoreUntil: now.AddDate(0, -1, 0)},
+			// still ignored by the remote ignores
+			{ID: "GHSA-3", IgnoreUntil: now.AddDate(0, -1, 0)},
+		},
+	}}
+	remoteIgnores := dismissals{{IDs: []string{"GHSA-3"}, Reason: "dismissed on GitHub as inaccurate"}}
+
+	query := osv.BatchedQuery{Queries: []*osv.Query{{Source: source}}}
+	resp := &osv.BatchedResponse{Results: []osv.MinimalResponse{
+		{Vulns: []osv.MinimalVulnerability{{ID: "GHSA-1"}, {ID: "GHSA-2"}, {ID: "GHSA-3"}}},
+	}}
+
+	var stdout bytes.Buffer
+	r := output.NewReporter(&stdout, io.Discard, "table")
+
+	if filtered := filterResponse(r, query, resp, &configManager, remoteIgnores, now, newIgnoreUsage()); filtered != 2 {
+		t.Errorf("expected 2 vulnerabilities to be filtered, got %d", filtered)
+	}
+
+	if len(resp.Results[0].Vulns) != 1 || resp.Results[0].Vulns[0].ID != "GHSA-2" {
+		t.Errorf("expected only GHSA-2 to remain, got %v", resp.Results[0].Vulns)
+	}
+
+	want := "The ignore for GHSA-2 in " + configPath + " expired on 2023-05-01, so it is reported again"
+	if !strings.Contains(stdout.String(), want) {
+		t.Errorf("expected the expired ignore to be reported, got:\n%s", stdout.String())
+	}
+	if strings.Count(stdout.String(), "expired on") != 1 {
+		t.Errorf("expected only one ignore to be reported as expired, got:\n%s", stdout.String())
+	}
+}
@@ -0,0 +1,283 @@
+package osvscanner
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/google/osv-scanner/internal/semantic"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+
+	"golang.org/x/exp/slices"
+)
+
+// upgradeAnalyzer works out what upgrading each vulnerable package to its
+// suggested fix involves, resolving the dependency graph of each version of a
+// package only once
+type upgradeAnalyzer struct {
+	lookupTracker[models.PackageInfo, []*osv.Query, models.UpgradeImpact]
+}
+
+func newUpgradeAnalyzer(ctx context.Context, graph func(ctx context.Context, ecosystem string, name string, version string) ([]*osv.Query, error)) *upgradeAnalyzer {
+	dependencies := func(pkg models.PackageInfo) ([]*osv.Query, error) {
+		return graph(ctx, pkg.Ecosystem, pkg.Name, pkg.Version)
+	}
+
+	return &upgradeAnalyzer{
+		lookupTracker: newLookupTracker(dependencies, sortUpgradeImpacts),
+	}
+}
+
+// dependencies returns the dependency graph of the given package version,
+// resolving it if it has not been already, or waiting for it to be if that is
+// in progress
+func (a *upgradeAnalyzer) dependencies(ecosystem string, name string, version string) ([]*osv.Query, error) {
+	return a.get(models.PackageInfo{Name: name, Version: version, Ecosystem: ecosystem})
+}
+
+// nearestFix returns the lowest of the fixed versions that is after the
+// version, or the first of them if the version cannot be compared
+func nearestFix(pkg models.PackageInfo, fixedVersions []string) string {
+	parsed, err := semantic.Parse(pkg.Version, semantic.Ecosystem(pkg.Ecosystem))
+	if err != nil {
+		if len(fixedVersions) > 0 {
+			return fixedVersions[0]
+		}
+
+		return ""
+	}
+
+	var nearest string
+	var nearestParsed semantic.Version
+	for _, fixed := range fixedVersions {
+		if parsed.CompareStr(fixed) >= 0 {
+			continue
+		}
+		if nearestParsed == nil || nearestParsed.CompareStr(fixed) > 0 {
+			fixedParsed, err := semantic.Parse(fixed, semantic.Ecosystem(pkg.Ecosystem))
+			if err != nil {
+				continue
+			}
+			nearestParsed = fixedParsed
+			nearest = fixed
+		}
+	}
+
+	return nearest
+}
+
+// suggestFix returns the upgrade of the package to the lowest version that
+// fixes each of its groups of vulnerabilities that have a fix, reporting false
+// if none of them do
+func suggestFix(pkg models.PackageVulns) (models.UpgradeImpact, bool) {
+	impact := models.UpgradeImpact{Package: pkg.Package, Fixes: []string{}}
+	ecosystem := semantic.Ecosystem(pkg.Package.Ecosystem)
+
+	var fixedParsed semantic.Version
+	nearest := make([]string, len(pkg.Groups))
+	for i, group := range pkg.Groups {
+		var fixedVersions []string
+		for _, vuln := range pkg.Vulnerabilities {
+			if slices.Contains(group.IDs, vuln.ID) {
+				fixedVersions = append(fixedVersions, vuln.FixedVersions(pkg.Package)...)
+			}
+		}
+
+		nearest[i] = nearestFix(pkg.Package, fixedVersions)
+		if nearest[i] == "" {
+			impact.Unfixed = append(impact.Unfixed, group.IDs[0])
+
+			continue
+		}
+
+		if fixedParsed == nil || fixedParsed.CompareStr(nearest[i]) < 0 {
+			parsed, err := semantic.Parse(nearest[i], ecosystem)
+			if err != nil {
+				continue
+			}
+			fixedParsed = parsed
+			impact.FixedVersion = nearest[i]
+		}
+	}
+
+	if impact.FixedVersion == "" {
+		return models.UpgradeImpact{}, false
+	}
+
+	for i, group := range pkg.Groups {
+		if nearest[i] != "" {
+			impact.Fixes = append(impact.Fixes, group.IDs[0])
+		}
+	}
+
+	impact.Bump = versionBump(pkg.Package.Version, impact.FixedVersion, pkg.Package.Ecosystem)
+	if impact.Bump == "" {
+		impact.Bump = "other"
+	}
+
+	return impact, true
+}
+
+// versionBump returns the most significant part of the version that changes
+// between the two versions, in either direction
+func versionBump(from string, to string, ecosystem string) string {
+	if bump, _, ok := howFarBehind(from, to, ecosystem); ok {
+		return bump
+	}
+	if bump, _, ok := howFarBehind(to, from, ecosystem); ok {
+		return bump
+	}
+
+	return ""
+}
+
+// highestVersions returns the highest version of each package of the graph
+// that the package itself depends on, keyed by the ecosystem and name
+func highestVersions(graph []*osv.Query, self models.PackageInfo) map[string]*osv.Query {
+	versions := map[string]*osv.Query{}
+
+	for _, query := range graph {
+		if query.Package.Name == self.Name && query.Package.Ecosystem == self.Ecosystem {
+			continue
+		}
+
+		key := query.Package.Ecosystem + "\x00" + query.Package.Name
+		existing, ok := versions[key]
+		if ok {
+			parsed, err := semantic.Parse(existing.Version, semantic.Ecosystem(query.Package.Ecosystem))
+			if err != nil || parsed.CompareStr(query.Version) >= 0 {
+				continue
+			}
+		}
+		versions[key] = query
+	}
+
+	return versions
+}
+
+// diffGraphs returns the packages whose versions change between the dependency
+// graphs of two versions of a package, comparing only the highest version of
+// each package where several versions of it are depended on
+func diffGraphs(before []*osv.Query, after []*osv.Query, self models.PackageInfo) []models.DependencyChange {
+	from, to := highestVersions(before, self), highestVersions(after, self)
+
+	var changes []models.DependencyChange
+	for key, query := range to {
+		change := models.DependencyChange{
+			Name:      query.Package.Name,
+			Ecosystem: query.Package.Ecosystem,
+			To:        query.Version,
+		}
+
+		if previous, ok := from[key]; ok {
+			if previous.Version == query.Version {
+				continue
+			}
+			change.From = previous.Version
+			change.Bump = versionBump(previous.Version, query.Version, query.Package.Ecosystem)
+		}
+
+		changes = append(changes, change)
+	}
+
+	for key, query := range from {
+		if _, ok := to[key]; !ok {
+			changes = append(changes, models.DependencyChange{
+				Name:      query.Package.Name,
+				Ecosystem: query.Package.Ecosystem,
+				From:      query.Version,
+			})
+		}
+	}
+
+	sort.Slice(changes, func(a, b int) bool {
+		if changes[a].Ecosystem != changes[b].Ecosystem {
+			return changes[a].Ecosystem < changes[b].Ecosystem
+		}
+
+		return changes[a].Name < changes[b].Name
+	})
+
+	return changes
+}
+
+// analyze works out the impact of upgrading the package to its suggested fix,
+// reporting false if it does not have one
+func (a *upgradeAnalyzer) analyze(source models.SourceInfo, pkg models.PackageVulns) (models.UpgradeImpact, bool) {
+	impact, ok := suggestFix(pkg)
+	if !ok {
+		return models.UpgradeImpact{}, false
+	}
+	impact.Source = source
+
+	before, err := a.dependencies(pkg.Package.Ecosystem, pkg.Package.Name, pkg.Package.Version)
+	if err == nil && before != nil {
+		var after []*osv.Query
+		after, err = a.dependencies(pkg.Package.Ecosystem, pkg.Package.Name, impact.FixedVersion)
+
+		if err == nil && after != nil {
+			impact.DependenciesKnown = true
+			impact.Changes = diffGraphs(before, after, pkg.Package)
+		}
+	}
+
+	return impact, true
+}
+
+// analyzeUpgrades works out what upgrading each vulnerable package of the
+// lockfiles, SBOMs and manifests to its suggested fix involves
+func (a *upgradeAnalyzer) analyzeUpgrades(results models.VulnerabilityResults) []models.UpgradeImpact {
+	type job struct {
+		source models.SourceInfo
+		pkg    models.PackageVulns
+	}
+
+	jobs := make(chan job)
+	var wg sync.WaitGroup
+
+	for i := 0; i < registryWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				if impact, ok := a.analyze(j.source, j.pkg); ok {
+					a.add(impact)
+				}
+			}
+		}()
+	}
+
+	for _, source := range results.Results {
+		switch source.Source.Type {
+		case "lockfile", "sbom", "manifest":
+		default:
+			continue
+		}
+
+		for _, pkg := range source.Packages {
+			if pkg.Package.Name == "" || len(pkg.Groups) == 0 {
+				continue
+			}
+			jobs <- job{source: source.Source, pkg: pkg}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return a.findings()
+}
+
+func sortUpgradeImpacts(upgrades []models.UpgradeImpact) {
+	sort.SliceStable(upgrades, func(a, b int) bool {
+		ua, ub := upgrades[a], upgrades[b]
+		if ua.Source.Path != ub.Source.Path {
+			return ua.Source.Path < ub.Source.Path
+		}
+		if ua.Package.Name != ub.Package.Name {
+			return ua.Package.Name < ub.Package.Name
+		}
+
+		return ua.Package.Version < ub.Package.Version
+	})
+}
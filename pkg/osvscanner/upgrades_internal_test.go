@@ -0,0 +1,206 @@
+package osvscanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+)
+
+func vulnerabilityFixedIn(t *testing.T, id string, pkg models.PackageInfo, fixed ...string) models.Vulnerability {
+	t.Helper()
+
+	events := []map[string]string{{"introduced": "0"}}
+	for _, version := range fixed {
+		events = append(events, map[string]string{"fixed": version})
+	}
+
+	data, err := json.Marshal(map[string]any{
+		"id": id,
+		"affected": []any{map[string]any{
+			"package": map[string]string{"name": pkg.Name, "ecosystem": pkg.Ecosystem},
+			"ranges":  []any{map[string]any{"type": "SEMVER", "events": events}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("could not marshal vulnerability: %v", err)
+	}
+
+	var vuln models.Vulnerability
+	if err := json.Unmarshal(data, &vuln); err != nil {
+		t.Fatalf("could not parse vulnerability: %v", err)
+	}
+
+	return vuln
+}
+
+func Test_suggestFix(t *testing.T) {
+	t.Parallel()
+
+	pkg := models.PackageInfo{Name: "minimist", Version: "1.2.0", Ecosystem: "npm"}
+
+	tests := []struct {
+		name   string
+		vulns  []models.Vulnerability
+		want   models.UpgradeImpact
+		wantOk bool
+	}{
+		{
+			name: "the nearest fix after the version",
+			vulns: []models.Vulnerability{
+				vulnerabilityFixedIn(t, "GHSA-1", pkg, "0.2.1", "1.2.3", "2.0.0"),
+			},
+			want:   models.UpgradeImpact{Package: pkg, FixedVersion: "1.2.3", Bump: "patch", Fixes: []string{"GHSA-1"}},
+			wantOk: true,
+		},
+		{
+			name: "the highest of the fixes needed for each vulnerability",
+			vulns: []models.Vulnerability{
+				vulnerabilityFixedIn(t, "GHSA-1", pkg, "1.2.3"),
+				vulnerabilityFixedIn(t, "GHSA-2", pkg, "1.5.0"),
+				vulnerabilityFixedIn(t, "GHSA-3", pkg),
+			},
+			want: models.UpgradeImpact{
+				Package:      pkg,
+				FixedVersion: "1.5.0",
+				Bump:         "minor",
+				Fixes:        []string{"GHSA-1", "GHSA-2"},
+				Unfixed:      []string{"GHSA-3"},
+			},
+			wantOk: true,
+		},
+		{
+			name: "no fixes",
+			vulns: []models.Vulnerability{
+				vulnerabilityFixedIn(t, "GHSA-3", pkg),
+			},
+			wantOk: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			vulns := models.PackageVulns{Package: pkg, Vulnerabilities: tt.vulns}
+			for _, vuln := range tt.vulns {
+				vulns.Groups = append(vulns.Groups, models.GroupInfo{IDs: []string{vuln.ID}})
+			}
+
+			got, ok := suggestFix(vulns)
+			if ok != tt.wantOk {
+				t.Fatalf("suggestFix() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("suggestFix() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_upgradeAnalyzer(t *testing.T) {
+	t.Parallel()
+
+	node := func(name string, version string) *osv.Query {
+		return &osv.Query{Version: version, Package: osv.Package{Name: name, Ecosystem: "npm"}}
+	}
+
+	graphs := map[string][]*osv.Query{
+		"express@4.17.0": {
+			node("express", "4.17.0"),
+			node("body-parser", "1.19.0"),
+			node("qs", "6.7.0"),
+			node("debug", "2.6.9"),
+			node("debug", "2.6.8"),
+			node("cookie", "0.4.0"),
+		},
+		"express@5.0.0": {
+			node("express", "5.0.0"),
+			node("body-parser", "2.0.0"),
+			node("qs", "6.7.0"),
+			node("debug", "2.6.9"),
+			node("router", "2.0.0"),
+		},
+	}
+
+	analyzer := newUpgradeAnalyzer(context.Background(), func(_ context.Context, ecosystem string, name string, version string) ([]*osv.Query, error) {
+		if name == "broken" {
+			return nil, errors.New("deps.dev is unavailable")
+		}
+
+		return graphs[name+"@"+version], nil
+	})
+
+	lockfile := models.SourceInfo{Path: "/app/package-lock.json", Type: "lockfile"}
+	express := models.PackageInfo{Name: "express", Version: "4.17.0", Ecosystem: "npm"}
+	broken := models.PackageInfo{Name: "broken", Version: "1.0.0", Ecosystem: "npm"}
+
+	results := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: lockfile,
+				Packages: []models.PackageVulns{
+					{
+						Package:         express,
+						Vulnerabilities: []models.Vulnerability{vulnerabilityFixedIn(t, "GHSA-1", express, "5.0.0")},
+						Groups:          []models.GroupInfo{{IDs: []string{"GHSA-1"}}},
+					},
+					{
+						Package:         broken,
+						Vulnerabilities: []models.Vulnerability{vulnerabilityFixedIn(t, "GHSA-2", broken, "1.0.1")},
+						Groups:          []models.GroupInfo{{IDs: []string{"GHSA-2"}}},
+					},
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "/app", Type: "git"},
+				Packages: []models.PackageVulns{{
+					Package:         models.PackageInfo{Version: "abc123", Ecosystem: "GIT"},
+					Vulnerabilities: []models.Vulnerability{{ID: "OSV-1"}},
+					Groups:          []models.GroupInfo{{IDs: []string{"OSV-1"}}},
+				}},
+			},
+		},
+	}
+
+	want := []models.UpgradeImpact{
+		{
+			Source:       lockfile,
+			Package:      broken,
+			FixedVersion: "1.0.1",
+			Bump:         "patch",
+			Fixes:        []string{"GHSA-2"},
+		},
+		{
+			Source:            lockfile,
+			Package:           express,
+			FixedVersion:      "5.0.0",
+			Bump:              "major",
+			Fixes:             []string{"GHSA-1"},
+			DependenciesKnown: true,
+			Changes: []models.DependencyChange{
+				{Name: "body-parser", Ecosystem: "npm", From: "1.19.0", To: "2.0.0", Bump: "major"},
+				{Name: "cookie", Ecosystem: "npm", From: "0.4.0"},
+				{Name: "router", Ecosystem: "npm", To: "2.0.0"},
+			},
+		},
+	}
+
+	got := analyzer.analyzeUpgrades(results)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("analyzeUpgrades() mismatch (-want +got):\n%s", diff)
+	}
+
+	if analyzer.failed != 1 {
+		t.Errorf("expected the dependencies of 1 package to fail to resolve, got %d", analyzer.failed)
+	}
+
+	if !got[1].Breaking() || got[0].Breaking() {
+		t.Errorf("expected only the major upgrade to be breaking")
+	}
+}
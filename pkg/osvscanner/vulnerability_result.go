@@ -2,6 +2,9 @@ package osvscanner
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/google/osv-scanner/pkg/grouper"
 	"github.com/google/osv-scanner/pkg/models"
@@ -10,8 +13,8 @@ import (
 )
 
 // groupResponseBySource converts raw OSV API response into structured vulnerability information
-// grouped by source location.
-func groupResponseBySource(r *output.Reporter, query osv.BatchedQuery, resp *osv.HydratedBatchedResponse) models.VulnerabilityResults {
+// grouped by source location, fingerprinting findings relative to the scanned root they are in.
+func groupResponseBySource(r output.Reporter, query osv.BatchedQuery, resp *osv.HydratedBatchedResponse, scanRoots []string) models.VulnerabilityResults {
 	output := models.VulnerabilityResults{
 		Results: []models.PackageSource{},
 	}
@@ -24,6 +27,7 @@ func groupResponseBySource(r *output.Reporter, query osv.BatchedQuery, resp *osv
 		}
 		var pkg models.PackageVulns
 		if query.Commit != "" {
+			pkg.Package.Name = query.PackageName
 			pkg.Package.Version = query.Commit
 			pkg.Package.Ecosystem = "GIT"
 		} else if query.Package.PURL != "" {
@@ -46,8 +50,20 @@ func groupResponseBySource(r *output.Reporter, query osv.BatchedQuery, resp *osv
 		}
 
 		pkg.Vulnerabilities = response.Vulns
+		pkg.Location = query.Location
+		pkg.Metadata = query.Metadata
+		pkg.Component = query.Component
 
 		pkg.Groups = grouper.Group(grouper.ConvertVulnerabilityToIDAliases(pkg.Vulnerabilities))
+		fingerprintPath := relativeToScanRoot(scanRoots, query.Source.Path)
+		for j := range pkg.Groups {
+			pkg.Groups[j].Fingerprint = models.Fingerprint(fingerprintPath, pkg.Package, pkg.Groups[j].IDs)
+			pkg.Groups[j].Modified, pkg.Groups[j].Withdrawn = advisoryStatus(pkg, pkg.Groups[j])
+			vulns := groupVulnerabilities(pkg, pkg.Groups[j])
+			pkg.Groups[j].CWEs = models.AllCWEs(vulns)
+			pkg.Groups[j].Exploits = models.AllExploitReferences(vulns)
+			pkg.Groups[j].AffectedSymbols = models.AllAffectedSymbols(pkg.Package, vulns)
+		}
 		groupedBySource[query.Source] = append(groupedBySource[query.Source], pkg)
 	}
 
@@ -60,3 +76,82 @@ func groupResponseBySource(r *output.Reporter, query osv.BatchedQuery, resp *osv
 
 	return output
 }
+
+// relativeToWorkingDir returns the given path relative to the current working
+// directory if possible, so that it does not depend on where the project is
+func relativeToWorkingDir(path string) string {
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+
+	rel, err := filepath.Rel(workingDir, path)
+	if err != nil {
+		return path
+	}
+
+	return rel
+}
+
+// relativeToScanRoot returns the given path relative to the deepest of the
+// scanned directories that it is within, being the clone for remote
+// repositories, so that it does not depend on where the project is checked
+// out. Paths that are not within any of them, such as lockfiles that were
+// scanned on their own, are relative to the working directory instead.
+func relativeToScanRoot(scanRoots []string, path string) string {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return relativeToWorkingDir(path)
+	}
+
+	best := ""
+	for _, root := range scanRoots {
+		absRoot, err := filepath.Abs(root)
+		if err != nil {
+			continue
+		}
+
+		rel, err := filepath.Rel(absRoot, absPath)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+
+		if best == "" || len(absRoot) > len(best) {
+			best = absRoot
+		}
+	}
+
+	if best == "" {
+		return relativeToWorkingDir(path)
+	}
+
+	rel, _ := filepath.Rel(best, absPath)
+
+	return rel
+}
+
+// markIncompleteSources flags the sources of the given failed queries as
+// incomplete, adding them to the results if they have no vulnerabilities
+func markIncompleteSources(results *models.VulnerabilityResults, query osv.BatchedQuery, failedQueries []int) {
+	for _, i := range failedQueries {
+		source := query.Queries[i].Source
+		found := false
+
+		for j := range results.Results {
+			if results.Results[j].Source == source {
+				results.Results[j].Incomplete = true
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			results.Results = append(results.Results, models.PackageSource{
+				Source:     source,
+				Packages:   []models.PackageVulns{},
+				Incomplete: true,
+			})
+		}
+	}
+}
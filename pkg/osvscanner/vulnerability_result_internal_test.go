@@ -1,6 +1,7 @@
 package osvscanner
 
 import (
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -12,7 +13,7 @@ import (
 func Test_groupResponseBySource(t *testing.T) {
 	t.Parallel()
 	type args struct {
-		r     *output.Reporter
+		r     output.Reporter
 		query osv.BatchedQuery
 		resp  *osv.HydratedBatchedResponse
 	}
@@ -25,9 +26,82 @@ func Test_groupResponseBySource(t *testing.T) {
 		tt := tt // Reinitialize for t.Parallel()
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			if got := groupResponseBySource(tt.args.r, tt.args.query, tt.args.resp); !reflect.DeepEqual(got, tt.want) {
+			if got := groupResponseBySource(tt.args.r, tt.args.query, tt.args.resp, nil); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("groupResponse() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func Test_markIncompleteSources(t *testing.T) {
+	t.Parallel()
+
+	sourceA := models.SourceInfo{Path: "/path/to/a/package-lock.json", Type: "lockfile"}
+	sourceB := models.SourceInfo{Path: "/path/to/b/Cargo.lock", Type: "lockfile"}
+
+	query := osv.BatchedQuery{Queries: []*osv.Query{
+		{Source: sourceA},
+		{Source: sourceA},
+		{Source: sourceB},
+	}}
+
+	results := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: sourceA,
+				Packages: []models.PackageVulns{
+					{Package: models.PackageInfo{Name: "a", Version: "1.0.0", Ecosystem: "npm"}},
+				},
+			},
+		},
+	}
+
+	markIncompleteSources(&results, query, []int{1, 2})
+
+	want := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: sourceA,
+				Packages: []models.PackageVulns{
+					{Package: models.PackageInfo{Name: "a", Version: "1.0.0", Ecosystem: "npm"}},
+				},
+				Incomplete: true,
+			},
+			{
+				Source:     sourceB,
+				Packages:   []models.PackageVulns{},
+				Incomplete: true,
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("markIncompleteSources() = %v, want %v", results, want)
+	}
+}
+
+func Test_relativeToScanRoot(t *testing.T) {
+	t.Parallel()
+
+	roots := []string{filepath.FromSlash("/tmp/clone"), filepath.FromSlash("/tmp/clone/services"), filepath.FromSlash("/work")}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/tmp/clone/package-lock.json", want: "package-lock.json"},
+		{path: "/tmp/clone/services/api/go.mod", want: "api/go.mod"},
+		{path: "/work/web/yarn.lock", want: "web/yarn.lock"},
+		{path: "/workspace/yarn.lock", want: relativeToWorkingDir(filepath.FromSlash("/workspace/yarn.lock"))},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			if got := relativeToScanRoot(roots, filepath.FromSlash(tt.path)); got != filepath.FromSlash(tt.want) {
+				t.Errorf("relativeToScanRoot() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
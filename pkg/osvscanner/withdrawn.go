@@ -0,0 +1,47 @@
+package osvscanner
+
+import (
+	"time"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+)
+
+// dropWithdrawn removes the advisories that have been withdrawn from the
+// response, as they are no longer thought to affect the packages, returning
+// how many were removed
+func dropWithdrawn(resp *osv.HydratedBatchedResponse) int {
+	dropped := 0
+	for i, result := range resp.Results {
+		var kept []models.Vulnerability
+		for _, vuln := range result.Vulns {
+			if vuln.Withdrawn != nil {
+				dropped++
+				continue
+			}
+			kept = append(kept, vuln)
+		}
+		resp.Results[i].Vulns = kept
+	}
+
+	return dropped
+}
+
+// advisoryStatus returns when the most recently modified advisory of the
+// group was modified, if any of them say, and whether all of them have been
+// withdrawn
+func advisoryStatus(pkg models.PackageVulns, group models.GroupInfo) (*time.Time, bool) {
+	var modified *time.Time
+	withdrawn := true
+	vulns := groupVulnerabilities(pkg, group)
+	for i, vuln := range vulns {
+		if !vuln.Modified.IsZero() && (modified == nil || vuln.Modified.After(*modified)) {
+			modified = &vulns[i].Modified
+		}
+		if vuln.Withdrawn == nil {
+			withdrawn = false
+		}
+	}
+
+	return modified, withdrawn && len(vulns) > 0
+}
@@ -0,0 +1,85 @@
+package osvscanner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+func TestDropWithdrawn(t *testing.T) {
+	t.Parallel()
+
+	withdrawn := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	resp := &osv.HydratedBatchedResponse{Results: []osv.Response{
+		{Vulns: []models.Vulnerability{{ID: "GHSA-1"}, {ID: "GHSA-2", Withdrawn: &withdrawn}}},
+		{Vulns: []models.Vulnerability{{ID: "GHSA-3", Withdrawn: &withdrawn}}},
+		{},
+	}}
+
+	if n := dropWithdrawn(resp); n != 2 {
+		t.Errorf("expected 2 advisories to be dropped, got %d", n)
+	}
+
+	want := &osv.HydratedBatchedResponse{Results: []osv.Response{
+		{Vulns: []models.Vulnerability{{ID: "GHSA-1"}}},
+		{},
+		{},
+	}}
+	if diff := cmp.Diff(want, resp); diff != "" {
+		t.Errorf("dropWithdrawn() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGroupResponseBySource_AdvisoryStatus(t *testing.T) {
+	t.Parallel()
+
+	jan := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)
+	query := osv.BatchedQuery{Queries: []*osv.Query{{
+		Package: osv.Package{Name: "minimist", Ecosystem: "npm"},
+		Version: "0.0.8",
+		Source:  models.SourceInfo{Path: "/app/package-lock.json", Type: "lockfile"},
+	}}}
+	resp := &osv.HydratedBatchedResponse{Results: []osv.Response{{Vulns: []models.Vulnerability{
+		// a withdrawn alias of an advisory that still stands
+		{ID: "GHSA-1", Aliases: []string{"CVE-1"}, Modified: jan, Withdrawn: &feb},
+		{ID: "CVE-1", Modified: feb},
+		{ID: "GHSA-2", Modified: jan, Withdrawn: &feb},
+		{ID: "GHSA-3"},
+	}}}}
+
+	results := groupResponseBySource(output.NewVoidReporter(), query, resp, nil)
+
+	type status struct {
+		IDs       []string
+		Modified  *time.Time
+		Withdrawn bool
+	}
+	var got []status
+	for _, group := range results.Results[0].Packages[0].Groups {
+		got = append(got, status{IDs: group.IDs, Modified: group.Modified, Withdrawn: group.Withdrawn})
+	}
+
+	want := []status{
+		{IDs: []string{"GHSA-1", "CVE-1"}, Modified: &feb},
+		{IDs: []string{"GHSA-2"}, Modified: &jan, Withdrawn: true},
+		{IDs: []string{"GHSA-3"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("groups mismatch (-want +got):\n%s", diff)
+	}
+
+	if newFailPolicy(ScannerActions{}).fails(models.VulnerabilityResults{Results: []models.PackageSource{{
+		Packages: []models.PackageVulns{{
+			Package:         results.Results[0].Packages[0].Package,
+			Vulnerabilities: results.Results[0].Packages[0].Vulnerabilities,
+			Groups:          results.Results[0].Packages[0].Groups[1:2],
+		}},
+	}}}) {
+		t.Errorf("expected a group of withdrawn advisories to not fail the scan")
+	}
+}
@@ -0,0 +1,94 @@
+package osvscanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/output"
+	"github.com/google/osv-scanner/pkg/workspaces"
+)
+
+// workspacesAbove returns the workspaces rooted at the given directory or any
+// of its parents, stopping at the root of the git repository it is in
+func workspacesAbove(r output.Reporter, cache map[string][]workspaces.Workspace, dir string) []workspaces.Workspace {
+	var found []workspaces.Workspace
+
+	for {
+		detected, ok := cache[dir]
+		if !ok {
+			var err error
+			detected, err = workspaces.Detect(dir)
+			if err != nil {
+				r.PrintText(fmt.Sprintf("Failed to detect workspaces in %s: %v\n", dir, err))
+			}
+			cache[dir] = detected
+		}
+
+		found = append(found, detected...)
+
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			break
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return found
+}
+
+func toWorkspaceMember(workspace workspaces.Workspace, member workspaces.Member) models.WorkspaceMember {
+	return models.WorkspaceMember{
+		Workspace: workspace.Tool,
+		Name:      member.Name,
+		Path:      workspace.RelativePath(member),
+		Owners:    member.Owners,
+	}
+}
+
+// attributeWorkspaceMembers attributes the packages of each lockfile to the
+// members of any workspaces the lockfile is part of: lockfiles within a
+// member belong to that member, while packages from a lockfile shared by the
+// whole workspace belong to the members that directly depend on them
+func attributeWorkspaceMembers(r output.Reporter, results *models.VulnerabilityResults) {
+	cache := map[string][]workspaces.Workspace{}
+
+	for i := range results.Results {
+		source := &results.Results[i]
+		if source.Source.Type != "lockfile" {
+			continue
+		}
+
+		sourceDir, err := filepath.Abs(filepath.Dir(source.Source.Path))
+		if err != nil {
+			continue
+		}
+
+		for _, workspace := range workspacesAbove(r, cache, sourceDir) {
+			owner, isOwned := workspace.MemberOwning(sourceDir)
+
+			for j := range source.Packages {
+				pkg := &source.Packages[j]
+				// packages that were checked by commit are attributed by their
+				// name, as their ecosystem is replaced by that of git
+				if pkg.Package.Ecosystem != workspace.Ecosystem && pkg.Package.Ecosystem != "GIT" {
+					continue
+				}
+
+				if isOwned {
+					pkg.WorkspaceMembers = append(pkg.WorkspaceMembers, toWorkspaceMember(workspace, owner))
+					continue
+				}
+
+				for _, member := range workspace.MembersDependingOn(pkg.Package.Name) {
+					pkg.WorkspaceMembers = append(pkg.WorkspaceMembers, toWorkspaceMember(workspace, member))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package osvscanner
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+func Test_attributeWorkspaceMembers(t *testing.T) {
+	t.Parallel()
+
+	root, err := filepath.Abs("../../fixtures/workspaces")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	results := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: filepath.Join(root, "npm/package-lock.json"), Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{Package: models.PackageInfo{Name: "express", Version: "4.17.0", Ecosystem: "npm"}},
+					{Package: models.PackageInfo{Name: "left-pad", Version: "1.0.0", Ecosystem: "npm"}},
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: filepath.Join(root, "go/svc/go.mod"), Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{Package: models.PackageInfo{Name: "golang.org/x/text", Version: "0.3.0", Ecosystem: "Go"}},
+				},
+			},
+		},
+	}
+
+	attributeWorkspaceMembers(output.NewVoidReporter(), &results)
+
+	want := [][]models.WorkspaceMember{
+		{
+			{Workspace: "npm", Name: "@acme/api", Path: "packages/api", Owners: []string{"@acme/backend"}},
+			{Workspace: "npm", Name: "@acme/web", Path: "packages/web", Owners: []string{"@acme/frontend", "@alice"}},
+		},
+		nil,
+		{
+			{Workspace: "go", Name: "example.com/svc", Path: "svc"},
+		},
+	}
+
+	got := [][]models.WorkspaceMember{
+		results.Results[0].Packages[0].WorkspaceMembers,
+		results.Results[0].Packages[1].WorkspaceMembers,
+		results.Results[1].Packages[0].WorkspaceMembers,
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("workspace members mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_attributeWorkspaceMembers_CargoLock(t *testing.T) {
+	t.Parallel()
+
+	root, err := filepath.Abs("../../fixtures/workspaces/cargo-lock")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	results := models.VulnerabilityResults{
+		Results: []models.PackageSource{{
+			Source: models.SourceInfo{Path: filepath.Join(root, "Cargo.lock"), Type: "lockfile"},
+			Packages: []models.PackageVulns{
+				{Package: models.PackageInfo{Name: "serde_derive", Version: "1.0.130", Ecosystem: "crates.io"}},
+				{Package: models.PackageInfo{Name: "regex", Version: "9f9f693768c584971a4d53bc3c586c33ed3a6831", Ecosystem: "GIT"}},
+			},
+		}},
+	}
+
+	attributeWorkspaceMembers(output.NewVoidReporter(), &results)
+
+	want := [][]models.WorkspaceMember{
+		{
+			{Workspace: "cargo", Name: "acme-app", Path: "crates/app"},
+			{Workspace: "cargo", Name: "acme-lib", Path: "crates/lib"},
+		},
+		{
+			{Workspace: "cargo", Name: "acme-app", Path: "crates/app"},
+		},
+	}
+
+	got := [][]models.WorkspaceMember{
+		results.Results[0].Packages[0].WorkspaceMembers,
+		results.Results[0].Packages[1].WorkspaceMembers,
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("workspace members mismatch (-want +got):\n%s", diff)
+	}
+}
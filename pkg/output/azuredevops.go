@@ -0,0 +1,62 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// azureDevOpsPropertyEscaper escapes values of logging command properties, per
+// https://github.com/microsoft/azure-pipelines-agent/blob/master/src/Agent.Worker/Command.cs
+var azureDevOpsPropertyEscaper = strings.NewReplacer(
+	"%", "%AZP25",
+	";", "%3B",
+	"\r", "%0D",
+	"\n", "%0A",
+	"]", "%5D",
+)
+
+// azureDevOpsMessageEscaper escapes the message of logging commands
+var azureDevOpsMessageEscaper = strings.NewReplacer(
+	"%", "%AZP25",
+	"\r", "%0D",
+	"\n", "%0A",
+)
+
+// PrintAzureDevOpsResults writes each vulnerability as an Azure Pipelines
+// logging command, so that they are surfaced as issues on the pipeline run
+func PrintAzureDevOpsResults(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) {
+	workingDir, workingDirErr := os.Getwd()
+
+	for _, sourceRes := range vulnResult.Results {
+		sourcePath := sourceRes.Source.Path
+		if workingDirErr == nil {
+			if rel, err := filepath.Rel(workingDir, sourcePath); err == nil {
+				sourcePath = rel
+			}
+		}
+
+		for _, pkg := range sourceRes.Packages {
+			for _, group := range pkg.Groups {
+				msg := fmt.Sprintf(
+					"%s@%s (%s) is affected by %s",
+					pkg.Package.Name,
+					pkg.Package.Version,
+					pkg.Package.Ecosystem,
+					strings.Join(group.IDs, ", "),
+				)
+
+				properties := "type=error"
+				if sourceRes.Source.Type == "lockfile" || sourceRes.Source.Type == "sbom" {
+					properties += ";sourcepath=" + azureDevOpsPropertyEscaper.Replace(sourcePath)
+				}
+
+				fmt.Fprintf(outputWriter, "##vso[task.logissue %s]%s\n", properties, azureDevOpsMessageEscaper.Replace(msg))
+			}
+		}
+	}
+}
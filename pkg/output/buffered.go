@@ -0,0 +1,47 @@
+package output
+
+import (
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// BufferedReporter holds what is reported to it until it is replayed onto the
+// reporter it was created for, so that work done concurrently can be reported
+// in a consistent order
+type BufferedReporter struct {
+	parent  Reporter
+	pending []func(r Reporter)
+}
+
+// NewBufferedReporter returns a reporter that holds what is reported to it
+// until it is replayed, translating messages as the given reporter does
+func NewBufferedReporter(parent Reporter) *BufferedReporter {
+	return &BufferedReporter{parent: parent}
+}
+
+func (b *BufferedReporter) PrintText(msg string) {
+	b.pending = append(b.pending, func(r Reporter) { r.PrintText(msg) })
+}
+
+func (b *BufferedReporter) PrintError(msg string) {
+	b.pending = append(b.pending, func(r Reporter) { r.PrintError(msg) })
+}
+
+// PrintResult holds the results until they are replayed, so it never fails
+func (b *BufferedReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	b.pending = append(b.pending, func(r Reporter) { _ = r.PrintResult(vulnResult) })
+
+	return nil
+}
+
+func (b *BufferedReporter) Localize(format string, args ...any) string {
+	return Localize(b.parent, format, args...)
+}
+
+// Replay reports what has been held so far onto r, in the order it was
+// reported
+func (b *BufferedReporter) Replay(r Reporter) {
+	for _, print := range b.pending {
+		print(r)
+	}
+	b.pending = nil
+}
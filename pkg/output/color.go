@@ -0,0 +1,149 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/models"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+)
+
+// ColorMode controls when table output is colored
+type ColorMode string
+
+const (
+	// ColorAuto colors output written to a terminal, unless the NO_COLOR
+	// environment variable is set, or FORCE_COLOR is set to force it on
+	ColorAuto ColorMode = "auto"
+	// ColorAlways colors output regardless of where it is written
+	ColorAlways ColorMode = "always"
+	// ColorNever never colors output
+	ColorNever ColorMode = "never"
+)
+
+// ParseColorMode parses one of "auto", "always" or "never"
+func ParseColorMode(mode string) (ColorMode, error) {
+	switch ColorMode(mode) {
+	case ColorAuto, ColorAlways, ColorNever:
+		return ColorMode(mode), nil
+	}
+
+	return ColorAuto, fmt.Errorf("unsupported color mode %q, expected one of auto, always, never", mode)
+}
+
+// Theme is the set of colors used when table output is colored
+type Theme struct {
+	// Row and RowAlternate are the colors of every other row of the table
+	Row          text.Colors
+	RowAlternate text.Colors
+	// Severities are the colors of vulnerability IDs with each of the
+	// severities in models.Severities, with IDs of unknown severity only
+	// being made bold
+	Severities map[string]text.Colors
+}
+
+// DefaultThemeName is the theme used unless another is selected
+const DefaultThemeName = "default"
+
+// Themes are the built-in themes that can be selected by name
+var Themes = map[string]Theme{
+	// default suits terminals with a dark background
+	"default": {
+		Row:          text.Colors{text.Reset, text.BgHiBlack},
+		RowAlternate: text.Colors{text.Reset, text.BgBlack},
+		Severities: map[string]text.Colors{
+			"CRITICAL": {text.FgHiRed},
+			"HIGH":     {text.FgRed},
+			"MEDIUM":   {text.FgYellow},
+			"LOW":      {text.FgCyan},
+		},
+	},
+	// light suits terminals with a light background
+	"light": {
+		Row:          text.Colors{text.Reset, text.BgHiWhite},
+		RowAlternate: text.Colors{text.Reset, text.BgWhite},
+		Severities: map[string]text.Colors{
+			"CRITICAL": {text.FgRed},
+			"HIGH":     {text.FgMagenta},
+			"MEDIUM":   {text.FgBlue},
+			"LOW":      {text.FgGreen},
+		},
+	},
+	// high-contrast leaves rows uncolored and highlights severities with a
+	// background color, which stays readable in most CI logs
+	"high-contrast": {
+		Severities: map[string]text.Colors{
+			"CRITICAL": {text.BgRed, text.FgHiWhite},
+			"HIGH":     {text.BgHiRed, text.FgBlack},
+			"MEDIUM":   {text.BgYellow, text.FgBlack},
+			"LOW":      {text.BgCyan, text.FgBlack},
+		},
+	},
+}
+
+// ParseTheme returns the built-in theme with the given name
+func ParseTheme(name string) (Theme, error) {
+	if theme, ok := Themes[name]; ok {
+		return theme, nil
+	}
+
+	names := make([]string, 0, len(Themes))
+	for n := range Themes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	return Theme{}, fmt.Errorf("unsupported theme %q, expected one of %s", name, strings.Join(names, ", "))
+}
+
+// colorID formats a vulnerability ID in bold, in the color of the given severity
+func (t Theme) colorID(id string, severity string) string {
+	colors := append(text.Colors{text.Bold}, t.Severities[severity]...)
+
+	return colors.Sprint(id)
+}
+
+// shouldColor reports if output should be colored, following the conventions
+// of https://no-color.org and FORCE_COLOR when the mode is ColorAuto
+func shouldColor(mode ColorMode, isTerminal bool) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	case ColorAuto:
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	if force := os.Getenv("FORCE_COLOR"); force != "" && force != "0" && force != "false" {
+		return true
+	}
+
+	return isTerminal
+}
+
+// groupSeverity returns the effective severity of a group if it has been
+// scored, and otherwise the highest severity of its vulnerabilities
+func groupSeverity(pkg models.PackageVulns, group models.GroupInfo) string {
+	if group.Severity != "" {
+		return group.Severity
+	}
+
+	var vulns []models.Vulnerability
+	for _, vuln := range pkg.Vulnerabilities {
+		for _, id := range group.IDs {
+			if vuln.ID == id {
+				vulns = append(vulns, vuln)
+				break
+			}
+		}
+	}
+
+	return models.HighestSeverity(vulns)
+}
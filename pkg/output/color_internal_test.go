@@ -0,0 +1,149 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+//nolint:paralleltest // sets environment variables
+func TestShouldColor(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       ColorMode
+		noColor    string
+		forceColor string
+		isTerminal bool
+		want       bool
+	}{
+		{name: "auto in a terminal", mode: ColorAuto, isTerminal: true, want: true},
+		{name: "auto when piped", mode: ColorAuto, isTerminal: false, want: false},
+		{name: "auto with NO_COLOR", mode: ColorAuto, noColor: "1", isTerminal: true, want: false},
+		{name: "auto with FORCE_COLOR", mode: ColorAuto, forceColor: "1", isTerminal: false, want: true},
+		{name: "auto with FORCE_COLOR=0", mode: ColorAuto, forceColor: "0", isTerminal: false, want: false},
+		{name: "NO_COLOR wins over FORCE_COLOR", mode: ColorAuto, noColor: "1", forceColor: "1", want: false},
+		{name: "always with NO_COLOR", mode: ColorAlways, noColor: "1", want: true},
+		{name: "never with FORCE_COLOR", mode: ColorNever, forceColor: "1", isTerminal: true, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			t.Setenv("FORCE_COLOR", tt.forceColor)
+
+			if got := shouldColor(tt.mode, tt.isTerminal); got != tt.want {
+				t.Errorf("shouldColor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func vulnerableResults() *models.VulnerabilityResults {
+	critical := models.Vulnerability{ID: "GHSA-aaaa", DatabaseSpecific: map[string]interface{}{"severity": "CRITICAL"}}
+	unknown := models.Vulnerability{ID: "GHSA-bbbb"}
+
+	return &models.VulnerabilityResults{
+		Results: []models.PackageSource{{
+			Source: models.SourceInfo{Path: "package-lock.json", Type: "lockfile"},
+			Packages: []models.PackageVulns{{
+				Package:         models.PackageInfo{Name: "minimist", Version: "0.0.8", Ecosystem: "npm"},
+				Vulnerabilities: []models.Vulnerability{critical, unknown},
+				Groups:          []models.GroupInfo{{IDs: []string{"GHSA-aaaa"}}, {IDs: []string{"GHSA-bbbb"}}},
+			}},
+		}},
+	}
+}
+
+func TestPrintTableResults_Colors(t *testing.T) {
+	t.Parallel()
+
+	theme := Themes[DefaultThemeName]
+
+	var colored strings.Builder
+	printTableResults(vulnerableResults(), &colored, DefaultLocale, ColorAlways, theme)
+
+	if want := theme.colorID("GHSA-aaaa", "CRITICAL"); !strings.Contains(colored.String(), want) {
+		t.Errorf("expected critical vulnerability to be colored as %q, got:\n%s", want, colored.String())
+	}
+	if want := theme.colorID("GHSA-bbbb", ""); !strings.Contains(colored.String(), want) {
+		t.Errorf("expected unknown severity vulnerability to be bold as %q, got:\n%s", want, colored.String())
+	}
+
+	var plain strings.Builder
+	printTableResults(vulnerableResults(), &plain, DefaultLocale, ColorNever, theme)
+
+	if strings.Contains(plain.String(), "\x1b[") {
+		t.Errorf("expected no escape sequences, got:\n%s", plain.String())
+	}
+	if !strings.Contains(plain.String(), "https://osv.dev/GHSA-aaaa") {
+		t.Errorf("expected plain output to include the vulnerability, got:\n%s", plain.String())
+	}
+}
+
+func TestParseTheme(t *testing.T) {
+	t.Parallel()
+
+	for name := range Themes {
+		if _, err := ParseTheme(name); err != nil {
+			t.Errorf("unexpected error parsing theme %s: %v", name, err)
+		}
+		for _, severity := range models.Severities {
+			if len(Themes[name].Severities[severity]) == 0 {
+				t.Errorf("theme %s has no color for %s vulnerabilities", name, severity)
+			}
+		}
+	}
+
+	if _, err := ParseTheme("neon"); err == nil {
+		t.Errorf("expected an error parsing an unknown theme")
+	}
+}
+
+func TestPrintTableResults_Withdrawn(t *testing.T) {
+	t.Parallel()
+
+	results := vulnerableResults()
+	results.Results[0].Packages[0].Groups[1].Withdrawn = true
+
+	var out strings.Builder
+	printTableResults(results, &out, DefaultLocale, ColorNever, Themes[DefaultThemeName])
+
+	if strings.Count(out.String(), "(withdrawn)") != 1 {
+		t.Errorf("expected the withdrawn group to be marked, got:\n%s", out.String())
+	}
+}
+
+func TestPrintTableResults_AffectedSymbols(t *testing.T) {
+	t.Parallel()
+
+	results := vulnerableResults()
+	results.Results[0].Packages[0].Groups[0].AffectedSymbols = []string{"minimist.setKey"}
+	results.Results[0].Packages[0].Groups[1].AffectedSymbols = []string{"a", "b", "c", "d", "e"}
+
+	var out strings.Builder
+	printTableResults(results, &out, DefaultLocale, ColorNever, Themes[DefaultThemeName])
+
+	for _, want := range []string{"affects: minimist.setKey", "affects: a, b, c and 2 more"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected the table to include %q, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestPrintTableResults_SymbolUsages(t *testing.T) {
+	t.Parallel()
+
+	results := vulnerableResults()
+	results.Results[0].Packages[0].Groups[0].SymbolUsages = []models.SymbolUsage{
+		{Symbol: "minimist.setKey", Path: "index.js", Line: 12, Column: 3},
+		{Symbol: "minimist.setKey", Path: "lib/args.js", Line: 4, Column: 1},
+	}
+
+	var out strings.Builder
+	printTableResults(results, &out, DefaultLocale, ColorNever, Themes[DefaultThemeName])
+
+	if !strings.Contains(out.String(), "used at index.js:12 and 1 more") {
+		t.Errorf("expected the table to include where the symbols are used, got:\n%s", out.String())
+	}
+}
@@ -0,0 +1,52 @@
+package output
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/google/osv-scanner/pkg/models"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// deprecatedPackagesTableBuilder adds a row for each package that should no
+// longer be used according to its registry
+func deprecatedPackagesTableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults) table.Writer {
+	workingDir, workingDirErr := os.Getwd()
+	for _, pkg := range vulnResult.Deprecated {
+		sourcePath := pkg.Source.Path
+		if workingDirErr == nil {
+			if rel, err := filepath.Rel(workingDir, sourcePath); err == nil {
+				sourcePath = rel
+			}
+		}
+
+		outputTable.AppendRow(table.Row{
+			pkg.Package.Ecosystem,
+			pkg.Package.Name,
+			pkg.Package.Version,
+			pkg.Kind,
+			pkg.Message,
+			sourcePath,
+		})
+	}
+
+	return outputTable
+}
+
+// printDeprecatedPackagesTable prints the packages that have been deprecated,
+// yanked or are unmaintained, if packages were checked and any were found
+func printDeprecatedPackagesTable(vulnResult *models.VulnerabilityResults, outputWriter io.Writer, locale Locale, style func(table.Writer)) {
+	outputTable := table.NewWriter()
+	outputTable.SetOutputMirror(outputWriter)
+	outputTable.AppendHeader(localizedRow(locale, "Ecosystem", "Package", "Version", "Deprecation", "Detail", "Source"))
+	style(outputTable)
+
+	outputTable = deprecatedPackagesTableBuilder(outputTable, vulnResult)
+
+	if outputTable.Length() == 0 {
+		return
+	}
+	outputTable.Render()
+}
@@ -0,0 +1,31 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestPrintTableResults_Deprecated(t *testing.T) {
+	t.Parallel()
+
+	results := &models.VulnerabilityResults{
+		Results: []models.PackageSource{},
+		Deprecated: []models.DeprecatedPackage{{
+			Source:  models.SourceInfo{Path: "package-lock.json", Type: "lockfile"},
+			Package: models.PackageInfo{Name: "request", Version: "2.88.2", Ecosystem: "npm"},
+			Kind:    "deprecated",
+			Message: "request has been deprecated",
+		}},
+	}
+
+	var out strings.Builder
+	printTableResults(results, &out, DefaultLocale, ColorNever, Themes[DefaultThemeName])
+
+	for _, want := range []string{"DEPRECATION", "request", "deprecated", "has been deprecated"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected the deprecated packages table to include %q, got:\n%s", want, out.String())
+		}
+	}
+}
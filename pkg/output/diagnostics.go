@@ -0,0 +1,137 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/models"
+
+	"golang.org/x/exp/slices"
+)
+
+// DiagnosticsVersion is the version of the diagnostics format, which is
+// incremented whenever a change is made that is not backwards compatible
+const DiagnosticsVersion = 1
+
+// Diagnostic describes a vulnerable dependency at the position it is declared,
+// in a form that editors can display inline. Lines and columns start from 1.
+type Diagnostic struct {
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndLine   int    `json:"endLine"`
+	EndColumn int    `json:"endColumn"`
+	// Severity is one of "error", "warning" or "information"
+	Severity string `json:"severity"`
+	Source   string `json:"source"`
+	// Code is the ID of the vulnerability, with IDs listing it and its aliases
+	Code          string             `json:"code"`
+	IDs           []string           `json:"ids"`
+	Message       string             `json:"message"`
+	Package       models.PackageInfo `json:"package"`
+	FixedVersions []string           `json:"fixedVersions,omitempty"`
+}
+
+type diagnosticsOutput struct {
+	Version     int          `json:"version"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// diagnosticSeverity maps the severity of a vulnerability onto the severities
+// that editors display
+func diagnosticSeverity(severity string) string {
+	switch severity {
+	case "CRITICAL", "HIGH":
+		return "error"
+	case "LOW":
+		return "information"
+	default:
+		return "warning"
+	}
+}
+
+// groupMessage describes the group of vulnerabilities of the package, with the
+// summary of the first of them that has one and the versions they are fixed in,
+// which are also returned
+func groupMessage(pkg models.PackageVulns, group models.GroupInfo) (string, []string) {
+	var summary string
+	var fixedVersions []string
+	for _, vuln := range pkg.Vulnerabilities {
+		if !slices.Contains(group.IDs, vuln.ID) {
+			continue
+		}
+		if summary == "" {
+			summary = vuln.Summary
+		}
+		for _, fixed := range vuln.FixedVersions(pkg.Package) {
+			if !slices.Contains(fixedVersions, fixed) {
+				fixedVersions = append(fixedVersions, fixed)
+			}
+		}
+	}
+
+	message := fmt.Sprintf("%s@%s is affected by %s", pkg.Package.Name, pkg.Package.Version, strings.Join(group.IDs, ", "))
+	if summary != "" {
+		message += ": " + summary
+	}
+	if len(fixedVersions) > 0 {
+		message += fmt.Sprintf(" (fixed in %s)", strings.Join(fixedVersions, ", "))
+	}
+
+	return message, fixedVersions
+}
+
+// Diagnostics returns a diagnostic for each group of vulnerabilities found in
+// a lockfile or SBOM, positioned at the declaration of the affected package or
+// at the start of the file if it could not be found
+func Diagnostics(vulnResult *models.VulnerabilityResults) []Diagnostic {
+	diagnostics := []Diagnostic{}
+
+	for _, sourceRes := range vulnResult.Results {
+		if sourceRes.Source.Type != "lockfile" && sourceRes.Source.Type != "sbom" {
+			continue
+		}
+
+		for _, pkg := range sourceRes.Packages {
+			location := models.SourceLocation{Line: 1, Column: 1, EndColumn: 1}
+			if pkg.Location != nil {
+				location = *pkg.Location
+			}
+
+			for _, group := range pkg.Groups {
+				diagnostic := Diagnostic{
+					File:      sourceRes.Source.Path,
+					Line:      location.Line,
+					Column:    location.Column,
+					EndLine:   location.Line,
+					EndColumn: location.EndColumn,
+					Severity:  diagnosticSeverity(groupSeverity(pkg, group)),
+					Source:    "osv-scanner",
+					Code:      group.IDs[0],
+					IDs:       group.IDs,
+					Package:   pkg.Package,
+				}
+
+				diagnostic.Message, diagnostic.FixedVersions = groupMessage(pkg, group)
+
+				diagnostics = append(diagnostics, diagnostic)
+			}
+		}
+	}
+
+	return diagnostics
+}
+
+// PrintDiagnosticsResults writes a diagnostic for each vulnerable dependency
+// as JSON, for editor extensions to show at the position it is declared
+func PrintDiagnosticsResults(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) error {
+	encoder := json.NewEncoder(outputWriter)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(diagnosticsOutput{
+		Version:     DiagnosticsVersion,
+		Diagnostics: Diagnostics(vulnResult),
+	})
+}
@@ -0,0 +1,95 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	var vuln models.Vulnerability
+	err := json.Unmarshal([]byte(`{
+		"id": "GHSA-vh95-rmgr-6w4m",
+		"aliases": ["CVE-2020-7598"],
+		"summary": "Prototype Pollution in minimist",
+		"affected": [{
+			"package": {"ecosystem": "npm", "name": "minimist"},
+			"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "0.2.1"}, {"introduced": "1.0.0"}, {"fixed": "1.2.3"}]}]
+		}],
+		"database_specific": {"severity": "MODERATE"}
+	}`), &vuln)
+	if err != nil {
+		t.Fatalf("could not parse vulnerability: %v", err)
+	}
+
+	minimist := models.PackageInfo{Name: "minimist", Version: "0.0.8", Ecosystem: "npm"}
+	results := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/app/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{{
+					Package:         minimist,
+					Vulnerabilities: []models.Vulnerability{vuln},
+					Groups:          []models.GroupInfo{{IDs: []string{"GHSA-vh95-rmgr-6w4m"}}},
+					Location:        &models.SourceLocation{Line: 12, Column: 6, EndColumn: 14},
+				}},
+			},
+			{
+				Source: models.SourceInfo{Path: "/app/bom.spdx.json", Type: "sbom"},
+				Packages: []models.PackageVulns{{
+					Package:         minimist,
+					Vulnerabilities: []models.Vulnerability{vuln},
+					Groups:          []models.GroupInfo{{IDs: []string{"GHSA-vh95-rmgr-6w4m"}}},
+				}},
+			},
+			{
+				Source: models.SourceInfo{Path: "/app/", Type: "git"},
+				Packages: []models.PackageVulns{{
+					Package:         models.PackageInfo{Version: "abc123", Ecosystem: "GIT"},
+					Vulnerabilities: []models.Vulnerability{{ID: "OSV-2020-1"}},
+					Groups:          []models.GroupInfo{{IDs: []string{"OSV-2020-1"}}},
+				}},
+			},
+		},
+	}
+
+	message := "minimist@0.0.8 is affected by GHSA-vh95-rmgr-6w4m: Prototype Pollution in minimist (fixed in 0.2.1, 1.2.3)"
+	want := []Diagnostic{
+		{
+			File:          "/app/package-lock.json",
+			Line:          12,
+			Column:        6,
+			EndLine:       12,
+			EndColumn:     14,
+			Severity:      "warning",
+			Source:        "osv-scanner",
+			Code:          "GHSA-vh95-rmgr-6w4m",
+			IDs:           []string{"GHSA-vh95-rmgr-6w4m"},
+			Message:       message,
+			Package:       minimist,
+			FixedVersions: []string{"0.2.1", "1.2.3"},
+		},
+		{
+			File:          "/app/bom.spdx.json",
+			Line:          1,
+			Column:        1,
+			EndLine:       1,
+			EndColumn:     1,
+			Severity:      "warning",
+			Source:        "osv-scanner",
+			Code:          "GHSA-vh95-rmgr-6w4m",
+			IDs:           []string{"GHSA-vh95-rmgr-6w4m"},
+			Message:       message,
+			Package:       minimist,
+			FixedVersions: []string{"0.2.1", "1.2.3"},
+		},
+	}
+
+	if diff := cmp.Diff(want, Diagnostics(results)); diff != "" {
+		t.Errorf("Diagnostics() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,86 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// gitHubPropertyEscaper escapes values of workflow command properties, per
+// https://github.com/actions/toolkit/blob/main/packages/core/src/command.ts
+var gitHubPropertyEscaper = strings.NewReplacer(
+	"%", "%25",
+	"\r", "%0D",
+	"\n", "%0A",
+	":", "%3A",
+	",", "%2C",
+)
+
+// gitHubMessageEscaper escapes the message of workflow commands
+var gitHubMessageEscaper = strings.NewReplacer(
+	"%", "%25",
+	"\r", "%0D",
+	"\n", "%0A",
+)
+
+// gitHubAnnotations returns a workflow command for each group of
+// vulnerabilities, annotating the lockfile or SBOM they were found in, relative
+// to workingDir, at the line their package is declared on if it is known
+func gitHubAnnotations(vulnResult *models.VulnerabilityResults, workingDir string) []string {
+	var annotations []string
+
+	for _, sourceRes := range vulnResult.Results {
+		sourcePath := sourceRes.Source.Path
+		if workingDir != "" {
+			if rel, err := filepath.Rel(workingDir, sourcePath); err == nil {
+				sourcePath = filepath.ToSlash(rel)
+			}
+		}
+
+		for _, pkg := range sourceRes.Packages {
+			for _, group := range pkg.Groups {
+				var properties []string
+				if sourceRes.Source.Type == "lockfile" || sourceRes.Source.Type == "sbom" {
+					properties = append(properties, "file="+gitHubPropertyEscaper.Replace(sourcePath))
+
+					if pkg.Location != nil && pkg.Location.Line > 0 {
+						properties = append(properties, "line="+strconv.Itoa(pkg.Location.Line))
+						if pkg.Location.Column > 0 {
+							properties = append(properties, "col="+strconv.Itoa(pkg.Location.Column))
+						}
+						if pkg.Location.EndColumn > pkg.Location.Column {
+							properties = append(properties, "endColumn="+strconv.Itoa(pkg.Location.EndColumn))
+						}
+					}
+				}
+				properties = append(properties, "title="+gitHubPropertyEscaper.Replace(
+					fmt.Sprintf("%s@%s (%s)", pkg.Package.Name, pkg.Package.Version, pkg.Package.Ecosystem),
+				))
+
+				message, _ := groupMessage(pkg, group)
+				annotations = append(annotations, fmt.Sprintf("::error %s::%s", strings.Join(properties, ","), gitHubMessageEscaper.Replace(message)))
+			}
+		}
+	}
+
+	return annotations
+}
+
+// PrintGitHubAnnotationsResults writes each vulnerability as a GitHub Actions
+// workflow command, so that they are shown as annotations on the lines of the
+// pull request that declare the affected packages
+func PrintGitHubAnnotationsResults(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) {
+	workingDir, err := os.Getwd()
+	if err != nil {
+		workingDir = ""
+	}
+
+	for _, annotation := range gitHubAnnotations(vulnResult, workingDir) {
+		fmt.Fprintln(outputWriter, annotation)
+	}
+}
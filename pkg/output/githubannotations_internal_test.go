@@ -0,0 +1,53 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestGitHubAnnotations(t *testing.T) {
+	t.Parallel()
+
+	minimist := models.PackageInfo{Name: "minimist", Version: "0.0.8", Ecosystem: "npm"}
+	results := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/repo/web/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{{
+					Package: minimist,
+					Vulnerabilities: []models.Vulnerability{
+						{ID: "GHSA-vh95-rmgr-6w4m", Summary: "Prototype Pollution in minimist, 100% of the time"},
+					},
+					Groups:   []models.GroupInfo{{IDs: []string{"GHSA-vh95-rmgr-6w4m"}}},
+					Location: &models.SourceLocation{Line: 12, Column: 6, EndColumn: 14},
+				}},
+			},
+			{
+				Source: models.SourceInfo{Path: "/repo/bom.cdx.json", Type: "sbom"},
+				Packages: []models.PackageVulns{{
+					Package: models.PackageInfo{Name: "a,b:c", Version: "1.0.0", Ecosystem: "npm"},
+					Groups:  []models.GroupInfo{{IDs: []string{"OSV-1", "CVE-1"}}},
+				}},
+			},
+			{
+				Source: models.SourceInfo{Path: "debian:11", Type: "docker"},
+				Packages: []models.PackageVulns{{
+					Package: models.PackageInfo{Name: "openssl", Version: "1.1.1n", Ecosystem: "Debian"},
+					Groups:  []models.GroupInfo{{IDs: []string{"OSV-2"}}},
+				}},
+			},
+		},
+	}
+
+	want := []string{
+		"::error file=web/package-lock.json,line=12,col=6,endColumn=14,title=minimist@0.0.8 (npm)::minimist@0.0.8 is affected by GHSA-vh95-rmgr-6w4m: Prototype Pollution in minimist, 100%25 of the time",
+		"::error file=bom.cdx.json,title=a%2Cb%3Ac@1.0.0 (npm)::a,b:c@1.0.0 is affected by OSV-1, CVE-1",
+		"::error title=openssl@1.1.1n (Debian)::openssl@1.1.1n is affected by OSV-2",
+	}
+
+	if diff := cmp.Diff(want, gitHubAnnotations(results, "/repo")); diff != "" {
+		t.Errorf("gitHubAnnotations() mismatch (-want +got):\n%s", diff)
+	}
+}
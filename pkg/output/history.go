@@ -0,0 +1,60 @@
+package output
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/google/osv-scanner/pkg/models"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// historyTableBuilder adds a row for each vulnerability whose history was
+// scanned, with the tag or commit at which it first affected its package
+func historyTableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults) table.Writer {
+	workingDir, workingDirErr := os.Getwd()
+	for _, history := range vulnResult.History {
+		sourcePath := history.Source.Path
+		if workingDirErr == nil {
+			if rel, err := filepath.Rel(workingDir, sourcePath); err == nil {
+				sourcePath = rel
+			}
+		}
+
+		since := history.Tag
+		if since == "" {
+			since = history.Commit
+			if len(since) > 12 {
+				since = since[:12]
+			}
+		}
+
+		outputTable.AppendRow(table.Row{
+			"https://osv.dev/" + history.ID,
+			history.Package.Name,
+			history.Version,
+			since,
+			history.Date.Format("2006-01-02"),
+			sourcePath,
+		})
+	}
+
+	return outputTable
+}
+
+// printHistoryTable prints when each vulnerability first affected its
+// package, if the history of lockfiles was scanned
+func printHistoryTable(vulnResult *models.VulnerabilityResults, outputWriter io.Writer, locale Locale, style func(table.Writer)) {
+	outputTable := table.NewWriter()
+	outputTable.SetOutputMirror(outputWriter)
+	outputTable.AppendHeader(localizedRow(locale, "OSV URL", "Package", "Version", "Affected Since", "Date", "Source"))
+	style(outputTable)
+
+	outputTable = historyTableBuilder(outputTable, vulnResult)
+
+	if outputTable.Length() == 0 {
+		return
+	}
+	outputTable.Render()
+}
@@ -0,0 +1,46 @@
+package output
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestPrintTableResults_History(t *testing.T) {
+	t.Parallel()
+
+	source := models.SourceInfo{Path: "requirements.txt", Type: "lockfile"}
+	results := &models.VulnerabilityResults{
+		Results: []models.PackageSource{},
+		History: []models.VulnerabilityHistory{
+			{
+				Source:  source,
+				Package: models.PackageInfo{Name: "flask", Version: "2.0.1", Ecosystem: "PyPI"},
+				ID:      "GHSA-m2qf-hxjv-5gpq",
+				Tag:     "v1.2.0",
+				Commit:  "9d1b2f0c5e8a4b7d6c3e2f1a0b9c8d7e6f5a4b3c",
+				Date:    time.Date(2022, 3, 14, 0, 0, 0, 0, time.UTC),
+				Version: "1.0.0",
+			},
+			{
+				Source:  source,
+				Package: models.PackageInfo{Name: "django", Version: "4.0.0", Ecosystem: "PyPI"},
+				ID:      "GHSA-2gwj-7jmv-h26r",
+				Commit:  "5a4b3c9d1b2f0c5e8a4b7d6c3e2f1a0b9c8d7e6f",
+				Date:    time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+				Version: "4.0.0",
+			},
+		},
+	}
+
+	var out strings.Builder
+	printTableResults(results, &out, DefaultLocale, ColorNever, Themes[DefaultThemeName])
+
+	for _, want := range []string{"AFFECTED SINCE", "| v1.2.0", "2022-03-14", "| 5a4b3c9d1b2f", "https://osv.dev/GHSA-2gwj-7jmv-h26r"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected the history table to include %q, got:\n%s", want, out.String())
+		}
+	}
+}
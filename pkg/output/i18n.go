@@ -0,0 +1,271 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Locale is a language that human-readable output can be written in,
+// identified by its ISO 639-1 code
+type Locale string
+
+// DefaultLocale is the locale that output is written in unless another is
+// selected, with its messages being used as the keys for all other locales
+const DefaultLocale Locale = "en"
+
+// translations holds the translations of the English messages used in table
+// headers and summaries for each supported locale; any message that is
+// missing from a locale is left in English
+var translations = map[Locale]map[string]string{
+	"de": {
+		"OSV URL (ID In Bold)": "OSV-URL (ID fett)",
+		"OSV URL":              "OSV-URL",
+		"Ecosystem":            "Ökosystem",
+		"Package":              "Paket",
+		"Version":              "Version",
+		"Source":               "Quelle",
+		"Workspace Member":     "Workspace-Mitglied",
+		"Workspace":            "Workspace",
+		"Owners":               "Verantwortliche",
+		"Packages":             "Pakete",
+		"Vulnerabilities":      "Schwachstellen",
+		"Registry Issue":       "Registry-Problem",
+		"Detail":               "Details",
+		"Suspicious Package":   "Verdächtiges Paket",
+		"similar to %s":        "ähnlich wie %s",
+		"Latest":               "Neueste",
+		"Behind":               "Rückstand",
+		"Deprecation":          "Veraltung",
+		"Repository":           "Repository",
+		"Scorecard":            "Scorecard",
+		"Maintained":           "Gepflegt",
+		"Fixed Version":        "Behobene Version",
+		"Upgrade":              "Aktualisierung",
+		"Dependency Changes":   "Geänderte Abhängigkeiten",
+		"License":              "Lizenz",
+		"Not Allowed":          "Nicht erlaubt",
+		"Reachability":         "Erreichbarkeit",
+		"Affected Since":       "Betroffen seit",
+		"Date":                 "Datum",
+		"Package URL":          "Paket-URL",
+		"Type":                 "Typ",
+		"Parser":               "Parser",
+		"Skipped":              "Übersprungen",
+		"Symbols":              "Symbole",
+		"called":               "aufgerufen",
+		"imported":             "importiert",
+		"not-imported":         "nicht importiert",
+		"Fixes":                "Behebt",
+		"unknown":              "unbekannt",
+		"none":                 "keine",
+		"%d (%d major)":        "%d (%d Major)",
+		"(breaking)":           "(inkompatibel)",
+		"Project":              "Projekt",
+		"Ecosystems":           "Ökosysteme",
+		"Critical":             "Kritisch",
+		"High":                 "Hoch",
+		"Medium":               "Mittel",
+		"Low":                  "Niedrig",
+		"Unknown":              "Unbekannt",
+
+		"Target %s could not be scanned: %s":                                   "Ziel %s konnte nicht gescannt werden: %s",
+		"Target %s has %d vulnerabilities":                                     "Ziel %s hat %d Schwachstellen",
+		"Scanned %d targets: %d with vulnerabilities, %d could not be scanned": "%d Ziele gescannt: %d mit Schwachstellen, %d konnten nicht gescannt werden",
+		"Filtered %d vulnerabilities from output":                              "%d Schwachstellen aus der Ausgabe gefiltert",
+		"%s has been filtered out because: %s":                                 "%s wurde herausgefiltert, weil: %s",
+		"Packages declared in %s have been filtered out because: %s":           "Pakete, die in %s deklariert sind, wurden herausgefiltert, weil: %s",
+		"The ignore for %s in %s does not match any vulnerabilities":           "Die Ausnahme für %s in %s trifft auf keine Schwachstellen zu",
+		"The ignore for %s in %s expired on %s, so it is reported again":       "Die Ausnahme für %s in %s ist am %s abgelaufen und wird daher wieder gemeldet",
+		"No package sources found, --help for usage information.":              "Keine Paketquellen gefunden, --help für Hinweise zur Verwendung.",
+	},
+	"es": {
+		"OSV URL (ID In Bold)": "URL de OSV (ID en negrita)",
+		"OSV URL":              "URL de OSV",
+		"Ecosystem":            "Ecosistema",
+		"Package":              "Paquete",
+		"Version":              "Versión",
+		"Source":               "Origen",
+		"Workspace Member":     "Miembro del espacio de trabajo",
+		"Workspace":            "Espacio de trabajo",
+		"Owners":               "Responsables",
+		"Packages":             "Paquetes",
+		"Vulnerabilities":      "Vulnerabilidades",
+		"Registry Issue":       "Problema del registro",
+		"Detail":               "Detalle",
+		"Suspicious Package":   "Paquete sospechoso",
+		"similar to %s":        "similar a %s",
+		"Latest":               "Última",
+		"Behind":               "Retraso",
+		"Deprecation":          "Obsolescencia",
+		"Repository":           "Repositorio",
+		"Scorecard":            "Scorecard",
+		"Maintained":           "Mantenido",
+		"Fixed Version":        "Versión corregida",
+		"Upgrade":              "Actualización",
+		"Dependency Changes":   "Cambios en dependencias",
+		"License":              "Licencia",
+		"Not Allowed":          "No permitida",
+		"Reachability":         "Alcance",
+		"Affected Since":       "Afectado desde",
+		"Date":                 "Fecha",
+		"Package URL":          "URL del paquete",
+		"Type":                 "Tipo",
+		"Parser":               "Analizador",
+		"Skipped":              "Omitido",
+		"Symbols":              "Símbolos",
+		"called":               "llamada",
+		"imported":             "importada",
+		"not-imported":         "no importada",
+		"Fixes":                "Corrige",
+		"unknown":              "desconocido",
+		"none":                 "ninguno",
+		"%d (%d major)":        "%d (%d mayores)",
+		"(breaking)":           "(incompatible)",
+		"Project":              "Proyecto",
+		"Ecosystems":           "Ecosistemas",
+		"Critical":             "Crítica",
+		"High":                 "Alta",
+		"Medium":               "Media",
+		"Low":                  "Baja",
+		"Unknown":              "Desconocida",
+
+		"Target %s could not be scanned: %s":                                   "No se pudo analizar el objetivo %s: %s",
+		"Target %s has %d vulnerabilities":                                     "El objetivo %s tiene %d vulnerabilidades",
+		"Scanned %d targets: %d with vulnerabilities, %d could not be scanned": "Se analizaron %d objetivos: %d con vulnerabilidades, %d no se pudieron analizar",
+		"Filtered %d vulnerabilities from output":                              "Se filtraron %d vulnerabilidades de la salida",
+		"%s has been filtered out because: %s":                                 "%s se ha filtrado porque: %s",
+		"Packages declared in %s have been filtered out because: %s":           "Los paquetes declarados en %s se han filtrado porque: %s",
+		"The ignore for %s in %s does not match any vulnerabilities":           "La exclusión de %s en %s no coincide con ninguna vulnerabilidad",
+		"The ignore for %s in %s expired on %s, so it is reported again":       "La exclusión de %s en %s caducó el %s, por lo que se vuelve a informar",
+		"No package sources found, --help for usage information.":              "No se encontraron fuentes de paquetes, use --help para ver cómo usarlo.",
+	},
+	"fr": {
+		"OSV URL (ID In Bold)": "URL OSV (ID en gras)",
+		"OSV URL":              "URL OSV",
+		"Ecosystem":            "Écosystème",
+		"Package":              "Paquet",
+		"Version":              "Version",
+		"Source":               "Source",
+		"Workspace Member":     "Membre de l'espace de travail",
+		"Workspace":            "Espace de travail",
+		"Owners":               "Responsables",
+		"Packages":             "Paquets",
+		"Vulnerabilities":      "Vulnérabilités",
+		"Registry Issue":       "Problème de registre",
+		"Detail":               "Détail",
+		"Suspicious Package":   "Paquet suspect",
+		"similar to %s":        "similaire à %s",
+		"Latest":               "Dernière",
+		"Behind":               "Retard",
+		"Deprecation":          "Obsolescence",
+		"Repository":           "Dépôt",
+		"Scorecard":            "Scorecard",
+		"Maintained":           "Maintenu",
+		"Fixed Version":        "Version corrigée",
+		"Upgrade":              "Mise à jour",
+		"Dependency Changes":   "Dépendances modifiées",
+		"License":              "Licence",
+		"Not Allowed":          "Non autorisée",
+		"Reachability":         "Atteignabilité",
+		"Affected Since":       "Affecté depuis",
+		"Date":                 "Date",
+		"Package URL":          "URL du paquet",
+		"Type":                 "Type",
+		"Parser":               "Analyseur",
+		"Skipped":              "Ignoré",
+		"Symbols":              "Symboles",
+		"called":               "appelée",
+		"imported":             "importée",
+		"not-imported":         "non importée",
+		"Fixes":                "Corrige",
+		"unknown":              "inconnu",
+		"none":                 "aucun",
+		"%d (%d major)":        "%d (%d majeures)",
+		"(breaking)":           "(incompatible)",
+		"Project":              "Projet",
+		"Ecosystems":           "Écosystèmes",
+		"Critical":             "Critique",
+		"High":                 "Élevée",
+		"Medium":               "Moyenne",
+		"Low":                  "Faible",
+		"Unknown":              "Inconnue",
+
+		"Target %s could not be scanned: %s":                                   "La cible %s n'a pas pu être analysée : %s",
+		"Target %s has %d vulnerabilities":                                     "La cible %s a %d vulnérabilités",
+		"Scanned %d targets: %d with vulnerabilities, %d could not be scanned": "%d cibles analysées : %d avec des vulnérabilités, %d n'ont pas pu être analysées",
+		"Filtered %d vulnerabilities from output":                              "%d vulnérabilités filtrées de la sortie",
+		"%s has been filtered out because: %s":                                 "%s a été filtrée car : %s",
+		"Packages declared in %s have been filtered out because: %s":           "Les paquets déclarés dans %s ont été filtrés car : %s",
+		"The ignore for %s in %s does not match any vulnerabilities":           "L'exclusion de %s dans %s ne correspond à aucune vulnérabilité",
+		"The ignore for %s in %s expired on %s, so it is reported again":       "L'exclusion de %s dans %s a expiré le %s, elle est donc de nouveau signalée",
+		"No package sources found, --help for usage information.":              "Aucune source de paquets trouvée, --help pour l'aide à l'utilisation.",
+	},
+}
+
+// SupportedLocales returns the locales that output can be written in
+func SupportedLocales() []Locale {
+	locales := []Locale{DefaultLocale}
+	for locale := range translations {
+		locales = append(locales, locale)
+	}
+
+	sort.Slice(locales, func(i, j int) bool { return locales[i] < locales[j] })
+
+	return locales
+}
+
+// ParseLocale parses a locale such as "de", "de-DE" or "de_DE.UTF-8" into one
+// of the supported locales, ignoring the region and encoding
+func ParseLocale(tag string) (Locale, error) {
+	language := strings.ToLower(tag)
+	if i := strings.IndexAny(language, "-_.@"); i != -1 {
+		language = language[:i]
+	}
+
+	locale := Locale(language)
+	if _, ok := translations[locale]; ok || locale == DefaultLocale {
+		return locale, nil
+	}
+
+	supported := make([]string, 0, len(translations)+1)
+	for _, l := range SupportedLocales() {
+		supported = append(supported, string(l))
+	}
+
+	return DefaultLocale, fmt.Errorf("unsupported locale %q, expected one of %s", tag, strings.Join(supported, ", "))
+}
+
+// LocaleFromEnvironment returns the locale selected by the LC_ALL, LC_MESSAGES
+// or LANG environment variables, falling back to DefaultLocale if none of them
+// select a supported locale
+func LocaleFromEnvironment() Locale {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		tag := os.Getenv(name)
+		if tag == "" {
+			continue
+		}
+
+		// the first variable that is set takes precedence, even if it is not
+		// supported, in the same way as with gettext
+		locale, err := ParseLocale(tag)
+		if err != nil {
+			return DefaultLocale
+		}
+
+		return locale
+	}
+
+	return DefaultLocale
+}
+
+// Sprintf formats the translation of the given English message, falling back
+// to the message itself if it has not been translated
+func (l Locale) Sprintf(format string, args ...any) string {
+	if translated, ok := translations[l][format]; ok {
+		format = translated
+	}
+
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,80 @@
+package output
+
+import (
+	"testing"
+)
+
+func TestParseLocale(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		tag     string
+		want    Locale
+		wantErr bool
+	}{
+		{tag: "en", want: "en"},
+		{tag: "de", want: "de"},
+		{tag: "de-AT", want: "de"},
+		{tag: "fr_CA.UTF-8", want: "fr"},
+		{tag: "ES", want: "es"},
+		{tag: "xx", want: DefaultLocale, wantErr: true},
+		{tag: "C", want: DefaultLocale, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.tag, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseLocale(tt.tag)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLocale(%q) error = %v, wantErr %v", tt.tag, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLocale(%q) = %q, want %q", tt.tag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocale_Sprintf(t *testing.T) {
+	t.Parallel()
+
+	if got := Locale("de").Sprintf("Target %s has %d vulnerabilities", "api", 3); got != "Ziel api hat 3 Schwachstellen" {
+		t.Errorf("unexpected translation %q", got)
+	}
+
+	if got := Locale("de").Sprintf("Not translated %d", 1); got != "Not translated 1" {
+		t.Errorf("expected untranslated messages to be left in English, got %q", got)
+	}
+
+	if got := DefaultLocale.Sprintf("Target %s has %d vulnerabilities", "api", 3); got != "Target api has 3 vulnerabilities" {
+		t.Errorf("unexpected message %q", got)
+	}
+}
+
+// every translation must use the same verbs, in the same order, as the
+// message it translates
+func TestTranslations_Verbs(t *testing.T) {
+	t.Parallel()
+
+	for locale, messages := range translations {
+		for message, translated := range messages {
+			if want, got := verbsOf(message), verbsOf(translated); want != got {
+				t.Errorf("%s translation of %q uses %q rather than %q", locale, message, got, want)
+			}
+		}
+	}
+}
+
+func verbsOf(format string) string {
+	verbs := ""
+	for i := 0; i < len(format)-1; i++ {
+		if format[i] == '%' {
+			verbs += format[i : i+2]
+			i++
+		}
+	}
+
+	return verbs
+}
@@ -0,0 +1,66 @@
+package output
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/google/osv-scanner/pkg/models"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// InventoryFormats are the output formats that can show an inventory of the
+// packages that were found
+var InventoryFormats = []string{"table", "markdown", "json"}
+
+// inventoryTableBuilder adds a row for each package that was found, with the
+// commit of git repositories in place of their version
+func inventoryTableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults) table.Writer {
+	workingDir, workingDirErr := os.Getwd()
+	for _, pkg := range vulnResult.Inventory {
+		sourcePath := pkg.Source.Path
+		if workingDirErr == nil {
+			if rel, err := filepath.Rel(workingDir, sourcePath); err == nil {
+				sourcePath = rel
+			}
+		}
+
+		version := pkg.Package.Version
+		if pkg.Commit != "" {
+			version = pkg.Commit
+		}
+
+		outputTable.AppendRow(table.Row{
+			pkg.Package.Ecosystem,
+			pkg.Package.Name,
+			version,
+			pkg.PURL,
+			sourcePath,
+		})
+	}
+
+	return outputTable
+}
+
+// printInventoryTable prints every package that was found, rendering the table
+// as markdown if asked to
+func printInventoryTable(vulnResult *models.VulnerabilityResults, outputWriter io.Writer, locale Locale, style func(table.Writer), markdown bool) {
+	outputTable := table.NewWriter()
+	outputTable.SetOutputMirror(outputWriter)
+	outputTable.AppendHeader(localizedRow(locale, "Ecosystem", "Package", "Version", "Package URL", "Source"))
+	if style != nil {
+		style(outputTable)
+	}
+
+	outputTable = inventoryTableBuilder(outputTable, vulnResult)
+
+	if outputTable.Length() == 0 {
+		return
+	}
+	if markdown {
+		outputTable.RenderMarkdown()
+	} else {
+		outputTable.Render()
+	}
+}
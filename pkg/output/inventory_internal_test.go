@@ -0,0 +1,47 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestReporter_PrintInventory(t *testing.T) {
+	t.Parallel()
+
+	results := &models.VulnerabilityResults{
+		Results: []models.PackageSource{},
+		Inventory: []models.InventoryPackage{
+			{
+				Source:  models.SourceInfo{Path: "vendor/zlib", Type: "git"},
+				Package: models.PackageInfo{Name: "zlib"},
+				Commit:  "9d1b2f0",
+			},
+			{
+				Source:  models.SourceInfo{Path: "package-lock.json", Type: "lockfile"},
+				Package: models.PackageInfo{Name: "wrappy", Version: "1.0.2", Ecosystem: "npm"},
+				PURL:    "pkg:npm/wrappy@1.0.2",
+			},
+		},
+	}
+
+	var out strings.Builder
+	if err := NewReporter(&out, &out, "markdown").PrintInventory(results); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"| Ecosystem | Package | Version | Package URL | Source |",
+		"|  | zlib | 9d1b2f0 |  | vendor/zlib |",
+		"| npm | wrappy | 1.0.2 | pkg:npm/wrappy@1.0.2 | package-lock.json |",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected the inventory to include %q, got:\n%s", want, out.String())
+		}
+	}
+
+	if err := NewReporter(&out, &out, "sarif").PrintInventory(results); err == nil {
+		t.Errorf("expected an error for a format that cannot show an inventory")
+	}
+}
@@ -0,0 +1,53 @@
+package output
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/models"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// licenseViolationsTableBuilder adds a row for each package whose licenses
+// are not allowed by the license policy
+func licenseViolationsTableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults) table.Writer {
+	workingDir, workingDirErr := os.Getwd()
+	for _, violation := range vulnResult.LicenseViolations {
+		sourcePath := violation.Source.Path
+		if workingDirErr == nil {
+			if rel, err := filepath.Rel(workingDir, sourcePath); err == nil {
+				sourcePath = rel
+			}
+		}
+
+		outputTable.AppendRow(table.Row{
+			violation.Package.Ecosystem,
+			violation.Package.Name,
+			violation.Package.Version,
+			violation.License,
+			strings.Join(violation.Denied, "\n"),
+			sourcePath,
+		})
+	}
+
+	return outputTable
+}
+
+// printLicenseViolationsTable prints the packages whose licenses are not
+// allowed by the license policy, if licenses were checked and any were found
+func printLicenseViolationsTable(vulnResult *models.VulnerabilityResults, outputWriter io.Writer, locale Locale, style func(table.Writer)) {
+	outputTable := table.NewWriter()
+	outputTable.SetOutputMirror(outputWriter)
+	outputTable.AppendHeader(localizedRow(locale, "Ecosystem", "Package", "Version", "License", "Not Allowed", "Source"))
+	style(outputTable)
+
+	outputTable = licenseViolationsTableBuilder(outputTable, vulnResult)
+
+	if outputTable.Length() == 0 {
+		return
+	}
+	outputTable.Render()
+}
@@ -0,0 +1,33 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestPrintTableResults_LicenseViolations(t *testing.T) {
+	t.Parallel()
+
+	results := &models.VulnerabilityResults{
+		Results: []models.PackageSource{},
+		LicenseViolations: []models.LicenseViolation{
+			{
+				Source:  models.SourceInfo{Path: "package-lock.json", Type: "lockfile"},
+				Package: models.PackageInfo{Name: "mongodb-memory-server", Version: "8.0.0", Ecosystem: "npm"},
+				License: "MIT AND SSPL-1.0",
+				Denied:  []string{"SSPL-1.0"},
+			},
+		},
+	}
+
+	var out strings.Builder
+	printTableResults(results, &out, DefaultLocale, ColorNever, Themes[DefaultThemeName])
+
+	for _, want := range []string{"NOT ALLOWED", "mongodb-memory-server", "MIT AND SSPL-1.0", "| SSPL-1.0"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected the license violations table to include %q, got:\n%s", want, out.String())
+		}
+	}
+}
@@ -2,15 +2,55 @@ package output
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 
 	"github.com/google/osv-scanner/pkg/models"
 )
 
+// JSONSchemaVersion is the version of the schema of the json format, which is
+// incremented whenever a change is made that is not backwards compatible, such
+// as removing or renaming a field or changing what its values mean. Adding a
+// field is backwards compatible.
+const JSONSchemaVersion = 1
+
+// ErrUnsupportedSchemaVersion is returned when parsing results written with a
+// newer schema than this version of the scanner understands
+var ErrUnsupportedSchemaVersion = errors.New("unsupported schema version")
+
+// JSONResults is the document written by the json format, being the results
+// along with the version of the schema they were written with
+type JSONResults struct {
+	// SchemaVersion is the JSONSchemaVersion the results were written with, or
+	// 0 for results written before the schema was versioned
+	SchemaVersion int `json:"schemaVersion"`
+	models.VulnerabilityResults
+}
+
 // PrintJSONResults writes results to the provided writer in JSON format
 func PrintJSONResults(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) error {
 	encoder := json.NewEncoder(outputWriter)
 	encoder.SetIndent("", "  ")
 
-	return encoder.Encode(vulnResult)
+	return encoder.Encode(JSONResults{
+		SchemaVersion:        JSONSchemaVersion,
+		VulnerabilityResults: *vulnResult,
+	})
+}
+
+// ParseJSONResults parses results written in the json format, returning
+// ErrUnsupportedSchemaVersion if they were written with a newer schema
+func ParseJSONResults(content []byte) (JSONResults, error) {
+	var results JSONResults
+
+	if err := json.Unmarshal(content, &results); err != nil {
+		return JSONResults{}, err
+	}
+
+	if results.SchemaVersion > JSONSchemaVersion {
+		return JSONResults{}, fmt.Errorf("%w: %d is newer than %d", ErrUnsupportedSchemaVersion, results.SchemaVersion, JSONSchemaVersion)
+	}
+
+	return results, nil
 }
@@ -0,0 +1,120 @@
+package output
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// the json format is parsed by other tools, so changes to its layout must be
+// deliberate, and come with a new JSONSchemaVersion if they are not backwards
+// compatible
+func TestPrintJSONResults(t *testing.T) {
+	t.Parallel()
+
+	results := &models.VulnerabilityResults{
+		Results: []models.PackageSource{{
+			Source: models.SourceInfo{Path: "/app/package-lock.json", Type: "lockfile"},
+			Packages: []models.PackageVulns{{
+				Package:         models.PackageInfo{Name: "minimist", Version: "0.0.8", Ecosystem: "npm"},
+				Vulnerabilities: []models.Vulnerability{{ID: "GHSA-vh95-rmgr-6w4m"}},
+				Groups:          []models.GroupInfo{{IDs: []string{"GHSA-vh95-rmgr-6w4m"}}},
+			}},
+		}},
+	}
+
+	var out strings.Builder
+	if err := PrintJSONResults(results, &out); err != nil {
+		t.Fatalf("PrintJSONResults() error = %v", err)
+	}
+
+	want := `{
+  "schemaVersion": 1,
+  "results": [
+    {
+      "source": {
+        "path": "/app/package-lock.json",
+        "type": "lockfile"
+      },
+      "packages": [
+        {
+          "package": {
+            "name": "minimist",
+            "version": "0.0.8",
+            "ecosystem": "npm"
+          },
+          "vulnerabilities": [
+            {
+              "schema_version": "",
+              "id": "GHSA-vh95-rmgr-6w4m",
+              "modified": "0001-01-01T00:00:00Z",
+              "published": "0001-01-01T00:00:00Z",
+              "aliases": null,
+              "summary": "",
+              "details": "",
+              "affected": null,
+              "references": null
+            }
+          ],
+          "groups": [
+            {
+              "ids": [
+                "GHSA-vh95-rmgr-6w4m"
+              ]
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}
+`
+
+	if diff := cmp.Diff(want, out.String()); diff != "" {
+		t.Errorf("PrintJSONResults() mismatch (-want +got):\n%s", diff)
+	}
+
+	parsed, err := ParseJSONResults([]byte(out.String()))
+	if err != nil {
+		t.Fatalf("ParseJSONResults() error = %v", err)
+	}
+	if parsed.SchemaVersion != JSONSchemaVersion {
+		t.Errorf("ParseJSONResults() schema version = %d, want %d", parsed.SchemaVersion, JSONSchemaVersion)
+	}
+	if diff := cmp.Diff(*results, parsed.VulnerabilityResults); diff != "" {
+		t.Errorf("ParseJSONResults() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseJSONResults(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    int
+		wantErr error
+	}{
+		{name: "current", content: `{"schemaVersion": 1, "results": []}`, want: 1},
+		{name: "before versioning", content: `{"results": []}`, want: 0},
+		{name: "newer", content: `{"schemaVersion": 2, "results": []}`, wantErr: ErrUnsupportedSchemaVersion},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseJSONResults([]byte(tt.content))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseJSONResults() error = %v, want %v", err, tt.wantErr)
+			}
+			if got.SchemaVersion != tt.want {
+				t.Errorf("ParseJSONResults() schema version = %d, want %d", got.SchemaVersion, tt.want)
+			}
+		})
+	}
+}
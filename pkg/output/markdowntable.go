@@ -10,14 +10,38 @@ import (
 
 // PrintTableResults prints the osv scan results into a human friendly table.
 func PrintMarkdownTableResults(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) {
+	printMarkdownTableResults(vulnResult, outputWriter, DefaultLocale)
+}
+
+func printMarkdownTableResults(vulnResult *models.VulnerabilityResults, outputWriter io.Writer, locale Locale) {
 	outputTable := table.NewWriter()
 	outputTable.SetOutputMirror(outputWriter)
-	outputTable.AppendHeader(table.Row{"OSV URL", "Ecosystem", "Package", "Version", "Source"})
+	// owners are only listed when the sources are in a repository with a
+	// CODEOWNERS file, which most are not
+	withOwners := hasOwners(vulnResult)
+	if withOwners {
+		outputTable.AppendHeader(localizedRow(locale, "OSV URL", "Ecosystem", "Package", "Version", "Source", "Owners"))
+	} else {
+		outputTable.AppendHeader(localizedRow(locale, "OSV URL", "Ecosystem", "Package", "Version", "Source"))
+	}
 
-	outputTable = tableBuilder(outputTable, vulnResult, false)
+	outputTable = tableBuilder(outputTable, vulnResult, nil, withOwners)
 
 	if outputTable.Length() == 0 {
 		return
 	}
 	outputTable.RenderMarkdown()
 }
+
+// hasOwners reports if any of the vulnerable packages have owners
+func hasOwners(vulnResult *models.VulnerabilityResults) bool {
+	for _, source := range vulnResult.Results {
+		for _, pkg := range source.Packages {
+			if len(pkg.Owners) > 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
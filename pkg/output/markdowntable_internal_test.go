@@ -0,0 +1,32 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPrintMarkdownTableResults_Owners(t *testing.T) {
+	t.Parallel()
+
+	var withoutOwners strings.Builder
+	printMarkdownTableResults(vulnerableResults(), &withoutOwners, DefaultLocale)
+
+	if strings.Contains(withoutOwners.String(), "Owners") {
+		t.Errorf("expected no owners column, got:\n%s", withoutOwners.String())
+	}
+
+	results := vulnerableResults()
+	results.Results[0].Packages[0].Owners = []string{"@acme/frontend", "@alice"}
+
+	var withOwners strings.Builder
+	printMarkdownTableResults(results, &withOwners, DefaultLocale)
+
+	for _, want := range []string{
+		"| OSV URL | Ecosystem | Package | Version | Source | Owners |",
+		"| package-lock.json | @acme/frontend<br/>@alice |",
+	} {
+		if !strings.Contains(withOwners.String(), want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, withOwners.String())
+		}
+	}
+}
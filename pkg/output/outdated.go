@@ -0,0 +1,58 @@
+package output
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/google/osv-scanner/pkg/models"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// outdatedPackagesTableBuilder adds a row for each package that is behind the
+// latest version on its registry
+func outdatedPackagesTableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults) table.Writer {
+	workingDir, workingDirErr := os.Getwd()
+	for _, pkg := range vulnResult.Outdated {
+		sourcePath := pkg.Source.Path
+		if workingDirErr == nil {
+			if rel, err := filepath.Rel(workingDir, sourcePath); err == nil {
+				sourcePath = rel
+			}
+		}
+
+		behind := pkg.Behind
+		if pkg.MajorVersionsBehind > 1 {
+			behind += " (" + strconv.Itoa(pkg.MajorVersionsBehind) + ")"
+		}
+
+		outputTable.AppendRow(table.Row{
+			pkg.Package.Ecosystem,
+			pkg.Package.Name,
+			pkg.Package.Version,
+			pkg.Latest,
+			behind,
+			sourcePath,
+		})
+	}
+
+	return outputTable
+}
+
+// printOutdatedPackagesTable prints the packages that are behind the latest
+// version on their registry, if packages were checked and any were found
+func printOutdatedPackagesTable(vulnResult *models.VulnerabilityResults, outputWriter io.Writer, locale Locale, style func(table.Writer)) {
+	outputTable := table.NewWriter()
+	outputTable.SetOutputMirror(outputWriter)
+	outputTable.AppendHeader(localizedRow(locale, "Ecosystem", "Package", "Version", "Latest", "Behind", "Source"))
+	style(outputTable)
+
+	outputTable = outdatedPackagesTableBuilder(outputTable, vulnResult)
+
+	if outputTable.Length() == 0 {
+		return
+	}
+	outputTable.Render()
+}
@@ -0,0 +1,32 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestPrintTableResults_Outdated(t *testing.T) {
+	t.Parallel()
+
+	results := &models.VulnerabilityResults{
+		Results: []models.PackageSource{},
+		Outdated: []models.OutdatedPackage{{
+			Source:              models.SourceInfo{Path: "package-lock.json", Type: "lockfile"},
+			Package:             models.PackageInfo{Name: "left-pad", Version: "1.1.0", Ecosystem: "npm"},
+			Latest:              "4.0.0",
+			Behind:              "major",
+			MajorVersionsBehind: 3,
+		}},
+	}
+
+	var out strings.Builder
+	printTableResults(results, &out, DefaultLocale, ColorNever, Themes[DefaultThemeName])
+
+	for _, want := range []string{"LATEST", "BEHIND", "left-pad", "4.0.0", "major (3)"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected the outdated packages table to include %q, got:\n%s", want, out.String())
+		}
+	}
+}
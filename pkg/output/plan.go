@@ -0,0 +1,59 @@
+package output
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/google/osv-scanner/pkg/models"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// PlanFormats are the output formats that can show the plan of a dry run
+var PlanFormats = []string{"table", "markdown", "json"}
+
+// planTableBuilder adds a row for each input that would be scanned or skipped,
+// in the order that they were found
+func planTableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults) table.Writer {
+	workingDir, workingDirErr := os.Getwd()
+	for _, input := range vulnResult.Plan {
+		sourcePath := input.Source.Path
+		if workingDirErr == nil {
+			if rel, err := filepath.Rel(workingDir, sourcePath); err == nil {
+				sourcePath = rel
+			}
+		}
+
+		outputTable.AppendRow(table.Row{
+			sourcePath,
+			input.Source.Type,
+			input.Parser,
+			input.Skipped,
+		})
+	}
+
+	return outputTable
+}
+
+// printPlanTable prints every input that would be scanned or skipped,
+// rendering the table as markdown if asked to
+func printPlanTable(vulnResult *models.VulnerabilityResults, outputWriter io.Writer, locale Locale, style func(table.Writer), markdown bool) {
+	outputTable := table.NewWriter()
+	outputTable.SetOutputMirror(outputWriter)
+	outputTable.AppendHeader(localizedRow(locale, "Source", "Type", "Parser", "Skipped"))
+	if style != nil {
+		style(outputTable)
+	}
+
+	outputTable = planTableBuilder(outputTable, vulnResult)
+
+	if outputTable.Length() == 0 {
+		return
+	}
+	if markdown {
+		outputTable.RenderMarkdown()
+	} else {
+		outputTable.Render()
+	}
+}
@@ -0,0 +1,39 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestReporter_PrintPlan(t *testing.T) {
+	t.Parallel()
+
+	results := &models.VulnerabilityResults{
+		Results: []models.PackageSource{},
+		Plan: []models.PlannedInput{
+			{Source: models.SourceInfo{Path: "package-lock.json", Type: "lockfile"}, Parser: "package-lock.json"},
+			{Source: models.SourceInfo{Path: "node_modules/", Type: "directory"}, Skipped: "ignored by git"},
+		},
+	}
+
+	var out strings.Builder
+	if err := NewReporter(&out, &out, "markdown").PrintPlan(results); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"| Source | Type | Parser | Skipped |",
+		"| package-lock.json | lockfile | package-lock.json |  |",
+		"| node_modules/ | directory |  | ignored by git |",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected the plan to include %q, got:\n%s", want, out.String())
+		}
+	}
+
+	if err := NewReporter(&out, &out, "sarif").PrintPlan(results); err == nil {
+		t.Errorf("expected an error for a format that cannot show a plan")
+	}
+}
@@ -0,0 +1,225 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/models"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"golang.org/x/exp/slices"
+)
+
+// directorySourceTypes are the types of sources whose paths are within the
+// directory of the project they belong to, with those of "git" sources being
+// the directory itself. Other sources, such as docker images, are projects of
+// their own.
+var directorySourceTypes = map[string]bool{
+	"lockfile": true,
+	"manifest": true,
+	"sbom":     true,
+	"artifact": true,
+	"binary":   true,
+	"git":      false,
+}
+
+// unknownSeverity is what vulnerabilities without a known severity are
+// totalled as
+const unknownSeverity = "UNKNOWN"
+
+// projectSeverities are the severities that are totalled for each project,
+// in the order of their columns
+var projectSeverities = []string{"CRITICAL", "HIGH", "MEDIUM", "LOW", unknownSeverity}
+
+// projectResults are the results of the sources that belong to a project
+// within a monorepo, with the number of vulnerabilities of each severity
+type projectResults struct {
+	dir        string
+	ecosystems []string
+	totals     map[string]int
+	results    models.VulnerabilityResults
+}
+
+// sourceDir returns the directory that the source is in, or "" if it is not
+// in a directory
+func sourceDir(source models.SourceInfo) string {
+	isFile, ok := directorySourceTypes[source.Type]
+	switch {
+	case !ok:
+		return ""
+	case isFile:
+		return filepath.Dir(filepath.Clean(source.Path))
+	default:
+		return filepath.Clean(source.Path)
+	}
+}
+
+// withinDir reports if the path is the directory or is within it
+func withinDir(path string, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// commonDir returns the deepest directory that all of the directories are in
+func commonDir(dirs []string) string {
+	common := dirs[0]
+	for _, dir := range dirs[1:] {
+		for !withinDir(dir, common) {
+			parent := filepath.Dir(common)
+			if parent == common {
+				break
+			}
+			common = parent
+		}
+	}
+
+	return common
+}
+
+// projectDirs finds the project that each of the directories belongs to,
+// being the outermost of the directories that it is within, other than the
+// directory that all of them are in, which is a project of its own. This
+// means the manifests of a monorepo's services, such as "services/api", and
+// anything nested within them are grouped together, without everything being
+// grouped under the root of the monorepo when it has a manifest too.
+func projectDirs(dirs []string) map[string]string {
+	projects := map[string]string{}
+	if len(dirs) == 0 {
+		return projects
+	}
+
+	isSourceDir := map[string]bool{}
+	for _, dir := range dirs {
+		isSourceDir[dir] = true
+	}
+	root := commonDir(dirs)
+
+	for _, dir := range dirs {
+		project := dir
+		for d := dir; d != root && withinDir(d, root); d = filepath.Dir(d) {
+			if isSourceDir[d] {
+				project = d
+			}
+			if filepath.Dir(d) == d {
+				break
+			}
+		}
+		projects[dir] = project
+	}
+
+	return projects
+}
+
+// groupResultsByProject splits the results into the projects that their
+// sources belong to, ordered by their directories
+func groupResultsByProject(vulnResult *models.VulnerabilityResults) []projectResults {
+	var dirs []string
+	for _, source := range vulnResult.Results {
+		if dir := sourceDir(source.Source); dir != "" && !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	dirProjects := projectDirs(dirs)
+
+	projects := map[string]*projectResults{}
+	for _, source := range vulnResult.Results {
+		dir := source.Source.Path
+		if sourceDir := sourceDir(source.Source); sourceDir != "" {
+			dir = dirProjects[sourceDir]
+		}
+
+		project, ok := projects[dir]
+		if !ok {
+			project = &projectResults{dir: dir, totals: map[string]int{}}
+			projects[dir] = project
+		}
+		project.results.Results = append(project.results.Results, source)
+
+		for _, pkg := range source.Packages {
+			if !slices.Contains(project.ecosystems, pkg.Package.Ecosystem) {
+				project.ecosystems = append(project.ecosystems, pkg.Package.Ecosystem)
+			}
+			for _, group := range pkg.Groups {
+				severity := groupSeverity(pkg, group)
+				if severity == "" {
+					severity = unknownSeverity
+				}
+				project.totals[severity]++
+			}
+		}
+	}
+
+	result := make([]projectResults, 0, len(projects))
+	for _, project := range projects {
+		sort.Strings(project.ecosystems)
+		result = append(result, *project)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].dir < result[j].dir
+	})
+
+	return result
+}
+
+// projectsTableBuilder adds a row for each project with its totals
+func projectsTableBuilder(outputTable table.Writer, projects []projectResults, displayDir func(string) string) table.Writer {
+	for _, project := range projects {
+		row := table.Row{displayDir(project.dir), strings.Join(project.ecosystems, ", ")}
+		for _, severity := range projectSeverities {
+			row = append(row, project.totals[severity])
+		}
+		outputTable.AppendRow(row)
+	}
+
+	return outputTable
+}
+
+// printProjectsResults prints the totals of the vulnerabilities of each
+// project of a monorepo by severity, followed by a section for each project
+// listing its vulnerabilities, so that each section can be routed to the
+// team that owns the project
+func printProjectsResults(vulnResult *models.VulnerabilityResults, outputWriter io.Writer, locale Locale, colorMode ColorMode, theme Theme) {
+	projects := groupResultsByProject(vulnResult)
+	if len(projects) == 0 {
+		return
+	}
+
+	// Working directory used to simplify paths
+	workingDir, workingDirErr := os.Getwd()
+	displayDir := func(dir string) string {
+		if workingDirErr == nil {
+			if rel, err := filepath.Rel(workingDir, dir); err == nil {
+				return rel
+			}
+		}
+
+		return dir
+	}
+
+	style, colored := tableStyle(colorMode, theme)
+	var idTheme *Theme
+	if colored {
+		idTheme = &theme
+	}
+
+	summaryTable := table.NewWriter()
+	summaryTable.SetOutputMirror(outputWriter)
+	summaryTable.AppendHeader(localizedRow(locale, "Project", "Ecosystems", "Critical", "High", "Medium", "Low", "Unknown"))
+	style(summaryTable)
+	projectsTableBuilder(summaryTable, projects, displayDir).Render()
+
+	for i := range projects {
+		fmt.Fprintf(outputWriter, "\n%s\n", displayDir(projects[i].dir))
+
+		outputTable := table.NewWriter()
+		outputTable.SetOutputMirror(outputWriter)
+		outputTable.AppendHeader(localizedRow(locale, "OSV URL (ID In Bold)", "Ecosystem", "Package", "Version", "Source"))
+		style(outputTable)
+		tableBuilder(outputTable, &projects[i].results, idTheme, false).Render()
+	}
+}
@@ -0,0 +1,123 @@
+package output
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func Test_projectDirs(t *testing.T) {
+	t.Parallel()
+
+	dirs := []string{
+		filepath.FromSlash("/repo"),
+		filepath.FromSlash("/repo/services/api"),
+		filepath.FromSlash("/repo/services/api/web"),
+		filepath.FromSlash("/repo/services/worker"),
+		filepath.FromSlash("/repo/tools/lint/cmd"),
+	}
+
+	want := map[string]string{
+		filepath.FromSlash("/repo"):                  filepath.FromSlash("/repo"),
+		filepath.FromSlash("/repo/services/api"):     filepath.FromSlash("/repo/services/api"),
+		filepath.FromSlash("/repo/services/api/web"): filepath.FromSlash("/repo/services/api"),
+		filepath.FromSlash("/repo/services/worker"):  filepath.FromSlash("/repo/services/worker"),
+		filepath.FromSlash("/repo/tools/lint/cmd"):   filepath.FromSlash("/repo/tools/lint/cmd"),
+	}
+
+	if diff := cmp.Diff(want, projectDirs(dirs)); diff != "" {
+		t.Errorf("projectDirs() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func projectsResults() *models.VulnerabilityResults {
+	critical := models.Vulnerability{ID: "GHSA-aaaa", DatabaseSpecific: map[string]interface{}{"severity": "CRITICAL"}}
+	high := models.Vulnerability{ID: "GHSA-bbbb", DatabaseSpecific: map[string]interface{}{"severity": "HIGH"}}
+	unknown := models.Vulnerability{ID: "GHSA-cccc"}
+
+	return &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: filepath.FromSlash("/repo/services/api/package-lock.json"), Type: "lockfile"},
+				Packages: []models.PackageVulns{{
+					Package:         models.PackageInfo{Name: "minimist", Version: "0.0.8", Ecosystem: "npm"},
+					Vulnerabilities: []models.Vulnerability{critical, unknown},
+					Groups:          []models.GroupInfo{{IDs: []string{"GHSA-aaaa"}}, {IDs: []string{"GHSA-cccc"}}},
+				}},
+			},
+			{
+				Source: models.SourceInfo{Path: filepath.FromSlash("/repo/services/api/tools/go.mod"), Type: "lockfile"},
+				Packages: []models.PackageVulns{{
+					Package:         models.PackageInfo{Name: "golang.org/x/text", Version: "0.3.0", Ecosystem: "Go"},
+					Vulnerabilities: []models.Vulnerability{high},
+					Groups:          []models.GroupInfo{{IDs: []string{"GHSA-bbbb"}, Severity: "MEDIUM"}},
+				}},
+			},
+			{
+				Source: models.SourceInfo{Path: filepath.FromSlash("/repo/services/worker/Cargo.lock"), Type: "lockfile"},
+				Packages: []models.PackageVulns{{
+					Package:         models.PackageInfo{Name: "regex", Version: "1.3.1", Ecosystem: "crates.io"},
+					Vulnerabilities: []models.Vulnerability{high},
+					Groups:          []models.GroupInfo{{IDs: []string{"GHSA-bbbb"}}},
+				}},
+			},
+			{
+				Source: models.SourceInfo{Path: "alpine:3.18", Type: "docker"},
+				Packages: []models.PackageVulns{{
+					Package:         models.PackageInfo{Name: "openssl", Version: "3.1.0-r0", Ecosystem: "Alpine:v3.18"},
+					Vulnerabilities: []models.Vulnerability{unknown},
+					Groups:          []models.GroupInfo{{IDs: []string{"GHSA-cccc"}}},
+				}},
+			},
+		},
+	}
+}
+
+func Test_groupResultsByProject(t *testing.T) {
+	t.Parallel()
+
+	projects := groupResultsByProject(projectsResults())
+
+	type summary struct {
+		Dir        string
+		Ecosystems []string
+		Totals     map[string]int
+		Sources    int
+	}
+	got := make([]summary, 0, len(projects))
+	for _, project := range projects {
+		got = append(got, summary{project.dir, project.ecosystems, project.totals, len(project.results.Results)})
+	}
+
+	want := []summary{
+		{filepath.FromSlash("/repo/services/api"), []string{"Go", "npm"}, map[string]int{"CRITICAL": 1, "MEDIUM": 1, "UNKNOWN": 1}, 2},
+		{filepath.FromSlash("/repo/services/worker"), []string{"crates.io"}, map[string]int{"HIGH": 1}, 1},
+		{"alpine:3.18", []string{"Alpine:v3.18"}, map[string]int{"UNKNOWN": 1}, 1},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("groupResultsByProject() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPrintProjectsResults(t *testing.T) {
+	t.Parallel()
+
+	var out strings.Builder
+	printProjectsResults(projectsResults(), &out, DefaultLocale, ColorNever, Themes[DefaultThemeName])
+
+	for _, want := range []string{
+		"| PROJECT ",
+		"| CRITICAL | HIGH | MEDIUM | LOW | UNKNOWN |",
+		"| alpine:3.18 ",
+		"\nalpine:3.18\n",
+		"https://osv.dev/GHSA-aaaa",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
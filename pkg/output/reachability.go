@@ -0,0 +1,53 @@
+package output
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/models"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// reachabilityTableBuilder adds a row for each vulnerability of a Go module
+// whose calls were analyzed
+func reachabilityTableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults, locale Locale) table.Writer {
+	workingDir, workingDirErr := os.Getwd()
+	for _, reachability := range vulnResult.Reachability {
+		sourcePath := reachability.Source.Path
+		if workingDirErr == nil {
+			if rel, err := filepath.Rel(workingDir, sourcePath); err == nil {
+				sourcePath = rel
+			}
+		}
+
+		outputTable.AppendRow(table.Row{
+			"https://osv.dev/" + reachability.ID,
+			reachability.Package.Name,
+			reachability.Package.Version,
+			locale.Sprintf(reachability.Verdict),
+			strings.Join(reachability.Symbols, "\n"),
+			sourcePath,
+		})
+	}
+
+	return outputTable
+}
+
+// printReachabilityTable prints whether the vulnerable symbols of the
+// vulnerabilities of each Go module are called, if calls were analyzed
+func printReachabilityTable(vulnResult *models.VulnerabilityResults, outputWriter io.Writer, locale Locale, style func(table.Writer)) {
+	outputTable := table.NewWriter()
+	outputTable.SetOutputMirror(outputWriter)
+	outputTable.AppendHeader(localizedRow(locale, "OSV URL", "Package", "Version", "Reachability", "Symbols", "Source"))
+	style(outputTable)
+
+	outputTable = reachabilityTableBuilder(outputTable, vulnResult, locale)
+
+	if outputTable.Length() == 0 {
+		return
+	}
+	outputTable.Render()
+}
@@ -0,0 +1,41 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestPrintTableResults_Reachability(t *testing.T) {
+	t.Parallel()
+
+	gomod := models.SourceInfo{Path: "go.mod", Type: "lockfile"}
+	results := &models.VulnerabilityResults{
+		Results: []models.PackageSource{},
+		Reachability: []models.VulnerabilityReachability{
+			{
+				Source:  gomod,
+				Package: models.PackageInfo{Name: "golang.org/x/net", Version: "0.1.0", Ecosystem: "Go"},
+				ID:      "GO-2022-1144",
+				Verdict: "called",
+				Symbols: []string{"golang.org/x/net/http2.Server.ServeConn"},
+			},
+			{
+				Source:  gomod,
+				Package: models.PackageInfo{Name: "golang.org/x/text", Version: "0.3.7", Ecosystem: "Go"},
+				ID:      "GO-2022-1059",
+				Verdict: "imported",
+			},
+		},
+	}
+
+	var out strings.Builder
+	printTableResults(results, &out, "de", ColorNever, Themes[DefaultThemeName])
+
+	for _, want := range []string{"ERREICHBARKEIT", "https://osv.dev/GO-2022-1144", "aufgerufen", "golang.org/x/net/http2.Server.ServeConn", "importiert"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected the reachability table to include %q, got:\n%s", want, out.String())
+		}
+	}
+}
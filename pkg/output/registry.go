@@ -0,0 +1,52 @@
+package output
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/google/osv-scanner/pkg/models"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// registryIssuesTableBuilder adds a row for each package that does not match
+// its public registry
+func registryIssuesTableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults) table.Writer {
+	workingDir, workingDirErr := os.Getwd()
+	for _, issue := range vulnResult.RegistryIssues {
+		sourcePath := issue.Source.Path
+		if workingDirErr == nil {
+			if rel, err := filepath.Rel(workingDir, sourcePath); err == nil {
+				sourcePath = rel
+			}
+		}
+
+		outputTable.AppendRow(table.Row{
+			issue.Package.Ecosystem,
+			issue.Package.Name,
+			issue.Package.Version,
+			issue.Kind,
+			issue.Detail,
+			sourcePath,
+		})
+	}
+
+	return outputTable
+}
+
+// printRegistryIssuesTable prints the packages that do not match their public
+// registry, if registries were checked and any were found
+func printRegistryIssuesTable(vulnResult *models.VulnerabilityResults, outputWriter io.Writer, locale Locale, style func(table.Writer)) {
+	outputTable := table.NewWriter()
+	outputTable.SetOutputMirror(outputWriter)
+	outputTable.AppendHeader(localizedRow(locale, "Ecosystem", "Package", "Version", "Registry Issue", "Detail", "Source"))
+	style(outputTable)
+
+	outputTable = registryIssuesTableBuilder(outputTable, vulnResult)
+
+	if outputTable.Length() == 0 {
+		return
+	}
+	outputTable.Render()
+}
@@ -0,0 +1,38 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestPrintTableResults_RegistryIssues(t *testing.T) {
+	t.Parallel()
+
+	results := &models.VulnerabilityResults{
+		Results: []models.PackageSource{},
+		RegistryIssues: []models.RegistryIssue{{
+			Source:  models.SourceInfo{Path: "package-lock.json", Type: "lockfile"},
+			Package: models.PackageInfo{Name: "wrappyy", Version: "1.0.2", Ecosystem: "npm"},
+			Kind:    "missing-package",
+			Detail:  "wrappyy is not published on https://registry.npmjs.org",
+		}},
+	}
+
+	var out strings.Builder
+	printTableResults(results, &out, DefaultLocale, ColorNever, Themes[DefaultThemeName])
+
+	for _, want := range []string{"REGISTRY ISSUE", "wrappyy", "missing-package", "is not published"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected the registry issues table to include %q, got:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	printTableResults(&models.VulnerabilityResults{}, &out, DefaultLocale, ColorNever, Themes[DefaultThemeName])
+
+	if out.Len() != 0 {
+		t.Errorf("expected no output without vulnerabilities or registry issues, got:\n%s", out.String())
+	}
+}
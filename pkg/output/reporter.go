@@ -8,23 +8,83 @@ import (
 	"github.com/google/osv-scanner/pkg/models"
 )
 
-type Reporter struct {
+// Reporter is where the progress, errors and results of a scan are reported,
+// which programs embedding the scanner can implement to send them somewhere
+// other than stdout and stderr, such as to their own logger
+type Reporter interface {
+	// PrintText reports progress and other informational messages
+	PrintText(msg string)
+	// PrintError reports problems, which do not necessarily stop the scan
+	PrintError(msg string)
+	// PrintResult reports the results of a scan
+	PrintResult(vulnResult *models.VulnerabilityResults) error
+}
+
+// Localizer is implemented by reporters that translate the messages that are
+// reported to them, as used by Localize
+type Localizer interface {
+	Localize(format string, args ...any) string
+}
+
+// Localize formats the translation of the given English message into the
+// locale of the reporter, if it has one
+func Localize(r Reporter, format string, args ...any) string {
+	if localizer, ok := r.(Localizer); ok {
+		return localizer.Localize(format, args...)
+	}
+
+	return fmt.Sprintf(format, args...)
+}
+
+// WriterReporter is the Reporter that writes to stdout and stderr, with the
+// results in one of the supported output formats
+type WriterReporter struct {
 	stdout          io.Writer
 	stderr          io.Writer
 	format          string
+	locale          Locale
+	colorMode       ColorMode
+	theme           Theme
+	quiet           bool
 	hasPrintedError bool
 }
 
-func NewReporter(stdout io.Writer, stderr io.Writer, format string) *Reporter {
-	return &Reporter{
-		stdout: stdout,
-		stderr: stderr,
-		format: format,
+func NewReporter(stdout io.Writer, stderr io.Writer, format string) *WriterReporter {
+	return &WriterReporter{
+		stdout:    stdout,
+		stderr:    stderr,
+		format:    format,
+		locale:    DefaultLocale,
+		colorMode: ColorAuto,
+		theme:     Themes[DefaultThemeName],
 	}
 }
 
+// SetColors sets when table output is colored, and the theme used to color it
+func (r *WriterReporter) SetColors(mode ColorMode, theme Theme) {
+	r.colorMode = mode
+	r.theme = theme
+}
+
+// SetQuiet sets if progress and informational messages printed with PrintText
+// should be suppressed, leaving only the results and errors
+func (r *WriterReporter) SetQuiet(quiet bool) {
+	r.quiet = quiet
+}
+
+// SetLocale sets the language that table headers and summaries are written in
+func (r *WriterReporter) SetLocale(locale Locale) {
+	r.locale = locale
+}
+
+// Localize formats the translation of the given English message into the
+// locale of the reporter
+func (r *WriterReporter) Localize(format string, args ...any) string {
+	return r.locale.Sprintf(format, args...)
+}
+
 // NewVoidReporter creates a reporter that doesn't report to anywhere
-func NewVoidReporter() *Reporter {
+func NewVoidReporter() *WriterReporter {
 	stdout := new(strings.Builder)
 	stderr := new(strings.Builder)
 
@@ -33,38 +93,91 @@ func NewVoidReporter() *Reporter {
 
 // PrintError writes the given message to stderr, regardless of if the reporter
 // is outputting as JSON or not
-func (r *Reporter) PrintError(msg string) {
+func (r *WriterReporter) PrintError(msg string) {
 	fmt.Fprint(r.stderr, msg)
 	r.hasPrintedError = true
 }
 
-func (r *Reporter) HasPrintedError() bool {
+func (r *WriterReporter) HasPrintedError() bool {
 	return r.hasPrintedError
 }
 
 // PrintText writes the given message to stdout, _unless_ the reporter is set
-// to output as JSON, in which case it writes the message to stderr.
+// to output as JSON (or another machine-readable format), in which case it
+// writes the message to stderr.
 //
 // This should be used for content that should always be outputted, but that
 // should not be captured when piping if outputting JSON.
-func (r *Reporter) PrintText(msg string) {
+//
+// Nothing is written if the reporter is quiet.
+func (r *WriterReporter) PrintText(msg string) {
+	if r.quiet {
+		return
+	}
+
 	target := r.stdout
 
-	if r.format == "json" {
+	if r.format == "json" || r.format == "diagnostics" || r.format == "sarif" {
 		target = r.stderr
 	}
 
 	fmt.Fprint(target, msg)
 }
 
-func (r *Reporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+func (r *WriterReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	switch r.format {
+	case "json":
+		return PrintJSONResults(vulnResult, r.stdout)
+	case "markdown":
+		printMarkdownTableResults(vulnResult, r.stdout, r.locale)
+	case "table":
+		printTableResults(vulnResult, r.stdout, r.locale, r.colorMode, r.theme)
+	case "projects":
+		printProjectsResults(vulnResult, r.stdout, r.locale, r.colorMode, r.theme)
+	case "azure-devops":
+		PrintAzureDevOpsResults(vulnResult, r.stdout)
+	case "github-annotations":
+		PrintGitHubAnnotationsResults(vulnResult, r.stdout)
+	case "diagnostics":
+		return PrintDiagnosticsResults(vulnResult, r.stdout)
+	case "sarif":
+		return PrintSARIFResults(vulnResult, r.stdout)
+	}
+
+	return nil
+}
+
+// PrintInventory writes the inventory of the results in place of their
+// vulnerabilities, such as for a scan that did not query for them
+func (r *WriterReporter) PrintInventory(vulnResult *models.VulnerabilityResults) error {
+	switch r.format {
+	case "json":
+		return PrintJSONResults(vulnResult, r.stdout)
+	case "markdown":
+		printInventoryTable(vulnResult, r.stdout, r.locale, nil, true)
+	case "table":
+		style, _ := tableStyle(r.colorMode, r.theme)
+		printInventoryTable(vulnResult, r.stdout, r.locale, style, false)
+	default:
+		return fmt.Errorf("the %s format cannot show an inventory - must be one of: %s", r.format, strings.Join(InventoryFormats, ", "))
+	}
+
+	return nil
+}
+
+// PrintPlan writes the plan of the results in place of their vulnerabilities,
+// for a scan that only found what it would scan
+func (r *WriterReporter) PrintPlan(vulnResult *models.VulnerabilityResults) error {
 	switch r.format {
 	case "json":
 		return PrintJSONResults(vulnResult, r.stdout)
 	case "markdown":
-		PrintMarkdownTableResults(vulnResult, r.stdout)
+		printPlanTable(vulnResult, r.stdout, r.locale, nil, true)
 	case "table":
-		PrintTableResults(vulnResult, r.stdout)
+		style, _ := tableStyle(r.colorMode, r.theme)
+		printPlanTable(vulnResult, r.stdout, r.locale, style, false)
+	default:
+		return fmt.Errorf("the %s format cannot show a plan - must be one of: %s", r.format, strings.Join(PlanFormats, ", "))
 	}
 
 	return nil
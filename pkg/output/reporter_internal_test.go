@@ -0,0 +1,60 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestBufferedReporter_Replay(t *testing.T) {
+	t.Parallel()
+
+	stdout := new(strings.Builder)
+	stderr := new(strings.Builder)
+	r := NewReporter(stdout, stderr, "table")
+	r.SetLocale("de")
+
+	first := NewBufferedReporter(r)
+	second := NewBufferedReporter(r)
+
+	second.PrintText("second\n")
+	first.PrintText("first\n")
+	first.PrintError("failed\n")
+
+	if stdout.Len() != 0 || stderr.Len() != 0 || r.HasPrintedError() {
+		t.Fatalf("expected nothing to be reported until replayed")
+	}
+
+	first.Replay(r)
+	second.Replay(r)
+
+	if got := stdout.String(); got != "first\nsecond\n" {
+		t.Errorf("expected stdout to be in the order replayed, got %q", got)
+	}
+	if got := stderr.String(); got != "failed\n" {
+		t.Errorf("expected stderr to be %q, got %q", "failed\n", got)
+	}
+	if !r.HasPrintedError() {
+		t.Errorf("expected the error of the replayed reporter to be kept")
+	}
+
+	if got := Localize(first, "Package"); got != "Paket" {
+		t.Errorf("expected the buffered reporter to translate as its parent does, got %q", got)
+	}
+}
+
+// plainReporter is a Reporter that does not translate messages
+type plainReporter struct{}
+
+func (plainReporter) PrintText(string)                               {}
+func (plainReporter) PrintError(string)                              {}
+func (plainReporter) PrintResult(*models.VulnerabilityResults) error { return nil }
+
+func TestLocalize_WithoutLocalizer(t *testing.T) {
+	t.Parallel()
+
+	if got := Localize(plainReporter{}, "Scanned %d targets", 2); got != "Scanned 2 targets" {
+		t.Errorf("expected the message to be formatted untranslated, got %q", got)
+	}
+}
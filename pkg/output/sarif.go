@@ -0,0 +1,380 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/models"
+
+	"golang.org/x/exp/slices"
+)
+
+// SARIFSchema is the JSON schema of the version of SARIF that is written
+const SARIFSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool       sarifTool            `json:"tool"`
+	Taxonomies []sarifToolComponent `json:"taxonomies,omitempty"`
+	Results    []sarifResult        `json:"results"`
+}
+
+// sarifToolComponent is a taxonomy that rules can be related to, such as CWE
+type sarifToolComponent struct {
+	Name           string       `json:"name"`
+	Organization   string       `json:"organization"`
+	InformationURI string       `json:"informationUri"`
+	Taxa           []sarifTaxon `json:"taxa"`
+}
+
+type sarifTaxon struct {
+	ID string `json:"id"`
+}
+
+type sarifRelationship struct {
+	Target sarifReportingDescriptorReference `json:"target"`
+	Kinds  []string                          `json:"kinds"`
+}
+
+type sarifReportingDescriptorReference struct {
+	ID            string                      `json:"id"`
+	ToolComponent sarifToolComponentReference `json:"toolComponent"`
+}
+
+type sarifToolComponentReference struct {
+	Name string `json:"name"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifMessage struct {
+	Text     string `json:"text"`
+	Markdown string `json:"markdown,omitempty"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	FullDescription      sarifMessage       `json:"fullDescription"`
+	HelpURI              string             `json:"helpUri"`
+	Help                 sarifMessage       `json:"help"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	Properties           sarifProperties    `json:"properties"`
+	// Relationships relate the rule to the CWEs that it is an instance of
+	Relationships []sarifRelationship `json:"relationships,omitempty"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifProperties struct {
+	// SecuritySeverity is the score out of 10 that GitHub code scanning ranks
+	// security alerts by
+	SecuritySeverity string   `json:"security-severity,omitempty"`
+	Tags             []string `json:"tags"`
+}
+
+type sarifResult struct {
+	RuleID              string                 `json:"ruleId"`
+	RuleIndex           int                    `json:"ruleIndex"`
+	Level               string                 `json:"level"`
+	Message             sarifMessage           `json:"message"`
+	Locations           []sarifLocation        `json:"locations"`
+	PartialFingerprints map[string]string      `json:"partialFingerprints"`
+	Properties          *sarifResultProperties `json:"properties,omitempty"`
+}
+
+// sarifResultProperties are the properties of a result, which are only
+// present when they are known
+type sarifResultProperties struct {
+	// Blame is the commit that last changed the line that pins the package
+	Blame *models.BlameInfo `json:"blame,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndColumn   int `json:"endColumn"`
+}
+
+// sarifLevel maps the severity of a vulnerability onto the levels of SARIF
+func sarifLevel(severity string) string {
+	switch severity {
+	case "CRITICAL", "HIGH":
+		return "error"
+	case "LOW":
+		return "note"
+	default:
+		return "warning"
+	}
+}
+
+// sarifURI returns the path of a source relative to the working directory,
+// which is usually the root of the repository that SARIF consumers expect
+// paths to be relative to, or as a file URI if it is outside of it
+func sarifURI(path string, workingDir string) string {
+	if workingDir != "" {
+		if rel, err := filepath.Rel(workingDir, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return filepath.ToSlash(rel)
+		}
+	}
+
+	if filepath.IsAbs(path) {
+		return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+	}
+
+	return filepath.ToSlash(path)
+}
+
+// groupVulnerabilities returns the vulnerabilities of a package that are in
+// the group
+func groupVulnerabilities(pkg models.PackageVulns, group models.GroupInfo) []models.Vulnerability {
+	var vulns []models.Vulnerability
+	for _, vuln := range pkg.Vulnerabilities {
+		if slices.Contains(group.IDs, vuln.ID) {
+			vulns = append(vulns, vuln)
+		}
+	}
+
+	return vulns
+}
+
+// sarifRuleFor describes a group of vulnerabilities as a rule, which results
+// refer to for the details of the vulnerability they are about
+func sarifRuleFor(pkg models.PackageVulns, group models.GroupInfo, vulns []models.Vulnerability) sarifRule {
+	id := group.IDs[0]
+
+	summary, details := "", ""
+	for _, vuln := range vulns {
+		if summary == "" {
+			summary = vuln.Summary
+		}
+		if details == "" {
+			details = vuln.Details
+		}
+	}
+	if summary == "" {
+		summary = fmt.Sprintf("%s has a known vulnerability", pkg.Package.Name)
+	}
+	if details == "" {
+		details = summary
+	}
+
+	severity := groupSeverity(pkg, group)
+	score := group.Score
+	if score == 0 {
+		score = models.HighestScore(vulns)
+	}
+
+	securitySeverity := ""
+	if score > 0 {
+		securitySeverity = strconv.FormatFloat(score, 'f', 1, 64)
+	}
+
+	helpURI := "https://osv.dev/" + id
+
+	// code scanning shows the CWEs of a rule from its tags
+	tags := []string{"security", "vulnerability"}
+	var relationships []sarifRelationship
+	for _, cwe := range group.CWEs {
+		number := strings.TrimPrefix(cwe, "CWE-")
+		tags = append(tags, "external/cwe/cwe-"+number)
+		relationships = append(relationships, sarifRelationship{
+			Target: sarifReportingDescriptorReference{ID: number, ToolComponent: sarifToolComponentReference{Name: "CWE"}},
+			Kinds:  []string{"superset"},
+		})
+	}
+
+	helpText := fmt.Sprintf("%s\n\nAliases: %s", summary, strings.Join(group.IDs, ", "))
+	helpMarkdown := fmt.Sprintf("**%s**\n\n%s\n\nAliases: %s", summary, details, strings.Join(group.IDs, ", "))
+	if len(group.Exploits) > 0 {
+		helpText += "\n\nExploits: " + strings.Join(group.Exploits, ", ")
+		helpMarkdown += "\n\nExploits: " + strings.Join(group.Exploits, ", ")
+	}
+
+	return sarifRule{
+		ID:               id,
+		ShortDescription: sarifMessage{Text: fmt.Sprintf("%s: %s", id, summary)},
+		FullDescription:  sarifMessage{Text: details},
+		HelpURI:          helpURI,
+		Help: sarifMessage{
+			Text:     fmt.Sprintf("%s\n\nSee %s for more details.", helpText, helpURI),
+			Markdown: fmt.Sprintf("%s\n\nSee [%s](%s) for more details.", helpMarkdown, id, helpURI),
+		},
+		DefaultConfiguration: sarifConfiguration{Level: sarifLevel(severity)},
+		Properties: sarifProperties{
+			SecuritySeverity: securitySeverity,
+			Tags:             tags,
+		},
+		Relationships: relationships,
+	}
+}
+
+// sarifFingerprint identifies a result across scans regardless of the line it
+// is found on or the version of the package, so that code scanning can track
+// it as the lockfile changes, using the same fingerprint as the other formats
+func sarifFingerprint(uri string, pkg models.PackageVulns, group models.GroupInfo) string {
+	if group.Fingerprint != "" {
+		return group.Fingerprint
+	}
+
+	return models.Fingerprint(uri, pkg.Package, group.IDs)
+}
+
+// SARIF converts the results into a SARIF log, with a result for each group
+// of vulnerabilities of each package positioned at its declaration within its
+// lockfile, SBOM or manifest, or at the start of the file if it could not be
+// found
+func SARIF(vulnResult *models.VulnerabilityResults) any {
+	workingDir, err := os.Getwd()
+	if err != nil {
+		workingDir = ""
+	}
+
+	rules := []sarifRule{}
+	ruleIndexes := map[string]int{}
+	results := []sarifResult{}
+	var cwes []sarifTaxon
+
+	for _, sourceRes := range vulnResult.Results {
+		// only files can be pointed at, unlike git directories or images
+		switch sourceRes.Source.Type {
+		case "lockfile", "sbom", "manifest":
+		default:
+			continue
+		}
+
+		uri := sarifURI(sourceRes.Source.Path, workingDir)
+
+		for _, pkg := range sourceRes.Packages {
+			location := models.SourceLocation{Line: 1, Column: 1, EndColumn: 1}
+			if pkg.Location != nil {
+				location = *pkg.Location
+			}
+
+			for _, group := range pkg.Groups {
+				vulns := groupVulnerabilities(pkg, group)
+				rule := sarifRuleFor(pkg, group, vulns)
+
+				index, ok := ruleIndexes[rule.ID]
+				if !ok {
+					index = len(rules)
+					ruleIndexes[rule.ID] = index
+					rules = append(rules, rule)
+
+					for _, relationship := range rule.Relationships {
+						if taxon := (sarifTaxon{ID: relationship.Target.ID}); !slices.Contains(cwes, taxon) {
+							cwes = append(cwes, taxon)
+						}
+					}
+				}
+
+				var fixed []string
+				for _, vuln := range vulns {
+					for _, version := range vuln.FixedVersions(pkg.Package) {
+						if !slices.Contains(fixed, version) {
+							fixed = append(fixed, version)
+						}
+					}
+				}
+
+				message := fmt.Sprintf("%s@%s is affected by %s", pkg.Package.Name, pkg.Package.Version, strings.Join(group.IDs, ", "))
+				if len(fixed) > 0 {
+					message += fmt.Sprintf(" (fixed in %s)", strings.Join(fixed, ", "))
+				}
+
+				var properties *sarifResultProperties
+				if pkg.Blame != nil {
+					properties = &sarifResultProperties{Blame: pkg.Blame}
+				}
+
+				results = append(results, sarifResult{
+					RuleID:    rule.ID,
+					RuleIndex: index,
+					Level:     sarifLevel(groupSeverity(pkg, group)),
+					Message:   sarifMessage{Text: message},
+					Locations: []sarifLocation{{
+						PhysicalLocation: sarifPhysicalLocation{
+							ArtifactLocation: sarifArtifactLocation{URI: uri},
+							Region: sarifRegion{
+								StartLine:   location.Line,
+								StartColumn: location.Column,
+								EndColumn:   location.EndColumn,
+							},
+						},
+					}},
+					PartialFingerprints: map[string]string{
+						"osvScanner/v1": sarifFingerprint(uri, pkg, group),
+					},
+					Properties: properties,
+				})
+			}
+		}
+	}
+
+	var taxonomies []sarifToolComponent
+	if len(cwes) > 0 {
+		taxonomies = append(taxonomies, sarifToolComponent{
+			Name:           "CWE",
+			Organization:   "MITRE",
+			InformationURI: "https://cwe.mitre.org/",
+			Taxa:           cwes,
+		})
+	}
+
+	return sarifLog{
+		Schema:  SARIFSchema,
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "osv-scanner",
+				InformationURI: "https://github.com/google/osv-scanner",
+				Rules:          rules,
+			}},
+			Taxonomies: taxonomies,
+			Results:    results,
+		}},
+	}
+}
+
+// PrintSARIFResults writes the results as a SARIF 2.1.0 log, which can be
+// uploaded to GitHub code scanning and other SARIF consumers
+func PrintSARIFResults(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) error {
+	encoder := json.NewEncoder(outputWriter)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(SARIF(vulnResult))
+}
@@ -0,0 +1,243 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestSARIFURI(t *testing.T) {
+	t.Parallel()
+
+	workingDir := filepath.FromSlash("/repo")
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: filepath.FromSlash("/repo/package-lock.json"), want: "package-lock.json"},
+		{path: filepath.FromSlash("/repo/web/yarn.lock"), want: "web/yarn.lock"},
+		{path: filepath.FromSlash("/repository/go.mod"), want: "file:///repository/go.mod"},
+		{path: filepath.FromSlash("/go.mod"), want: "file:///go.mod"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			if filepath.Separator != '/' && tt.want[0] == 'f' {
+				t.Skip("file URIs of absolute paths depend on the volume")
+			}
+
+			if got := sarifURI(tt.path, workingDir); got != tt.want {
+				t.Errorf("sarifURI() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSARIF(t *testing.T) {
+	t.Parallel()
+
+	var vuln models.Vulnerability
+	err := json.Unmarshal([]byte(`{
+		"id": "GHSA-vh95-rmgr-6w4m",
+		"aliases": ["CVE-2020-7598"],
+		"summary": "Prototype Pollution in minimist",
+		"details": "minimist could be tricked into adding or modifying properties of Object.prototype",
+		"affected": [{
+			"package": {"ecosystem": "npm", "name": "minimist"},
+			"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "0.2.1"}, {"introduced": "1.0.0"}, {"fixed": "1.2.3"}]}]
+		}],
+		"database_specific": {"severity": "MODERATE"}
+	}`), &vuln)
+	if err != nil {
+		t.Fatalf("could not parse vulnerability: %v", err)
+	}
+
+	workingDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("could not get working directory: %v", err)
+	}
+
+	minimist := models.PackageInfo{Name: "minimist", Version: "0.0.8", Ecosystem: "npm"}
+	group := models.GroupInfo{IDs: []string{"GHSA-vh95-rmgr-6w4m", "CVE-2020-7598"}, Score: 5.6, CWEs: []string{"CWE-1321"}}
+	results := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: filepath.Join(workingDir, "package-lock.json"), Type: "lockfile"},
+				Packages: []models.PackageVulns{{
+					Package:         minimist,
+					Vulnerabilities: []models.Vulnerability{vuln},
+					Groups:          []models.GroupInfo{group},
+					Location:        &models.SourceLocation{Line: 12, Column: 6, EndColumn: 14},
+				}},
+			},
+			{
+				Source: models.SourceInfo{Path: filepath.Join(workingDir, "web", "bom.spdx.json"), Type: "sbom"},
+				Packages: []models.PackageVulns{{
+					Package:         minimist,
+					Vulnerabilities: []models.Vulnerability{vuln},
+					Groups:          []models.GroupInfo{group},
+				}},
+			},
+			{
+				Source: models.SourceInfo{Path: workingDir, Type: "git"},
+				Packages: []models.PackageVulns{{
+					Package:         models.PackageInfo{Version: "abc123", Ecosystem: "GIT"},
+					Vulnerabilities: []models.Vulnerability{{ID: "OSV-2020-1"}},
+					Groups:          []models.GroupInfo{{IDs: []string{"OSV-2020-1"}}},
+				}},
+			},
+		},
+	}
+
+	log, ok := SARIF(results).(sarifLog)
+	if !ok {
+		t.Fatalf("SARIF() did not return a sarifLog")
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("SARIF() returned version %q with %d runs, want 2.1.0 with 1", log.Version, len(log.Runs))
+	}
+
+	run := log.Runs[0]
+
+	if len(run.Tool.Driver.Rules) != 1 {
+		t.Fatalf("SARIF() returned %d rules, want 1", len(run.Tool.Driver.Rules))
+	}
+
+	rule := run.Tool.Driver.Rules[0]
+	if rule.ID != "GHSA-vh95-rmgr-6w4m" {
+		t.Errorf("rule ID = %q, want GHSA-vh95-rmgr-6w4m", rule.ID)
+	}
+	if rule.HelpURI != "https://osv.dev/GHSA-vh95-rmgr-6w4m" {
+		t.Errorf("rule helpUri = %q, want https://osv.dev/GHSA-vh95-rmgr-6w4m", rule.HelpURI)
+	}
+	if rule.ShortDescription.Text != "GHSA-vh95-rmgr-6w4m: Prototype Pollution in minimist" {
+		t.Errorf("rule shortDescription = %q", rule.ShortDescription.Text)
+	}
+	if rule.Properties.SecuritySeverity != "5.6" {
+		t.Errorf("rule security-severity = %q, want 5.6", rule.Properties.SecuritySeverity)
+	}
+	if diff := cmp.Diff([]string{"security", "vulnerability", "external/cwe/cwe-1321"}, rule.Properties.Tags); diff != "" {
+		t.Errorf("rule tags mismatch (-want +got):\n%s", diff)
+	}
+	wantRelationships := []sarifRelationship{{
+		Target: sarifReportingDescriptorReference{ID: "1321", ToolComponent: sarifToolComponentReference{Name: "CWE"}},
+		Kinds:  []string{"superset"},
+	}}
+	if diff := cmp.Diff(wantRelationships, rule.Relationships); diff != "" {
+		t.Errorf("rule relationships mismatch (-want +got):\n%s", diff)
+	}
+	if len(run.Taxonomies) != 1 || run.Taxonomies[0].Name != "CWE" || !cmp.Equal(run.Taxonomies[0].Taxa, []sarifTaxon{{ID: "1321"}}) {
+		t.Errorf("SARIF() taxonomies = %+v, want the CWE taxonomy with 1321", run.Taxonomies)
+	}
+
+	type location struct {
+		URI                               string
+		StartLine, StartColumn, EndColumn int
+	}
+	type result struct {
+		RuleID, Level, Message string
+		Location               location
+	}
+
+	var got []result
+	fingerprints := map[string]bool{}
+	for _, r := range run.Results {
+		loc := r.Locations[0].PhysicalLocation
+		got = append(got, result{
+			RuleID:  r.RuleID,
+			Level:   r.Level,
+			Message: r.Message.Text,
+			Location: location{
+				URI:         loc.ArtifactLocation.URI,
+				StartLine:   loc.Region.StartLine,
+				StartColumn: loc.Region.StartColumn,
+				EndColumn:   loc.Region.EndColumn,
+			},
+		})
+		fingerprints[r.PartialFingerprints["osvScanner/v1"]] = true
+	}
+
+	message := "minimist@0.0.8 is affected by GHSA-vh95-rmgr-6w4m, CVE-2020-7598 (fixed in 0.2.1, 1.2.3)"
+	want := []result{
+		{
+			RuleID:   "GHSA-vh95-rmgr-6w4m",
+			Level:    "warning",
+			Message:  message,
+			Location: location{URI: "package-lock.json", StartLine: 12, StartColumn: 6, EndColumn: 14},
+		},
+		{
+			RuleID:   "GHSA-vh95-rmgr-6w4m",
+			Level:    "warning",
+			Message:  message,
+			Location: location{URI: "web/bom.spdx.json", StartLine: 1, StartColumn: 1, EndColumn: 1},
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("SARIF() results mismatch (-want +got):\n%s", diff)
+	}
+
+	if len(fingerprints) != len(want) {
+		t.Errorf("SARIF() results should have distinct fingerprints, got %v", fingerprints)
+	}
+}
+
+func TestSARIF_FingerprintAndBlame(t *testing.T) {
+	t.Parallel()
+
+	workingDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("could not get working directory: %v", err)
+	}
+
+	blame := &models.BlameInfo{Line: 12, Commit: "abc123", Author: "Jane Doe", Email: "jane@example.com", Date: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)}
+	source := models.SourceInfo{Path: filepath.Join(workingDir, "package-lock.json"), Type: "lockfile"}
+	results := func(version string) *models.VulnerabilityResults {
+		return &models.VulnerabilityResults{Results: []models.PackageSource{{
+			Source: source,
+			Packages: []models.PackageVulns{
+				{
+					Package:         models.PackageInfo{Name: "minimist", Version: version, Ecosystem: "npm"},
+					Vulnerabilities: []models.Vulnerability{{ID: "GHSA-1"}},
+					Groups:          []models.GroupInfo{{IDs: []string{"GHSA-1"}, Fingerprint: "finding-1"}},
+					Blame:           blame,
+				},
+				{
+					Package:         models.PackageInfo{Name: "lodash", Version: version, Ecosystem: "npm"},
+					Vulnerabilities: []models.Vulnerability{{ID: "GHSA-2"}},
+					Groups:          []models.GroupInfo{{IDs: []string{"GHSA-2"}}},
+				},
+			},
+		}}}
+	}
+
+	run := SARIF(results("0.0.8")).(sarifLog).Runs[0]
+	updated := SARIF(results("0.0.9")).(sarifLog).Runs[0]
+
+	if got := run.Results[0].PartialFingerprints["osvScanner/v1"]; got != "finding-1" {
+		t.Errorf("expected the fingerprint of the group to be used, got %q", got)
+	}
+	want := models.Fingerprint("package-lock.json", models.PackageInfo{Name: "lodash", Ecosystem: "npm"}, []string{"GHSA-2"})
+	if got := run.Results[1].PartialFingerprints["osvScanner/v1"]; got != want {
+		t.Errorf("expected the fingerprint to be computed without one on the group, got %q, want %q", got, want)
+	}
+	if diff := cmp.Diff(run.Results[1].PartialFingerprints, updated.Results[1].PartialFingerprints); diff != "" {
+		t.Errorf("expected the fingerprint to not change with the version (-old +new):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(&sarifResultProperties{Blame: blame}, run.Results[0].Properties); diff != "" {
+		t.Errorf("properties mismatch (-want +got):\n%s", diff)
+	}
+	if run.Results[1].Properties != nil {
+		t.Errorf("expected no properties without blame, got %+v", run.Results[1].Properties)
+	}
+}
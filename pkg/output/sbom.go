@@ -0,0 +1,238 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/CycloneDX/cyclonedx-go"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// SBOMFormats are the formats that the inventory of a scan can be written in
+var SBOMFormats = []string{"cyclonedx", "spdx"}
+
+// spdxDocument is an SPDX 2.3 document, which is written by hand as the version
+// of tools-golang that is used to read SBOMs drops "filesAnalyzed" when it is
+// false, which SPDX then takes to mean that the files were analyzed
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Creators []string `json:"creators"`
+	Created  string   `json:"created"`
+}
+
+type spdxPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	CopyrightText    string            `json:"copyrightText"`
+	SourceInfo       string            `json:"sourceInfo,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// sbomComponent is a package or commit of the inventory, along with each of
+// the sources that it was found in
+type sbomComponent struct {
+	pkg     models.PackageInfo
+	purl    string
+	commit  string
+	sources []models.SourceInfo
+}
+
+// ref identifies the component within the SBOM, being its package URL where
+// it has one
+func (c sbomComponent) ref() string {
+	switch {
+	case c.purl != "":
+		return c.purl
+	case c.commit != "":
+		return "git:" + c.pkg.Name + "@" + c.commit
+	default:
+		return c.pkg.Ecosystem + ":" + c.pkg.Name + "@" + c.pkg.Version
+	}
+}
+
+// sbomComponents merges the packages of the inventory that were found in
+// several sources, ordered by their ecosystem, name and version
+func sbomComponents(inventory []models.InventoryPackage) []sbomComponent {
+	indexes := map[string]int{}
+	var components []sbomComponent
+
+	for _, item := range inventory {
+		component := sbomComponent{pkg: item.Package, purl: item.PURL, commit: item.Commit}
+
+		i, ok := indexes[component.ref()]
+		if !ok {
+			i = len(components)
+			indexes[component.ref()] = i
+			components = append(components, component)
+		}
+		components[i].sources = append(components[i].sources, item.Source)
+	}
+
+	sort.SliceStable(components, func(a, b int) bool {
+		pa, pb := components[a].pkg, components[b].pkg
+		if pa.Ecosystem != pb.Ecosystem {
+			return pa.Ecosystem < pb.Ecosystem
+		}
+		if pa.Name != pb.Name {
+			return pa.Name < pb.Name
+		}
+		if pa.Version != pb.Version {
+			return pa.Version < pb.Version
+		}
+
+		return components[a].commit < components[b].commit
+	})
+
+	return components
+}
+
+// cycloneDXInventory describes the inventory of the results as a CycloneDX
+// BOM, with the sources that each component was found in as its properties
+func cycloneDXInventory(vulnResult *models.VulnerabilityResults, now time.Time) *cyclonedx.BOM {
+	bom := cyclonedx.NewBOM()
+	bom.Metadata = &cyclonedx.Metadata{
+		Timestamp: now.UTC().Format(time.RFC3339),
+		Tools:     &[]cyclonedx.Tool{{Name: "osv-scanner"}},
+	}
+
+	components := []cyclonedx.Component{}
+	for _, c := range sbomComponents(vulnResult.Inventory) {
+		var properties []cyclonedx.Property
+		if c.purl == "" && c.pkg.Ecosystem != "" {
+			properties = append(properties, cyclonedx.Property{Name: "osv-scanner:ecosystem", Value: c.pkg.Ecosystem})
+		}
+		for _, source := range c.sources {
+			properties = append(properties, cyclonedx.Property{Name: "osv-scanner:source", Value: source.String()})
+		}
+
+		version := c.pkg.Version
+		if c.commit != "" {
+			version = c.commit
+		}
+
+		components = append(components, cyclonedx.Component{
+			BOMRef:     c.ref(),
+			Type:       cyclonedx.ComponentTypeLibrary,
+			Name:       c.pkg.Name,
+			Version:    version,
+			PackageURL: c.purl,
+			Properties: &properties,
+		})
+	}
+	bom.Components = &components
+
+	return bom
+}
+
+// spdxInventory describes the inventory of the results as an SPDX document,
+// with the sources that each package was found in as its source information
+func spdxInventory(vulnResult *models.VulnerabilityResults, now time.Time) spdxDocument {
+	created := now.UTC().Format("2006-01-02T15:04:05Z")
+	components := sbomComponents(vulnResult.Inventory)
+
+	// the namespace has to be unique to each document, so is derived from
+	// what the document describes and when
+	hash := sha256.New()
+	hash.Write([]byte(created))
+	for _, c := range components {
+		hash.Write([]byte("\x00" + c.ref()))
+	}
+
+	doc := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              "osv-scanner-inventory",
+		DocumentNamespace: "https://spdx.org/spdxdocs/osv-scanner-inventory-" + hex.EncodeToString(hash.Sum(nil))[:32],
+		CreationInfo: spdxCreationInfo{
+			Creators: []string{"Tool: osv-scanner"},
+			Created:  created,
+		},
+		Packages:      []spdxPackage{},
+		Relationships: []spdxRelationship{},
+	}
+
+	for i, c := range components {
+		id := fmt.Sprintf("SPDXRef-Package-%d", i+1)
+
+		sources := make([]string, 0, len(c.sources))
+		for _, source := range c.sources {
+			sources = append(sources, source.String())
+		}
+
+		pkg := spdxPackage{
+			Name:             c.pkg.Name,
+			SPDXID:           id,
+			VersionInfo:      c.pkg.Version,
+			DownloadLocation: "NOASSERTION",
+			CopyrightText:    "NOASSERTION",
+			SourceInfo:       "found in " + strings.Join(sources, ", "),
+		}
+		if c.commit != "" {
+			pkg.VersionInfo = c.commit
+		}
+		if c.purl != "" {
+			pkg.ExternalRefs = []spdxExternalRef{
+				{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: c.purl},
+			}
+		}
+
+		doc.Packages = append(doc.Packages, pkg)
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SPDXElementID:      "SPDXRef-DOCUMENT",
+			RelationshipType:   "DESCRIBES",
+			RelatedSPDXElement: id,
+		})
+	}
+
+	return doc
+}
+
+// PrintSBOM writes the inventory of the results as an SBOM in the given format,
+// being either a CycloneDX 1.4 or SPDX 2.3 JSON document
+func PrintSBOM(vulnResult *models.VulnerabilityResults, format string, outputWriter io.Writer) error {
+	switch format {
+	case "cyclonedx":
+		return cyclonedx.NewBOMEncoder(outputWriter, cyclonedx.BOMFileFormatJSON).
+			SetPretty(true).
+			Encode(cycloneDXInventory(vulnResult, time.Now()))
+	case "spdx":
+		encoder := json.NewEncoder(outputWriter)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(spdxInventory(vulnResult, time.Now()))
+	}
+
+	return fmt.Errorf("unsupported SBOM format %q - must be one of: %s", format, strings.Join(SBOMFormats, ", "))
+}
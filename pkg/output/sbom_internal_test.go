@@ -0,0 +1,147 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/internal/sbom"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+var inventoryResults = models.VulnerabilityResults{
+	Inventory: []models.InventoryPackage{
+		{
+			Source:  models.SourceInfo{Path: "/app/package-lock.json", Type: "lockfile"},
+			Package: models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+			PURL:    "pkg:npm/lodash@4.17.20",
+		},
+		{
+			Source:  models.SourceInfo{Path: "/app/web/package-lock.json", Type: "lockfile"},
+			Package: models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+			PURL:    "pkg:npm/lodash@4.17.20",
+		},
+		{
+			Source:  models.SourceInfo{Path: "/app/Gemfile.lock", Type: "lockfile"},
+			Package: models.PackageInfo{Name: "rack", Version: "2.2.3", Ecosystem: "RubyGems"},
+			PURL:    "pkg:gem/rack@2.2.3",
+		},
+		{
+			Source:  models.SourceInfo{Path: "/app/pubspec.lock", Type: "lockfile"},
+			Package: models.PackageInfo{Name: "http", Version: "0.13.5", Ecosystem: "Pub"},
+		},
+		{
+			Source:  models.SourceInfo{Path: "/app/", Type: "git"},
+			Package: models.PackageInfo{Name: "app"},
+			Commit:  "9d1b2f0",
+		},
+	},
+}
+
+func TestSBOMComponents(t *testing.T) {
+	t.Parallel()
+
+	var got []string
+	for _, c := range sbomComponents(inventoryResults.Inventory) {
+		got = append(got, c.ref())
+		if c.ref() == "pkg:npm/lodash@4.17.20" && len(c.sources) != 2 {
+			t.Errorf("expected lodash to have been found in 2 sources, got %v", c.sources)
+		}
+	}
+
+	want := []string{
+		"git:app@9d1b2f0",
+		"Pub:http@0.13.5",
+		"pkg:gem/rack@2.2.3",
+		"pkg:npm/lodash@4.17.20",
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("sbomComponents() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSPDXInventory(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 2, 1, 12, 30, 0, 0, time.UTC)
+	doc := spdxInventory(&inventoryResults, now)
+
+	if doc.CreationInfo.Created != "2023-02-01T12:30:00Z" {
+		t.Errorf("expected the document to have been created at 2023-02-01T12:30:00Z, got %s", doc.CreationInfo.Created)
+	}
+	if other := spdxInventory(&models.VulnerabilityResults{}, now); other.DocumentNamespace == doc.DocumentNamespace {
+		t.Errorf("expected documents of different inventories to have different namespaces")
+	}
+
+	want := spdxPackage{
+		Name:             "lodash",
+		SPDXID:           "SPDXRef-Package-4",
+		VersionInfo:      "4.17.20",
+		DownloadLocation: "NOASSERTION",
+		CopyrightText:    "NOASSERTION",
+		SourceInfo:       "found in lockfile:/app/package-lock.json, lockfile:/app/web/package-lock.json",
+		ExternalRefs: []spdxExternalRef{
+			{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:npm/lodash@4.17.20"},
+		},
+	}
+
+	if len(doc.Packages) != 4 || len(doc.Relationships) != 4 {
+		t.Fatalf("expected 4 packages and relationships, got %d and %d", len(doc.Packages), len(doc.Relationships))
+	}
+	if diff := cmp.Diff(want, doc.Packages[3]); diff != "" {
+		t.Errorf("spdxInventory() mismatch (-want +got):\n%s", diff)
+	}
+	if got := doc.Packages[0].VersionInfo; got != "9d1b2f0" {
+		t.Errorf("expected the version of the git repository to be its commit, got %s", got)
+	}
+}
+
+func TestPrintSBOM(t *testing.T) {
+	t.Parallel()
+
+	readers := map[string]sbom.SBOMReader{
+		"cyclonedx": &sbom.CycloneDX{},
+		"spdx":      &sbom.SPDX{},
+	}
+
+	for _, format := range SBOMFormats {
+		format := format
+		t.Run(format, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if err := PrintSBOM(&inventoryResults, format, &buf); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var purls []string
+			err := readers[format].GetPackages(bytes.NewReader(buf.Bytes()), func(id sbom.Identifier) error {
+				purls = append(purls, id.PURL)
+
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("could not read the SBOM back: %v", err)
+			}
+
+			want := []string{"pkg:gem/rack@2.2.3", "pkg:npm/lodash@4.17.20"}
+			if diff := cmp.Diff(want, purls); diff != "" {
+				t.Errorf("PrintSBOM() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPrintSBOM_UnsupportedFormat(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	err := PrintSBOM(&inventoryResults, "swid", &buf)
+
+	want := `unsupported SBOM format "swid" - must be one of: cyclonedx, spdx`
+	if err == nil || err.Error() != want {
+		t.Errorf("expected error %q, got %v", want, err)
+	}
+}
@@ -0,0 +1,60 @@
+package output
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/google/osv-scanner/pkg/models"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// scorecardsTableBuilder adds a row with the overall and maintenance scores of
+// each package that has a scorecard
+func scorecardsTableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults) table.Writer {
+	workingDir, workingDirErr := os.Getwd()
+	for _, pkg := range vulnResult.Scorecards {
+		sourcePath := pkg.Source.Path
+		if workingDirErr == nil {
+			if rel, err := filepath.Rel(workingDir, sourcePath); err == nil {
+				sourcePath = rel
+			}
+		}
+
+		// inconclusive checks are left blank
+		maintained := ""
+		if check, ok := pkg.Scorecard.Check("Maintained"); ok && check.Score >= 0 {
+			maintained = strconv.Itoa(check.Score)
+		}
+
+		outputTable.AppendRow(table.Row{
+			pkg.Package.Ecosystem,
+			pkg.Package.Name,
+			pkg.Package.Version,
+			pkg.Scorecard.Project,
+			strconv.FormatFloat(pkg.Scorecard.OverallScore, 'f', 1, 64),
+			maintained,
+			sourcePath,
+		})
+	}
+
+	return outputTable
+}
+
+// printScorecardsTable prints the scorecards of the direct dependencies, if
+// scorecards were fetched and any were found
+func printScorecardsTable(vulnResult *models.VulnerabilityResults, outputWriter io.Writer, locale Locale, style func(table.Writer)) {
+	outputTable := table.NewWriter()
+	outputTable.SetOutputMirror(outputWriter)
+	outputTable.AppendHeader(localizedRow(locale, "Ecosystem", "Package", "Version", "Repository", "Scorecard", "Maintained", "Source"))
+	style(outputTable)
+
+	outputTable = scorecardsTableBuilder(outputTable, vulnResult)
+
+	if outputTable.Length() == 0 {
+		return
+	}
+	outputTable.Render()
+}
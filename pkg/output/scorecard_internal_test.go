@@ -0,0 +1,34 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestPrintTableResults_Scorecards(t *testing.T) {
+	t.Parallel()
+
+	results := &models.VulnerabilityResults{
+		Results: []models.PackageSource{},
+		Scorecards: []models.PackageScorecard{{
+			Source:  models.SourceInfo{Path: "package-lock.json", Type: "lockfile"},
+			Package: models.PackageInfo{Name: "react", Version: "18.2.0", Ecosystem: "npm"},
+			Scorecard: models.Scorecard{
+				Project:      "github.com/facebook/react",
+				OverallScore: 7.4,
+				Checks:       []models.ScorecardCheck{{Name: "Maintained", Score: 10}},
+			},
+		}},
+	}
+
+	var out strings.Builder
+	printTableResults(results, &out, DefaultLocale, ColorNever, Themes[DefaultThemeName])
+
+	for _, want := range []string{"SCORECARD", "MAINTAINED", "github.com/facebook/react", "7.4", "10"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected the scorecards table to include %q, got:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package output
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/models"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// suspiciousPackagesTableBuilder adds a row for each package that is known or
+// likely to be malicious
+func suspiciousPackagesTableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults, locale Locale) table.Writer {
+	workingDir, workingDirErr := os.Getwd()
+	for _, pkg := range vulnResult.SuspiciousPackages {
+		sourcePath := pkg.Source.Path
+		if workingDirErr == nil {
+			if rel, err := filepath.Rel(workingDir, sourcePath); err == nil {
+				sourcePath = rel
+			}
+		}
+
+		detail := strings.Join(pkg.IDs, "\n")
+		if pkg.SimilarTo != "" {
+			detail = locale.Sprintf("similar to %s", pkg.SimilarTo)
+		}
+
+		outputTable.AppendRow(table.Row{
+			pkg.Package.Ecosystem,
+			pkg.Package.Name,
+			pkg.Package.Version,
+			pkg.Kind,
+			detail,
+			sourcePath,
+		})
+	}
+
+	return outputTable
+}
+
+// printSuspiciousPackagesTable prints the packages that have been reported as
+// malicious or are likely typosquats, if any were found
+func printSuspiciousPackagesTable(vulnResult *models.VulnerabilityResults, outputWriter io.Writer, locale Locale, style func(table.Writer)) {
+	outputTable := table.NewWriter()
+	outputTable.SetOutputMirror(outputWriter)
+	outputTable.AppendHeader(localizedRow(locale, "Ecosystem", "Package", "Version", "Suspicious Package", "Detail", "Source"))
+	style(outputTable)
+
+	outputTable = suspiciousPackagesTableBuilder(outputTable, vulnResult, locale)
+
+	if outputTable.Length() == 0 {
+		return
+	}
+	outputTable.Render()
+}
@@ -0,0 +1,46 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestPrintTableResults_SuspiciousPackages(t *testing.T) {
+	t.Parallel()
+
+	results := &models.VulnerabilityResults{
+		Results: []models.PackageSource{},
+		SuspiciousPackages: []models.SuspiciousPackage{
+			{
+				Source:  models.SourceInfo{Path: "package-lock.json", Type: "lockfile"},
+				Package: models.PackageInfo{Name: "lodahs", Version: "1.0.0", Ecosystem: "npm"},
+				Kind:    "malicious",
+				IDs:     []string{"MAL-2023-1"},
+			},
+			{
+				Source:    models.SourceInfo{Path: "package-lock.json", Type: "lockfile"},
+				Package:   models.PackageInfo{Name: "lodahs", Version: "1.0.0", Ecosystem: "npm"},
+				Kind:      "typosquat",
+				SimilarTo: "lodash",
+			},
+		},
+	}
+
+	var out strings.Builder
+	printTableResults(results, &out, DefaultLocale, ColorNever, Themes[DefaultThemeName])
+
+	for _, want := range []string{"SUSPICIOUS PACKAGE", "lodahs", "malicious", "MAL-2023-1", "typosquat", "similar to lodash"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected the suspicious packages table to include %q, got:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	printTableResults(results, &out, "fr", ColorNever, Themes[DefaultThemeName])
+
+	if !strings.Contains(out.String(), "similaire à lodash") {
+		t.Errorf("expected the detail to be translated, got:\n%s", out.String())
+	}
+}
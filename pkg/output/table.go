@@ -1,6 +1,7 @@
 package output
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -10,36 +11,79 @@ import (
 	"github.com/google/osv-scanner/pkg/osv"
 
 	"github.com/jedib0t/go-pretty/v6/table"
-	"github.com/jedib0t/go-pretty/v6/text"
 	"golang.org/x/term"
 )
 
 // PrintTableResults prints the osv scan results into a human friendly table.
 func PrintTableResults(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) {
+	printTableResults(vulnResult, outputWriter, DefaultLocale, ColorAuto, Themes[DefaultThemeName])
+}
+
+func printTableResults(vulnResult *models.VulnerabilityResults, outputWriter io.Writer, locale Locale, colorMode ColorMode, theme Theme) {
 	outputTable := table.NewWriter()
 	outputTable.SetOutputMirror(outputWriter)
-	outputTable.AppendHeader(table.Row{"OSV URL (ID In Bold)", "Ecosystem", "Package", "Version", "Source"})
+	outputTable.AppendHeader(localizedRow(locale, "OSV URL (ID In Bold)", "Ecosystem", "Package", "Version", "Source"))
+
+	style, colored := tableStyle(colorMode, theme)
+	style(outputTable)
+
+	var idTheme *Theme
+	if colored {
+		idTheme = &theme
+	}
+	outputTable = tableBuilder(outputTable, vulnResult, idTheme, false)
+
+	if outputTable.Length() != 0 {
+		outputTable.Render()
+
+		printWorkspaceMembersTable(vulnResult, outputWriter, locale, style)
+	}
+
+	printRegistryIssuesTable(vulnResult, outputWriter, locale, style)
+	printLicenseViolationsTable(vulnResult, outputWriter, locale, style)
+	printSuspiciousPackagesTable(vulnResult, outputWriter, locale, style)
+	printOutdatedPackagesTable(vulnResult, outputWriter, locale, style)
+	printDeprecatedPackagesTable(vulnResult, outputWriter, locale, style)
+	printScorecardsTable(vulnResult, outputWriter, locale, style)
+	printUpgradesTable(vulnResult, outputWriter, locale, style)
+	printReachabilityTable(vulnResult, outputWriter, locale, style)
+	printHistoryTable(vulnResult, outputWriter, locale, style)
+}
 
+// tableStyle returns a function that styles tables for the terminal they are
+// written to, along with whether they are colored with the theme
+func tableStyle(colorMode ColorMode, theme Theme) (func(table.Writer), bool) {
 	width, _, err := term.GetSize(int(os.Stdout.Fd()))
-	isTerminal := false
-	if err == nil { // If output is a terminal, set max length to width and add styling
-		outputTable.SetStyle(table.StyleRounded)
-		outputTable.Style().Color.Row = text.Colors{text.Reset, text.BgHiBlack}
-		outputTable.Style().Color.RowAlternate = text.Colors{text.Reset, text.BgBlack}
-		outputTable.Style().Options.DoNotColorBordersAndSeparators = true
-		outputTable.SetAllowedRowLength(width)
-		isTerminal = true
-	} // Otherwise use default ascii (e.g. getting piped to a file)
-
-	outputTable = tableBuilder(outputTable, vulnResult, isTerminal)
-
-	if outputTable.Length() == 0 {
-		return
+	isTerminal := err == nil
+	colored := shouldColor(colorMode, isTerminal)
+
+	return func(outputTable table.Writer) {
+		if isTerminal { // If output is a terminal, set max length to width and add styling
+			outputTable.SetStyle(table.StyleRounded)
+			outputTable.SetAllowedRowLength(width)
+		} // Otherwise use default ascii (e.g. getting piped to a file)
+		if colored {
+			outputTable.Style().Color.Row = theme.Row
+			outputTable.Style().Color.RowAlternate = theme.RowAlternate
+			outputTable.Style().Options.DoNotColorBordersAndSeparators = true
+		}
+	}, colored
+}
+
+// localizedRow translates each of the given headers into the locale
+func localizedRow(locale Locale, headers ...string) table.Row {
+	row := make(table.Row, 0, len(headers))
+	for _, header := range headers {
+		row = append(row, locale.Sprintf(header))
 	}
-	outputTable.Render()
+
+	return row
 }
 
-func tableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults, addStyling bool) table.Writer {
+// tableBuilder adds a row for each group of vulnerabilities, coloring their IDs
+// according to their severity in the given theme unless it is nil, and ending
+// with a column of the owners of their package if `withOwners` is set
+func tableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults, theme *Theme, withOwners bool) table.Writer {
 	// Working directory used to simplify path
 	workingDir, workingDirErr := os.Getwd()
 	for _, sourceRes := range vulnResult.Results {
@@ -59,14 +103,27 @@ func tableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResu
 
 				var links []string
 
+				severity := groupSeverity(pkg, group)
 				for _, vuln := range group.IDs {
-					if addStyling {
-						links = append(links, osv.BaseVulnerabilityURL+text.Bold.EscapeSeq()+vuln+text.Reset.EscapeSeq())
+					if theme != nil {
+						links = append(links, osv.BaseVulnerabilityURL+theme.colorID(vuln, severity))
 					} else {
 						links = append(links, osv.BaseVulnerabilityURL+vuln)
 					}
 				}
 
+				// withdrawn advisories are only reported when asked to be
+				if group.Withdrawn {
+					links = append(links, "(withdrawn)")
+				}
+
+				if symbols := affectedSymbolsSummary(group.AffectedSymbols); symbols != "" {
+					links = append(links, symbols)
+				}
+				if len(group.SymbolUsages) > 0 {
+					links = append(links, symbolUsagesSummary(group.SymbolUsages, workingDir, workingDirErr == nil))
+				}
+
 				outputRow = append(outputRow, strings.Join(links, "\n"))
 
 				if pkg.Package.Ecosystem == "GIT" {
@@ -77,6 +134,9 @@ func tableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResu
 				}
 
 				outputRow = append(outputRow, source.Path)
+				if withOwners {
+					outputRow = append(outputRow, strings.Join(pkg.Owners, "\n"))
+				}
 				outputTable.AppendRow(outputRow, table.RowConfig{AutoMerge: shouldMerge})
 			}
 		}
@@ -84,3 +144,39 @@ func tableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResu
 
 	return outputTable
 }
+
+// maxTableSymbols is how many affected symbols of a group are listed in the
+// table, with the rest being counted
+const maxTableSymbols = 3
+
+// affectedSymbolsSummary lists the affected symbols of a group for the table,
+// so that whether the vulnerable code is used can be judged at a glance
+func affectedSymbolsSummary(symbols []string) string {
+	if len(symbols) == 0 {
+		return ""
+	}
+
+	if len(symbols) <= maxTableSymbols {
+		return "affects: " + strings.Join(symbols, ", ")
+	}
+
+	return fmt.Sprintf("affects: %s and %d more", strings.Join(symbols[:maxTableSymbols], ", "), len(symbols)-maxTableSymbols)
+}
+
+// symbolUsagesSummary gives where the first of the usages of the affected
+// symbols of a group is, relative to the working directory if it is known
+func symbolUsagesSummary(usages []models.SymbolUsage, workingDir string, relative bool) string {
+	path := usages[0].Path
+	if relative {
+		if rel, err := filepath.Rel(workingDir, path); err == nil {
+			path = rel
+		}
+	}
+
+	summary := fmt.Sprintf("used at %s:%d", path, usages[0].Line)
+	if len(usages) > 1 {
+		summary += fmt.Sprintf(" and %d more", len(usages)-1)
+	}
+
+	return summary
+}
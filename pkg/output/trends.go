@@ -0,0 +1,33 @@
+package output
+
+import (
+	"io"
+	"time"
+
+	"github.com/google/osv-scanner/pkg/store"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// PrintTrendsTable prints how the findings of a repository changed over time
+// into a human friendly table.
+func PrintTrendsTable(trends []store.Trend, outputWriter io.Writer) {
+	outputTable := table.NewWriter()
+	outputTable.SetOutputMirror(outputWriter)
+	outputTable.AppendHeader(table.Row{"Scanned At", "New", "Fixed", "Ongoing", "Total"})
+
+	for _, trend := range trends {
+		outputTable.AppendRow(table.Row{
+			trend.Time.Format(time.RFC3339),
+			trend.New,
+			trend.Fixed,
+			trend.Ongoing,
+			trend.New + trend.Ongoing,
+		})
+	}
+
+	if outputTable.Length() == 0 {
+		return
+	}
+	outputTable.Render()
+}
@@ -0,0 +1,85 @@
+package output
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/models"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// dependencyChangesSummary summarises how the dependencies of a package change
+// when it is upgraded, such as "3 (1 major)"
+func dependencyChangesSummary(upgrade models.UpgradeImpact, locale Locale) string {
+	if !upgrade.DependenciesKnown {
+		return locale.Sprintf("unknown")
+	}
+
+	if len(upgrade.Changes) == 0 {
+		return locale.Sprintf("none")
+	}
+
+	major := 0
+	for _, change := range upgrade.Changes {
+		if change.Bump == "major" {
+			major++
+		}
+	}
+
+	if major > 0 {
+		return locale.Sprintf("%d (%d major)", len(upgrade.Changes), major)
+	}
+
+	return locale.Sprintf("%d", len(upgrade.Changes))
+}
+
+// upgradesTableBuilder adds a row for each vulnerable package that can be
+// upgraded to fix its vulnerabilities
+func upgradesTableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults, locale Locale) table.Writer {
+	workingDir, workingDirErr := os.Getwd()
+	for _, upgrade := range vulnResult.Upgrades {
+		sourcePath := upgrade.Source.Path
+		if workingDirErr == nil {
+			if rel, err := filepath.Rel(workingDir, sourcePath); err == nil {
+				sourcePath = rel
+			}
+		}
+
+		bump := upgrade.Bump
+		if upgrade.Breaking() {
+			bump += " " + locale.Sprintf("(breaking)")
+		}
+
+		outputTable.AppendRow(table.Row{
+			upgrade.Package.Ecosystem,
+			upgrade.Package.Name,
+			upgrade.Package.Version,
+			upgrade.FixedVersion,
+			bump,
+			dependencyChangesSummary(upgrade, locale),
+			strings.Join(upgrade.Fixes, "\n"),
+			sourcePath,
+		})
+	}
+
+	return outputTable
+}
+
+// printUpgradesTable prints what upgrading each vulnerable package to its
+// suggested fix involves, if upgrades were analyzed and any can be
+func printUpgradesTable(vulnResult *models.VulnerabilityResults, outputWriter io.Writer, locale Locale, style func(table.Writer)) {
+	outputTable := table.NewWriter()
+	outputTable.SetOutputMirror(outputWriter)
+	outputTable.AppendHeader(localizedRow(locale, "Ecosystem", "Package", "Version", "Fixed Version", "Upgrade", "Dependency Changes", "Fixes", "Source"))
+	style(outputTable)
+
+	outputTable = upgradesTableBuilder(outputTable, vulnResult, locale)
+
+	if outputTable.Length() == 0 {
+		return
+	}
+	outputTable.Render()
+}
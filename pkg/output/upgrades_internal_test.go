@@ -0,0 +1,47 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestPrintTableResults_Upgrades(t *testing.T) {
+	t.Parallel()
+
+	lockfile := models.SourceInfo{Path: "package-lock.json", Type: "lockfile"}
+	results := &models.VulnerabilityResults{
+		Results: []models.PackageSource{},
+		Upgrades: []models.UpgradeImpact{
+			{
+				Source:            lockfile,
+				Package:           models.PackageInfo{Name: "express", Version: "4.17.0", Ecosystem: "npm"},
+				FixedVersion:      "4.17.3",
+				Bump:              "patch",
+				Fixes:             []string{"GHSA-rv95-896h-c2vc"},
+				DependenciesKnown: true,
+				Changes: []models.DependencyChange{
+					{Name: "body-parser", Ecosystem: "npm", From: "2.0.0", To: "3.0.0", Bump: "major"},
+					{Name: "qs", Ecosystem: "npm", From: "6.7.0", To: "6.9.7", Bump: "minor"},
+				},
+			},
+			{
+				Source:       lockfile,
+				Package:      models.PackageInfo{Name: "minimist", Version: "1.2.0", Ecosystem: "npm"},
+				FixedVersion: "1.2.6",
+				Bump:         "patch",
+				Fixes:        []string{"GHSA-xvch-5gv4-984h"},
+			},
+		},
+	}
+
+	var out strings.Builder
+	printTableResults(results, &out, DefaultLocale, ColorNever, Themes[DefaultThemeName])
+
+	for _, want := range []string{"FIXED VERSION", "DEPENDENCY CHANGES", "4.17.3", "patch (breaking)", "2 (1 major)", "unknown", "GHSA-xvch-5gv4-984h"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected the upgrades table to include %q, got:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/models"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+type workspaceMemberSummary struct {
+	member   models.WorkspaceMember
+	packages map[string]bool
+	vulns    int
+}
+
+// summariseWorkspaceMembers counts the vulnerable packages and vulnerability
+// groups attributed to each workspace member
+func summariseWorkspaceMembers(vulnResult *models.VulnerabilityResults) []workspaceMemberSummary {
+	summaries := map[string]*workspaceMemberSummary{}
+
+	for _, source := range vulnResult.Results {
+		for _, pkg := range source.Packages {
+			for _, member := range pkg.WorkspaceMembers {
+				key := member.Workspace + ":" + member.Path
+				summary, ok := summaries[key]
+				if !ok {
+					summary = &workspaceMemberSummary{member: member, packages: map[string]bool{}}
+					summaries[key] = summary
+				}
+
+				summary.packages[pkg.Package.Name+"@"+pkg.Package.Version] = true
+				summary.vulns += len(pkg.Groups)
+			}
+		}
+	}
+
+	result := make([]workspaceMemberSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		result = append(result, *summary)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].member.Workspace != result[j].member.Workspace {
+			return result[i].member.Workspace < result[j].member.Workspace
+		}
+
+		return result[i].member.Path < result[j].member.Path
+	})
+
+	return result
+}
+
+// workspaceMembersTableBuilder adds a row for each workspace member that has
+// vulnerable packages attributed to it
+func workspaceMembersTableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults) table.Writer {
+	for _, summary := range summariseWorkspaceMembers(vulnResult) {
+		outputTable.AppendRow(table.Row{
+			fmt.Sprintf("%s (%s)", summary.member.Name, summary.member.Path),
+			summary.member.Workspace,
+			strings.Join(summary.member.Owners, "\n"),
+			len(summary.packages),
+			summary.vulns,
+		})
+	}
+
+	return outputTable
+}
+
+// printWorkspaceMembersTable prints the vulnerabilities attributed to each
+// member of any monorepo workspaces, if there are any
+func printWorkspaceMembersTable(vulnResult *models.VulnerabilityResults, outputWriter io.Writer, locale Locale, style func(table.Writer)) {
+	outputTable := table.NewWriter()
+	outputTable.SetOutputMirror(outputWriter)
+	outputTable.AppendHeader(localizedRow(locale, "Workspace Member", "Workspace", "Owners", "Packages", "Vulnerabilities"))
+	style(outputTable)
+
+	outputTable = workspaceMembersTableBuilder(outputTable, vulnResult)
+
+	if outputTable.Length() == 0 {
+		return
+	}
+	outputTable.Render()
+}
@@ -0,0 +1,70 @@
+package publisher
+
+import (
+	"context"
+	"fmt"
+	"html"
+
+	"github.com/google/osv-scanner/pkg/azuredevops"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+)
+
+// AzureDevOpsWorkItems publishes results by creating a work item for each new
+// vulnerability, unless there is already an open work item for it
+type AzureDevOpsWorkItems struct {
+	Client *azuredevops.Client
+	// WorkItemType is the type of work item to create, such as "Bug" or "Issue"
+	WorkItemType string
+}
+
+var _ Publisher = AzureDevOpsWorkItems{}
+
+func (p AzureDevOpsWorkItems) Publish(ctx context.Context, results models.VulnerabilityResults, base *models.VulnerabilityResults) error {
+	newVulns := NewVulnerabilities(results, base)
+
+	for _, source := range newVulns.Results {
+		for _, pkg := range source.Packages {
+			for _, group := range pkg.Groups {
+				// the version is left out of the title so that bumping to another
+				// vulnerable version does not result in a duplicate work item
+				title := fmt.Sprintf("%s in %s (%s)", group.IDs[0], pkg.Package.Name, pkg.Package.Ecosystem)
+
+				exists, err := p.Client.HasOpenWorkItem(ctx, title)
+				if err != nil {
+					//nolint:wrapcheck
+					return err
+				}
+
+				if exists {
+					continue
+				}
+
+				vuln := findVulnerability(pkg.Vulnerabilities, group.IDs)
+
+				description := fmt.Sprintf(
+					"<p>%s</p><p>%s@%s was found in <code>%s</code>.</p><p><a href=\"%s\">%s</a></p>",
+					html.EscapeString(vuln.Summary),
+					html.EscapeString(pkg.Package.Name),
+					html.EscapeString(pkg.Package.Version),
+					html.EscapeString(source.Source.Path),
+					osv.BaseVulnerabilityURL+group.IDs[0],
+					osv.BaseVulnerabilityURL+group.IDs[0],
+				)
+
+				err = p.Client.CreateWorkItem(ctx, azuredevops.WorkItem{
+					Type:        p.WorkItemType,
+					Title:       title,
+					Description: description,
+					Tags:        []string{"osv-scanner"},
+				})
+				if err != nil {
+					//nolint:wrapcheck
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
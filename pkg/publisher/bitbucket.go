@@ -0,0 +1,147 @@
+package publisher
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/bitbucket"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+)
+
+// BitbucketReportID identifies the Code Insights report created by osv-scanner
+const BitbucketReportID = "osv-scanner"
+
+// maxAnnotationSummaryLength is the longest summary Bitbucket accepts
+const maxAnnotationSummaryLength = 450
+
+// BitbucketCodeInsights publishes results as a Code Insights report on a
+// commit, with an annotation for each new vulnerability
+type BitbucketCodeInsights struct {
+	Client *bitbucket.Client
+	// Owner is the workspace (Bitbucket Cloud) or project key (Bitbucket Server)
+	Owner  string
+	Repo   string
+	Commit string
+}
+
+var _ Publisher = BitbucketCodeInsights{}
+
+func (p BitbucketCodeInsights) Publish(ctx context.Context, results models.VulnerabilityResults, base *models.VulnerabilityResults) error {
+	newVulns := NewVulnerabilities(results, base)
+	newCount := countGroups(newVulns)
+
+	details := fmt.Sprintf("Found %d %s.", newCount, pluralise(newCount))
+	if base != nil {
+		details = fmt.Sprintf("Found %d new %s compared to the base branch.", newCount, pluralise(newCount))
+	}
+
+	report := bitbucket.Report{
+		Title:   "OSV-Scanner",
+		Details: details,
+		Passed:  newCount == 0,
+	}
+
+	//nolint:wrapcheck
+	return p.Client.PublishReport(ctx, p.Owner, p.Repo, p.Commit, BitbucketReportID, report, bitbucketAnnotations(newVulns))
+}
+
+func bitbucketAnnotations(results models.VulnerabilityResults) []bitbucket.Annotation {
+	var annotations []bitbucket.Annotation
+
+	workingDir, workingDirErr := os.Getwd()
+
+	for _, source := range results.Results {
+		path := source.Source.Path
+		if workingDirErr == nil {
+			if rel, err := filepath.Rel(workingDir, path); err == nil {
+				path = filepath.ToSlash(rel)
+			}
+		}
+
+		var content []byte
+		if source.Source.Type == "lockfile" {
+			// the line is only a nicety, so it's fine if the file can't be read
+			content, _ = os.ReadFile(source.Source.Path)
+		}
+
+		for _, pkg := range source.Packages {
+			var line int
+			if pkg.Location != nil {
+				line = pkg.Location.Line
+			} else {
+				line = findPackageLine(content, pkg.Package.Name)
+			}
+
+			for _, group := range pkg.Groups {
+				vuln := findVulnerability(pkg.Vulnerabilities, group.IDs)
+
+				summary := fmt.Sprintf("%s@%s is affected by %s", pkg.Package.Name, pkg.Package.Version, strings.Join(group.IDs, ", "))
+				if vuln.Summary != "" {
+					summary += ": " + vuln.Summary
+				}
+				if len(summary) > maxAnnotationSummaryLength {
+					summary = summary[:maxAnnotationSummaryLength-3] + "..."
+				}
+
+				externalID := group.Fingerprint
+				if externalID == "" {
+					externalID = fmt.Sprintf("%s:%s:%s", path, pkg.Package.Name, group.IDs[0])
+				}
+
+				annotations = append(annotations, bitbucket.Annotation{
+					ExternalID: externalID,
+					Path:       path,
+					Line:       line,
+					Summary:    summary,
+					Severity:   severityOf(vuln),
+					Link:       osv.BaseVulnerabilityURL + group.IDs[0],
+				})
+			}
+		}
+	}
+
+	return annotations
+}
+
+// findVulnerability returns the first vulnerability with one of the given ids
+func findVulnerability(vulns []models.Vulnerability, ids []string) models.Vulnerability {
+	for _, vuln := range vulns {
+		for _, id := range ids {
+			if vuln.ID == id {
+				return vuln
+			}
+		}
+	}
+
+	return models.Vulnerability{}
+}
+
+// findPackageLine returns the first line in content that mentions the given
+// package name, or 0 if there is no such line
+func findPackageLine(content []byte, name string) int {
+	if name == "" {
+		return 0
+	}
+
+	for i, line := range strings.Split(string(content), "\n") {
+		if strings.Contains(line, name) {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+// severityOf returns the severity of a vulnerability as reported by its
+// database, defaulting to MEDIUM if it is unknown
+func severityOf(vuln models.Vulnerability) string {
+	if severity := vuln.Severity(); severity != "" {
+		return severity
+	}
+
+	return "MEDIUM"
+}
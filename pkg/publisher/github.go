@@ -0,0 +1,23 @@
+package publisher
+
+import (
+	"context"
+	"github.com/google/osv-scanner/pkg/github"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// GitHubPullRequest publishes results as a single comment on a pull request,
+// which is updated in place by subsequent scans
+type GitHubPullRequest struct {
+	Client *github.Client
+	Owner  string
+	Repo   string
+	Number int
+}
+
+var _ Publisher = GitHubPullRequest{}
+
+func (p GitHubPullRequest) Publish(ctx context.Context, results models.VulnerabilityResults, base *models.VulnerabilityResults) error {
+	//nolint:wrapcheck
+	return p.Client.UpsertIssueComment(ctx, p.Owner, p.Repo, p.Number, CommentMarker, MarkdownSummary(results, base))
+}
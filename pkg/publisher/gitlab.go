@@ -0,0 +1,23 @@
+package publisher
+
+import (
+	"context"
+	"github.com/google/osv-scanner/pkg/gitlab"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// GitLabMergeRequest publishes results as a single note on a merge request,
+// which is updated in place by subsequent scans
+type GitLabMergeRequest struct {
+	Client *gitlab.Client
+	// Project is the numeric id or full path of the project
+	Project string
+	IID     int
+}
+
+var _ Publisher = GitLabMergeRequest{}
+
+func (p GitLabMergeRequest) Publish(ctx context.Context, results models.VulnerabilityResults, base *models.VulnerabilityResults) error {
+	//nolint:wrapcheck
+	return p.Client.UpsertMergeRequestNote(ctx, p.Project, p.IID, CommentMarker, MarkdownSummary(results, base))
+}
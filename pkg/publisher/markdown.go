@@ -0,0 +1,73 @@
+package publisher
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+// CommentMarker is included in published comments so that they can be found
+// and updated by later scans, rather than a new comment being added each time
+const CommentMarker = "<!-- osv-scanner-results -->"
+
+// countGroups returns the number of distinct vulnerabilities in the results,
+// counting vulnerabilities that are aliases of each other once
+func countGroups(results models.VulnerabilityResults) int {
+	count := 0
+	for _, source := range results.Results {
+		for _, pkg := range source.Packages {
+			count += len(pkg.Groups)
+		}
+	}
+
+	return count
+}
+
+// MarkdownSummary renders a Markdown summary of the vulnerabilities that are
+// new compared to `base`, suitable for posting as a comment
+func MarkdownSummary(results models.VulnerabilityResults, base *models.VulnerabilityResults) string {
+	newVulns := NewVulnerabilities(results, base)
+	newCount := countGroups(newVulns)
+	totalCount := countGroups(results)
+
+	sb := strings.Builder{}
+	sb.WriteString(CommentMarker + "\n")
+	sb.WriteString("## OSV-Scanner results\n\n")
+
+	switch {
+	case newCount == 0 && base != nil:
+		sb.WriteString("No new vulnerabilities were found compared to the base branch.\n")
+	case newCount == 0:
+		sb.WriteString("No vulnerabilities were found.\n")
+	case base != nil:
+		sb.WriteString(fmt.Sprintf("Found %d new %s compared to the base branch:\n\n", newCount, pluralise(newCount)))
+		output.PrintMarkdownTableResults(&newVulns, &sb)
+	default:
+		sb.WriteString(fmt.Sprintf("Found %d %s:\n\n", newCount, pluralise(newCount)))
+		output.PrintMarkdownTableResults(&newVulns, &sb)
+	}
+
+	if base != nil && totalCount > newCount {
+		sb.WriteString(fmt.Sprintf("\n%d %s already present on the base branch %s not shown.\n", totalCount-newCount, pluralise(totalCount-newCount), isOrAre(totalCount-newCount)))
+	}
+
+	return sb.String()
+}
+
+func pluralise(count int) string {
+	if count == 1 {
+		return "vulnerability"
+	}
+
+	return "vulnerabilities"
+}
+
+func isOrAre(count int) string {
+	if count == 1 {
+		return "is"
+	}
+
+	return "are"
+}
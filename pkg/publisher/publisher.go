@@ -0,0 +1,49 @@
+// Package publisher posts the results of a scan to code hosting and CI
+// systems, so that findings show up where changes are reviewed
+package publisher
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/output"
+)
+
+// Publisher publishes the results of a scan to an external system
+type Publisher interface {
+	// Publish the given results, highlighting the vulnerabilities that are not
+	// present in `base` (which may be nil if there is nothing to compare to),
+	// abandoning the requests to the system once ctx is done
+	Publish(ctx context.Context, results models.VulnerabilityResults, base *models.VulnerabilityResults) error
+}
+
+// LoadResults reads the JSON output of a previous scan, such as one of the
+// base branch of a pull request
+func LoadResults(path string) (models.VulnerabilityResults, error) {
+	var results models.VulnerabilityResults
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return results, fmt.Errorf("could not read results: %w", err)
+	}
+
+	parsed, err := output.ParseJSONResults(content)
+	if err != nil {
+		return results, fmt.Errorf("could not parse results from %s: %w", path, err)
+	}
+
+	return parsed.VulnerabilityResults, nil
+}
+
+// NewVulnerabilities returns the results with any vulnerabilities that are
+// also present in `base` removed, dropping packages and sources that are left
+// with no vulnerabilities. If base is nil, the results are returned as-is.
+func NewVulnerabilities(results models.VulnerabilityResults, base *models.VulnerabilityResults) models.VulnerabilityResults {
+	if base == nil {
+		return results
+	}
+
+	return models.VulnerabilityResults{Results: results.NewSince(base)}
+}
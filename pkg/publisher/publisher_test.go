@@ -0,0 +1,97 @@
+package publisher_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/publisher"
+)
+
+func packageVulns(name string, version string, ids ...string) models.PackageVulns {
+	pkg := models.PackageVulns{
+		Package: models.PackageInfo{Name: name, Version: version, Ecosystem: "npm"},
+	}
+
+	for _, id := range ids {
+		pkg.Vulnerabilities = append(pkg.Vulnerabilities, models.Vulnerability{ID: id})
+		pkg.Groups = append(pkg.Groups, models.GroupInfo{IDs: []string{id}})
+	}
+
+	return pkg
+}
+
+func results(packages ...models.PackageVulns) models.VulnerabilityResults {
+	return models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source:   models.SourceInfo{Path: "/path/to/package-lock.json", Type: "lockfile"},
+				Packages: packages,
+			},
+		},
+	}
+}
+
+func TestNewVulnerabilities(t *testing.T) {
+	t.Parallel()
+
+	current := results(
+		packageVulns("minimist", "0.0.8", "GHSA-vh95-rmgr-6w4m", "GHSA-xvch-5gv4-984h"),
+		packageVulns("lodash", "4.17.15", "GHSA-p6mc-m468-83gw"),
+	)
+	// an older version of the same package, with one of the same vulnerabilities
+	base := results(
+		packageVulns("minimist", "0.0.5", "GHSA-vh95-rmgr-6w4m"),
+	)
+
+	got := publisher.NewVulnerabilities(current, &base)
+	want := results(
+		packageVulns("minimist", "0.0.8", "GHSA-xvch-5gv4-984h"),
+		packageVulns("lodash", "4.17.15", "GHSA-p6mc-m468-83gw"),
+	)
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("NewVulnerabilities() mismatch (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(current, publisher.NewVulnerabilities(current, nil)); diff != "" {
+		t.Errorf("NewVulnerabilities() without base mismatch (-want +got):\n%s", diff)
+	}
+
+	if got := publisher.NewVulnerabilities(current, &current); len(got.Results) != 0 {
+		t.Errorf("expected no new vulnerabilities, got %v", got)
+	}
+}
+
+func TestMarkdownSummary(t *testing.T) {
+	t.Parallel()
+
+	current := results(
+		packageVulns("minimist", "0.0.8", "GHSA-vh95-rmgr-6w4m", "GHSA-xvch-5gv4-984h"),
+	)
+	base := results(
+		packageVulns("minimist", "0.0.8", "GHSA-vh95-rmgr-6w4m"),
+	)
+
+	got := publisher.MarkdownSummary(current, &base)
+
+	for _, expected := range []string{
+		publisher.CommentMarker,
+		"Found 1 new vulnerability compared to the base branch",
+		"https://osv.dev/GHSA-xvch-5gv4-984h",
+		"1 vulnerability already present on the base branch is not shown",
+	} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("expected summary to contain %q, got:\n%s", expected, got)
+		}
+	}
+
+	if strings.Contains(got, "https://osv.dev/GHSA-vh95-rmgr-6w4m") {
+		t.Errorf("expected summary to not include known vulnerabilities, got:\n%s", got)
+	}
+
+	if got := publisher.MarkdownSummary(base, &base); !strings.Contains(got, "No new vulnerabilities were found") {
+		t.Errorf("expected summary to say there are no new vulnerabilities, got:\n%s", got)
+	}
+}
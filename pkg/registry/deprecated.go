@@ -0,0 +1,174 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// DeprecationKind is why a version of a package should no longer be used
+type DeprecationKind string
+
+const (
+	// DeprecationDeprecated is a package or version that its maintainers have
+	// marked as deprecated
+	DeprecationDeprecated DeprecationKind = "deprecated"
+	// DeprecationYanked is a version that has been pulled from its registry,
+	// or retracted in the case of Go modules, usually for being broken
+	DeprecationYanked DeprecationKind = "yanked"
+	// DeprecationUnmaintained is a package that its maintainers have said is
+	// no longer being maintained
+	DeprecationUnmaintained DeprecationKind = "unmaintained"
+)
+
+// Deprecation describes if a version of a package should no longer be used,
+// with Kind being empty if it is fine to use
+type Deprecation struct {
+	Kind DeprecationKind
+	// Message is the reason given by the maintainers, if there is one
+	Message string
+}
+
+// fromVersion looks up the deprecation of a package from the description of
+// its version on the registry, using the given function to read it
+func fromVersion(read func(body []byte) (Deprecation, error)) func(ctx context.Context, c *Checker, baseURL string, reg registry, pkg Package) (Deprecation, error) {
+	return func(ctx context.Context, c *Checker, baseURL string, reg registry, pkg Package) (Deprecation, error) {
+		body, found, err := c.get(ctx, baseURL+reg.versionPath(pkg.Name, pkg.Version))
+		if err != nil || !found {
+			return Deprecation{}, err
+		}
+
+		return read(body)
+	}
+}
+
+// npmDeprecation reads the deprecation message of a version, which npm sets
+// on every version when a whole package is deprecated
+func npmDeprecation(body []byte) (Deprecation, error) {
+	var version struct {
+		// Deprecated is usually a message, but is sometimes set to a boolean
+		Deprecated interface{} `json:"deprecated"`
+	}
+	if err := json.Unmarshal(body, &version); err != nil {
+		return Deprecation{}, err
+	}
+
+	switch deprecated := version.Deprecated.(type) {
+	case string:
+		if deprecated != "" {
+			return Deprecation{Kind: DeprecationDeprecated, Message: deprecated}, nil
+		}
+	case bool:
+		if deprecated {
+			return Deprecation{Kind: DeprecationDeprecated}, nil
+		}
+	}
+
+	return Deprecation{}, nil
+}
+
+// pypiInactive is the trove classifier of projects that are no longer being
+// developed
+const pypiInactive = "Development Status :: 7 - Inactive"
+
+// pypiDeprecation reads if a release has been yanked, or if the project has
+// been classified as inactive
+func pypiDeprecation(body []byte) (Deprecation, error) {
+	var release struct {
+		Info struct {
+			Yanked       bool     `json:"yanked"`
+			YankedReason string   `json:"yanked_reason"`
+			Classifiers  []string `json:"classifiers"`
+		} `json:"info"`
+	}
+	if err := json.Unmarshal(body, &release); err != nil {
+		return Deprecation{}, err
+	}
+
+	if release.Info.Yanked {
+		return Deprecation{Kind: DeprecationYanked, Message: release.Info.YankedReason}, nil
+	}
+
+	for _, classifier := range release.Info.Classifiers {
+		if classifier == pypiInactive {
+			return Deprecation{Kind: DeprecationUnmaintained, Message: classifier}, nil
+		}
+	}
+
+	return Deprecation{}, nil
+}
+
+func cratesDeprecation(body []byte) (Deprecation, error) {
+	var version struct {
+		Version struct {
+			Yanked bool `json:"yanked"`
+			// YankMessage is only published for versions yanked with a message
+			YankMessage string `json:"yank_message"`
+		} `json:"version"`
+	}
+	if err := json.Unmarshal(body, &version); err != nil {
+		return Deprecation{}, err
+	}
+
+	if version.Version.Yanked {
+		return Deprecation{Kind: DeprecationYanked, Message: version.Version.YankMessage}, nil
+	}
+
+	return Deprecation{}, nil
+}
+
+// goDeprecation reads the go.mod of the latest version of a module, which
+// says if the module is deprecated and which versions have been retracted
+func goDeprecation(ctx context.Context, c *Checker, baseURL string, _ registry, pkg Package) (Deprecation, error) {
+	// the latest version is not looked up with Latest, as that would make the
+	// registries refer to themselves while they are being initialised
+	body, found, err := c.get(ctx, baseURL+"/"+goModulePath(pkg.Name)+"/@latest")
+	if err != nil || !found {
+		return Deprecation{}, err
+	}
+
+	latest, err := goLatest(body)
+	if err != nil {
+		return Deprecation{}, fmt.Errorf("could not read the latest version of %s from %s: %w", pkg.Name, baseURL, err)
+	}
+
+	body, found, err = c.get(ctx, baseURL+"/"+goModulePath(pkg.Name)+"/@v/"+goModuleVersion(latest)+".mod")
+	if err != nil || !found {
+		return Deprecation{}, err
+	}
+
+	mod, err := modfile.ParseLax("go.mod", body, nil)
+	if err != nil {
+		return Deprecation{}, fmt.Errorf("could not read the go.mod of %s@%s from %s: %w", pkg.Name, latest, baseURL, err)
+	}
+
+	version := "v" + strings.TrimPrefix(pkg.Version, "v")
+	for _, retract := range mod.Retract {
+		if semver.Compare(version, retract.Low) >= 0 && semver.Compare(version, retract.High) <= 0 {
+			return Deprecation{Kind: DeprecationYanked, Message: retract.Rationale}, nil
+		}
+	}
+
+	if mod.Module != nil && mod.Module.Deprecated != "" {
+		return Deprecation{Kind: DeprecationDeprecated, Message: mod.Module.Deprecated}, nil
+	}
+
+	return Deprecation{}, nil
+}
+
+// Deprecation looks up if a version of a package has been deprecated, yanked
+// or is unmaintained according to its registry, returning an empty
+// deprecation if it is not or its ecosystem is not supported. The requests are
+// abandoned once ctx is done.
+func (c *Checker) Deprecation(ctx context.Context, pkg Package) (Deprecation, error) {
+	reg, ok := registries[pkg.Ecosystem]
+	if !ok || reg.deprecation == nil {
+		return Deprecation{}, nil
+	}
+
+	return reg.deprecation(ctx, c, c.baseURL(pkg.Ecosystem, reg), reg, pkg)
+}
@@ -0,0 +1,103 @@
+package registry_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/registry"
+)
+
+func TestChecker_Deprecation(t *testing.T) {
+	t.Parallel()
+
+	checker := newRegistry(t, map[string]string{
+		"/request/2.88.2":                 `{"deprecated": "request has been deprecated, see https://github.com/request/request/issues/3142"}`,
+		"/wrappy/1.0.2":                   `{"dist": {}}`,
+		"/pypi/urllib3/2.0.0/json":        `{"info": {"yanked": true, "yanked_reason": "broken wheels"}}`,
+		"/pypi/nose/1.3.7/json":           `{"info": {"yanked": false, "classifiers": ["Development Status :: 7 - Inactive"]}}`,
+		"/api/v1/crates/addr2line/0.15.0": `{"version": {"yanked": true}}`,
+		"/api/v1/crates/addr2line/0.15.2": `{"version": {"yanked": false}}`,
+		"/github.com/pkg/errors/@latest":  `{"Version": "v0.9.2"}`,
+		"/github.com/pkg/errors/@v/v0.9.2.mod": "// Deprecated: use the errors package of the standard library.\n" +
+			"module github.com/pkg/errors\n\n" +
+			"// published by mistake\n" +
+			"retract [v0.9.0, v0.9.1]\n",
+	})
+
+	tests := []struct {
+		name string
+		pkg  registry.Package
+		want registry.Deprecation
+	}{
+		{
+			name: "deprecated npm package",
+			pkg:  registry.Package{Name: "request", Version: "2.88.2", Ecosystem: "npm"},
+			want: registry.Deprecation{
+				Kind:    registry.DeprecationDeprecated,
+				Message: "request has been deprecated, see https://github.com/request/request/issues/3142",
+			},
+		},
+		{
+			name: "npm package in use",
+			pkg:  registry.Package{Name: "wrappy", Version: "1.0.2", Ecosystem: "npm"},
+			want: registry.Deprecation{},
+		},
+		{
+			name: "yanked pypi release",
+			pkg:  registry.Package{Name: "urllib3", Version: "2.0.0", Ecosystem: "PyPI"},
+			want: registry.Deprecation{Kind: registry.DeprecationYanked, Message: "broken wheels"},
+		},
+		{
+			name: "inactive pypi project",
+			pkg:  registry.Package{Name: "nose", Version: "1.3.7", Ecosystem: "PyPI"},
+			want: registry.Deprecation{Kind: registry.DeprecationUnmaintained, Message: "Development Status :: 7 - Inactive"},
+		},
+		{
+			name: "yanked crate",
+			pkg:  registry.Package{Name: "addr2line", Version: "0.15.0", Ecosystem: "crates.io"},
+			want: registry.Deprecation{Kind: registry.DeprecationYanked},
+		},
+		{
+			name: "crate in use",
+			pkg:  registry.Package{Name: "addr2line", Version: "0.15.2", Ecosystem: "crates.io"},
+			want: registry.Deprecation{},
+		},
+		{
+			name: "retracted go module version",
+			pkg:  registry.Package{Name: "github.com/pkg/errors", Version: "0.9.1", Ecosystem: "Go"},
+			want: registry.Deprecation{Kind: registry.DeprecationYanked, Message: "published by mistake"},
+		},
+		{
+			name: "deprecated go module",
+			pkg:  registry.Package{Name: "github.com/pkg/errors", Version: "0.8.1", Ecosystem: "Go"},
+			want: registry.Deprecation{Kind: registry.DeprecationDeprecated, Message: "use the errors package of the standard library."},
+		},
+		{
+			name: "unpublished version",
+			pkg:  registry.Package{Name: "wrappy", Version: "9.9.9", Ecosystem: "npm"},
+			want: registry.Deprecation{},
+		},
+		{
+			name: "unsupported ecosystem",
+			pkg:  registry.Package{Name: "some/package", Version: "1.0.0", Ecosystem: "Packagist"},
+			want: registry.Deprecation{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := checker.Deprecation(context.Background(), tt.pkg)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Deprecation() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
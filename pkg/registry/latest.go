@@ -0,0 +1,108 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// npmLatest returns the version that the "latest" dist-tag points to
+func npmLatest(body []byte) (string, error) {
+	var pkg struct {
+		DistTags struct {
+			Latest string `json:"latest"`
+		} `json:"dist-tags"`
+	}
+	if err := json.Unmarshal(body, &pkg); err != nil {
+		return "", err
+	}
+
+	return pkg.DistTags.Latest, nil
+}
+
+func pypiLatest(body []byte) (string, error) {
+	var pkg struct {
+		Info struct {
+			Version string `json:"version"`
+		} `json:"info"`
+	}
+	if err := json.Unmarshal(body, &pkg); err != nil {
+		return "", err
+	}
+
+	return pkg.Info.Version, nil
+}
+
+// cratesLatest returns the latest stable version of a crate, or its latest
+// version if it has never had a stable one
+func cratesLatest(body []byte) (string, error) {
+	var pkg struct {
+		Crate struct {
+			MaxStableVersion string `json:"max_stable_version"`
+			MaxVersion       string `json:"max_version"`
+		} `json:"crate"`
+	}
+	if err := json.Unmarshal(body, &pkg); err != nil {
+		return "", err
+	}
+
+	if pkg.Crate.MaxStableVersion != "" {
+		return pkg.Crate.MaxStableVersion, nil
+	}
+
+	return pkg.Crate.MaxVersion, nil
+}
+
+// goLatest returns the latest version of a module without the "v" prefix, as
+// it is removed when go.mod files are parsed
+func goLatest(body []byte) (string, error) {
+	var info struct {
+		Version string `json:"Version"`
+	}
+	if err := json.Unmarshal(body, &info); err != nil {
+		return "", err
+	}
+
+	return strings.TrimPrefix(info.Version, "v"), nil
+}
+
+func rubyGemsLatest(body []byte) (string, error) {
+	var gem struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(body, &gem); err != nil {
+		return "", err
+	}
+
+	return gem.Version, nil
+}
+
+// Latest returns the latest version of a package that is published on its
+// registry, or "" if the package is not published there or its ecosystem is
+// not supported. The request is abandoned once ctx is done.
+func (c *Checker) Latest(ctx context.Context, ecosystem string, name string) (string, error) {
+	reg, ok := registries[ecosystem]
+	if !ok || reg.latest == nil {
+		return "", nil
+	}
+
+	baseURL := c.baseURL(ecosystem, reg)
+
+	path := reg.packagePath
+	if reg.latestPath != nil {
+		path = reg.latestPath
+	}
+
+	body, found, err := c.get(ctx, baseURL+path(name))
+	if err != nil || !found {
+		return "", err
+	}
+
+	latest, err := reg.latest(body)
+	if err != nil {
+		return "", fmt.Errorf("could not read the latest version of %s from %s: %w", name, baseURL, err)
+	}
+
+	return latest, nil
+}
@@ -0,0 +1,368 @@
+// Package registry checks that the packages named in lockfiles exist on their
+// public registries, with the same content as when they were locked, which
+// catches packages that were never published or have been tampered with, and
+// that internal packages are not exposed to dependency confusion
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/osv"
+
+	"golang.org/x/mod/module"
+)
+
+// Status is the outcome of checking a package against its registry
+type Status string
+
+const (
+	// StatusOK is a package whose version exists on its registry, with the
+	// content recorded by the lockfile if it records any
+	StatusOK Status = "ok"
+	// StatusUnsupported is a package from an ecosystem whose registry cannot
+	// be checked
+	StatusUnsupported Status = "unsupported"
+	// StatusMissingPackage is a package that does not exist on its registry
+	StatusMissingPackage Status = "missing-package"
+	// StatusMissingVersion is a package that exists on its registry, but
+	// without the version in the lockfile
+	StatusMissingVersion Status = "missing-version"
+	// StatusIntegrityMismatch is a package whose content on its registry has
+	// a different hash to the one recorded in the lockfile
+	StatusIntegrityMismatch Status = "integrity-mismatch"
+	// StatusDependencyConfusion is an internal package whose name is also
+	// published on its public registry, which package managers might install
+	// in place of the internal package
+	StatusDependencyConfusion Status = "dependency-confusion"
+)
+
+// Package is a version of a package to check against its registry
+type Package struct {
+	Name      string
+	Version   string
+	Ecosystem string
+	// Integrity is the hash that the lockfile records for the package, if any
+	Integrity string
+	// Internal is set for packages that are only published to a private
+	// registry, which are checked to be absent from the public registry
+	Internal bool
+}
+
+// Result describes how a package compares to its registry
+type Result struct {
+	Status Status
+	// Detail explains why the package does not match its registry
+	Detail string
+}
+
+type registry struct {
+	baseURL string
+	// versionPath and packagePath return the paths on the registry that
+	// describe a version of a package, and the package itself
+	versionPath func(name string, version string) string
+	packagePath func(name string) string
+	// integrity returns the hash of the version described by the body in the
+	// same format as the recorded hash, or "" if they cannot be compared
+	integrity func(body []byte, recorded string) (string, error)
+	// latestPath returns the path that describes the latest version of a
+	// package, defaulting to packagePath, with latest reading it from there
+	latestPath func(name string) string
+	latest     func(body []byte) (string, error)
+	// deprecation looks up if a version of a package has been deprecated,
+	// yanked or is unmaintained
+	deprecation func(ctx context.Context, c *Checker, baseURL string, reg registry, pkg Package) (Deprecation, error)
+}
+
+// registries are the public registries of each ecosystem that can be checked
+var registries = map[string]registry{
+	"npm": {
+		baseURL: "https://registry.npmjs.org",
+		versionPath: func(name string, version string) string {
+			return "/" + npmName(name) + "/" + url.PathEscape(version)
+		},
+		packagePath: func(name string) string {
+			return "/" + npmName(name)
+		},
+		integrity:   npmIntegrity,
+		latest:      npmLatest,
+		deprecation: fromVersion(npmDeprecation),
+	},
+	"PyPI": {
+		baseURL: "https://pypi.org",
+		versionPath: func(name string, version string) string {
+			return "/pypi/" + url.PathEscape(name) + "/" + url.PathEscape(version) + "/json"
+		},
+		packagePath: func(name string) string {
+			return "/pypi/" + url.PathEscape(name) + "/json"
+		},
+		latest:      pypiLatest,
+		deprecation: fromVersion(pypiDeprecation),
+	},
+	"crates.io": {
+		baseURL: "https://crates.io",
+		versionPath: func(name string, version string) string {
+			return "/api/v1/crates/" + url.PathEscape(name) + "/" + url.PathEscape(version)
+		},
+		packagePath: func(name string) string {
+			return "/api/v1/crates/" + url.PathEscape(name)
+		},
+		integrity:   cratesIntegrity,
+		latest:      cratesLatest,
+		deprecation: fromVersion(cratesDeprecation),
+	},
+	"Go": {
+		baseURL: "https://proxy.golang.org",
+		versionPath: func(name string, version string) string {
+			return "/" + goModulePath(name) + "/@v/" + goModuleVersion(version) + ".info"
+		},
+		packagePath: func(name string) string {
+			return "/" + goModulePath(name) + "/@v/list"
+		},
+		latestPath: func(name string) string {
+			return "/" + goModulePath(name) + "/@latest"
+		},
+		latest:      goLatest,
+		deprecation: goDeprecation,
+	},
+	"RubyGems": {
+		baseURL: "https://rubygems.org",
+		versionPath: func(name string, version string) string {
+			return "/api/v2/rubygems/" + url.PathEscape(name) + "/versions/" + url.PathEscape(version) + ".json"
+		},
+		packagePath: func(name string) string {
+			return "/api/v1/gems/" + url.PathEscape(name) + ".json"
+		},
+		latest: rubyGemsLatest,
+	},
+}
+
+// npmName escapes the name of an npm package, keeping the scope of scoped
+// packages in the same path segment as the name
+func npmName(name string) string {
+	return strings.ReplaceAll(url.PathEscape(name), "%2F", "%2f")
+}
+
+// npmIntegrity returns the hash that the registry publishes for a version in
+// the same Subresource Integrity format as the recorded hash
+func npmIntegrity(body []byte, recorded string) (string, error) {
+	var version struct {
+		Dist struct {
+			Integrity string `json:"integrity"`
+			Shasum    string `json:"shasum"`
+		} `json:"dist"`
+	}
+	if err := json.Unmarshal(body, &version); err != nil {
+		return "", err
+	}
+
+	algorithm, _, _ := strings.Cut(recorded, "-")
+
+	switch {
+	case algorithm == "sha1" && version.Dist.Shasum != "":
+		shasum, err := hex.DecodeString(version.Dist.Shasum)
+		if err != nil {
+			return "", err
+		}
+
+		return "sha1-" + base64.StdEncoding.EncodeToString(shasum), nil
+	case algorithm != "" && strings.HasPrefix(version.Dist.Integrity, algorithm+"-"):
+		published, _, _ := strings.Cut(version.Dist.Integrity, " ")
+
+		return published, nil
+	}
+
+	return "", nil
+}
+
+// cratesIntegrity returns the SHA-256 of the crate that the registry publishes
+func cratesIntegrity(body []byte, _ string) (string, error) {
+	var version struct {
+		Version struct {
+			Checksum string `json:"checksum"`
+		} `json:"version"`
+	}
+	if err := json.Unmarshal(body, &version); err != nil {
+		return "", err
+	}
+
+	return version.Version.Checksum, nil
+}
+
+func goModulePath(name string) string {
+	escaped, err := module.EscapePath(name)
+	if err != nil {
+		return name
+	}
+
+	return escaped
+}
+
+// goModuleVersion adds the "v" prefix that is removed when go.mod files are
+// parsed back onto the version
+func goModuleVersion(version string) string {
+	escaped, err := module.EscapeVersion("v" + version)
+	if err != nil {
+		return "v" + version
+	}
+
+	return escaped
+}
+
+// Checker checks packages against their public registries
+type Checker struct {
+	// Client sends the requests to the registries
+	Client *http.Client
+	// BaseURLs replaces the URL of the registry of each ecosystem, such as to
+	// check against a mirror
+	BaseURLs map[string]string
+}
+
+// NewChecker returns a checker that uses the same HTTP client as requests to
+// the OSV API
+func NewChecker() *Checker {
+	return &Checker{Client: osv.HTTPClient}
+}
+
+// Supported reports if packages of the given ecosystem can be checked
+func Supported(ecosystem string) bool {
+	_, ok := registries[ecosystem]
+
+	return ok
+}
+
+// Check compares a package against its registry, returning an error only if
+// the registry could not be asked about the package. The requests are
+// abandoned once ctx is done.
+func (c *Checker) Check(ctx context.Context, pkg Package) (Result, error) {
+	reg, ok := registries[pkg.Ecosystem]
+	if !ok {
+		return Result{Status: StatusUnsupported}, nil
+	}
+
+	baseURL := c.baseURL(pkg.Ecosystem, reg)
+
+	if pkg.Internal {
+		return c.checkInternal(ctx, baseURL, reg, pkg)
+	}
+
+	body, found, err := c.get(ctx, baseURL+reg.versionPath(pkg.Name, pkg.Version))
+	if err != nil {
+		return Result{}, err
+	}
+
+	if !found {
+		_, found, err := c.get(ctx, baseURL+reg.packagePath(pkg.Name))
+		if err != nil {
+			return Result{}, err
+		}
+
+		if !found {
+			return Result{
+				Status: StatusMissingPackage,
+				Detail: fmt.Sprintf("%s is not published on %s", pkg.Name, baseURL),
+			}, nil
+		}
+
+		return Result{
+			Status: StatusMissingVersion,
+			Detail: fmt.Sprintf("%s has no version %s on %s", pkg.Name, pkg.Version, baseURL),
+		}, nil
+	}
+
+	if pkg.Integrity == "" || reg.integrity == nil {
+		return Result{Status: StatusOK}, nil
+	}
+
+	// an integrity can list more than one hash, of which only the first is
+	// compared
+	recorded, _, _ := strings.Cut(pkg.Integrity, " ")
+
+	published, err := reg.integrity(body, recorded)
+	if err != nil {
+		return Result{}, fmt.Errorf("could not read %s@%s from %s: %w", pkg.Name, pkg.Version, baseURL, err)
+	}
+
+	if published == "" || published == recorded {
+		return Result{Status: StatusOK}, nil
+	}
+
+	return Result{
+		Status: StatusIntegrityMismatch,
+		Detail: fmt.Sprintf("the lockfile records %s but %s publishes %s", pkg.Integrity, baseURL, published),
+	}, nil
+}
+
+// baseURL returns the URL of the registry of the ecosystem, or its override
+func (c *Checker) baseURL(ecosystem string, reg registry) string {
+	if override, ok := c.BaseURLs[ecosystem]; ok {
+		return strings.TrimSuffix(override, "/")
+	}
+
+	return reg.baseURL
+}
+
+// checkInternal checks that no package with the name of an internal package
+// is published on the public registry
+func (c *Checker) checkInternal(ctx context.Context, baseURL string, reg registry, pkg Package) (Result, error) {
+	_, found, err := c.get(ctx, baseURL+reg.packagePath(pkg.Name))
+	if err != nil {
+		return Result{}, err
+	}
+
+	if !found {
+		return Result{Status: StatusOK}, nil
+	}
+
+	return Result{
+		Status: StatusDependencyConfusion,
+		Detail: fmt.Sprintf("%s is an internal package, but a package with the same name is published on %s", pkg.Name, baseURL),
+	}, nil
+}
+
+// get returns the body of the given URL, with found being false if the
+// registry reports that there is nothing there, which is abandoned once ctx is
+// done
+func (c *Checker) get(ctx context.Context, url string) ([]byte, bool, error) {
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	// crates.io rejects requests without a user agent
+	req.Header.Set("User-Agent", "osv-scanner")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusGone:
+		// the Go module proxy reports modules that do not exist as gone
+		return nil, false, nil
+	default:
+		return nil, false, fmt.Errorf("%s responded with %s", url, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return body, true, nil
+}
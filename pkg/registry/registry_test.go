@@ -0,0 +1,193 @@
+package registry_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/registry"
+)
+
+func newRegistry(t *testing.T, responses map[string]string) *registry.Checker {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.EscapedPath()]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return &registry.Checker{
+		Client: server.Client(),
+		BaseURLs: map[string]string{
+			"npm":       server.URL,
+			"PyPI":      server.URL,
+			"crates.io": server.URL,
+			"Go":        server.URL,
+		},
+	}
+}
+
+func TestChecker_Check(t *testing.T) {
+	t.Parallel()
+
+	checker := newRegistry(t, map[string]string{
+		"/wrappy":                         `{}`,
+		"/wrappy/1.0.2":                   `{"dist": {"integrity": "sha512-l4Sp/DRseor9wL6EvV2+TuQn63dMkPjZ/sp9XkghTEbV9KlPS1xUsZ3u7/IQO4wxtcFB4bgpQPRcR3QCvezPcQ==", "shasum": "b5243d8f3ec1aa35f1364605bc0d1036e30ab69f"}}`,
+		"/@babel%2fcore/7.0.0":            `{"dist": {"integrity": "sha512-AAAA"}}`,
+		"/api/v1/crates/addr2line/0.15.2": `{"version": {"checksum": "e7a2e47a1fbe209ee101dd6d61285226744c6c8d3c21c8dc878ba6cb9f467f3a"}}`,
+		"/github.com/!burnt!sushi/toml/@v/v1.0.0.info": `{"Version": "v1.0.0"}`,
+	})
+
+	tests := []struct {
+		name string
+		pkg  registry.Package
+		want registry.Status
+	}{
+		{
+			name: "matching sha512 integrity",
+			pkg: registry.Package{
+				Name: "wrappy", Version: "1.0.2", Ecosystem: "npm",
+				Integrity: "sha512-l4Sp/DRseor9wL6EvV2+TuQn63dMkPjZ/sp9XkghTEbV9KlPS1xUsZ3u7/IQO4wxtcFB4bgpQPRcR3QCvezPcQ==",
+			},
+			want: registry.StatusOK,
+		},
+		{
+			name: "matching sha1 integrity",
+			pkg:  registry.Package{Name: "wrappy", Version: "1.0.2", Ecosystem: "npm", Integrity: "sha1-tSQ9jz7BqjXxNkYFvA0QNuMKtp8="},
+			want: registry.StatusOK,
+		},
+		{
+			name: "tampered integrity",
+			pkg:  registry.Package{Name: "wrappy", Version: "1.0.2", Ecosystem: "npm", Integrity: "sha1-AAAAAAAAAAAAAAAAAAAAAAAAAAA="},
+			want: registry.StatusIntegrityMismatch,
+		},
+		{
+			name: "integrity that cannot be compared",
+			pkg:  registry.Package{Name: "wrappy", Version: "1.0.2", Ecosystem: "npm", Integrity: "9706c088a283058a8a99e0bf91b0a2f7"},
+			want: registry.StatusOK,
+		},
+		{
+			name: "scoped package",
+			pkg:  registry.Package{Name: "@babel/core", Version: "7.0.0", Ecosystem: "npm", Integrity: "sha512-AAAA"},
+			want: registry.StatusOK,
+		},
+		{
+			name: "missing version",
+			pkg:  registry.Package{Name: "wrappy", Version: "9.9.9", Ecosystem: "npm"},
+			want: registry.StatusMissingVersion,
+		},
+		{
+			name: "ghost package",
+			pkg:  registry.Package{Name: "wrappyy", Version: "1.0.2", Ecosystem: "npm"},
+			want: registry.StatusMissingPackage,
+		},
+		{
+			name: "tampered crate",
+			pkg:  registry.Package{Name: "addr2line", Version: "0.15.2", Ecosystem: "crates.io", Integrity: "0000"},
+			want: registry.StatusIntegrityMismatch,
+		},
+		{
+			name: "go module with capitals",
+			pkg:  registry.Package{Name: "github.com/BurntSushi/toml", Version: "1.0.0", Ecosystem: "Go"},
+			want: registry.StatusOK,
+		},
+		{
+			name: "internal package published publicly",
+			pkg:  registry.Package{Name: "wrappy", Ecosystem: "npm", Internal: true},
+			want: registry.StatusDependencyConfusion,
+		},
+		{
+			name: "unpublished internal package",
+			pkg:  registry.Package{Name: "@acme/utils", Ecosystem: "npm", Internal: true},
+			want: registry.StatusOK,
+		},
+		{
+			name: "unsupported ecosystem",
+			pkg:  registry.Package{Name: "some/package", Version: "1.0.0", Ecosystem: "Packagist"},
+			want: registry.StatusUnsupported,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := checker.Check(context.Background(), tt.pkg)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got.Status != tt.want {
+				t.Errorf("Check() = %s (%s), want %s", got.Status, got.Detail, tt.want)
+			}
+			if got.Status != registry.StatusOK && got.Status != registry.StatusUnsupported && got.Detail == "" {
+				t.Errorf("expected a detail explaining the %s status", got.Status)
+			}
+		})
+	}
+}
+
+func TestChecker_Check_RegistryError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	checker := registry.Checker{Client: server.Client(), BaseURLs: map[string]string{"npm": server.URL}}
+
+	if _, err := checker.Check(context.Background(), registry.Package{Name: "wrappy", Version: "1.0.2", Ecosystem: "npm"}); err == nil {
+		t.Errorf("expected an error when the registry fails")
+	}
+}
+
+func TestChecker_Latest(t *testing.T) {
+	t.Parallel()
+
+	checker := newRegistry(t, map[string]string{
+		"/wrappy":                               `{"dist-tags": {"latest": "1.0.2", "next": "2.0.0-rc.1"}}`,
+		"/api/v1/crates/addr2line":              `{"crate": {"max_version": "0.22.0-alpha", "max_stable_version": "0.21.0"}}`,
+		"/api/v1/crates/unstable":               `{"crate": {"max_version": "0.1.0-beta", "max_stable_version": null}}`,
+		"/github.com/!burnt!sushi/toml/@latest": `{"Version": "v1.3.2"}`,
+	})
+
+	tests := []struct {
+		name      string
+		ecosystem string
+		pkg       string
+		want      string
+	}{
+		{name: "npm latest tag", ecosystem: "npm", pkg: "wrappy", want: "1.0.2"},
+		{name: "latest stable crate", ecosystem: "crates.io", pkg: "addr2line", want: "0.21.0"},
+		{name: "crate without a stable version", ecosystem: "crates.io", pkg: "unstable", want: "0.1.0-beta"},
+		{name: "go module", ecosystem: "Go", pkg: "github.com/BurntSushi/toml", want: "1.3.2"},
+		{name: "unpublished package", ecosystem: "npm", pkg: "wrappyy", want: ""},
+		{name: "unsupported ecosystem", ecosystem: "Packagist", pkg: "some/package", want: ""},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := checker.Latest(context.Background(), tt.ecosystem, tt.pkg)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Latest() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+// Package server runs osv-scanner as a long-lived service, with a web
+// dashboard over the scan history store
+package server
+
+import (
+	"embed"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"github.com/google/osv-scanner/pkg/store"
+)
+
+//go:embed templates/*.html
+var templateFiles embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"osvURL": func(id string) string {
+		return osv.BaseVulnerabilityURL + id
+	},
+	"formatTime": func(t time.Time) string {
+		return t.Format(time.RFC3339)
+	},
+	"severityOrUnknown": func(severity string) string {
+		if severity == "" {
+			return "UNKNOWN"
+		}
+
+		return severity
+	},
+}).ParseFS(templateFiles, "templates/*.html"))
+
+// SeverityCount is the number of findings with a particular severity
+type SeverityCount struct {
+	Severity string
+	Count    int
+}
+
+// RepositorySummary describes the latest scan of a repository
+type RepositorySummary struct {
+	Name        string
+	LastScanned time.Time
+	Findings    []store.Finding
+	Severities  []SeverityCount
+}
+
+// AdvisoryUsage is a finding of an advisory in a repository
+type AdvisoryUsage struct {
+	Repository string
+	Finding    store.Finding
+}
+
+// Dashboard serves a web UI listing the scanned repositories and their latest
+// findings, backed by a scan history store
+type Dashboard struct {
+	Store store.Store
+	mux   *http.ServeMux
+}
+
+// NewDashboard creates a dashboard over the given store
+func NewDashboard(s store.Store) *Dashboard {
+	d := &Dashboard{Store: s, mux: http.NewServeMux()}
+
+	d.mux.HandleFunc("/", d.handleIndex)
+	d.mux.HandleFunc("/repositories/", d.handleRepository)
+	d.mux.HandleFunc("/advisories/", d.handleAdvisory)
+
+	return d
+}
+
+func (d *Dashboard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mux.ServeHTTP(w, r)
+}
+
+// severityCounts counts the findings by severity, most severe first
+func severityCounts(findings []store.Finding) []SeverityCount {
+	counts := map[string]int{}
+	for _, finding := range findings {
+		counts[finding.Severity]++
+	}
+
+	result := []SeverityCount{}
+	for i := len(models.Severities) - 1; i >= 0; i-- {
+		if count := counts[models.Severities[i]]; count > 0 {
+			result = append(result, SeverityCount{Severity: models.Severities[i], Count: count})
+		}
+	}
+	if count := counts[""]; count > 0 {
+		result = append(result, SeverityCount{Severity: "", Count: count})
+	}
+
+	return result
+}
+
+// summaries returns the latest scan of every repository in the store
+func (d *Dashboard) summaries() ([]RepositorySummary, error) {
+	repositories, err := d.Store.Repositories()
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]RepositorySummary, 0, len(repositories))
+
+	for _, repository := range repositories {
+		scans, err := d.Store.Scans(repository)
+		if err != nil {
+			return nil, err
+		}
+		if len(scans) == 0 {
+			continue
+		}
+
+		latest := scans[len(scans)-1]
+		summaries = append(summaries, RepositorySummary{
+			Name:        repository,
+			LastScanned: latest.Time,
+			Findings:    latest.Findings,
+			Severities:  severityCounts(latest.Findings),
+		})
+	}
+
+	return summaries, nil
+}
+
+func (d *Dashboard) render(w http.ResponseWriter, name string, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if err := templates.ExecuteTemplate(w, name, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (d *Dashboard) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	summaries, err := d.summaries()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	d.render(w, "index.html", summaries)
+}
+
+func (d *Dashboard) handleRepository(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/repositories/")
+
+	scans, err := d.Store.Scans(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(scans) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	latest := scans[len(scans)-1]
+
+	d.render(w, "repository.html", struct {
+		RepositorySummary
+		Trends []store.Trend
+	}{
+		RepositorySummary: RepositorySummary{
+			Name:        name,
+			LastScanned: latest.Time,
+			Findings:    latest.Findings,
+			Severities:  severityCounts(latest.Findings),
+		},
+		Trends: store.Trends(scans),
+	})
+}
+
+func (d *Dashboard) handleAdvisory(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/advisories/")
+
+	summaries, err := d.summaries()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	usages := []AdvisoryUsage{}
+	for _, summary := range summaries {
+		for _, finding := range summary.Findings {
+			for _, findingID := range finding.IDs {
+				if findingID == id {
+					usages = append(usages, AdvisoryUsage{Repository: summary.Name, Finding: finding})
+					break
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(usages, func(i, j int) bool {
+		return usages[i].Repository < usages[j].Repository
+	})
+
+	d.render(w, "advisory.html", struct {
+		ID     string
+		Usages []AdvisoryUsage
+	}{ID: id, Usages: usages})
+}
@@ -0,0 +1,136 @@
+package server_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/osv-scanner/pkg/server"
+	"github.com/google/osv-scanner/pkg/store"
+)
+
+func newTestDashboard(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	s := store.FileStore{Path: filepath.Join(t.TempDir(), "history.json")}
+
+	scans := []store.Scan{
+		{
+			Repository: "frontend",
+			Time:       time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			Findings: []store.Finding{
+				{Fingerprint: "a", Ecosystem: "npm", Package: "minimist", Version: "0.0.8", IDs: []string{"GHSA-vh95-rmgr-6w4m"}, Severity: "CRITICAL"},
+				{Fingerprint: "b", Ecosystem: "npm", Package: "lodash", Version: "4.17.15", IDs: []string{"GHSA-p6mc-m468-83gw"}, Severity: "HIGH"},
+			},
+		},
+		{
+			Repository: "frontend",
+			Time:       time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC),
+			Findings: []store.Finding{
+				{Fingerprint: "b", Ecosystem: "npm", Package: "lodash", Version: "4.17.15", IDs: []string{"GHSA-p6mc-m468-83gw"}, Severity: "HIGH"},
+			},
+		},
+		{
+			Repository: "backend",
+			Time:       time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC),
+			Findings:   []store.Finding{},
+		},
+	}
+
+	for _, scan := range scans {
+		if err := s.Save(scan); err != nil {
+			t.Fatalf("could not save scan: %v", err)
+		}
+	}
+
+	ts := httptest.NewServer(server.NewDashboard(s))
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func get(t *testing.T, url string) (int, string) {
+	t.Helper()
+
+	//nolint:gosec,noctx // the url is from a test server
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("could not read response: %v", err)
+	}
+
+	return resp.StatusCode, string(body)
+}
+
+func TestDashboard(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestDashboard(t)
+
+	tests := []struct {
+		path         string
+		wantStatus   int
+		wantContains []string
+		wantMissing  []string
+	}{
+		{
+			path:         "/",
+			wantStatus:   http.StatusOK,
+			wantContains: []string{`href="/repositories/frontend"`, `href="/repositories/backend"`, "HIGH: 1"},
+		},
+		{
+			path:         "/repositories/frontend",
+			wantStatus:   http.StatusOK,
+			wantContains: []string{"lodash", `href="/advisories/GHSA-p6mc-m468-83gw"`, "HIGH: 1"},
+			wantMissing:  []string{"minimist"},
+		},
+		{
+			path:         "/repositories/backend",
+			wantStatus:   http.StatusOK,
+			wantContains: []string{"No vulnerabilities were found in the latest scan"},
+		},
+		{
+			path:         "/advisories/GHSA-p6mc-m468-83gw",
+			wantStatus:   http.StatusOK,
+			wantContains: []string{"https://osv.dev/GHSA-p6mc-m468-83gw", `href="/repositories/frontend"`},
+			wantMissing:  []string{`href="/repositories/backend"`},
+		},
+		{
+			path:       "/repositories/unknown",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			path:       "/unknown",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		status, body := get(t, ts.URL+tt.path)
+
+		if status != tt.wantStatus {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.wantStatus, status)
+		}
+
+		for _, want := range tt.wantContains {
+			if !strings.Contains(body, want) {
+				t.Errorf("%s: expected body to contain %q", tt.path, want)
+			}
+		}
+
+		for _, missing := range tt.wantMissing {
+			if strings.Contains(body, missing) {
+				t.Errorf("%s: expected body to not contain %q", tt.path, missing)
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Project is something that is scanned on a schedule, identified by exactly
+// one of a local path, a git URL, or a docker image
+type Project struct {
+	Name  string `yaml:"name"`
+	Path  string `yaml:"path"`
+	Git   string `yaml:"git"`
+	Image string `yaml:"image"`
+	// Interval between scans, overriding the scheduler's default
+	Interval time.Duration `yaml:"interval"`
+}
+
+var errInvalidProject = errors.New("invalid project")
+
+func (p Project) validate() error {
+	targets := 0
+	for _, target := range []string{p.Path, p.Git, p.Image} {
+		if target != "" {
+			targets++
+		}
+	}
+
+	if p.Name == "" {
+		return fmt.Errorf("%w: a name is required", errInvalidProject)
+	}
+
+	if targets != 1 {
+		return fmt.Errorf("%w: %s must have exactly one of path, git, or image", errInvalidProject, p.Name)
+	}
+
+	if p.Interval < 0 {
+		return fmt.Errorf("%w: %s has a negative interval", errInvalidProject, p.Name)
+	}
+
+	return nil
+}
+
+// LoadProjects reads the projects to scan from a YAML file like
+//
+//	projects:
+//	  - name: frontend
+//	    path: /srv/frontend
+//	  - name: api
+//	    git: https://github.com/my-org/api.git
+//	    interval: 6h
+//	  - name: base-image
+//	    image: debian:bookworm
+func LoadProjects(path string) ([]Project, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read projects: %w", err)
+	}
+
+	var file struct {
+		Projects []Project `yaml:"projects"`
+	}
+
+	if err := yaml.UnmarshalStrict(content, &file); err != nil {
+		return nil, fmt.Errorf("could not parse projects from %s: %w", path, err)
+	}
+
+	seen := map[string]bool{}
+	for _, project := range file.Projects {
+		if err := project.validate(); err != nil {
+			return nil, err
+		}
+
+		if seen[project.Name] {
+			return nil, fmt.Errorf("%w: %s is listed more than once", errInvalidProject, project.Name)
+		}
+		seen[project.Name] = true
+	}
+
+	return file.Projects, nil
+}
@@ -0,0 +1,78 @@
+package server_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/server"
+)
+
+func writeProjects(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "projects.yaml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("could not write projects: %v", err)
+	}
+
+	return path
+}
+
+func TestLoadProjects(t *testing.T) {
+	t.Parallel()
+
+	path := writeProjects(t, `
+projects:
+  - name: frontend
+    path: /srv/frontend
+  - name: api
+    git: https://github.com/my-org/api.git
+    interval: 6h
+  - name: base-image
+    image: debian:bookworm
+`)
+
+	got, err := server.LoadProjects(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []server.Project{
+		{Name: "frontend", Path: "/srv/frontend"},
+		{Name: "api", Git: "https://github.com/my-org/api.git", Interval: 6 * time.Hour},
+		{Name: "base-image", Image: "debian:bookworm"},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("LoadProjects() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLoadProjects_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "no name", content: "projects:\n  - path: /srv/frontend\n"},
+		{name: "no target", content: "projects:\n  - name: frontend\n"},
+		{name: "many targets", content: "projects:\n  - name: frontend\n    path: /srv/frontend\n    image: node:18\n"},
+		{name: "duplicate", content: "projects:\n  - name: frontend\n    path: /a\n  - name: frontend\n    path: /b\n"},
+		{name: "unknown key", content: "projects:\n  - name: frontend\n    paht: /srv/frontend\n"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := server.LoadProjects(writeProjects(t, tt.content)); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
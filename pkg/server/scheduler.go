@@ -0,0 +1,149 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/osv-scanner/pkg/gitauth"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osvscanner"
+	"github.com/google/osv-scanner/pkg/output"
+	"github.com/google/osv-scanner/pkg/store"
+)
+
+// ScanFunc scans a project, returning the results
+type ScanFunc func(project Project, r output.Reporter) (models.VulnerabilityResults, error)
+
+// Scheduler rescans projects on an interval, recording the results in a
+// store, so that newly published advisories affecting projects that have not
+// changed are still caught
+type Scheduler struct {
+	Store    store.Store
+	Projects []Project
+	// DefaultInterval is used for projects that do not have an interval
+	DefaultInterval time.Duration
+	// Scan is used to scan each project, defaulting to ScanProject
+	Scan     ScanFunc
+	Reporter output.Reporter
+
+	// scanning ensures only one project is scanned at a time
+	scanning sync.Mutex
+}
+
+// ProjectScanner returns a ScanFunc that scans projects with
+// osvscanner.DoScan, cloning them first with the given credentials if they
+// are git repositories
+func ProjectScanner(credentials gitauth.Credentials) ScanFunc {
+	return func(project Project, r output.Reporter) (models.VulnerabilityResults, error) {
+		actions := osvscanner.ScannerActions{Recursive: true}
+
+		switch {
+		case project.Path != "":
+			actions.DirectoryPaths = []string{project.Path}
+		case project.Image != "":
+			actions.DockerContainerNames = []string{project.Image}
+		case project.Git != "":
+			dir, err := osvscanner.CloneRepository(project.Git, credentials)
+			if err != nil {
+				//nolint:wrapcheck
+				return models.VulnerabilityResults{}, err
+			}
+			defer os.RemoveAll(dir)
+
+			actions.DirectoryPaths = []string{dir}
+		}
+
+		results, err := osvscanner.DoScan(actions, r)
+
+		// finding vulnerabilities (or nothing at all) is still a successful scan
+		if errors.Is(err, osvscanner.VulnerabilitiesFoundErr) || errors.Is(err, osvscanner.NoPackagesFoundErr) {
+			err = nil
+		}
+
+		//nolint:wrapcheck
+		return results, err
+	}
+}
+
+// ScanProject scans the given project, cloning git repositories with the
+// default credentials
+func ScanProject(project Project, r output.Reporter) (models.VulnerabilityResults, error) {
+	return ProjectScanner(gitauth.Credentials{})(project, r)
+}
+
+func (s *Scheduler) reporter() output.Reporter {
+	if s.Reporter == nil {
+		return output.NewVoidReporter()
+	}
+
+	return s.Reporter
+}
+
+// RunOnce scans the given project and records the results
+func (s *Scheduler) RunOnce(project Project) error {
+	s.scanning.Lock()
+	defer s.scanning.Unlock()
+
+	scan := s.Scan
+	if scan == nil {
+		scan = ScanProject
+	}
+
+	results, err := scan(project, s.reporter())
+	if err != nil {
+		return fmt.Errorf("failed to scan %s: %w", project.Name, err)
+	}
+
+	//nolint:wrapcheck
+	return s.Store.Save(store.Scan{
+		Repository: project.Name,
+		Time:       time.Now().UTC(),
+		Findings:   store.FindingsFromResults(results),
+	})
+}
+
+func (s *Scheduler) interval(project Project) time.Duration {
+	if project.Interval > 0 {
+		return project.Interval
+	}
+
+	if s.DefaultInterval > 0 {
+		return s.DefaultInterval
+	}
+
+	return 24 * time.Hour
+}
+
+// Run scans every project straight away and then again each time its
+// interval passes, until `stop` is closed
+func (s *Scheduler) Run(stop <-chan struct{}) {
+	var wg sync.WaitGroup
+
+	for _, project := range s.Projects {
+		wg.Add(1)
+
+		go func(project Project) {
+			defer wg.Done()
+
+			ticker := time.NewTicker(s.interval(project))
+			defer ticker.Stop()
+
+			for {
+				if err := s.RunOnce(project); err != nil {
+					s.reporter().PrintError(fmt.Sprintf("%v\n", err))
+				}
+
+				select {
+				case <-stop:
+					return
+				case <-ticker.C:
+				}
+			}
+		}(project)
+	}
+
+	wg.Wait()
+}
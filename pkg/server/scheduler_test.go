@@ -0,0 +1,83 @@
+package server_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/output"
+	"github.com/google/osv-scanner/pkg/server"
+	"github.com/google/osv-scanner/pkg/store"
+)
+
+func fakeScan(project server.Project, r output.Reporter) (models.VulnerabilityResults, error) {
+	if project.Name == "broken" {
+		return models.VulnerabilityResults{}, errors.New("could not scan")
+	}
+
+	return models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: project.Path, Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "minimist", Version: "0.0.8", Ecosystem: "npm"},
+						Vulnerabilities: []models.Vulnerability{{ID: "GHSA-vh95-rmgr-6w4m"}},
+						Groups:          []models.GroupInfo{{IDs: []string{"GHSA-vh95-rmgr-6w4m"}}},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func TestScheduler_Run(t *testing.T) {
+	t.Parallel()
+
+	s := store.FileStore{Path: filepath.Join(t.TempDir(), "history.json")}
+
+	scheduler := &server.Scheduler{
+		Store: s,
+		Projects: []server.Project{
+			{Name: "frontend", Path: "/srv/frontend", Interval: 10 * time.Millisecond},
+			{Name: "broken", Path: "/srv/broken", Interval: 10 * time.Millisecond},
+		},
+		Scan: fakeScan,
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		scheduler.Run(stop)
+		close(done)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	close(stop)
+	<-done
+
+	scans, err := s.Scans("frontend")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(scans) < 2 {
+		t.Fatalf("expected frontend to have been scanned repeatedly, got %d scans", len(scans))
+	}
+
+	if len(scans[0].Findings) != 1 || scans[0].Findings[0].Package != "minimist" {
+		t.Errorf("expected the findings of the scan to be recorded, got %v", scans[0].Findings)
+	}
+
+	broken, err := s.Scans("broken")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(broken) != 0 {
+		t.Errorf("expected failed scans to not be recorded, got %d scans", len(broken))
+	}
+}
@@ -0,0 +1,167 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// FileStore keeps the history of scans in a single JSON file, which can be
+// saved to by many scans at once as they take turns to write it
+type FileStore struct {
+	Path string
+}
+
+const (
+	// lockTimeout is how long to wait for other scans to finish saving
+	lockTimeout = 30 * time.Second
+	// lockRetryInterval is how often to check if the store has been unlocked
+	lockRetryInterval = 50 * time.Millisecond
+	// staleLockAge is how old a lock must be to have been left behind by a
+	// scan that crashed while saving, as saving only takes a moment
+	staleLockAge = 5 * time.Minute
+)
+
+var _ Store = FileStore{}
+
+type fileStoreContent struct {
+	Scans []Scan `json:"scans"`
+}
+
+func (s FileStore) load() (fileStoreContent, error) {
+	var content fileStoreContent
+
+	buf, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return content, nil
+	}
+	if err != nil {
+		return content, fmt.Errorf("could not read store: %w", err)
+	}
+
+	if err := json.Unmarshal(buf, &content); err != nil {
+		return content, fmt.Errorf("could not parse store %s: %w", s.Path, err)
+	}
+
+	return content, nil
+}
+
+// lock stops other scans from saving to the store until the returned
+// function is called, by creating a lock file next to it
+func (s FileStore) lock() (func(), error) {
+	lockPath := s.Path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			f.Close()
+
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("could not lock store: %w", err)
+		}
+
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > staleLockAge {
+			os.Remove(lockPath)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("could not lock store: %s is still held by another scan", lockPath)
+		}
+
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// Save records the given scan, holding a lock on the store from reading it
+// until it has been replaced so that scans saved at the same time are kept
+func (s FileStore) Save(scan Scan) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	content, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	content.Scans = append(content.Scans, scan)
+
+	buf, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first so that the store is never left half-written
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("could not write store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf); err != nil {
+		tmp.Close()
+		return fmt.Errorf("could not write store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("could not write store: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.Path); err != nil {
+		return fmt.Errorf("could not write store: %w", err)
+	}
+
+	return nil
+}
+
+// Scans returns the recorded scans of the given repository, oldest first
+func (s FileStore) Scans(repository string) ([]Scan, error) {
+	content, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	scans := []Scan{}
+	for _, scan := range content.Scans {
+		if scan.Repository == repository {
+			scans = append(scans, scan)
+		}
+	}
+
+	sort.SliceStable(scans, func(i, j int) bool {
+		return scans[i].Time.Before(scans[j].Time)
+	})
+
+	return scans, nil
+}
+
+// Repositories returns the names of every repository with recorded scans
+func (s FileStore) Repositories() ([]string, error) {
+	content, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	repositories := []string{}
+
+	for _, scan := range content.Scans {
+		if !seen[scan.Repository] {
+			seen[scan.Repository] = true
+			repositories = append(repositories, scan.Repository)
+		}
+	}
+
+	sort.Strings(repositories)
+
+	return repositories, nil
+}
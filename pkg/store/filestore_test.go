@@ -0,0 +1,104 @@
+package store_test
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/store"
+)
+
+func TestFileStore(t *testing.T) {
+	t.Parallel()
+
+	s := store.FileStore{Path: filepath.Join(t.TempDir(), "history.json")}
+
+	scans, err := s.Scans("my-repo")
+	if err != nil {
+		t.Fatalf("unexpected error reading an empty store: %v", err)
+	}
+	if len(scans) != 0 {
+		t.Errorf("expected no scans, got %v", scans)
+	}
+
+	older := store.Scan{
+		Repository: "my-repo",
+		Time:       time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		Findings:   []store.Finding{{Fingerprint: "a", Package: "minimist", IDs: []string{"GHSA-vh95-rmgr-6w4m"}}},
+	}
+	newer := store.Scan{
+		Repository: "my-repo",
+		Time:       time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC),
+		Findings:   []store.Finding{},
+	}
+	other := store.Scan{
+		Repository: "another-repo",
+		Time:       time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC),
+		Findings:   []store.Finding{},
+	}
+
+	for _, scan := range []store.Scan{newer, other, older} {
+		if err := s.Save(scan); err != nil {
+			t.Fatalf("unexpected error saving scan: %v", err)
+		}
+	}
+
+	scans, err = s.Scans("my-repo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff([]store.Scan{older, newer}, scans); diff != "" {
+		t.Errorf("Scans() mismatch (-want +got):\n%s", diff)
+	}
+
+	repositories, err := s.Repositories()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"another-repo", "my-repo"}, repositories); diff != "" {
+		t.Errorf("Repositories() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFileStore_ConcurrentSaves(t *testing.T) {
+	t.Parallel()
+
+	s := store.FileStore{Path: filepath.Join(t.TempDir(), "history.json")}
+
+	const saves = 20
+
+	var wg sync.WaitGroup
+	errs := make(chan error, saves)
+	for i := 0; i < saves; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			errs <- s.Save(store.Scan{
+				Repository: "my-repo",
+				Time:       time.Date(2023, 1, 1, 0, 0, i, 0, time.UTC),
+				Findings:   []store.Finding{},
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error saving scan: %v", err)
+		}
+	}
+
+	scans, err := s.Scans("my-repo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(scans) != saves {
+		t.Errorf("expected every scan to be kept, got %d of %d", len(scans), saves)
+	}
+}
@@ -0,0 +1,58 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// driverNames are the names that drivers for each dialect commonly register as
+var driverNames = map[Dialect][]string{
+	DialectSQLite:   {"sqlite", "sqlite3"},
+	DialectPostgres: {"postgres", "pgx"},
+}
+
+// Open opens the store at the given location, which is either a "postgres://"
+// connection URL, the path to a JSON file ending in ".json", or otherwise the
+// path to a SQLite database, optionally prefixed with "sqlite://".
+//
+// SQL stores require a driver for the database to have been registered with
+// database/sql by importing it.
+func Open(location string) (Store, error) {
+	var dialect Dialect
+	var dataSource string
+
+	switch {
+	case strings.HasPrefix(location, "sqlite://"):
+		dialect = DialectSQLite
+		dataSource = strings.TrimPrefix(location, "sqlite://")
+	case strings.HasPrefix(location, "postgres://"), strings.HasPrefix(location, "postgresql://"):
+		dialect = DialectPostgres
+		dataSource = location
+	case strings.EqualFold(filepath.Ext(location), ".json"):
+		return FileStore{Path: location}, nil
+	default:
+		dialect = DialectSQLite
+		dataSource = location
+	}
+
+	drivers := sql.Drivers()
+
+	for _, name := range driverNames[dialect] {
+		if !slices.Contains(drivers, name) {
+			continue
+		}
+
+		db, err := sql.Open(name, dataSource)
+		if err != nil {
+			return nil, fmt.Errorf("could not open store: %w", err)
+		}
+
+		return NewSQLStore(db, dialect)
+	}
+
+	return nil, fmt.Errorf("could not open store: no %s driver has been registered", dialect)
+}
@@ -0,0 +1,27 @@
+package store_test
+
+import (
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/store"
+)
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+
+	s, err := store.Open("/path/to/history.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if fs, ok := s.(store.FileStore); !ok || fs.Path != "/path/to/history.json" {
+		t.Errorf("expected a file store for /path/to/history.json, got %#v", s)
+	}
+
+	// no sql drivers are imported by the tests
+	for _, location := range []string{"/path/to/history.db", "sqlite:///path/to/history.db", "postgres://user@localhost/osv"} {
+		if _, err := store.Open(location); err == nil {
+			t.Errorf("expected an error opening %s without a driver", location)
+		}
+	}
+}
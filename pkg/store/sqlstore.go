@@ -0,0 +1,164 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dialect is the flavour of SQL spoken by a database
+type Dialect string
+
+const (
+	DialectSQLite   Dialect = "sqlite"
+	DialectPostgres Dialect = "postgres"
+)
+
+// timeLayout is fixed-width so that times sort correctly as text
+const timeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// SQLStore keeps the history of scans in a SQL database, so that many
+// repositories can be tracked centrally.
+//
+// The caller is responsible for opening the database, and so for importing
+// the driver for it.
+type SQLStore struct {
+	DB      *sql.DB
+	Dialect Dialect
+}
+
+var _ Store = &SQLStore{}
+
+// NewSQLStore creates the tables used to store scans if they do not already exist
+func NewSQLStore(db *sql.DB, dialect Dialect) (*SQLStore, error) {
+	s := &SQLStore{DB: db, Dialect: dialect}
+
+	idColumn := "INTEGER PRIMARY KEY AUTOINCREMENT"
+	if dialect == DialectPostgres {
+		idColumn = "BIGSERIAL PRIMARY KEY"
+	}
+
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS osv_scanner_scans (
+		id ` + idColumn + `,
+		repository TEXT NOT NULL,
+		scanned_at TEXT NOT NULL,
+		findings TEXT NOT NULL
+	)`)
+	if err != nil {
+		return nil, fmt.Errorf("could not create tables: %w", err)
+	}
+
+	return s, nil
+}
+
+// rebind converts the `?` placeholders in a query into those used by the dialect
+func (s *SQLStore) rebind(query string) string {
+	if s.Dialect != DialectPostgres {
+		return query
+	}
+
+	var sb strings.Builder
+	n := 0
+
+	for _, r := range query {
+		if r == '?' {
+			n++
+			sb.WriteString("$" + strconv.Itoa(n))
+
+			continue
+		}
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
+
+func (s *SQLStore) Save(scan Scan) error {
+	findings, err := json.Marshal(scan.Findings)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.DB.Exec(
+		s.rebind("INSERT INTO osv_scanner_scans (repository, scanned_at, findings) VALUES (?, ?, ?)"),
+		scan.Repository,
+		scan.Time.UTC().Format(timeLayout),
+		string(findings),
+	)
+	if err != nil {
+		return fmt.Errorf("could not save scan: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SQLStore) Scans(repository string) ([]Scan, error) {
+	rows, err := s.DB.Query(
+		s.rebind("SELECT scanned_at, findings FROM osv_scanner_scans WHERE repository = ?"),
+		repository,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not load scans: %w", err)
+	}
+	defer rows.Close()
+
+	scans := []Scan{}
+
+	for rows.Next() {
+		var scannedAt, findings string
+		if err := rows.Scan(&scannedAt, &findings); err != nil {
+			return nil, fmt.Errorf("could not load scans: %w", err)
+		}
+
+		scan := Scan{Repository: repository}
+
+		scan.Time, err = time.Parse(timeLayout, scannedAt)
+		if err != nil {
+			return nil, fmt.Errorf("could not load scans: %w", err)
+		}
+
+		if err := json.Unmarshal([]byte(findings), &scan.Findings); err != nil {
+			return nil, fmt.Errorf("could not load scans: %w", err)
+		}
+
+		scans = append(scans, scan)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not load scans: %w", err)
+	}
+
+	sort.SliceStable(scans, func(i, j int) bool {
+		return scans[i].Time.Before(scans[j].Time)
+	})
+
+	return scans, nil
+}
+
+func (s *SQLStore) Repositories() ([]string, error) {
+	rows, err := s.DB.Query("SELECT DISTINCT repository FROM osv_scanner_scans ORDER BY repository")
+	if err != nil {
+		return nil, fmt.Errorf("could not load repositories: %w", err)
+	}
+	defer rows.Close()
+
+	repositories := []string{}
+
+	for rows.Next() {
+		var repository string
+		if err := rows.Scan(&repository); err != nil {
+			return nil, fmt.Errorf("could not load repositories: %w", err)
+		}
+		repositories = append(repositories, repository)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not load repositories: %w", err)
+	}
+
+	return repositories, nil
+}
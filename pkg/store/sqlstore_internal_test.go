@@ -0,0 +1,20 @@
+package store
+
+import "testing"
+
+func TestSQLStore_rebind(t *testing.T) {
+	t.Parallel()
+
+	query := "INSERT INTO osv_scanner_scans (repository, scanned_at, findings) VALUES (?, ?, ?)"
+
+	sqlite := &SQLStore{Dialect: DialectSQLite}
+	if got := sqlite.rebind(query); got != query {
+		t.Errorf("expected sqlite query to be unchanged, got %s", got)
+	}
+
+	postgres := &SQLStore{Dialect: DialectPostgres}
+	want := "INSERT INTO osv_scanner_scans (repository, scanned_at, findings) VALUES ($1, $2, $3)"
+	if got := postgres.rebind(query); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
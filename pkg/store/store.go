@@ -0,0 +1,81 @@
+// Package store records the findings of scans over time, so that trends can
+// be reported on
+package store
+
+import (
+	"time"
+
+	"github.com/google/osv-scanner/pkg/models"
+
+	"golang.org/x/exp/slices"
+)
+
+// Store records scans and retrieves them later
+type Store interface {
+	// Save records the given scan
+	Save(scan Scan) error
+	// Scans returns the recorded scans of the given repository, oldest first
+	Scans(repository string) ([]Scan, error)
+	// Repositories returns the names of every repository with recorded scans
+	Repositories() ([]string, error)
+}
+
+// Finding is a vulnerability found in a package by a scan
+type Finding struct {
+	// Fingerprint identifies this finding across scans, see models.Fingerprint
+	Fingerprint string   `json:"fingerprint"`
+	Source      string   `json:"source"`
+	Ecosystem   string   `json:"ecosystem"`
+	Package     string   `json:"package"`
+	Version     string   `json:"version"`
+	IDs         []string `json:"ids"`
+	// Severity is the effective severity of the vulnerabilities, if known
+	Severity string `json:"severity,omitempty"`
+}
+
+// Scan is the record of a single scan of a repository
+type Scan struct {
+	Repository string    `json:"repository"`
+	Time       time.Time `json:"time"`
+	Findings   []Finding `json:"findings"`
+}
+
+// FindingsFromResults converts the results of a scan into findings
+func FindingsFromResults(results models.VulnerabilityResults) []Finding {
+	findings := []Finding{}
+
+	for _, source := range results.Results {
+		for _, pkg := range source.Packages {
+			for _, group := range pkg.Groups {
+				fingerprint := group.Fingerprint
+				if fingerprint == "" {
+					fingerprint = models.Fingerprint(source.Source.Path, pkg.Package, group.IDs)
+				}
+
+				var vulns []models.Vulnerability
+				for _, vuln := range pkg.Vulnerabilities {
+					if slices.Contains(group.IDs, vuln.ID) {
+						vulns = append(vulns, vuln)
+					}
+				}
+
+				severity := group.Severity
+				if severity == "" {
+					severity = models.HighestSeverity(vulns)
+				}
+
+				findings = append(findings, Finding{
+					Fingerprint: fingerprint,
+					Source:      source.Source.Path,
+					Ecosystem:   pkg.Package.Ecosystem,
+					Package:     pkg.Package.Name,
+					Version:     pkg.Package.Version,
+					IDs:         group.IDs,
+					Severity:    severity,
+				})
+			}
+		}
+	}
+
+	return findings
+}
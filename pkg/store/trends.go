@@ -0,0 +1,54 @@
+package store
+
+import "time"
+
+// Trend summarises how the findings of a repository changed with a scan
+type Trend struct {
+	Time time.Time `json:"time"`
+	// New is the number of findings that were not present in the previous scan
+	New int `json:"new"`
+	// Fixed is the number of findings from the previous scan that are no longer present
+	Fixed int `json:"fixed"`
+	// Ongoing is the number of findings that were also present in the previous scan
+	Ongoing int `json:"ongoing"`
+}
+
+func fingerprints(scan Scan) map[string]bool {
+	set := make(map[string]bool, len(scan.Findings))
+	for _, finding := range scan.Findings {
+		set[finding.Fingerprint] = true
+	}
+
+	return set
+}
+
+// Trends compares each scan to the one before it, expecting the scans to be
+// of the same repository and ordered oldest first
+func Trends(scans []Scan) []Trend {
+	trends := make([]Trend, 0, len(scans))
+	previous := map[string]bool{}
+
+	for _, scan := range scans {
+		current := fingerprints(scan)
+		trend := Trend{Time: scan.Time}
+
+		for fingerprint := range current {
+			if previous[fingerprint] {
+				trend.Ongoing++
+			} else {
+				trend.New++
+			}
+		}
+
+		for fingerprint := range previous {
+			if !current[fingerprint] {
+				trend.Fixed++
+			}
+		}
+
+		trends = append(trends, trend)
+		previous = current
+	}
+
+	return trends
+}
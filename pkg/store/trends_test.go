@@ -0,0 +1,44 @@
+package store_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/store"
+)
+
+func scanWith(day int, fingerprints ...string) store.Scan {
+	scan := store.Scan{
+		Repository: "my-repo",
+		Time:       time.Date(2023, 1, day, 0, 0, 0, 0, time.UTC),
+	}
+
+	for _, fingerprint := range fingerprints {
+		scan.Findings = append(scan.Findings, store.Finding{Fingerprint: fingerprint})
+	}
+
+	return scan
+}
+
+func TestTrends(t *testing.T) {
+	t.Parallel()
+
+	got := store.Trends([]store.Scan{
+		scanWith(1, "a", "b"),
+		scanWith(2, "a", "b", "c"),
+		scanWith(3, "c", "d"),
+		scanWith(4),
+	})
+
+	want := []store.Trend{
+		{Time: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), New: 2, Fixed: 0, Ongoing: 0},
+		{Time: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC), New: 1, Fixed: 0, Ongoing: 2},
+		{Time: time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC), New: 1, Fixed: 2, Ongoing: 1},
+		{Time: time.Date(2023, 1, 4, 0, 0, 0, 0, time.UTC), New: 0, Fixed: 2, Ongoing: 0},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Trends() mismatch (-want +got):\n%s", diff)
+	}
+}
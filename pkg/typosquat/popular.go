@@ -0,0 +1,55 @@
+package typosquat
+
+// Popular lists the most downloaded packages of each ecosystem, most popular
+// first, which are the names that typosquatters imitate. A name that is close
+// to more than one of them is reported as imitating the most popular one.
+var Popular = map[string][]string{
+	"npm": {
+		"lodash", "react", "chalk", "tslib", "commander", "axios", "debug",
+		"express", "react-dom", "request", "moment", "semver", "uuid",
+		"typescript", "webpack", "yargs", "glob", "minimist", "async",
+		"fs-extra", "inquirer", "classnames", "dotenv", "body-parser",
+		"colors", "color", "bluebird", "rxjs", "underscore", "prop-types",
+		"mkdirp", "rimraf", "jquery", "core-js", "vue", "cross-env", "eslint",
+		"prettier", "babel-core", "@babel/core", "node-fetch", "ws",
+		"socket.io", "mongoose", "jsonwebtoken", "bcrypt", "cheerio",
+		"electron", "nodemon", "jest", "mocha", "ora", "qs", "cors",
+		"redux", "react-redux", "styled-components", "graphql", "ethers",
+		"web3", "discord.js", "puppeteer", "sharp", "dayjs", "date-fns",
+		"coffee-script", "event-stream", "ua-parser-js", "node-sass",
+		"crypto-js", "left-pad", "cross-spawn", "object-assign",
+	},
+	"PyPI": {
+		"requests", "urllib3", "boto3", "botocore", "setuptools", "numpy",
+		"pandas", "python-dateutil", "six", "certifi", "idna", "pyyaml",
+		"charset-normalizer", "cryptography", "typing-extensions", "wheel",
+		"pip", "jinja2", "markupsafe", "attrs", "click", "pytest",
+		"colorama", "protobuf", "packaging", "pyparsing", "flask", "django",
+		"sqlalchemy", "scipy", "matplotlib", "pillow", "beautifulsoup4",
+		"tensorflow", "torch", "scikit-learn", "psycopg2", "pymongo",
+		"redis", "celery", "fastapi", "pydantic", "aiohttp", "httpx",
+		"selenium", "paramiko", "openai", "tqdm", "pytz", "simplejson",
+		"virtualenv", "docutils", "lxml", "opencv-python", "pycrypto",
+		"pycryptodome", "jsonschema", "python-nmap", "discord.py",
+	},
+	"crates.io": {
+		"serde", "syn", "quote", "proc-macro2", "rand", "libc", "tokio",
+		"serde_json", "regex", "clap", "log", "lazy_static", "futures",
+		"bitflags", "anyhow", "thiserror", "itertools", "chrono", "reqwest",
+		"hyper", "bytes", "once_cell", "memchr", "base64", "hashbrown",
+		"tracing", "env_logger", "time", "url", "uuid", "smallvec",
+		"parking_lot", "crossbeam", "rayon", "num-traits", "cfg-if",
+		"byteorder", "sha2", "openssl", "actix-web", "axum", "diesel",
+		"sqlx", "toml", "walkdir", "tempfile", "indexmap", "semver",
+	},
+	"RubyGems": {
+		"rake", "rack", "bundler", "rails", "activesupport", "json",
+		"nokogiri", "i18n", "minitest", "rspec", "rspec-core", "thor",
+		"tzinfo", "concurrent-ruby", "faraday", "mime-types", "puma",
+		"sinatra", "devise", "sidekiq", "redis", "pg", "mysql2", "sqlite3",
+		"aws-sdk", "httparty", "rest-client", "colorize", "rubocop",
+		"capistrano", "jwt", "bcrypt", "pry", "byebug", "rack-test",
+		"activerecord", "actionpack", "railties", "sprockets", "sass",
+		"coffee-rails", "jquery-rails",
+	},
+}
@@ -0,0 +1,124 @@
+// Package typosquat flags packages whose names are so close to the name of a
+// popular package that they are likely to have been published to catch those
+// who mistype it
+package typosquat
+
+import (
+	"regexp"
+	"strings"
+)
+
+// minLength is the length that the name of a popular package must have for
+// names close to it to be flagged, as most short names are a single edit away
+// from some other legitimate package
+const minLength = 5
+
+// pypiSeparators matches the runs of characters that PyPI treats as the same
+// when comparing names
+var pypiSeparators = regexp.MustCompile(`[-_.]+`)
+
+// normalize returns the name in the form that its registry compares names in
+func normalize(ecosystem string, name string) string {
+	name = strings.ToLower(name)
+
+	switch ecosystem {
+	case "PyPI":
+		return pypiSeparators.ReplaceAllString(name, "-")
+	case "crates.io":
+		return strings.ReplaceAll(name, "_", "-")
+	}
+
+	return name
+}
+
+// withoutSeparators removes the characters that are commonly used to separate
+// the words of a name, which are easily added, dropped or swapped
+func withoutSeparators(name string) string {
+	return strings.NewReplacer("-", "", "_", "", ".", "").Replace(name)
+}
+
+// Supported reports if there is a list of popular packages for the ecosystem
+func Supported(ecosystem string) bool {
+	_, ok := Popular[ecosystem]
+
+	return ok
+}
+
+// Detect returns the popular package that the named package is likely to be
+// typosquatting, being either a single insertion, deletion, substitution or
+// transposition of characters away from it, or differing from it only in the
+// separators between its words. Popular packages themselves, and scoped npm
+// packages whose scope is owned by their publisher, are never flagged.
+func Detect(ecosystem string, name string) (string, bool) {
+	popular, ok := Popular[ecosystem]
+	if !ok || strings.HasPrefix(name, "@") {
+		return "", false
+	}
+
+	name = normalize(ecosystem, name)
+
+	for _, p := range popular {
+		if normalize(ecosystem, p) == name {
+			return "", false
+		}
+	}
+
+	for _, p := range popular {
+		target := normalize(ecosystem, p)
+		if len(target) < minLength {
+			continue
+		}
+
+		if distance(name, target) == 1 || withoutSeparators(name) == withoutSeparators(target) {
+			return p, true
+		}
+	}
+
+	return "", false
+}
+
+// distance returns the optimal string alignment distance between a and b,
+// which is the number of insertions, deletions, substitutions and
+// transpositions of adjacent characters needed to turn one into the other
+func distance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// rows holds the last three rows of the distance matrix
+	rows := [3][]int{make([]int, len(rb)+1), make([]int, len(rb)+1), make([]int, len(rb)+1)}
+	for j := range rows[1] {
+		rows[1][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		prev2, prev, cur := rows[0], rows[1], rows[2]
+		cur[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			cur[j] = minOf(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = minOf(cur[j], prev2[j-2]+1)
+			}
+		}
+
+		rows[0], rows[1], rows[2] = prev, cur, prev2
+	}
+
+	return rows[1][len(rb)]
+}
+
+func minOf(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
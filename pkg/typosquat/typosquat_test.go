@@ -0,0 +1,50 @@
+package typosquat_test
+
+import (
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/typosquat"
+)
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		ecosystem string
+		pkg       string
+		want      string
+	}{
+		{name: "popular package", ecosystem: "npm", pkg: "lodash", want: ""},
+		{name: "popular package in another case", ecosystem: "npm", pkg: "Lodash", want: ""},
+		{name: "insertion", ecosystem: "npm", pkg: "lodashs", want: "lodash"},
+		{name: "deletion", ecosystem: "npm", pkg: "expres", want: "express"},
+		{name: "substitution", ecosystem: "npm", pkg: "axois", want: "axios"},
+		{name: "transposition", ecosystem: "npm", pkg: "lodahs", want: "lodash"},
+		{name: "dropped separator", ecosystem: "npm", pkg: "crossenv", want: "cross-env"},
+		{name: "swapped separator", ecosystem: "npm", pkg: "cross_env", want: "cross-env"},
+		{name: "separators in other places", ecosystem: "npm", pkg: "node-fet-ch", want: "node-fetch"},
+		{name: "unrelated package", ecosystem: "npm", pkg: "wrappy", want: ""},
+		{name: "close to a short name", ecosystem: "npm", pkg: "ws2", want: ""},
+		{name: "scoped package", ecosystem: "npm", pkg: "@types/lodashs", want: ""},
+		{name: "pypi separators are equivalent", ecosystem: "PyPI", pkg: "Python_Dateutil", want: ""},
+		{name: "pypi typosquat", ecosystem: "PyPI", pkg: "reqeusts", want: "requests"},
+		{name: "pypi dropped separator", ecosystem: "PyPI", pkg: "pythondateutil", want: "python-dateutil"},
+		{name: "crate with dash instead of underscore", ecosystem: "crates.io", pkg: "serde-json", want: ""},
+		{name: "crate typosquat", ecosystem: "crates.io", pkg: "tokoi", want: "tokio"},
+		{name: "gem typosquat", ecosystem: "RubyGems", pkg: "nokogiri2", want: "nokogiri"},
+		{name: "unsupported ecosystem", ecosystem: "Packagist", pkg: "lodashs", want: ""},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := typosquat.Detect(tt.ecosystem, tt.pkg)
+			if ok != (tt.want != "") || got != tt.want {
+				t.Errorf("Detect(%q, %q) = %q, %v, want %q", tt.ecosystem, tt.pkg, got, ok, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package workspaces
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+type codeownersRule struct {
+	pattern string
+	owners  []string
+}
+
+// Codeowners are the rules from a CODEOWNERS file, in the order they were declared
+type Codeowners []codeownersRule
+
+// codeownersLocations are where CODEOWNERS files are looked for, in order
+var codeownersLocations = []string{
+	".github/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+	".gitlab/CODEOWNERS",
+}
+
+// LoadCodeowners reads the CODEOWNERS file of the repository rooted at the
+// given directory, returning no rules if there is not one
+func LoadCodeowners(root string) (Codeowners, error) {
+	for _, location := range codeownersLocations {
+		content, ok, err := readFileIfExists(filepath.Join(root, filepath.FromSlash(location)))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return ParseCodeowners(content), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ParseCodeowners parses the content of a CODEOWNERS file
+func ParseCodeowners(content []byte) Codeowners {
+	var rules Codeowners
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		rules = append(rules, codeownersRule{pattern: fields[0], owners: fields[1:]})
+	}
+
+	return rules
+}
+
+// Owners returns the owners of the given slash-separated path relative to the
+// repository root, which are taken from the last rule that matches it
+func (c Codeowners) Owners(relPath string) []string {
+	for i := len(c) - 1; i >= 0; i-- {
+		if c[i].matches(relPath) {
+			return c[i].owners
+		}
+	}
+
+	return nil
+}
+
+// matches reports if the rule applies to the given directory; this supports
+// the common forms of patterns rather than the full gitignore syntax
+func (r codeownersRule) matches(relPath string) bool {
+	pattern := r.pattern
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.Trim(pattern, "/")
+
+	if pattern == "*" || pattern == "**" {
+		return true
+	}
+
+	candidates := []string{relPath}
+	if !anchored {
+		// unanchored patterns can match any part of the path
+		parts := strings.Split(relPath, "/")
+		for i := 1; i < len(parts); i++ {
+			candidates = append(candidates, strings.Join(parts[i:], "/"))
+		}
+	}
+
+	for _, candidate := range candidates {
+		// a pattern matching a directory also covers everything within it
+		for dir := candidate; dir != "."; dir = path.Dir(dir) {
+			if ok, _ := path.Match(pattern, dir); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
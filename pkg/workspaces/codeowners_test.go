@@ -0,0 +1,48 @@
+package workspaces_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/workspaces"
+)
+
+func TestCodeowners_Owners(t *testing.T) {
+	t.Parallel()
+
+	codeowners := workspaces.ParseCodeowners([]byte(`
+# comment
+*                @org/everyone
+/services/       @org/services
+/services/billing/ @org/billing
+docs             @org/docs
+libs/*/internal  @org/internals
+
+[Section]
+tools/           @org/tools
+`))
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{path: "web", want: []string{"@org/everyone"}},
+		{path: "services/api", want: []string{"@org/services"}},
+		{path: "services/billing", want: []string{"@org/billing"}},
+		{path: "services/billing/worker", want: []string{"@org/billing"}},
+		{path: "packages/docs", want: []string{"@org/docs"}},
+		{path: "libs/auth/internal", want: []string{"@org/internals"}},
+		{path: "tools/lint", want: []string{"@org/tools"}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tt.want, codeowners.Owners(tt.path)); diff != "" {
+				t.Errorf("owners mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,461 @@
+package workspaces
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+
+	"github.com/BurntSushi/toml"
+	"golang.org/x/mod/modfile"
+	"gopkg.in/yaml.v2"
+)
+
+// Member is a package or module that is part of a workspace
+type Member struct {
+	Name string
+	// Dir is the absolute path of the directory the member is in
+	Dir string
+	// Dependencies are the names of the packages the member directly depends on
+	Dependencies map[string]bool
+	// IndirectDependencies are the names of the packages the member only
+	// depends on through its dependencies, if the lockfile of the workspace
+	// records the dependencies of each package
+	IndirectDependencies map[string]bool
+	Owners               []string
+}
+
+// Workspace is a set of members that are managed together by a tool, and
+// usually share a single lockfile at the root of the workspace
+type Workspace struct {
+	// Tool is the name of the tool that manages the workspace, e.g. "npm"
+	Tool string
+	// Ecosystem is the OSV ecosystem of the packages managed by the tool
+	Ecosystem string
+	Root      string
+	Members   []Member
+}
+
+type detector func(root string) (*Workspace, error)
+
+var detectors = []detector{
+	detectNpm,
+	detectPnpm,
+	detectGo,
+	detectCargo,
+}
+
+// Detect returns the workspaces that are rooted at the given directory
+func Detect(root string) ([]Workspace, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var workspaces []Workspace
+
+	for _, detect := range detectors {
+		workspace, err := detect(root)
+		if err != nil {
+			return nil, err
+		}
+		if workspace == nil {
+			continue
+		}
+
+		workspaces = append(workspaces, *workspace)
+	}
+
+	if len(workspaces) == 0 {
+		return nil, nil
+	}
+
+	owners, err := LoadCodeowners(root)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, workspace := range workspaces {
+		for i, member := range workspace.Members {
+			workspace.Members[i].Owners = owners.Owners(workspace.RelativePath(member))
+		}
+	}
+
+	return workspaces, nil
+}
+
+// RelativePath returns the slash-separated path of the member's directory
+// relative to the root of the workspace
+func (w Workspace) RelativePath(member Member) string {
+	rel, err := filepath.Rel(w.Root, member.Dir)
+	if err != nil {
+		return filepath.ToSlash(member.Dir)
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// MemberOwning returns the member whose directory contains the given path, if any
+func (w Workspace) MemberOwning(path string) (Member, bool) {
+	for _, member := range w.Members {
+		if member.Dir == w.Root {
+			continue
+		}
+
+		if path == member.Dir || strings.HasPrefix(path, member.Dir+string(filepath.Separator)) {
+			return member, true
+		}
+	}
+
+	return Member{}, false
+}
+
+// MembersDependingOn returns the members that depend on the named package,
+// either directly or through their dependencies
+func (w Workspace) MembersDependingOn(name string) []Member {
+	var members []Member
+
+	for _, member := range w.Members {
+		if member.Dependencies[name] || member.IndirectDependencies[name] {
+			members = append(members, member)
+		}
+	}
+
+	return members
+}
+
+// expandMembers resolves the given glob patterns into member directories that
+// have a file with the given name
+func expandMembers(root string, patterns []string, manifest string) ([]string, error) {
+	seen := map[string]bool{}
+	var dirs []string
+
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(root, filepath.FromSlash(pattern)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid workspace pattern %q: %w", pattern, err)
+		}
+
+		for _, match := range matches {
+			if seen[match] {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(match, manifest)); err != nil {
+				continue
+			}
+
+			seen[match] = true
+			dirs = append(dirs, match)
+		}
+	}
+
+	sort.Strings(dirs)
+
+	return dirs, nil
+}
+
+func readFileIfExists(path string) ([]byte, bool, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return content, true, nil
+}
+
+type packageJSON struct {
+	Name                 string            `json:"name"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+	// Workspaces is either a list of patterns, or an object with a list of
+	// patterns under "packages"
+	Workspaces json.RawMessage `json:"workspaces"`
+}
+
+func readPackageJSON(dir string) (packageJSON, bool, error) {
+	var pkg packageJSON
+
+	content, ok, err := readFileIfExists(filepath.Join(dir, "package.json"))
+	if !ok || err != nil {
+		return pkg, ok, err
+	}
+
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return pkg, false, fmt.Errorf("could not parse %s: %w", filepath.Join(dir, "package.json"), err)
+	}
+
+	return pkg, true, nil
+}
+
+func (pkg packageJSON) workspacePatterns() []string {
+	if len(pkg.Workspaces) == 0 {
+		return nil
+	}
+
+	var patterns []string
+	if err := json.Unmarshal(pkg.Workspaces, &patterns); err == nil {
+		return patterns
+	}
+
+	var object struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.Unmarshal(pkg.Workspaces, &object); err == nil {
+		return object.Packages
+	}
+
+	return nil
+}
+
+func (pkg packageJSON) dependencies() map[string]bool {
+	deps := map[string]bool{}
+
+	for _, group := range []map[string]string{pkg.Dependencies, pkg.DevDependencies, pkg.OptionalDependencies, pkg.PeerDependencies} {
+		for name := range group {
+			deps[name] = true
+		}
+	}
+
+	return deps
+}
+
+func npmMembers(root string, patterns []string) ([]Member, error) {
+	dirs, err := expandMembers(root, patterns, "package.json")
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]Member, 0, len(dirs))
+
+	for _, dir := range dirs {
+		pkg, _, err := readPackageJSON(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		name := pkg.Name
+		if name == "" {
+			name = filepath.Base(dir)
+		}
+
+		members = append(members, Member{Name: name, Dir: dir, Dependencies: pkg.dependencies()})
+	}
+
+	return members, nil
+}
+
+// detectNpm detects npm and yarn workspaces, which are declared in package.json
+func detectNpm(root string) (*Workspace, error) {
+	pkg, ok, err := readPackageJSON(root)
+	if !ok || err != nil {
+		return nil, err
+	}
+
+	patterns := pkg.workspacePatterns()
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+
+	members, err := npmMembers(root, patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Workspace{Tool: "npm", Ecosystem: string(lockfile.NpmEcosystem), Root: root, Members: members}, nil
+}
+
+func detectPnpm(root string) (*Workspace, error) {
+	content, ok, err := readFileIfExists(filepath.Join(root, "pnpm-workspace.yaml"))
+	if !ok || err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		Packages []string `yaml:"packages"`
+	}
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", filepath.Join(root, "pnpm-workspace.yaml"), err)
+	}
+
+	members, err := npmMembers(root, file.Packages)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Workspace{Tool: "pnpm", Ecosystem: string(lockfile.NpmEcosystem), Root: root, Members: members}, nil
+}
+
+func detectGo(root string) (*Workspace, error) {
+	path := filepath.Join(root, "go.work")
+
+	content, ok, err := readFileIfExists(path)
+	if !ok || err != nil {
+		return nil, err
+	}
+
+	work, err := modfile.ParseWork(path, content, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", path, err)
+	}
+
+	members := make([]Member, 0, len(work.Use))
+
+	for _, use := range work.Use {
+		dir := filepath.Join(root, filepath.FromSlash(use.Path))
+		if filepath.IsAbs(use.Path) {
+			dir = filepath.Clean(use.Path)
+		}
+
+		modPath := filepath.Join(dir, "go.mod")
+		modContent, ok, err := readFileIfExists(modPath)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		mod, err := modfile.ParseLax(modPath, modContent, nil)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse %s: %w", modPath, err)
+		}
+
+		member := Member{Name: filepath.Base(dir), Dir: dir, Dependencies: map[string]bool{}}
+		if mod.Module != nil {
+			member.Name = mod.Module.Mod.Path
+		}
+		for _, require := range mod.Require {
+			member.Dependencies[require.Mod.Path] = true
+		}
+
+		members = append(members, member)
+	}
+
+	return &Workspace{Tool: "go", Ecosystem: string(lockfile.GoEcosystem), Root: root, Members: members}, nil
+}
+
+type cargoManifest struct {
+	Package struct {
+		Name string `toml:"name"`
+	} `toml:"package"`
+	Workspace *struct {
+		Members []string `toml:"members"`
+	} `toml:"workspace"`
+	Dependencies      map[string]interface{} `toml:"dependencies"`
+	DevDependencies   map[string]interface{} `toml:"dev-dependencies"`
+	BuildDependencies map[string]interface{} `toml:"build-dependencies"`
+}
+
+func readCargoManifest(dir string) (cargoManifest, bool, error) {
+	var manifest cargoManifest
+
+	path := filepath.Join(dir, "Cargo.toml")
+	content, ok, err := readFileIfExists(path)
+	if !ok || err != nil {
+		return manifest, ok, err
+	}
+
+	if _, err := toml.Decode(string(content), &manifest); err != nil {
+		return manifest, false, fmt.Errorf("could not parse %s: %w", path, err)
+	}
+
+	return manifest, true, nil
+}
+
+func detectCargo(root string) (*Workspace, error) {
+	manifest, ok, err := readCargoManifest(root)
+	if !ok || err != nil || manifest.Workspace == nil {
+		return nil, err
+	}
+
+	dirs, err := expandMembers(root, manifest.Workspace.Members, "Cargo.toml")
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]Member, 0, len(dirs))
+
+	for _, dir := range dirs {
+		memberManifest, _, err := readCargoManifest(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		member := Member{Name: memberManifest.Package.Name, Dir: dir, Dependencies: map[string]bool{}}
+		if member.Name == "" {
+			member.Name = filepath.Base(dir)
+		}
+		for _, group := range []map[string]interface{}{memberManifest.Dependencies, memberManifest.DevDependencies, memberManifest.BuildDependencies} {
+			for name := range group {
+				member.Dependencies[name] = true
+			}
+		}
+
+		members = append(members, member)
+	}
+
+	if err := addCargoIndirectDependencies(root, members); err != nil {
+		return nil, err
+	}
+
+	return &Workspace{Tool: "cargo", Ecosystem: string(lockfile.CargoEcosystem), Root: root, Members: members}, nil
+}
+
+// addCargoIndirectDependencies follows the dependencies that the Cargo.lock
+// of the workspace records for each package from each of the members, as
+// every member shares the lockfile at the root of the workspace
+func addCargoIndirectDependencies(root string, members []Member) error {
+	path := filepath.Join(root, "Cargo.lock")
+	content, ok, err := readFileIfExists(path)
+	if !ok || err != nil {
+		return err
+	}
+
+	var parsed lockfile.CargoLockFile
+	if _, err := toml.Decode(string(content), &parsed); err != nil {
+		return fmt.Errorf("could not parse %s: %w", path, err)
+	}
+
+	// versions of the same package are not told apart, as members are only
+	// known to depend on packages by name
+	graph := map[string][]string{}
+	for _, pkg := range parsed.Packages {
+		for _, dep := range pkg.Dependencies {
+			name, _, _ := strings.Cut(dep, " ")
+			graph[pkg.Name] = append(graph[pkg.Name], name)
+		}
+	}
+
+	for i := range members {
+		member := &members[i]
+		member.IndirectDependencies = map[string]bool{}
+
+		queue := append([]string{}, graph[member.Name]...)
+		seen := map[string]bool{member.Name: true}
+		for len(queue) > 0 {
+			name := queue[0]
+			queue = queue[1:]
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			if !member.Dependencies[name] {
+				member.IndirectDependencies[name] = true
+			}
+			queue = append(queue, graph[name]...)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package workspaces_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/workspaces"
+)
+
+type expectedMember struct {
+	Name         string
+	Path         string
+	Dependencies []string
+	Owners       []string
+}
+
+func summarise(workspace workspaces.Workspace) []expectedMember {
+	members := make([]expectedMember, 0, len(workspace.Members))
+
+	for _, member := range workspace.Members {
+		var deps []string
+		for _, dep := range []string{"express", "jest", "react", "lodash", "github.com/gogo/protobuf", "serde", "acme-core", "tempfile"} {
+			if member.Dependencies[dep] {
+				deps = append(deps, dep)
+			}
+		}
+
+		members = append(members, expectedMember{
+			Name:         member.Name,
+			Path:         workspace.RelativePath(member),
+			Dependencies: deps,
+			Owners:       member.Owners,
+		})
+	}
+
+	return members
+}
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		dir       string
+		tool      string
+		ecosystem string
+		members   []expectedMember
+	}{
+		{
+			dir:       "npm",
+			tool:      "npm",
+			ecosystem: "npm",
+			members: []expectedMember{
+				{Name: "@acme/api", Path: "packages/api", Dependencies: []string{"express", "jest"}, Owners: []string{"@acme/backend"}},
+				{Name: "@acme/web", Path: "packages/web", Dependencies: []string{"express", "react"}, Owners: []string{"@acme/frontend", "@alice"}},
+			},
+		},
+		{
+			dir:       "pnpm",
+			tool:      "pnpm",
+			ecosystem: "npm",
+			members: []expectedMember{
+				{Name: "site", Path: "apps/site", Dependencies: []string{"lodash"}},
+			},
+		},
+		{
+			dir:       "go",
+			tool:      "go",
+			ecosystem: "Go",
+			members: []expectedMember{
+				{Name: "example.com/svc", Path: "svc", Dependencies: []string{"github.com/gogo/protobuf"}},
+				{Name: "example.com/lib", Path: "lib"},
+			},
+		},
+		{
+			dir:       "cargo",
+			tool:      "cargo",
+			ecosystem: "crates.io",
+			members: []expectedMember{
+				{Name: "acme-cli", Path: "crates/cli", Dependencies: []string{"acme-core", "tempfile"}},
+				{Name: "acme-core", Path: "crates/core", Dependencies: []string{"serde"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.dir, func(t *testing.T) {
+			t.Parallel()
+
+			detected, err := workspaces.Detect(filepath.Join("../../fixtures/workspaces", tt.dir))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(detected) != 1 {
+				t.Fatalf("expected 1 workspace, got %d", len(detected))
+			}
+
+			if detected[0].Tool != tt.tool || detected[0].Ecosystem != tt.ecosystem {
+				t.Errorf("expected %s workspace of %s, got %s of %s", tt.tool, tt.ecosystem, detected[0].Tool, detected[0].Ecosystem)
+			}
+
+			if diff := cmp.Diff(tt.members, summarise(detected[0])); diff != "" {
+				t.Errorf("members mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDetect_NoWorkspaces(t *testing.T) {
+	t.Parallel()
+
+	detected, err := workspaces.Detect("../../fixtures/locks-many")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(detected) != 0 {
+		t.Errorf("expected no workspaces, got %v", detected)
+	}
+}
+
+func TestWorkspace_MemberOwning(t *testing.T) {
+	t.Parallel()
+
+	detected, err := workspaces.Detect("../../fixtures/workspaces/go")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	workspace := detected[0]
+
+	member, ok := workspace.MemberOwning(filepath.Join(workspace.Root, "svc"))
+	if !ok || member.Name != "example.com/svc" {
+		t.Errorf("expected svc to be owned by example.com/svc, got %v", member.Name)
+	}
+
+	if _, ok := workspace.MemberOwning(filepath.Join(workspace.Root, "svcs")); ok {
+		t.Errorf("did not expect svcs to be owned by a member")
+	}
+
+	if _, ok := workspace.MemberOwning(workspace.Root); ok {
+		t.Errorf("did not expect the workspace root to be owned by a member")
+	}
+}
+
+func TestWorkspace_MembersDependingOn_CargoLock(t *testing.T) {
+	t.Parallel()
+
+	detected, err := workspaces.Detect("../../fixtures/workspaces/cargo-lock")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(detected) != 1 {
+		t.Fatalf("expected 1 workspace, got %d", len(detected))
+	}
+
+	tests := []struct {
+		pkg  string
+		want []string
+	}{
+		{pkg: "acme-lib", want: []string{"acme-app"}},
+		{pkg: "regex", want: []string{"acme-app"}},
+		{pkg: "aho-corasick", want: []string{"acme-app"}},
+		{pkg: "serde", want: []string{"acme-app", "acme-lib"}},
+		{pkg: "serde_derive", want: []string{"acme-app", "acme-lib"}},
+		{pkg: "acme-app", want: nil},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, member := range detected[0].MembersDependingOn(tt.pkg) {
+			got = append(got, member.Name)
+		}
+
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("MembersDependingOn(%q) mismatch (-want +got):\n%s", tt.pkg, diff)
+		}
+	}
+}